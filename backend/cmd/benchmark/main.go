@@ -11,11 +11,13 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/folding"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
 )
 
 // BenchmarkProtein represents a test case with metadata
@@ -60,6 +62,8 @@ type BenchmarkSummary struct {
 	// Mean metrics
 	MeanRMSD         float64   `json:"mean_rmsd"`
 	MedianRMSD       float64   `json:"median_rmsd"`
+	RMSDCILower      float64   `json:"rmsd_ci95_lower"` // 95% bootstrap CI of mean RMSD
+	RMSDCIUpper      float64   `json:"rmsd_ci95_upper"`
 	MeanTMScore      float64   `json:"mean_tm_score"`
 	MedianTMScore    float64   `json:"median_tm_score"`
 	MeanGDT_TS       float64   `json:"mean_gdt_ts"`
@@ -146,7 +150,7 @@ func main() {
 	fmt.Printf("Success rate: %.1f%% (%d/%d)\n",
 		float64(summary.SuccessfulPreds)/float64(summary.TotalProteins)*100,
 		summary.SuccessfulPreds, summary.TotalProteins)
-	fmt.Printf("Mean RMSD: %.2f Å\n", summary.MeanRMSD)
+	fmt.Printf("Mean RMSD: %.2f Å (95%% CI %.2f-%.2f Å)\n", summary.MeanRMSD, summary.RMSDCILower, summary.RMSDCIUpper)
 	fmt.Printf("Mean TM-score: %.3f\n", summary.MeanTMScore)
 	fmt.Printf("Quality score: %.3f\n", summary.MeanQuality)
 }
@@ -311,26 +315,10 @@ func runSinglePrediction(dataDir string, prot BenchmarkProtein, idx, total int)
 	return result
 }
 
+// extractSequence returns the one-letter sequence of protein's amino acids,
+// modified residues as their parent (MSE → M) and other residues dropped
 func extractSequence(protein *parser.Protein) string {
-	sequence := ""
-	for _, res := range protein.Residues {
-		// Convert three-letter to one-letter code (simplified)
-		aa := threeToOne(res.Name)
-		if aa != "" {
-			sequence += aa
-		}
-	}
-	return sequence
-}
-
-func threeToOne(three string) string {
-	codes := map[string]string{
-		"ALA": "A", "ARG": "R", "ASN": "N", "ASP": "D", "CYS": "C",
-		"GLN": "Q", "GLU": "E", "GLY": "G", "HIS": "H", "ILE": "I",
-		"LEU": "L", "LYS": "K", "MET": "M", "PHE": "F", "PRO": "P",
-		"SER": "S", "THR": "T", "TRP": "W", "TYR": "Y", "VAL": "V",
-	}
-	return codes[three]
+	return strings.ReplaceAll(protein.OneLetterSequence(), "X", "")
 }
 
 func calculateSummary(results []BenchmarkResult) BenchmarkSummary {
@@ -355,17 +343,15 @@ func calculateSummary(results []BenchmarkResult) BenchmarkSummary {
 		return summary
 	}
 
-	// Calculate means
-	sumRMSD := 0.0
-	sumTM := 0.0
-	sumGDT := 0.0
-	sumQuality := 0.0
+	// Calculate means with running sums
+	var rmsd, tm, gdt, quality, elapsed stats.RunningStats
 
 	for _, r := range successResults {
-		sumRMSD += r.RMSD
-		sumTM += r.TMScore
-		sumGDT += r.GDT_TS
-		sumQuality += r.QualityScore
+		rmsd.Add(r.RMSD)
+		tm.Add(r.TMScore)
+		gdt.Add(r.GDT_TS)
+		quality.Add(r.QualityScore)
+		elapsed.Add(r.TimeElapsed)
 
 		// Count by quality threshold
 		if r.RMSD < 2.0 && r.TMScore > 0.6 {
@@ -377,49 +363,28 @@ func calculateSummary(results []BenchmarkResult) BenchmarkSummary {
 		}
 	}
 
-	n := float64(len(successResults))
-	summary.MeanRMSD = sumRMSD / n
-	summary.MeanTMScore = sumTM / n
-	summary.MeanGDT_TS = sumGDT / n
-	summary.MeanQuality = sumQuality / n
-	summary.MeanTime = summary.TotalTime / n
+	summary.MeanRMSD = rmsd.Mean()
+	summary.MeanTMScore = tm.Mean()
+	summary.MeanGDT_TS = gdt.Mean()
+	summary.MeanQuality = quality.Mean()
+	summary.MeanTime = elapsed.Mean()
 
-	// Calculate medians (simplified - just sort and take middle)
+	// Medians and the bootstrap confidence interval of mean RMSD are exact,
+	// so they still need the full per-metric slices (a P2 estimate of the
+	// median would be approximate at benchmark sizes)
 	rmsdValues := make([]float64, len(successResults))
 	tmValues := make([]float64, len(successResults))
 	for i, r := range successResults {
 		rmsdValues[i] = r.RMSD
 		tmValues[i] = r.TMScore
 	}
-	summary.MedianRMSD = median(rmsdValues)
-	summary.MedianTMScore = median(tmValues)
+	summary.MedianRMSD = stats.Median(rmsdValues)
+	summary.MedianTMScore = stats.Median(tmValues)
+	summary.RMSDCILower, summary.RMSDCIUpper = stats.BootstrapCI(rmsdValues, 0.95)
 
 	return summary
 }
 
-func median(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	// Simple bubble sort for small arrays
-	sorted := make([]float64, len(values))
-	copy(sorted, values)
-	for i := 0; i < len(sorted); i++ {
-		for j := i + 1; j < len(sorted); j++ {
-			if sorted[i] > sorted[j] {
-				sorted[i], sorted[j] = sorted[j], sorted[i]
-			}
-		}
-	}
-
-	mid := len(sorted) / 2
-	if len(sorted)%2 == 0 {
-		return (sorted[mid-1] + sorted[mid]) / 2
-	}
-	return sorted[mid]
-}
-
 func generateReport(summary BenchmarkSummary) {
 	report := fmt.Sprintf(`# Wave 6 Benchmark Validation Report
 
@@ -439,6 +404,8 @@ func generateReport(summary BenchmarkSummary) {
 | **GDT_TS** | %.3f | - | %s |
 | **Quality Score** | %.3f | - | %s |
 
+Mean RMSD 95%% bootstrap confidence interval: [%.2f, %.2f] Å
+
 ## Quality Distribution
 
 - **Excellent** (RMSD < 2Å, TM > 0.6): %d (%.1f%%)
@@ -457,36 +424,35 @@ func generateReport(summary BenchmarkSummary) {
 		summary.MeanTMScore, summary.MedianTMScore, interpretTM(summary.MeanTMScore),
 		summary.MeanGDT_TS, interpretGDT(summary.MeanGDT_TS),
 		summary.MeanQuality, interpretQuality(summary.MeanQuality),
+		summary.RMSDCILower, summary.RMSDCIUpper,
 		summary.ExcellentPreds, float64(summary.ExcellentPreds)/float64(summary.SuccessfulPreds)*100,
 		summary.GoodPreds, float64(summary.GoodPreds)/float64(summary.SuccessfulPreds)*100,
 		summary.AcceptablePreds, float64(summary.AcceptablePreds)/float64(summary.SuccessfulPreds)*100,
 	)
 
-	// Per-fold-class analysis
-	foldClasses := map[string][]BenchmarkResult{
+	// Per-fold-class analysis, accumulated as the results stream past
+	type classStats struct{ rmsd, tm stats.RunningStats }
+	foldClasses := map[string]*classStats{
 		"alpha": {}, "beta": {}, "alpha+beta": {}, "irregular": {},
 	}
 	for _, r := range summary.Results {
 		if r.Success {
-			foldClasses[r.FoldClass] = append(foldClasses[r.FoldClass], r)
+			c := foldClasses[r.FoldClass]
+			if c == nil {
+				c = &classStats{}
+				foldClasses[r.FoldClass] = c
+			}
+			c.rmsd.Add(r.RMSD)
+			c.tm.Add(r.TMScore)
 		}
 	}
 
-	for class, results := range foldClasses {
-		if len(results) == 0 {
+	for class, c := range foldClasses {
+		if c.rmsd.Count() == 0 {
 			continue
 		}
-		meanRMSD := 0.0
-		meanTM := 0.0
-		for _, r := range results {
-			meanRMSD += r.RMSD
-			meanTM += r.TMScore
-		}
-		meanRMSD /= float64(len(results))
-		meanTM /= float64(len(results))
-
 		report += fmt.Sprintf("**%s:** %d proteins, RMSD=%.2fÅ, TM=%.3f\n",
-			class, len(results), meanRMSD, meanTM)
+			class, c.rmsd.Count(), c.rmsd.Mean(), c.tm.Mean())
 	}
 
 	report += "\n## Individual Results\n\n"
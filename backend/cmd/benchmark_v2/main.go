@@ -5,9 +5,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/pipeline"
 )
 
@@ -25,6 +28,25 @@ func main() {
 		{"Medium (15aa)", "GACDEFGHIKLMNPQ"},
 	}
 
+	// Optional: benchmark sequences from a FASTA file instead
+	fastaPath := flag.String("fasta", "", "FASTA file of sequences to fold")
+	flag.Parse()
+
+	if *fastaPath != "" {
+		records, err := parser.ParseFASTA(*fastaPath)
+		if err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		testCases = testCases[:0]
+		for _, record := range records {
+			testCases = append(testCases, struct {
+				name     string
+				sequence string
+			}{record.Header, record.Sequence})
+		}
+	}
+
 	for _, tc := range testCases {
 		fmt.Printf("Testing: %s\n", tc.name)
 		fmt.Printf("Sequence: %s\n", tc.sequence)
@@ -3,20 +3,14 @@ package main
 import (
 	"fmt"
 	"log"
+	"math/rand"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
 
-// Three-letter to one-letter amino acid code mapping
-var threeToOne = map[string]byte{
-	"ALA": 'A', "CYS": 'C', "ASP": 'D', "GLU": 'E',
-	"PHE": 'F', "GLY": 'G', "HIS": 'H', "ILE": 'I',
-	"LYS": 'K', "LEU": 'L', "MET": 'M', "ASN": 'N',
-	"PRO": 'P', "GLN": 'Q', "ARG": 'R', "SER": 'S',
-	"THR": 'T', "VAL": 'V', "TRP": 'W', "TYR": 'Y',
-}
-
 func main() {
 	fmt.Println("=== Energy Function Validation ===")
 	fmt.Println()
@@ -88,6 +82,24 @@ func main() {
 	}
 	fmt.Println()
 
+	// Step 3.2: Salt bridges and cation-π
+	fmt.Println("Step 3.2: Salt Bridge and Cation-π Analysis...")
+	saltBridges := physics.DetectSaltBridges(nativeProtein)
+	cationPi := physics.DetectCationPi(nativeProtein)
+	fmt.Printf("  Number of salt bridges: %d\n", len(saltBridges))
+	for _, b := range saltBridges {
+		fmt.Printf("    %s%d - %s%d: %.2f Å\n",
+			nativeProtein.Residues[b.Cation].Name, b.SeqNum1,
+			nativeProtein.Residues[b.Anion].Name, b.SeqNum2, b.Distance)
+	}
+	fmt.Printf("  Number of cation-π:     %d\n", len(cationPi))
+	for _, c := range cationPi {
+		fmt.Printf("    %s%d - %s%d: %.2f Å, %.0f° to ring plane\n",
+			nativeProtein.Residues[c.Cation].Name, c.SeqNum1,
+			nativeProtein.Residues[c.Aromatic].Name, c.SeqNum2, c.Distance, c.Angle)
+	}
+	fmt.Println()
+
 	// Step 3.5: Ramachandran Analysis (NEW)
 	fmt.Println("Step 3.5: Ramachandran Backbone Geometry Analysis...")
 	ramaStats := physics.GetRamachandranStatistics(nativeProtein)
@@ -145,10 +157,29 @@ func main() {
 	}
 	fmt.Println()
 
-	// Step 5: Energy gap analysis (decoy creation skipped for now)
+	// Step 5: Energy gap analysis against dihedral-randomized decoys, with
+	// the native rebuilt as a backbone model like them
 	fmt.Println("Step 5: Energy gap analysis...")
-	fmt.Println("(Decoy comparison skipped - native structure analysis only)")
-	energyGap := 0.0 // Placeholder
+	rng := rand.New(rand.NewSource(42))
+	reference, err := validation.GenerateDihedralDecoys(nativeProtein, 1, 0, rng)
+	if err != nil {
+		log.Fatalf("Failed to rebuild native backbone: %v", err)
+	}
+	decoys, err := validation.GenerateDihedralDecoys(nativeProtein, 50, 1.0, rng)
+	if err != nil {
+		log.Fatalf("Failed to generate decoys: %v", err)
+	}
+	gap, err := validation.EnergyGap(reference[0], decoys, func(p *parser.Protein) float64 {
+		return physics.CalculateTotalEnergy(p, 10.0, 12.0).Total
+	})
+	if err != nil {
+		log.Fatalf("Energy gap analysis failed: %v", err)
+	}
+	fmt.Printf("  Native (rebuilt backbone): %10.2f kcal/mol\n", gap.NativeEnergy)
+	fmt.Printf("  Decoys (%d, σ = 1 rad):    %10.2f ± %.2f kcal/mol\n", len(decoys), gap.DecoyMean, gap.DecoyStdDev)
+	fmt.Printf("  Z-score:                   %10.2f (good: > 2)\n", gap.ZScore)
+	fmt.Printf("  Gap to best decoy:         %10.2f kcal/mol (%d decoys below native)\n", gap.Gap, gap.DecoysBelowNative)
+	energyGap := gap.Gap
 	fmt.Println()
 
 	// Quality assessment
@@ -164,8 +195,9 @@ func main() {
 	synergy := 0.96     // H-bonds + solvation synergize well
 	elegance := 0.96    // Clean implementation
 
-	// Skip energy gap check since we didn't create decoy
-	_ = energyGap
+	if energyGap <= 0 {
+		correctness -= 0.10 // Some decoy scores at or below the native
+	}
 
 	fmt.Printf("Correctness: %.3f (energy gap quality)\n", correctness)
 	fmt.Printf("Performance: %.3f (calculation speed)\n", performance)
@@ -173,7 +205,7 @@ func main() {
 	fmt.Printf("Synergy: %.3f (H-bonds + solvation)\n", synergy)
 	fmt.Printf("Elegance: %.3f (code quality)\n", elegance)
 
-	quality := harmonicMean([]float64{correctness, performance, reliability, synergy, elegance})
+	quality := stats.HarmonicMean([]float64{correctness, performance, reliability, synergy, elegance})
 	fmt.Printf("\nAgent 4.4 Quality: %.4f", quality)
 	if quality >= 0.96 {
 		fmt.Printf(" (LEGENDARY) ✅ TARGET MET\n")
@@ -188,19 +220,3 @@ func main() {
 
 	fmt.Println("=== ENERGY FUNCTION VALIDATION COMPLETE ===")
 }
-
-func harmonicMean(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	sum := 0.0
-	for _, v := range values {
-		if v <= 0 {
-			return 0
-		}
-		sum += 1.0 / v
-	}
-
-	return float64(len(values)) / sum
-}
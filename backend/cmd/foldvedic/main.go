@@ -0,0 +1,369 @@
+// Package main - FoldVedic command-line tool
+//
+// One binary for the common tasks, without writing Go:
+//
+//	foldvedic fold     --seq ACDEF... | --fasta in.fasta  --out model.pdb  [--config run.json]
+//	foldvedic score    --pdb model.pdb
+//	foldvedic validate --model model.pdb --native native.pdb
+//	foldvedic convert  --in model.pdb --out model.xyz|model.fasta|copy.pdb
+//
+// Every subcommand prints its results to stdout and, on error, a one-line
+// message to stderr with a non-zero exit status.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/pipeline"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
+)
+
+// command is one subcommand: its flags are parsed from args, results go to stdout
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = map[string]command{
+	"fold":     {"predict a structure from a sequence", runFold},
+	"score":    {"energy, Vedic and Ramachandran scores and sequence properties of a PDB", runScore},
+	"validate": {"compare a model with a native structure (RMSD/TM/GDT/lDDT)", runValidate},
+	"convert":  {"convert a PDB to PDB, XYZ or FASTA", runConvert},
+}
+
+// commandOrder lists subcommands in usage order
+var commandOrder = []string{"fold", "score", "validate", "convert"}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches to a subcommand and returns the process exit status
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "foldvedic: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	if err := cmd.run(args[1:], stdout, stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "foldvedic %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: foldvedic <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run 'foldvedic <command> -h' for the command's flags")
+}
+
+// newFlagSet returns a flag set that reports errors instead of exiting
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("foldvedic "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// parseFlags parses args and rejects leftover positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// runFold predicts a structure with the unified pipeline and writes it as PDB
+func runFold(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("fold", stderr)
+	seq := fs.String("seq", "", "amino acid sequence (one-letter codes)")
+	fasta := fs.String("fasta", "", "FASTA file with one sequence")
+	out := fs.String("out", "", "output PDB path (required)")
+	configPath := fs.String("config", "", "JSON pipeline config (see pipeline.LoadConfig); flags override it")
+	samples := fs.Int("samples", 5, "samples per sampling method")
+	workers := fs.Int("workers", 0, "optimization workers (0 = all CPUs)")
+	verbose := fs.Bool("v", false, "print pipeline progress")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	config := pipeline.DefaultUnifiedPipelineV2Config("")
+	if *configPath != "" {
+		loaded, err := pipeline.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		config = loaded
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if *seq != "" && *fasta != "" || *seq == "" && *fasta == "" && config.Sequence == "" {
+		return fmt.Errorf("give exactly one of --seq or --fasta, or a config with a Sequence")
+	}
+	if *out == "" {
+		return fmt.Errorf("--out is required")
+	}
+	if *samples < 1 {
+		return fmt.Errorf("--samples must be at least 1")
+	}
+
+	sequence := strings.ToUpper(strings.TrimSpace(*seq))
+	if sequence == "" {
+		sequence = strings.ToUpper(strings.TrimSpace(config.Sequence))
+	}
+	name := "sequence"
+	if *fasta != "" {
+		records, err := parser.ParseFASTA(*fasta)
+		if err != nil {
+			return err
+		}
+		if len(records) != 1 {
+			return fmt.Errorf("%s has %d records, want exactly one", *fasta, len(records))
+		}
+		sequence, name = records[0].Sequence, records[0].Header
+	}
+	if err := parser.ValidateSequence(sequence); err != nil {
+		return err
+	}
+
+	config.Sequence = sequence
+	if set["samples"] || *configPath == "" {
+		config.NumSamplesPerMethod = *samples
+	}
+	if *workers > 0 {
+		config.MaxWorkers = *workers
+	}
+	if set["v"] || *configPath == "" {
+		config.Verbose = *verbose
+	}
+
+	start := time.Now()
+	result, err := pipeline.RunUnifiedPipelineV2(config, nil)
+	if err != nil {
+		return fmt.Errorf("folding failed: %w", err)
+	}
+
+	remarks := []string{
+		"FOLDVEDIC PREDICTION: " + strings.ToUpper(name),
+		fmt.Sprintf("FINAL ENERGY: %.3f KCAL/MOL", result.FinalEnergy),
+		fmt.Sprintf("VEDIC SCORE: %.4f", result.FinalVedicScore),
+	}
+	if err := parser.WritePDB(result.FinalStructure, *out, remarks); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Sequence:            %s (%d residues)\n", sequence, len(sequence))
+	fmt.Fprintf(stdout, "Secondary structure: %s\n", prediction.GetSecondaryStructureString(result.SecondaryStructure))
+	fmt.Fprintf(stdout, "Energy:              %.2f kcal/mol\n", result.FinalEnergy)
+	fmt.Fprintf(stdout, "Vedic score:         %.4f\n", result.FinalVedicScore)
+	fmt.Fprintf(stdout, "Quality score:       %.4f\n", result.QualityScore)
+	fmt.Fprintf(stdout, "Time:                %.2f s\n", time.Since(start).Seconds())
+	fmt.Fprintf(stdout, "Wrote %s\n", *out)
+	return nil
+}
+
+// runScore reports the energy terms, Vedic score, Ramachandran quality and
+// sequence properties of a PDB
+func runScore(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("score", stderr)
+	pdb := fs.String("pdb", "", "PDB file to score (required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *pdb == "" {
+		return fmt.Errorf("--pdb is required")
+	}
+
+	protein, err := parser.ParsePDB(*pdb)
+	if err != nil {
+		return err
+	}
+
+	energy := physics.CalculateTotalEnergyWithConfig(protein, physics.DefaultEnergyConfig())
+	vedicScore := vedic.CalculateVedicScore(protein, geometry.CalculateRamachandran(protein))
+	ramaScore, outliers := validation.RamachandranScore(protein)
+
+	fmt.Fprintf(stdout, "Structure:      %s (%d residues, %d atoms)\n", filepath.Base(*pdb), len(protein.Residues), len(protein.Atoms))
+	fmt.Fprintf(stdout, "Energy:         %.2f kcal/mol\n", energy.Total)
+	fmt.Fprintf(stdout, "  Bond          %.2f\n", energy.Bond)
+	fmt.Fprintf(stdout, "  Angle         %.2f\n", energy.Angle)
+	fmt.Fprintf(stdout, "  Dihedral      %.2f\n", energy.Dihedral)
+	fmt.Fprintf(stdout, "  Van der Waals %.2f\n", energy.VanDerWaals)
+	fmt.Fprintf(stdout, "  Electrostatic %.2f\n", energy.Electrostatic)
+	fmt.Fprintf(stdout, "Vedic score:    %.4f\n", vedicScore.TotalScore)
+	fmt.Fprintf(stdout, "Ramachandran:   %.3f mean log-probability, %d outliers\n", ramaScore, len(outliers))
+
+	// Unknown residues are left out of the sequence properties
+	sequence := strings.ReplaceAll(protein.OneLetterSequence(), "X", "")
+	if properties, err := prediction.SequenceProperties(sequence); err == nil {
+		fmt.Fprintf(stdout, "Sequence:       %s\n", properties)
+		fmt.Fprintf(stdout, "Composition:    %s\n", formatComposition(properties.Composition))
+	}
+	return nil
+}
+
+// formatComposition lists composition percentages in alphabetical order of
+// one-letter code, e.g. "A 10.0% G 5.0% ..."
+func formatComposition(composition map[byte]float64) string {
+	var parts []string
+	for _, code := range []byte("ACDEFGHIKLMNPQRSTVWY") {
+		if percent, ok := composition[code]; ok {
+			parts = append(parts, fmt.Sprintf("%c %.1f%%", code, percent))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// runValidate compares a model with a native structure
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("validate", stderr)
+	modelPath := fs.String("model", "", "predicted PDB (required)")
+	nativePath := fs.String("native", "", "reference PDB (required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *modelPath == "" || *nativePath == "" {
+		return fmt.Errorf("--model and --native are required")
+	}
+
+	model, err := parser.ParsePDB(*modelPath)
+	if err != nil {
+		return fmt.Errorf("model: %w", err)
+	}
+	native, err := parser.ParsePDB(*nativePath)
+	if err != nil {
+		return fmt.Errorf("native: %w", err)
+	}
+	if len(model.Residues) != len(native.Residues) {
+		return fmt.Errorf("model has %d residues, native has %d", len(model.Residues), len(native.Residues))
+	}
+
+	comparison := validation.CompareStructures(model, native)
+	fmt.Fprintf(stdout, "Residues:  %d\n", comparison.NumResidues)
+	fmt.Fprintf(stdout, "RMSD:      %.3f Å\n", comparison.RMSD)
+	fmt.Fprintf(stdout, "TM-score:  %.4f\n", comparison.TMScore)
+	fmt.Fprintf(stdout, "GDT_TS:    %.2f\n", 100*comparison.GDT_TS)
+	fmt.Fprintf(stdout, "GDT_HA:    %.2f\n", comparison.GDT_HA)
+	fmt.Fprintf(stdout, "lDDT:      %.4f\n", comparison.LDDT)
+	fmt.Fprintf(stdout, "Verdict:   %s\n", comparison.Interpretation)
+	return nil
+}
+
+// runConvert rewrites a PDB (every MODEL) as PDB, XYZ or FASTA
+func runConvert(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	in := fs.String("in", "", "input PDB (required)")
+	out := fs.String("out", "", "output file (required)")
+	format := fs.String("to", "", "output format: pdb, xyz or fasta (default: from --out extension)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" {
+		return fmt.Errorf("--in and --out are required")
+	}
+
+	to := strings.ToLower(*format)
+	if to == "" {
+		to = formatFromExtension(*out)
+	}
+
+	models, err := parser.ParsePDBModels(*in)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSuffix(filepath.Base(*in), filepath.Ext(*in))
+
+	switch to {
+	case "pdb":
+		remarks := []string{"CONVERTED FROM " + strings.ToUpper(filepath.Base(*in))}
+		if len(models) == 1 {
+			err = parser.WritePDB(models[0], *out, remarks)
+		} else {
+			err = parser.WritePDBModels(models, *out, remarks)
+		}
+	case "xyz":
+		trajectory := parser.NewTrajectory(1, 0)
+		for i, model := range models {
+			energy := physics.CalculateTotalEnergy(model, 10.0, 12.0).Total
+			trajectory.Record(i+1, i+1, energy, model)
+		}
+		err = trajectory.WriteXYZ(*out)
+	case "fasta":
+		err = writeFASTA(*out, name, models)
+	default:
+		return fmt.Errorf("unknown output format %q (want pdb, xyz or fasta)", to)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Wrote %s (%s, %d model(s), %d residues)\n", *out, to, len(models), len(models[0].Residues))
+	return nil
+}
+
+// formatFromExtension maps an output path to pdb, xyz or fasta
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdb", ".ent":
+		return "pdb"
+	case ".xyz":
+		return "xyz"
+	case ".fasta", ".fa", ".faa":
+		return "fasta"
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+// writeFASTA writes one record per model, wrapped at 60 residues
+func writeFASTA(path, name string, models []*parser.Protein) error {
+	var b strings.Builder
+	for i, model := range models {
+		header := name
+		if len(models) > 1 {
+			header = fmt.Sprintf("%s_model%d", name, i+1)
+		}
+		sequence := model.Sequence()
+		fmt.Fprintf(&b, ">%s\n", header)
+		for start := 0; start < len(sequence); start += 60 {
+			fmt.Fprintln(&b, sequence[start:min(start+60, len(sequence))])
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write FASTA file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// runCLI runs the tool in-process and returns its exit status and output
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+// TestFoldScoreValidateConvert folds a short sequence, then scores,
+// converts and validates the model against its own PDB round-trip
+func TestFoldScoreValidateConvert(t *testing.T) {
+	dir := t.TempDir()
+	model := filepath.Join(dir, "model.pdb")
+
+	status, out, errOut := runCLI("fold", "--seq", "ACDEFGHIK", "--samples", "1", "--workers", "1", "--out", model)
+	if status != 0 {
+		t.Fatalf("fold exited %d: %s", status, errOut)
+	}
+	t.Logf("fold:\n%s", out)
+
+	status, out, errOut = runCLI("score", "--pdb", model)
+	if status != 0 || !strings.Contains(out, "Energy:") || !strings.Contains(out, "Ramachandran:") || !strings.Contains(out, "Da, pI") {
+		t.Errorf("score exited %d: %s%s", status, out, errOut)
+	}
+
+	copyPath := filepath.Join(dir, "copy.pdb")
+	fastaPath := filepath.Join(dir, "model.fasta")
+	xyzPath := filepath.Join(dir, "model.xyz")
+	for _, target := range []string{copyPath, fastaPath, xyzPath} {
+		if status, _, errOut := runCLI("convert", "--in", model, "--out", target); status != 0 {
+			t.Errorf("convert to %s exited %d: %s", filepath.Ext(target), status, errOut)
+		}
+	}
+
+	fasta, err := os.ReadFile(fastaPath)
+	if err != nil || !strings.Contains(string(fasta), "ACDEFGHIK") {
+		t.Errorf("FASTA output %q (err %v) does not hold the sequence", fasta, err)
+	}
+	protein, err := parser.ParsePDB(model)
+	if err != nil {
+		t.Fatalf("Model PDB does not parse: %v", err)
+	}
+	xyz, err := os.ReadFile(xyzPath)
+	if header := fmt.Sprintf("%d\n", len(protein.Atoms)); err != nil || !strings.HasPrefix(string(xyz), header) {
+		t.Errorf("XYZ output does not start with the atom count %d: %.40q (err %v)", len(protein.Atoms), xyz, err)
+	}
+
+	status, out, errOut = runCLI("validate", "--model", copyPath, "--native", model)
+	if status != 0 {
+		t.Fatalf("validate exited %d: %s", status, errOut)
+	}
+	t.Logf("validate:\n%s", out)
+	if !strings.Contains(out, "RMSD:      0.000") || !strings.Contains(out, "lDDT:      1.0000") {
+		t.Errorf("PDB round-trip does not validate as identical:\n%s", out)
+	}
+}
+
+// TestCLIErrors checks bad invocations exit non-zero with a message
+func TestCLIErrors(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		args []string
+		want int
+	}{
+		{nil, 2},
+		{[]string{"bogus"}, 2},
+		{[]string{"fold", "--out", filepath.Join(dir, "x.pdb")}, 1},
+		{[]string{"fold", "--seq", "AC1D", "--out", filepath.Join(dir, "x.pdb")}, 1},
+		{[]string{"fold", "--seq", "ACDE", "--config", filepath.Join(dir, "missing.json"), "--out", filepath.Join(dir, "x.pdb")}, 1},
+		{[]string{"score"}, 1},
+		{[]string{"score", "--pdb", filepath.Join(dir, "missing.pdb")}, 1},
+		{[]string{"validate", "--model", "a.pdb"}, 1},
+		{[]string{"convert", "--in", "a.pdb", "--out", "b.mol2"}, 1},
+		{[]string{"score", "--no-such-flag"}, 1},
+		{[]string{"score", "-h"}, 0},
+	}
+	for _, tc := range cases {
+		status, _, errOut := runCLI(tc.args...)
+		if status != tc.want {
+			t.Errorf("%v exited %d, want %d", tc.args, status, tc.want)
+		}
+		if tc.want != 0 && errOut == "" {
+			t.Errorf("%v printed no error message", tc.args)
+		}
+	}
+}
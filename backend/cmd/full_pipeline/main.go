@@ -9,6 +9,7 @@ import (
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/sampling"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
 
@@ -280,7 +281,7 @@ func main() {
 	fmt.Printf("Synergy: %.2f (phase integration)\n", calculateSynergyScore(phase1RMSD, phase2BestRMSD, bestAgent.rmsd))
 	fmt.Printf("Elegance: %.2f (code quality)\n", 0.97) // Matches D3-Enterprise Grade+
 
-	overallQuality := harmonicMean([]float64{
+	overallQuality := stats.HarmonicMean([]float64{
 		calculateCorrectnessScore(bestAgent.rmsd),
 		calculatePerformanceScore(totalDuration.Seconds()),
 		0.95,
@@ -350,19 +351,3 @@ func calculateSynergyScore(phase1, phase2, phase3 float64) float64 {
 	}
 	return 0.80
 }
-
-func harmonicMean(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	sum := 0.0
-	for _, v := range values {
-		if v <= 0 {
-			return 0 // Undefined for non-positive values
-		}
-		sum += 1.0 / v
-	}
-
-	return float64(len(values)) / sum
-}
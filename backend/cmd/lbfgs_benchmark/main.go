@@ -11,6 +11,7 @@ import (
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/sampling"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
 
@@ -173,7 +174,7 @@ func main() {
 	fmt.Printf("Synergy: %.3f (improvement ratio)\n", synergy)
 	fmt.Printf("Elegance: %.3f (code quality)\n", elegance)
 
-	quality := harmonicMean([]float64{correctness, performance, reliability, synergy, elegance})
+	quality := stats.HarmonicMean([]float64{correctness, performance, reliability, synergy, elegance})
 	fmt.Printf("\nAgent 4.2 Quality: %.4f", quality)
 	if quality >= 0.96 {
 		fmt.Printf(" (LEGENDARY) ✅ TARGET MET\n")
@@ -235,22 +236,6 @@ func calculateSynergyScore(improvementRatio float64) float64 {
 	return 0.80
 }
 
-func harmonicMean(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	sum := 0.0
-	for _, v := range values {
-		if v <= 0 {
-			return 0
-		}
-		sum += 1.0 / v
-	}
-
-	return float64(len(values)) / sum
-}
-
 func convertTuningToLBFGSConfig(tuningConfig optimization.LBFGSTuningConfig) optimization.LBFGSConfig {
 	return optimization.LBFGSConfig{
 		MaxIterations:     tuningConfig.MaxIterations,
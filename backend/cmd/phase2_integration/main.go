@@ -26,7 +26,9 @@ import (
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/sampling"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
 )
@@ -141,16 +143,20 @@ func main() {
 		Structures:   make([]StructureMetric, 0, 100),
 	}
 
-	// Build initial extended structure
-	fmt.Println("Building initial extended structure...")
-	initialAngles := make([]geometry.RamachandranAngles, len(sequence))
-	for i := range initialAngles {
-		initialAngles[i] = geometry.RamachandranAngles{
-			Phi: -120.0 * math.Pi / 180.0,
-			Psi: +120.0 * math.Pi / 180.0,
-		}
+	// Build initial structure from predicted secondary structure
+	// (helices as helices, coil as PPII - not a uniformly extended strand)
+	fmt.Println("Building initial structure from predicted secondary structure...")
+	ssPred, err := prediction.PredictSecondaryStructure(sequence, prediction.DefaultPredictionConfig())
+	if err != nil {
+		fmt.Printf("❌ ERROR: Secondary structure prediction failed: %v\n", err)
+		return
 	}
-	initialStructure, err := geometry.BuildProteinFromAngles(sequence, initialAngles)
+	ss := make([]prediction.SecondaryStructureType, len(ssPred))
+	for i, p := range ssPred {
+		ss[i] = p.PredictedType
+	}
+	fmt.Printf("  Predicted SS: %s\n", prediction.GetSecondaryStructureString(ssPred))
+	initialStructure, err := geometry.BuildFromSecondaryStructure(sequence, ss)
 	if err != nil {
 		fmt.Printf("❌ ERROR: Failed to build initial structure: %v\n", err)
 		return
@@ -333,19 +339,19 @@ func main() {
 
 		result.BestRMSD = rmsds[0]
 		result.WorstRMSD = rmsds[len(rmsds)-1]
-		result.MedianRMSD = median(rmsds)
-		result.MeanRMSD = mean(rmsds)
-		result.RMSDStdDev = stdDev(rmsds)
+		result.MedianRMSD = stats.Median(rmsds)
+		result.MeanRMSD = stats.Mean(rmsds)
+		result.RMSDStdDev = stats.StdDev(rmsds)
 		result.RMSDImprovement = (26.45 - result.BestRMSD) / 26.45 * 100 // vs Phase 1
 
 		result.BestEnergy = energies[0]
 		result.WorstEnergy = energies[len(energies)-1]
-		result.MedianEnergy = median(energies)
-		result.MeanEnergy = mean(energies)
+		result.MedianEnergy = stats.Median(energies)
+		result.MeanEnergy = stats.Mean(energies)
 
 		result.BestVedic = vedics[len(vedics)-1]
-		result.MedianVedic = median(vedics)
-		result.MeanVedic = mean(vedics)
+		result.MedianVedic = stats.Median(vedics)
+		result.MeanVedic = stats.Mean(vedics)
 
 		// Find best structure for TM-score and GDT_TS
 		bestIdx := 0
@@ -363,9 +369,16 @@ func main() {
 			methodRMSDs[s.SamplingMethod] = append(methodRMSDs[s.SamplingMethod], s.RMSD)
 		}
 
+		// Sorted, so a tie goes to the same method on every run
+		methods := make([]string, 0, len(methodRMSDs))
+		for method := range methodRMSDs {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
 		bestMethodRMSD := math.Inf(1)
-		for method, methodRmsds := range methodRMSDs {
-			avgRMSD := mean(methodRmsds)
+		for _, method := range methods {
+			avgRMSD := stats.Mean(methodRMSDs[method])
 			if avgRMSD < bestMethodRMSD {
 				bestMethodRMSD = avgRMSD
 				result.BestMethod = method
@@ -507,42 +520,6 @@ func getQualityTier(score float64) string {
 	}
 }
 
-// Statistical helper functions
-func mean(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	sum := 0.0
-	for _, v := range values {
-		sum += v
-	}
-	return sum / float64(len(values))
-}
-
-func median(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	n := len(values)
-	if n%2 == 0 {
-		return (values[n/2-1] + values[n/2]) / 2.0
-	}
-	return values[n/2]
-}
-
-func stdDev(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	m := mean(values)
-	sumSq := 0.0
-	for _, v := range values {
-		diff := v - m
-		sumSq += diff * diff
-	}
-	return math.Sqrt(sumSq / float64(len(values)))
-}
-
 // printPhase2Results prints comprehensive results
 func printPhase2Results(result *Phase2Result) {
 	fmt.Println("📊 PHASE 2 RESULTS:")
@@ -21,13 +21,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"math"
 	"os"
 	"time"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
@@ -131,18 +131,20 @@ func main() {
 		Timestamp:    time.Now(),
 	}
 
-	// Build initial structure (extended conformation)
-	// In production, we'd load Phase 2's best structure (5.01 Å)
-	// For this demo, we'll start from extended and show the full cascade
-	fmt.Println("Building initial extended structure...")
-	initialAngles := make([]geometry.RamachandranAngles, len(sequence))
-	for i := range initialAngles {
-		initialAngles[i] = geometry.RamachandranAngles{
-			Phi: -120.0 * math.Pi / 180.0,
-			Psi: +120.0 * math.Pi / 180.0,
-		}
+	// Build initial structure from predicted secondary structure
+	// (helices as helices, coil as PPII - not a uniformly extended strand)
+	fmt.Println("Building initial structure from predicted secondary structure...")
+	ssPred, err := prediction.PredictSecondaryStructure(sequence, prediction.DefaultPredictionConfig())
+	if err != nil {
+		fmt.Printf("❌ ERROR: Secondary structure prediction failed: %v\n", err)
+		return
+	}
+	ss := make([]prediction.SecondaryStructureType, len(ssPred))
+	for i, p := range ssPred {
+		ss[i] = p.PredictedType
 	}
-	protein, err := geometry.BuildProteinFromAngles(sequence, initialAngles)
+	fmt.Printf("  Predicted SS: %s\n", prediction.GetSecondaryStructureString(ssPred))
+	protein, err := geometry.BuildFromSecondaryStructure(sequence, ss)
 	if err != nil {
 		fmt.Printf("❌ ERROR: Failed to build initial structure: %v\n", err)
 		return
@@ -0,0 +1,258 @@
+// Package main - FoldVedic HTTP folding server
+//
+// Exposes the unified pipeline over HTTP:
+//
+//	POST /fold    {"sequence": "ACDEF...", "config": {...}} -> PDB + scores
+//	GET  /health  -> {"status": "ok"}
+//
+// Each fold runs under a context deadline (the server's -timeout, or a
+// shorter timeout_seconds from the request) so a runaway fold cannot hang
+// the server. A fold cut off during optimization still returns the best
+// structure found so far, marked "partial".
+//
+// Usage: go run ./cmd/server -addr :8080 -timeout 2m
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/pipeline"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// maxRequestBytes bounds the JSON body of POST /fold
+const maxRequestBytes = 1 << 20
+
+// serverConfig holds the limits applied to every request
+type serverConfig struct {
+	Timeout        time.Duration // Per-fold deadline (upper bound for timeout_seconds)
+	MaxSequenceLen int           // Longest sequence accepted
+}
+
+// foldOverrides are optional changes to the QuickFold defaults
+type foldOverrides struct {
+	NumSamplesPerMethod     *int     `json:"num_samples_per_method,omitempty"`
+	MaxWorkers              *int     `json:"max_workers,omitempty"`
+	UseQuaternionSlerp      *bool    `json:"use_quaternion_slerp,omitempty"`
+	UseMonteCarlo           *bool    `json:"use_monte_carlo,omitempty"`
+	UseFragmentAssembly     *bool    `json:"use_fragment_assembly,omitempty"`
+	UseBasinExplorer        *bool    `json:"use_basin_explorer,omitempty"`
+	UseConstraintRefinement *bool    `json:"use_constraint_refinement,omitempty"`
+	UseVedicBiasing         *bool    `json:"use_vedic_biasing,omitempty"`
+	TimeoutSeconds          *float64 `json:"timeout_seconds,omitempty"`
+}
+
+// foldRequest is the POST /fold body
+type foldRequest struct {
+	Sequence string        `json:"sequence"`
+	Config   foldOverrides `json:"config"`
+}
+
+// foldResponse is the POST /fold result
+type foldResponse struct {
+	Sequence           string  `json:"sequence"`
+	PDB                string  `json:"pdb"`
+	SecondaryStructure string  `json:"secondary_structure"`
+	Energy             float64 `json:"energy"`
+	VedicScore         float64 `json:"vedic_score"`
+	CombinedScore      float64 `json:"combined_score"`
+	QualityScore       float64 `json:"quality_score"`
+	CompactnessScore   float64 `json:"compactness_score"`
+	RadiusOfGyration   float64 `json:"radius_of_gyration"`
+	SamplesGenerated   int     `json:"samples_generated"`
+	TimeSeconds        float64 `json:"time_seconds"`
+	Partial            bool    `json:"partial"` // Deadline hit; best structure so far
+}
+
+// errorResponse is returned with every non-200 status
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	timeout := flag.Duration("timeout", 2*time.Minute, "maximum time per fold")
+	maxLen := flag.Int("max-length", 200, "longest sequence accepted")
+	flag.Parse()
+
+	handler := newServer(serverConfig{Timeout: *timeout, MaxSequenceLen: *maxLen})
+
+	log.Printf("FoldVedic server listening on %s (fold timeout %s)", *addr, *timeout)
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newServer returns the HTTP handler serving /fold and /health
+func newServer(config serverConfig) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", handleHealth)
+	mux.HandleFunc("/fold", func(w http.ResponseWriter, r *http.Request) {
+		handleFold(w, r, config)
+	})
+	return mux
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func handleFold(w http.ResponseWriter, r *http.Request, server serverConfig) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+
+	var req foldRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+
+	sequence := strings.ToUpper(strings.TrimSpace(req.Sequence))
+	if err := parser.ValidateSequence(sequence); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if server.MaxSequenceLen > 0 && len(sequence) > server.MaxSequenceLen {
+		writeError(w, http.StatusBadRequest,
+			fmt.Sprintf("sequence has %d residues, limit is %d", len(sequence), server.MaxSequenceLen))
+		return
+	}
+
+	config, timeout, err := applyOverrides(sequence, req.Config, server.Timeout)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := pipeline.RunUnifiedPipelineV2Ctx(ctx, config, nil)
+	partial := false
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("fold failed: %v", err))
+			return
+		}
+		if result == nil || result.FinalStructure == nil {
+			writeError(w, http.StatusGatewayTimeout, fmt.Sprintf("fold stopped before any structure was optimized: %v", err))
+			return
+		}
+		partial = true
+	}
+
+	var pdb strings.Builder
+	remarks := []string{
+		"FOLDVEDIC SERVER PREDICTION",
+		fmt.Sprintf("FINAL ENERGY: %.3f KCAL/MOL", result.FinalEnergy),
+		fmt.Sprintf("VEDIC SCORE: %.4f", result.FinalVedicScore),
+		fmt.Sprintf("QUALITY SCORE: %.4f", result.QualityScore),
+	}
+	if partial {
+		remarks = append(remarks, "PARTIAL RESULT: DEADLINE REACHED DURING OPTIMIZATION")
+	}
+	if err := parser.WritePDBTo(&pdb, result.FinalStructure, remarks); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to write PDB: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, foldResponse{
+		Sequence:           sequence,
+		PDB:                pdb.String(),
+		SecondaryStructure: prediction.GetSecondaryStructureString(result.SecondaryStructure),
+		Energy:             result.FinalEnergy,
+		VedicScore:         result.FinalVedicScore,
+		CombinedScore:      result.CombinedScore,
+		QualityScore:       result.QualityScore,
+		CompactnessScore:   result.CompactnessScore,
+		RadiusOfGyration:   result.RadiusOfGyration,
+		SamplesGenerated:   result.TotalSamplesGenerated,
+		TimeSeconds:        result.TotalTimeSeconds,
+		Partial:            partial,
+	})
+}
+
+// applyOverrides builds the QuickFold configuration with the request's
+// overrides, and the fold deadline (never longer than maxTimeout)
+func applyOverrides(sequence string, overrides foldOverrides, maxTimeout time.Duration) (pipeline.UnifiedPipelineV2Config, time.Duration, error) {
+	config := pipeline.DefaultUnifiedPipelineV2Config(sequence)
+
+	if overrides.NumSamplesPerMethod != nil {
+		if *overrides.NumSamplesPerMethod < 1 || *overrides.NumSamplesPerMethod > 100 {
+			return config, 0, fmt.Errorf("num_samples_per_method must be in [1, 100]")
+		}
+		config.NumSamplesPerMethod = *overrides.NumSamplesPerMethod
+	}
+	if overrides.MaxWorkers != nil {
+		if *overrides.MaxWorkers < 1 || *overrides.MaxWorkers > config.MaxWorkers {
+			return config, 0, fmt.Errorf("max_workers must be in [1, %d]", config.MaxWorkers)
+		}
+		config.MaxWorkers = *overrides.MaxWorkers
+	}
+
+	toggles := []struct {
+		value  *bool
+		target *bool
+	}{
+		{overrides.UseQuaternionSlerp, &config.UseQuaternionSlerp},
+		{overrides.UseMonteCarlo, &config.UseMonteCarlo},
+		{overrides.UseFragmentAssembly, &config.UseFragmentAssembly},
+		{overrides.UseBasinExplorer, &config.UseBasinExplorer},
+		{overrides.UseConstraintRefinement, &config.UseConstraintRefinement},
+		{overrides.UseVedicBiasing, &config.UseVedicBiasing},
+	}
+	for _, toggle := range toggles {
+		if toggle.value != nil {
+			*toggle.target = *toggle.value
+		}
+	}
+	if !config.UseQuaternionSlerp && !config.UseMonteCarlo && !config.UseFragmentAssembly && !config.UseBasinExplorer {
+		return config, 0, fmt.Errorf("at least one sampling method must be enabled")
+	}
+
+	timeout := maxTimeout
+	if overrides.TimeoutSeconds != nil {
+		if *overrides.TimeoutSeconds <= 0 {
+			return config, 0, fmt.Errorf("timeout_seconds must be positive")
+		}
+		requested := time.Duration(*overrides.TimeoutSeconds * float64(time.Second))
+		if maxTimeout <= 0 || requested < maxTimeout {
+			timeout = requested
+		}
+	}
+
+	return config, timeout, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
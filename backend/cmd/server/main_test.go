@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestFoldHandler posts a short sequence and checks the response carries a
+// parseable PDB with one residue per amino acid
+func TestFoldHandler(t *testing.T) {
+	server := newServer(serverConfig{Timeout: time.Minute, MaxSequenceLen: 50})
+
+	body := `{"sequence": "ACDEFGHIK", "config": {"num_samples_per_method": 1, "max_workers": 1}}`
+	req := httptest.NewRequest(http.MethodPost, "/fold", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /fold returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp foldResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Response is not JSON: %v", err)
+	}
+	t.Logf("Energy %.2f kcal/mol, Vedic %.3f, quality %.3f, %.2f s (partial %v)",
+		resp.Energy, resp.VedicScore, resp.QualityScore, resp.TimeSeconds, resp.Partial)
+
+	residues := 0
+	err := parser.ParsePDBStream(strings.NewReader(resp.PDB), func(*parser.Residue) error {
+		residues++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PDB body does not parse: %v", err)
+	}
+	if residues != len("ACDEFGHIK") {
+		t.Errorf("PDB has %d residues, want %d", residues, len("ACDEFGHIK"))
+	}
+}
+
+// TestFoldHandlerDeadline folds a sequence too long to finish within a very
+// short timeout_seconds and checks the response arrives within a small
+// multiple of the deadline, marked partial or as a gateway timeout
+func TestFoldHandlerDeadline(t *testing.T) {
+	const deadline = 250 * time.Millisecond
+	server := newServer(serverConfig{Timeout: time.Minute, MaxSequenceLen: 100})
+
+	sequence := strings.Repeat("AEELLKKAEELLKK", 4)
+	body := fmt.Sprintf(`{"sequence": %q, "config": {"num_samples_per_method": 1, "max_workers": 1, "timeout_seconds": %g}}`, sequence, deadline.Seconds())
+	req := httptest.NewRequest(http.MethodPost, "/fold", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	server.ServeHTTP(rec, req)
+	elapsed := time.Since(start)
+	t.Logf("Deadline %v: %d after %v", deadline, rec.Code, elapsed)
+
+	if elapsed > 4*deadline {
+		t.Errorf("Response took %v, more than 4× the %v deadline", elapsed, deadline)
+	}
+
+	switch rec.Code {
+	case http.StatusOK:
+		var resp foldResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Response is not JSON: %v", err)
+		}
+		if !resp.Partial || !strings.Contains(resp.PDB, "PARTIAL RESULT") {
+			t.Errorf("Result within the deadline not marked partial (partial %v)", resp.Partial)
+		}
+	case http.StatusGatewayTimeout:
+		// Stopped before any structure was optimized
+	default:
+		t.Fatalf("POST /fold returned %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestServerRejectsBadRequests checks validation errors and /health
+func TestServerRejectsBadRequests(t *testing.T) {
+	server := newServer(serverConfig{Timeout: time.Minute, MaxSequenceLen: 5})
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodGet, "/health", "", http.StatusOK},
+		{http.MethodGet, "/fold", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/fold", `{"sequence": "AC1DE"}`, http.StatusBadRequest},
+		{http.MethodPost, "/fold", `{"sequence": "ACDEFGHIK"}`, http.StatusBadRequest},
+		{http.MethodPost, "/fold", `{"sequence": "ACD", "config": {"timeout_seconds": -1}}`, http.StatusBadRequest},
+		{http.MethodPost, "/fold", `not json`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s %s %q returned %d, want %d: %s", tc.method, tc.path, tc.body, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+}
@@ -48,6 +48,9 @@ func main() {
 		log.Fatal("❌ Broken backbone structure should fail validation")
 	}
 	fmt.Printf("  Error: %s\n", report3.ValidationError)
+	for _, v := range report3.GeometryViolations {
+		fmt.Printf("  Geometry: %s\n", v)
+	}
 	fmt.Println("  ✅ PASS")
 	fmt.Println()
 
@@ -12,7 +12,10 @@ func main() {
 	fmt.Println("=== Detailed H-Bond Geometry Check ===")
 
 	// Load PDB
-	protein, err := parser.ParsePDB("testdata/1L2Y.pdb")
+	protein, err := parser.ParsePDBWithOptions("testdata/1L2Y.pdb", parser.PDBOptions{
+		NormalizeNames: true,
+		Logger:         log.Default(),
+	})
 	if err != nil {
 		log.Fatalf("Failed to load PDB: %v", err)
 	}
@@ -20,7 +23,7 @@ func main() {
 	// Build H atom map
 	hAtomMap := make(map[int]*parser.Atom)
 	for _, atom := range protein.Atoms {
-		if atom.Element == "H" && (atom.Name == "H" || atom.Name == "HN") {
+		if atom.Element == "H" && atom.Name == "H" {
 			hAtomMap[atom.ResSeq] = atom
 			fmt.Printf("H atom for residue %d: (%.2f, %.2f, %.2f)\n",
 				atom.ResSeq, atom.X, atom.Y, atom.Z)
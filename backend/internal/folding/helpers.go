@@ -36,20 +36,7 @@ func CalculateEnergy(protein *parser.Protein) float64 {
 }
 
 // GetSequence extracts amino acid sequence from protein structure
+// (see parser.Protein.OneLetterSequence)
 func GetSequence(protein *parser.Protein) string {
-	if protein == nil || len(protein.Residues) == 0 {
-		return ""
-	}
-
-	sequence := make([]byte, len(protein.Residues))
-	for i, res := range protein.Residues {
-		// Convert three-letter code to one-letter
-		// Simplified: just take first letter for now
-		if len(res.Name) > 0 {
-			sequence[i] = res.Name[0]
-		} else {
-			sequence[i] = 'X' // Unknown
-		}
-	}
-	return string(sequence)
+	return protein.OneLetterSequence()
 }
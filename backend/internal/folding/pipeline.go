@@ -79,7 +79,7 @@ type PredictionResult struct {
 // 4. Select best structure (lowest energy + highest Vedic score)
 // 5. Validate against experimental if available
 func PredictStructure(config PredictionConfig, experimental *parser.Protein) (*PredictionResult, error) {
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
 	result := &PredictionResult{}
 
@@ -104,7 +104,7 @@ func PredictStructure(config PredictionConfig, experimental *parser.Protein) (*P
 		structure := cloneProtein(config.InitialStructure)
 
 		// Perturb angles for conformational sampling
-		perturbStructure(structure, sample)
+		perturbStructure(structure, sample, rng)
 
 		// Energy minimize
 		minResult, err := physics.MinimizeEnergy(structure, config.MinimizerConfig)
@@ -290,6 +290,7 @@ func cloneProtein(protein *parser.Protein) *parser.Protein {
 		clonedRes := &parser.Residue{
 			Name:    res.Name,
 			SeqNum:  res.SeqNum,
+			ICode:   res.ICode,
 			ChainID: res.ChainID,
 		}
 		if res.N != nil {
@@ -310,14 +311,14 @@ func cloneProtein(protein *parser.Protein) *parser.Protein {
 	return clone
 }
 
-func perturbStructure(protein *parser.Protein, sampleIndex int) {
+func perturbStructure(protein *parser.Protein, sampleIndex int, rng *rand.Rand) {
 	// Add small random perturbations to break symmetry
 	noise := 0.1 * float64(sampleIndex+1) // Increasing noise per sample
 
 	for _, atom := range protein.Atoms {
-		atom.X += (rand.Float64()*2 - 1) * noise
-		atom.Y += (rand.Float64()*2 - 1) * noise
-		atom.Z += (rand.Float64()*2 - 1) * noise
+		atom.X += (rng.Float64()*2 - 1) * noise
+		atom.Y += (rng.Float64()*2 - 1) * noise
+		atom.Z += (rng.Float64()*2 - 1) * noise
 	}
 }
 
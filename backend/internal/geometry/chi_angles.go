@@ -0,0 +1,212 @@
+// Package geometry - Side-chain χ angles
+//
+// φ and ψ fix the backbone; χ1..χ4 fix each side chain. Reporting them is
+// what rotamer analysis and dihedral-space side-chain optimization need.
+//
+// BIOCHEMIST: χ1 = N-CA-CB-XG, χ2 = CA-CB-XG-XD, ... along the side chain
+// (IUPAC-IUB 1970 atom definitions). Gly and Ala have no χ.
+// PHYSICIST: Where the last atom of a χ has a chemically equivalent twin
+// (Asp OD1/OD2, Glu OE1/OE2, Phe and Tyr CD1/CD2) the angle is only
+// defined modulo 180°; it is reported in [-90°, 90°) so the arbitrary
+// atom labelling does not change it
+// MATHEMATICIAN: Radians in [-π, π], IUPAC sign, like CalculateRamachandran
+//
+// Citation: IUPAC-IUB Commission on Biochemical Nomenclature (1970).
+// "Abbreviations and symbols for the description of the conformation of
+// polypeptide chains." Biochemistry 9(18): 3471-3479.
+package geometry
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// chiDefinition names the four atoms of one χ angle
+type chiDefinition struct {
+	atoms     [4]string
+	symmetric bool // Last atom has an equivalent twin: χ is defined modulo π
+}
+
+// chiDefinitions lists χ1, χ2, ... per residue type
+var chiDefinitions = map[string][]chiDefinition{
+	"ALA": {},
+	"GLY": {},
+	"ARG": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD"}},
+		{atoms: [4]string{"CB", "CG", "CD", "NE"}},
+		{atoms: [4]string{"CG", "CD", "NE", "CZ"}},
+	},
+	"ASN": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "OD1"}},
+	},
+	"ASP": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "OD1"}, symmetric: true},
+	},
+	"CYS": {
+		{atoms: [4]string{"N", "CA", "CB", "SG"}},
+	},
+	"GLN": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD"}},
+		{atoms: [4]string{"CB", "CG", "CD", "OE1"}},
+	},
+	"GLU": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD"}},
+		{atoms: [4]string{"CB", "CG", "CD", "OE1"}, symmetric: true},
+	},
+	"HIS": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "ND1"}},
+	},
+	"ILE": {
+		{atoms: [4]string{"N", "CA", "CB", "CG1"}},
+		{atoms: [4]string{"CA", "CB", "CG1", "CD1"}},
+	},
+	"LEU": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD1"}},
+	},
+	"LYS": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD"}},
+		{atoms: [4]string{"CB", "CG", "CD", "CE"}},
+		{atoms: [4]string{"CG", "CD", "CE", "NZ"}},
+	},
+	"MET": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "SD"}},
+		{atoms: [4]string{"CB", "CG", "SD", "CE"}},
+	},
+	"PHE": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD1"}, symmetric: true},
+	},
+	"PRO": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD"}},
+	},
+	"SER": {
+		{atoms: [4]string{"N", "CA", "CB", "OG"}},
+	},
+	"THR": {
+		{atoms: [4]string{"N", "CA", "CB", "OG1"}},
+	},
+	"TRP": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD1"}},
+	},
+	"TYR": {
+		{atoms: [4]string{"N", "CA", "CB", "CG"}},
+		{atoms: [4]string{"CA", "CB", "CG", "CD1"}, symmetric: true},
+	},
+	"VAL": {
+		{atoms: [4]string{"N", "CA", "CB", "CG1"}},
+	},
+}
+
+// CalculateChiAngles returns χ1..χn (radians) for every residue of a
+// standard type, keyed by index into protein.Residues
+//
+// Gly and Ala get an empty slice; residue types without χ definitions
+// (ligands, unknown residues) are left out. A χ whose atoms are not all
+// present is NaN, as in CalculateRamachandran, so the slice length is
+// always the residue type's χ count. Symmetric terminal χ (Asp χ2, Glu χ3,
+// Phe and Tyr χ2) lie in [-π/2, π/2).
+func CalculateChiAngles(protein *parser.Protein) map[int][]float64 {
+	chis := make(map[int][]float64, len(protein.Residues))
+	atoms := residueAtomsByName(protein)
+
+	for i, res := range protein.Residues {
+		if res == nil {
+			continue
+		}
+		name := res.Name
+		if len(name) == 1 {
+			// One-letter names from the coordinate builders
+			name = parser.OneToThree(name[0])
+		}
+		defs, ok := chiDefinitions[name]
+		if !ok {
+			continue
+		}
+
+		angles := make([]float64, len(defs))
+		for k, def := range defs {
+			angles[k] = math.NaN()
+			var p [4]Vector3
+			complete := true
+			for a, atomName := range def.atoms {
+				atom := atoms[i][atomName]
+				if atom == nil {
+					complete = false
+					break
+				}
+				p[a] = atomToVector(atom)
+			}
+			if !complete {
+				continue
+			}
+
+			chi := definedDihedral(p[0], p[1], p[2], p[3])
+			if def.symmetric && !math.IsNaN(chi) {
+				chi = foldHalfTurn(chi)
+			}
+			angles[k] = chi
+		}
+		chis[i] = angles
+	}
+
+	return chis
+}
+
+// foldHalfTurn maps an angle defined modulo π into [-π/2, π/2)
+func foldHalfTurn(angle float64) float64 {
+	for angle >= math.Pi/2 {
+		angle -= math.Pi
+	}
+	for angle < -math.Pi/2 {
+		angle += math.Pi
+	}
+	return angle
+}
+
+// residueAtomsByName indexes each residue's atoms by name, from the residue
+// backbone pointers and protein.Atoms (matched on chain and residue number,
+// keeping the first alternate location)
+func residueAtomsByName(protein *parser.Protein) []map[string]*parser.Atom {
+	type key struct {
+		chain string
+		seq   int
+		iCode string
+	}
+	atoms := make([]map[string]*parser.Atom, len(protein.Residues))
+	index := make(map[key]int, len(protein.Residues))
+	for i, res := range protein.Residues {
+		atoms[i] = make(map[string]*parser.Atom)
+		if res == nil {
+			continue
+		}
+		index[key{res.ChainID, res.SeqNum, res.ICode}] = i
+		for name, atom := range map[string]*parser.Atom{"N": res.N, "CA": res.CA, "C": res.C, "O": res.O} {
+			if atom != nil {
+				atoms[i][name] = atom
+			}
+		}
+	}
+
+	for _, atom := range protein.Atoms {
+		i, ok := index[key{atom.ChainID, atom.ResSeq, atom.ICode}]
+		if !ok {
+			continue
+		}
+		if atoms[i][atom.Name] == nil {
+			atoms[i][atom.Name] = atom
+		}
+	}
+	return atoms
+}
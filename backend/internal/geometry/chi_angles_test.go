@@ -0,0 +1,174 @@
+package geometry
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// chiTestChain builds GDFKA with side chains placed at known χ (radians)
+func chiTestChain(t *testing.T, chis map[int][]float64) *parser.Protein {
+	t.Helper()
+	deg := math.Pi / 180
+	sequence := "GDFKA"
+	angles := make([]RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = RamachandranAngles{Phi: -65 * deg, Psi: -40 * deg}
+	}
+	protein, err := BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+
+	serial := len(protein.Atoms) + 1
+	add := func(i int, name, element string, pos [3]float64) [3]float64 {
+		protein.Atoms = append(protein.Atoms, &parser.Atom{
+			Serial: serial, Name: name, ResName: string(sequence[i]), ChainID: "A",
+			ResSeq: i + 1, X: pos[0], Y: pos[1], Z: pos[2], Element: element,
+		})
+		serial++
+		return pos
+	}
+	pos := func(atom *parser.Atom) [3]float64 { return [3]float64{atom.X, atom.Y, atom.Z} }
+
+	for i := 1; i < len(sequence); i++ {
+		res := protein.Residues[i]
+		n, ca, c := pos(res.N), pos(res.CA), pos(res.C)
+		cb := add(i, "CB", "C", PlaceAtom(c, n, ca, BondCA_CB, AngleN_CA_CB*deg, -122.5*deg))
+		chi := chis[i]
+		switch sequence[i] {
+		case 'D':
+			cg := add(i, "CG", "C", PlaceAtom(n, ca, cb, 1.52, 113*deg, chi[0]))
+			add(i, "OD1", "O", PlaceAtom(ca, cb, cg, 1.25, 119*deg, chi[1]))
+			add(i, "OD2", "O", PlaceAtom(ca, cb, cg, 1.25, 119*deg, chi[1]+math.Pi))
+		case 'F':
+			cg := add(i, "CG", "C", PlaceAtom(n, ca, cb, 1.50, 114*deg, chi[0]))
+			add(i, "CD1", "C", PlaceAtom(ca, cb, cg, 1.39, 121*deg, chi[1]))
+			add(i, "CD2", "C", PlaceAtom(ca, cb, cg, 1.39, 121*deg, chi[1]+math.Pi))
+		case 'K':
+			cg := add(i, "CG", "C", PlaceAtom(n, ca, cb, 1.52, 114*deg, chi[0]))
+			cd := add(i, "CD", "C", PlaceAtom(ca, cb, cg, 1.52, 111*deg, chi[1]))
+			ce := add(i, "CE", "C", PlaceAtom(cb, cg, cd, 1.52, 111*deg, chi[2]))
+			add(i, "NZ", "N", PlaceAtom(cg, cd, ce, 1.49, 112*deg, chi[3]))
+		}
+	}
+	return protein
+}
+
+func TestCalculateChiAngles(t *testing.T) {
+	deg := math.Pi / 180
+	placed := map[int][]float64{
+		1: {-70 * deg, 130 * deg}, // Asp χ2 130° folds to -50°
+		2: {-170 * deg, 80 * deg},
+		3: {-65 * deg, 175 * deg, -178 * deg, 60 * deg},
+	}
+	want := map[int][]float64{
+		0: {},
+		1: {-70 * deg, -50 * deg},
+		2: {-170 * deg, 80 * deg},
+		3: placed[3],
+		4: {},
+	}
+
+	protein := chiTestChain(t, placed)
+	check := func(label string, got map[int][]float64) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got χ for %d residues, want %d", label, len(got), len(want))
+		}
+		for i, wantChis := range want {
+			if len(got[i]) != len(wantChis) {
+				t.Fatalf("%s: residue %d has %d χ, want %d", label, i, len(got[i]), len(wantChis))
+			}
+			for k := range wantChis {
+				if math.Abs(got[i][k]-wantChis[k]) > 1e-6 {
+					t.Errorf("%s: residue %d χ%d = %.3f°, want %.3f°", label, i, k+1, got[i][k]/deg, wantChis[k]/deg)
+				}
+			}
+		}
+	}
+	check("built", CalculateChiAngles(protein))
+
+	// Through a PDB file: three-letter residue names
+	path := filepath.Join(t.TempDir(), "chi.pdb")
+	if err := parser.WritePDB(protein, path, nil); err != nil {
+		t.Fatalf("WritePDB failed: %v", err)
+	}
+	parsed, err := parser.ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+	if parsed.Residues[1].Name != "ASP" {
+		t.Fatalf("parsed residue 1 is %q, want ASP", parsed.Residues[1].Name)
+	}
+	parsedChis := CalculateChiAngles(parsed)
+	for i, wantChis := range want {
+		for k := range wantChis {
+			// PDB coordinates are rounded to 0.001 Å
+			if math.Abs(parsedChis[i][k]-wantChis[k]) > 0.2*deg {
+				t.Errorf("parsed: residue %d χ%d = %.3f°, want %.3f°", i, k+1, parsedChis[i][k]/deg, wantChis[k]/deg)
+			}
+		}
+	}
+
+	// A missing atom leaves its χ undefined but not the others
+	var trimmed []*parser.Atom
+	for _, atom := range protein.Atoms {
+		if !(atom.ResSeq == 4 && atom.Name == "NZ") {
+			trimmed = append(trimmed, atom)
+		}
+	}
+	protein.Atoms = trimmed
+	lys := CalculateChiAngles(protein)[3]
+	if !math.IsNaN(lys[3]) {
+		t.Errorf("Lys χ4 without NZ = %.3f, want NaN", lys[3])
+	}
+	if math.IsNaN(lys[2]) {
+		t.Error("Lys χ3 should not depend on NZ")
+	}
+}
+
+// TestCalculateChiAngles1L2Y checks χ1 of Tyr3 in the Trp-cage NMR
+// structure against the dihedral of its own atoms
+func TestCalculateChiAngles1L2Y(t *testing.T) {
+	const path = "../../../testdata/1L2Y.pdb"
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s not available (fetch with cmd/download_pdb)", path)
+	}
+	protein, err := parser.ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+
+	atoms := make(map[string]*parser.Atom)
+	index := -1
+	for i, res := range protein.Residues {
+		if res.SeqNum == 3 {
+			index = i
+		}
+	}
+	for _, atom := range protein.Atoms {
+		if atom.ResSeq == 3 && atoms[atom.Name] == nil {
+			atoms[atom.Name] = atom
+		}
+	}
+	if index < 0 || protein.Residues[index].Name != "TYR" {
+		t.Fatalf("1L2Y residue 3 is not TYR")
+	}
+
+	chis := CalculateChiAngles(protein)[index]
+	if len(chis) != 2 {
+		t.Fatalf("Tyr3 has %d χ, want 2", len(chis))
+	}
+	want := AtomDihedral(atoms["N"], atoms["CA"], atoms["CB"], atoms["CG"])
+	if math.Abs(chis[0]-want) > 1e-9 {
+		t.Errorf("Tyr3 χ1 = %.3f, want %.3f from its atoms", chis[0], want)
+	}
+	if math.Abs(chis[1]) > math.Pi/2 {
+		t.Errorf("Tyr3 χ2 = %.3f, want within ±π/2", chis[1])
+	}
+	t.Logf("1L2Y Tyr3: χ1 = %.1f°, χ2 = %.1f°", chis[0]*180/math.Pi, chis[1]*180/math.Pi)
+}
@@ -6,7 +6,11 @@
 // - NeRF (Natural Extension Reference Frame): Uses rotation matrices
 // - Forward kinematics: Matrix multiplication chains
 //
-// OUR APPROACH: QUATERNION COMPOSITION
+// BuildProteinFromAngles now places atoms with NeRF (PlaceAtom): the
+// quaternion chain accumulated orientation errors and collapsed φ.
+// QuaternionFromAxisAngle remains for rotations elsewhere.
+//
+// ORIGINAL APPROACH: QUATERNION COMPOSITION
 // - Leverage existing quaternion engine from Wave 7
 // - Use quaternion rotations instead of matrices
 // - Composable, numerically stable, elegant
@@ -59,28 +63,29 @@ const (
 
 // BuildProteinFromAngles constructs 3D protein coordinates from (φ, ψ, ω) angles
 //
-// ALGORITHM: Quaternion-Based Forward Kinematics
+// ALGORITHM: NeRF chain growth (see PlaceAtom)
+//
+// Residue 0 is seeded with N at the origin, CA on +X and C in the XY plane.
+// Then for each residue i:
+//   1. N(i+1) from (N, CA, C)(i) with dihedral ψ_i
+//   2. O(i) from (N, CA, C)(i) with dihedral ψ_i + 180° (anti to N(i+1))
+//   3. CA(i+1) from CA(i), C(i), N(i+1) with dihedral ω = 180° (trans)
+//   4. C(i+1) from C(i), N(i+1), CA(i+1) with dihedral φ_(i+1)
 //
-// For each residue i:
-//   1. Start with previous C atom position and orientation
-//   2. Rotate by ω (peptide bond rotation) to place N
-//   3. Rotate by φ (phi dihedral) to place CA
-//   4. Rotate by ψ (psi dihedral) to place C
-//   5. Place O based on C=O bond
-//   6. Update orientation quaternion for next residue
+// Missing angles (beyond len(angles), or NaN for an interior φ/ψ) default to
+// extended (-120°, 120°), so undefined angles never produce NaN coordinates
+// or a broken chain. The first φ and last ψ only orient the chain ends.
+//
+// Proline φ is clamped into its ring-compatible window (see
+// ConstrainProlinePhi): no φ outside it can close the pyrrolidine ring.
 //
 // INPUTS:
 //   - sequence: Amino acid sequence (e.g., "ACDEFG")
-//   - angles: φ, ψ, ω angles for each residue
+//   - angles: φ, ψ angles (radians, IUPAC sign) for each residue
 //
 // OUTPUTS:
-//   - Protein with realistic 3D coordinates
-//
-// WRIGHT BROTHERS TEST:
-//   - Try on "GAC" (3 residues)
-//   - Check if bond lengths are ~correct
-//   - Check if it doesn't explode
-//
+//   - Protein with ideal bond lengths/angles; CalculateRamachandran on the
+//     result returns the input angles (proline φ after clamping)
 func BuildProteinFromAngles(sequence string, angles []RamachandranAngles) (*parser.Protein, error) {
 	n := len(sequence)
 
@@ -89,161 +94,82 @@ func BuildProteinFromAngles(sequence string, angles []RamachandranAngles) (*pars
 		Residues: make([]*parser.Residue, n),
 		Atoms:    make([]*parser.Atom, 0, n*4),
 	}
+	if n == 0 {
+		return protein, nil
+	}
 
-	// Initial position and orientation
-	// Start at origin, pointing along +X axis
-	currentPos := Vector3{0, 0, 0}
-	currentDir := Vector3{1, 0, 0} // Direction to next atom
-
-	// Reference frame: Z-axis up, Y-axis to the side
-	upVec := Vector3{0, 0, 1}
-
-	atomSerial := 1
-
-	for i := 0; i < n; i++ {
-		res := &parser.Residue{
-			Name:    string(sequence[i]),
-			SeqNum:  i + 1,
-			ChainID: "A",
-		}
+	deg := math.Pi / 180.0
+	omega := 180.0 * deg // Trans peptide bond
 
-		// Get angles for this residue
-		var phi, psi, omega float64
+	// Angles for residue i, with extended defaults
+	torsions := func(i int) (phi, psi float64) {
+		phi, psi = -120.0*deg, 120.0*deg
 		if i < len(angles) {
-			phi = angles[i].Phi
-			psi = angles[i].Psi
-			if i > 0 {
-				// Omega is typically ~180° (trans peptide bond)
-				omega = 180.0 * math.Pi / 180.0
+			if !math.IsNaN(angles[i].Phi) {
+				phi = angles[i].Phi
+			}
+			if !math.IsNaN(angles[i].Psi) {
+				psi = angles[i].Psi
 			}
-		} else {
-			// Default: extended conformation
-			phi = -120.0 * math.Pi / 180.0
-			psi = 120.0 * math.Pi / 180.0
-			omega = 180.0 * math.Pi / 180.0
 		}
-
-		// === PLACE N ATOM ===
-		if i > 0 {
-			// Rotate by omega around C-N axis
-			axis := currentDir.Normalize()
-			omegaQuat := QuaternionFromAxisAngle(axis, omega)
-			currentDir = currentDir.RotateByQuaternion(omegaQuat)
+		if sequence[i] == 'P' {
+			phi = ConstrainProlinePhi(phi)
 		}
+		return phi, psi
+	}
 
-		nPos := currentPos
-		res.N = &parser.Atom{
-			Serial:  atomSerial,
-			Name:    "N",
-			ResName: string(sequence[i]),
-			ChainID: "A",
-			ResSeq:  i + 1,
-			X:       nPos.X,
-			Y:       nPos.Y,
-			Z:       nPos.Z,
-			Element: "N",
-		}
-		protein.Atoms = append(protein.Atoms, res.N)
-		atomSerial++
-
-		// === PLACE CA ATOM ===
-		// Move along current direction by N-CA bond length
-		caDir := currentDir.Normalize()
-		caPos := nPos.Add(caDir.Scale(BondN_CA))
+	// Seed residue 0: N at origin, CA on +X, C in the XY plane
+	nPos := [3]float64{0, 0, 0}
+	caPos := [3]float64{BondN_CA, 0, 0}
+	theta := AngleN_CA_C * deg
+	cPos := [3]float64{
+		BondN_CA - BondCA_C*math.Cos(theta),
+		BondCA_C * math.Sin(theta),
+		0,
+	}
 
-		res.CA = &parser.Atom{
+	atomSerial := 1
+	newAtom := func(name, element string, i int, pos [3]float64) *parser.Atom {
+		atom := &parser.Atom{
 			Serial:  atomSerial,
-			Name:    "CA",
+			Name:    name,
 			ResName: string(sequence[i]),
 			ChainID: "A",
 			ResSeq:  i + 1,
-			X:       caPos.X,
-			Y:       caPos.Y,
-			Z:       caPos.Z,
-			Element: "C",
+			X:       pos[0],
+			Y:       pos[1],
+			Z:       pos[2],
+			Element: element,
 		}
-		protein.Atoms = append(protein.Atoms, res.CA)
 		atomSerial++
+		protein.Atoms = append(protein.Atoms, atom)
+		return atom
+	}
 
-		// Rotate by phi around N-CA axis
-		// Skip rotation if phi is undefined (N-terminal residue)
-		if !math.IsNaN(phi) {
-			axis := caDir
-			phiQuat := QuaternionFromAxisAngle(axis, phi)
-			currentDir = currentDir.RotateByQuaternion(phiQuat)
-		}
-
-		// === PLACE C ATOM ===
-		// Rotate to account for N-CA-C bond angle
-		bondAngle := AngleN_CA_C * math.Pi / 180.0
-		// Rotate current direction by (180° - bond angle)
-		rotAngle := math.Pi - bondAngle
-
-		// Find perpendicular axis for rotation
-		perpAxis := currentDir.Cross(upVec).Normalize()
-		if perpAxis.Length() < 0.1 {
-			// Current dir parallel to up, use different perpendicular
-			perpAxis = currentDir.Cross(Vector3{0, 1, 0}).Normalize()
-		}
-
-		angleQuat := QuaternionFromAxisAngle(perpAxis, rotAngle)
-		cDir := currentDir.RotateByQuaternion(angleQuat).Normalize()
+	for i := 0; i < n; i++ {
+		_, psi := torsions(i)
 
-		cPos := caPos.Add(cDir.Scale(BondCA_C))
+		// Next residue's N fixes ψ_i; O sits anti to it in the peptide plane
+		nextN := PlaceAtom(nPos, caPos, cPos, BondC_N, AngleCA_C_N*deg, psi)
+		oPos := PlaceAtom(nPos, caPos, cPos, BondC_O, AngleCA_C_O*deg, psi+math.Pi)
 
-		res.C = &parser.Atom{
-			Serial:  atomSerial,
-			Name:    "C",
-			ResName: string(sequence[i]),
+		res := &parser.Residue{
+			Name:    string(sequence[i]),
+			SeqNum:  i + 1,
 			ChainID: "A",
-			ResSeq:  i + 1,
-			X:       cPos.X,
-			Y:       cPos.Y,
-			Z:       cPos.Z,
-			Element: "C",
-		}
-		protein.Atoms = append(protein.Atoms, res.C)
-		atomSerial++
-
-		// Rotate by psi around CA-C axis
-		// Skip rotation if psi is undefined (C-terminal residue)
-		if !math.IsNaN(psi) {
-			psiAxis := cDir
-			psiQuat := QuaternionFromAxisAngle(psiAxis, psi)
-			currentDir = cDir.RotateByQuaternion(psiQuat)
-		}
-
-		// === PLACE O ATOM ===
-		// O is perpendicular to C, at C=O bond angle
-		oAngle := AngleCA_C_O * math.Pi / 180.0
-		oAxis := cDir.Cross(caDir).Normalize()
-		if oAxis.Length() < 0.1 {
-			oAxis = cDir.Cross(upVec).Normalize()
 		}
+		res.N = newAtom("N", "N", i, nPos)
+		res.CA = newAtom("CA", "C", i, caPos)
+		res.C = newAtom("C", "C", i, cPos)
+		res.O = newAtom("O", "O", i, oPos)
+		protein.Residues[i] = res
 
-		oQuat := QuaternionFromAxisAngle(oAxis, oAngle)
-		oDir := cDir.Scale(-1.0).RotateByQuaternion(oQuat).Normalize()
-		oPos := cPos.Add(oDir.Scale(BondC_O))
-
-		res.O = &parser.Atom{
-			Serial:  atomSerial,
-			Name:    "O",
-			ResName: string(sequence[i]),
-			ChainID: "A",
-			ResSeq:  i + 1,
-			X:       oPos.X,
-			Y:       oPos.Y,
-			Z:       oPos.Z,
-			Element: "O",
+		if i+1 < n {
+			phiNext, _ := torsions(i + 1)
+			nextCA := PlaceAtom(caPos, cPos, nextN, BondN_CA, AngleC_N_CA*deg, omega)
+			nextC := PlaceAtom(cPos, nextN, nextCA, BondCA_C, AngleN_CA_C*deg, phiNext)
+			nPos, caPos, cPos = nextN, nextCA, nextC
 		}
-		protein.Atoms = append(protein.Atoms, res.O)
-		atomSerial++
-
-		// Update current position for next residue
-		// Next N is C-N bond length away
-		currentPos = cPos.Add(currentDir.Scale(BondC_N))
-
-		protein.Residues[i] = res
 	}
 
 	// Add hydrogen atoms for H-bond detection
@@ -255,6 +181,37 @@ func BuildProteinFromAngles(sequence string, angles []RamachandranAngles) (*pars
 	return protein, nil
 }
 
+// PlaceAtom returns the position of atom d bonded to c, given the three
+// preceding atoms a, b, c
+//
+// bondLen = |cd| (Å), bondAngle = ∠bcd and dihedral = a-b-c-d (radians,
+// IUPAC sign: positive is clockwise looking from b to c).
+//
+// MATHEMATICIAN: NeRF builds d in the local frame [bc, n×bc, n] with
+// n = ab × bc, then maps it to lab coordinates - one 3×3 product, no
+// accumulated rotations
+//
+// CITATION:
+// Parsons, J., et al. (2005). "Practical conversion from torsion space to Cartesian space
+// for in silico protein synthesis." J. Comput. Chem. 26(10): 1063-1068.
+func PlaceAtom(a, b, c [3]float64, bondLen, bondAngle, dihedral float64) [3]float64 {
+	av := Vector3{a[0], a[1], a[2]}
+	bv := Vector3{b[0], b[1], b[2]}
+	cv := Vector3{c[0], c[1], c[2]}
+
+	bc := cv.Sub(bv).Normalize()
+	normal := bv.Sub(av).Cross(bc).Normalize()
+	inPlane := normal.Cross(bc)
+
+	// d relative to c in the local frame
+	x := -bondLen * math.Cos(bondAngle)
+	y := bondLen * math.Sin(bondAngle) * math.Cos(dihedral)
+	z := bondLen * math.Sin(bondAngle) * math.Sin(dihedral)
+
+	d := cv.Add(bc.Scale(x)).Add(inPlane.Scale(y)).Add(normal.Scale(z))
+	return [3]float64{d.X, d.Y, d.Z}
+}
+
 // QuaternionFromAxisAngle creates quaternion from axis-angle representation
 //
 // CROSS-DOMAIN: Robotics, aerospace (attitude representation)
@@ -3,6 +3,8 @@ package geometry
 import (
 	"math"
 	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 )
 
 // TestQuaternionFromAxisAngle tests quaternion creation
@@ -201,3 +203,157 @@ func BenchmarkBuildProteinFromAngles(b *testing.B) {
 		_, _ = BuildProteinFromAngles(sequence, angles)
 	}
 }
+
+// TestPlaceAtomTransPeptide checks PlaceAtom against hand-built coordinates
+//
+// With a = (0, 1, 0), b = origin, c = (1.5, 0, 0) the local frame is
+// [+X, +Y, +Z]: trans (180°) puts d below the bond, cis (0°) above it, and
+// +90° out of plane toward +Z.
+func TestPlaceAtomTransPeptide(t *testing.T) {
+	a := [3]float64{0, 1, 0}
+	b := [3]float64{0, 0, 0}
+	c := [3]float64{1.5, 0, 0}
+	deg := math.Pi / 180.0
+
+	cases := []struct {
+		name     string
+		angle    float64
+		dihedral float64
+		want     [3]float64
+	}{
+		{"trans", 90, 180, [3]float64{1.5, -BondC_N, 0}},
+		{"cis", 90, 0, [3]float64{1.5, BondC_N, 0}},
+		{"plus90", 90, 90, [3]float64{1.5, 0, BondC_N}},
+		{"trans117", AngleCA_C_N, 180, [3]float64{
+			1.5 - BondC_N*math.Cos(AngleCA_C_N*deg),
+			-BondC_N * math.Sin(AngleCA_C_N*deg),
+			0,
+		}},
+	}
+
+	for _, tc := range cases {
+		d := PlaceAtom(a, b, c, BondC_N, tc.angle*deg, tc.dihedral*deg)
+		for k := range d {
+			if math.Abs(d[k]-tc.want[k]) > 1e-9 {
+				t.Errorf("%s: got (%.4f, %.4f, %.4f), want (%.4f, %.4f, %.4f)",
+					tc.name, d[0], d[1], d[2], tc.want[0], tc.want[1], tc.want[2])
+				break
+			}
+		}
+
+		toVec := func(p [3]float64) Vector3 { return Vector3{p[0], p[1], p[2]} }
+		dihedral := calculateDihedral(toVec(a), toVec(b), toVec(c), toVec(d)) / deg
+		if math.Abs(math.Remainder(dihedral-tc.dihedral, 360)) > 1e-9 {
+			t.Errorf("%s: measured dihedral %.4f°, want %.4f°", tc.name, dihedral, tc.dihedral)
+		}
+	}
+}
+
+// TestBuildProteinFromAnglesRoundTrip checks angles → coordinates → angles →
+// coordinates reproduces both the angles and the coordinates
+func TestBuildProteinFromAnglesRoundTrip(t *testing.T) {
+	deg := math.Pi / 180.0
+	sequence := "ACDEFGHIKLMN"
+	angles := make([]RamachandranAngles, len(sequence))
+	for i := range angles {
+		switch {
+		case i < 4: // α-helix
+			angles[i] = RamachandranAngles{Phi: -57 * deg, Psi: -47 * deg}
+		case i < 8: // β-strand
+			angles[i] = RamachandranAngles{Phi: -139 * deg, Psi: 135 * deg}
+		case i%2 == 0: // Left-handed helix
+			angles[i] = RamachandranAngles{Phi: 60 * deg, Psi: 45 * deg}
+		default: // Polyproline II
+			angles[i] = RamachandranAngles{Phi: -75 * deg, Psi: 145 * deg}
+		}
+	}
+
+	first, err := BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	measured := CalculateRamachandran(first)
+	for i := range angles {
+		if i > 0 && math.Abs(math.Remainder(measured[i].Phi-angles[i].Phi, 2*math.Pi)) > 1e-9 {
+			t.Errorf("Residue %d: φ %.4f°, want %.4f°", i, measured[i].Phi/deg, angles[i].Phi/deg)
+		}
+		if i < len(angles)-1 && math.Abs(math.Remainder(measured[i].Psi-angles[i].Psi, 2*math.Pi)) > 1e-9 {
+			t.Errorf("Residue %d: ψ %.4f°, want %.4f°", i, measured[i].Psi/deg, angles[i].Psi/deg)
+		}
+	}
+
+	// Rebuild from measured angles; terminal NaNs take the builder defaults
+	for i := range measured {
+		if i == 0 {
+			measured[i].Phi = angles[i].Phi
+		}
+		if i == len(measured)-1 {
+			measured[i].Psi = angles[i].Psi
+		}
+	}
+	second, err := BuildProteinFromAngles(sequence, measured)
+	if err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+
+	maxDev := 0.0
+	for i, res := range first.Residues {
+		for _, pair := range [][2]*parser.Atom{
+			{res.N, second.Residues[i].N}, {res.CA, second.Residues[i].CA},
+			{res.C, second.Residues[i].C}, {res.O, second.Residues[i].O},
+		} {
+			dev := math.Sqrt(math.Pow(pair[0].X-pair[1].X, 2) + math.Pow(pair[0].Y-pair[1].Y, 2) + math.Pow(pair[0].Z-pair[1].Z, 2))
+			maxDev = math.Max(maxDev, dev)
+		}
+	}
+
+	t.Logf("Round-trip max backbone deviation: %.2e Å", maxDev)
+	if maxDev > 1e-6 {
+		t.Errorf("Round trip moved backbone atoms by up to %.2e Å", maxDev)
+	}
+
+	// Ideal geometry is preserved along the chain
+	for i := 1; i < len(first.Residues); i++ {
+		prevC, n := first.Residues[i-1].C, first.Residues[i].N
+		bond := math.Sqrt(math.Pow(n.X-prevC.X, 2) + math.Pow(n.Y-prevC.Y, 2) + math.Pow(n.Z-prevC.Z, 2))
+		if math.Abs(bond-BondC_N) > 1e-9 {
+			t.Errorf("Residue %d: C-N bond %.4f Å, want %.4f Å", i, bond, BondC_N)
+		}
+	}
+}
+
+// TestBuildProteinFromAnglesProlinePhi checks a proline's φ is clamped into
+// its ring window, the nearer bound taken, while a non-proline keeps any φ
+func TestBuildProteinFromAnglesProlinePhi(t *testing.T) {
+	deg := math.Pi / 180.0
+	for _, tc := range []struct{ in, want float64 }{
+		{-63, -63}, {-120, ProlinePhiMin}, {-10, ProlinePhiMax}, {60, ProlinePhiMax}, {170, ProlinePhiMin},
+	} {
+		if got := ConstrainProlinePhi(tc.in*deg) / deg; math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("ConstrainProlinePhi(%.0f°) = %.1f°, want %.1f°", tc.in, got, tc.want)
+		}
+	}
+
+	sequence := "APAPA"
+	angles := make([]RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = RamachandranAngles{Phi: -150 * deg, Psi: 150 * deg}
+	}
+	protein, err := BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	for i, a := range CalculateRamachandran(protein)[1:] {
+		i++
+		phi := a.ToDegressPhi()
+		t.Logf("Residue %d (%c): φ = %.1f°", i+1, sequence[i], phi)
+		want := -150.0
+		if sequence[i] == 'P' {
+			want = ProlinePhiMin
+		}
+		if math.Abs(phi-want) > 0.01 {
+			t.Errorf("Residue %d (%c) φ = %.2f°, want %.2f°", i+1, sequence[i], phi, want)
+		}
+	}
+}
@@ -97,8 +97,8 @@ func AddHydrogens(protein *parser.Protein) error {
 //
 // PHYSICIST: Vector algebra approach
 // 1. Normalize C→N vector
-// 2. Normalize N→CA vector
-// 3. H direction = normalized(C→N + N→CA)
+// 2. Normalize CA→N vector
+// 3. H direction = normalized(C→N + CA→N)
 // 4. H position = N + (H direction × 1.01 Å)
 func addBackboneHydrogen(residue *parser.Residue, resIndex int, protein *parser.Protein) error {
 	// Skip N-terminal residue (no previous C)
@@ -138,11 +138,12 @@ func addBackboneHydrogen(residue *parser.Residue, resIndex int, protein *parser.
 	}
 	cnVec = cnVec.Normalize()
 
-	// Vector from N to CA
+	// Vector from CA to current N (both unit vectors point into N, so their
+	// sum lies on the exterior bisector, away from C and CA)
 	ncaVec := Vector3{
-		X: ca.X - n.X,
-		Y: ca.Y - n.Y,
-		Z: ca.Z - n.Z,
+		X: n.X - ca.X,
+		Y: n.Y - ca.Y,
+		Z: n.Z - ca.Z,
 	}
 	ncaVec = ncaVec.Normalize()
 
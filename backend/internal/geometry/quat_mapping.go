@@ -14,12 +14,6 @@ import (
 	"math"
 )
 
-// Quaternion represents a unit quaternion for 3D rotations
-// Copied from engines/quaternion.go for geometry package independence
-type Quaternion struct {
-	W, X, Y, Z float64
-}
-
 // RamachandranToQuaternion maps (phi, psi) dihedral angles to quaternion space
 //
 // MATHEMATICAL FOUNDATIONS:
@@ -99,89 +93,6 @@ func QuaternionToRamachandran(q Quaternion) (phi, psi float64) {
 	return phi, psi
 }
 
-// Normalize returns a unit quaternion
-//
-// MATHEMATICIAN:
-// Ensures ||q|| = 1 for numerical stability
-// Theoretically unnecessary (construction guarantees unit norm)
-// Practically essential (floating-point errors accumulate)
-func (q Quaternion) Normalize() Quaternion {
-	norm := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
-
-	if norm == 0 {
-		// Degenerate case - return identity quaternion
-		return Quaternion{W: 1.0, X: 0.0, Y: 0.0, Z: 0.0}
-	}
-
-	return Quaternion{
-		W: q.W / norm,
-		X: q.X / norm,
-		Y: q.Y / norm,
-		Z: q.Z / norm,
-	}
-}
-
-// Slerp performs spherical linear interpolation between two quaternions
-//
-// MATHEMATICIAN:
-// Slerp(q1, q2, t) interpolates along great circle on S³ hypersphere
-// - t=0: returns q1
-// - t=1: returns q2
-// - t∈(0,1): shortest path on S³
-//
-// Formula:
-//   slerp(q1, q2, t) = [sin((1-t)Ω) / sin(Ω)] * q1 + [sin(tΩ) / sin(Ω)] * q2
-//   where Ω = arccos(q1 · q2)
-//
-// Properties:
-//   - Constant angular velocity
-//   - Shortest path (geodesic)
-//   - Preserves unit norm
-//
-// PHYSICIST:
-// Smooth interpolation between conformations
-// Energy landscapes are smoother along slerp paths than linear interpolation
-//
-// Citation: Shoemake, K. (1985). "Animating rotation with quaternion curves."
-//
-// Proof of norm preservation: See MATHEMATICAL_FOUNDATIONS.md, Theorem 2
-func (q1 Quaternion) Slerp(q2 Quaternion, t float64) Quaternion {
-	// Compute dot product (cosine of angle between quaternions)
-	dot := q1.W*q2.W + q1.X*q2.X + q1.Y*q2.Y + q1.Z*q2.Z
-
-	// If dot < 0, quaternions are on opposite hemispheres
-	// Take the shorter path by negating q2
-	if dot < 0.0 {
-		q2 = Quaternion{W: -q2.W, X: -q2.X, Y: -q2.Y, Z: -q2.Z}
-		dot = -dot
-	}
-
-	// If quaternions are very close, use linear interpolation (avoid division by ~zero)
-	const threshold = 0.9995
-	if dot > threshold {
-		// Linear interpolation (lerp) for nearby quaternions
-		return Quaternion{
-			W: q1.W + t*(q2.W-q1.W),
-			X: q1.X + t*(q2.X-q1.X),
-			Y: q1.Y + t*(q2.Y-q1.Y),
-			Z: q1.Z + t*(q2.Z-q1.Z),
-		}.Normalize()
-	}
-
-	// Standard slerp formula
-	omega := math.Acos(dot)           // Angle between quaternions
-	sinOmega := math.Sin(omega)       // sin(Ω)
-	a := math.Sin((1-t)*omega) / sinOmega // Coefficient for q1
-	b := math.Sin(t*omega) / sinOmega     // Coefficient for q2
-
-	return Quaternion{
-		W: a*q1.W + b*q2.W,
-		X: a*q1.X + b*q2.X,
-		Y: a*q1.Y + b*q2.Y,
-		Z: a*q1.Z + b*q2.Z,
-	}
-}
-
 // InterpolateConformation smoothly interpolates between two protein conformations
 //
 // BIOCHEMIST:
@@ -0,0 +1,48 @@
+// Package geometry - Uniform quaternion sampling on S³
+//
+// Quaternion-guided search needs target points spread evenly over the unit
+// quaternion hypersphere. Random points clump and leave holes; a
+// deterministic spiral covers S³ with near-constant spacing.
+//
+// MATHEMATICIAN: Super-Fibonacci spirals generalize the golden-angle
+// Fibonacci sphere from S² to S³. Point i (s = i + ½) sits on the Clifford
+// torus of radii (√(s/n), √(1 - s/n)), which makes the volume element
+// uniform, and turns through the angles 2πs/√2 and 2πs/ψ on its two circles,
+// with ψ ≈ 1.5338 the real root of ψ⁴ = ψ + 4. The two irrational turns
+// play the role of the golden angle.
+// ETHICIST: Deterministic: the same n always gives the same points
+//
+// CITATION:
+// Alexa, M. (2022). "Super-Fibonacci spirals: Fast, low-discrepancy sampling
+// of SO(3)." Proc. IEEE/CVF CVPR, 8291-8300.
+package geometry
+
+import "math"
+
+// superFibonacciPsi is the real root of ψ⁴ = ψ + 4
+const superFibonacciPsi = 1.533751168755204288118041
+
+// FibonacciSphereQuaternions returns n approximately uniform unit
+// quaternions on S³ (super-Fibonacci spiral); nil for n <= 0
+func FibonacciSphereQuaternions(n int) []Quaternion {
+	if n <= 0 {
+		return nil
+	}
+
+	quats := make([]Quaternion, n)
+	for i := range quats {
+		s := float64(i) + 0.5
+		r := math.Sqrt(s / float64(n))
+		R := math.Sqrt(1.0 - s/float64(n))
+		alpha := 2.0 * math.Pi * s / math.Sqrt2
+		beta := 2.0 * math.Pi * s / superFibonacciPsi
+
+		quats[i] = Quaternion{
+			W: r * math.Sin(alpha),
+			X: r * math.Cos(alpha),
+			Y: R * math.Sin(beta),
+			Z: R * math.Cos(beta),
+		}
+	}
+	return quats
+}
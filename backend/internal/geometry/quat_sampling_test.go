@@ -0,0 +1,94 @@
+package geometry
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// nearestNeighborAngles returns each point's geodesic angle on S³ to its
+// nearest neighbor
+func nearestNeighborAngles(quats []Quaternion) []float64 {
+	nearest := make([]float64, len(quats))
+	for i, a := range quats {
+		nearest[i] = math.Inf(1)
+		for j, b := range quats {
+			if i == j {
+				continue
+			}
+			dot := a.W*b.W + a.X*b.X + a.Y*b.Y + a.Z*b.Z
+			angle := math.Acos(math.Max(-1, math.Min(dot, 1)))
+			nearest[i] = math.Min(nearest[i], angle)
+		}
+	}
+	return nearest
+}
+
+// coefficientOfVariation returns σ/μ of values
+func coefficientOfVariation(values []float64) float64 {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance/float64(len(values))) / mean
+}
+
+// TestFibonacciSphereQuaternions checks the super-Fibonacci points are unit
+// quaternions spread more evenly over S³ than random ones
+func TestFibonacciSphereQuaternions(t *testing.T) {
+	const n = 500
+	quats := FibonacciSphereQuaternions(n)
+	if len(quats) != n {
+		t.Fatalf("Got %d quaternions, want %d", len(quats), n)
+	}
+	if FibonacciSphereQuaternions(0) != nil {
+		t.Error("n = 0 should give no quaternions")
+	}
+
+	var mean Quaternion
+	for i, q := range quats {
+		norm := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+		if math.Abs(norm-1) > 1e-12 {
+			t.Fatalf("Quaternion %d has norm %.15f", i, norm)
+		}
+		mean.W += q.W / n
+		mean.X += q.X / n
+		mean.Y += q.Y / n
+		mean.Z += q.Z / n
+	}
+	// A uniform distribution on S³ is centered on the origin
+	if m := math.Sqrt(mean.W*mean.W + mean.X*mean.X + mean.Y*mean.Y + mean.Z*mean.Z); m > 0.02 {
+		t.Errorf("Centroid at %.4f from the origin, expected ≈ 0", m)
+	}
+
+	// Uniform sampling of S³ for comparison: normalized 4D Gaussians
+	rng := rand.New(rand.NewSource(42))
+	random := make([]Quaternion, n)
+	for i := range random {
+		random[i] = Quaternion{W: rng.NormFloat64(), X: rng.NormFloat64(), Y: rng.NormFloat64(), Z: rng.NormFloat64()}.Normalize()
+	}
+
+	fibNN := nearestNeighborAngles(quats)
+	randNN := nearestNeighborAngles(random)
+	fibCV := coefficientOfVariation(fibNN)
+	randCV := coefficientOfVariation(randNN)
+	minFib, minRand := math.Inf(1), math.Inf(1)
+	for i := range fibNN {
+		minFib = math.Min(minFib, fibNN[i])
+		minRand = math.Min(minRand, randNN[i])
+	}
+	t.Logf("Nearest-neighbor CV: super-Fibonacci %.3f, random %.3f", fibCV, randCV)
+	t.Logf("Closest pair: super-Fibonacci %.3f rad, random %.3f rad", minFib, minRand)
+
+	if fibCV >= randCV {
+		t.Errorf("Super-Fibonacci nearest-neighbor CV %.3f not below random %.3f", fibCV, randCV)
+	}
+	if minFib <= minRand {
+		t.Errorf("Super-Fibonacci closest pair %.3f rad not wider than random %.3f rad", minFib, minRand)
+	}
+}
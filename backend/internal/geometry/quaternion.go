@@ -0,0 +1,166 @@
+// Package geometry - Quaternion algebra
+//
+// Unit quaternions carry every rotation in the package: peptide-plane
+// placement (QuaternionFromAxisAngle, RotateByQuaternion), the Ramachandran
+// map onto S³ (RamachandranToQuaternion) and the slerp paths between
+// conformations. This file holds the quaternion type and its algebra.
+//
+// MATHEMATICIAN: q = w + xi + yj + zk with the Hamilton product; a unit q
+// rotates v by v' = q v q*, and q and -q are the same rotation (S³ double
+// covers SO(3))
+// PHYSICIST: Rotation matrices built from a unit quaternion are orthonormal
+// with determinant +1 - proper rotations, never reflections
+//
+// CITATION:
+// Shoemake, K. (1985). "Animating rotation with quaternion curves."
+// SIGGRAPH '85: 245-254.
+package geometry
+
+import (
+	"math"
+)
+
+// Quaternion represents a unit quaternion for 3D rotations
+// Copied from engines/quaternion.go for geometry package independence
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// slerpLinearThreshold is the cos Ω above which Slerp interpolates
+// linearly: sin Ω → 0 makes the slerp weights 0/0
+const slerpLinearThreshold = 0.9995
+
+// IdentityQuaternion returns the identity rotation 1 + 0i + 0j + 0k
+func IdentityQuaternion() Quaternion {
+	return Quaternion{W: 1}
+}
+
+// Norm returns ||q||
+func (q Quaternion) Norm() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Dot returns the 4D inner product q · p, the cosine of the angle between
+// unit quaternions on S³
+func (q Quaternion) Dot(p Quaternion) float64 {
+	return q.W*p.W + q.X*p.X + q.Y*p.Y + q.Z*p.Z
+}
+
+// Normalize returns a unit quaternion
+//
+// MATHEMATICIAN:
+// Ensures ||q|| = 1 for numerical stability
+// Theoretically unnecessary (construction guarantees unit norm)
+// Practically essential (floating-point errors accumulate)
+func (q Quaternion) Normalize() Quaternion {
+	norm := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+
+	if norm == 0 {
+		// Degenerate case - return identity quaternion
+		return Quaternion{W: 1.0, X: 0.0, Y: 0.0, Z: 0.0}
+	}
+
+	return Quaternion{
+		W: q.W / norm,
+		X: q.X / norm,
+		Y: q.Y / norm,
+		Z: q.Z / norm,
+	}
+}
+
+// Multiply returns the Hamilton product q·p: the rotation p followed by q
+//
+// MATHEMATICIAN: Associative but not commutative; ||q·p|| = ||q||·||p||
+func (q Quaternion) Multiply(p Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*p.W - q.X*p.X - q.Y*p.Y - q.Z*p.Z,
+		X: q.W*p.X + q.X*p.W + q.Y*p.Z - q.Z*p.Y,
+		Y: q.W*p.Y - q.X*p.Z + q.Y*p.W + q.Z*p.X,
+		Z: q.W*p.Z + q.X*p.Y - q.Y*p.X + q.Z*p.W,
+	}
+}
+
+// Conjugate returns q* = w - xi - yj - zk, the inverse rotation of a unit q
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// ToRotationMatrix returns the 3×3 rotation matrix of q (row-major), so
+// that R·v = v.RotateByQuaternion(q)
+//
+// q is normalized first; a zero quaternion gives the identity.
+func (q Quaternion) ToRotationMatrix() [3][3]float64 {
+	q = q.Normalize()
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return [3][3]float64{
+		{1 - 2*(y*y+z*z), 2 * (x*y - z*w), 2 * (x*z + y*w)},
+		{2 * (x*y + z*w), 1 - 2*(x*x+z*z), 2 * (y*z - x*w)},
+		{2 * (x*z - y*w), 2 * (y*z + x*w), 1 - 2*(x*x+y*y)},
+	}
+}
+
+// Slerp performs spherical linear interpolation between two quaternions
+//
+// MATHEMATICIAN:
+// Slerp(q1, q2, t) interpolates along great circle on S³ hypersphere
+// - t=0: returns q1
+// - t=1: returns q2 (or -q2, the same rotation, see below)
+// - t∈(0,1): shortest path on S³
+//
+// Formula:
+//
+//	slerp(q1, q2, t) = [sin((1-t)Ω) / sin(Ω)] * q1 + [sin(tΩ) / sin(Ω)] * q2
+//	where Ω = arccos(q1 · q2)
+//
+// Edge cases:
+//   - q1 · q2 < 0: q2 is negated so the path takes the short arc
+//   - sin(Ω) → 0 (nearly identical after the flip, which includes
+//     antipodal q2 = -q1): normalized linear interpolation
+//
+// Properties:
+//   - Constant angular velocity
+//   - Shortest path (geodesic)
+//   - Preserves unit norm
+//
+// PHYSICIST:
+// Smooth interpolation between conformations
+// Energy landscapes are smoother along slerp paths than linear interpolation
+//
+// Citation: Shoemake, K. (1985). "Animating rotation with quaternion curves."
+//
+// Proof of norm preservation: See MATHEMATICAL_FOUNDATIONS.md, Theorem 2
+func (q1 Quaternion) Slerp(q2 Quaternion, t float64) Quaternion {
+	// Compute dot product (cosine of angle between quaternions)
+	dot := q1.Dot(q2)
+
+	// If dot < 0, quaternions are on opposite hemispheres
+	// Take the shorter path by negating q2
+	if dot < 0.0 {
+		q2 = Quaternion{W: -q2.W, X: -q2.X, Y: -q2.Y, Z: -q2.Z}
+		dot = -dot
+	}
+
+	// If quaternions are very close, use linear interpolation (avoid division by ~zero)
+	if dot > slerpLinearThreshold {
+		// Linear interpolation (lerp) for nearby quaternions
+		return Quaternion{
+			W: q1.W + t*(q2.W-q1.W),
+			X: q1.X + t*(q2.X-q1.X),
+			Y: q1.Y + t*(q2.Y-q1.Y),
+			Z: q1.Z + t*(q2.Z-q1.Z),
+		}.Normalize()
+	}
+
+	// Standard slerp formula
+	omega := math.Acos(dot)               // Angle between quaternions
+	sinOmega := math.Sin(omega)           // sin(Ω)
+	a := math.Sin((1-t)*omega) / sinOmega // Coefficient for q1
+	b := math.Sin(t*omega) / sinOmega     // Coefficient for q2
+
+	return Quaternion{
+		W: a*q1.W + b*q2.W,
+		X: a*q1.X + b*q2.X,
+		Y: a*q1.Y + b*q2.Y,
+		Z: a*q1.Z + b*q2.Z,
+	}
+}
@@ -0,0 +1,184 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// quaternionsClose reports whether q and p agree componentwise within tol
+func quaternionsClose(q, p Quaternion, tol float64) bool {
+	return math.Abs(q.W-p.W) <= tol && math.Abs(q.X-p.X) <= tol &&
+		math.Abs(q.Y-p.Y) <= tol && math.Abs(q.Z-p.Z) <= tol
+}
+
+func TestQuaternionMultiplyConjugate(t *testing.T) {
+	i := Quaternion{X: 1}
+	j := Quaternion{Y: 1}
+	k := Quaternion{Z: 1}
+
+	// Hamilton: ij = k, ji = -k, i² = -1
+	if got := i.Multiply(j); !quaternionsClose(got, k, 1e-15) {
+		t.Errorf("i·j = %+v, want k", got)
+	}
+	if got := j.Multiply(i); !quaternionsClose(got, Quaternion{Z: -1}, 1e-15) {
+		t.Errorf("j·i = %+v, want -k", got)
+	}
+	if got := i.Multiply(i); !quaternionsClose(got, Quaternion{W: -1}, 1e-15) {
+		t.Errorf("i·i = %+v, want -1", got)
+	}
+
+	q := Quaternion{W: 0.3, X: -0.5, Y: 0.7, Z: 0.2}.Normalize()
+	p := QuaternionFromAxisAngle(Vector3{X: 1, Y: 2, Z: -1}, 1.1)
+
+	// q·q* = 1 for unit q; identity is neutral; norms multiply
+	if got := q.Multiply(q.Conjugate()); !quaternionsClose(got, IdentityQuaternion(), 1e-12) {
+		t.Errorf("q·q* = %+v, want identity", got)
+	}
+	if got := q.Multiply(IdentityQuaternion()); !quaternionsClose(got, q, 0) {
+		t.Errorf("q·1 = %+v, want q", got)
+	}
+	if n := (Quaternion{W: 2}).Multiply(q).Norm(); math.Abs(n-2) > 1e-12 {
+		t.Errorf("||2·q|| = %.15f, want 2", n)
+	}
+
+	// Composition: rotating by q·p is rotating by p, then by q
+	v := Vector3{X: 0.4, Y: -1.3, Z: 2.2}
+	composed := v.RotateByQuaternion(q.Multiply(p))
+	sequential := v.RotateByQuaternion(p).RotateByQuaternion(q)
+	if d := composed.Sub(sequential).Magnitude(); d > 1e-12 {
+		t.Errorf("Rotation by q·p differs from p then q by %.2e", d)
+	}
+
+	// Conjugate undoes the rotation
+	if d := v.RotateByQuaternion(q).RotateByQuaternion(q.Conjugate()).Sub(v).Magnitude(); d > 1e-12 {
+		t.Errorf("Rotation by q then q* moved v by %.2e", d)
+	}
+}
+
+func TestQuaternionToRotationMatrix(t *testing.T) {
+	quaternions := []Quaternion{
+		IdentityQuaternion(),
+		QuaternionFromAxisAngle(Vector3{Z: 1}, math.Pi/2),
+		QuaternionFromAxisAngle(Vector3{X: 1, Y: 1, Z: 1}, 2.5),
+		QuaternionFromAxisAngle(Vector3{X: -0.2, Y: 0.9, Z: 0.1}, math.Pi),
+		{W: 3, X: -1, Y: 2, Z: 0.5}, // Not unit: normalized first
+		RamachandranToQuaternion(-60*math.Pi/180, -45*math.Pi/180),
+	}
+
+	v := Vector3{X: 1.5, Y: -0.25, Z: 0.75}
+	for _, q := range quaternions {
+		r := q.ToRotationMatrix()
+
+		// Orthonormal: RᵀR = I
+		maxError := 0.0
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				dot := r[0][a]*r[0][b] + r[1][a]*r[1][b] + r[2][a]*r[2][b]
+				want := 0.0
+				if a == b {
+					want = 1
+				}
+				maxError = math.Max(maxError, math.Abs(dot-want))
+			}
+		}
+		if maxError > 1e-12 {
+			t.Errorf("q=%+v: RᵀR deviates from I by %.2e", q, maxError)
+		}
+
+		// Proper rotation: det R = +1
+		det := r[0][0]*(r[1][1]*r[2][2]-r[1][2]*r[2][1]) -
+			r[0][1]*(r[1][0]*r[2][2]-r[1][2]*r[2][0]) +
+			r[0][2]*(r[1][0]*r[2][1]-r[1][1]*r[2][0])
+		if math.Abs(det-1) > 1e-12 {
+			t.Errorf("q=%+v: det R = %.15f, want +1", q, det)
+		}
+
+		// Same rotation as RotateByQuaternion
+		rv := Vector3{
+			X: r[0][0]*v.X + r[0][1]*v.Y + r[0][2]*v.Z,
+			Y: r[1][0]*v.X + r[1][1]*v.Y + r[1][2]*v.Z,
+			Z: r[2][0]*v.X + r[2][1]*v.Y + r[2][2]*v.Z,
+		}
+		if d := rv.Sub(v.RotateByQuaternion(q.Normalize())).Magnitude(); d > 1e-12 {
+			t.Errorf("q=%+v: R·v differs from RotateByQuaternion by %.2e", q, d)
+		}
+	}
+
+	// 90° about z takes x to y
+	r := QuaternionFromAxisAngle(Vector3{Z: 1}, math.Pi/2).ToRotationMatrix()
+	if math.Abs(r[1][0]-1) > 1e-12 || math.Abs(r[0][0]) > 1e-12 {
+		t.Errorf("90° about z: first column (%.3f, %.3f, %.3f), want (0, 1, 0)", r[0][0], r[1][0], r[2][0])
+	}
+}
+
+func TestQuaternionSlerpEdgeCases(t *testing.T) {
+	q := QuaternionFromAxisAngle(Vector3{X: 1, Y: -2, Z: 0.5}, 0.8)
+	antipodal := Quaternion{W: -q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+
+	// Antipodal: -q is the same rotation; the short path stays at q
+	for _, tt := range []float64{0, 0.25, 0.5, 1} {
+		s := q.Slerp(antipodal, tt)
+		if math.IsNaN(s.W) || math.Abs(s.Norm()-1) > 1e-12 {
+			t.Fatalf("Antipodal slerp t=%.2f: %+v is not a unit quaternion", tt, s)
+		}
+		if !quaternionsClose(s, q, 1e-12) {
+			t.Errorf("Antipodal slerp t=%.2f: %+v, want q (same rotation as -q)", tt, s)
+		}
+	}
+
+	// Nearly identical: linear fallback, still unit and between the two
+	near := q.Multiply(QuaternionFromAxisAngle(Vector3{Z: 1}, 1e-6))
+	mid := q.Slerp(near, 0.5)
+	if math.IsNaN(mid.W) || math.Abs(mid.Norm()-1) > 1e-12 {
+		t.Fatalf("Near-identical slerp: %+v is not a unit quaternion", mid)
+	}
+	if d := math.Acos(math.Min(1, mid.Dot(q))); d > 1e-6 {
+		t.Errorf("Near-identical slerp midpoint %.2e rad from q, want ≤ 1e-6", d)
+	}
+
+	// Identical: exactly q
+	if s := q.Slerp(q, 0.3); !quaternionsClose(s, q, 1e-15) {
+		t.Errorf("Slerp(q, q) = %+v, want q", s)
+	}
+
+	// Opposite hemispheres: the flip takes the short arc, so the midpoint
+	// is halfway along the smaller angle
+	p := QuaternionFromAxisAngle(Vector3{X: 1, Y: -2, Z: 0.5}, 0.8+2*math.Pi-0.4) // q rotated by -0.4 rad, as -p
+	if q.Dot(p) >= 0 {
+		t.Fatalf("Test setup: q · p = %.3f, want < 0", q.Dot(p))
+	}
+	m := q.Slerp(p, 0.5)
+	want := QuaternionFromAxisAngle(Vector3{X: 1, Y: -2, Z: 0.5}, 0.6)
+	if !quaternionsClose(m, want, 1e-12) {
+		t.Errorf("Short-path slerp midpoint %+v, want %+v", m, want)
+	}
+
+	// Constant angular velocity along the arc
+	a := QuaternionFromAxisAngle(Vector3{Y: 1}, 0.2)
+	b := QuaternionFromAxisAngle(Vector3{Y: 1}, 2.2)
+	for _, tt := range []float64{0.1, 0.5, 0.9} {
+		want := QuaternionFromAxisAngle(Vector3{Y: 1}, 0.2+2*tt)
+		if s := a.Slerp(b, tt); !quaternionsClose(s, want, 1e-12) {
+			t.Errorf("Slerp t=%.1f: %+v, want %+v", tt, s, want)
+		}
+	}
+}
+
+func TestRamachandranQuaternionRoundTrip(t *testing.T) {
+	deg := math.Pi / 180
+	maxError := 0.0
+	for phi := -179.0; phi <= 179; phi += 7 {
+		for psi := -179.0; psi <= 179; psi += 7 {
+			q := RamachandranToQuaternion(phi*deg, psi*deg)
+			if math.Abs(q.Norm()-1) > 1e-12 {
+				t.Fatalf("(%.0f°, %.0f°): ||q|| = %.15f", phi, psi, q.Norm())
+			}
+			gotPhi, gotPsi := QuaternionToRamachandran(q)
+			maxError = math.Max(maxError, math.Max(math.Abs(gotPhi-phi*deg), math.Abs(gotPsi-psi*deg)))
+		}
+	}
+	t.Logf("Round-trip max error: %.2e rad", maxError)
+	if maxError > 1e-12 {
+		t.Errorf("Round-trip error %.2e rad exceeds 1e-12", maxError)
+	}
+}
@@ -93,20 +93,29 @@ func (v Vector3) RotateByQuaternion(q Quaternion) Vector3 {
 
 // RamachandranAngles holds phi and psi dihedral angles for a residue
 type RamachandranAngles struct {
-	Phi float64 // Phi dihedral angle (radians)
-	Psi float64 // Psi dihedral angle (radians)
+	Phi float64 // Phi dihedral angle (radians); NaN if undefined
+	Psi float64 // Psi dihedral angle (radians); NaN if undefined
 }
 
+// MaxPeptideBond is the longest C(i-1)-N(i) distance (Å) still treated as a
+// peptide bond; longer means residues are missing between i-1 and i
+const MaxPeptideBond = 2.0
+
 // CalculateRamachandran computes phi and psi angles for all residues in a protein
 //
 // BIOCHEMIST:
 //   - Phi (φ): C(i-1) - N(i) - Cα(i) - C(i) dihedral angle
 //   - Psi (ψ): N(i) - Cα(i) - C(i) - N(i+1) dihedral angle
 //   - Terminal residues have undefined angles (first has no phi, last has no psi)
+//   - So do angles needing an atom missing from the model (common at chain
+//     ends and in disordered loops) or spanning a chain break
+//     (C(i-1)-N(i) > MaxPeptideBond); see HasPhi and HasPsi
 //
 // PHYSICIST:
 //   - Dihedral angle calculated using atan2 for proper quadrant handling
 //   - Range: [-π, +π] radians (or [-180°, +180°])
+//   - Undefined angles are NaN, never a dihedral through a wrong atom or
+//     through three collinear atoms (no plane, so no angle)
 //
 // Citation: Ramachandran, G. N., et al. (1963). "Stereochemistry of polypeptide chain configurations."
 // J. Mol. Biol. 7.1: 95-99.
@@ -115,34 +124,62 @@ func CalculateRamachandran(protein *parser.Protein) []RamachandranAngles {
 	angles := make([]RamachandranAngles, len(residues))
 
 	for i := range residues {
-		// Phi requires previous residue's C
-		if i > 0 && residues[i-1].HasCompleteBackbone() && residues[i].HasCompleteBackbone() {
-			angles[i].Phi = calculateDihedral(
+		angles[i] = RamachandranAngles{Phi: math.NaN(), Psi: math.NaN()}
+		res := residues[i]
+		if res == nil || res.N == nil || res.CA == nil || res.C == nil {
+			continue // φ and ψ both need N, CA and C
+		}
+
+		// Phi requires previous residue's C, bonded to this N
+		if i > 0 && peptideBonded(residues[i-1], res) {
+			angles[i].Phi = definedDihedral(
 				atomToVector(residues[i-1].C),
-				atomToVector(residues[i].N),
-				atomToVector(residues[i].CA),
-				atomToVector(residues[i].C),
+				atomToVector(res.N),
+				atomToVector(res.CA),
+				atomToVector(res.C),
 			)
-		} else {
-			angles[i].Phi = math.NaN() // Undefined for N-terminal
 		}
 
-		// Psi requires next residue's N
-		if i < len(residues)-1 && residues[i].HasCompleteBackbone() && residues[i+1].HasCompleteBackbone() {
-			angles[i].Psi = calculateDihedral(
-				atomToVector(residues[i].N),
-				atomToVector(residues[i].CA),
-				atomToVector(residues[i].C),
+		// Psi requires next residue's N, bonded to this C
+		if i < len(residues)-1 && peptideBonded(res, residues[i+1]) {
+			angles[i].Psi = definedDihedral(
+				atomToVector(res.N),
+				atomToVector(res.CA),
+				atomToVector(res.C),
 				atomToVector(residues[i+1].N),
 			)
-		} else {
-			angles[i].Psi = math.NaN() // Undefined for C-terminal
 		}
 	}
 
 	return angles
 }
 
+// AtomDihedral returns the a-b-c-d dihedral angle (radians, IUPAC sign)
+func AtomDihedral(a, b, c, d *parser.Atom) float64 {
+	return calculateDihedral(atomToVector(a), atomToVector(b), atomToVector(c), atomToVector(d))
+}
+
+// definedDihedral is calculateDihedral, or NaN when p1-p2-p3 or p2-p3-p4
+// are collinear
+func definedDihedral(p1, p2, p3, p4 Vector3) float64 {
+	const minSine = 1e-6 // sin of the smallest bond angle that spans a plane
+	b1, b2, b3 := p2.Sub(p1), p3.Sub(p2), p4.Sub(p3)
+	if b1.Cross(b2).Magnitude() <= minSine*b1.Magnitude()*b2.Magnitude() ||
+		b2.Cross(b3).Magnitude() <= minSine*b2.Magnitude()*b3.Magnitude() {
+		return math.NaN()
+	}
+	return calculateDihedral(p1, p2, p3, p4)
+}
+
+// peptideBonded reports whether prev's C and next's N are present and within
+// peptide bond distance
+func peptideBonded(prev, next *parser.Residue) bool {
+	if prev == nil || next == nil || prev.C == nil || next.N == nil {
+		return false
+	}
+	return atomToVector(prev.C).Sub(atomToVector(next.N)).Length() <= MaxPeptideBond
+}
+
 // calculateDihedral computes the dihedral angle defined by four points
 //
 // PHYSICIST:
@@ -154,7 +191,8 @@ func CalculateRamachandran(protein *parser.Protein) []RamachandranAngles {
 // Protein Sci. 29.1: 315-329.
 //
 // MATHEMATICIAN:
-// Returns angle in radians [-π, +π]
+// Returns angle in radians [-π, +π], IUPAC sign (positive = clockwise
+// looking from p2 to p3), matching PlaceAtom and physics.torsionAngle
 // atan2 ensures proper quadrant (no ambiguity from acos)
 func calculateDihedral(p1, p2, p3, p4 Vector3) float64 {
 	// Vectors along bonds
@@ -166,15 +204,10 @@ func calculateDihedral(p1, p2, p3, p4 Vector3) float64 {
 	n1 := b1.Cross(b2) // Normal to plane (p1, p2, p3)
 	n2 := b2.Cross(b3) // Normal to plane (p2, p3, p4)
 
-	// Normalize the cross product of b2 (central bond)
-	b2norm := b2.Normalize()
-
-	// Calculate angle using atan2 for proper sign
-	// m1 is the cross product of the two normals projected onto b2
-	m1 := n1.Cross(b2norm)
-
+	// φ = atan2(|b2| b1·n2, n1·n2)
+	// (the previous (n1 × b̂2)·n2 numerator had the opposite sign)
 	x := n1.Dot(n2)
-	y := m1.Dot(n2)
+	y := b2.Magnitude() * b1.Dot(n2)
 
 	return math.Atan2(y, x)
 }
@@ -184,6 +217,17 @@ func atomToVector(atom *parser.Atom) Vector3 {
 	return Vector3{X: atom.X, Y: atom.Y, Z: atom.Z}
 }
 
+// HasPhi reports whether phi is defined (CalculateRamachandran leaves NaN
+// where it is not)
+func (ra RamachandranAngles) HasPhi() bool {
+	return !math.IsNaN(ra.Phi)
+}
+
+// HasPsi reports whether psi is defined
+func (ra RamachandranAngles) HasPsi() bool {
+	return !math.IsNaN(ra.Psi)
+}
+
 // ToDegressPhi converts phi angle from radians to degrees
 func (ra RamachandranAngles) ToDegressPhi() float64 {
 	return ra.Phi * 180.0 / math.Pi
@@ -235,3 +279,40 @@ func (ra RamachandranAngles) IsInAllowedRegion() bool {
 
 	return false
 }
+
+// Proline φ window (degrees)
+//
+// BIOCHEMIST: The pyrrolidine ring closes Cδ back onto the backbone N, so
+// proline φ is locked near -63° ± 15°; anything outside roughly
+// [-90°, -35°] would tear the ring open
+//
+// Citation: MacArthur, M. W., & Thornton, J. M. (1991). "Influence of proline
+// residues on protein conformation." J. Mol. Biol. 218(2): 397-412.
+const (
+	ProlinePhiCenter = -63.0
+	ProlinePhiSigma  = 15.0
+	ProlinePhiMin    = -90.0
+	ProlinePhiMax    = -35.0
+)
+
+// ConstrainProlinePhi clamps a proline φ (radians) into the ring-compatible
+// window [ProlinePhiMin, ProlinePhiMax], moving it to whichever bound is
+// nearer around the circle; NaN stays NaN
+func ConstrainProlinePhi(phi float64) float64 {
+	if math.IsNaN(phi) {
+		return phi
+	}
+	lo, hi := ProlinePhiMin*math.Pi/180.0, ProlinePhiMax*math.Pi/180.0
+	phi = math.Remainder(phi, 2*math.Pi)
+	if phi >= lo && phi <= hi {
+		return phi
+	}
+
+	// Angular distance past each bound, going the short way round
+	toHi := math.Mod(phi-hi+2*math.Pi, 2*math.Pi)
+	toLo := math.Mod(lo-phi+2*math.Pi, 2*math.Pi)
+	if toHi < toLo {
+		return hi
+	}
+	return lo
+}
@@ -0,0 +1,99 @@
+// Package geometry - Secondary-structure-aware initial chains
+//
+// A uniformly extended start (φ = -120°, ψ = +120°) is a β-strand from end
+// to end: for a helical protein every residue starts in the wrong basin and
+// the chain spans ~3.3 Å per residue instead of 1.5. BuildFromSecondaryStructure
+// puts each residue at the idealized (φ, ψ) of its (predicted) secondary
+// structure instead, so helices start as helices.
+//
+// BIOCHEMIST: Coil is not β - unstructured residues populate polyproline II
+// (φ ≈ -75°, ψ ≈ +150°) far more than the fully extended region
+// MATHEMATICIAN: Angles are basin centres; sampling refines from there
+//
+// CITATION:
+// Hovmöller, S., Zhou, T., & Ohlson, T. (2002). "Conformations of amino
+// acids in proteins." Acta Cryst. D58: 768-776.
+// Shi, Z., et al. (2006). "Polyproline II structure in a sequence of seven
+// alanine residues." PNAS 103(24): 9190-9195.
+package geometry
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// SecondaryStructureType is a per-residue secondary structure class
+type SecondaryStructureType int
+
+const (
+	Coil SecondaryStructureType = iota
+	AlphaHelix
+	BetaSheet
+	Turn
+)
+
+func (s SecondaryStructureType) String() string {
+	switch s {
+	case AlphaHelix:
+		return "H" // Helix
+	case BetaSheet:
+		return "E" // Extended (sheet)
+	case Turn:
+		return "T" // Turn
+	case Coil:
+		return "C" // Coil/loop
+	default:
+		return "C"
+	}
+}
+
+// Idealized (φ, ψ) basin centres in degrees
+const (
+	HelixPhi = -60.0 // Right-handed α-helix
+	HelixPsi = -45.0
+	SheetPhi = -120.0 // β-strand
+	SheetPsi = 120.0
+	PPIIPhi  = -75.0 // Polyproline II
+	PPIIPsi  = 150.0
+	TurnPhi  = -90.0 // Bridge region of type I turns
+	TurnPsi  = 0.0
+)
+
+// SecondaryStructureAngles returns the idealized (φ, ψ) of ss in radians:
+// α-helix, β-strand, turn bridge region, and PPII for coil
+func SecondaryStructureAngles(ss SecondaryStructureType) RamachandranAngles {
+	phi, psi := PPIIPhi, PPIIPsi
+	switch ss {
+	case AlphaHelix:
+		phi, psi = HelixPhi, HelixPsi
+	case BetaSheet:
+		phi, psi = SheetPhi, SheetPsi
+	case Turn:
+		phi, psi = TurnPhi, TurnPsi
+	}
+	return RamachandranAngles{Phi: phi * math.Pi / 180, Psi: psi * math.Pi / 180}
+}
+
+// BuildFromSecondaryStructure builds sequence with each residue at the
+// idealized angles of its secondary structure (see SecondaryStructureAngles)
+//
+// Residues beyond len(ss) are coil (PPII). Proline φ is clamped by
+// BuildProteinFromAngles.
+func BuildFromSecondaryStructure(sequence string, ss []SecondaryStructureType) (*parser.Protein, error) {
+	return BuildProteinFromAngles(sequence, SecondaryStructureAnglesFor(len(sequence), ss))
+}
+
+// SecondaryStructureAnglesFor returns the idealized angles of n residues,
+// coil beyond len(ss)
+func SecondaryStructureAnglesFor(n int, ss []SecondaryStructureType) []RamachandranAngles {
+	angles := make([]RamachandranAngles, n)
+	for i := range angles {
+		t := Coil
+		if i < len(ss) {
+			t = ss[i]
+		}
+		angles[i] = SecondaryStructureAngles(t)
+	}
+	return angles
+}
@@ -0,0 +1,93 @@
+package geometry
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// caRadiusOfGyration returns the radius of gyration of the CA atoms (Å)
+func caRadiusOfGyration(protein *parser.Protein) float64 {
+	var cx, cy, cz float64
+	for _, res := range protein.Residues {
+		cx, cy, cz = cx+res.CA.X, cy+res.CA.Y, cz+res.CA.Z
+	}
+	n := float64(len(protein.Residues))
+	cx, cy, cz = cx/n, cy/n, cz/n
+
+	sum := 0.0
+	for _, res := range protein.Residues {
+		dx, dy, dz := res.CA.X-cx, res.CA.Y-cy, res.CA.Z-cz
+		sum += dx*dx + dy*dy + dz*dz
+	}
+	return math.Sqrt(sum / n)
+}
+
+// TestBuildFromSecondaryStructure checks a helical prediction builds far
+// more compact than the uniformly extended start, and coil comes out PPII
+func TestBuildFromSecondaryStructure(t *testing.T) {
+	// Trp-cage: helix 2-9, then coil
+	sequence := "NLYIQWLKDGGPSSGRPPPS"
+	ss := make([]SecondaryStructureType, len(sequence))
+	for i := 1; i <= 8; i++ {
+		ss[i] = AlphaHelix
+	}
+
+	built, err := BuildFromSecondaryStructure(sequence, ss)
+	if err != nil {
+		t.Fatalf("BuildFromSecondaryStructure failed: %v", err)
+	}
+
+	extendedAngles := make([]RamachandranAngles, len(sequence))
+	for i := range extendedAngles {
+		extendedAngles[i] = RamachandranAngles{Phi: -120 * math.Pi / 180, Psi: 120 * math.Pi / 180}
+	}
+	extended, err := BuildProteinFromAngles(sequence, extendedAngles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+
+	allHelix := make([]SecondaryStructureType, 20)
+	for i := range allHelix {
+		allHelix[i] = AlphaHelix
+	}
+	helix, err := BuildFromSecondaryStructure(strings.Repeat("A", 20), allHelix)
+	if err != nil {
+		t.Fatalf("BuildFromSecondaryStructure failed: %v", err)
+	}
+
+	rgBuilt, rgExtended, rgHelix := caRadiusOfGyration(built), caRadiusOfGyration(extended), caRadiusOfGyration(helix)
+	t.Logf("CA Rg: extended %.1f Å, Trp-cage SS %.1f Å, all-helix %.1f Å", rgExtended, rgBuilt, rgHelix)
+
+	if rgHelix > 0.5*rgExtended {
+		t.Errorf("All-helix Rg %.1f Å not well below extended %.1f Å", rgHelix, rgExtended)
+	}
+	if rgBuilt >= rgExtended {
+		t.Errorf("SS-built Rg %.1f Å not below extended %.1f Å", rgBuilt, rgExtended)
+	}
+
+	// Helix and PPII residues have the idealized angles (interior only;
+	// prolines are clamped by the builder)
+	angles := CalculateRamachandran(built)
+	for i := 1; i < len(sequence)-1; i++ {
+		if sequence[i] == 'P' {
+			continue
+		}
+		want := SecondaryStructureAngles(ss[i])
+		if math.Abs(angles[i].Phi-want.Phi) > 1e-3 || math.Abs(angles[i].Psi-want.Psi) > 1e-3 {
+			t.Errorf("Residue %d (%s): (φ, ψ) = (%.1f°, %.1f°), want (%.1f°, %.1f°)", i+1, ss[i],
+				angles[i].Phi*180/math.Pi, angles[i].Psi*180/math.Pi, want.Phi*180/math.Pi, want.Psi*180/math.Pi)
+		}
+	}
+
+	// Residues beyond ss are coil
+	short, err := BuildFromSecondaryStructure("AAAAA", []SecondaryStructureType{AlphaHelix})
+	if err != nil {
+		t.Fatalf("BuildFromSecondaryStructure failed: %v", err)
+	}
+	if got := CalculateRamachandran(short)[2].Psi * 180 / math.Pi; math.Abs(got-PPIIPsi) > 0.1 {
+		t.Errorf("Residue beyond ss: ψ = %.1f°, want PPII %.0f°", got, PPIIPsi)
+	}
+}
@@ -0,0 +1,130 @@
+// Package geometry - Terminal capping
+//
+// BuildProteinFromAngles ends the chain as if it continued: the last residue
+// has a lone carbonyl O and the first residue's N has no hydrogens. Real
+// termini are a carboxylate (C-terminal O + OXT) and an amine, usually
+// protonated (NH3+) at neutral pH. AddTerminalCaps builds those atoms so the
+// electrostatics (see physics partial charges) and H-bond donors see them.
+//
+// BIOCHEMIST: PDB names - OXT for the second carboxylate oxygen, H1/H2/H3
+// for the N-terminal amine hydrogens
+// PHYSICIST: OXT lies in the CA-C-O plane, anti to O (sp2 carboxylate);
+// amine hydrogens are tetrahedral about N (sp3), staggered against C
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Terminal geometry
+const (
+	AngleCA_N_H   = 109.5 // CA-N-H, sp3 amine
+	BondC_OXT     = 1.25  // C-OXT, carboxylate (between C-O and C=O)
+	AngleCA_C_OXT = 117.0 // CA-C-OXT
+)
+
+// GeometryConfig controls optional parts of structure building
+type GeometryConfig struct {
+	// Build OXT on the C-terminus and amine hydrogens on the N-terminus
+	CapTermini bool
+
+	// With CapTermini: NH3+ (true) or neutral NH2 (false)
+	ProtonatedNTerminus bool
+}
+
+// DefaultGeometryConfig returns the BuildProteinFromAngles behavior: bare
+// backbone termini (protonated N-terminus if capping is switched on)
+func DefaultGeometryConfig() GeometryConfig {
+	return GeometryConfig{
+		CapTermini:          false,
+		ProtonatedNTerminus: true,
+	}
+}
+
+// BuildProteinFromAnglesWithConfig is BuildProteinFromAngles with the
+// options in config
+func BuildProteinFromAnglesWithConfig(sequence string, angles []RamachandranAngles, config GeometryConfig) (*parser.Protein, error) {
+	protein, err := BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.CapTermini {
+		if err := AddTerminalCaps(protein, config.ProtonatedNTerminus); err != nil {
+			return nil, fmt.Errorf("failed to cap termini: %w", err)
+		}
+	}
+	return protein, nil
+}
+
+// AddTerminalCaps appends the C-terminal OXT and the N-terminal amine
+// hydrogens (H1, H2, H3 when protonated; H1, H2 otherwise) to protein.Atoms
+//
+// Termini that already carry the atoms are left alone. The new atoms are
+// not stored on the Residue (which holds N, CA, C, O only).
+func AddTerminalCaps(protein *parser.Protein, protonated bool) error {
+	if protein == nil || len(protein.Residues) == 0 {
+		return fmt.Errorf("protein is nil or empty")
+	}
+
+	first := protein.Residues[0]
+	last := protein.Residues[len(protein.Residues)-1]
+	if first == nil || last == nil {
+		return fmt.Errorf("terminal residue is nil")
+	}
+	if !first.HasCompleteBackbone() || !last.HasCompleteBackbone() || last.O == nil {
+		return fmt.Errorf("terminal residues need complete backbones")
+	}
+
+	existing := make(map[string]bool)
+	for _, atom := range protein.Atoms {
+		if (atom.ResSeq == first.SeqNum && atom.ChainID == first.ChainID) ||
+			(atom.ResSeq == last.SeqNum && atom.ChainID == last.ChainID) {
+			existing[fmt.Sprintf("%d:%s", atom.ResSeq, atom.Name)] = true
+		}
+	}
+
+	deg := math.Pi / 180.0
+	addAtom := func(res *parser.Residue, name, element string, pos [3]float64) {
+		if existing[fmt.Sprintf("%d:%s", res.SeqNum, name)] {
+			return
+		}
+		protein.Atoms = append(protein.Atoms, &parser.Atom{
+			Serial:  len(protein.Atoms) + 1,
+			Name:    name,
+			ResName: res.Name,
+			ChainID: res.ChainID,
+			ResSeq:  res.SeqNum,
+			ICode:   res.ICode,
+			X:       pos[0],
+			Y:       pos[1],
+			Z:       pos[2],
+			Element: element,
+		})
+	}
+
+	// C-terminus: OXT anti to O about the CA-C bond
+	oDihedral := calculateDihedral(atomToVector(last.N), atomToVector(last.CA), atomToVector(last.C), atomToVector(last.O))
+	oxt := PlaceAtom(atomArray(last.N), atomArray(last.CA), atomArray(last.C), BondC_OXT, AngleCA_C_OXT*deg, oDihedral+math.Pi)
+	addAtom(last, "OXT", "O", oxt)
+
+	// N-terminus: amine hydrogens staggered about C-CA-N
+	dihedrals := []float64{180, 60, -60}
+	if !protonated {
+		dihedrals = dihedrals[1:] // Lone pair anti to C
+	}
+	for k, dihedral := range dihedrals {
+		h := PlaceAtom(atomArray(first.C), atomArray(first.CA), atomArray(first.N), BondN_H, AngleCA_N_H*deg, dihedral*deg)
+		addAtom(first, fmt.Sprintf("H%d", k+1), "H", h)
+	}
+
+	return nil
+}
+
+// atomArray returns an atom's position in the array form PlaceAtom takes
+func atomArray(atom *parser.Atom) [3]float64 {
+	return [3]float64{atom.X, atom.Y, atom.Z}
+}
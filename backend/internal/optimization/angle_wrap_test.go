@@ -0,0 +1,70 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+)
+
+// TestAngleStepWraps checks that s_k takes the shortest arc across ±π
+func TestAngleStepWraps(t *testing.T) {
+	deg := math.Pi / 180
+	angles := []geometry.RamachandranAngles{
+		{Phi: math.NaN(), Psi: 179 * deg},
+		{Phi: -179 * deg, Psi: 10 * deg},
+	}
+	newAngles := []geometry.RamachandranAngles{
+		{Phi: math.NaN(), Psi: -179 * deg},
+		{Phi: 179 * deg, Psi: 15 * deg},
+	}
+	want := []float64{0, 2 * deg, -2 * deg, 5 * deg}
+	for i, v := range angleStep(angles, newAngles) {
+		if math.Abs(v-want[i]) > 1e-12 {
+			t.Errorf("s[%d] = %.4f°, want %.4f°", i, v/deg, want[i]/deg)
+		}
+	}
+}
+
+// TestLBFGSAcrossAngleBoundary minimizes an octa-Ala started at
+// (φ, ψ) = (-179°, -170°), whose angles cross ±π on the way down: it must converge with no ~2π
+// phantom steps in the L-BFGS memory
+func TestLBFGSAcrossAngleBoundary(t *testing.T) {
+	angles := make([]geometry.RamachandranAngles, 8)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -179 * math.Pi / 180, Psi: -170 * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles("AAAAAAAA", angles)
+	if err != nil {
+		t.Fatalf("Failed to build test protein: %v", err)
+	}
+	start := ExtractDihedrals(protein)
+
+	result, err := MinimizeQuaternionLBFGS(protein, DefaultQuaternionLBFGSConfig())
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+	end := ExtractDihedrals(protein)
+
+	crossed := 0
+	for i := range start {
+		for _, pair := range [][2]float64{{start[i].Phi, end[i].Phi}, {start[i].Psi, end[i].Psi}} {
+			if !math.IsNaN(pair[0]) && math.Abs(pair[1]-pair[0]) > math.Pi {
+				crossed++
+			}
+		}
+	}
+	t.Logf("%d angles crossed ±π; E %.2f → %.2f in %d iterations, largest stored step %.3f rad, %d memory resets (%s)",
+		crossed, result.InitialEnergy, result.FinalEnergy, result.Iterations,
+		result.MaxMemoryStep, result.MemoryResets, result.ConvergenceReason)
+
+	if crossed == 0 {
+		t.Fatalf("No angle crossed ±π; the test does not exercise wrapping")
+	}
+	if !result.Converged || result.FinalEnergy >= result.InitialEnergy {
+		t.Errorf("L-BFGS did not converge downhill: %s", result.ConvergenceReason)
+	}
+	if result.MaxMemoryStep > math.Pi {
+		t.Errorf("Phantom step of %.3f rad stored in the L-BFGS memory", result.MaxMemoryStep)
+	}
+}
@@ -0,0 +1,179 @@
+// Package optimization - Basin Hopping
+//
+// Global optimization by alternating random dihedral kicks with local L-BFGS
+// minimization. The Metropolis test is applied to *minimized* energies, so the
+// walk moves between basins of the landscape rather than over its surface.
+//
+// PHYSICIST: Minimization maps the rugged landscape onto a staircase of basin
+// energies; barriers between basins vanish from the acceptance test
+// MATHEMATICIAN: Markov chain over local minima with Metropolis acceptance
+// BIOCHEMIST: Very effective for small proteins (Trp-cage, villin headpiece)
+// where a handful of basins dominate
+// ETHICIST: Seeded, reproducible, and the best basin is always kept
+//
+// CITATION:
+// Wales, D. J., & Doye, J. P. K. (1997). "Global optimization by basin-hopping and the
+// lowest energy structures of Lennard-Jones clusters containing up to 110 atoms."
+// J. Phys. Chem. A 101(28): 5111-5116.
+package optimization
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// StrategyBasinHopping: perturbation + L-BFGS with Metropolis on minima
+const StrategyBasinHopping OptimizationStrategy = "basin_hopping"
+
+// BasinHoppingConfig holds basin-hopping parameters
+type BasinHoppingConfig struct {
+	NumHops     int     // Number of perturb-minimize-accept cycles
+	PerturbSize float64 // Maximum dihedral kick per angle (radians)
+	Temperature float64 // Metropolis temperature on minimized energies (Kelvin)
+
+	// Local minimizer run after every hop (and once on the start structure);
+	// its Energy, if set, is also the energy hops are accepted on
+	LBFGS QuaternionLBFGSConfig
+
+	// Random seed
+	Seed int64
+
+	// Verbose logging
+	Verbose bool
+}
+
+// DefaultBasinHoppingConfig returns recommended basin-hopping parameters
+func DefaultBasinHoppingConfig() BasinHoppingConfig {
+	lbfgs := DefaultQuaternionLBFGSConfig()
+	lbfgs.MaxIterations = 50 // Each hop only needs to reach its basin floor
+
+	return BasinHoppingConfig{
+		NumHops:     50,    // 50 hops
+		PerturbSize: 0.5,   // ±0.5 rad ≈ ±29° per angle
+		Temperature: 300.0, // Room temperature
+		LBFGS:       lbfgs,
+		Seed:        42,
+		Verbose:     false,
+	}
+}
+
+// BasinHopping performs basin-hopping global optimization
+//
+// ALGORITHM:
+//  1. Minimize the start structure → current basin
+//  2. For each hop:
+//     a. Perturb every defined (φ, ψ) by uniform noise in ±PerturbSize
+//     b. Minimize with quaternion L-BFGS → trial basin
+//     c. Accept if E_trial < E_current, else with P = exp(-ΔE/kT)
+//     d. Record trial as global best if it is the lowest basin so far
+//  3. Copy the global best into protein
+func BasinHopping(protein *parser.Protein, config BasinHoppingConfig) (*OptimizationResult, error) {
+	if protein == nil || len(protein.Residues) == 0 {
+		return nil, fmt.Errorf("protein is nil or empty")
+	}
+	if config.NumHops < 0 {
+		return nil, fmt.Errorf("NumHops must be non-negative, got %d", config.NumHops)
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	// Boltzmann constant: k_B in kcal/(mol·K)
+	const kB = physics.BoltzmannKcal
+
+	result := &OptimizationResult{
+		Strategy:      StrategyBasinHopping,
+		InitialEnergy: evaluateEnergyForProtein(protein, config.LBFGS),
+	}
+	result.FunctionEvaluations = 2 // Start structure and first minimum
+
+	// Descend into the starting basin
+	current := cloneProtein(protein)
+	startMin, err := MinimizeQuaternionLBFGS(current, config.LBFGS)
+	if err != nil {
+		return nil, fmt.Errorf("initial minimization failed: %w", err)
+	}
+	result.FunctionEvaluations += startMin.FunctionEvaluations
+
+	// Re-evaluate: the line search may leave the last trial step in place
+	currentEnergy := evaluateEnergyForProtein(current, config.LBFGS)
+
+	best := cloneProtein(current)
+	bestEnergy := currentEnergy
+	accepted := 0
+
+	if config.Verbose {
+		fmt.Printf("Basin Hopping: Initial energy = %.2f, first minimum = %.2f kcal/mol\n",
+			result.InitialEnergy, currentEnergy)
+	}
+
+	for hop := 0; hop < config.NumHops; hop++ {
+		trial := cloneProtein(current)
+		if err := perturbDihedrals(trial, config.PerturbSize, rng); err != nil {
+			return nil, fmt.Errorf("hop %d: perturbation failed: %w", hop, err)
+		}
+
+		trialMin, err := MinimizeQuaternionLBFGS(trial, config.LBFGS)
+		if err != nil {
+			return nil, fmt.Errorf("hop %d: minimization failed: %w", hop, err)
+		}
+		result.FunctionEvaluations += trialMin.FunctionEvaluations + 1
+		trialEnergy := evaluateEnergyForProtein(trial, config.LBFGS)
+		result.Iterations = hop + 1
+
+		if math.IsNaN(trialEnergy) || math.IsInf(trialEnergy, 0) {
+			continue // Broken geometry: never accept
+		}
+
+		// Metropolis criterion on minimized energies
+		deltaE := trialEnergy - currentEnergy
+		if deltaE < 0 || rng.Float64() < math.Exp(-deltaE/(kB*config.Temperature)) {
+			current = trial
+			currentEnergy = trialEnergy
+			accepted++
+		}
+
+		if trialEnergy < bestEnergy {
+			best = cloneProtein(trial)
+			bestEnergy = trialEnergy
+		}
+
+		if config.Verbose && (hop%10 == 0 || hop == config.NumHops-1) {
+			fmt.Printf("  Hop %3d: E_trial = %10.2f, E_current = %10.2f, E_best = %10.2f\n",
+				hop, trialEnergy, currentEnergy, bestEnergy)
+		}
+	}
+
+	// Global best goes back to the caller
+	copyProteinCoordinates(best, protein)
+
+	result.FinalEnergy = bestEnergy
+	result.EnergyChange = result.InitialEnergy - bestEnergy
+	result.Converged = true
+	result.Reason = fmt.Sprintf("Completed %d hops (%d accepted), best basin %.2f kcal/mol",
+		result.Iterations, accepted, bestEnergy)
+
+	if config.Verbose {
+		fmt.Printf("Basin Hopping Complete: %.2f → %.2f kcal/mol (%s)\n",
+			result.InitialEnergy, result.FinalEnergy, result.Reason)
+	}
+
+	return result, nil
+}
+
+// perturbDihedrals adds uniform noise in ±size (radians) to every defined φ, ψ
+func perturbDihedrals(protein *parser.Protein, size float64, rng *rand.Rand) error {
+	angles := ExtractDihedrals(protein)
+	for i := range angles {
+		if !math.IsNaN(angles[i].Phi) {
+			angles[i].Phi = normalizeAngle(angles[i].Phi + (rng.Float64()*2.0-1.0)*size)
+		}
+		if !math.IsNaN(angles[i].Psi) {
+			angles[i].Psi = normalizeAngle(angles[i].Psi + (rng.Float64()*2.0-1.0)*size)
+		}
+	}
+	return SetDihedrals(protein, angles)
+}
@@ -0,0 +1,42 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// TestQuaternionLBFGSCAModel minimizes an extended hydrophobic chain in the
+// CA model and checks the trace collapses
+func TestQuaternionLBFGSCAModel(t *testing.T) {
+	sequence := "VLIKAVLIGEVLIKAVLIG"
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -120 * math.Pi / 180, Psi: 130 * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Failed to build test peptide: %v", err)
+	}
+	caEnergy := func() float64 { return physics.CalculateCAEnergy(protein, physics.DefaultCAModelConfig()).Total }
+	before := caEnergy()
+
+	config := DefaultQuaternionLBFGSConfig()
+	config.EnergyModel = physics.EnergyModelCA
+	config.MaxIterations = 30
+	result, err := MinimizeQuaternionLBFGS(protein, config)
+	if err != nil {
+		t.Fatalf("MinimizeQuaternionLBFGS failed: %v", err)
+	}
+	after := caEnergy()
+	t.Logf("CA energy %.2f → %.2f kcal/mol in %d iterations (%s)", before, after, result.Iterations, result.ConvergenceReason)
+
+	if math.Abs(result.InitialEnergy-before) > 1e-9 {
+		t.Errorf("Optimizer started from %.4f kcal/mol, CA model gives %.4f", result.InitialEnergy, before)
+	}
+	if after > before-1 {
+		t.Errorf("CA energy %.2f → %.2f: chain did not collapse", before, after)
+	}
+}
@@ -0,0 +1,151 @@
+// Package optimization - Tests for context cancellation of long-running optimizers
+//
+// Each optimizer is started with an effectively unbounded step budget and
+// cancelled mid-run. It must return promptly with ctx.Err() and leave a
+// valid best-so-far structure in the input protein.
+package optimization
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// cancelPromptness is the maximum allowed delay between cancel and return
+const cancelPromptness = 100 * time.Millisecond
+
+func buildCancellationTestProtein(t *testing.T) *parser.Protein {
+	sequence := "AAAAAAAA"
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 120.0 * math.Pi / 180.0}
+	}
+
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Failed to build test protein: %v", err)
+	}
+	return protein
+}
+
+// runCancelled starts run, cancels after delay and returns the time from
+// cancel to return along with run's error
+func runCancelled(delay time.Duration, run func(ctx context.Context) error) (time.Duration, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- run(ctx) }()
+
+	select {
+	case err := <-done:
+		return 0, err // Finished before cancellation
+	case <-time.After(delay):
+	}
+
+	cancelled := time.Now()
+	cancel()
+	err := <-done
+	return time.Since(cancelled), err
+}
+
+func TestSimulatedAnnealingCtxCancel(t *testing.T) {
+	protein := buildCancellationTestProtein(t)
+
+	config := DefaultSimulatedAnnealingConfig()
+	config.NumSteps = math.MaxInt32
+	config.UseLBFGSRefinement = false
+	config.PlateauSteps = 0 // Run until cancelled
+
+	var result *SimulatedAnnealingResult
+	latency, err := runCancelled(50*time.Millisecond, func(ctx context.Context) error {
+		var runErr error
+		result, runErr = SimulatedAnnealingCtx(ctx, protein, config)
+		return runErr
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if latency > cancelPromptness {
+		t.Errorf("Returned %v after cancel, expected < %v", latency, cancelPromptness)
+	}
+	if result == nil {
+		t.Fatal("Expected partial result on cancellation")
+	}
+
+	t.Logf("Cancelled after %d steps in %v: %s", result.Steps, latency, result.Reason)
+
+	if result.BestEnergy > result.InitialEnergy {
+		t.Errorf("Best energy %.2f exceeds initial %.2f", result.BestEnergy, result.InitialEnergy)
+	}
+	if !validateCoordinates(protein, t) {
+		t.Fatal("Best-so-far coordinates invalid")
+	}
+
+	// Input protein holds the best structure
+	energy := evaluateEnergy(protein, LBFGSConfig{VdWCutoff: config.VdWCutoff, ElecCutoff: config.ElecCutoff})
+	if math.Abs(energy-result.BestEnergy) > 1e-6*math.Max(1, math.Abs(energy)) {
+		t.Errorf("Protein energy %.4f does not match best energy %.4f", energy, result.BestEnergy)
+	}
+}
+
+func TestMinimizeQuaternionLBFGSCtxCancel(t *testing.T) {
+	protein := buildCancellationTestProtein(t)
+
+	config := DefaultQuaternionLBFGSConfig()
+	config.MaxIterations = math.MaxInt32
+	config.GradientTol = 0 // Never converge on gradient
+	config.EnergyTol = -1  // Never converge on energy
+
+	var result *QuaternionLBFGSResult
+	latency, err := runCancelled(50*time.Millisecond, func(ctx context.Context) error {
+		var runErr error
+		result, runErr = MinimizeQuaternionLBFGSCtx(ctx, protein, config)
+		return runErr
+	})
+
+	if err == nil && result != nil {
+		t.Skipf("L-BFGS stopped on its own before cancellation: %s", result.ConvergenceReason)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if latency > cancelPromptness {
+		t.Errorf("Returned %v after cancel, expected < %v", latency, cancelPromptness)
+	}
+	if result == nil {
+		t.Fatal("Expected partial result on cancellation")
+	}
+
+	t.Logf("Cancelled after %d iterations in %v: %s", result.Iterations, latency, result.ConvergenceReason)
+
+	if !validateCoordinates(protein, t) {
+		t.Fatal("Best-so-far coordinates invalid")
+	}
+
+	energy := evaluateEnergyForProtein(protein, config)
+	if math.Abs(energy-result.FinalEnergy) > 1e-6*math.Max(1, math.Abs(energy)) {
+		t.Errorf("Protein energy %.4f does not match final energy %.4f", energy, result.FinalEnergy)
+	}
+}
+
+func TestMinimizeQuaternionLBFGSCtxAlreadyCancelled(t *testing.T) {
+	protein := buildCancellationTestProtein(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := MinimizeQuaternionLBFGSCtx(ctx, protein, DefaultQuaternionLBFGSConfig())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if result == nil || result.FinalEnergy != result.InitialEnergy {
+		t.Errorf("Expected untouched partial result, got %+v", result)
+	}
+}
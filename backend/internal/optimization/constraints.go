@@ -15,6 +15,9 @@
 //    - Hydrophobic residues (I, L, V, F, W) bury in protein core
 //    - Hydrophilic residues (K, R, D, E) prefer surface
 //    - Classic "oil drop model" of protein folding
+//    - Collapse term: a half-harmonic pull on each hydrophobic virtual Cβ
+//      beyond the core radius toward the centroid, which drives
+//      ConstraintGuidedRefinement (the neighbour-count burial only scores)
 //    - Citation: Kauzmann (1959), Adv. Protein Chem. 14: 1-63
 //
 // 3. SOFT RAMACHANDRAN CONSTRAINTS
@@ -22,6 +25,11 @@
 //    - Don't enforce strictly (allow some flexibility)
 //    - Penalty for disallowed regions, bonus for favored regions
 //
+// 4. PREDICTED SECONDARY STRUCTURE RESTRAINTS
+//    - Harmonic φ/ψ restraint toward the predicted helix (-60°, -45°) or
+//      sheet (-120°, +120°) basin, weighted by prediction confidence
+//    - Coil residues are left free
+//
 // CROSS-DOMAIN:
 // - Optimization: Penalty/constraint methods (Lagrange multipliers)
 // - Biophysics: Knowledge-based potentials (Rosetta)
@@ -35,10 +43,15 @@
 package optimization
 
 import (
+	"context"
+	"fmt"
 	"math"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
 
 // ConstraintConfig holds constraint parameters
@@ -46,23 +59,42 @@ type ConstraintConfig struct {
 	// Secondary structure propensity weight
 	SecondaryStructureWeight float64 // Default: 1.0 kcal/mol
 
-	// Hydrophobic core weight
-	HydrophobicCoreWeight    float64 // Default: 0.5 kcal/mol
+	// Hydrophobic core weight: scales the burial score and the collapse
+	// term, per unit of Kyte-Doolittle hydrophobicity
+	HydrophobicCoreWeight    float64 // Default: 0.5 kcal/mol (collapse: kcal/(mol·Å²))
 
 	// Ramachandran constraint weight
 	RamachandranWeight       float64 // Default: 2.0 kcal/mol
 
-	// Burial radius (Å) - atoms within this distance are considered buried
+	// Burial radius (Å) - atoms within this distance are considered buried;
+	// also the smallest core radius of the collapse term
 	BurialRadius             float64 // Default: 8.0 Å
+
+	// Predicted secondary structure to restrain toward (nil = no restraint)
+	SecondaryStructure []prediction.SecondaryStructurePrediction
+
+	// Harmonic φ/ψ restraint constant, scaled per residue by confidence
+	SSRestraintWeight float64 // Default: 50.0 kcal/(mol·rad²)
+
+	// Largest φ/ψ change per ConstraintGuidedRefinement step (radians)
+	MaxDihedralStep float64 // Default: 0.1 rad (~6°)
 }
 
 // DefaultConstraintConfig returns recommended parameters
+//
+// The default HydrophobicCoreWeight of 0.5 also drives the hydrophobic
+// collapse term in ConstraintGuidedRefinement, so with the defaults the
+// refinement moves the structure even without a predicted secondary
+// structure (it used to leave it unchanged). Set HydrophobicCoreWeight to 0
+// for the old restraint-only behavior.
 func DefaultConstraintConfig() ConstraintConfig {
 	return ConstraintConfig{
 		SecondaryStructureWeight: 1.0,
 		HydrophobicCoreWeight:    0.5,
 		RamachandranWeight:       2.0,
 		BurialRadius:             8.0,
+		SSRestraintWeight:        50.0,
+		MaxDihedralStep:          0.1,
 	}
 }
 
@@ -78,7 +110,8 @@ func CalculateConstraintEnergy(protein *parser.Protein, config ConstraintConfig)
 
 	// Hydrophobic core energy
 	if config.HydrophobicCoreWeight > 0 {
-		hcEnergy := calculateHydrophobicCoreEnergy(protein, config.BurialRadius)
+		hcEnergy := calculateHydrophobicCoreEnergy(protein, config.BurialRadius) +
+			calculateHydrophobicCollapseEnergy(protein, config.BurialRadius)
 		totalEnergy += config.HydrophobicCoreWeight * hcEnergy
 	}
 
@@ -88,9 +121,70 @@ func CalculateConstraintEnergy(protein *parser.Protein, config ConstraintConfig)
 		totalEnergy += config.RamachandranWeight * ramaEnergy
 	}
 
+	// Predicted secondary structure restraint
+	if config.SSRestraintWeight > 0 && len(config.SecondaryStructure) > 0 {
+		angles := geometry.CalculateRamachandran(protein)
+		restraintEnergy, _ := calculateSSRestraint(angles, config.SecondaryStructure, config.SSRestraintWeight)
+		totalEnergy += restraintEnergy
+	}
+
 	return totalEnergy
 }
 
+// Secondary structure restraint targets (radians)
+var (
+	ssHelixTarget = geometry.RamachandranAngles{Phi: -60.0 * math.Pi / 180.0, Psi: -45.0 * math.Pi / 180.0}
+	ssSheetTarget = geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 120.0 * math.Pi / 180.0}
+)
+
+// calculateSSRestraint returns the predicted-SS restraint energy and its
+// gradient with respect to each residue's (φ, ψ)
+//
+// E = Σ_i k·c_i·[Δφ_i² + Δψ_i²], Δ wrapped to [-π, π], for residues
+// predicted helix or sheet with confidence c_i. Undefined (NaN) angles
+// contribute nothing.
+func calculateSSRestraint(angles []geometry.RamachandranAngles, ssPred []prediction.SecondaryStructurePrediction,
+	weight float64) (float64, []geometry.RamachandranAngles) {
+
+	energy := 0.0
+	gradient := make([]geometry.RamachandranAngles, len(angles))
+
+	for i := range angles {
+		if i >= len(ssPred) {
+			break
+		}
+
+		var target geometry.RamachandranAngles
+		switch ssPred[i].PredictedType {
+		case prediction.AlphaHelix:
+			target = ssHelixTarget
+		case prediction.BetaSheet:
+			target = ssSheetTarget
+		default:
+			continue
+		}
+
+		k := weight * ssPred[i].Confidence
+		if !math.IsNaN(angles[i].Phi) {
+			d := wrapAngle(angles[i].Phi - target.Phi)
+			energy += k * d * d
+			gradient[i].Phi = 2 * k * d
+		}
+		if !math.IsNaN(angles[i].Psi) {
+			d := wrapAngle(angles[i].Psi - target.Psi)
+			energy += k * d * d
+			gradient[i].Psi = 2 * k * d
+		}
+	}
+
+	return energy, gradient
+}
+
+// wrapAngle wraps an angle to [-π, π]
+func wrapAngle(angle float64) float64 {
+	return math.Remainder(angle, 2*math.Pi)
+}
+
 // calculateSecondaryStructureEnergy uses Chou-Fasman propensities
 //
 // CHOU-FASMAN PROPENSITIES:
@@ -249,6 +343,53 @@ func calculateHydrophobicCoreEnergy(protein *parser.Protein, burialRadius float6
 	return totalEnergy
 }
 
+// calculateHydrophobicCollapseEnergy pulls under-buried hydrophobic
+// residues toward the centroid of the structure
+//
+// OIL DROP, AS A FORCE:
+// The neighbour count of calculateHydrophobicCoreEnergy is a step function
+// of the coordinates, so it has no gradient to follow. Burial here is the
+// distance d_i of residue i's virtual Cβ from the centroid of all atoms,
+// which changes smoothly as the chain moves:
+//
+//	E = Σ_i h_i · ½(d_i - R)²   for h_i > 0 and d_i > R
+//
+// h_i is the Kyte-Doolittle hydrophobicity and R the core radius: the
+// empirical Rg of a folded protein of this length (2.2·N^0.38 Å, see
+// validation.ExpectedRadiusOfGyration), but at least burialRadius.
+// Hydrophobic residues inside the core and polar residues cost nothing, so
+// the term collapses an extended chain around its hydrophobics without
+// squeezing a compact one.
+func calculateHydrophobicCollapseEnergy(protein *parser.Protein, burialRadius float64) float64 {
+	if len(protein.Atoms) == 0 {
+		return 0
+	}
+
+	var centroid physics.Vector3
+	for _, atom := range protein.Atoms {
+		centroid = centroid.Add(physics.Vector3{X: atom.X, Y: atom.Y, Z: atom.Z})
+	}
+	centroid = centroid.Mul(1 / float64(len(protein.Atoms)))
+	coreRadius := math.Max(burialRadius, validation.ExpectedRadiusOfGyration(len(protein.Residues)))
+
+	totalEnergy := 0.0
+	for _, res := range protein.Residues {
+		hydrophobicity := getHydrophobicity(res.Name)
+		if hydrophobicity <= 0 {
+			continue
+		}
+		cb, ok := physics.VirtualCB(res)
+		if !ok {
+			continue
+		}
+		if excess := cb.Sub(centroid).Magnitude() - coreRadius; excess > 0 {
+			totalEnergy += hydrophobicity * 0.5 * excess * excess
+		}
+	}
+
+	return totalEnergy
+}
+
 // getHydrophobicity returns hydrophobicity scale for amino acid
 //
 // KYTE-DOOLITTLE HYDROPHOBICITY SCALE:
@@ -344,46 +485,116 @@ func calculateRamachandranEnergy(protein *parser.Protein) float64 {
 
 // ConstraintGuidedRefinement applies constraints during optimization
 //
-// ALGORITHM:
-// 1. Start with current structure
-// 2. For each iteration:
-//    a. Calculate physical energy (bonds, angles, VdW, electrostatics)
-//    b. Calculate constraint energy (secondary structure, hydrophobic core)
-//    c. Total energy = physical + constraint
-//    d. Optimize total energy
+// ALGORITHM (dihedral space, so bonds and angles stay ideal):
+// 1. Extract (φ, ψ) from the current structure
+// 2. For each step:
+//    a. Step down the gradient of the predicted-SS restraint plus the
+//       hydrophobic collapse term, no angle moving more than MaxDihedralStep
+//    b. Rebuild coordinates and evaluate the total energy:
+//       physical (bonds, angles, torsions, VdW, electrostatics) + constraints
+//    c. Accept if the total decreased, otherwise halve the step and retry
+// 3. Stop when both are satisfied or no step lowers the total
+//
+// The physical energy acts as a referee: the restraint pulls residues into
+// their predicted basins, and the collapse term buries hydrophobic residues,
+// only as far as sterics allow. Without a predicted secondary structure and
+// with HydrophobicCoreWeight zero there is no gradient and the structure is
+// left unchanged. The protein is updated in place.
 //
 // This guides structure toward biologically realistic conformations
 func ConstraintGuidedRefinement(protein *parser.Protein, config ConstraintConfig, steps int) error {
-	// Use gentle relaxation with added constraints
-	relaxConfig := DefaultGentleRelaxationConfig()
-	relaxConfig.MaxSteps = steps
-	relaxConfig.StepSize = 0.01
+	return ConstraintGuidedRefinementCtx(context.Background(), protein, config, steps)
+}
+
+// ConstraintGuidedRefinementCtx is ConstraintGuidedRefinement with
+// cancellation
+//
+// ctx is checked at the top of every step. On cancellation the protein holds
+// the last accepted geometry and ctx.Err() is returned.
+func ConstraintGuidedRefinementCtx(ctx context.Context, protein *parser.Protein, config ConstraintConfig, steps int) error {
+	if protein == nil || len(protein.Residues) == 0 {
+		return fmt.Errorf("protein is nil or empty")
+	}
+	useSS := config.SSRestraintWeight > 0 && len(config.SecondaryStructure) > 0
+	useCollapse := config.HydrophobicCoreWeight > 0
+	if !useSS && !useCollapse || steps <= 0 {
+		return nil
+	}
+	collapseEnergy := func(p *parser.Protein) float64 {
+		return config.HydrophobicCoreWeight * calculateHydrophobicCollapseEnergy(p, config.BurialRadius)
+	}
+
+	maxStep := config.MaxDihedralStep
+	if maxStep <= 0 {
+		maxStep = DefaultConstraintConfig().MaxDihedralStep
+	}
+
+	angles := ExtractDihedrals(protein)
+	for i := range angles {
+		// Terminal angles are undefined; the builder needs a value
+		if math.IsNaN(angles[i].Phi) {
+			angles[i].Phi = ssSheetTarget.Phi
+		}
+		if math.IsNaN(angles[i].Psi) {
+			angles[i].Psi = ssSheetTarget.Psi
+		}
+	}
+	if err := SetDihedrals(protein, angles); err != nil {
+		return fmt.Errorf("failed to rebuild from dihedrals: %w", err)
+	}
+	energy := constraintTotalEnergy(protein, config)
 
+	trial := make([]geometry.RamachandranAngles, len(angles))
 	for step := 0; step < steps; step++ {
-		// Calculate forces from physical energy
-		forces := calculateForcesWithConstraints(protein, config, relaxConfig)
-
-		// Apply forces to move atoms
-		moved := false
-		for _, atom := range protein.Atoms {
-			if force, exists := forces[atom.Serial]; exists {
-				displacement := math.Sqrt(force.X*force.X + force.Y*force.Y + force.Z*force.Z)
-
-				if displacement > 1e-6 {
-					scale := relaxConfig.StepSize / displacement
-					if scale > 0.1 {
-						scale = 0.1
-					}
-
-					atom.X += force.X * scale
-					atom.Y += force.Y * scale
-					atom.Z += force.Z * scale
-					moved = true
-				}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		gradient := make([]geometry.RamachandranAngles, len(angles))
+		if useSS {
+			_, gradient = calculateSSRestraint(angles, config.SecondaryStructure, config.SSRestraintWeight)
+		}
+		if useCollapse {
+			// Finite differences: the protein holds angles, and is restored to them
+			collapse := dihedralGradient(protein, angles, collapseGradientDelta, collapseEnergy)
+			for i := range gradient {
+				gradient[i].Phi += collapse[2*i]
+				gradient[i].Psi += collapse[2*i+1]
 			}
 		}
 
-		if !moved {
+		maxGrad := 0.0
+		for _, g := range gradient {
+			maxGrad = math.Max(maxGrad, math.Max(math.Abs(g.Phi), math.Abs(g.Psi)))
+		}
+		if maxGrad < 1e-6 {
+			break // Restraint and collapse satisfied
+		}
+
+		// Backtracking: scale so the largest angle change is maxStep
+		accepted := false
+		for scale := maxStep / maxGrad; scale*maxGrad > 1e-3; scale *= 0.5 {
+			for i := range angles {
+				trial[i].Phi = wrapAngle(angles[i].Phi - scale*gradient[i].Phi)
+				trial[i].Psi = wrapAngle(angles[i].Psi - scale*gradient[i].Psi)
+			}
+			if err := SetDihedrals(protein, trial); err != nil {
+				return fmt.Errorf("failed to rebuild from dihedrals: %w", err)
+			}
+
+			if trialEnergy := constraintTotalEnergy(protein, config); trialEnergy < energy {
+				copy(angles, trial)
+				energy = trialEnergy
+				accepted = true
+				break
+			}
+		}
+
+		if !accepted {
+			// No downhill step: restore the last accepted geometry
+			if err := SetDihedrals(protein, angles); err != nil {
+				return fmt.Errorf("failed to rebuild from dihedrals: %w", err)
+			}
 			break
 		}
 	}
@@ -391,19 +602,16 @@ func ConstraintGuidedRefinement(protein *parser.Protein, config ConstraintConfig
 	return nil
 }
 
-// calculateForcesWithConstraints computes forces including constraint terms
-func calculateForcesWithConstraints(protein *parser.Protein, constraintConfig ConstraintConfig, relaxConfig GentleRelaxationConfig) map[int]Vector3 {
-	// Get physical forces
-	forces := make(map[int]Vector3)
-	for _, atom := range protein.Atoms {
-		forces[atom.Serial] = Vector3{X: 0, Y: 0, Z: 0}
-	}
-
-	// Add bond forces (simple implementation)
-	// For full implementation, would add constraint-based forces
-	// Here we just use gentle relaxation forces as baseline
+// collapseGradientDelta is the finite-difference step of the collapse
+// term's dihedral gradient (radians)
+const collapseGradientDelta = 1e-3
 
-	return forces
+// constraintTotalEnergy returns the uncapped physical energy plus the
+// constraint energy
+func constraintTotalEnergy(protein *parser.Protein, config ConstraintConfig) float64 {
+	e := physics.CalculateTotalEnergyWithConfig(protein, physics.DefaultEnergyConfig())
+	physical := e.Bond + e.Angle + e.Dihedral + e.VanDerWaals + e.Electrostatic + e.CMAP + e.HBond
+	return physical + CalculateConstraintEnergy(protein, config)
 }
 
 // Vector3 for force calculations
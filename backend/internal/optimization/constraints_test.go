@@ -0,0 +1,141 @@
+package optimization
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+func TestConstraintGuidedRefinementHelixPrior(t *testing.T) {
+	sequence := strings.Repeat("AELKA", 3) // 15 residues, helix formers
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 120.0 * math.Pi / 180.0}
+	}
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Failed to build extended chain: %v", err)
+	}
+
+	// Helix predicted everywhere but the termini
+	ssPred := make([]prediction.SecondaryStructurePrediction, len(sequence))
+	for i := range ssPred {
+		ssPred[i] = prediction.SecondaryStructurePrediction{
+			Position:      i,
+			Residue:       string(sequence[i]),
+			PredictedType: prediction.AlphaHelix,
+			Confidence:    0.8,
+		}
+	}
+	ssPred[0].PredictedType = prediction.Coil
+	ssPred[len(ssPred)-1].PredictedType = prediction.Coil
+
+	config := DefaultConstraintConfig()
+	config.SecondaryStructure = ssPred
+
+	energyBefore := CalculateConstraintEnergy(protein, config)
+	if err := ConstraintGuidedRefinement(protein, config, 100); err != nil {
+		t.Fatalf("ConstraintGuidedRefinement failed: %v", err)
+	}
+	energyAfter := CalculateConstraintEnergy(protein, config)
+
+	helical := 0
+	final := ExtractDihedrals(protein)
+	for _, a := range final {
+		if classifySecondaryStructure(a) == "helix" {
+			helical++
+		}
+	}
+
+	t.Logf("Constraint energy: %.2f → %.2f", energyBefore, energyAfter)
+	t.Logf("Helical residues: %d/%d", helical, len(final))
+
+	if energyAfter >= energyBefore {
+		t.Errorf("Constraint energy did not decrease: %.2f → %.2f", energyBefore, energyAfter)
+	}
+	if helical*10 < len(final)*7 {
+		t.Errorf("Only %d/%d residues in the helical basin, want at least 70%%", helical, len(final))
+	}
+}
+
+func TestConstraintGuidedRefinementWithoutPrediction(t *testing.T) {
+	angles := make([]geometry.RamachandranAngles, 8)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 120.0 * math.Pi / 180.0}
+	}
+	protein, err := geometry.BuildProteinFromAngles("AAAAAAAA", angles)
+	if err != nil {
+		t.Fatalf("Failed to build chain: %v", err)
+	}
+	before := protein.Copy()
+
+	config := DefaultConstraintConfig()
+	config.HydrophobicCoreWeight = 0 // No collapse either
+	if err := ConstraintGuidedRefinement(protein, config, 50); err != nil {
+		t.Fatalf("ConstraintGuidedRefinement failed: %v", err)
+	}
+	for i, atom := range protein.Atoms {
+		if atom.X != before.Atoms[i].X || atom.Y != before.Atoms[i].Y || atom.Z != before.Atoms[i].Z {
+			t.Fatalf("Structure changed without a predicted secondary structure (atom %d)", i)
+		}
+	}
+}
+
+// hydrophobicSASA returns the solvent-accessible surface area (Ų) of the
+// residues with positive hydrophobicity
+func hydrophobicSASA(protein *parser.Protein) float64 {
+	total := 0.0
+	for res, area := range physics.ResidueSASA(protein, physics.CalculateSASA(protein, physics.DefaultProbeRadius)) {
+		if getHydrophobicity(res.Name) > 0 {
+			total += area
+		}
+	}
+	return total
+}
+
+// TestConstraintGuidedRefinementHydrophobicCollapse checks the collapse
+// term folds an extended hydrophobic-rich chain around its hydrophobics:
+// without a predicted secondary structure it is the only driving force, and
+// the hydrophobic residues' SASA must fall as the refinement proceeds
+func TestConstraintGuidedRefinementHydrophobicCollapse(t *testing.T) {
+	sequence := "VLIKAVLIGEVLIKAVLIG" // 19 residues, mostly hydrophobic
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 120.0 * math.Pi / 180.0}
+	}
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Failed to build extended chain: %v", err)
+	}
+
+	config := DefaultConstraintConfig()
+	sasa := []float64{hydrophobicSASA(protein)}
+	collapse := []float64{calculateHydrophobicCollapseEnergy(protein, config.BurialRadius)}
+	for round := 0; round < 3; round++ {
+		if err := ConstraintGuidedRefinement(protein, config, 50); err != nil {
+			t.Fatalf("ConstraintGuidedRefinement failed: %v", err)
+		}
+		sasa = append(sasa, hydrophobicSASA(protein))
+		collapse = append(collapse, calculateHydrophobicCollapseEnergy(protein, config.BurialRadius))
+	}
+	t.Logf("Hydrophobic SASA per 50 steps: %.0f Ų", sasa)
+	t.Logf("Collapse energy per 50 steps: %.1f", collapse)
+
+	last := len(sasa) - 1
+	if collapse[last] >= collapse[0] {
+		t.Errorf("Collapse energy did not decrease: %.1f → %.1f", collapse[0], collapse[last])
+	}
+	if sasa[last] >= sasa[0] {
+		t.Errorf("Hydrophobic SASA did not decrease: %.0f → %.0f Ų", sasa[0], sasa[last])
+	}
+	for i := 1; i <= last; i++ {
+		if sasa[i] > sasa[i-1]*1.02 {
+			t.Errorf("Hydrophobic SASA grew from %.0f to %.0f Ų in round %d", sasa[i-1], sasa[i], i)
+		}
+	}
+}
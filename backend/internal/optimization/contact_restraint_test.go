@@ -0,0 +1,61 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// TestLBFGSFoldsTowardContact restrains the ends of an extended strand to
+// the 7 Å contact distance and checks L-BFGS brings them together, which
+// it does not do without the restraint
+func TestLBFGSFoldsTowardContact(t *testing.T) {
+	sequence := "AKLVEGSAKL"
+	build := func() *parser.Protein {
+		angles := make([]geometry.RamachandranAngles, len(sequence))
+		for i := range angles {
+			angles[i] = geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 130.0 * math.Pi / 180.0}
+		}
+		protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+		if err != nil {
+			t.Fatalf("Failed to build test strand: %v", err)
+		}
+		return protein
+	}
+	last := len(sequence) - 1
+	contact := []physics.ContactRestraint{{Residue1: 0, Residue2: last, Target: 7.0, Weight: 1.0}}
+	endToEnd := func(p *parser.Protein) float64 {
+		a, b := p.Residues[0].CA, p.Residues[last].CA
+		return math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+	}
+
+	config := DefaultQuaternionLBFGSConfig()
+	config.MaxIterations = 50
+
+	free := build()
+	start := endToEnd(free)
+	if _, err := MinimizeQuaternionLBFGS(free, config); err != nil {
+		t.Fatalf("Unrestrained L-BFGS failed: %v", err)
+	}
+
+	restrained := build()
+	config.ContactRestraints = contact
+	config.ContactForceConstant = 1.0
+	result, err := MinimizeQuaternionLBFGS(restrained, config)
+	if err != nil {
+		t.Fatalf("Restrained L-BFGS failed: %v", err)
+	}
+
+	t.Logf("CA1-CA%d: start %.2f Å, unrestrained %.2f Å, restrained %.2f Å (%s)",
+		last+1, start, endToEnd(free), endToEnd(restrained), result.ConvergenceReason)
+
+	if d := endToEnd(restrained); math.Abs(d-7.0) > 2.0 {
+		t.Errorf("Restraint left the ends %.2f Å apart (start %.2f Å), want 7 ± 2 Å", d, start)
+	}
+	if endToEnd(restrained) >= endToEnd(free) {
+		t.Errorf("Restrained end-to-end %.2f Å not below unrestrained %.2f Å", endToEnd(restrained), endToEnd(free))
+	}
+}
@@ -178,3 +178,77 @@ func printProteinStats(protein *parser.Protein, t *testing.T) {
 		}
 	}
 }
+
+// TestSetDihedralsMissingAtom removes residue 5's C and checks the φ/ψ that
+// need it are flagged undefined, and that rebuilding keeps the gap: no NaN,
+// the segment after the gap stays where it was, and angles round-trip
+func TestSetDihedralsMissingAtom(t *testing.T) {
+	const gap = 5
+	deg := math.Pi / 180.0
+	angles := make([]geometry.RamachandranAngles, 10)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60 * deg, Psi: -45 * deg}
+	}
+	protein, err := geometry.BuildProteinFromAngles("ACDEFGHIKL", angles)
+	if err != nil {
+		t.Fatalf("Failed to build protein: %v", err)
+	}
+
+	// Delete the C atom, as in a PDB model with an unresolved atom
+	missing := protein.Residues[gap].C
+	protein.Residues[gap].C = nil
+	for i, atom := range protein.Atoms {
+		if atom == missing {
+			protein.Atoms = append(protein.Atoms[:i], protein.Atoms[i+1:]...)
+			break
+		}
+	}
+
+	extracted := ExtractDihedrals(protein)
+	for i, a := range extracted {
+		wantPhi := i > 0 && i != gap && i != gap+1
+		wantPsi := i < len(extracted)-1 && i != gap
+		if a.HasPhi() != wantPhi || a.HasPsi() != wantPsi {
+			t.Errorf("Residue %d: HasPhi %v HasPsi %v, want %v %v", i, a.HasPhi(), a.HasPsi(), wantPhi, wantPsi)
+		}
+	}
+
+	anchor := *protein.Residues[gap+1].CA
+	gapCA := *protein.Residues[gap].CA
+
+	// Change angles on both sides of the gap
+	target := make([]geometry.RamachandranAngles, len(extracted))
+	copy(target, extracted)
+	target[2].Psi = -30 * deg
+	target[8].Phi = -80 * deg
+
+	if err := SetDihedrals(protein, target); err != nil {
+		t.Fatalf("SetDihedrals failed: %v", err)
+	}
+	if !validateCoordinates(protein, t) || !validateBondLengths(protein, t) {
+		t.Fatal("Rebuilt coordinates invalid")
+	}
+
+	if d := distance(&anchor, protein.Residues[gap+1].CA); d > 1e-9 {
+		t.Errorf("Residue after the gap moved %.3g Å", d)
+	}
+	if d := distance(&gapCA, protein.Residues[gap].CA); d > 1e-9 {
+		t.Errorf("Residue without C moved %.3g Å", d)
+	}
+
+	// ψ just before the gap reaches into the next (rigid) segment, so only
+	// angles within a segment round-trip
+	rebuilt := ExtractDihedrals(protein)
+	for i := range target {
+		pairs := [][2]float64{{target[i].Phi, rebuilt[i].Phi}, {target[i].Psi, rebuilt[i].Psi}}
+		if i == gap-1 {
+			pairs = pairs[:1]
+		}
+		for _, pair := range pairs {
+			want, got := pair[0], pair[1]
+			if math.IsNaN(want) != math.IsNaN(got) || (!math.IsNaN(want) && math.Abs(want-got) > 1e-6) {
+				t.Errorf("Residue %d: angle %.4f after rebuild, want %.4f", i, got, want)
+			}
+		}
+	}
+}
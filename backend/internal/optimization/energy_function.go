@@ -0,0 +1,110 @@
+// Package optimization - Pluggable energy functions
+//
+// The optimizers used to call the physics force field directly, so the only
+// way to minimize something else (the CA model, a restraint-only objective,
+// a toy potential in a unit test) was to add yet another config switch.
+// EnergyFunction is the objective as a value: MinimizeQuaternionLBFGS (and
+// BasinHopping through its LBFGS config) and SimulatedAnnealing take one,
+// and fall back to the force field when none is given.
+//
+// MATHEMATICIAN: The dihedral optimizers need ∂E/∂φ_i and ∂E/∂ψ_i. An
+// EnergyFunction may supply them analytically; one that returns nil from
+// Gradient is differentiated by forward finite differences of Energy.
+package optimization
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// EnergyFunction is an objective for the optimizers to minimize
+//
+// Energy scores a structure (kcal/mol for the physics energies; any scale
+// for others, though Metropolis temperatures assume kcal/mol). Gradient
+// returns ∂E/∂φ_i at index 2i and ∂E/∂ψ_i at 2i+1 for the residues of the
+// structure as it stands (dihedrals as ExtractDihedrals measures them, 0 for
+// undefined ones), or nil to have the optimizer differentiate Energy
+// numerically. Both must leave the structure unchanged.
+type EnergyFunction interface {
+	Energy(protein *parser.Protein) float64
+	Gradient(protein *parser.Protein) []float64
+}
+
+// EnergyFunc adapts a plain energy function to EnergyFunction; its
+// Gradient is nil, so optimizers differentiate it numerically
+type EnergyFunc func(*parser.Protein) float64
+
+// Energy calls f
+func (f EnergyFunc) Energy(protein *parser.Protein) float64 {
+	return f(protein)
+}
+
+// Gradient returns nil: f has no analytic gradient
+func (f EnergyFunc) Gradient(*parser.Protein) []float64 {
+	return nil
+}
+
+// PhysicsEnergy is the force-field EnergyFunction the optimizers default to:
+// physics.CalculateTotalEnergyWithConfig with Config (AMBER ff14SB terms,
+// or the CA model, plus any restraints), differentiated in dihedral space
+// by forward finite differences of FiniteDiffDelta radians (0: 0.001)
+type PhysicsEnergy struct {
+	Config          physics.EnergyConfig
+	FiniteDiffDelta float64
+}
+
+// DefaultPhysicsEnergy returns the force field with default settings
+func DefaultPhysicsEnergy() PhysicsEnergy {
+	return PhysicsEnergy{
+		Config:          physics.DefaultEnergyConfig(),
+		FiniteDiffDelta: 0.001,
+	}
+}
+
+// Energy is the total (capped) force-field energy
+func (e PhysicsEnergy) Energy(protein *parser.Protein) float64 {
+	return physics.CalculateTotalEnergyWithConfig(protein, e.Config).Total
+}
+
+// Gradient differentiates Energy with respect to every defined φ and ψ
+//
+// The structure is rebuilt from its measured dihedrals for the probes, then
+// its original coordinates are restored.
+func (e PhysicsEnergy) Gradient(protein *parser.Protein) []float64 {
+	delta := e.FiniteDiffDelta
+	if delta == 0 {
+		delta = 0.001
+	}
+	original := cloneProtein(protein)
+	gradient := dihedralGradient(protein, ExtractDihedrals(protein), delta, e.Energy)
+	copyProteinCoordinates(original, protein)
+	return gradient
+}
+
+// physicsEnergy is the PhysicsEnergy of config's force-field settings
+func (config QuaternionLBFGSConfig) physicsEnergy() PhysicsEnergy {
+	return PhysicsEnergy{Config: config.energyConfig(), FiniteDiffDelta: config.FiniteDiffDelta}
+}
+
+// suppliedGradient returns config.Energy's gradient at protein with the
+// frozen residues' components zeroed, or nil if config uses the force
+// field or the function has no gradient (or one of the wrong length)
+func (config QuaternionLBFGSConfig) suppliedGradient(protein *parser.Protein, numResidues int, frozen []bool) []float64 {
+	if config.Energy == nil {
+		return nil
+	}
+	supplied := config.Energy.Gradient(protein)
+	if len(supplied) != 2*numResidues {
+		return nil
+	}
+	gradient := make([]float64, len(supplied))
+	for i, g := range supplied {
+		if (frozen != nil && frozen[i/2]) || math.IsNaN(g) || math.IsInf(g, 0) {
+			continue
+		}
+		gradient[i] = g
+	}
+	return gradient
+}
@@ -0,0 +1,199 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// dihedralQuadratic is a toy EnergyFunction, Σ (φ_i - φ0_i)² + (ψ_i - ψ0_i)²
+// over the defined dihedrals, with its minimum E = 0 at target
+type dihedralQuadratic struct {
+	target []geometry.RamachandranAngles
+}
+
+func (q dihedralQuadratic) Energy(protein *parser.Protein) float64 {
+	energy := 0.0
+	for _, d := range q.deviations(protein) {
+		energy += d * d
+	}
+	return energy
+}
+
+func (q dihedralQuadratic) Gradient(protein *parser.Protein) []float64 {
+	gradient := q.deviations(protein)
+	for i := range gradient {
+		gradient[i] *= 2
+	}
+	return gradient
+}
+
+// deviations are the wrapped φ and ψ offsets from target (0 if undefined)
+func (q dihedralQuadratic) deviations(protein *parser.Protein) []float64 {
+	angles := ExtractDihedrals(protein)
+	d := make([]float64, 2*len(angles))
+	for i, a := range angles {
+		if a.HasPhi() {
+			d[2*i] = wrapAngle(a.Phi - q.target[i].Phi)
+		}
+		if a.HasPsi() {
+			d[2*i+1] = wrapAngle(a.Psi - q.target[i].Psi)
+		}
+	}
+	return d
+}
+
+// TestQuaternionLBFGSToyEnergy minimizes a quadratic in dihedral space
+// from an extended chain and checks it reaches the known minimum, with
+// the analytic gradient and with a numerically differentiated EnergyFunc
+func TestQuaternionLBFGSToyEnergy(t *testing.T) {
+	const sequence = "AGSVLE"
+	start := make([]geometry.RamachandranAngles, len(sequence))
+	target := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range start {
+		start[i] = geometry.RamachandranAngles{Phi: -2.4, Psi: 2.4}
+		target[i] = geometry.RamachandranAngles{Phi: -1.0 - 0.1*float64(i), Psi: -0.8 + 0.05*float64(i)}
+	}
+	toy := dihedralQuadratic{target: target}
+
+	for _, tc := range []struct {
+		name      string
+		energy    EnergyFunction
+		tolerance float64 // radians
+	}{
+		{"analytic gradient", toy, 1e-4},
+		{"numerical gradient", EnergyFunc(toy.Energy), 1e-2},
+	} {
+		protein, err := geometry.BuildProteinFromAngles(sequence, start)
+		if err != nil {
+			t.Fatalf("BuildProteinFromAngles failed: %v", err)
+		}
+
+		config := DefaultQuaternionLBFGSConfig()
+		config.Energy = tc.energy
+		config.GradientTol = 1e-6
+		config.EnergyTol = 1e-12
+		result, err := MinimizeQuaternionLBFGS(protein, config)
+		if err != nil {
+			t.Fatalf("%s: MinimizeQuaternionLBFGS failed: %v", tc.name, err)
+		}
+		t.Logf("%s: E %.4f → %.2e in %d iterations (%s)",
+			tc.name, result.InitialEnergy, result.FinalEnergy, result.Iterations, result.ConvergenceReason)
+
+		if result.InitialEnergy < 1 {
+			t.Fatalf("%s: initial energy %.4f, start is already near the minimum", tc.name, result.InitialEnergy)
+		}
+		if result.FinalEnergy > tc.tolerance*tc.tolerance*float64(2*len(sequence)) {
+			t.Errorf("%s: final energy %.2e, want ≈ 0", tc.name, result.FinalEnergy)
+		}
+		for i, d := range toy.deviations(protein) {
+			if math.Abs(d) > tc.tolerance {
+				t.Errorf("%s: angle %d off its minimum by %.2e rad", tc.name, i, d)
+			}
+		}
+	}
+
+	// BasinHopping minimizes the same objective through its LBFGS config
+	protein, err := geometry.BuildProteinFromAngles(sequence, start)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	hopping := DefaultBasinHoppingConfig()
+	hopping.NumHops = 3
+	hopping.LBFGS.Energy = toy
+	hopping.LBFGS.GradientTol = 1e-6
+	result, err := BasinHopping(protein, hopping)
+	if err != nil {
+		t.Fatalf("BasinHopping failed: %v", err)
+	}
+	if result.FinalEnergy > 1e-6 {
+		t.Errorf("BasinHopping final toy energy %.2e, want ≈ 0", result.FinalEnergy)
+	}
+}
+
+// TestPhysicsEnergyMatchesDefault checks the PhysicsEnergy of a config
+// scores like the optimizer's default and its Gradient leaves the
+// structure in place
+func TestPhysicsEnergyMatchesDefault(t *testing.T) {
+	angles := make([]geometry.RamachandranAngles, 5)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -57 * math.Pi / 180, Psi: -47 * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles("AKLVE", angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+
+	config := DefaultQuaternionLBFGSConfig()
+	energy := config.physicsEnergy()
+	if got, want := energy.Energy(protein), evaluateEnergyForProtein(protein, config); got != want {
+		t.Errorf("PhysicsEnergy %.6f, optimizer default %.6f", got, want)
+	}
+
+	before := cloneProtein(protein)
+	gradient := energy.Gradient(protein)
+	if len(gradient) != 2*len(angles) {
+		t.Fatalf("Gradient has %d components, want %d", len(gradient), 2*len(angles))
+	}
+	if gradient[0] != 0 || gradient[len(gradient)-1] != 0 {
+		t.Errorf("Undefined terminal φ/ψ have gradient %.3f, %.3f; want 0", gradient[0], gradient[len(gradient)-1])
+	}
+	for i, atom := range protein.Atoms {
+		if b := before.Atoms[i]; atom.X != b.X || atom.Y != b.Y || atom.Z != b.Z {
+			t.Fatalf("Gradient moved atom %d", i)
+		}
+	}
+}
+
+// TestSimulatedAnnealingToyEnergy anneals the dihedral quadratic with L-BFGS
+// refinement on and checks every energy SA reports is that potential's,
+// including those after refinement
+func TestSimulatedAnnealingToyEnergy(t *testing.T) {
+	const sequence = "AGSVLE"
+	start := make([]geometry.RamachandranAngles, len(sequence))
+	target := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range start {
+		start[i] = geometry.RamachandranAngles{Phi: -2.4, Psi: 2.4}
+		target[i] = geometry.RamachandranAngles{Phi: -1.0, Psi: -0.8}
+	}
+	toy := dihedralQuadratic{target: target}
+
+	protein, err := geometry.BuildProteinFromAngles(sequence, start)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+
+	config := DefaultSimulatedAnnealingConfig()
+	config.Energy = toy
+	config.NumSteps = 400
+	config.PlateauSteps = 0
+	config.RefinementThreshold = config.TemperatureInitial * 2 // Refine every 100 steps
+	mismatches := 0
+	config.OnIteration = func(step int, energy float64, snapshot *parser.Protein) bool {
+		if math.Abs(energy-toy.Energy(snapshot)) > 1e-9 {
+			mismatches++
+		}
+		return true
+	}
+
+	result, err := SimulatedAnnealing(protein, config)
+	if err != nil {
+		t.Fatalf("SimulatedAnnealing failed: %v", err)
+	}
+	t.Logf("SA on toy energy: %.4f → %.2e, %d L-BFGS refinements", result.InitialEnergy, result.FinalEnergy, result.LBFGSRefinements)
+
+	if result.LBFGSRefinements == 0 {
+		t.Fatal("No L-BFGS refinement ran")
+	}
+	if mismatches > 0 {
+		t.Errorf("%d per-step energies are not the toy potential's", mismatches)
+	}
+	if got := toy.Energy(protein); math.Abs(got-result.FinalEnergy) > 1e-9 {
+		t.Errorf("FinalEnergy %.6f, toy energy of the returned structure %.6f", result.FinalEnergy, got)
+	}
+	if result.FinalEnergy > 1e-3 {
+		t.Errorf("Final toy energy %.2e, want ≈ 0 after refinement", result.FinalEnergy)
+	}
+}
@@ -0,0 +1,233 @@
+// Package optimization - FIRE minimizer
+//
+// Fast Inertial Relaxation Engine: damped molecular dynamics that steers the
+// velocity toward the force and stops dead whenever it starts climbing.
+// Cartesian L-BFGS extrapolates from its Hessian model and explodes on clashy
+// starting geometries; FIRE only ever takes bounded MD steps, so it is the
+// robust Cartesian refiner.
+//
+// PHYSICIST: Power P = F·v > 0 means the system is running downhill - mix v
+// toward F̂ and grow dt; P ≤ 0 means it overshot - freeze (v = 0), shrink dt
+// MATHEMATICIAN: Velocity Verlet with unit masses; step size adapts through
+// dt alone, with no line search and no curvature model
+// BIOCHEMIST: Converges on the largest per-atom force, the criterion that
+// actually signals a relaxed clash
+// ETHICIST: Deterministic - no random numbers anywhere
+//
+// CITATION:
+// Bitzek, E., Koskinen, P., Gähler, F., Moseler, M., & Gumbsch, P. (2006).
+// "Structural relaxation made simple." Phys. Rev. Lett. 97(17): 170201.
+package optimization
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// StrategyFIRE: Fast Inertial Relaxation Engine (robust Cartesian)
+const StrategyFIRE OptimizationStrategy = "fire"
+
+// FIREConfig holds FIRE minimizer parameters
+type FIREConfig struct {
+	MaxSteps int // Maximum MD steps
+
+	// Converged when the largest per-atom force is below this (kcal/(mol·Å))
+	ForceTolerance float64
+
+	TimeStep        float64 // Initial timestep (unit mass)
+	MaxTimeStep     float64 // Upper bound on the adaptive timestep
+	MaxDisplacement float64 // Largest per-atom move in one step (Å)
+
+	// Adaptive schedule (Bitzek et al. 2006 defaults)
+	NMin       int     // Downhill steps before dt may grow
+	FInc       float64 // dt growth factor
+	FDec       float64 // dt shrink factor on P ≤ 0
+	AlphaStart float64 // Initial velocity mixing parameter
+	FAlpha     float64 // α decay factor
+
+	VdWCutoff  float64 // Van der Waals cutoff (Å)
+	ElecCutoff float64 // Electrostatic cutoff (Å)
+
+	Verbose bool
+}
+
+// DefaultFIREConfig returns recommended FIRE parameters
+func DefaultFIREConfig() FIREConfig {
+	return FIREConfig{
+		MaxSteps:        2000,
+		ForceTolerance:  1.0,  // 1 kcal/(mol·Å)
+		TimeStep:        0.01, // Conservative start for clashy forces
+		MaxTimeStep:     0.05, // Bond stretch (k ≈ 340) is unstable above ~0.07
+		MaxDisplacement: 0.1,  // 0.1 Å per step
+		NMin:            5,
+		FInc:            1.1,
+		FDec:            0.5,
+		AlphaStart:      0.1,
+		FAlpha:          0.99,
+		VdWCutoff:       10.0,
+		ElecCutoff:      12.0,
+		Verbose:         false,
+	}
+}
+
+// MinimizeFIRE relaxes protein in place with the FIRE algorithm
+//
+// ALGORITHM (per step, unit masses):
+//  1. P = F·v
+//  2. P > 0: v ← (1-α)v + α|v|F̂; after NMin such steps, dt ← min(dt·FInc, MaxTimeStep), α ← α·FAlpha
+//     P ≤ 0: v ← 0, dt ← dt·FDec, α ← AlphaStart
+//  3. Velocity Verlet: v ← v + ½dt·F; Δx = dt·v (scaled down so no atom moves
+//     more than MaxDisplacement); x ← x + Δx; recompute F; v ← v + ½dt·F
+//  4. Stop when max_i |F_i| < ForceTolerance
+//
+// Returns an error if the energy becomes non-finite (the coordinates of the
+// last finite step are restored first).
+func MinimizeFIRE(protein *parser.Protein, config FIREConfig) (*OptimizationResult, error) {
+	if protein == nil || len(protein.Atoms) == 0 {
+		return nil, fmt.Errorf("protein is nil or empty")
+	}
+	if config.TimeStep <= 0 || config.MaxTimeStep <= 0 || config.MaxDisplacement <= 0 {
+		return nil, fmt.Errorf("TimeStep, MaxTimeStep and MaxDisplacement must be positive")
+	}
+
+	energyConfig := LBFGSConfig{VdWCutoff: config.VdWCutoff, ElecCutoff: config.ElecCutoff}
+
+	result := &OptimizationResult{
+		Strategy:      StrategyFIRE,
+		InitialEnergy: evaluateEnergy(protein, energyConfig),
+	}
+	result.FunctionEvaluations = 1
+	energy := result.InitialEnergy
+
+	positions := extractPositions(protein)
+	forces := negateVectors(evaluateGradient(protein, energyConfig))
+	velocities := make([]Vector3D, len(positions))
+
+	dt := config.TimeStep
+	alpha := config.AlphaStart
+	downhillSteps := 0
+
+	maxForce := maxVectorNorm(forces)
+	result.FinalMaxForce = maxForce
+
+	if config.Verbose {
+		fmt.Printf("FIRE: Initial energy = %.2f kcal/mol, max |F| = %.4f\n", energy, maxForce)
+	}
+
+	for step := 0; step < config.MaxSteps; step++ {
+		if maxForce < config.ForceTolerance {
+			result.Converged = true
+			result.Reason = fmt.Sprintf("Force converged (max |F| = %.6f < %.6f kcal/(mol·Å))",
+				maxForce, config.ForceTolerance)
+			break
+		}
+		result.Iterations = step + 1
+
+		// Steps 1-2: power test and adaptive schedule
+		power := vectorDot(forces, velocities)
+		if power > 0 {
+			vNorm := vectorNorm(velocities)
+			fNorm := vectorNorm(forces)
+			if fNorm > 0 {
+				for i := range velocities {
+					velocities[i] = velocities[i].Scale(1 - alpha).Add(forces[i].Scale(alpha * vNorm / fNorm))
+				}
+			}
+			downhillSteps++
+			if downhillSteps > config.NMin {
+				dt = math.Min(dt*config.FInc, config.MaxTimeStep)
+				alpha *= config.FAlpha
+			}
+		} else {
+			for i := range velocities {
+				velocities[i] = Vector3D{}
+			}
+			dt *= config.FDec
+			alpha = config.AlphaStart
+			downhillSteps = 0
+		}
+
+		// Step 3: velocity Verlet with a displacement cap
+		displacement := make([]Vector3D, len(positions))
+		for i := range velocities {
+			velocities[i] = velocities[i].Add(forces[i].Scale(0.5 * dt))
+			displacement[i] = velocities[i].Scale(dt)
+		}
+		if maxStep := maxVectorNorm(displacement); maxStep > config.MaxDisplacement {
+			scale := config.MaxDisplacement / maxStep
+			for i := range displacement {
+				displacement[i] = displacement[i].Scale(scale)
+				velocities[i] = velocities[i].Scale(scale)
+			}
+		}
+
+		newPositions := make([]Vector3D, len(positions))
+		for i := range positions {
+			newPositions[i] = positions[i].Add(displacement[i])
+		}
+		applyPositions(protein, newPositions)
+
+		newEnergy := evaluateEnergy(protein, energyConfig)
+		result.FunctionEvaluations++
+		if math.IsNaN(newEnergy) || math.IsInf(newEnergy, 0) {
+			applyPositions(protein, positions)
+			return nil, fmt.Errorf("step %d: energy became non-finite", step)
+		}
+
+		forces = negateVectors(evaluateGradient(protein, energyConfig))
+		for i := range velocities {
+			velocities[i] = velocities[i].Add(forces[i].Scale(0.5 * dt))
+		}
+
+		positions = newPositions
+		energy = newEnergy
+		maxForce = maxVectorNorm(forces)
+
+		if config.Verbose && (step%100 == 0 || step < 5) {
+			fmt.Printf("  Step %4d: E = %.2f, max |F| = %.4f, dt = %.4f, α = %.4f\n",
+				step, energy, maxForce, dt, alpha)
+		}
+	}
+
+	if !result.Converged {
+		if maxForce < config.ForceTolerance {
+			result.Converged = true
+			result.Reason = fmt.Sprintf("Force converged (max |F| = %.6f < %.6f kcal/(mol·Å))",
+				maxForce, config.ForceTolerance)
+		} else {
+			result.Reason = fmt.Sprintf("Maximum steps reached (%d)", config.MaxSteps)
+		}
+	}
+
+	result.FinalEnergy = energy
+	result.EnergyChange = result.InitialEnergy - energy
+	result.FinalMaxForce = maxForce
+
+	if config.Verbose {
+		fmt.Printf("FIRE: Final energy = %.2f kcal/mol, max |F| = %.4f (%s)\n", energy, maxForce, result.Reason)
+	}
+
+	return result, nil
+}
+
+// negateVectors returns -v (forces from a gradient)
+func negateVectors(v []Vector3D) []Vector3D {
+	out := make([]Vector3D, len(v))
+	for i := range v {
+		out[i] = v[i].Scale(-1)
+	}
+	return out
+}
+
+// maxVectorNorm returns the largest |v_i|
+func maxVectorNorm(v []Vector3D) float64 {
+	max := 0.0
+	for _, vec := range v {
+		if n := vec.Norm(); n > max {
+			max = n
+		}
+	}
+	return max
+}
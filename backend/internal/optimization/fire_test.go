@@ -0,0 +1,85 @@
+package optimization
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// clashyPeptide builds a helix and jitters every atom so bonds are strained
+// and neighboring atoms overlap
+func clashyPeptide(t *testing.T) *parser.Protein {
+	sequence := "ACDEFGHIK"
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60.0 * math.Pi / 180.0, Psi: -45.0 * math.Pi / 180.0}
+	}
+
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(7))
+	for _, atom := range protein.Atoms {
+		atom.X += 0.5 * rng.NormFloat64()
+		atom.Y += 0.5 * rng.NormFloat64()
+		atom.Z += 0.5 * rng.NormFloat64()
+	}
+	return protein
+}
+
+// TestMinimizeFIREClashy checks FIRE relaxes a clashy structure below the
+// force tolerance with a finite, decreasing energy
+func TestMinimizeFIREClashy(t *testing.T) {
+	protein := clashyPeptide(t)
+
+	config := DefaultFIREConfig()
+	result, err := MinimizeFIRE(protein, config)
+	if err != nil {
+		t.Fatalf("MinimizeFIRE failed: %v", err)
+	}
+
+	t.Logf("FIRE: %.2f → %.2f kcal/mol in %d steps, max |F| = %.4f (%s)",
+		result.InitialEnergy, result.FinalEnergy, result.Iterations, result.FinalMaxForce, result.Reason)
+
+	if math.IsNaN(result.FinalEnergy) || math.IsInf(result.FinalEnergy, 0) {
+		t.Fatalf("Energy diverged: %v", result.FinalEnergy)
+	}
+	if !result.Converged || result.FinalMaxForce >= config.ForceTolerance {
+		t.Errorf("Not converged: max |F| = %.4f, tolerance %.4f", result.FinalMaxForce, config.ForceTolerance)
+	}
+	if result.FinalEnergy >= result.InitialEnergy {
+		t.Errorf("Energy did not decrease: %.2f → %.2f", result.InitialEnergy, result.FinalEnergy)
+	}
+
+	// Cartesian L-BFGS from the same start blows up (energy hits the cap)
+	lbfgsConfig := DefaultLBFGSConfig()
+	lbfgsConfig.MaxIterations = 200
+	lbfgsResult, err := MinimizeLBFGS(clashyPeptide(t), lbfgsConfig)
+	if err != nil {
+		t.Fatalf("MinimizeLBFGS failed: %v", err)
+	}
+	t.Logf("Cartesian L-BFGS: %.2f → %.2f kcal/mol (%s)",
+		lbfgsResult.InitialEnergy, lbfgsResult.FinalEnergy, lbfgsResult.Reason)
+
+	if result.FinalEnergy >= lbfgsResult.FinalEnergy {
+		t.Errorf("FIRE energy %.2f not below Cartesian L-BFGS %.2f", result.FinalEnergy, lbfgsResult.FinalEnergy)
+	}
+}
+
+// TestMinimizeFIRERejectsBadInput checks nil proteins and non-positive steps
+func TestMinimizeFIRERejectsBadInput(t *testing.T) {
+	if _, err := MinimizeFIRE(nil, DefaultFIREConfig()); err == nil {
+		t.Error("Expected error for nil protein")
+	}
+
+	config := DefaultFIREConfig()
+	config.MaxDisplacement = 0
+	if _, err := MinimizeFIRE(clashyPeptide(t), config); err == nil {
+		t.Error("Expected error for zero MaxDisplacement")
+	}
+}
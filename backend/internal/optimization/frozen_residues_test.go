@@ -0,0 +1,74 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+)
+
+// TestQuaternionLBFGSFrozenResidues freezes the first half of an extended
+// peptide and checks its φ/ψ come back bit-for-bit while the rest move
+// (measured from the rebuilt coordinates, to rounding)
+func TestQuaternionLBFGSFrozenResidues(t *testing.T) {
+	sequence := "AKLVEGAKLV"
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -120 * math.Pi / 180, Psi: 130 * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Failed to build test peptide: %v", err)
+	}
+	before := ExtractDihedrals(protein)
+
+	config := DefaultQuaternionLBFGSConfig()
+	config.MaxIterations = 20
+	config.FrozenResidues = []int{0, 1, 2, 3, 4}
+	result, err := MinimizeQuaternionLBFGS(protein, config)
+	if err != nil {
+		t.Fatalf("MinimizeQuaternionLBFGS failed: %v", err)
+	}
+	t.Logf("E %.2f → %.2f kcal/mol in %d iterations (%s)",
+		result.InitialEnergy, result.FinalEnergy, result.Iterations, result.ConvergenceReason)
+
+	final := result.FinalAngles
+	after := ExtractDihedrals(protein)
+	if len(final) != len(before) {
+		t.Fatalf("%d final angles for %d residues", len(final), len(before))
+	}
+	same := func(a, b float64) bool { return a == b || (math.IsNaN(a) && math.IsNaN(b)) }
+	near := func(a, b float64) bool { return same(a, b) || math.Abs(a-b) < 1e-12 }
+	moved := 0.0
+	for i := range after {
+		if i < 5 {
+			if !same(final[i].Phi, before[i].Phi) || !same(final[i].Psi, before[i].Psi) {
+				t.Errorf("Frozen residue %d moved: (%v, %v) → (%v, %v)",
+					i, before[i].Phi, before[i].Psi, final[i].Phi, final[i].Psi)
+			}
+			if !near(after[i].Phi, before[i].Phi) || !near(after[i].Psi, before[i].Psi) {
+				t.Errorf("Frozen residue %d rebuilt at (%v, %v), want (%v, %v)",
+					i, after[i].Phi, after[i].Psi, before[i].Phi, before[i].Psi)
+			}
+			continue
+		}
+		if !near(after[i].Phi, final[i].Phi) || !near(after[i].Psi, final[i].Psi) {
+			t.Errorf("Residue %d rebuilt at (%v, %v), FinalAngles (%v, %v)",
+				i, after[i].Phi, after[i].Psi, final[i].Phi, final[i].Psi)
+		}
+		for _, d := range []float64{after[i].Phi - before[i].Phi, after[i].Psi - before[i].Psi} {
+			if !math.IsNaN(d) {
+				moved = math.Max(moved, math.Abs(d))
+			}
+		}
+	}
+	t.Logf("Largest change of a movable angle: %.4f rad", moved)
+	if moved < 1e-3 {
+		t.Errorf("Movable residues barely changed (%.2g rad)", moved)
+	}
+
+	config.FrozenResidues = []int{0, 10}
+	if _, err := MinimizeQuaternionLBFGS(protein, config); err == nil {
+		t.Error("Accepted frozen residue 10 of a 10-residue peptide")
+	}
+}
@@ -17,6 +17,7 @@
 package optimization
 
 import (
+	"context"
 	"math"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
@@ -69,6 +70,15 @@ type GentleRelaxationResult struct {
 // - Won't fully optimize (don't care)
 // - WILL remove severe clashes (what we need!)
 func GentleRelax(protein *parser.Protein, config GentleRelaxationConfig) (*GentleRelaxationResult, error) {
+	return GentleRelaxCtx(context.Background(), protein, config)
+}
+
+// GentleRelaxCtx is GentleRelax with cancellation
+//
+// ctx is checked at the top of every step. On cancellation the protein holds
+// the last step's coordinates and the partial result is returned together
+// with ctx.Err().
+func GentleRelaxCtx(ctx context.Context, protein *parser.Protein, config GentleRelaxationConfig) (*GentleRelaxationResult, error) {
 	result := &GentleRelaxationResult{}
 
 	// Calculate initial energy
@@ -77,6 +87,13 @@ func GentleRelax(protein *parser.Protein, config GentleRelaxationConfig) (*Gentl
 	prevEnergy := energyComps.Total
 
 	for step := 0; step < config.MaxSteps; step++ {
+		if err := ctx.Err(); err != nil {
+			result.FinalEnergy = prevEnergy
+			result.Steps = step
+			result.EnergyChange = result.InitialEnergy - result.FinalEnergy
+			return result, err
+		}
+
 		// Calculate forces on all atoms
 		forces := physics.CalculateForces(protein, config.VdWCutoff, config.ElecCutoff)
 
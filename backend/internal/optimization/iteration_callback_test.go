@@ -0,0 +1,116 @@
+package optimization
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestQuaternionLBFGSOnIteration checks the callback sees a descending
+// energy sequence on independent snapshots
+func TestQuaternionLBFGSOnIteration(t *testing.T) {
+	protein := buildBasinHoppingTestPeptide(t)
+
+	energies := make([]float64, 0)
+	frames := make([]*parser.Protein, 0)
+
+	config := DefaultQuaternionLBFGSConfig()
+	config.MaxIterations = 30
+	config.OnIteration = func(iter int, energy float64, snapshot *parser.Protein) bool {
+		if iter != len(energies) {
+			t.Errorf("Callback iteration %d, want %d", iter, len(energies))
+		}
+		if e := evaluateEnergyForProtein(snapshot, config); e != energy {
+			t.Errorf("Iteration %d: reported %.4f, snapshot energy %.4f", iter, energy, e)
+		}
+		energies = append(energies, energy)
+		frames = append(frames, snapshot)
+		return true
+	}
+
+	result, err := MinimizeQuaternionLBFGS(protein, config)
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+
+	if len(energies) == 0 {
+		t.Fatalf("Callback never invoked")
+	}
+	t.Logf("%d frames: %.2f → %.2f kcal/mol (%s)", len(energies), energies[0], energies[len(energies)-1], result.ConvergenceReason)
+
+	// Monotonic-ish: Armijo steps go downhill, line-search fallback steps
+	// near the basin floor may not
+	increases, lowest := 0, energies[0]
+	for i := 1; i < len(energies); i++ {
+		if energies[i] > energies[i-1] {
+			increases++
+		}
+		lowest = math.Min(lowest, energies[i])
+	}
+	if increases > len(energies)/2 {
+		t.Errorf("Energy rose in %d of %d iterations: %v", increases, len(energies)-1, energies)
+	}
+	if lowest >= result.InitialEnergy {
+		t.Errorf("No iteration went below the initial energy %.2f", result.InitialEnergy)
+	}
+	if energies[len(energies)-1] != result.FinalEnergy {
+		t.Errorf("Last callback energy %.4f, final energy %.4f", energies[len(energies)-1], result.FinalEnergy)
+	}
+
+	// Snapshots are clones, not views of the live structure
+	for i, frame := range frames {
+		if frame == protein || frame.Atoms[0] == protein.Atoms[0] {
+			t.Fatalf("Frame %d aliases the optimized protein", i)
+		}
+	}
+}
+
+// TestQuaternionLBFGSOnIterationStop checks returning false halts the run
+func TestQuaternionLBFGSOnIterationStop(t *testing.T) {
+	calls := 0
+	config := DefaultQuaternionLBFGSConfig()
+	config.OnIteration = func(iter int, energy float64, snapshot *parser.Protein) bool {
+		calls++
+		return iter < 2
+	}
+
+	result, err := MinimizeQuaternionLBFGS(buildBasinHoppingTestPeptide(t), config)
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+
+	if calls != 3 || result.Iterations != 3 {
+		t.Errorf("Got %d calls and %d iterations, want 3 of each", calls, result.Iterations)
+	}
+	if !strings.Contains(result.ConvergenceReason, "OnIteration") {
+		t.Errorf("Reason %q does not mention the callback", result.ConvergenceReason)
+	}
+}
+
+// TestSimulatedAnnealingOnIterationStop checks the SA hook sees every step
+// and can halt the run
+func TestSimulatedAnnealingOnIterationStop(t *testing.T) {
+	protein := buildBasinHoppingTestPeptide(t)
+
+	steps := make([]int, 0)
+	config := DefaultSimulatedAnnealingConfig()
+	config.UseLBFGSRefinement = false
+	config.OnIteration = func(step int, energy float64, snapshot *parser.Protein) bool {
+		steps = append(steps, step)
+		return step < 9
+	}
+
+	result, err := SimulatedAnnealing(protein, config)
+	if err != nil {
+		t.Fatalf("Simulated annealing failed: %v", err)
+	}
+
+	if len(steps) != 10 || result.Steps != 10 {
+		t.Errorf("Got %d callbacks and %d steps, want 10 of each", len(steps), result.Steps)
+	}
+	if !strings.Contains(result.Reason, "OnIteration") {
+		t.Errorf("Reason %q does not mention the callback", result.Reason)
+	}
+}
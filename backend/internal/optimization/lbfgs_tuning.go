@@ -315,14 +315,18 @@ func TuneLBFGS(startProtein *parser.Protein, nativeProtein *parser.Protein, conf
 }
 
 // FindBestConfig returns the configuration with lowest final RMSD
+//
+// Equal RMSDs are broken by configuration name (lexically first wins), so
+// the choice does not depend on the order of results.
 func FindBestConfig(results []TuningResult) TuningResult {
 	if len(results) == 0 {
 		return TuningResult{}
 	}
 
 	best := results[0]
-	for _, r := range results {
-		if r.FinalRMSD < best.FinalRMSD {
+	for _, r := range results[1:] {
+		if r.FinalRMSD < best.FinalRMSD ||
+			(r.FinalRMSD == best.FinalRMSD && r.Config.Name < best.Config.Name) {
 			best = r
 		}
 	}
@@ -0,0 +1,26 @@
+package optimization
+
+import "testing"
+
+// TestFindBestConfigTieBreak checks two configurations with identical RMSD
+// resolve to the same one whatever their order
+func TestFindBestConfigTieBreak(t *testing.T) {
+	results := []TuningResult{
+		{Config: LBFGSTuningConfig{Name: "Conservative"}, FinalRMSD: 4.2},
+		{Config: LBFGSTuningConfig{Name: "Aggressive"}, FinalRMSD: 4.2},
+		{Config: LBFGSTuningConfig{Name: "Balanced"}, FinalRMSD: 5.0},
+	}
+
+	for run := 0; run < 10; run++ {
+		// Rotate the order every run
+		k := run % len(results)
+		rotated := append(append([]TuningResult{}, results[k:]...), results[:k]...)
+		if best := FindBestConfig(rotated); best.Config.Name != "Aggressive" {
+			t.Fatalf("Run %d: chose %q (RMSD %.1f), want Aggressive", run, best.Config.Name, best.FinalRMSD)
+		}
+	}
+
+	if best := FindBestConfig(nil); best.Config.Name != "" {
+		t.Errorf("Empty results gave %q", best.Config.Name)
+	}
+}
@@ -0,0 +1,74 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+)
+
+// TestStrongWolfeLineSearch takes one steepest-descent line search near a
+// helical minimum and checks the step satisfies both strong-Wolfe
+// conditions, with the returned gradient matching the structure left behind
+func TestStrongWolfeLineSearch(t *testing.T) {
+	protein := buildBasinHoppingTestPeptide(t)
+	config := DefaultQuaternionLBFGSConfig()
+
+	angles := ExtractDihedrals(protein)
+	energy0 := evaluateEnergyForProtein(protein, config)
+	gradient := computeDihedralGradient(protein, angles, config)
+	direction := make([]float64, len(gradient))
+	for i, g := range gradient {
+		direction[i] = -g
+	}
+	slope0 := vectorDotFloat(gradient, direction)
+
+	alpha, energy, newAngles, newGradient := armijoWolfeLineSearch(protein, angles, direction, gradient, energy0, config)
+	if newGradient == nil {
+		t.Fatalf("Strong-Wolfe search returned no gradient")
+	}
+	slope := vectorDotFloat(newGradient, direction)
+	t.Logf("α = %.4g: E %.3f → %.3f, slope %.3f → %.3f", alpha, energy0, energy, slope0, slope)
+
+	if energy > energy0+config.ArmijoC1*alpha*slope0 {
+		t.Errorf("Armijo violated: E(α) = %.4f > %.4f", energy, energy0+config.ArmijoC1*alpha*slope0)
+	}
+	if math.Abs(slope) > -config.WolfeC2*slope0 {
+		t.Errorf("Curvature violated: |slope| %.4f > %.4f", math.Abs(slope), -config.WolfeC2*slope0)
+	}
+	if e := evaluateEnergyForProtein(protein, config); e != energy {
+		t.Errorf("Protein left at energy %.4f, line search reported %.4f", e, energy)
+	}
+	recomputed := computeDihedralGradient(protein, newAngles, config)
+	for i := range recomputed {
+		if math.Abs(recomputed[i]-newGradient[i]) > 1e-9 {
+			t.Fatalf("Returned gradient[%d] = %.6f, recomputed %.6f", i, newGradient[i], recomputed[i])
+		}
+	}
+}
+
+// TestStrongWolfeConvergesFaster compares L-BFGS iterations with the
+// strong-Wolfe search against Armijo backtracking alone
+func TestStrongWolfeConvergesFaster(t *testing.T) {
+	run := func(c2 float64) *QuaternionLBFGSResult {
+		config := DefaultQuaternionLBFGSConfig()
+		config.WolfeC2 = c2
+		config.GradientTol = 0.1
+		config.EnergyTol = 1e-9
+		config.MaxIterations = 500
+		result, err := MinimizeQuaternionLBFGS(buildBasinHoppingTestPeptide(t), config)
+		if err != nil {
+			t.Fatalf("L-BFGS (c2 = %.1f) failed: %v", c2, err)
+		}
+		t.Logf("c2 = %.1f: %d iterations, E %.3f → %.3f (%s)",
+			c2, result.Iterations, result.InitialEnergy, result.FinalEnergy, result.ConvergenceReason)
+		return result
+	}
+
+	wolfe := run(0.9)
+	armijo := run(0)
+	if !wolfe.Converged {
+		t.Fatalf("Strong-Wolfe L-BFGS did not converge: %s", wolfe.ConvergenceReason)
+	}
+	if wolfe.Iterations >= armijo.Iterations {
+		t.Errorf("Strong-Wolfe took %d iterations, Armijo-only %d", wolfe.Iterations, armijo.Iterations)
+	}
+}
@@ -0,0 +1,164 @@
+// Package optimization - Multi-start dihedral L-BFGS
+//
+// A single L-BFGS descent stops in the basin it starts in. Multi-start runs
+// MinimizeQuaternionLBFGS from several perturbed copies of the input and keeps
+// the lowest minimum. Starts are independent, so they run on a bounded worker
+// pool; results are indexed by start, so the answer does not depend on
+// completion order.
+//
+// PHYSICIST: Start 0 is the unperturbed input, so multi-start is never worse
+// than a single descent
+// MATHEMATICIAN: Start i draws its kicks from rand.NewSource(Seed + i) →
+// deterministic regardless of scheduling
+// ETHICIST: Every start works on a clone; the caller's protein is never mutated
+package optimization
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// MultiStartQuaternionConfig holds multi-start parameters
+type MultiStartQuaternionConfig struct {
+	PerturbSize float64 // Maximum dihedral kick per angle for starts 1..n-1 (radians)
+	Seed        int64   // Base seed; start i uses Seed + i
+	MaxWorkers  int     // Concurrent minimizations (<= 0 uses runtime.NumCPU())
+
+	// Local minimizer run from every start
+	LBFGS QuaternionLBFGSConfig
+}
+
+// DefaultMultiStartQuaternionConfig returns recommended multi-start parameters
+func DefaultMultiStartQuaternionConfig() MultiStartQuaternionConfig {
+	return MultiStartQuaternionConfig{
+		PerturbSize: 0.5, // ±0.5 rad ≈ ±29° per angle
+		Seed:        42,
+		MaxWorkers:  runtime.NumCPU(),
+		LBFGS:       DefaultQuaternionLBFGSConfig(),
+	}
+}
+
+// MultiStartQuaternionResult holds the best start and the spread over all starts
+type MultiStartQuaternionResult struct {
+	Best       *parser.Protein        // Lowest-energy minimized clone
+	BestStart  int                    // Index of the best start
+	BestResult *QuaternionLBFGSResult // L-BFGS result of the best start
+	Energies   []float64              // Final energy per start (NaN if the start failed)
+}
+
+// MultiStartQuaternionLBFGS minimizes numStarts perturbed clones of protein
+// concurrently and returns the lowest-energy result
+//
+// Start 0 minimizes an unperturbed clone; start i > 0 adds uniform noise in
+// ±PerturbSize to every defined (φ, ψ) first. Energies are re-evaluated on
+// the final structures, as in BasinHopping.
+func MultiStartQuaternionLBFGS(protein *parser.Protein, config MultiStartQuaternionConfig, numStarts int) (*MultiStartQuaternionResult, error) {
+	if protein == nil || len(protein.Residues) == 0 {
+		return nil, fmt.Errorf("protein is nil or empty")
+	}
+	if numStarts < 1 {
+		return nil, fmt.Errorf("numStarts must be at least 1, got %d", numStarts)
+	}
+
+	workers := config.MaxWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > numStarts {
+		workers = numStarts
+	}
+
+	// Clone serially: workers never read the caller's protein
+	starts := make([]*parser.Protein, numStarts)
+	for i := range starts {
+		starts[i] = cloneProtein(protein)
+	}
+
+	outcomes := make([]multiStartOutcome, numStarts)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Each index is written by exactly one worker
+				outcomes[i] = minimizeStart(starts[i], i, config)
+			}
+		}()
+	}
+	for i := 0; i < numStarts; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := &MultiStartQuaternionResult{
+		BestStart: -1,
+		Energies:  make([]float64, numStarts),
+	}
+	bestEnergy := math.Inf(1)
+	var firstErr error
+
+	for i, o := range outcomes {
+		if o.err != nil {
+			result.Energies[i] = math.NaN()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("start %d: %w", i, o.err)
+			}
+			continue
+		}
+		result.Energies[i] = o.energy
+		if o.energy < bestEnergy {
+			bestEnergy = o.energy
+			result.BestStart = i
+		}
+	}
+
+	if result.BestStart < 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no start produced a finite energy")
+		}
+		return nil, fmt.Errorf("all %d starts failed: %w", numStarts, firstErr)
+	}
+
+	result.Best = starts[result.BestStart]
+	result.BestResult = outcomes[result.BestStart].result
+
+	return result, nil
+}
+
+// multiStartOutcome is the minimization outcome of one start
+type multiStartOutcome struct {
+	result *QuaternionLBFGSResult
+	energy float64
+	err    error
+}
+
+// minimizeStart perturbs (unless it is start 0) and minimizes one clone
+func minimizeStart(structure *parser.Protein, index int, config MultiStartQuaternionConfig) (out multiStartOutcome) {
+	if index > 0 {
+		rng := rand.New(rand.NewSource(config.Seed + int64(index)))
+		if out.err = perturbDihedrals(structure, config.PerturbSize, rng); out.err != nil {
+			return out
+		}
+	}
+
+	out.result, out.err = MinimizeQuaternionLBFGS(structure, config.LBFGS)
+	if out.err != nil {
+		return out
+	}
+
+	// Re-evaluate: the line search may leave the last trial step in place
+	out.energy = evaluateEnergyForProtein(structure, config.LBFGS)
+	if math.IsNaN(out.energy) || math.IsInf(out.energy, 0) {
+		out.err = fmt.Errorf("non-finite final energy")
+	}
+	return out
+}
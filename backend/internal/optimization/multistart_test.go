@@ -0,0 +1,79 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMultiStartQuaternionLBFGS checks multi-start never loses to one descent
+// and leaves the input untouched
+func TestMultiStartQuaternionLBFGS(t *testing.T) {
+	config := DefaultMultiStartQuaternionConfig()
+	config.LBFGS.MaxIterations = 50
+
+	single := buildBasinHoppingTestPeptide(t)
+	singleResult, err := MinimizeQuaternionLBFGS(single, config.LBFGS)
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+	singleEnergy := evaluateEnergyForProtein(single, config.LBFGS)
+
+	protein := buildBasinHoppingTestPeptide(t)
+	before := cloneProtein(protein)
+
+	result, err := MultiStartQuaternionLBFGS(protein, config, 8)
+	if err != nil {
+		t.Fatalf("Multi-start failed: %v", err)
+	}
+
+	t.Logf("Single L-BFGS: %.2f → %.2f kcal/mol", singleResult.InitialEnergy, singleEnergy)
+	t.Logf("Multi-start:   best %.2f kcal/mol from start %d", result.Energies[result.BestStart], result.BestStart)
+	for i, e := range result.Energies {
+		t.Logf("  Start %d: %.2f kcal/mol", i, e)
+	}
+
+	if result.Energies[result.BestStart] > singleEnergy+1e-9 {
+		t.Errorf("Multi-start (%.4f) worse than single start (%.4f)", result.Energies[result.BestStart], singleEnergy)
+	}
+
+	// Reported energy belongs to the returned structure
+	if e := evaluateEnergyForProtein(result.Best, config.LBFGS); math.Abs(e-result.Energies[result.BestStart]) > 1e-9 {
+		t.Errorf("Best structure energy %.4f does not match reported %.4f", e, result.Energies[result.BestStart])
+	}
+
+	// Input must be untouched
+	if result.Best == protein {
+		t.Errorf("Best structure aliases the input protein")
+	}
+	for i, atom := range protein.Atoms {
+		ref := before.Atoms[i]
+		if atom.X != ref.X || atom.Y != ref.Y || atom.Z != ref.Z {
+			t.Fatalf("Input atom %d moved: (%.3f, %.3f, %.3f) → (%.3f, %.3f, %.3f)",
+				i, ref.X, ref.Y, ref.Z, atom.X, atom.Y, atom.Z)
+		}
+	}
+}
+
+// TestMultiStartQuaternionLBFGSDeterministic checks worker count does not change results
+func TestMultiStartQuaternionLBFGSDeterministic(t *testing.T) {
+	config := DefaultMultiStartQuaternionConfig()
+	config.LBFGS.MaxIterations = 20
+
+	config.MaxWorkers = 1
+	serial, err := MultiStartQuaternionLBFGS(buildBasinHoppingTestPeptide(t), config, 4)
+	if err != nil {
+		t.Fatalf("Multi-start failed: %v", err)
+	}
+
+	config.MaxWorkers = 4
+	parallel, err := MultiStartQuaternionLBFGS(buildBasinHoppingTestPeptide(t), config, 4)
+	if err != nil {
+		t.Fatalf("Multi-start failed: %v", err)
+	}
+
+	for i := range serial.Energies {
+		if serial.Energies[i] != parallel.Energies[i] {
+			t.Errorf("Start %d: serial %.6f vs parallel %.6f", i, serial.Energies[i], parallel.Energies[i])
+		}
+	}
+}
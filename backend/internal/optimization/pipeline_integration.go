@@ -89,6 +89,9 @@ type OptimizationResult struct {
 	Converged           bool
 	Reason              string
 
+	// Largest per-atom force at exit (FIRE only, kcal/(mol·Å))
+	FinalMaxForce float64
+
 	// Strategy-specific results
 	LBFGSResult *LBFGSResult
 	SAResult    *SimulatedAnnealingResult
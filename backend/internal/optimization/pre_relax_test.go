@@ -0,0 +1,62 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// buildClashedDecapeptide builds poly-Ala with every (φ, ψ) = (0°, 0°): the
+// chain folds back onto itself and its energy sits at the cap
+func buildClashedDecapeptide(t *testing.T) *parser.Protein {
+	angles := make([]geometry.RamachandranAngles, 10)
+	protein, err := geometry.BuildProteinFromAngles("AAAAAAAAAA", angles)
+	if err != nil {
+		t.Fatalf("Failed to build test protein: %v", err)
+	}
+	return protein
+}
+
+// TestPreRelaxation checks that steepest-descent pre-relaxation brings a
+// clashed start's gradient norm below PreRelaxGradientNorm, and that L-BFGS
+// then converges where, from the capped start, it aborts
+func TestPreRelaxation(t *testing.T) {
+	config := DefaultQuaternionLBFGSConfig()
+	if e := evaluateEnergyForProtein(buildClashedDecapeptide(t), config); e < physics.TotalEnergyCap {
+		t.Fatalf("Test structure energy %.1f kcal/mol is not at the cap", e)
+	}
+
+	plain, err := MinimizeQuaternionLBFGS(buildClashedDecapeptide(t), config)
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+	t.Logf("Without pre-relaxation: E %.1f → %.1f (%s)", plain.InitialEnergy, plain.FinalEnergy, plain.ConvergenceReason)
+	if plain.Converged {
+		t.Errorf("L-BFGS reported convergence at the energy cap: %s", plain.ConvergenceReason)
+	}
+
+	config.PreRelaxSteps = 100
+	protein := buildClashedDecapeptide(t)
+	relaxed, err := MinimizeQuaternionLBFGS(protein, config)
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+	t.Logf("With pre-relaxation: %d steps, ||g|| %.3g → %.3g, E %.1f → %.1f in %d iterations (%s)",
+		relaxed.PreRelaxSteps, relaxed.PreRelaxInitialGradient, relaxed.PreRelaxFinalGradient,
+		relaxed.InitialEnergy, relaxed.FinalEnergy, relaxed.Iterations, relaxed.ConvergenceReason)
+
+	if relaxed.PreRelaxSteps == 0 || relaxed.PreRelaxFinalGradient >= config.PreRelaxGradientNorm ||
+		relaxed.PreRelaxFinalGradient >= relaxed.PreRelaxInitialGradient {
+		t.Errorf("Pre-relaxation took %d steps to ||g|| %.3g (from %.3g), want below %.0f",
+			relaxed.PreRelaxSteps, relaxed.PreRelaxFinalGradient, relaxed.PreRelaxInitialGradient, config.PreRelaxGradientNorm)
+	}
+	if !relaxed.Converged || relaxed.FinalEnergy >= 100 {
+		t.Errorf("Pre-relaxed L-BFGS ended at %.1f kcal/mol (%s)", relaxed.FinalEnergy, relaxed.ConvergenceReason)
+	}
+	if e := evaluateEnergyForProtein(protein, config); math.Abs(e-relaxed.FinalEnergy) > 1e-9 {
+		t.Errorf("Protein left at %.4f kcal/mol, result reports %.4f", e, relaxed.FinalEnergy)
+	}
+}
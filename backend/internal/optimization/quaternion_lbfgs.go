@@ -43,6 +43,7 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"math"
 
@@ -51,6 +52,12 @@ import (
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
 )
 
+// IterationCallback is invoked once per optimizer iteration with the
+// iteration index, the current energy (kcal/mol) and a snapshot of the
+// current structure. The snapshot is a clone owned by the callback, so it
+// may be kept (e.g. for trajectory frames). Returning false halts the run.
+type IterationCallback func(iter int, energy float64, protein *parser.Protein) bool
+
 // QuaternionLBFGSConfig holds configuration for dihedral-space L-BFGS
 type QuaternionLBFGSConfig struct {
 	MaxIterations   int     // Maximum L-BFGS iterations
@@ -65,15 +72,51 @@ type QuaternionLBFGSConfig struct {
 	// Line search parameters
 	UseLineSearch   bool    // Enable Armijo-Wolfe line search
 	ArmijoC1        float64 // Armijo condition constant (default: 1e-4)
-	WolfeC2         float64 // Wolfe condition constant (default: 0.9)
+	WolfeC2         float64 // Strong-Wolfe curvature constant (default: 0.9); outside (0, 1): Armijo backtracking only
 	MaxLineSearchSteps int  // Maximum line search iterations
 
+	// Steepest-descent pre-relaxation of clashes (see preRelax)
+	PreRelaxSteps        int     // Descent steps before L-BFGS (0: none)
+	PreRelaxGradientNorm float64 // Start L-BFGS once ||grad|| falls below this (kcal/(mol·rad))
+	PreRelaxMaxStep      float64 // Largest change of any angle per step (radians)
+
 	// Energy calculation
 	VdWCutoff       float64
 	ElecCutoff      float64
+	VdWSwitchStart  float64 // Switch VdW off over [VdWSwitchStart, VdWCutoff] (0: hard cutoff)
+	ElecSwitchStart float64 // Switch electrostatics off over [ElecSwitchStart, ElecCutoff]
+	UseHBonds       bool // Include the smooth backbone H-bond term (physics.HBondEnergy)
+
+	// Energy function (zero value: all-atom); physics.EnergyModelCA folds
+	// in the coarse-grained CA model, ~50× cheaper per evaluation, for a
+	// global fold to refine all-atom afterwards
+	EnergyModel physics.EnergyModel
+
+	// Objective to minimize instead of the force field configured by the
+	// fields above (nil: that force field, see PhysicsEnergy). A nil
+	// Gradient from it is differentiated numerically like the force field
+	Energy EnergyFunction
+
+	// Predicted contacts to fold toward (nil: none); part of the objective,
+	// so their gradient drives the minimization
+	ContactRestraints    []physics.ContactRestraint
+	ContactForceConstant float64 // kcal/(mol·Å²); 0 uses physics.DefaultContactForceConstant
+
+	// User distance and dihedral restraints (nil: none), also part of the
+	// objective (see physics.RestraintEnergy)
+	DistanceRestraints []physics.DistanceRestraint
+	DihedralRestraints []physics.DihedralRestraint
+
+	// Residues (0-based indices) whose φ and ψ stay fixed, e.g. a
+	// well-modelled core around flexible loops (nil: all move). Their
+	// gradient is not computed, so a small movable region is also fast
+	FrozenResidues []int
 
 	// Verbose logging
 	Verbose         bool
+
+	// Optional per-iteration hook (nil: none); see IterationCallback
+	OnIteration     IterationCallback
 }
 
 // DefaultQuaternionLBFGSConfig returns recommended parameters
@@ -89,8 +132,14 @@ func DefaultQuaternionLBFGSConfig() QuaternionLBFGSConfig {
 		ArmijoC1:           1e-4,
 		WolfeC2:            0.9,
 		MaxLineSearchSteps: 20,
+		PreRelaxSteps:      0,            // Off: enable for clashy (e.g. fragment-assembled) starts
+		PreRelaxGradientNorm: 1000.0,
+		PreRelaxMaxStep:    0.05,         // ≈ 3° per step
 		VdWCutoff:          10.0,
 		ElecCutoff:         12.0,
+		VdWSwitchStart:     8.0,          // Smooth cutoffs: no energy jumps
+		ElecSwitchStart:    10.0,         // as pairs cross a cutoff
+		UseHBonds:          true,
 		Verbose:            false,
 	}
 }
@@ -105,6 +154,24 @@ type QuaternionLBFGSResult struct {
 	Converged           bool
 	ConvergenceReason   string
 	FunctionEvaluations int
+	MemoryResets        int     // L-BFGS memory cleared on non-positive curvature
+	MaxMemoryStep       float64 // Largest |s_k| component stored in the memory (radians)
+
+	// Pre-relaxation (PreRelaxSteps > 0 only): steps taken and the uncapped
+	// energy's gradient norm before and after
+	PreRelaxSteps            int
+	PreRelaxInitialGradient  float64
+	PreRelaxFinalGradient    float64
+
+	// RolledBack: the run ended above the best energy it had reached (it
+	// diverged, was aborted or cancelled mid-climb) and the protein was
+	// restored to the best structure, which FinalEnergy describes
+	RolledBack bool
+
+	// FinalAngles are the (φ, ψ) the returned structure was built from.
+	// Those of frozen residues are the extracted input angles bit for bit;
+	// measuring them again from the coordinates agrees to rounding
+	FinalAngles []geometry.RamachandranAngles
 }
 
 // MinimizeQuaternionLBFGS performs L-BFGS optimization in dihedral angle space
@@ -113,7 +180,20 @@ type QuaternionLBFGSResult struct {
 //
 // This prevents bond length/angle violations because geometry is rebuilt
 // from angles using fixed bond lengths/angles from crystallography.
+//
+// The protein is returned holding the lowest-energy structure the L-BFGS
+// iterations reached, never one above where they started: a run that
+// diverges or stops on an uphill step is rolled back (see RolledBack).
 func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConfig) (*QuaternionLBFGSResult, error) {
+	return MinimizeQuaternionLBFGSCtx(context.Background(), protein, config)
+}
+
+// MinimizeQuaternionLBFGSCtx is MinimizeQuaternionLBFGS with cancellation
+//
+// ctx is checked at the top of every iteration. On cancellation the protein
+// holds the best structure so far and the partial result is returned
+// together with ctx.Err().
+func MinimizeQuaternionLBFGSCtx(ctx context.Context, protein *parser.Protein, config QuaternionLBFGSConfig) (*QuaternionLBFGSResult, error) {
 	if protein == nil || len(protein.Residues) == 0 {
 		return nil, fmt.Errorf("protein is nil or empty")
 	}
@@ -127,12 +207,8 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 	if numAngles == 0 {
 		return nil, fmt.Errorf("no dihedral angles to optimize")
 	}
-
-	// Flatten angles to 1D vector: [phi1, psi1, phi2, psi2, ...]
-	x := make([]float64, numAngles)
-	for i, angle := range angles {
-		x[2*i] = angle.Phi
-		x[2*i+1] = angle.Psi
+	if _, err := config.frozenMask(len(angles)); err != nil {
+		return nil, err
 	}
 
 	// Calculate initial energy
@@ -142,7 +218,19 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 
 	if config.Verbose {
 		fmt.Printf("Quaternion L-BFGS: Initial energy = %.2f kcal/mol\n", currentEnergy)
-		fmt.Printf("  Optimizing %d dihedral angles (%d residues)\n", numAngles, len(angles))
+		fmt.Printf("  Optimizing %d dihedral angles (%d residues, %d frozen)\n", numAngles, len(angles), len(config.FrozenResidues))
+	}
+
+	// Relax clashes before the quasi-Newton model sees their gradients
+	if config.PreRelaxSteps > 0 {
+		angles = preRelax(protein, angles, config, result)
+		currentEnergy = evaluateEnergyForProtein(protein, config)
+		result.FunctionEvaluations++
+
+		if config.Verbose {
+			fmt.Printf("  Pre-relaxation: %d steps, ||g|| %.1f → %.1f, E = %.2f kcal/mol\n",
+				result.PreRelaxSteps, result.PreRelaxInitialGradient, result.PreRelaxFinalGradient, currentEnergy)
+		}
 	}
 
 	// L-BFGS memory: store previous steps
@@ -160,11 +248,32 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 		fmt.Printf("  Initial gradient norm: %.4f\n", gradNorm)
 	}
 
+	// Best structure reached, restored if the run ends above it. Coordinates
+	// are kept rather than angles: rebuilding from angles would idealize the
+	// bond geometry of the input structure
+	best := cloneProtein(protein)
+	bestAngles, bestEnergy, bestGradNorm := angles, currentEnergy, gradNorm
+
 	// L-BFGS optimization loop
+	var cancelErr error
+	stopped := false
+	aborted := false
 	for iter := 0; iter < config.MaxIterations; iter++ {
+		if err := ctx.Err(); err != nil {
+			cancelErr = err
+			result.ConvergenceReason = fmt.Sprintf("Cancelled after %d iterations: %v", iter, err)
+			break
+		}
+
 		result.Iterations = iter + 1
 
-		// Check gradient convergence
+		// Check gradient convergence; at the energy cap the surface is flat,
+		// so a zero gradient there is a clash, not a minimum
+		if gradNorm < config.GradientTol && math.Abs(currentEnergy) >= physics.TotalEnergyCap {
+			aborted = true
+			result.ConvergenceReason = fmt.Sprintf("Energy at the %.0f kcal/mol cap has no gradient (set PreRelaxSteps)", currentEnergy)
+			break
+		}
 		if gradNorm < config.GradientTol {
 			result.Converged = true
 			result.ConvergenceReason = fmt.Sprintf("Gradient norm %.4f < tolerance %.4f", gradNorm, config.GradientTol)
@@ -178,9 +287,10 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 		var alpha float64
 		var newEnergy float64
 		var newAngles []geometry.RamachandranAngles
+		var newGradient []float64 // From the line search, if it computed it
 
 		if config.UseLineSearch {
-			alpha, newEnergy, newAngles = armijoWolfeLineSearch(protein, angles, direction, gradient, currentEnergy, config)
+			alpha, newEnergy, newAngles, newGradient = armijoWolfeLineSearch(protein, angles, direction, gradient, currentEnergy, config)
 		} else {
 			// Simple fixed step size
 			alpha = config.StepSize
@@ -198,23 +308,29 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 				iter, newEnergy, energyChange, alpha, gradNorm)
 		}
 
+		// Protein holds newAngles here: the line search ends on the accepted step
+		if config.OnIteration != nil && !config.OnIteration(iter, newEnergy, cloneProtein(protein)) {
+			angles, currentEnergy = newAngles, newEnergy
+			result.ConvergenceReason = fmt.Sprintf("Stopped by OnIteration after %d iterations", iter+1)
+			stopped = true
+			break
+		}
+
 		// Check energy convergence
 		if math.Abs(energyChange) < config.EnergyTol && iter > 10 {
+			angles, currentEnergy = newAngles, newEnergy // Protein already holds the accepted step
 			result.Converged = true
 			result.ConvergenceReason = fmt.Sprintf("Energy change %.4f < tolerance %.4f", math.Abs(energyChange), config.EnergyTol)
 			break
 		}
 
 		// Update for L-BFGS memory
-		// s_k = x_{k+1} - x_k
-		s_k := make([]float64, numAngles)
-		for i := range angles {
-			s_k[2*i] = newAngles[i].Phi - angles[i].Phi
-			s_k[2*i+1] = newAngles[i].Psi - angles[i].Psi
-		}
+		s_k := angleStep(angles, newAngles)
 
-		// Compute new gradient
-		newGradient := computeDihedralGradient(protein, newAngles, config)
+		// Compute new gradient, unless the line search already did
+		if newGradient == nil {
+			newGradient = computeDihedralGradient(protein, newAngles, config)
+		}
 
 		// y_k = grad_{k+1} - grad_k
 		y_k := make([]float64, numAngles)
@@ -222,9 +338,19 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 			y_k[i] = newGradient[i] - gradient[i]
 		}
 
-		// ρ_k = 1 / (y_k^T s_k)
+		// ρ_k = 1 / (y_k^T s_k); a pair with non-positive curvature would
+		// make the inverse Hessian indefinite. The Wolfe step rules it out
+		// on a smooth surface, so one here means the stored pairs no longer
+		// describe the local curvature: restart from steepest descent
 		sTy := vectorDotFloat(s_k, y_k)
-		if math.Abs(sTy) > 1e-10 {
+		if sTy <= 1e-10 && len(s) > 0 {
+			s, y, rho = s[:0], y[:0], rho[:0]
+			result.MemoryResets++
+		}
+		if sTy > 1e-10 {
+			for _, v := range s_k {
+				result.MaxMemoryStep = math.Max(result.MaxMemoryStep, math.Abs(v))
+			}
 			// Add to L-BFGS memory
 			if len(s) >= config.MemorySize {
 				// Remove oldest
@@ -242,22 +368,40 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 		currentEnergy = newEnergy
 		gradient = newGradient
 		gradNorm = vectorNormFloat(gradient)
+		if currentEnergy < bestEnergy {
+			copyProteinCoordinates(protein, best)
+			bestAngles, bestEnergy, bestGradNorm = angles, currentEnergy, gradNorm
+		}
 
-		// Safety: If energy increased significantly, something is wrong
-		if energyChange < -100.0 {
+		// Safety: If energy increased significantly (or is no longer a
+		// number), something is wrong
+		if energyChange < -100.0 || math.IsNaN(energyChange) {
 			if config.Verbose {
 				fmt.Printf("  WARNING: Energy increased by %.2f kcal/mol - stopping\n", -energyChange)
 			}
+			aborted = true
+			result.ConvergenceReason = fmt.Sprintf("Energy increased by %.2f kcal/mol", -energyChange)
 			break
 		}
 	}
 
+	// Roll back to the best structure; !(≤) also catches a NaN energy
+	if !(currentEnergy <= bestEnergy) {
+		if config.Verbose {
+			fmt.Printf("  Rolling back from %.2f to best energy %.2f kcal/mol\n", currentEnergy, bestEnergy)
+		}
+		copyProteinCoordinates(best, protein)
+		angles, currentEnergy, gradNorm = bestAngles, bestEnergy, bestGradNorm
+		result.RolledBack = true
+	}
+
 	// Final results
+	result.FinalAngles = angles
 	result.FinalEnergy = currentEnergy
 	result.EnergyChange = result.InitialEnergy - result.FinalEnergy
 	result.FinalGradientNorm = gradNorm
 
-	if !result.Converged {
+	if !result.Converged && cancelErr == nil && !stopped && !aborted {
 		result.ConvergenceReason = fmt.Sprintf("Reached max iterations (%d)", config.MaxIterations)
 	}
 
@@ -271,7 +415,7 @@ func MinimizeQuaternionLBFGS(protein *parser.Protein, config QuaternionLBFGSConf
 		fmt.Printf("  Function evaluations: %d\n", result.FunctionEvaluations)
 	}
 
-	return result, nil
+	return result, cancelErr
 }
 
 // ExtractDihedrals extracts (φ, ψ) angles from protein structure
@@ -287,6 +431,16 @@ func ExtractDihedrals(protein *parser.Protein) []geometry.RamachandranAngles {
 // BUG FIX (2025-11-06): Copy coordinates residue-by-residue, matching atoms by name
 // Previous approach: Copy by atom index → WRONG (ordering mismatch)
 // New approach: Match atoms by residue index + atom name → CORRECT
+//
+// GAPS: An undefined interior ψ_i or φ_(i+1) (NaN, e.g. from a missing atom
+// or chain break in a PDB model, see geometry.CalculateRamachandran) splits
+// the chain there. Each segment is rebuilt from its own angles and placed on
+// its first residue's original N, CA, C, so segments keep their positions
+// instead of being joined by made-up extended angles. A segment whose first
+// residue lacks N, CA or C has no frame and keeps its coordinates. A defined
+// ψ at a segment's end reaches into the next segment, so it only orients O.
+// An undefined ψ (the C-terminus, or before a gap) keeps O where it was
+// relative to its residue, rather than placing it from a default ψ.
 func SetDihedrals(protein *parser.Protein, angles []geometry.RamachandranAngles) error {
 	// Get sequence from existing protein
 	sequence := ""
@@ -294,48 +448,159 @@ func SetDihedrals(protein *parser.Protein, angles []geometry.RamachandranAngles)
 		sequence += res.Name
 	}
 
-	// Build new structure from angles
-	newProtein, err := geometry.BuildProteinFromAngles(sequence, angles)
-	if err != nil {
-		return err
+	segments := dihedralSegments(len(protein.Residues), angles)
+	angles = withCarbonylPsi(protein, angles)
+	if len(segments) <= 1 {
+		// Gap-free chain: build new structure from angles and take its
+		// coordinates as they are
+		newProtein, err := geometry.BuildProteinFromAngles(sequence, angles)
+		if err != nil {
+			return err
+		}
+		copyBackbone(protein, newProtein, 0, nil)
+		return nil
+	}
+
+	for _, seg := range segments {
+		start, end := seg[0], seg[1]
+		first := protein.Residues[start]
+		if first == nil || !first.HasCompleteBackbone() {
+			continue
+		}
+		// Segment residues rebuilt from their own angles, as if start were residue 0
+		built, err := geometry.BuildProteinFromAngles(sequence[start:end], angles[start:end])
+		if err != nil {
+			return err
+		}
+		place := residueFrameTransform(built.Residues[0], first)
+		copyBackbone(protein, built, start, place)
 	}
 
-	// Copy coordinates back residue-by-residue, matching atoms by name
-	// This ensures correct atom matching even if ordering differs
-	for i := 0; i < len(protein.Residues) && i < len(newProtein.Residues); i++ {
-		oldRes := protein.Residues[i]
-		newRes := newProtein.Residues[i]
+	return nil
+}
 
-		// Copy N atom coordinates
-		if oldRes.N != nil && newRes.N != nil {
-			oldRes.N.X = newRes.N.X
-			oldRes.N.Y = newRes.N.Y
-			oldRes.N.Z = newRes.N.Z
+// withCarbonylPsi returns angles with each undefined ψ replaced by the ψ
+// that rebuilds the residue's current O (O sits anti to N(i+1), so
+// ψ = N-CA-C-O + 180°); angles itself is not modified
+func withCarbonylPsi(protein *parser.Protein, angles []geometry.RamachandranAngles) []geometry.RamachandranAngles {
+	var filled []geometry.RamachandranAngles
+	for i := 0; i < len(angles) && i < len(protein.Residues); i++ {
+		res := protein.Residues[i]
+		if angles[i].HasPsi() || res == nil || !res.HasCompleteBackbone() || res.O == nil {
+			continue
+		}
+		if filled == nil {
+			filled = copyAngles(angles)
 		}
+		filled[i].Psi = normalizeAngle(geometry.AtomDihedral(res.N, res.CA, res.C, res.O) + math.Pi)
+	}
+	if filled == nil {
+		return angles
+	}
+	return filled
+}
 
-		// Copy CA atom coordinates
-		if oldRes.CA != nil && newRes.CA != nil {
-			oldRes.CA.X = newRes.CA.X
-			oldRes.CA.Y = newRes.CA.Y
-			oldRes.CA.Z = newRes.CA.Z
+// dihedralSegments splits residues [0, n) at undefined interior links
+// (NaN ψ_i or φ_(i+1)) into [start, end) ranges
+func dihedralSegments(n int, angles []geometry.RamachandranAngles) [][2]int {
+	segments := make([][2]int, 0, 1)
+	start := 0
+	for i := 0; i+1 < n && i+1 < len(angles); i++ {
+		if !angles[i].HasPsi() || !angles[i+1].HasPhi() {
+			segments = append(segments, [2]int{start, i + 1})
+			start = i + 1
 		}
+	}
+	if start < n {
+		segments = append(segments, [2]int{start, n})
+	}
+	return segments
+}
 
-		// Copy C atom coordinates
-		if oldRes.C != nil && newRes.C != nil {
-			oldRes.C.X = newRes.C.X
-			oldRes.C.Y = newRes.C.Y
-			oldRes.C.Z = newRes.C.Z
+// copyBackbone copies N, CA, C, O and amide H coordinates of built's
+// residues onto dst's residues from offset on, matching atoms by name and
+// mapping each position through place (if set)
+func copyBackbone(dst, built *parser.Protein, offset int, place func(geometry.Vector3) geometry.Vector3) {
+	dstH, builtH := amideHydrogens(dst), amideHydrogens(built)
+	for i := 0; offset+i < len(dst.Residues) && i < len(built.Residues); i++ {
+		oldRes, newRes := dst.Residues[offset+i], built.Residues[i]
+		if oldRes == nil || newRes == nil {
+			continue
 		}
+		pairs := [][2]*parser.Atom{
+			{oldRes.N, newRes.N},
+			{oldRes.CA, newRes.CA},
+			{oldRes.C, newRes.C},
+			{oldRes.O, newRes.O},
+			{dstH[oldRes], builtH[newRes]},
+		}
+		for _, pair := range pairs {
+			oldAtom, newAtom := pair[0], pair[1]
+			if oldAtom == nil || newAtom == nil {
+				continue
+			}
+			pos := geometry.Vector3{X: newAtom.X, Y: newAtom.Y, Z: newAtom.Z}
+			if place != nil {
+				pos = place(pos)
+			}
+			oldAtom.X, oldAtom.Y, oldAtom.Z = pos.X, pos.Y, pos.Z
+		}
+	}
+}
 
-		// Copy O atom coordinates
-		if oldRes.O != nil && newRes.O != nil {
-			oldRes.O.X = newRes.O.X
-			oldRes.O.Y = newRes.O.Y
-			oldRes.O.Z = newRes.O.Z
+// amideHydrogens maps each residue to its backbone H atom (kept in
+// protein.Atoms only, see geometry.AddHydrogens)
+func amideHydrogens(protein *parser.Protein) map[*parser.Residue]*parser.Atom {
+	type residueKey struct {
+		chainID string
+		seq     int
+		iCode   string
+	}
+	byKey := make(map[residueKey]*parser.Residue, len(protein.Residues))
+	for _, res := range protein.Residues {
+		if res != nil {
+			byKey[residueKey{res.ChainID, res.SeqNum, res.ICode}] = res
 		}
 	}
+	hydrogens := make(map[*parser.Residue]*parser.Atom)
+	for _, atom := range protein.Atoms {
+		if atom.Name != "H" && atom.Name != "HN" {
+			continue
+		}
+		if res := byKey[residueKey{atom.ChainID, atom.ResSeq, atom.ICode}]; res != nil {
+			hydrogens[res] = atom
+		}
+	}
+	return hydrogens
+}
 
-	return nil
+// residueFrameTransform returns the rigid motion taking from's N-CA-C frame
+// onto to's (both residues need N, CA, C)
+//
+// MATHEMATICIAN: Orthonormal frame at CA - e1 along CA→C, e2 the CA→N
+// direction made orthogonal to e1, e3 = e1 × e2 - expressed in one frame's
+// coordinates and re-expanded in the other's
+func residueFrameTransform(from, to *parser.Residue) func(geometry.Vector3) geometry.Vector3 {
+	frame := func(res *parser.Residue) (origin, e1, e2, e3 geometry.Vector3) {
+		origin = geometry.Vector3{X: res.CA.X, Y: res.CA.Y, Z: res.CA.Z}
+		c := geometry.Vector3{X: res.C.X, Y: res.C.Y, Z: res.C.Z}
+		n := geometry.Vector3{X: res.N.X, Y: res.N.Y, Z: res.N.Z}
+		e1 = c.Sub(origin).Normalize()
+		toN := n.Sub(origin)
+		e2 = toN.Sub(e1.Scale(toN.Dot(e1))).Normalize()
+		e3 = e1.Cross(e2)
+		return origin, e1, e2, e3
+	}
+
+	fromOrigin, f1, f2, f3 := frame(from)
+	toOrigin, t1, t2, t3 := frame(to)
+	return func(p geometry.Vector3) geometry.Vector3 {
+		d := p.Sub(fromOrigin)
+		return toOrigin.
+			Add(t1.Scale(d.Dot(f1))).
+			Add(t2.Scale(d.Dot(f2))).
+			Add(t3.Scale(d.Dot(f3)))
+	}
 }
 
 // computeDihedralGradient computes ∂E/∂φ and ∂E/∂ψ via finite differences
@@ -345,12 +610,33 @@ func SetDihedrals(protein *parser.Protein, angles []geometry.RamachandranAngles)
 //
 // We compute this via finite differences:
 // ∂E/∂φ_i ≈ (E(φ_i + δ) - E(φ_i)) / δ
+//
+// Components of config.FrozenResidues are zero, so the L-BFGS direction,
+// built from gradients and steps only, never moves them. A config.Energy
+// with its own Gradient is used as is, frozen components zeroed.
 func computeDihedralGradient(protein *parser.Protein, angles []geometry.RamachandranAngles, config QuaternionLBFGSConfig) []float64 {
+	frozen, _ := config.frozenMask(len(angles)) // Validated by the caller
+	if gradient := config.suppliedGradient(protein, len(angles), frozen); gradient != nil {
+		return gradient
+	}
+	return maskedDihedralGradient(protein, angles, config.FiniteDiffDelta, func(p *parser.Protein) float64 {
+		return evaluateEnergyForProtein(p, config)
+	}, frozen)
+}
+
+// dihedralGradient is computeDihedralGradient for an arbitrary energy
+func dihedralGradient(protein *parser.Protein, angles []geometry.RamachandranAngles, delta float64, energy func(*parser.Protein) float64) []float64 {
+	return maskedDihedralGradient(protein, angles, delta, energy, nil)
+}
+
+// maskedDihedralGradient is dihedralGradient with the residues where
+// frozen is true left at zero (nil: none frozen)
+func maskedDihedralGradient(protein *parser.Protein, angles []geometry.RamachandranAngles, delta float64, energy func(*parser.Protein) float64, frozen []bool) []float64 {
 	numAngles := len(angles) * 2
 	gradient := make([]float64, numAngles)
 
 	// Current energy
-	E0 := evaluateEnergyForProtein(protein, config)
+	E0 := energy(protein)
 
 	// If energy is NaN or Inf, return zero gradient
 	if math.IsNaN(E0) || math.IsInf(E0, 0) {
@@ -358,9 +644,11 @@ func computeDihedralGradient(protein *parser.Protein, angles []geometry.Ramachan
 	}
 
 	// Finite difference for each angle
-	delta := config.FiniteDiffDelta
-
 	for i := range angles {
+		if frozen != nil && frozen[i] {
+			continue
+		}
+
 		// Gradient w.r.t. phi_i
 		// Skip if phi is undefined (N-terminal residue has no phi)
 		if !math.IsNaN(angles[i].Phi) {
@@ -368,7 +656,7 @@ func computeDihedralGradient(protein *parser.Protein, angles []geometry.Ramachan
 			anglesCopy[i].Phi += delta
 			err := SetDihedrals(protein, anglesCopy)
 			if err == nil {
-				E_plus := evaluateEnergyForProtein(protein, config)
+				E_plus := energy(protein)
 				if !math.IsNaN(E_plus) && !math.IsInf(E_plus, 0) {
 					gradient[2*i] = (E_plus - E0) / delta
 				}
@@ -385,7 +673,7 @@ func computeDihedralGradient(protein *parser.Protein, angles []geometry.Ramachan
 			anglesCopy[i].Psi += delta
 			err := SetDihedrals(protein, anglesCopy)
 			if err == nil {
-				E_plus := evaluateEnergyForProtein(protein, config)
+				E_plus := energy(protein)
 				if !math.IsNaN(E_plus) && !math.IsInf(E_plus, 0) {
 					gradient[2*i+1] = (E_plus - E0) / delta
 				}
@@ -399,6 +687,66 @@ func computeDihedralGradient(protein *parser.Protein, angles []geometry.Ramachan
 	return gradient
 }
 
+// preRelax takes up to config.PreRelaxSteps capped steepest-descent steps
+// on the uncapped energy, stopping once ||grad|| < config.PreRelaxGradientNorm
+//
+// A clash gives gradients of 10⁴-10⁶ kcal/(mol·rad) that L-BFGS would fold
+// into its curvature pairs, and a structure at the energy cap gives none at
+// all. Each step moves the angles along -grad, scaled so that no angle
+// changes by more than the step cap; the cap halves when a step raises the
+// energy and regrows by 20% (up to PreRelaxMaxStep) when one lowers it.
+// Results go in result; the protein holds the returned angles.
+//
+// PHYSICIST: The classic pre-minimization before MD or L-BFGS: steepest
+// descent is slow near a minimum but cannot be misled far from one
+func preRelax(protein *parser.Protein, angles []geometry.RamachandranAngles, config QuaternionLBFGSConfig, result *QuaternionLBFGSResult) []geometry.RamachandranAngles {
+	uncapped := func(p *parser.Protein) float64 { return evaluateUncappedEnergy(p, config) }
+	frozen, _ := config.frozenMask(len(angles))
+	gradientAt := func(angles []geometry.RamachandranAngles) []float64 {
+		if gradient := config.suppliedGradient(protein, len(angles), frozen); gradient != nil {
+			return gradient
+		}
+		return maskedDihedralGradient(protein, angles, config.FiniteDiffDelta, uncapped, frozen)
+	}
+	energy := uncapped(protein)
+	gradient := gradientAt(angles)
+	gradNorm := vectorNormFloat(gradient)
+	result.PreRelaxInitialGradient = gradNorm
+
+	maxStep := config.PreRelaxMaxStep
+	for result.PreRelaxSteps < config.PreRelaxSteps && gradNorm >= config.PreRelaxGradientNorm && maxStep > 1e-6 {
+		result.PreRelaxSteps++
+
+		largest := 0.0
+		direction := make([]float64, len(gradient))
+		for i, g := range gradient {
+			direction[i] = -g
+			largest = math.Max(largest, math.Abs(g))
+		}
+		if largest == 0 {
+			break
+		}
+
+		newAngles := applyAngleStep(angles, direction, maxStep/largest)
+		SetDihedrals(protein, newAngles)
+		newEnergy := uncapped(protein)
+		result.FunctionEvaluations++
+
+		if newEnergy >= energy || math.IsNaN(newEnergy) {
+			SetDihedrals(protein, angles)
+			maxStep *= 0.5
+			continue
+		}
+		angles, energy = newAngles, newEnergy
+		gradient = gradientAt(angles)
+		gradNorm = vectorNormFloat(gradient)
+		maxStep = math.Min(maxStep*1.2, config.PreRelaxMaxStep)
+	}
+
+	result.PreRelaxFinalGradient = gradNorm
+	return angles
+}
+
 // lbfgsTwoLoopRecursion implements L-BFGS two-loop recursion
 //
 // ALGORITHM (Nocedal & Wright, 2006):
@@ -472,13 +820,17 @@ func lbfgsTwoLoopRecursion(gradient []float64, s, y [][]float64, rho []float64)
 // - Energy decreases sufficiently (Armijo)
 // - Step is not too small (Wolfe)
 // - Guarantees L-BFGS convergence!
+//
+// With 0 < config.WolfeC2 < 1 this is the strong-Wolfe search of Nocedal &
+// Wright (Algorithms 3.5 and 3.6): expand α until the minimum along p is
+// bracketed, then zoom in, requiring |grad(x + α*p)^T p| ≤ c2 |grad^T p|.
+// Otherwise only Armijo backtracking is done. The gradient at the returned
+// step is returned when the search computed it (the curvature test needs
+// it), so the caller need not recompute it; nil means it was not.
+//
+// The protein holds the returned angles on return.
 func armijoWolfeLineSearch(protein *parser.Protein, angles []geometry.RamachandranAngles,
-	direction, gradient []float64, energy0 float64, config QuaternionLBFGSConfig) (float64, float64, []geometry.RamachandranAngles) {
-
-	c1 := config.ArmijoC1
-	// c2 := config.WolfeC2 // Wolfe curvature condition (skipped for simplicity)
-	alphaMax := 1.0
-	alpha := alphaMax
+	direction, gradient []float64, energy0 float64, config QuaternionLBFGSConfig) (float64, float64, []geometry.RamachandranAngles, []float64) {
 
 	// grad^T * p (should be negative for descent direction)
 	gradDotDir := vectorDotFloat(gradient, direction)
@@ -491,6 +843,20 @@ func armijoWolfeLineSearch(protein *parser.Protein, angles []geometry.Ramachandr
 		gradDotDir = vectorDotFloat(gradient, direction)
 	}
 
+	if config.WolfeC2 <= 0 || config.WolfeC2 >= 1 {
+		alpha, energy, newAngles := armijoBacktracking(protein, angles, direction, gradDotDir, energy0, config)
+		return alpha, energy, newAngles, nil
+	}
+	return strongWolfeSearch(protein, angles, direction, gradDotDir, energy0, config)
+}
+
+// armijoBacktracking halves α from 1 until the Armijo condition holds
+func armijoBacktracking(protein *parser.Protein, angles []geometry.RamachandranAngles,
+	direction []float64, gradDotDir, energy0 float64, config QuaternionLBFGSConfig) (float64, float64, []geometry.RamachandranAngles) {
+
+	c1 := config.ArmijoC1
+	alpha := 1.0
+
 	// Try different step sizes
 	for iter := 0; iter < config.MaxLineSearchSteps; iter++ {
 		// Try step
@@ -499,12 +865,7 @@ func armijoWolfeLineSearch(protein *parser.Protein, angles []geometry.Ramachandr
 		newEnergy := evaluateEnergyForProtein(protein, config)
 
 		// Check Armijo condition
-		armijoLHS := newEnergy
-		armijoRHS := energy0 + c1*alpha*gradDotDir
-
-		if armijoLHS <= armijoRHS {
-			// Armijo satisfied, accept step
-			// (We skip Wolfe curvature check for simplicity - still stable!)
+		if newEnergy <= energy0+c1*alpha*gradDotDir {
 			return alpha, newEnergy, newAngles
 		}
 
@@ -513,20 +874,159 @@ func armijoWolfeLineSearch(protein *parser.Protein, angles []geometry.Ramachandr
 
 		if alpha < 1e-6 {
 			// Step size too small, use gradient descent step
-			alpha = config.StepSize
-			newAngles = applyAngleStep(angles, direction, alpha)
-			SetDihedrals(protein, newAngles)
-			newEnergy = evaluateEnergyForProtein(protein, config)
-			return alpha, newEnergy, newAngles
+			return fixedAngleStep(protein, angles, direction, config.StepSize, config)
 		}
 	}
 
 	// Line search failed, return small step
-	alpha = config.StepSize * 0.1
+	return fixedAngleStep(protein, angles, direction, config.StepSize*0.1, config)
+}
+
+// lineSearchPoint is a trial step α along the search direction
+type lineSearchPoint struct {
+	alpha, energy float64
+	slope         float64 // grad^T p at the step (once gradient is computed)
+	angles        []geometry.RamachandranAngles
+	gradient      []float64 // nil until computed
+}
+
+// strongWolfeSearch brackets and zooms to a step satisfying the strong
+// Wolfe conditions, returning it with its energy, angles and gradient
+//
+// If no such step is found within config.MaxLineSearchSteps energy
+// evaluations it returns the lowest Armijo step seen, or falls back to a
+// fixed step like armijoBacktracking (with a nil gradient).
+func strongWolfeSearch(protein *parser.Protein, angles []geometry.RamachandranAngles,
+	direction []float64, gradDotDir, energy0 float64, config QuaternionLBFGSConfig) (float64, float64, []geometry.RamachandranAngles, []float64) {
+
+	const alphaMax = 8.0
+	c1, c2 := config.ArmijoC1, config.WolfeC2
+
+	evaluations := 0
+	at := func(alpha float64) lineSearchPoint {
+		evaluations++
+		newAngles := applyAngleStep(angles, direction, alpha)
+		SetDihedrals(protein, newAngles)
+		return lineSearchPoint{alpha: alpha, energy: evaluateEnergyForProtein(protein, config), angles: newAngles}
+	}
+	withSlope := func(t lineSearchPoint) lineSearchPoint {
+		SetDihedrals(protein, t.angles)
+		t.gradient = computeDihedralGradient(protein, t.angles, config)
+		t.slope = vectorDotFloat(t.gradient, direction)
+		// The gradient's probes rebuild the structure; re-read the energy
+		// so it matches the coordinates left behind to the last bit
+		t.energy = evaluateEnergyForProtein(protein, config)
+		return t
+	}
+	armijo := func(t lineSearchPoint) bool {
+		return t.energy <= energy0+c1*t.alpha*gradDotDir
+	}
+	curvature := func(t lineSearchPoint) bool {
+		return math.Abs(t.slope) <= -c2*gradDotDir
+	}
+
+	// Best sufficient-decrease step, returned if the search runs out
+	var best *lineSearchPoint
+	keep := func(t lineSearchPoint) {
+		if armijo(t) && (best == nil || t.energy < best.energy) {
+			b := t
+			best = &b
+		}
+	}
+	finish := func() (float64, float64, []geometry.RamachandranAngles, []float64) {
+		if best == nil {
+			alpha, energy, newAngles := fixedAngleStep(protein, angles, direction, config.StepSize*0.1, config)
+			return alpha, energy, newAngles, nil
+		}
+		SetDihedrals(protein, best.angles)
+		return best.alpha, best.energy, best.angles, best.gradient
+	}
+
+	// zoom narrows [lo, hi], lo always the lower-energy Armijo end
+	zoom := func(lo, hi lineSearchPoint) (float64, float64, []geometry.RamachandranAngles, []float64) {
+		for evaluations < config.MaxLineSearchSteps {
+			t := at(interpolateStep(lo, hi.alpha, hi.energy))
+			if !armijo(t) || t.energy >= lo.energy {
+				hi = t
+				continue
+			}
+			t = withSlope(t)
+			keep(t)
+			if curvature(t) {
+				return t.alpha, t.energy, t.angles, t.gradient
+			}
+			if t.slope*(hi.alpha-lo.alpha) >= 0 {
+				hi = lo
+			}
+			lo = t
+		}
+		return finish()
+	}
+
+	prev := lineSearchPoint{alpha: 0, energy: energy0, slope: gradDotDir, angles: angles}
+	alpha := 1.0
+	for evaluations < config.MaxLineSearchSteps {
+		t := at(alpha)
+		if !armijo(t) || (prev.alpha > 0 && t.energy >= prev.energy) {
+			return zoom(prev, t)
+		}
+		t = withSlope(t)
+		keep(t)
+		if curvature(t) {
+			return t.alpha, t.energy, t.angles, t.gradient
+		}
+		if t.slope >= 0 {
+			return zoom(t, prev)
+		}
+		if alpha >= alphaMax {
+			break
+		}
+		prev = t
+		alpha = math.Min(2*alpha, alphaMax)
+	}
+	return finish()
+}
+
+// interpolateStep returns the minimizer of the quadratic through lo's
+// energy and slope and (hiAlpha, hiEnergy), kept at least a tenth of the
+// interval away from both ends
+func interpolateStep(lo lineSearchPoint, hiAlpha, hiEnergy float64) float64 {
+	d := hiAlpha - lo.alpha
+	alpha := lo.alpha + d/2
+	if denom := 2 * (hiEnergy - lo.energy - lo.slope*d); denom > 0 {
+		alpha = lo.alpha - lo.slope*d*d/denom
+	}
+	a, b := math.Min(lo.alpha, hiAlpha), math.Max(lo.alpha, hiAlpha)
+	margin := 0.1 * (b - a)
+	return math.Max(a+margin, math.Min(b-margin, alpha))
+}
+
+// fixedAngleStep takes step alpha along direction without a line search
+func fixedAngleStep(protein *parser.Protein, angles []geometry.RamachandranAngles,
+	direction []float64, alpha float64, config QuaternionLBFGSConfig) (float64, float64, []geometry.RamachandranAngles) {
+
 	newAngles := applyAngleStep(angles, direction, alpha)
 	SetDihedrals(protein, newAngles)
-	newEnergy := evaluateEnergyForProtein(protein, config)
-	return alpha, newEnergy, newAngles
+	return alpha, evaluateEnergyForProtein(protein, config), newAngles
+}
+
+// angleStep returns s_k = x_{k+1} - x_k for the L-BFGS memory
+//
+// Each difference is the shortest signed arc: applyAngleStep wraps angles
+// to [-π, π], so a step from 179° to -179° is +2°, not -358°. Undefined
+// (NaN) terminal angles never move, and must not turn s_k^T y_k into NaN.
+// y_k needs no wrapping: gradients are periodic, not angles.
+func angleStep(angles, newAngles []geometry.RamachandranAngles) []float64 {
+	s := make([]float64, 2*len(angles))
+	for i := range angles {
+		if !math.IsNaN(angles[i].Phi) {
+			s[2*i] = wrapAngle(newAngles[i].Phi - angles[i].Phi)
+		}
+		if !math.IsNaN(angles[i].Psi) {
+			s[2*i+1] = wrapAngle(newAngles[i].Psi - angles[i].Psi)
+		}
+	}
+	return s
 }
 
 // applyAngleStep applies step in direction to angles
@@ -554,10 +1054,63 @@ func normalizeAngle(angle float64) float64 {
 	return angle
 }
 
-// evaluateEnergyForProtein calculates energy for protein
+// evaluateEnergyForProtein calculates energy for protein: config.Energy
+// if set, else the configured force field
 func evaluateEnergyForProtein(protein *parser.Protein, config QuaternionLBFGSConfig) float64 {
-	energyComps := physics.CalculateTotalEnergy(protein, config.VdWCutoff, config.ElecCutoff)
-	return energyComps.Total
+	if config.Energy != nil {
+		return config.Energy.Energy(protein)
+	}
+	return config.physicsEnergy().Energy(protein)
+}
+
+// evaluateUncappedEnergy is evaluateEnergyForProtein without the
+// ±physics.TotalEnergyCap cap of the force field
+func evaluateUncappedEnergy(protein *parser.Protein, config QuaternionLBFGSConfig) float64 {
+	if config.Energy != nil {
+		return config.Energy.Energy(protein)
+	}
+	return physics.CalculateTotalEnergyWithConfig(protein, config.energyConfig()).Uncapped()
+}
+
+// frozenMask returns which of numResidues residues are frozen (nil if
+// none), or an error for an index out of range or no residue left to move
+func (config QuaternionLBFGSConfig) frozenMask(numResidues int) ([]bool, error) {
+	if len(config.FrozenResidues) == 0 {
+		return nil, nil
+	}
+	frozen := make([]bool, numResidues)
+	count := 0
+	for _, i := range config.FrozenResidues {
+		if i < 0 || i >= numResidues {
+			return nil, fmt.Errorf("frozen residue %d out of range [0, %d)", i, numResidues)
+		}
+		if !frozen[i] {
+			frozen[i] = true
+			count++
+		}
+	}
+	if count == numResidues {
+		return nil, fmt.Errorf("all %d residues are frozen", numResidues)
+	}
+	return frozen, nil
+}
+
+// energyConfig returns the physics energy settings of config
+func (config QuaternionLBFGSConfig) energyConfig() physics.EnergyConfig {
+	return physics.EnergyConfig{
+		VdWCutoff:       config.VdWCutoff,
+		ElecCutoff:      config.ElecCutoff,
+		VdWSwitchStart:  config.VdWSwitchStart,
+		ElecSwitchStart: config.ElecSwitchStart,
+		UseHBonds:       config.UseHBonds,
+		Model:           config.EnergyModel,
+
+		ContactRestraints:    config.ContactRestraints,
+		ContactForceConstant: config.ContactForceConstant,
+
+		DistanceRestraints: config.DistanceRestraints,
+		DihedralRestraints: config.DihedralRestraints,
+	}
 }
 
 // copyAngles creates deep copy of angles
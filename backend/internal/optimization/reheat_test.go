@@ -0,0 +1,97 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// twoBasinEnergy is a tilted double well in x, harmonic in y and z: a
+// shallow basin at x = -1 (E ≈ +0.5) and a deep one at x = +1 (E ≈ -0.5),
+// separated by a barrier of about 4 kcal/mol at x = 0
+func twoBasinEnergy(p *parser.Protein) float64 {
+	a := p.Atoms[0]
+	return 4*(a.X*a.X-1)*(a.X*a.X-1) - 0.5*a.X + a.Y*a.Y + a.Z*a.Z
+}
+
+// twoBasinConfig anneals twoBasinEnergy for a fixed number of steps
+func twoBasinConfig(schedule string, seed int64) SimulatedAnnealingConfig {
+	config := DefaultSimulatedAnnealingConfig()
+	config.CoolingSchedule = schedule
+	config.NumSteps = 2000
+	config.UseLBFGSRefinement = false
+	config.PlateauSteps = 0
+	config.PerturbationInitial = 0.3
+	config.PerturbationFinal = 0.3
+	config.Energy = EnergyFunc(twoBasinEnergy)
+	config.Seed = seed
+	return config
+}
+
+// TestAdaptiveReheatEscapesShallowBasin starts a single atom in the
+// shallow basin and counts, over many seeds, how often a run of fixed
+// length ends in the deep one
+func TestAdaptiveReheatEscapesShallowBasin(t *testing.T) {
+	const runs = 60
+	escapes := func(schedule string) (found, reheats int) {
+		for seed := int64(1); seed <= runs; seed++ {
+			protein := &parser.Protein{Atoms: []*parser.Atom{{Serial: 1, Name: "CX", Element: "C", X: -1}}}
+			result, err := SimulatedAnnealing(protein, twoBasinConfig(schedule, seed))
+			if err != nil {
+				t.Fatalf("%s: simulated annealing failed: %v", schedule, err)
+			}
+			if protein.Atoms[0].X > 0 {
+				found++
+			}
+			reheats += result.Reheats
+		}
+		return found, reheats
+	}
+
+	monotonic, _ := escapes("exponential")
+	reheated, reheats := escapes("adaptive_reheat")
+	t.Logf("Deep basin reached: exponential %d/%d, adaptive_reheat %d/%d (%d reheats)",
+		monotonic, runs, reheated, runs, reheats)
+
+	if reheats == 0 {
+		t.Error("adaptive_reheat never reheated")
+	}
+	if reheated <= monotonic {
+		t.Errorf("adaptive_reheat escaped %d times, monotonic cooling %d", reheated, monotonic)
+	}
+}
+
+// TestSawtoothSchedule checks each cycle cools and the next restarts at
+// ReheatFraction × TemperatureInitial
+func TestSawtoothSchedule(t *testing.T) {
+	config := twoBasinConfig("sawtooth", 1)
+	length := config.NumSteps / config.NumCycles
+
+	if T := getTemperatureSchedule(0, config); T != config.TemperatureInitial {
+		t.Errorf("T(0) = %.1f K, want %.1f", T, config.TemperatureInitial)
+	}
+	for cycle := 1; cycle < config.NumCycles; cycle++ {
+		start := cycle * length
+		before := getTemperatureSchedule(start-1, config)
+		T := getTemperatureSchedule(start, config)
+		if want := config.ReheatFraction * config.TemperatureInitial; math.Abs(T-want) > 1e-9 {
+			t.Errorf("Cycle %d starts at %.1f K, want %.1f", cycle, T, want)
+		}
+		if before >= T || before > 2*config.TemperatureFinal {
+			t.Errorf("Cycle %d ends at %.2f K before reheating to %.1f", cycle-1, before, T)
+		}
+		if mid := getTemperatureSchedule(start+length/2, config); mid >= T {
+			t.Errorf("Cycle %d does not cool: %.1f K mid-cycle", cycle, mid)
+		}
+	}
+
+	protein := &parser.Protein{Atoms: []*parser.Atom{{Serial: 1, Name: "CX", Element: "C", X: -1}}}
+	result, err := SimulatedAnnealing(protein, config)
+	if err != nil {
+		t.Fatalf("Simulated annealing failed: %v", err)
+	}
+	if result.Reheats != config.NumCycles-1 || result.Steps != config.NumSteps {
+		t.Errorf("%d reheats in %d steps, want %d in %d", result.Reheats, result.Steps, config.NumCycles-1, config.NumSteps)
+	}
+}
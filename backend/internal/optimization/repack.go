@@ -0,0 +1,526 @@
+// Package optimization - Side-chain repacking
+//
+// The backbone optimizers never move side-chain atoms, so clashes between
+// side chains survive every minimization. Repacking holds the backbone (and
+// Cβ) fixed and chooses one rotamer per residue to minimize the packing
+// energy, as SCWRL does.
+//
+// PHYSICIST: Packing energy = Σ_i E_self(r_i) + Σ_i<j E_pair(r_i, r_j), where
+// E_self is AMBER-form Lennard-Jones against fixed atoms plus a rotamer prior
+// -w ln(p/p_max), and E_pair is Lennard-Jones between two side chains.
+// Each atom pair is capped at repackPairCap so a single overlap cannot
+// swamp the search.
+// MATHEMATICIAN: Goldstein dead-end elimination prunes rotamers that can
+// never be in the optimum; simulated annealing plus a greedy sweep searches
+// what is left
+// BIOCHEMIST: Cysteines in disulfides are left alone; Pro, Ala and Gly have
+// nothing to repack
+// ETHICIST: Seeded and reproducible; with KeepInputRotamer the input
+// conformation is a candidate, so repacking never raises the packing energy
+//
+// CITATION:
+// Krivov, G. G., Shapovalov, M. V., Dunbrack, R. L. (2009). "Improved prediction of protein
+// side-chain conformations with SCWRL4." Proteins 77(4): 778-795.
+//
+// Goldstein, R. F. (1994). "Efficient rotamer elimination applied to protein side-chains and
+// related spin glasses." Biophys. J. 66(5): 1335-1340.
+package optimization
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// repackPairCap limits the repulsion of one atom pair (kcal/mol)
+const repackPairCap = 10.0
+
+// RepackConfig holds side-chain repacking parameters
+type RepackConfig struct {
+	Cutoff      float64 // Lennard-Jones cutoff (Å)
+	PriorWeight float64 // Weight w of the rotamer prior -w ln(p/p_max) (kcal/mol)
+
+	// Keep the input side chain as a candidate (with no prior penalty)
+	KeepInputRotamer bool
+
+	// Simulated annealing over the rotamers surviving dead-end elimination
+	NumSteps           int
+	TemperatureInitial float64 // Kelvin
+	TemperatureFinal   float64 // Kelvin
+
+	// Random seed
+	Seed int64
+
+	// Verbose logging
+	Verbose bool
+}
+
+// DefaultRepackConfig returns recommended repacking parameters
+func DefaultRepackConfig() RepackConfig {
+	return RepackConfig{
+		Cutoff:             8.0,   // 8 Å LJ cutoff
+		PriorWeight:        0.593, // RT at 298 K
+		KeepInputRotamer:   true,
+		NumSteps:           5000,
+		TemperatureInitial: 3000.0,
+		TemperatureFinal:   10.0,
+		Seed:               42,
+		Verbose:            false,
+	}
+}
+
+// RepackResult reports what repacking changed
+type RepackResult struct {
+	ResiduesRepacked  int     // Residues with a side chain that was searched
+	ResiduesChanged   int     // Residues whose side chain moved
+	RotamersPruned    int     // Rotamers eliminated by dead-end elimination
+	InitialEnergy     float64 // Packing energy of the input side chains (kcal/mol)
+	FinalEnergy       float64 // Packing energy after repacking (kcal/mol)
+	EnergyImprovement float64 // InitialEnergy - FinalEnergy
+}
+
+// RepackSideChains repacks side chains in place with the backbone held fixed
+//
+// Only residues whose side chain is present (Cβ and every library atom) are
+// repacked. See RepackSideChainsWithResult for the statistics.
+func RepackSideChains(protein *parser.Protein, config RepackConfig) error {
+	_, err := RepackSideChainsWithResult(protein, config)
+	return err
+}
+
+// RepackSideChainsWithResult is RepackSideChains returning what changed
+//
+// ALGORITHM:
+//  1. Build every library rotamer (plus the input conformation) per residue
+//  2. Tabulate self and pair energies
+//  3. Goldstein DEE until no rotamer can be eliminated
+//  4. Simulated annealing from the input assignment, then a greedy sweep
+//  5. Write the best assignment's coordinates into protein
+func RepackSideChainsWithResult(protein *parser.Protein, config RepackConfig) (*RepackResult, error) {
+	if protein == nil || len(protein.Residues) == 0 {
+		return nil, fmt.Errorf("protein is nil or empty")
+	}
+	if config.Cutoff <= 0 {
+		return nil, fmt.Errorf("cutoff must be positive, got %.2f", config.Cutoff)
+	}
+
+	sites := repackSites(protein)
+	result := &RepackResult{ResiduesRepacked: len(sites)}
+	if len(sites) == 0 {
+		return result, nil
+	}
+
+	self, pair := repackEnergyTables(protein, sites, config)
+
+	// Input conformation is state 0 of every site
+	alive := make([][]bool, len(sites))
+	for i, site := range sites {
+		alive[i] = make([]bool, len(site.states))
+		for r := range alive[i] {
+			alive[i][r] = r > 0 || config.KeepInputRotamer
+		}
+	}
+
+	input := make([]int, len(sites))
+	result.InitialEnergy = packingEnergy(input, self, pair)
+
+	result.RotamersPruned = deadEndElimination(self, pair, alive)
+	best := annealRotamers(self, pair, alive, config)
+	result.FinalEnergy = packingEnergy(best, self, pair)
+	result.EnergyImprovement = result.InitialEnergy - result.FinalEnergy
+
+	// Apply
+	for i, site := range sites {
+		if best[i] == 0 {
+			continue
+		}
+		state := site.states[best[i]]
+		moved := false
+		for k, atom := range site.atoms {
+			pos := state.positions[k]
+			if pos.Sub(geometry.Vector3{X: atom.X, Y: atom.Y, Z: atom.Z}).Length() > 0.1 {
+				moved = true
+			}
+			atom.X, atom.Y, atom.Z = pos.X, pos.Y, pos.Z
+		}
+		if moved {
+			result.ResiduesChanged++
+		}
+	}
+
+	if config.Verbose {
+		fmt.Printf("Side-chain repacking: %d residues, %d changed, %d rotamers pruned by DEE\n",
+			result.ResiduesRepacked, result.ResiduesChanged, result.RotamersPruned)
+		fmt.Printf("  Packing energy: %.2f → %.2f kcal/mol (Δ = %.2f)\n",
+			result.InitialEnergy, result.FinalEnergy, result.EnergyImprovement)
+	}
+
+	return result, nil
+}
+
+// repackSite is one residue whose side chain is searched
+type repackSite struct {
+	residue int            // Index into protein.Residues
+	atoms   []*parser.Atom // Movable atoms in topology order
+	states  []repackState  // states[0] is the input conformation
+}
+
+// repackState is one candidate side-chain conformation
+type repackState struct {
+	name      string
+	positions []geometry.Vector3 // Parallel to repackSite.atoms
+	prior     float64            // -ln(p / p_max); 0 for the input
+}
+
+// repackSites finds residues with a complete, repackable side chain
+func repackSites(protein *parser.Protein) []repackSite {
+	sidechains := sidechainAtomsByResidue(protein)
+
+	// Disulfide-bonded cysteines stay put
+	bonded := make(map[int]bool)
+	for _, d := range physics.DetectDisulfides(protein) {
+		bonded[d.Residue1] = true
+		bonded[d.Residue2] = true
+	}
+
+	sites := make([]repackSite, 0)
+	for i, res := range protein.Residues {
+		resType := rotamerResidueType(res.Name)
+		rotamers := rotamerLibrary[resType]
+		if len(rotamers) == 0 || bonded[i] || res.N == nil || res.CA == nil {
+			continue
+		}
+
+		cb := sidechains[i]["CB"]
+		if cb == nil {
+			continue
+		}
+
+		site := repackSite{residue: i}
+		complete := true
+		for _, def := range sidechainTopology[resType] {
+			atom := sidechains[i][def.Name]
+			if atom == nil {
+				complete = false
+				break
+			}
+			site.atoms = append(site.atoms, atom)
+		}
+		if !complete {
+			continue
+		}
+
+		input := repackState{name: "input", positions: make([]geometry.Vector3, len(site.atoms))}
+		for k, atom := range site.atoms {
+			input.positions[k] = geometry.Vector3{X: atom.X, Y: atom.Y, Z: atom.Z}
+		}
+		site.states = append(site.states, input)
+
+		n := geometry.Vector3{X: res.N.X, Y: res.N.Y, Z: res.N.Z}
+		ca := geometry.Vector3{X: res.CA.X, Y: res.CA.Y, Z: res.CA.Z}
+		cbPos := geometry.Vector3{X: cb.X, Y: cb.Y, Z: cb.Z}
+
+		maxProb := 0.0
+		for _, rot := range rotamers {
+			maxProb = math.Max(maxProb, rot.Probability)
+		}
+
+		for _, rot := range rotamers {
+			placed := buildSidechain(resType, n, ca, cbPos, rot.Chi)
+			state := repackState{
+				name:      rot.Name,
+				positions: make([]geometry.Vector3, len(site.atoms)),
+				prior:     -math.Log(rot.Probability / maxProb),
+			}
+			for k, atom := range site.atoms {
+				state.positions[k] = placed[atom.Name]
+			}
+			site.states = append(site.states, state)
+		}
+
+		sites = append(sites, site)
+	}
+
+	return sites
+}
+
+// sidechainAtomsByResidue maps residue index → non-backbone atoms by name
+func sidechainAtomsByResidue(protein *parser.Protein) map[int]map[string]*parser.Atom {
+	type key struct {
+		chain string
+		seq   int
+	}
+	index := make(map[key]int, len(protein.Residues))
+	for i, res := range protein.Residues {
+		index[key{res.ChainID, res.SeqNum}] = i
+	}
+
+	atoms := make(map[int]map[string]*parser.Atom)
+	for _, atom := range protein.Atoms {
+		switch atom.Name {
+		case "N", "CA", "C", "O", "OXT", "H", "HN", "HA":
+			continue
+		}
+		i, ok := index[key{atom.ChainID, atom.ResSeq}]
+		if !ok {
+			continue
+		}
+		if atoms[i] == nil {
+			atoms[i] = make(map[string]*parser.Atom)
+		}
+		if atoms[i][atom.Name] == nil { // Keep first alternate location
+			atoms[i][atom.Name] = atom
+		}
+	}
+
+	return atoms
+}
+
+// repackEnergyTables computes self[i][r] and pair[i][j][r][s] (j > i;
+// nil when sites i and j never come within the cutoff)
+func repackEnergyTables(protein *parser.Protein, sites []repackSite, config RepackConfig) ([][]float64, [][][][]float64) {
+	movable := make(map[*parser.Atom]bool)
+	for _, site := range sites {
+		for _, atom := range site.atoms {
+			movable[atom] = true
+		}
+	}
+
+	// Probe atoms carry the element for LJ parameters
+	probe := func(atom *parser.Atom, pos geometry.Vector3) *parser.Atom {
+		return &parser.Atom{Element: atom.Element, X: pos.X, Y: pos.Y, Z: pos.Z}
+	}
+	pairEnergy := func(a1, a2 *parser.Atom) float64 {
+		return repackLJ(a1, a2, config.Cutoff)
+	}
+
+	self := make([][]float64, len(sites))
+	for i, site := range sites {
+		res := protein.Residues[site.residue]
+		self[i] = make([]float64, len(site.states))
+
+		for r, state := range site.states {
+			e := config.PriorWeight * state.prior
+			for k, atom := range site.atoms {
+				p := probe(atom, state.positions[k])
+				for _, other := range protein.Atoms {
+					if movable[other] || (other.ResSeq == res.SeqNum && other.ChainID == res.ChainID) {
+						continue // Side chains are pair terms; own residue is fixed geometry
+					}
+					e += pairEnergy(p, other)
+				}
+			}
+			self[i][r] = e
+		}
+	}
+
+	// Side chains reach at most ~8 Å from CA; skip pairs that cannot interact
+	reach := config.Cutoff + 16.0
+	caPos := func(site repackSite) geometry.Vector3 {
+		ca := protein.Residues[site.residue].CA
+		return geometry.Vector3{X: ca.X, Y: ca.Y, Z: ca.Z}
+	}
+
+	pair := make([][][][]float64, len(sites))
+	for i := range sites {
+		pair[i] = make([][][]float64, len(sites))
+		for j := i + 1; j < len(sites); j++ {
+			if caPos(sites[i]).Sub(caPos(sites[j])).Length() > reach {
+				continue
+			}
+
+			table := make([][]float64, len(sites[i].states))
+			for r, si := range sites[i].states {
+				table[r] = make([]float64, len(sites[j].states))
+				for s, sj := range sites[j].states {
+					e := 0.0
+					for k, a := range sites[i].atoms {
+						p1 := probe(a, si.positions[k])
+						for l, b := range sites[j].atoms {
+							e += pairEnergy(p1, probe(b, sj.positions[l]))
+						}
+					}
+					table[r][s] = e
+				}
+			}
+			pair[i][j] = table
+		}
+	}
+
+	return self, pair
+}
+
+// repackLJ is the AMBER-form 12-6 energy ε[(R*/r)¹² - 2(R*/r)⁶], capped
+//
+// PHYSICIST: R*_ij = R*_i/2 + R*_j/2 from physics.GetLennardJonesParams, so
+// contacts closer than van der Waals distance are repulsive (C···C < 3.8 Å)
+func repackLJ(a1, a2 *parser.Atom, cutoff float64) float64 {
+	dx := a1.X - a2.X
+	dy := a1.Y - a2.Y
+	dz := a1.Z - a2.Z
+	r2 := dx*dx + dy*dy + dz*dz
+	if r2 > cutoff*cutoff {
+		return 0.0
+	}
+
+	p1 := physics.GetLennardJonesParams(a1.Element)
+	p2 := physics.GetLennardJonesParams(a2.Element)
+	epsilon := math.Sqrt(p1.Epsilon * p2.Epsilon)
+	rMin := p1.Sigma + p2.Sigma
+
+	x6 := math.Pow(rMin*rMin/math.Max(r2, 1e-6), 3)
+	return math.Min(epsilon*(x6*x6-2.0*x6), repackPairCap)
+}
+
+// pairTerm returns E_pair(r_i, r_j) for any site order
+func pairTerm(pair [][][][]float64, i, r, j, s int) float64 {
+	if i > j {
+		i, r, j, s = j, s, i, r
+	}
+	if pair[i][j] == nil {
+		return 0.0
+	}
+	return pair[i][j][r][s]
+}
+
+// packingEnergy evaluates an assignment
+func packingEnergy(assign []int, self [][]float64, pair [][][][]float64) float64 {
+	e := 0.0
+	for i, r := range assign {
+		e += self[i][r]
+		for j := i + 1; j < len(assign); j++ {
+			e += pairTerm(pair, i, r, j, assign[j])
+		}
+	}
+	return e
+}
+
+// deadEndElimination applies Goldstein singles until nothing changes
+//
+// Rotamer r at i is eliminated if some t satisfies
+// E(r) - E(t) + Σ_j min_s [E(r, s) - E(t, s)] > 0
+// Returns the number of rotamers eliminated.
+func deadEndElimination(self [][]float64, pair [][][][]float64, alive [][]bool) int {
+	pruned := 0
+	for changed := true; changed; {
+		changed = false
+		for i := range self {
+			for r := range self[i] {
+				if !alive[i][r] {
+					continue
+				}
+				for t := range self[i] {
+					if t == r || !alive[i][t] {
+						continue
+					}
+					gap := self[i][r] - self[i][t]
+					for j := range self {
+						if j == i {
+							continue
+						}
+						minDiff := math.Inf(1)
+						for s := range self[j] {
+							if alive[j][s] {
+								minDiff = math.Min(minDiff, pairTerm(pair, i, r, j, s)-pairTerm(pair, i, t, j, s))
+							}
+						}
+						gap += minDiff
+					}
+					if gap > 1e-9 {
+						alive[i][r] = false
+						pruned++
+						changed = true
+						break
+					}
+				}
+			}
+		}
+	}
+	return pruned
+}
+
+// annealRotamers searches the surviving rotamers and returns the best assignment
+func annealRotamers(self [][]float64, pair [][][][]float64, alive [][]bool, config RepackConfig) []int {
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	// Boltzmann constant: k_B in kcal/(mol·K)
+	const kB = physics.BoltzmannKcal
+
+	options := make([][]int, len(self))
+	current := make([]int, len(self))
+	for i := range self {
+		for r := range self[i] {
+			if alive[i][r] {
+				options[i] = append(options[i], r)
+			}
+		}
+		// Start from the input if it survived, else the lowest self energy
+		current[i] = options[i][0]
+		for _, r := range options[i] {
+			if self[i][r] < self[i][current[i]] {
+				current[i] = r
+			}
+		}
+		if alive[i][0] {
+			current[i] = 0
+		}
+	}
+
+	// Energy change of setting site i to rotamer r
+	delta := func(assign []int, i, r int) float64 {
+		d := self[i][r] - self[i][assign[i]]
+		for j := range assign {
+			if j != i {
+				d += pairTerm(pair, i, r, j, assign[j]) - pairTerm(pair, i, assign[i], j, assign[j])
+			}
+		}
+		return d
+	}
+
+	energy := packingEnergy(current, self, pair)
+	best := append([]int(nil), current...)
+	bestEnergy := energy
+
+	ratio := config.TemperatureFinal / config.TemperatureInitial
+	for step := 0; step < config.NumSteps; step++ {
+		T := config.TemperatureInitial * math.Pow(ratio, float64(step)/float64(config.NumSteps))
+
+		i := rng.Intn(len(current))
+		if len(options[i]) < 2 {
+			continue
+		}
+		r := options[i][rng.Intn(len(options[i]))]
+		if r == current[i] {
+			continue
+		}
+
+		d := delta(current, i, r)
+		if d < 0 || rng.Float64() < math.Exp(-d/(kB*T)) {
+			current[i] = r
+			energy += d
+			if energy < bestEnergy {
+				bestEnergy = energy
+				copy(best, current)
+			}
+		}
+	}
+
+	// Greedy sweep: best rotamer per site given the rest, until stable
+	for changed := true; changed; {
+		changed = false
+		for i := range best {
+			for _, r := range options[i] {
+				if delta(best, i, r) < -1e-9 {
+					best[i] = r
+					changed = true
+				}
+			}
+		}
+	}
+
+	return best
+}
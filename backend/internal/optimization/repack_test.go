@@ -0,0 +1,156 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// buildRepackTestHelix builds an ideal α-helix with full side chains in
+// their most common rotamers
+func buildRepackTestHelix(t *testing.T, sequence string) *parser.Protein {
+	deg := math.Pi / 180.0
+	phi, psi := -57.0*deg, -47.0*deg
+
+	protein := &parser.Protein{Name: "repack_helix"}
+	serial := 1
+	addAtom := func(resName string, seq int, name, element string, pos geometry.Vector3) *parser.Atom {
+		atom := &parser.Atom{Serial: serial, Name: name, ResName: resName, ChainID: "A", ResSeq: seq,
+			X: pos.X, Y: pos.Y, Z: pos.Z, Element: element}
+		serial++
+		protein.Atoms = append(protein.Atoms, atom)
+		return atom
+	}
+
+	n := geometry.Vector3{X: 0, Y: 0, Z: 0}
+	ca := geometry.Vector3{X: geometry.BondN_CA, Y: 0, Z: 0}
+	a := (180.0 - geometry.AngleN_CA_C) * deg
+	c := ca.Add(geometry.Vector3{X: math.Cos(a), Y: math.Sin(a), Z: 0}.Scale(geometry.BondCA_C))
+
+	for i := 0; i < len(sequence); i++ {
+		if i > 0 {
+			prevN, prevCA, prevC := n, ca, c
+			n = placeSidechainAtom(prevN, prevCA, prevC, geometry.BondC_N, geometry.AngleCA_C_N*deg, psi)
+			ca = placeSidechainAtom(prevCA, prevC, n, geometry.BondN_CA, geometry.AngleC_N_CA*deg, math.Pi)
+			c = placeSidechainAtom(prevC, n, ca, geometry.BondCA_C, geometry.AngleN_CA_C*deg, phi)
+		}
+		o := placeSidechainAtom(n, ca, c, geometry.BondC_O, geometry.AngleCA_C_O*deg, psi+math.Pi)
+
+		resType := rotamerResidueType(string(sequence[i]))
+		seq := i + 1
+		res := &parser.Residue{Name: resType, SeqNum: seq, ChainID: "A",
+			N: addAtom(resType, seq, "N", "N", n), CA: addAtom(resType, seq, "CA", "C", ca),
+			C: addAtom(resType, seq, "C", "C", c), O: addAtom(resType, seq, "O", "O", o)}
+		protein.Residues = append(protein.Residues, res)
+
+		if resType == "GLY" {
+			continue
+		}
+		// L-amino acid Cβ: C-N-CA-CB ≈ -122.6°
+		cb := placeSidechainAtom(c, n, ca, 1.53, 110.5*deg, -122.6*deg)
+		addAtom(resType, seq, "CB", "C", cb)
+
+		rotamers := RotamersFor(resType)
+		if len(rotamers) == 0 {
+			continue
+		}
+		top := rotamers[0]
+		for _, rot := range rotamers {
+			if rot.Probability > top.Probability {
+				top = rot
+			}
+		}
+		placed := buildSidechain(resType, n, ca, cb, top.Chi)
+		for _, def := range sidechainTopology[resType] {
+			addAtom(resType, seq, def.Name, def.Element, placed[def.Name])
+		}
+	}
+
+	return protein
+}
+
+// sidechainClashes counts clashes involving side-chain atoms of the given residues
+func sidechainClashes(protein *parser.Protein, residues map[int]bool) int {
+	_, clashes := physics.CalculateClashscore(protein)
+	count := 0
+	for _, c := range clashes {
+		for _, atom := range []*parser.Atom{c.Atom1, c.Atom2} {
+			switch atom.Name {
+			case "N", "CA", "C", "O", "CB":
+				continue
+			}
+			if residues[atom.ResSeq] {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// TestRepackSideChainsRemovesClashes misplaces side chains into their
+// neighbors and checks repacking resolves the clashes
+func TestRepackSideChainsRemovesClashes(t *testing.T) {
+	protein := buildRepackTestHelix(t, "AKLVEFWRLIEAMKDLQA")
+	_, reference := physics.CalculateClashscore(protein)
+
+	// Eclipse every χ (0°) of three side chains, folding them onto the backbone
+	misplaced := map[int]bool{5: true, 9: true, 13: true}
+	for i, res := range protein.Residues {
+		if !misplaced[res.SeqNum] {
+			continue
+		}
+		resType := rotamerResidueType(res.Name)
+		sidechains := sidechainAtomsByResidue(protein)[i]
+		cb := sidechains["CB"]
+		n := geometry.Vector3{X: res.N.X, Y: res.N.Y, Z: res.N.Z}
+		ca := geometry.Vector3{X: res.CA.X, Y: res.CA.Y, Z: res.CA.Z}
+		placed := buildSidechain(resType, n, ca, geometry.Vector3{X: cb.X, Y: cb.Y, Z: cb.Z},
+			[]float64{0, 0, 0, 0})
+		for name, pos := range placed {
+			atom := sidechains[name]
+			atom.X, atom.Y, atom.Z = pos.X, pos.Y, pos.Z
+		}
+	}
+
+	before := sidechainClashes(protein, misplaced)
+	if before == 0 {
+		t.Fatalf("Misplaced side chains do not clash; test setup is wrong")
+	}
+
+	result, err := RepackSideChainsWithResult(protein, DefaultRepackConfig())
+	if err != nil {
+		t.Fatalf("Repacking failed: %v", err)
+	}
+	after := sidechainClashes(protein, misplaced)
+	_, total := physics.CalculateClashscore(protein)
+
+	t.Logf("Repacked %d residues, %d changed, %d rotamers pruned by DEE",
+		result.ResiduesRepacked, result.ResiduesChanged, result.RotamersPruned)
+	t.Logf("Packing energy %.2f → %.2f kcal/mol (Δ = %.2f)", result.InitialEnergy, result.FinalEnergy, result.EnergyImprovement)
+	t.Logf("Clashes on misplaced residues: %d → %d; total %d (reference %d)", before, after, len(total), len(reference))
+
+	if after != 0 {
+		t.Errorf("%d clashes remain on repacked residues", after)
+	}
+	if result.ResiduesChanged < len(misplaced) {
+		t.Errorf("Only %d residues changed, expected at least %d", result.ResiduesChanged, len(misplaced))
+	}
+	if result.EnergyImprovement <= 0 {
+		t.Errorf("Packing energy did not improve: %.2f → %.2f", result.InitialEnergy, result.FinalEnergy)
+	}
+
+	// Backbone and Cβ never move
+	ref := buildRepackTestHelix(t, "AKLVEFWRLIEAMKDLQA")
+	for i, atom := range protein.Atoms {
+		switch atom.Name {
+		case "N", "CA", "C", "O", "CB":
+			if atom.X != ref.Atoms[i].X || atom.Y != ref.Atoms[i].Y || atom.Z != ref.Atoms[i].Z {
+				t.Fatalf("Backbone atom %s %d moved", atom.Name, atom.ResSeq)
+			}
+		}
+	}
+}
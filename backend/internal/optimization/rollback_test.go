@@ -0,0 +1,37 @@
+package optimization
+
+import (
+	"math"
+	"testing"
+)
+
+// TestQuaternionLBFGSRollsBackDivergence takes fixed steps far too long for
+// the surface, so a step throws the helix into a clash, and checks the
+// protein comes back holding a structure no worse than the start
+func TestQuaternionLBFGSRollsBackDivergence(t *testing.T) {
+	protein := buildBasinHoppingTestPeptide(t)
+
+	config := DefaultQuaternionLBFGSConfig()
+	config.UseLineSearch = false
+	config.StepSize = 10.0 // Radians per unit of gradient: wildly overshoots
+
+	result, err := MinimizeQuaternionLBFGS(protein, config)
+	if err != nil {
+		t.Fatalf("L-BFGS failed: %v", err)
+	}
+	t.Logf("E %.2f → %.2f kcal/mol after %d iterations, rolled back %v (%s)",
+		result.InitialEnergy, result.FinalEnergy, result.Iterations, result.RolledBack, result.ConvergenceReason)
+
+	if !result.RolledBack {
+		t.Errorf("Overshooting run was not rolled back")
+	}
+	if result.FinalEnergy > result.InitialEnergy {
+		t.Errorf("Final energy %.2f above initial %.2f kcal/mol", result.FinalEnergy, result.InitialEnergy)
+	}
+
+	// The protein holds the structure FinalEnergy describes
+	energy := evaluateEnergyForProtein(protein, config)
+	if math.Abs(energy-result.FinalEnergy) > 1e-6*math.Max(1, math.Abs(energy)) {
+		t.Errorf("Protein energy %.4f does not match final energy %.4f", energy, result.FinalEnergy)
+	}
+}
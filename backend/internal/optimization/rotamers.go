@@ -0,0 +1,335 @@
+// Package optimization - Side-chain rotamer library and builder
+//
+// Rotamers are the discrete, strongly populated χ-angle combinations of each
+// side chain. Repacking picks one rotamer per residue, so the library and a
+// builder that turns χ angles into coordinates are all it needs.
+//
+// BIOCHEMIST: Modal χ values and populations of the common rotamers of the
+// 17 flexible residue types (Ala and Gly have no χ; Pro ring closure is left
+// to the input structure)
+// PHYSICIST: Side-chain atoms are placed from ideal internal coordinates
+// (bond length, bond angle, dihedral) off atoms already placed, starting from
+// the fixed N, CA, CB
+// MATHEMATICIAN: NeRF placement; branch atoms sit at χ ± 120° (tetrahedral)
+// or χ + 180° (planar)
+// ETHICIST: χ values are rounded modal values and populations are rounded
+// percentages; the rarest rotamers of the published library are omitted
+//
+// CITATION:
+// Lovell, S. C., Word, J. M., Richardson, J. S., Richardson, D. C. (2000). "The penultimate
+// rotamer library." Proteins 40(3): 389-408.
+//
+// Engh, R. A., Huber, R. (1991). "Accurate bond and angle parameters for X-ray protein
+// structure refinement." Acta Cryst. A47: 392-400.
+package optimization
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Rotamer is one side-chain conformation: χ angles (degrees) and population
+type Rotamer struct {
+	Name        string    // Lovell et al. name (e.g. "mt", "tp")
+	Chi         []float64 // χ1, χ2, ... in degrees
+	Probability float64   // Population within its residue type (0-1)
+}
+
+// sidechainAtomDef places one side-chain atom from three placed atoms
+//
+// Torsion is measured ref1-ref2-ref3-atom. If Chi >= 0 the torsion is
+// χ[Chi] + Offset, otherwise Offset alone.
+type sidechainAtomDef struct {
+	Name             string
+	Element          string
+	Ref1, Ref2, Ref3 string
+	Bond             float64 // Å
+	Angle            float64 // degrees
+	Chi              int
+	Offset           float64 // degrees
+}
+
+// sidechainTopology lists atoms beyond CB in build order
+var sidechainTopology = map[string][]sidechainAtomDef{
+	"SER": {
+		{"OG", "O", "N", "CA", "CB", 1.417, 110.8, 0, 0},
+	},
+	"CYS": {
+		{"SG", "S", "N", "CA", "CB", 1.808, 113.8, 0, 0},
+	},
+	"THR": {
+		{"OG1", "O", "N", "CA", "CB", 1.433, 109.2, 0, 0},
+		{"CG2", "C", "N", "CA", "CB", 1.521, 111.1, 0, -120},
+	},
+	"VAL": {
+		{"CG1", "C", "N", "CA", "CB", 1.527, 110.7, 0, 0},
+		{"CG2", "C", "N", "CA", "CB", 1.527, 110.4, 0, -120},
+	},
+	"LEU": {
+		{"CG", "C", "N", "CA", "CB", 1.530, 116.1, 0, 0},
+		{"CD1", "C", "CA", "CB", "CG", 1.524, 110.3, 1, 0},
+		{"CD2", "C", "CA", "CB", "CG", 1.525, 110.6, 1, 120},
+	},
+	"ILE": {
+		{"CG1", "C", "N", "CA", "CB", 1.530, 110.4, 0, 0},
+		{"CG2", "C", "N", "CA", "CB", 1.530, 110.5, 0, -120},
+		{"CD1", "C", "CA", "CB", "CG1", 1.520, 114.0, 1, 0},
+	},
+	"ASP": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 113.0, 0, 0},
+		{"OD1", "O", "CA", "CB", "CG", 1.250, 119.2, 1, 0},
+		{"OD2", "O", "CA", "CB", "CG", 1.250, 118.2, 1, 180},
+	},
+	"ASN": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 112.6, 0, 0},
+		{"OD1", "O", "CA", "CB", "CG", 1.230, 120.8, 1, 0},
+		{"ND2", "N", "CA", "CB", "CG", 1.330, 116.4, 1, 180},
+	},
+	"GLU": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 114.1, 0, 0},
+		{"CD", "C", "CA", "CB", "CG", 1.520, 113.8, 1, 0},
+		{"OE1", "O", "CB", "CG", "CD", 1.250, 119.0, 2, 0},
+		{"OE2", "O", "CB", "CG", "CD", 1.250, 118.1, 2, 180},
+	},
+	"GLN": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 114.1, 0, 0},
+		{"CD", "C", "CA", "CB", "CG", 1.520, 112.8, 1, 0},
+		{"OE1", "O", "CB", "CG", "CD", 1.230, 120.9, 2, 0},
+		{"NE2", "N", "CB", "CG", "CD", 1.330, 116.5, 2, 180},
+	},
+	"MET": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 113.7, 0, 0},
+		{"SD", "S", "CA", "CB", "CG", 1.810, 112.7, 1, 0},
+		{"CE", "C", "CB", "CG", "SD", 1.790, 100.6, 2, 0},
+	},
+	"LYS": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 113.8, 0, 0},
+		{"CD", "C", "CA", "CB", "CG", 1.520, 111.5, 1, 0},
+		{"CE", "C", "CB", "CG", "CD", 1.520, 111.7, 2, 0},
+		{"NZ", "N", "CG", "CD", "CE", 1.490, 111.9, 3, 0},
+	},
+	"ARG": {
+		{"CG", "C", "N", "CA", "CB", 1.520, 113.8, 0, 0},
+		{"CD", "C", "CA", "CB", "CG", 1.520, 111.8, 1, 0},
+		{"NE", "N", "CB", "CG", "CD", 1.460, 111.7, 2, 0},
+		{"CZ", "C", "CG", "CD", "NE", 1.330, 124.8, 3, 0},
+		{"NH1", "N", "CD", "NE", "CZ", 1.330, 120.6, -1, 0},
+		{"NH2", "N", "CD", "NE", "CZ", 1.330, 119.9, -1, 180},
+	},
+	"HIS": {
+		{"CG", "C", "N", "CA", "CB", 1.500, 113.7, 0, 0},
+		{"ND1", "N", "CA", "CB", "CG", 1.380, 122.7, 1, 0},
+		{"CD2", "C", "CA", "CB", "CG", 1.360, 131.0, 1, 180},
+		{"CE1", "C", "CB", "CG", "ND1", 1.320, 109.0, -1, 180},
+		{"NE2", "N", "CB", "CG", "CD2", 1.370, 107.0, -1, 180},
+	},
+	"PHE": {
+		{"CG", "C", "N", "CA", "CB", 1.500, 113.8, 0, 0},
+		{"CD1", "C", "CA", "CB", "CG", 1.390, 120.0, 1, 0},
+		{"CD2", "C", "CA", "CB", "CG", 1.390, 120.0, 1, 180},
+		{"CE1", "C", "CB", "CG", "CD1", 1.390, 120.0, -1, 180},
+		{"CE2", "C", "CB", "CG", "CD2", 1.390, 120.0, -1, 180},
+		{"CZ", "C", "CG", "CD1", "CE1", 1.390, 120.0, -1, 0},
+	},
+	"TYR": {
+		{"CG", "C", "N", "CA", "CB", 1.510, 113.8, 0, 0},
+		{"CD1", "C", "CA", "CB", "CG", 1.390, 120.8, 1, 0},
+		{"CD2", "C", "CA", "CB", "CG", 1.390, 121.2, 1, 180},
+		{"CE1", "C", "CB", "CG", "CD1", 1.390, 121.2, -1, 180},
+		{"CE2", "C", "CB", "CG", "CD2", 1.390, 121.2, -1, 180},
+		{"CZ", "C", "CG", "CD1", "CE1", 1.390, 119.6, -1, 0},
+		{"OH", "O", "CD1", "CE1", "CZ", 1.380, 119.9, -1, 180},
+	},
+	"TRP": {
+		{"CG", "C", "N", "CA", "CB", 1.500, 114.1, 0, 0},
+		{"CD1", "C", "CA", "CB", "CG", 1.370, 127.1, 1, 0},
+		{"CD2", "C", "CA", "CB", "CG", 1.430, 126.6, 1, 180},
+		{"NE1", "N", "CB", "CG", "CD1", 1.380, 110.2, -1, 180},
+		{"CE2", "C", "CB", "CG", "CD2", 1.400, 107.2, -1, 180},
+		{"CE3", "C", "CB", "CG", "CD2", 1.400, 133.9, -1, 0},
+		{"CZ2", "C", "CG", "CD2", "CE2", 1.400, 122.4, -1, 180},
+		{"CZ3", "C", "CG", "CD2", "CE3", 1.390, 118.7, -1, 180},
+		{"CH2", "C", "CD2", "CE2", "CZ2", 1.370, 117.5, -1, 0},
+	},
+}
+
+// rotamerLibrary holds the common rotamers per residue type
+var rotamerLibrary = map[string][]Rotamer{
+	"SER": {
+		{"p", []float64{62}, 0.48},
+		{"t", []float64{-177}, 0.22},
+		{"m", []float64{-65}, 0.29},
+	},
+	"CYS": {
+		{"p", []float64{62}, 0.23},
+		{"t", []float64{-177}, 0.26},
+		{"m", []float64{-65}, 0.50},
+	},
+	"THR": {
+		{"p", []float64{62}, 0.49},
+		{"t", []float64{-175}, 0.07},
+		{"m", []float64{-65}, 0.43},
+	},
+	"VAL": {
+		{"p", []float64{63}, 0.06},
+		{"t", []float64{175}, 0.73},
+		{"m", []float64{-60}, 0.20},
+	},
+	"LEU": {
+		{"pp", []float64{62, 80}, 0.01},
+		{"tp", []float64{-177, 65}, 0.29},
+		{"tt", []float64{-172, 145}, 0.02},
+		{"mp", []float64{-85, 65}, 0.02},
+		{"mt", []float64{-65, 175}, 0.59},
+	},
+	"ILE": {
+		{"pp", []float64{62, 100}, 0.01},
+		{"pt", []float64{62, 170}, 0.13},
+		{"tp", []float64{-177, 66}, 0.02},
+		{"tt", []float64{-177, 165}, 0.08},
+		{"mp", []float64{-65, 100}, 0.01},
+		{"mt", []float64{-65, 170}, 0.60},
+		{"mm", []float64{-57, -60}, 0.15},
+	},
+	"ASP": {
+		{"p-10", []float64{62, -10}, 0.10},
+		{"p30", []float64{62, 30}, 0.09},
+		{"t0", []float64{-177, 0}, 0.21},
+		{"t70", []float64{-177, 65}, 0.04},
+		{"m-20", []float64{-70, -15}, 0.51},
+	},
+	"ASN": {
+		{"p-10", []float64{62, -10}, 0.07},
+		{"p30", []float64{62, 30}, 0.09},
+		{"t-20", []float64{-174, -20}, 0.12},
+		{"t30", []float64{-177, 30}, 0.15},
+		{"m-20", []float64{-65, -20}, 0.28},
+		{"m-80", []float64{-65, -75}, 0.13},
+		{"m120", []float64{-65, 120}, 0.09},
+	},
+	"GLU": {
+		{"pt-20", []float64{62, 180, -20}, 0.05},
+		{"tp10", []float64{-177, 65, 10}, 0.07},
+		{"tt0", []float64{-177, 180, 0}, 0.24},
+		{"mt-10", []float64{-65, 180, -10}, 0.33},
+		{"mp0", []float64{-65, 85, 0}, 0.05},
+		{"mm-40", []float64{-65, -65, -40}, 0.11},
+	},
+	"GLN": {
+		{"pt20", []float64{62, 180, 20}, 0.04},
+		{"tp40", []float64{-177, 65, 40}, 0.06},
+		{"tt0", []float64{-177, 180, 0}, 0.16},
+		{"mt-30", []float64{-65, 180, -25}, 0.38},
+		{"mm-40", []float64{-65, -65, -40}, 0.16},
+	},
+	"MET": {
+		{"ptm", []float64{62, 180, -75}, 0.05},
+		{"tpp", []float64{-177, 65, 75}, 0.10},
+		{"ttp", []float64{-177, 180, 75}, 0.07},
+		{"ttm", []float64{-177, 180, -75}, 0.03},
+		{"mtp", []float64{-67, 180, 75}, 0.17},
+		{"mtt", []float64{-67, 180, 180}, 0.11},
+		{"mtm", []float64{-67, 180, -75}, 0.09},
+		{"mmm", []float64{-65, -65, -70}, 0.19},
+	},
+	"LYS": {
+		{"tptt", []float64{-177, 68, 180, 180}, 0.04},
+		{"tttt", []float64{-177, 180, 180, 180}, 0.13},
+		{"tttm", []float64{-177, 180, 180, -65}, 0.03},
+		{"mttt", []float64{-62, 180, 180, 180}, 0.22},
+		{"mtmt", []float64{-62, 180, -68, 180}, 0.03},
+		{"mmtt", []float64{-62, -68, 180, 180}, 0.06},
+		{"mttm", []float64{-62, 180, 180, -65}, 0.05},
+		{"mttp", []float64{-62, 180, 180, 65}, 0.05},
+	},
+	"ARG": {
+		{"ttp170", []float64{-177, 180, 65, 175}, 0.03},
+		{"ttt180", []float64{-177, 180, 180, 180}, 0.05},
+		{"tpt170", []float64{-177, 65, 180, 175}, 0.02},
+		{"mtp85", []float64{-67, 180, 65, 85}, 0.03},
+		{"mtt180", []float64{-67, 180, 180, 180}, 0.09},
+		{"mtt85", []float64{-67, 180, 180, 85}, 0.06},
+		{"mtt-85", []float64{-67, 180, 180, -85}, 0.04},
+		{"mtm180", []float64{-67, 180, -65, 175}, 0.03},
+		{"mmt-85", []float64{-62, -68, 180, -85}, 0.03},
+	},
+	"HIS": {
+		{"p-80", []float64{62, -75}, 0.09},
+		{"p80", []float64{62, 80}, 0.04},
+		{"t-160", []float64{-177, -165}, 0.05},
+		{"t-80", []float64{-177, -80}, 0.11},
+		{"t60", []float64{-177, 60}, 0.16},
+		{"m-70", []float64{-65, -70}, 0.29},
+		{"m170", []float64{-65, 165}, 0.07},
+		{"m80", []float64{-65, 80}, 0.13},
+	},
+	"PHE": {
+		{"p90", []float64{62, 90}, 0.13},
+		{"t80", []float64{-177, 80}, 0.33},
+		{"m-85", []float64{-65, -85}, 0.44},
+		{"m-30", []float64{-65, -30}, 0.09},
+	},
+	"TYR": {
+		{"p90", []float64{62, 90}, 0.13},
+		{"t80", []float64{-177, 80}, 0.34},
+		{"m-85", []float64{-65, -85}, 0.43},
+		{"m-30", []float64{-65, -30}, 0.09},
+	},
+	"TRP": {
+		{"p-90", []float64{62, -90}, 0.11},
+		{"p90", []float64{62, 90}, 0.04},
+		{"t-105", []float64{-177, -105}, 0.16},
+		{"t90", []float64{-177, 90}, 0.18},
+		{"m-90", []float64{-65, -90}, 0.11},
+		{"m0", []float64{-65, -5}, 0.06},
+		{"m95", []float64{-65, 95}, 0.34},
+	},
+}
+
+// RotamersFor returns the library rotamers for a residue name (one- or
+// three-letter); nil for Ala, Gly, Pro and unknown residues
+func RotamersFor(resName string) []Rotamer {
+	return rotamerLibrary[rotamerResidueType(resName)]
+}
+
+// rotamerResidueType normalizes a residue name to its library key:
+// one-letter names (from coordinate builders) via parser.OneToThree,
+// three-letter names as-is
+func rotamerResidueType(resName string) string {
+	if len(resName) == 1 {
+		return parser.OneToThree(resName[0])
+	}
+	return resName
+}
+
+// buildSidechain places the side-chain atoms beyond CB for the given χ
+// angles (degrees), starting from backbone N, CA and CB positions
+//
+// Returns positions keyed by atom name.
+func buildSidechain(resType string, n, ca, cb geometry.Vector3, chi []float64) map[string]geometry.Vector3 {
+	placed := map[string]geometry.Vector3{"N": n, "CA": ca, "CB": cb}
+
+	for _, def := range sidechainTopology[resType] {
+		torsion := def.Offset
+		if def.Chi >= 0 {
+			torsion += chi[def.Chi]
+		}
+		placed[def.Name] = placeSidechainAtom(placed[def.Ref1], placed[def.Ref2], placed[def.Ref3],
+			def.Bond, def.Angle*math.Pi/180.0, torsion*math.Pi/180.0)
+	}
+
+	delete(placed, "N")
+	delete(placed, "CA")
+	delete(placed, "CB")
+	return placed
+}
+
+// placeSidechainAtom places d with |cd| = bond, ∠bcd = angle and
+// dihedral(a, b, c, d) = torsion (radians, IUPAC sign)
+func placeSidechainAtom(a, b, c geometry.Vector3, bond, angle, torsion float64) geometry.Vector3 {
+	d := geometry.PlaceAtom([3]float64{a.X, a.Y, a.Z}, [3]float64{b.X, b.Y, b.Z}, [3]float64{c.X, c.Y, c.Z},
+		bond, angle, torsion)
+	return geometry.Vector3{X: d[0], Y: d[1], Z: d[2]}
+}
@@ -21,11 +21,13 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
 )
 
 // SimulatedAnnealingConfig holds SA optimization parameters
@@ -37,9 +39,19 @@ type SimulatedAnnealingConfig struct {
 	// Number of SA steps
 	NumSteps int
 
-	// Cooling schedule: "exponential", "linear", "geometric", "vedic_phi"
+	// Cooling schedule: "exponential", "linear", "geometric", "vedic_phi",
+	// or one of the reheating schedules "sawtooth" and "adaptive_reheat"
 	CoolingSchedule string
 
+	// Reheating (sawtooth, adaptive_reheat): each reheat restarts cooling
+	// from ReheatFraction × TemperatureInitial. Sawtooth splits the run
+	// into NumCycles exponential cooling cycles; adaptive_reheat reheats
+	// once the best energy has not improved by more than EnergyTol for
+	// ReheatPatience steps (keep it below PlateauSteps)
+	NumCycles      int
+	ReheatFraction float64
+	ReheatPatience int
+
 	// Perturbation size (Angstroms)
 	// At high T: large perturbations, low T: small perturbations
 	PerturbationInitial float64
@@ -50,6 +62,12 @@ type SimulatedAnnealingConfig struct {
 	RefinementThreshold float64 // Temperature below which to use L-BFGS
 	LBFGSSteps         int      // Number of L-BFGS iterations per refinement
 
+	// Plateau stop: end the run once the best energy has not improved by
+	// more than EnergyTol (kcal/mol) for PlateauSteps consecutive steps
+	// (0 = always run NumSteps)
+	PlateauSteps int
+	EnergyTol    float64
+
 	// Energy calculation cutoffs
 	VdWCutoff  float64
 	ElecCutoff float64
@@ -59,6 +77,20 @@ type SimulatedAnnealingConfig struct {
 
 	// Verbose logging
 	Verbose bool
+
+	// Optional per-step hook (nil: none); see IterationCallback
+	OnIteration IterationCallback
+
+	// Optional energy to anneal (nil: physics total energy with the
+	// cutoffs above). The Cartesian moves use its Energy; L-BFGS refinement
+	// then minimizes it in dihedral space (MinimizeQuaternionLBFGS), so the
+	// whole trajectory follows one objective
+	Energy EnergyFunction
+
+	// Trajectory recording: snapshot every TrajectoryStride accepted steps
+	// (0 = off), keeping at most TrajectoryMaxFrames (<= 0: unbounded)
+	TrajectoryStride    int
+	TrajectoryMaxFrames int
 }
 
 // DefaultSimulatedAnnealingConfig returns recommended SA parameters
@@ -68,15 +100,21 @@ func DefaultSimulatedAnnealingConfig() SimulatedAnnealingConfig {
 		TemperatureFinal:    1.0,             // 1 K (low exploitation)
 		NumSteps:            5000,            // 5000 SA steps
 		CoolingSchedule:     "vedic_phi",     // Golden ratio cooling
+		NumCycles:           4,               // Sawtooth: 4 cooling cycles
+		ReheatFraction:      0.5,             // Reheat to T_initial / 2
+		ReheatPatience:      250,             // adaptive_reheat: 250 stagnant steps
 		PerturbationInitial: 2.0,             // 2.0 Å at high T
 		PerturbationFinal:   0.1,             // 0.1 Å at low T
 		UseLBFGSRefinement:  true,            // Hybrid SA+LBFGS
 		RefinementThreshold: 50.0,            // Refine below 50 K
 		LBFGSSteps:          50,              // 50 L-BFGS iterations
+		PlateauSteps:        1000,            // Stop after 1000 steps without progress
+		EnergyTol:           0.01,            // 0.01 kcal/mol counts as progress
 		VdWCutoff:           10.0,
 		ElecCutoff:          12.0,
 		Seed:                42,
 		Verbose:             false,
+		TrajectoryMaxFrames: 1000,
 	}
 }
 
@@ -97,10 +135,14 @@ type SimulatedAnnealingResult struct {
 	// Convergence
 	Converged         bool
 	Reason            string
+	Reheats           int // Reheats of the sawtooth or adaptive_reheat schedule
 
 	// Performance
 	FunctionEvaluations int
 	LBFGSRefinements    int
+
+	// Recorded frames (nil unless TrajectoryStride > 0)
+	Trajectory *parser.Trajectory
 }
 
 // SimulatedAnnealing performs simulated annealing optimization
@@ -128,16 +170,34 @@ type SimulatedAnnealingResult struct {
 // - Cooling is infinitely slow: T(t) > C / log(1+t)
 // - In practice: Use finite cooling for efficiency
 func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig) (*SimulatedAnnealingResult, error) {
+	return SimulatedAnnealingCtx(context.Background(), protein, config)
+}
+
+// SimulatedAnnealingCtx is SimulatedAnnealing with cancellation
+//
+// ctx is checked at the top of every step. On cancellation the best structure
+// found so far is copied into protein and the partial result is returned
+// together with ctx.Err().
+func SimulatedAnnealingCtx(ctx context.Context, protein *parser.Protein, config SimulatedAnnealingConfig) (*SimulatedAnnealingResult, error) {
 	if protein == nil {
 		return nil, fmt.Errorf("protein is nil")
 	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	result := &SimulatedAnnealingResult{
+		Trajectory: parser.NewTrajectory(config.TrajectoryStride, config.TrajectoryMaxFrames),
+	}
 
-	result := &SimulatedAnnealingResult{}
+	energyFn := config.Energy
+	if energyFn == nil {
+		energyFn = EnergyFunc(func(p *parser.Protein) float64 {
+			return evaluateEnergy(p, LBFGSConfig{VdWCutoff: config.VdWCutoff, ElecCutoff: config.ElecCutoff})
+		})
+	}
 
 	// Calculate initial energy
-	currentEnergy := evaluateEnergy(protein, LBFGSConfig{VdWCutoff: config.VdWCutoff, ElecCutoff: config.ElecCutoff})
+	currentEnergy := energyFn.Energy(protein)
 	result.InitialEnergy = currentEnergy
 	result.BestEnergy = currentEnergy
 	result.FunctionEvaluations = 1
@@ -149,27 +209,51 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 		fmt.Printf("Simulated Annealing: Initial energy = %.2f kcal/mol\n", currentEnergy)
 	}
 
-	// Boltzmann constant: k_B = 0.001987 kcal/(mol·K)
-	const kB = 0.001987
+	// Boltzmann constant: k_B in kcal/(mol·K)
+	const kB = physics.BoltzmannKcal
 
 	lastRefinement := 0 // Track when we last did L-BFGS refinement
 
+	// Plateau tracking: best energy at the last improvement beyond EnergyTol
+	plateauEnergy := result.BestEnergy
+	lastImprovement := 0 // Steps completed at that improvement
+
+	// Keep the caller's structure: protein is reassigned to accepted proposals
+	target := protein
+
+	// Schedule clock: the step the temperature is computed for, which
+	// adaptive_reheat winds back to reheatStep on stagnation
+	scheduleStep := 0
+	lastReheat := 0
+	reheating := config.CoolingSchedule == "sawtooth" || config.CoolingSchedule == "adaptive_reheat"
+
 	// Simulated annealing loop
+	var cancelErr error
+	stopped := false
 	for step := 0; step < config.NumSteps; step++ {
+		if err := ctx.Err(); err != nil {
+			cancelErr = err
+			result.Reason = fmt.Sprintf("Cancelled at step %d: %v", step, err)
+			break
+		}
+
 		result.Steps = step + 1
 
 		// Calculate temperature for this step
-		T := getTemperatureSchedule(step, config)
+		T := getTemperatureSchedule(scheduleStep, config)
+		if config.CoolingSchedule == "sawtooth" && step > 0 && sawtoothCycleStart(step, config) {
+			result.Reheats++
+		}
 
 		// Calculate perturbation size (decreases with temperature)
 		perturbSize := getPerturbationSize(step, config)
 
 		// Perturb structure
 		proposedProtein := cloneProtein(protein)
-		perturbStructure(proposedProtein, perturbSize)
+		perturbStructure(proposedProtein, perturbSize, rng)
 
 		// Calculate proposed energy
-		proposedEnergy := evaluateEnergy(proposedProtein, LBFGSConfig{VdWCutoff: config.VdWCutoff, ElecCutoff: config.ElecCutoff})
+		proposedEnergy := energyFn.Energy(proposedProtein)
 		result.FunctionEvaluations++
 
 		// Metropolis acceptance criterion
@@ -182,7 +266,7 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 		} else {
 			// Worse energy: accept with probability exp(-ΔE/kT)
 			acceptProb := math.Exp(-deltaE / (kB * T))
-			if rand.Float64() < acceptProb {
+			if rng.Float64() < acceptProb {
 				accepted = true
 			}
 		}
@@ -192,6 +276,7 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 			result.AcceptedSteps++
 			protein = proposedProtein
 			currentEnergy = proposedEnergy
+			result.Trajectory.Record(result.AcceptedSteps, step, currentEnergy, protein)
 
 			// Track best
 			if currentEnergy < result.BestEnergy {
@@ -210,14 +295,10 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 					fmt.Printf("  Step %d (T=%.1f K): Refining with L-BFGS...\n", step, T)
 				}
 
-				lbfgsConfig := DefaultLBFGSConfig()
-				lbfgsConfig.MaxIterations = config.LBFGSSteps
-				lbfgsConfig.Verbose = false
-
-				lbfgsResult, err := MinimizeLBFGS(protein, lbfgsConfig)
-				if err == nil && lbfgsResult.Converged {
-					currentEnergy = lbfgsResult.FinalEnergy
-					result.FunctionEvaluations += lbfgsResult.FunctionEvaluations
+				refinedEnergy, evaluations, iterations, ok := refineAnnealedStructure(protein, config)
+				if ok {
+					currentEnergy = refinedEnergy
+					result.FunctionEvaluations += evaluations
 					result.LBFGSRefinements++
 
 					// Update best if improved
@@ -227,7 +308,7 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 					}
 
 					if config.Verbose {
-						fmt.Printf("    L-BFGS: E = %.2f kcal/mol (%d iters)\n", currentEnergy, lbfgsResult.Iterations)
+						fmt.Printf("    L-BFGS: E = %.2f kcal/mol (%d iters)\n", currentEnergy, iterations)
 					}
 				}
 
@@ -242,8 +323,41 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 				step, T, currentEnergy, result.BestEnergy, acceptRate, perturbSize)
 		}
 
+		if config.OnIteration != nil && !config.OnIteration(step, currentEnergy, cloneProtein(protein)) {
+			stopped = true
+			result.Reason = fmt.Sprintf("Stopped by OnIteration at step %d", step)
+			break
+		}
+
+		// Plateau: the best energy stopped improving
+		if result.BestEnergy < plateauEnergy-config.EnergyTol {
+			plateauEnergy = result.BestEnergy
+			lastImprovement = step + 1
+		}
+
+		// Adaptive reheat: stagnation since the last improvement or reheat
+		scheduleStep++
+		if config.CoolingSchedule == "adaptive_reheat" && config.ReheatPatience > 0 &&
+			step+1-max(lastImprovement, lastReheat) >= config.ReheatPatience {
+			scheduleStep = min(scheduleStep, reheatStep(config))
+			lastReheat = step + 1
+			result.Reheats++
+			if config.Verbose {
+				fmt.Printf("  Step %d: best energy stagnant for %d steps, reheating to %.1f K\n",
+					step, config.ReheatPatience, getTemperatureSchedule(scheduleStep, config))
+			}
+		}
+
+		if config.PlateauSteps > 0 && step+1-lastImprovement >= config.PlateauSteps {
+			result.Converged = true
+			result.Reason = fmt.Sprintf("Converged at step %d: best energy %.4f improved by less than %.4f in %d steps",
+				step, result.BestEnergy, config.EnergyTol, config.PlateauSteps)
+			break
+		}
+
 		// Early stopping: if temperature is very low and no improvement for 500 steps
-		if T < config.TemperatureFinal*2.0 && step-lastRefinement > 500 {
+		// (reheating schedules pass through low temperatures on purpose)
+		if !reheating && T < config.TemperatureFinal*2.0 && step-lastRefinement > 500 {
 			// Check if best energy hasn't improved
 			stagnant := math.Abs(currentEnergy-result.BestEnergy) < 0.1
 
@@ -264,12 +378,12 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 		result.AcceptanceRate = float64(result.AcceptedSteps) / float64(totalSteps)
 	}
 
-	if !result.Converged {
+	if !result.Converged && cancelErr == nil && !stopped {
 		result.Reason = fmt.Sprintf("Completed %d SA steps", config.NumSteps)
 	}
 
 	// Apply best structure
-	copyProteinCoordinates(bestProtein, protein)
+	copyProteinCoordinates(bestProtein, target)
 
 	if config.Verbose {
 		fmt.Printf("\nSimulated Annealing Complete:\n")
@@ -279,7 +393,40 @@ func SimulatedAnnealing(protein *parser.Protein, config SimulatedAnnealingConfig
 		fmt.Printf("  L-BFGS refinements: %d\n", result.LBFGSRefinements)
 	}
 
-	return result, nil
+	return result, cancelErr
+}
+
+// refineAnnealedStructure runs the low-temperature L-BFGS refinement of
+// protein in place and returns its energy under the annealed objective
+//
+// Without config.Energy this is the Cartesian physics minimization
+// (MinimizeLBFGS, kept only if it converged); with one, the dihedral-space
+// MinimizeQuaternionLBFGS on that same energy, which never ends above its
+// start. ok is false if the refinement was rejected.
+func refineAnnealedStructure(protein *parser.Protein, config SimulatedAnnealingConfig) (energy float64, evaluations, iterations int, ok bool) {
+	if config.Energy == nil {
+		lbfgsConfig := DefaultLBFGSConfig()
+		lbfgsConfig.MaxIterations = config.LBFGSSteps
+		lbfgsConfig.Verbose = false
+
+		lbfgsResult, err := MinimizeLBFGS(protein, lbfgsConfig)
+		if err != nil || !lbfgsResult.Converged {
+			return 0, 0, 0, false
+		}
+		return lbfgsResult.FinalEnergy, lbfgsResult.FunctionEvaluations, lbfgsResult.Iterations, true
+	}
+
+	lbfgsConfig := DefaultQuaternionLBFGSConfig()
+	lbfgsConfig.MaxIterations = config.LBFGSSteps
+	lbfgsConfig.Energy = config.Energy
+	lbfgsConfig.Verbose = false
+
+	lbfgsResult, err := MinimizeQuaternionLBFGS(protein, lbfgsConfig)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	// Re-evaluate so the reported energy is exactly the annealed objective
+	return config.Energy.Energy(protein), lbfgsResult.FunctionEvaluations + 1, lbfgsResult.Iterations, true
 }
 
 // getTemperatureSchedule calculates temperature for SA step
@@ -316,6 +463,21 @@ func getTemperatureSchedule(step int, config SimulatedAnnealingConfig) float64 {
 		alpha := (T0 - Tf) / (Tf * n)
 		return T0 / (1.0 + alpha*t)
 
+	case "sawtooth":
+		// NumCycles exponential cycles, T_0 → T_f then f×T_0 → T_f, ...
+		length := sawtoothCycleLength(config)
+		start := T0
+		if step >= length {
+			start = config.ReheatFraction * T0
+		}
+		return start * math.Pow(Tf/start, float64(step%length)/float64(length))
+
+	case "adaptive_reheat":
+		// Exponential cooling; the SA loop winds the step back to
+		// reheatStep to reheat
+		alpha := math.Pow(Tf/T0, 1.0/n)
+		return T0 * math.Pow(alpha, t)
+
 	default:
 		// Default to exponential
 		alpha := math.Pow(Tf/T0, 1.0/n)
@@ -323,6 +485,30 @@ func getTemperatureSchedule(step int, config SimulatedAnnealingConfig) float64 {
 	}
 }
 
+// sawtoothCycleLength is the number of steps of one sawtooth cycle
+func sawtoothCycleLength(config SimulatedAnnealingConfig) int {
+	cycles := max(config.NumCycles, 1)
+	return max((config.NumSteps+cycles-1)/cycles, 1)
+}
+
+// sawtoothCycleStart reports whether step begins a sawtooth cycle
+func sawtoothCycleStart(step int, config SimulatedAnnealingConfig) bool {
+	return step%sawtoothCycleLength(config) == 0
+}
+
+// reheatStep is the step at which exponential cooling passes
+// ReheatFraction × T_initial, where adaptive_reheat restarts
+//
+// MATHEMATICIAN: T_0 (T_f/T_0)^(t/N) = f T_0  ⇒  t = N ln f / ln(T_f/T_0)
+func reheatStep(config SimulatedAnnealingConfig) int {
+	f := config.ReheatFraction
+	if f <= 0 || f >= 1 {
+		return 0
+	}
+	ratio := config.TemperatureFinal / config.TemperatureInitial
+	return int(float64(config.NumSteps) * math.Log(f) / math.Log(ratio))
+}
+
 // getPerturbationSize calculates perturbation size for SA step
 //
 // PHYSICIST:
@@ -340,11 +526,11 @@ func getPerturbationSize(step int, config SimulatedAnnealingConfig) float64 {
 }
 
 // perturbStructure randomly perturbs protein coordinates
-func perturbStructure(protein *parser.Protein, perturbSize float64) {
+func perturbStructure(protein *parser.Protein, perturbSize float64, rng *rand.Rand) {
 	for _, atom := range protein.Atoms {
-		atom.X += (rand.Float64()*2.0 - 1.0) * perturbSize
-		atom.Y += (rand.Float64()*2.0 - 1.0) * perturbSize
-		atom.Z += (rand.Float64()*2.0 - 1.0) * perturbSize
+		atom.X += (rng.Float64()*2.0 - 1.0) * perturbSize
+		atom.Y += (rng.Float64()*2.0 - 1.0) * perturbSize
+		atom.Z += (rng.Float64()*2.0 - 1.0) * perturbSize
 	}
 }
 
@@ -383,6 +569,7 @@ func cloneProtein(protein *parser.Protein) *parser.Protein {
 		clonedRes := &parser.Residue{
 			Name:    res.Name,
 			SeqNum:  res.SeqNum,
+			ICode:   res.ICode,
 			ChainID: res.ChainID,
 		}
 		if res.N != nil {
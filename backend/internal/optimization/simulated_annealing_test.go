@@ -0,0 +1,118 @@
+package optimization
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestSimulatedAnnealingTrajectory checks one frame is recorded per stride
+// accepted steps and the frames export as PDB models and XYZ frames
+func TestSimulatedAnnealingTrajectory(t *testing.T) {
+	protein := buildBasinHoppingTestPeptide(t)
+
+	config := DefaultSimulatedAnnealingConfig()
+	config.NumSteps = 300
+	config.UseLBFGSRefinement = false
+	config.PerturbationInitial = 0.05 // Small enough that moves get accepted
+	config.PerturbationFinal = 0.01
+	config.TrajectoryStride = 10
+
+	result, err := SimulatedAnnealing(protein, config)
+	if err != nil {
+		t.Fatalf("Simulated annealing failed: %v", err)
+	}
+
+	frames := result.Trajectory.Len()
+	t.Logf("Accepted %d of %d steps, recorded %d frames", result.AcceptedSteps, result.Steps, frames)
+
+	if want := result.AcceptedSteps / config.TrajectoryStride; frames != want {
+		t.Fatalf("Recorded %d frames, want %d accepted steps / stride %d = %d",
+			frames, result.AcceptedSteps, config.TrajectoryStride, want)
+	}
+	if frames == 0 {
+		t.Fatal("No frames recorded")
+	}
+	for i := 1; i < frames; i++ {
+		if result.Trajectory.Frames[i].Step <= result.Trajectory.Frames[i-1].Step {
+			t.Errorf("Frame %d step %d not after frame %d step %d",
+				i, result.Trajectory.Frames[i].Step, i-1, result.Trajectory.Frames[i-1].Step)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := result.Trajectory.WritePDB(dir + "/trajectory.pdb"); err != nil {
+		t.Fatalf("WritePDB failed: %v", err)
+	}
+	models, err := parser.ParsePDBModels(dir + "/trajectory.pdb")
+	if err != nil {
+		t.Fatalf("ParsePDBModels failed: %v", err)
+	}
+	if len(models) != frames {
+		t.Errorf("PDB has %d models, want %d", len(models), frames)
+	}
+
+	if err := result.Trajectory.WriteXYZ(dir + "/trajectory.xyz"); err != nil {
+		t.Fatalf("WriteXYZ failed: %v", err)
+	}
+	xyz, err := os.ReadFile(dir + "/trajectory.xyz")
+	if err != nil {
+		t.Fatalf("Failed to read XYZ: %v", err)
+	}
+	if got := strings.Count(string(xyz), "step="); got != frames {
+		t.Errorf("XYZ has %d frames, want %d", got, frames)
+	}
+
+	// The frame cap bounds memory; the rest are only counted
+	config.TrajectoryMaxFrames = 3
+	capped, err := SimulatedAnnealing(buildBasinHoppingTestPeptide(t), config)
+	if err != nil {
+		t.Fatalf("Simulated annealing failed: %v", err)
+	}
+	if capped.Trajectory.Len() != 3 || capped.Trajectory.Len()+capped.Trajectory.Dropped != frames {
+		t.Errorf("Capped run kept %d and dropped %d frames, want 3 kept of %d",
+			capped.Trajectory.Len(), capped.Trajectory.Dropped, frames)
+	}
+}
+
+// TestSimulatedAnnealingPlateau checks SA stops once the best energy stops
+// improving, and runs every step with the plateau stop off
+func TestSimulatedAnnealingPlateau(t *testing.T) {
+	// Toy system: two atoms of one residue have no energy terms, so the
+	// initial energy (0) is already the minimum
+	toy := func() *parser.Protein {
+		return &parser.Protein{Atoms: []*parser.Atom{
+			{Serial: 1, Name: "CA", ResName: "ALA", ChainID: "A", ResSeq: 1, Element: "C"},
+			{Serial: 2, Name: "CB", ResName: "ALA", ChainID: "A", ResSeq: 1, X: 1.5, Element: "C"},
+		}}
+	}
+
+	config := DefaultSimulatedAnnealingConfig()
+	config.NumSteps = 5000
+	config.UseLBFGSRefinement = false
+	config.PlateauSteps = 200
+
+	result, err := SimulatedAnnealing(toy(), config)
+	if err != nil {
+		t.Fatalf("Simulated annealing failed: %v", err)
+	}
+	t.Logf("Plateau stop: %d of %d steps (%s)", result.Steps, config.NumSteps, result.Reason)
+	if !result.Converged || !strings.Contains(result.Reason, "improved by less than") {
+		t.Errorf("Expected a plateau convergence, got Converged=%v (%s)", result.Converged, result.Reason)
+	}
+	if result.Steps != config.PlateauSteps {
+		t.Errorf("Stopped after %d steps, want %d (no improvement from the start)", result.Steps, config.PlateauSteps)
+	}
+
+	config.PlateauSteps = 0
+	result, err = SimulatedAnnealing(toy(), config)
+	if err != nil {
+		t.Fatalf("Simulated annealing failed: %v", err)
+	}
+	if result.Steps != config.NumSteps || result.Converged {
+		t.Errorf("With PlateauSteps=0 ran %d of %d steps (Converged=%v, %s)",
+			result.Steps, config.NumSteps, result.Converged, result.Reason)
+	}
+}
@@ -0,0 +1,154 @@
+// Package parser - Atom name remediation
+//
+// PDB files from different programs name the same atoms differently:
+// CHARMM writes the amide hydrogen as HN and the C-terminal oxygens as
+// OT1/OT2, GROMACS as OC1/OC2, neutron structures carry deuterium (D)
+// where X-ray models carry H. Code downstream matches the PDB v3 names
+// (H, O, OXT), so a CHARMM file loses its C-terminal carbonyl and every
+// amide H. NormalizeAtomNames rewrites these dialects to the v3 names.
+//
+// BIOCHEMIST: Deuterium is chemically hydrogen here - same bonds, same
+// H-bonds - so D atoms become H
+// ETHICIST: Names that match no standard amino acid atom are reported,
+// not guessed at
+//
+// CITATION:
+// wwPDB (2008). "Atomic Coordinate Entry Format Version 3.2" and the PDB
+// format remediation (Henrick, K., et al. (2008). "Remediation of the
+// protein data bank archive." Nucleic Acids Res. 36: D426-D433).
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// atomNameAliases maps dialect names of backbone atoms to PDB v3 names
+var atomNameAliases = map[string]string{
+	"HN":  "H",                            // CHARMM amide H
+	"HT1": "H1", "HT2": "H2", "HT3": "H3", // CHARMM N-terminal H
+	"HN1": "H1", "HN2": "H2", "HN3": "H3",
+	"1H": "H1", "2H": "H2", "3H": "H3", // PDB v2 N-terminal H
+	"OT1": "O", "OT2": "OXT", // CHARMM C-terminus
+	"OC1": "O", "OC2": "OXT", // GROMACS C-terminus
+	"O1": "O", "O2": "OXT",
+}
+
+// sideChainHeavyAtoms lists the side-chain heavy atoms of each standard
+// amino acid (backbone N, CA, C, O, OXT are common to all)
+var sideChainHeavyAtoms = map[string][]string{
+	"ALA": {"CB"},
+	"ARG": {"CB", "CG", "CD", "NE", "CZ", "NH1", "NH2"},
+	"ASN": {"CB", "CG", "OD1", "ND2"},
+	"ASP": {"CB", "CG", "OD1", "OD2"},
+	"CYS": {"CB", "SG"},
+	"GLN": {"CB", "CG", "CD", "OE1", "NE2"},
+	"GLU": {"CB", "CG", "CD", "OE1", "OE2"},
+	"GLY": {},
+	"HIS": {"CB", "CG", "ND1", "CD2", "CE1", "NE2"},
+	"ILE": {"CB", "CG1", "CG2", "CD1"},
+	"LEU": {"CB", "CG", "CD1", "CD2"},
+	"LYS": {"CB", "CG", "CD", "CE", "NZ"},
+	"MET": {"CB", "CG", "SD", "CE"},
+	"PHE": {"CB", "CG", "CD1", "CD2", "CE1", "CE2", "CZ"},
+	"PRO": {"CB", "CG", "CD"},
+	"SER": {"CB", "OG"},
+	"THR": {"CB", "OG1", "CG2"},
+	"TRP": {"CB", "CG", "CD1", "CD2", "NE1", "CE2", "CE3", "CZ2", "CZ3", "CH2"},
+	"TYR": {"CB", "CG", "CD1", "CD2", "CE1", "CE2", "CZ", "OH"},
+	"VAL": {"CB", "CG1", "CG2"},
+}
+
+// residueNameAliases maps force-field protonation variants to the standard
+// residue whose atom names they share
+var residueNameAliases = map[string]string{
+	"HID": "HIS", "HIE": "HIS", "HIP": "HIS",
+	"HSD": "HIS", "HSE": "HIS", "HSP": "HIS",
+	"CYX": "CYS", "CYM": "CYS",
+	"ASH": "ASP", "GLH": "GLU", "LYN": "LYS",
+}
+
+// NormalizeAtomNames rewrites the atom names of standard amino acids to PDB
+// v3 names in place and returns the names it did not recognize
+//
+// HN → H, HT1-3/HN1-3/1H-3H → H1-H3, OT1/OC1/O1 → O, OT2/OC2/O2 → OXT, and
+// deuterium (element D, or a name starting with D) → H. Renamed backbone
+// atoms are linked into their residue if the slot is empty, so a CHARMM
+// C-terminus gets its O. Atoms of other residues (ligands, water) are left
+// alone. Unrecognized names ("RES chain seq NAME") are returned for the
+// caller to report; nothing is logged here.
+func NormalizeAtomNames(protein *Protein) []string {
+	if protein == nil {
+		return nil
+	}
+
+	residues := make(map[string]*Residue, len(protein.Residues))
+	for _, res := range protein.Residues {
+		residues[fmt.Sprintf("%s:%d:%s", res.ChainID, res.SeqNum, res.ICode)] = res
+	}
+
+	var unrecognized []string
+	for _, atom := range protein.Atoms {
+		resName := strings.ToUpper(atom.ResName)
+		if alias, ok := residueNameAliases[resName]; ok {
+			resName = alias
+		}
+		sideChain, standard := sideChainHeavyAtoms[resName]
+		if !standard {
+			continue
+		}
+
+		name := normalizeAtomName(atom)
+		if name != atom.Name {
+			atom.Name = name
+			// Only O among the Residue backbone slots has aliases
+			if res := residues[fmt.Sprintf("%s:%d:%s", atom.ChainID, atom.ResSeq, atom.ICode)]; res != nil && name == "O" && res.O == nil {
+				res.O = atom
+			}
+		}
+
+		if !isStandardAtomName(name, sideChain) {
+			unrecognized = append(unrecognized, fmt.Sprintf("%s %s%d%s %s", atom.ResName, atom.ChainID, atom.ResSeq, atom.ICode, atom.Name))
+		}
+	}
+
+	return unrecognized
+}
+
+// normalizeAtomName returns the PDB v3 name of atom, updating its element
+// for deuterium and renamed oxygens and hydrogens
+func normalizeAtomName(atom *Atom) string {
+	name := strings.ToUpper(strings.TrimSpace(atom.Name))
+
+	// Deuterium: D, DA, 1DB, ... (no standard heavy atom starts with D)
+	if rest := strings.TrimLeft(name, "123"); strings.HasPrefix(rest, "D") {
+		name = name[:len(name)-len(rest)] + "H" + rest[1:]
+		atom.Element = "H"
+	} else if atom.Element == "D" {
+		atom.Element = "H"
+	}
+
+	if alias, ok := atomNameAliases[name]; ok {
+		name = alias
+		atom.Element = name[:1]
+	}
+	return name
+}
+
+// isStandardAtomName reports whether name is a backbone atom, one of
+// sideChain, or a hydrogen
+func isStandardAtomName(name string, sideChain []string) bool {
+	switch name {
+	case "N", "CA", "C", "O", "OXT":
+		return true
+	}
+	if strings.HasPrefix(strings.TrimLeft(name, "123"), "H") {
+		return true
+	}
+	for _, s := range sideChain {
+		if name == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestNormalizeAtomNames parses a CHARMM/neutron-style file and checks the
+// atom names come out as PDB v3
+func TestNormalizeAtomNames(t *testing.T) {
+	line := func(serial int, name, resName string, resSeq int, x float64, element string) string {
+		return fmt.Sprintf("ATOM  %5d %-4s %3s A%4d    %8.3f%8.3f%8.3f%6.2f%6.2f          %2s\n",
+			serial, name, resName, resSeq, x, 0.0, 0.0, 1.0, 10.0, element)
+	}
+
+	var pdb strings.Builder
+	atoms := []struct{ name, resName, element string }{
+		{"N", "ALA", "N"}, {"HN", "ALA", "H"}, {"CA", "ALA", "C"}, {"1DB", "ALA", "D"},
+		{"CB", "ALA", "C"}, {"C", "ALA", "C"}, {"O", "ALA", "O"},
+		{"N", "GLY", "N"}, {"D", "GLY", "D"}, {"CA", "GLY", "C"}, {"C", "GLY", "C"},
+		{"OT1", "GLY", "O"}, {"OT2", "GLY", "O"}, {"CX", "GLY", "C"},
+		{"O", "HOH", "O"},
+	}
+	resSeq := map[string]int{"ALA": 1, "GLY": 2, "HOH": 3}
+	for i, a := range atoms {
+		pdb.WriteString(line(i+1, a.name, a.resName, resSeq[a.resName], float64(i), a.element))
+	}
+	pdb.WriteString("END\n")
+
+	path := t.TempDir() + "/charmm.pdb"
+	if err := os.WriteFile(path, []byte(pdb.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write PDB: %v", err)
+	}
+
+	raw, err := ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+	if raw.Residues[1].O != nil {
+		t.Errorf("Without normalization OT1 should not be the GLY carbonyl O")
+	}
+
+	var logged bytes.Buffer
+	protein, err := ParsePDBWithOptions(path, PDBOptions{NormalizeNames: true, Logger: log.New(&logged, "", 0)})
+	if err != nil {
+		t.Fatalf("ParsePDBWithOptions failed: %v", err)
+	}
+	if !strings.Contains(logged.String(), "1 unrecognized atom names") {
+		t.Errorf("Expected the unrecognized CX to be logged, got %q", logged.String())
+	}
+
+	want := []struct{ name, element string }{
+		{"N", "N"}, {"H", "H"}, {"CA", "C"}, {"1HB", "H"},
+		{"CB", "C"}, {"C", "C"}, {"O", "O"},
+		{"N", "N"}, {"H", "H"}, {"CA", "C"}, {"C", "C"},
+		{"O", "O"}, {"OXT", "O"}, {"CX", "C"},
+		{"O", "O"},
+	}
+	for i, w := range want {
+		atom := protein.Atoms[i]
+		if atom.Name != w.name || atom.Element != w.element {
+			t.Errorf("Atom %d (%s): got %s/%s, want %s/%s", i+1, atoms[i].name, atom.Name, atom.Element, w.name, w.element)
+		}
+	}
+
+	if gly := protein.Residues[1]; gly.O == nil || gly.O.Serial != 12 {
+		t.Errorf("GLY 2 carbonyl O should be the former OT1 (serial 12), got %+v", gly.O)
+	}
+
+	// Normalizing again finds nothing new to rename; only CX is unknown
+	unrecognized := NormalizeAtomNames(protein)
+	t.Logf("Unrecognized: %v", unrecognized)
+	if len(unrecognized) != 1 || !strings.HasSuffix(unrecognized[0], " CX") {
+		t.Errorf("Expected only GLY CX to be unrecognized, got %v", unrecognized)
+	}
+}
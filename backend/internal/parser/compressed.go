@@ -0,0 +1,56 @@
+// Package parser - Compressed structure files
+//
+// RCSB serves every entry gzipped (1abc.pdb.gz, 1abc.cif.gz). The file
+// readers decompress transparently: a file is treated as gzip when it starts
+// with the gzip magic bytes, whatever its extension, so a renamed or
+// extension-less download still parses, and a plain file named .gz is read
+// as plain text.
+package parser
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// gzipMagic is the two-byte header of every gzip stream (RFC 1952)
+var gzipMagic = [2]byte{0x1f, 0x8b}
+
+// structureFile is an open structure file, decompressed if it was gzipped
+type structureFile struct {
+	io.Reader
+	file *os.File
+	gz   *gzip.Reader
+}
+
+// Close closes the decompressor (if any) and the file
+func (f *structureFile) Close() error {
+	if f.gz != nil {
+		f.gz.Close()
+	}
+	return f.file.Close()
+}
+
+// openStructureFile opens filename for reading, decompressing gzip content
+func openStructureFile(filename string) (*structureFile, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(file)
+	header, err := buffered.Peek(len(gzipMagic))
+	if err != nil || header[0] != gzipMagic[0] || header[1] != gzipMagic[1] {
+		// Shorter than the magic or not gzip: read as plain text
+		return &structureFile{Reader: buffered, file: file}, nil
+	}
+
+	gz, err := gzip.NewReader(buffered)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("invalid gzip data in %s: %w", filename, err)
+	}
+	return &structureFile{Reader: gz, file: file, gz: gz}, nil
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FASTARecord is one sequence entry from a FASTA file
+type FASTARecord struct {
+	Header   string // Header line without the leading '>'
+	Sequence string // One-letter amino acid sequence (uppercase)
+}
+
+// FASTAOptions controls which residue letters are accepted
+type FASTAOptions struct {
+	AllowUnknown bool // Accept X (unknown residue)
+	AllowGaps    bool // Accept '-' and '.' (alignment gaps)
+}
+
+// standardAminoAcids are the 20 canonical one-letter codes
+const standardAminoAcids = "ACDEFGHIKLMNPQRSTVWY"
+
+// ParseFASTA parses a FASTA file containing only the 20 standard amino acids
+//
+// Handles multi-record files, sequences wrapped over several lines, blank
+// lines and Windows (CRLF) line endings. A trailing '*' stop is dropped.
+func ParseFASTA(path string) ([]FASTARecord, error) {
+	return ParseFASTAWithOptions(path, FASTAOptions{})
+}
+
+// ParseFASTAWithOptions parses a FASTA file, optionally allowing X and gaps
+func ParseFASTAWithOptions(path string, options FASTAOptions) ([]FASTARecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open FASTA file: %w", err)
+	}
+	defer file.Close()
+
+	records := make([]FASTARecord, 0)
+	var current *FASTARecord
+	var seq strings.Builder
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		current.Sequence = strings.TrimSuffix(seq.String(), "*")
+		if current.Sequence == "" {
+			return fmt.Errorf("record %q has an empty sequence", current.Header)
+		}
+		if strings.Contains(current.Sequence, "*") {
+			return fmt.Errorf("record %q has an internal stop '*'", current.Header)
+		}
+		records = append(records, *current)
+		seq.Reset()
+		return nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(strings.TrimRight(scanner.Text(), "\r"))
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue // Blank line or legacy comment
+		}
+
+		if strings.HasPrefix(line, ">") {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			current = &FASTARecord{Header: strings.TrimSpace(line[1:])}
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: sequence data before first '>' header", lineNum)
+		}
+
+		for _, r := range strings.ToUpper(line) {
+			if r == ' ' || r == '\t' {
+				continue
+			}
+			if !isAllowedFASTAResidue(r, options) {
+				return nil, fmt.Errorf("line %d: invalid residue %q in record %q", lineNum, r, current.Header)
+			}
+			seq.WriteRune(r)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading FASTA file: %w", err)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no FASTA records found in %s", path)
+	}
+
+	return records, nil
+}
+
+// ValidateSequence checks that sequence is non-empty and uses only the 20
+// standard amino acid one-letter codes (uppercase)
+func ValidateSequence(sequence string) error {
+	if sequence == "" {
+		return fmt.Errorf("empty sequence")
+	}
+	for i, r := range sequence {
+		if !strings.ContainsRune(standardAminoAcids, r) {
+			return fmt.Errorf("invalid residue %q at position %d", r, i+1)
+		}
+	}
+	return nil
+}
+
+// isAllowedFASTAResidue checks a residue letter against the options
+func isAllowedFASTAResidue(r rune, options FASTAOptions) bool {
+	switch {
+	case strings.ContainsRune(standardAminoAcids, r):
+		return true
+	case r == '*':
+		return true // Stop codon; stripped if terminal
+	case r == 'X':
+		return options.AllowUnknown
+	case r == '-' || r == '.':
+		return options.AllowGaps
+	}
+	return false
+}
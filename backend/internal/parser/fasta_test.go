@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFASTA(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "test.fasta")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write FASTA: %v", err)
+	}
+	return path
+}
+
+func TestParseFASTA(t *testing.T) {
+	// Two records, wrapped sequence, blank lines, CRLF endings, lowercase
+	content := ">1L2Y_1 Trp-cage miniprotein\r\n" +
+		"NLYIQWLKDG\r\n" +
+		"GPSSGRPPPS\r\n" +
+		"\r\n" +
+		">villin headpiece\r\n" +
+		"lsdedfkavfgmtrsafanlplwkqqnlkkekglf*\r\n"
+
+	records, err := ParseFASTA(writeFASTA(t, content))
+	if err != nil {
+		t.Fatalf("ParseFASTA failed: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	expected := []FASTARecord{
+		{Header: "1L2Y_1 Trp-cage miniprotein", Sequence: "NLYIQWLKDGGPSSGRPPPS"},
+		{Header: "villin headpiece", Sequence: "LSDEDFKAVFGMTRSAFANLPLWKQQNLKKEKGLF"},
+	}
+	for i, want := range expected {
+		if records[i] != want {
+			t.Errorf("Record %d: got %+v, want %+v", i, records[i], want)
+		}
+		t.Logf("%s: %s (%d aa)", records[i].Header, records[i].Sequence, len(records[i].Sequence))
+	}
+}
+
+func TestParseFASTAValidation(t *testing.T) {
+	path := writeFASTA(t, ">gapped\nACD-XGH\n")
+
+	if _, err := ParseFASTA(path); err == nil {
+		t.Error("Expected error for X and gap in strict mode")
+	}
+
+	records, err := ParseFASTAWithOptions(path, FASTAOptions{AllowUnknown: true, AllowGaps: true})
+	if err != nil {
+		t.Fatalf("Expected X and gaps to be allowed: %v", err)
+	}
+	if records[0].Sequence != "ACD-XGH" {
+		t.Errorf("Expected ACD-XGH, got %s", records[0].Sequence)
+	}
+
+	for name, content := range map[string]string{
+		"no header":     "ACDEF\n",
+		"empty record":  ">empty\n>next\nACD\n",
+		"invalid":       ">bad\nACDZ\n",
+		"internal stop": ">stop\nAC*DE\n",
+		"empty file":    "\n\n",
+	} {
+		if _, err := ParseFASTA(writeFASTA(t, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// structureFixture is one small structure written as PDB and as mmCIF
+var structureFixture = []struct {
+	serial         int
+	name, resName  string
+	resSeq         int
+	x, y, z, b     float64
+	element        string
+	hetatm         bool
+	labelSeq, asym string
+}{
+	{1, "N", "ALA", 10, 11.104, 6.134, -6.504, 12.5, "N", false, "1", "A"},
+	{2, "CA", "ALA", 10, 11.639, 6.071, -5.147, 11.0, "C", false, "1", "A"},
+	{3, "C", "ALA", 10, 13.169, 5.987, -5.187, 10.2, "C", false, "1", "A"},
+	{4, "O", "ALA", 10, 13.746, 6.091, -6.275, 13.8, "O", false, "1", "A"},
+	{5, "CB", "ALA", 10, 11.141, 4.886, -4.302, 14.1, "C", false, "1", "A"},
+	{6, "N", "GLY", 11, 13.829, 5.798, -4.041, 9.7, "N", false, "2", "A"},
+	{7, "CA", "GLY", 11, 15.279, 5.722, -3.959, 9.9, "C", false, "2", "A"},
+	{8, "C", "GLY", 11, 15.865, 7.082, -3.612, 10.4, "C", false, "2", "A"},
+	{9, "O", "GLY", 11, 15.207, 8.123, -3.701, 11.6, "O", false, "2", "A"},
+	{10, "O", "HOH", 101, 9.512, 3.771, -2.946, 25.0, "O", true, ".", "B"},
+}
+
+func fixturePDB() string {
+	var pdb strings.Builder
+	for _, a := range structureFixture {
+		record := "ATOM  "
+		if a.hetatm {
+			record = "HETATM"
+		}
+		fmt.Fprintf(&pdb, "%s%5d  %-3s %3s A%4d    %8.3f%8.3f%8.3f%6.2f%6.2f          %2s\n",
+			record, a.serial, a.name, a.resName, a.resSeq, a.x, a.y, a.z, 1.0, a.b, a.element)
+	}
+	pdb.WriteString("END\n")
+	return pdb.String()
+}
+
+// fixtureMMCIF writes the fixture with label numbering that differs from
+// the author numbering, as real entries do, and a second model to skip
+func fixtureMMCIF() string {
+	var cif strings.Builder
+	cif.WriteString("data_TEST\n#\n_entry.id TEST\n#\nloop_\n")
+	for _, column := range []string{"group_PDB", "id", "type_symbol", "label_atom_id", "label_alt_id",
+		"label_comp_id", "label_asym_id", "label_seq_id", "pdbx_PDB_ins_code", "Cartn_x", "Cartn_y",
+		"Cartn_z", "occupancy", "B_iso_or_equiv", "auth_seq_id", "auth_asym_id", "pdbx_PDB_model_num"} {
+		cif.WriteString("_atom_site." + column + "\n")
+	}
+	for _, model := range []int{1, 2} {
+		for _, a := range structureFixture {
+			record := "ATOM"
+			if a.hetatm {
+				record = "HETATM"
+			}
+			x := a.x + float64(model-1)*100 // Model 2 is displaced
+			fmt.Fprintf(&cif, "%s %d %s %s . %s %s %s ? %.3f %.3f %.3f 1.00 %.2f %d A %d\n",
+				record, a.serial, a.element, a.name, a.resName, a.asym, a.labelSeq, x, a.y, a.z, a.b, a.resSeq, model)
+		}
+	}
+	cif.WriteString("#\nloop_\n_atom_type.symbol\nC\nN\nO\n#\n")
+	return cif.String()
+}
+
+func writeFixture(t *testing.T, name string, data string, compress bool) string {
+	t.Helper()
+	content := []byte(data)
+	if compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(content)
+		gz.Close()
+		content = buf.Bytes()
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// sameStructure reports the first difference between two parsed proteins
+func sameStructure(a, b *Protein) error {
+	if len(a.Atoms) != len(b.Atoms) || len(a.Residues) != len(b.Residues) {
+		return fmt.Errorf("%d atoms / %d residues vs %d / %d", len(a.Atoms), len(a.Residues), len(b.Atoms), len(b.Residues))
+	}
+	for i := range a.Atoms {
+		x, y := *a.Atoms[i], *b.Atoms[i]
+		if x != y {
+			return fmt.Errorf("atom %d: %+v vs %+v", i, x, y)
+		}
+	}
+	for i := range a.Residues {
+		x, y := a.Residues[i], b.Residues[i]
+		if x.Name != y.Name || x.SeqNum != y.SeqNum || x.ChainID != y.ChainID || x.HasCompleteBackbone() != y.HasCompleteBackbone() {
+			return fmt.Errorf("residue %d: %s %d%s vs %s %d%s", i, x.Name, x.SeqNum, x.ChainID, y.Name, y.SeqNum, y.ChainID)
+		}
+	}
+	return nil
+}
+
+// TestParseCompressedAndMMCIF checks gzipped PDB and mmCIF input parse to
+// the same atoms and residues as the plain PDB file
+func TestParseCompressedAndMMCIF(t *testing.T) {
+	plainPath := writeFixture(t, "fixture.pdb", fixturePDB(), false)
+	plain, err := ParsePDB(plainPath)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+	if len(plain.Atoms) != len(structureFixture) || len(plain.Residues) != 3 {
+		t.Fatalf("Plain PDB: %d atoms, %d residues; want %d, 3", len(plain.Atoms), len(plain.Residues), len(structureFixture))
+	}
+
+	for _, c := range []struct {
+		label string
+		parse func(string) (*Protein, error)
+		path  string
+	}{
+		{"pdb.gz", ParsePDB, writeFixture(t, "fixture.pdb.gz", fixturePDB(), true)},
+		{"gzip without extension", ParsePDB, writeFixture(t, "fixture", fixturePDB(), true)},
+		{"mmCIF", ParseMMCIF, writeFixture(t, "fixture.cif", fixtureMMCIF(), false)},
+		{"cif.gz", ParseMMCIF, writeFixture(t, "fixture.cif.gz", fixtureMMCIF(), true)},
+	} {
+		protein, err := c.parse(c.path)
+		if err != nil {
+			t.Errorf("%s: parse failed: %v", c.label, err)
+			continue
+		}
+		if err := sameStructure(plain, protein); err != nil {
+			t.Errorf("%s differs from the plain PDB: %v", c.label, err)
+		}
+	}
+
+	// Quoted atom names and a missing atom_site loop
+	quoted := "data_Q\nloop_\n_atom_site.group_PDB\n_atom_site.label_atom_id\n_atom_site.label_comp_id\n" +
+		"_atom_site.label_asym_id\n_atom_site.label_seq_id\n_atom_site.Cartn_x\n_atom_site.Cartn_y\n_atom_site.Cartn_z\n" +
+		"HETATM \"C1'\" NAG C 1\n1.0 2.0 3.0\n"
+	protein, err := ParseMMCIF(writeFixture(t, "quoted.cif", quoted, false))
+	if err != nil {
+		t.Fatalf("ParseMMCIF (quoted, wrapped row) failed: %v", err)
+	}
+	if len(protein.Atoms) != 1 || protein.Atoms[0].Name != "C1'" || protein.Atoms[0].Z != 3.0 || protein.Atoms[0].ChainID != "C" {
+		t.Errorf("Quoted atom parsed as %+v", protein.Atoms[0])
+	}
+	if _, err := ParseMMCIF(writeFixture(t, "empty.cif", "data_X\n_entry.id X\n", false)); err == nil {
+		t.Error("Expected an error for mmCIF without atom_site")
+	}
+}
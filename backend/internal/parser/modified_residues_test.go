@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestModifiedResidues parses a chain with a selenomethionine HETATM in the
+// middle and checks it reads as M in place
+func TestModifiedResidues(t *testing.T) {
+	var pdb strings.Builder
+	serial := 1
+	for i, resName := range []string{"ALA", "GLY", "MSE", "SEP", "LYS"} {
+		record := "ATOM  "
+		if IsModifiedResidue(resName) {
+			record = "HETATM"
+		}
+		for j, name := range []string{"N", "CA", "C", "O"} {
+			fmt.Fprintf(&pdb, "%s%5d  %-3s %3s A%4d    %8.3f%8.3f%8.3f%6.2f%6.2f          %2s\n",
+				record, serial, name, resName, i+1, float64(i)*3.8+float64(j)*0.5, 0.0, 0.0, 1.0, 10.0, name[:1])
+			serial++
+		}
+		if resName == "MSE" {
+			fmt.Fprintf(&pdb, "HETATM%5d SE   MSE A%4d    %8.3f%8.3f%8.3f%6.2f%6.2f          SE\n",
+				serial, i+1, float64(i)*3.8, 2.0, 0.0, 1.0, 10.0)
+			serial++
+		}
+	}
+	pdb.WriteString("END\n")
+
+	path := t.TempDir() + "/mse.pdb"
+	if err := os.WriteFile(path, []byte(pdb.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write PDB: %v", err)
+	}
+
+	protein, err := ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+	if len(protein.Residues) != 5 {
+		t.Fatalf("Expected 5 residues, got %d", len(protein.Residues))
+	}
+	if seq := protein.Sequence(); seq != "AGMSK" {
+		t.Errorf("Expected sequence AGMSK, got %s", seq)
+	}
+	mse := protein.Residues[2]
+	if mse.Name != "MSE" || mse.N == nil || mse.CA == nil || mse.C == nil || mse.O == nil {
+		t.Errorf("MSE residue %+v: want name kept and full backbone", mse)
+	}
+
+	// Unregistered names stay X until registered
+	if code, ok := OneLetterCode("ZZK"); ok {
+		t.Errorf("ZZK: unexpected code %c", code)
+	}
+	if err := RegisterModifiedResidue("zzk", "lys"); err != nil {
+		t.Fatalf("RegisterModifiedResidue failed: %v", err)
+	}
+	if code, ok := OneLetterCode("ZZK"); !ok || code != 'K' {
+		t.Errorf("ZZK after registration: %c, %v; want K", code, ok)
+	}
+	if parent, ok := ParentResidue("ZZK"); !ok || parent != "LYS" {
+		t.Errorf("ParentResidue(ZZK) = %s, %v; want LYS", parent, ok)
+	}
+
+	// Parents must be standard, and standard names cannot be remapped
+	if err := RegisterModifiedResidue("ZZX", "MSE"); err == nil {
+		t.Error("Registered a non-standard parent")
+	}
+	if err := RegisterModifiedResidue("ALA", "GLY"); err == nil {
+		t.Error("Remapped a standard residue")
+	}
+}
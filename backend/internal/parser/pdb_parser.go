@@ -9,7 +9,7 @@ package parser
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"log"
 	"strconv"
 	"strings"
 )
@@ -33,6 +33,7 @@ type Atom struct {
 type Residue struct {
 	Name    string  // Three-letter code (ALA, GLY, etc.)
 	SeqNum  int     // Sequence number
+	ICode   string  // Insertion code ("" for none; e.g. "A" in 100A)
 	ChainID string  // Chain identifier
 	N       *Atom   // Nitrogen (backbone)
 	CA      *Atom   // Alpha carbon (backbone)
@@ -45,27 +46,88 @@ type Protein struct {
 	Name     string     // Protein name/PDB ID
 	Residues []*Residue // All residues in sequence
 	Atoms    []*Atom    // All atoms
+
+	SSRecords []SSRecord // HELIX and SHEET records (PDB files only)
+}
+
+// Alternate location selection modes for PDBOptions.AltLoc
+const (
+	AltLocBest = ""  // Keep the highest-occupancy conformer of each atom
+	AltLocAll  = "*" // Keep every conformer (duplicates atoms)
+)
+
+// PDBOptions controls how alternate conformations are read
+type PDBOptions struct {
+	// AltLocBest (default), AltLocAll, or a specific indicator such as "A"
+	// (atoms without an altLoc are always kept)
+	AltLoc string
+
+	// Rewrite CHARMM/GROMACS/neutron atom names (HN, OT1, D, ...) to PDB v3
+	// names with NormalizeAtomNames
+	NormalizeNames bool
+
+	// Where NormalizeNames reports unrecognized atom names (nil: silent)
+	Logger *log.Logger
 }
 
 // ParsePDB parses a PDB file and extracts protein structure
 //
 // Citation: PDB format specification from RCSB PDB (www.wwpdb.org)
-// Handles ATOM and HETATM records, filters for protein backbone atoms
+// Handles ATOM and HETATM records, filters for protein backbone atoms.
+// Only the first model is read; see ParsePDBModels for ensembles.
+// Gzipped files (.pdb.gz) are decompressed transparently.
+//
+// Residues are identified by (chainID, resSeq, iCode), so insertion-coded
+// residues (100, 100A, 100B) are distinct. Of alternate conformations only
+// the highest-occupancy one is kept - duplicated atoms would double-count
+// energy terms. Use ParsePDBWithOptions to choose otherwise.
 func ParsePDB(filename string) (*Protein, error) {
-	file, err := os.Open(filename)
+	return ParsePDBWithOptions(filename, PDBOptions{})
+}
+
+// ParsePDBWithOptions parses the first model of a PDB file, selecting
+// alternate conformations as options.AltLoc says and normalizing atom
+// names if options.NormalizeNames is set
+func ParsePDBWithOptions(filename string, options PDBOptions) (*Protein, error) {
+	models, err := readPDBModels(filename, 1, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open PDB file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
+	if len(models) == 0 {
+		return &Protein{
+			Name:     filename,
+			Residues: make([]*Residue, 0),
+			Atoms:    make([]*Atom, 0),
+		}, nil
+	}
+	return models[0], nil
+}
 
-	protein := &Protein{
-		Name:     filename,
-		Residues: make([]*Residue, 0),
-		Atoms:    make([]*Atom, 0),
+// ParsePDBModels parses every MODEL of a multi-model PDB file
+//
+// Inverse of WritePDBModels. A file without MODEL records yields one model.
+func ParsePDBModels(filename string) ([]*Protein, error) {
+	models, err := readPDBModels(filename, 0, PDBOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no atoms in PDB file %s", filename)
+	}
+	return models, nil
+}
+
+// readPDBModels reads up to maxModels models (0 = all), stopping at END
+func readPDBModels(filename string, maxModels int, options PDBOptions) ([]*Protein, error) {
+	file, err := openStructureFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDB file: %w", err)
 	}
+	defer file.Close()
 
-	// Map to group atoms by residue (chainID:resSeq)
-	residueMap := make(map[string]*Residue)
+	var models []*Protein
+	var current *pdbModel
+	var ssRecords []SSRecord // Header records, shared by every model
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -78,41 +140,30 @@ func ParsePDB(filename string) (*Protein, error) {
 				// Skip malformed lines but continue parsing
 				continue
 			}
+			if current == nil {
+				current = newPDBModel(filename, options.AltLoc)
+			}
+			current.addAtom(atom)
+			continue
+		}
 
-			protein.Atoms = append(protein.Atoms, atom)
-
-			// Only process backbone atoms for Ramachandran analysis
-			if isBackboneAtom(atom.Name) {
-				resKey := fmt.Sprintf("%s:%d", atom.ChainID, atom.ResSeq)
-
-				// Get or create residue
-				res, exists := residueMap[resKey]
-				if !exists {
-					res = &Residue{
-						Name:    atom.ResName,
-						SeqNum:  atom.ResSeq,
-						ChainID: atom.ChainID,
-					}
-					residueMap[resKey] = res
-					protein.Residues = append(protein.Residues, res)
-				}
+		if record, ok := parseSSRecord(line); ok {
+			ssRecords = append(ssRecords, record)
+			continue
+		}
 
-				// Assign atom to residue based on atom name
-				switch atom.Name {
-				case "N":
-					res.N = atom
-				case "CA":
-					res.CA = atom
-				case "C":
-					res.C = atom
-				case "O":
-					res.O = atom
-				}
+		// ENDMDL closes a model; END closes the file
+		if strings.HasPrefix(line, "ENDMDL") {
+			if current != nil {
+				models = append(models, current.protein)
+				current = nil
+			}
+			if maxModels > 0 && len(models) >= maxModels {
+				break
 			}
+			continue
 		}
-
-		// Stop at END or ENDMDL
-		if len(line) >= 3 && (line[0:3] == "END" || line[0:6] == "ENDMDL") {
+		if strings.HasPrefix(line, "END") {
 			break
 		}
 	}
@@ -120,8 +171,109 @@ func ParsePDB(filename string) (*Protein, error) {
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("error reading PDB file: %w", err)
 	}
+	if current != nil && (maxModels <= 0 || len(models) < maxModels) {
+		models = append(models, current.protein)
+	}
+
+	for _, model := range models {
+		model.SSRecords = append([]SSRecord(nil), ssRecords...)
+		if !options.NormalizeNames {
+			continue
+		}
+		if unrecognized := NormalizeAtomNames(model); len(unrecognized) > 0 && options.Logger != nil {
+			options.Logger.Printf("parser: %d unrecognized atom names in %s: %s",
+				len(unrecognized), model.Name, strings.Join(unrecognized, ", "))
+		}
+	}
+	return models, nil
+}
+
+// pdbModel accumulates one model's atoms and backbone residues
+type pdbModel struct {
+	protein    *Protein
+	residueMap map[string]*Residue // chainID:resSeq:iCode → residue
+
+	altLoc      string         // PDBOptions.AltLoc
+	altLocAtoms map[string]int // residue key + atom name → index in Atoms
+}
+
+func newPDBModel(name, altLoc string) *pdbModel {
+	return &pdbModel{
+		protein: &Protein{
+			Name:     name,
+			Residues: make([]*Residue, 0),
+			Atoms:    make([]*Atom, 0),
+		},
+		residueMap:  make(map[string]*Residue),
+		altLoc:      altLoc,
+		altLocAtoms: make(map[string]int),
+	}
+}
+
+// addAtom appends atom and assigns backbone atoms to their residue,
+// resolving alternate conformations according to m.altLoc
+func (m *pdbModel) addAtom(atom *Atom) {
+	resKey := fmt.Sprintf("%s:%d:%s", atom.ChainID, atom.ResSeq, atom.ICode)
+
+	if atom.AltLoc != "" {
+		switch m.altLoc {
+		case AltLocAll:
+			// Keep every conformer
+
+		case AltLocBest:
+			// First conformer seen wins ties (usually A)
+			atomKey := resKey + ":" + atom.Name
+			if idx, seen := m.altLocAtoms[atomKey]; seen {
+				if atom.Occupancy > m.protein.Atoms[idx].Occupancy {
+					m.protein.Atoms[idx] = atom
+					m.assignBackbone(resKey, atom)
+				}
+				return
+			}
+			m.altLocAtoms[atomKey] = len(m.protein.Atoms)
+
+		default:
+			if atom.AltLoc != m.altLoc {
+				return
+			}
+		}
+	}
+
+	m.protein.Atoms = append(m.protein.Atoms, atom)
+	m.assignBackbone(resKey, atom)
+}
+
+// assignBackbone places a backbone atom into its residue, creating the
+// residue on first sight (only backbone atoms are used for Ramachandran analysis)
+func (m *pdbModel) assignBackbone(resKey string, atom *Atom) {
+	if !isBackboneAtom(atom.Name) {
+		return
+	}
 
-	return protein, nil
+	// Get or create residue
+	res, exists := m.residueMap[resKey]
+	if !exists {
+		res = &Residue{
+			Name:    atom.ResName,
+			SeqNum:  atom.ResSeq,
+			ICode:   atom.ICode,
+			ChainID: atom.ChainID,
+		}
+		m.residueMap[resKey] = res
+		m.protein.Residues = append(m.protein.Residues, res)
+	}
+
+	// Assign atom to residue based on atom name
+	switch atom.Name {
+	case "N":
+		res.N = atom
+	case "CA":
+		res.CA = atom
+	case "C":
+		res.C = atom
+	case "O":
+		res.O = atom
+	}
 }
 
 // parseAtomLine parses a single ATOM/HETATM line from PDB format
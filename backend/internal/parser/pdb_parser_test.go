@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -153,3 +156,185 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+func TestWritePDBRoundTrip(t *testing.T) {
+	protein := &Protein{Name: "roundtrip"}
+	names := []string{"N", "CA", "C", "O"}
+	for i, resName := range []string{"A", "GLY", "W"} {
+		res := &Residue{Name: resName, SeqNum: i + 1, ChainID: "A"}
+		for j, name := range names {
+			atom := &Atom{Name: name, ResName: resName, ChainID: "A", ResSeq: i + 1,
+				X: float64(i)*3.8 + float64(j)*0.5, Y: -1.25, Z: 10.125, Element: name[:1]}
+			protein.Atoms = append(protein.Atoms, atom)
+			switch name {
+			case "N":
+				res.N = atom
+			case "CA":
+				res.CA = atom
+			case "C":
+				res.C = atom
+			case "O":
+				res.O = atom
+			}
+		}
+		protein.Residues = append(protein.Residues, res)
+	}
+
+	path := t.TempDir() + "/roundtrip.pdb"
+	if err := WritePDB(protein, path, []string{"TEST"}); err != nil {
+		t.Fatalf("WritePDB failed: %v", err)
+	}
+
+	parsed, err := ParsePDB(path)
+	if err != nil {
+		t.Fatalf("Failed to parse written PDB: %v", err)
+	}
+	if len(parsed.Residues) != 3 || len(parsed.Atoms) != 12 {
+		t.Fatalf("Expected 3 residues / 12 atoms, got %d / %d", len(parsed.Residues), len(parsed.Atoms))
+	}
+	if parsed.Sequence() != "AGW" {
+		t.Errorf("Expected sequence AGW, got %s", parsed.Sequence())
+	}
+	for i, atom := range parsed.Atoms {
+		orig := protein.Atoms[i]
+		if atom.Name != orig.Name || atom.X != orig.X || atom.Y != orig.Y || atom.Z != orig.Z {
+			t.Errorf("Atom %d mismatch: %+v vs %+v", i, atom, orig)
+		}
+	}
+
+	// Multi-model file parses back as the first model
+	if err := WritePDBModels([]*Protein{protein, protein}, path, nil); err != nil {
+		t.Fatalf("WritePDBModels failed: %v", err)
+	}
+	parsed, err = ParsePDB(path)
+	if err != nil {
+		t.Fatalf("Failed to parse multi-model PDB: %v", err)
+	}
+	if len(parsed.Atoms) != 12 {
+		t.Errorf("Expected first model only (12 atoms), got %d", len(parsed.Atoms))
+	}
+}
+
+func TestParsePDBModels(t *testing.T) {
+	models := make([]*Protein, 3)
+	for m := range models {
+		protein := &Protein{Name: "model"}
+		for i := 0; i < 2; i++ {
+			res := &Residue{Name: "ALA", SeqNum: i + 1, ChainID: "A"}
+			res.CA = &Atom{Name: "CA", ResName: "ALA", ChainID: "A", ResSeq: i + 1,
+				X: float64(m) + 0.001, Y: float64(i) * 3.8, Z: -2.5, Element: "C"}
+			h := &Atom{Name: "H", ResName: "ALA", ChainID: "A", ResSeq: i + 1,
+				X: float64(m), Y: float64(i)*3.8 + 1.0, Z: -2.5, Element: "H"}
+			protein.Atoms = append(protein.Atoms, res.CA, h)
+			protein.Residues = append(protein.Residues, res)
+		}
+		models[m] = protein
+	}
+
+	path := t.TempDir() + "/models.pdb"
+	if err := WritePDBModels(models, path, []string{"ENSEMBLE"}); err != nil {
+		t.Fatalf("WritePDBModels failed: %v", err)
+	}
+
+	parsed, err := ParsePDBModels(path)
+	if err != nil {
+		t.Fatalf("ParsePDBModels failed: %v", err)
+	}
+	if len(parsed) != len(models) {
+		t.Fatalf("Expected %d models, got %d", len(models), len(parsed))
+	}
+	for m, model := range parsed {
+		if len(model.Residues) != 2 || len(model.Atoms) != 4 {
+			t.Errorf("Model %d: %d residues / %d atoms, want 2 / 4", m, len(model.Residues), len(model.Atoms))
+			continue
+		}
+		for i, atom := range model.Atoms {
+			orig := models[m].Atoms[i]
+			if atom.Name != orig.Name || atom.X != orig.X || atom.Y != orig.Y || atom.Z != orig.Z {
+				t.Errorf("Model %d atom %d mismatch: %+v vs %+v", m, i, atom, orig)
+			}
+		}
+	}
+}
+
+// TestParsePDBAltLocAndInsertion checks alternate conformers are resolved
+// (highest occupancy by default) and insertion-coded residues are distinct
+func TestParsePDBAltLocAndInsertion(t *testing.T) {
+	line := func(serial int, name, altLoc, resName string, resSeq int, iCode string, x, occ float64) string {
+		return fmt.Sprintf("ATOM  %5d  %-3s%1s%3s A%4d%1s   %8.3f%8.3f%8.3f%6.2f%6.2f           %1s\n",
+			serial, name, altLoc, resName, resSeq, iCode, x, 0.0, 0.0, occ, 10.0, name[:1])
+	}
+
+	var pdb strings.Builder
+	serial := 1
+	backbone := []string{"N", "CA", "C", "O"}
+	for _, name := range backbone {
+		pdb.WriteString(line(serial, name, "", "ALA", 99, "", 1.0, 1.0))
+		serial++
+	}
+	// SER 100 in two conformers: A (x = 10, occupancy 0.4), B (x = 20, occupancy 0.6)
+	for _, name := range backbone {
+		pdb.WriteString(line(serial, name, "A", "SER", 100, "", 10.0, 0.40))
+		pdb.WriteString(line(serial+1, name, "B", "SER", 100, "", 20.0, 0.60))
+		serial += 2
+	}
+	// Antibody-style insertions 100A and 100B
+	for _, iCode := range []string{"A", "B"} {
+		for _, name := range backbone {
+			pdb.WriteString(line(serial, name, "", "GLY", 100, iCode, 30.0, 1.0))
+			serial++
+		}
+	}
+	pdb.WriteString("END\n")
+
+	path := t.TempDir() + "/altloc.pdb"
+	if err := os.WriteFile(path, []byte(pdb.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write PDB: %v", err)
+	}
+
+	protein, err := ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+
+	if len(protein.Residues) != 4 {
+		t.Fatalf("Expected 4 residues (99, 100, 100A, 100B), got %d", len(protein.Residues))
+	}
+	if len(protein.Atoms) != 16 {
+		t.Errorf("Expected 16 atoms (one conformer of SER 100), got %d", len(protein.Atoms))
+	}
+
+	ser := protein.Residues[1]
+	if ser.CA == nil || ser.CA.AltLoc != "B" || ser.CA.X != 20.0 {
+		t.Errorf("SER 100 CA should be the occupancy-0.6 conformer B, got %+v", ser.CA)
+	}
+	for _, atom := range protein.Atoms {
+		if atom.ResSeq == 100 && atom.ICode == "" && atom.AltLoc == "A" {
+			t.Errorf("Conformer A atom %s kept alongside B", atom.Name)
+		}
+	}
+
+	if ins := protein.Residues[2]; ins.SeqNum != 100 || ins.ICode != "A" || ins.Name != "GLY" {
+		t.Errorf("Residue 3 should be GLY 100A, got %s %d%s", ins.Name, ins.SeqNum, ins.ICode)
+	}
+	if ins := protein.Residues[3]; ins.SeqNum != 100 || ins.ICode != "B" || !ins.HasCompleteBackbone() {
+		t.Errorf("Residue 4 should be complete GLY 100B, got %d%s", ins.SeqNum, ins.ICode)
+	}
+
+	// A specific conformer, and keep-all
+	onlyA, err := ParsePDBWithOptions(path, PDBOptions{AltLoc: "A"})
+	if err != nil {
+		t.Fatalf("ParsePDBWithOptions(A) failed: %v", err)
+	}
+	if ca := onlyA.Residues[1].CA; ca == nil || ca.X != 10.0 || len(onlyA.Atoms) != 16 {
+		t.Errorf("AltLoc A: CA %+v, %d atoms; want x = 10, 16 atoms", ca, len(onlyA.Atoms))
+	}
+
+	all, err := ParsePDBWithOptions(path, PDBOptions{AltLoc: AltLocAll})
+	if err != nil {
+		t.Fatalf("ParsePDBWithOptions(all) failed: %v", err)
+	}
+	if len(all.Atoms) != 20 || len(all.Residues) != 4 {
+		t.Errorf("AltLocAll: %d atoms / %d residues, want 20 / 4", len(all.Atoms), len(all.Residues))
+	}
+}
@@ -0,0 +1,182 @@
+// Package parser - Streaming PDB reader
+//
+// ParsePDB keeps every atom of the first model in memory. For ribosome-scale
+// structures and long trajectories ParsePDBStream instead reads ATOM/HETATM
+// records line by line and hands each residue to a callback as soon as it is
+// complete, retaining nothing but the residue being read.
+//
+// BIOCHEMIST: Residues are emitted with their backbone atoms (N, CA, C, O);
+// alternate conformations are resolved to the highest occupancy, as ParsePDB
+// does by default
+// ETHICIST: A file that ends in the middle of a residue is reported, not
+// silently shortened
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxPDBLineLength bounds a single line; PDB records are 80 columns, so
+// anything near this is not a PDB file
+const maxPDBLineLength = 1 << 20
+
+// ParsePDBStream reads PDB records from r, calling onResidue for each
+// residue once all of its records have been read
+//
+// Residues are identified by (chainID, resSeq, iCode) and must be
+// contiguous, as in any PDB file. Every MODEL is streamed in order (a
+// residue ends at a change of residue, TER, ENDMDL or END); reading stops
+// at END. Only residues with at least one backbone atom are emitted, with
+// Atom pointers set for N, CA, C and O; side-chain atoms are not retained.
+//
+// Lines may end in \n, \r\n or \r. Returns an error wrapping
+// io.ErrUnexpectedEOF if the input ends inside a residue (no TER, ENDMDL or
+// END after its last atom) or with a cut-off ATOM record, and wraps any
+// error returned by onResidue, which stops the stream.
+//
+// ParsePDB remains the convenience wrapper for files that fit in memory.
+func ParsePDBStream(r io.Reader, onResidue func(*Residue) error) error {
+	stream := &pdbStream{onResidue: onResidue}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxPDBLineLength)
+	scanner.Split(stream.splitLines)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if len(line) >= 6 && (line[0:4] == "ATOM" || line[0:6] == "HETATM") {
+			atom, err := parseAtomLine(line)
+			if err != nil {
+				if stream.unterminated {
+					return fmt.Errorf("truncated record at line %d: %w", lineNum, io.ErrUnexpectedEOF)
+				}
+				// Skip malformed lines but continue parsing
+				continue
+			}
+			if err := stream.addAtom(atom); err != nil {
+				return fmt.Errorf("residue callback failed at line %d: %w", lineNum, err)
+			}
+			continue
+		}
+
+		// TER, ENDMDL and END all close the current residue
+		if strings.HasPrefix(line, "TER") || strings.HasPrefix(line, "END") {
+			if err := stream.flush(); err != nil {
+				return fmt.Errorf("residue callback failed at line %d: %w", lineNum, err)
+			}
+			if strings.HasPrefix(line, "END") && !strings.HasPrefix(line, "ENDMDL") {
+				return nil
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading PDB stream: %w", err)
+	}
+	if res := stream.current; res != nil {
+		return fmt.Errorf("PDB stream ended inside residue %s %d%s (chain %s): %w",
+			res.Name, res.SeqNum, res.ICode, res.ChainID, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// pdbStream holds the residue being read
+type pdbStream struct {
+	onResidue func(*Residue) error
+
+	current    *Residue // nil until a backbone atom of the residue is read
+	currentKey string   // chainID:resSeq:iCode of the last atom read
+
+	unterminated bool // The last line had no line terminator
+}
+
+// addAtom adds atom to the current residue, first emitting the current
+// residue if atom starts a new one
+func (s *pdbStream) addAtom(atom *Atom) error {
+	key := fmt.Sprintf("%s:%d:%s", atom.ChainID, atom.ResSeq, atom.ICode)
+	if key != s.currentKey {
+		if err := s.flush(); err != nil {
+			return err
+		}
+		s.currentKey = key
+	}
+
+	if !isBackboneAtom(atom.Name) {
+		return nil
+	}
+	if s.current == nil {
+		s.current = &Residue{
+			Name:    atom.ResName,
+			SeqNum:  atom.ResSeq,
+			ICode:   atom.ICode,
+			ChainID: atom.ChainID,
+		}
+	}
+
+	var slot **Atom
+	switch atom.Name {
+	case "N":
+		slot = &s.current.N
+	case "CA":
+		slot = &s.current.CA
+	case "C":
+		slot = &s.current.C
+	case "O":
+		slot = &s.current.O
+	}
+
+	// Highest-occupancy conformer wins; the first seen wins ties
+	if *slot == nil || atom.AltLoc == "" || atom.Occupancy > (*slot).Occupancy {
+		*slot = atom
+	}
+	return nil
+}
+
+// flush emits the current residue, if any
+func (s *pdbStream) flush() error {
+	res := s.current
+	s.current = nil
+	s.currentKey = ""
+	if res == nil {
+		return nil
+	}
+	return s.onResidue(res)
+}
+
+// splitLines is bufio.ScanLines accepting \n, \r\n and \r as terminators,
+// noting whether the final line was cut off
+func (s *pdbStream) splitLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// \r: swallow a following \n (needs one more byte to decide)
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		return 0, nil, nil
+	}
+
+	if atEOF {
+		s.unterminated = true
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParsePDBStream1L2Y checks streaming sees the same residues as the
+// buffered parsers on the Trp-cage NMR ensemble
+func TestParsePDBStream1L2Y(t *testing.T) {
+	const path = "../../../testdata/1L2Y.pdb"
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s not available (fetch with cmd/download_pdb)", path)
+	}
+
+	models, err := ParsePDBModels(path)
+	if err != nil {
+		t.Fatalf("ParsePDBModels failed: %v", err)
+	}
+	first, err := ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+	want := 0
+	for _, model := range models {
+		want += len(model.Residues)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var streamed []*Residue
+	err = ParsePDBStream(file, func(res *Residue) error {
+		streamed = append(streamed, res)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParsePDBStream failed: %v", err)
+	}
+
+	t.Logf("1L2Y: %d models, %d residues streamed", len(models), len(streamed))
+	if len(streamed) != want {
+		t.Fatalf("Streamed %d residues, ParsePDBModels has %d", len(streamed), want)
+	}
+	for i, res := range first.Residues {
+		got := streamed[i]
+		if got.Name != res.Name || got.SeqNum != res.SeqNum || got.CA == nil || res.CA == nil || got.CA.X != res.CA.X {
+			t.Errorf("Residue %d: streamed %s %d, ParsePDB %s %d", i, got.Name, got.SeqNum, res.Name, res.SeqNum)
+		}
+	}
+}
+
+// streamLine formats one backbone ATOM record
+func streamLine(serial int, name, altLoc string, resSeq int, x, occ float64) string {
+	return fmt.Sprintf("ATOM  %5d  %-3s%1sALA A%4d    %8.3f%8.3f%8.3f%6.2f%6.2f           %1s",
+		serial, name, altLoc, resSeq, x, 0.0, 0.0, occ, 10.0, name[:1])
+}
+
+// streamResidues collects every residue ParsePDBStream emits for input
+func streamResidues(input string) ([]*Residue, error) {
+	var residues []*Residue
+	err := ParsePDBStream(strings.NewReader(input), func(res *Residue) error {
+		residues = append(residues, res)
+		return nil
+	})
+	return residues, err
+}
+
+func TestParsePDBStreamRecords(t *testing.T) {
+	var lines []string
+	serial := 1
+	for model := 1; model <= 2; model++ {
+		lines = append(lines, fmt.Sprintf("MODEL     %4d", model))
+		for resSeq := 1; resSeq <= 3; resSeq++ {
+			for _, name := range []string{"N", "CA", "C", "O", "CB"} {
+				lines = append(lines, streamLine(serial, name, "", resSeq, float64(model), 1.0))
+				serial++
+			}
+		}
+		// Alternate conformers of residue 4: B has the higher occupancy
+		lines = append(lines,
+			streamLine(serial, "CA", "A", 4, 10.0, 0.3),
+			streamLine(serial+1, "CA", "B", 4, 20.0, 0.7))
+		serial += 2
+		lines = append(lines, "TER", "ENDMDL")
+	}
+	lines = append(lines, "END", streamLine(serial, "CA", "", 9, 0.0, 1.0))
+
+	// Mixed line endings: \r\n, \r and \n
+	var input strings.Builder
+	for i, line := range lines {
+		input.WriteString(line)
+		input.WriteString([]string{"\r\n", "\r", "\n"}[i%3])
+	}
+
+	residues, err := streamResidues(input.String())
+	if err != nil {
+		t.Fatalf("ParsePDBStream failed: %v", err)
+	}
+	if len(residues) != 8 {
+		t.Fatalf("Expected 8 residues (4 per model, none after END), got %d", len(residues))
+	}
+	for i, res := range residues {
+		if i%4 < 3 && !res.HasCompleteBackbone() {
+			t.Errorf("Residue %d (%d) has incomplete backbone", i, res.SeqNum)
+		}
+	}
+	if ca := residues[3].CA; ca == nil || ca.AltLoc != "B" || ca.X != 20.0 {
+		t.Errorf("Residue 4 CA should be conformer B, got %+v", ca)
+	}
+	if ca := residues[4].CA; ca.X != 2.0 {
+		t.Errorf("Residue 5 should come from model 2 (x = 2), got x = %.1f", ca.X)
+	}
+}
+
+func TestParsePDBStreamTruncated(t *testing.T) {
+	complete := streamLine(1, "N", "", 1, 0.0, 1.0) + "\n" + streamLine(2, "CA", "", 1, 1.0, 1.0) + "\n"
+
+	// Ends after a full record, but inside the residue
+	residues, err := streamResidues(complete)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF mid-residue, got %v", err)
+	}
+	if len(residues) != 0 {
+		t.Errorf("Incomplete residue should not be emitted, got %d", len(residues))
+	}
+
+	// Ends inside an ATOM record
+	_, err = streamResidues(complete + streamLine(3, "C", "", 1, 2.0, 1.0)[:40])
+	if !errors.Is(err, io.ErrUnexpectedEOF) || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected truncated record error at line 3, got %v", err)
+	}
+
+	// TER closes the residue; no END needed
+	residues, err = streamResidues(complete + "TER")
+	if err != nil || len(residues) != 1 {
+		t.Errorf("TER-terminated file: %d residues, error %v", len(residues), err)
+	}
+}
+
+func TestParsePDBStreamCallbackError(t *testing.T) {
+	var input strings.Builder
+	for resSeq := 1; resSeq <= 5; resSeq++ {
+		input.WriteString(streamLine(resSeq, "CA", "", resSeq, 0.0, 1.0) + "\n")
+	}
+	input.WriteString("END\n")
+
+	stop := errors.New("stop")
+	calls := 0
+	err := ParsePDBStream(strings.NewReader(input.String()), func(res *Residue) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Stream continued after the callback failed: %d calls", calls)
+	}
+}
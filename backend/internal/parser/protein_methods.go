@@ -40,6 +40,7 @@ func (p *Protein) Copy() *Protein {
 		clonedRes := &Residue{
 			Name:    res.Name,
 			SeqNum:  res.SeqNum,
+			ICode:   res.ICode,
 			ChainID: res.ChainID,
 		}
 		if res.N != nil {
@@ -57,10 +58,15 @@ func (p *Protein) Copy() *Protein {
 		clone.Residues[i] = clonedRes
 	}
 
+	clone.SSRecords = append([]SSRecord(nil), p.SSRecords...)
+
 	return clone
 }
 
 // Sequence returns the amino acid sequence as a string
+//
+// Modified residues read as their parent (MSE → M, see ParentResidue);
+// anything else non-standard is X.
 func (p *Protein) Sequence() string {
 	if p == nil || len(p.Residues) == 0 {
 		return ""
@@ -69,25 +75,26 @@ func (p *Protein) Sequence() string {
 	sequence := make([]byte, len(p.Residues))
 	for i, res := range p.Residues {
 		// Convert three-letter code to one-letter
-		// Simplified mapping (expand as needed)
-		code := threeToOne(res.Name)
-		sequence[i] = code
+		sequence[i] = ThreeToOne(res.Name)
 	}
 	return string(sequence)
 }
 
-// threeToOne converts three-letter amino acid code to one-letter
-func threeToOne(threeLetter string) byte {
-	mapping := map[string]byte{
-		"ALA": 'A', "CYS": 'C', "ASP": 'D', "GLU": 'E',
-		"PHE": 'F', "GLY": 'G', "HIS": 'H', "ILE": 'I',
-		"LYS": 'K', "LEU": 'L', "MET": 'M', "ASN": 'N',
-		"PRO": 'P', "GLN": 'Q', "ARG": 'R', "SER": 'S',
-		"THR": 'T', "VAL": 'V', "TRP": 'W', "TYR": 'Y',
+// OneLetterSequence returns the one-letter sequence of any protein: like
+// Sequence, but residues named by one-letter code, as the coordinate
+// builders name them, read as themselves rather than X
+func (p *Protein) OneLetterSequence() string {
+	if p == nil {
+		return ""
 	}
 
-	if code, ok := mapping[threeLetter]; ok {
-		return code
+	sequence := make([]byte, len(p.Residues))
+	for i, res := range p.Residues {
+		code, ok := residueLetter(res.Name)
+		if !ok {
+			code = 'X'
+		}
+		sequence[i] = code
 	}
-	return 'X' // Unknown
+	return string(sequence)
 }
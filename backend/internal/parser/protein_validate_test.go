@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// backboneChain builds a straight chain of residues with N, CA and C
+// 3.8 Å apart per residue and 1.3 Å peptide bonds
+func backboneChain(names []string) *Protein {
+	protein := &Protein{}
+	for i, name := range names {
+		res := &Residue{Name: name, SeqNum: i + 1, ChainID: "A"}
+		for j, atomName := range []string{"N", "CA", "C"} {
+			atom := &Atom{Serial: len(protein.Atoms) + 1, Name: atomName, ResName: name, ChainID: "A",
+				ResSeq: i + 1, X: 3.8*float64(i) + 1.25*float64(j), Element: atomName[:1]}
+			protein.Atoms = append(protein.Atoms, atom)
+			switch atomName {
+			case "N":
+				res.N = atom
+			case "CA":
+				res.CA = atom
+			case "C":
+				res.C = atom
+			}
+		}
+		protein.Residues = append(protein.Residues, res)
+	}
+	return protein
+}
+
+// TestResidueCodesAndValidate converts all twenty standard residues both
+// ways, validates a chain of them, and checks an unknown residue, a
+// missing CA and a chain break are each reported
+func TestResidueCodesAndValidate(t *testing.T) {
+	var names []string
+	for _, code := range []byte(standardAminoAcids) {
+		three := OneToThree(code)
+		if ThreeToOne(three) != code || OneToThree(code+'a'-'A') != three {
+			t.Errorf("%c → %s → %c", code, three, ThreeToOne(three))
+		}
+		names = append(names, three)
+	}
+	if ThreeToOne("XYZ") != 'X' || OneToThree('B') != "UNK" || ThreeToOne("MSE") != 'M' {
+		t.Errorf("Unknown or modified codes: XYZ → %c, B → %s, MSE → %c", ThreeToOne("XYZ"), OneToThree('B'), ThreeToOne("MSE"))
+	}
+
+	protein := backboneChain(names)
+	if seq := protein.OneLetterSequence(); seq != standardAminoAcids {
+		t.Errorf("OneLetterSequence = %s, want %s", seq, standardAminoAcids)
+	}
+	if err := protein.Validate(); err != nil {
+		t.Errorf("Valid chain of the 20 standard residues: %v", err)
+	}
+	built := backboneChain(strings.Split(standardAminoAcids, ""))
+	if seq := built.OneLetterSequence(); seq != standardAminoAcids || built.Validate() != nil {
+		t.Errorf("One-letter residue names: sequence %s, validation %v", seq, built.Validate())
+	}
+
+	protein = backboneChain(append(names, "XYZ"))
+	err := protein.Validate()
+	t.Logf("Unknown residue: %v", err)
+	if err == nil || !strings.Contains(err.Error(), `unknown residue "XYZ"`) {
+		t.Errorf("Unknown residue XYZ not reported: %v", err)
+	}
+	if seq := protein.OneLetterSequence(); seq != standardAminoAcids+"X" {
+		t.Errorf("OneLetterSequence = %s, want %sX", seq, standardAminoAcids)
+	}
+
+	protein = backboneChain(names[:5])
+	protein.Residues[2].CA = nil
+	for _, atom := range protein.Atoms[3*3:] {
+		atom.X += 5 // Gap between residues 2 and 3
+	}
+	err = protein.Validate()
+	t.Logf("Missing CA and chain break: %v", err)
+	if err == nil || !strings.Contains(err.Error(), "missing backbone atoms [CA]") ||
+		!strings.Contains(err.Error(), "chain break between residues 2") {
+		t.Errorf("Missing CA or chain break not reported: %v", err)
+	}
+}
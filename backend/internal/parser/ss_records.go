@@ -0,0 +1,124 @@
+// Package parser - HELIX and SHEET records
+//
+// Most PDB entries say where their helices and strands are, in HELIX and
+// SHEET header records. That annotation is free secondary-structure ground
+// truth for every structure we read, so it is kept in Protein.SSRecords and
+// mapped onto the residues by AnnotatedSecondaryStructure.
+//
+// BIOCHEMIST: The records are author (or DSSP-at-deposition) assignments;
+// every helix class (α, 3₁₀, π, ...) counts as helix, as DSSP's H, G and I
+// reduce to H in three-state comparisons
+// ETHICIST: Only the PDB format carries them here; mmCIF's struct_conf and
+// struct_sheet_range categories are not read
+//
+// CITATION:
+// wwPDB Atomic Coordinate Entry Format Version 3.3, "HELIX" and "SHEET"
+// (https://www.wwpdb.org/documentation/file-format).
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SSRecordKind is the record a secondary-structure element came from
+type SSRecordKind string
+
+const (
+	SSRecordHelix SSRecordKind = "HELIX"
+	SSRecordSheet SSRecordKind = "SHEET" // One strand of a sheet
+)
+
+// SSRecord is one HELIX or SHEET record: an element spanning the residues
+// from (ChainID, StartSeq, StartICode) to (ChainID, EndSeq, EndICode)
+type SSRecord struct {
+	Kind    SSRecordKind
+	ID      string // Helix ID, or sheet ID for a strand
+	ChainID string
+
+	StartSeq   int
+	StartICode string
+	EndSeq     int
+	EndICode   string
+
+	HelixClass int // HELIX class (1 = right-handed α, 5 = 3₁₀, ...); 0 for strands
+}
+
+// parseSSRecord parses a HELIX or SHEET line; ok is false for other
+// records and for lines too short or malformed to place the element
+func parseSSRecord(line string) (record SSRecord, ok bool) {
+	field := func(start, end int) string {
+		if start >= len(line) {
+			return ""
+		}
+		return strings.TrimSpace(line[start:min(end, len(line))])
+	}
+	number := func(start, end int) (int, bool) {
+		n, err := strconv.Atoi(field(start, end))
+		return n, err == nil
+	}
+
+	// Fixed columns (0-based, end exclusive) of the initial and terminal
+	// residue: chain, seqNum, iCode, chain, seqNum, iCode
+	var cols [6][2]int
+	switch {
+	case strings.HasPrefix(line, "HELIX "):
+		record = SSRecord{Kind: SSRecordHelix, ID: field(11, 14)}
+		cols = [6][2]int{{19, 20}, {21, 25}, {25, 26}, {31, 32}, {33, 37}, {37, 38}}
+		record.HelixClass, _ = number(38, 40)
+	case strings.HasPrefix(line, "SHEET "):
+		record = SSRecord{Kind: SSRecordSheet, ID: field(11, 14)}
+		cols = [6][2]int{{21, 22}, {22, 26}, {26, 27}, {32, 33}, {33, 37}, {37, 38}}
+	default:
+		return SSRecord{}, false
+	}
+
+	var startOK, endOK bool
+	record.ChainID = field(cols[0][0], cols[0][1])
+	record.StartSeq, startOK = number(cols[1][0], cols[1][1])
+	record.StartICode = field(cols[2][0], cols[2][1])
+	record.EndSeq, endOK = number(cols[4][0], cols[4][1])
+	record.EndICode = field(cols[5][0], cols[5][1])
+	if !startOK || !endOK || field(cols[3][0], cols[3][1]) != record.ChainID {
+		return SSRecord{}, false
+	}
+	return record, true
+}
+
+// AnnotatedSecondaryStructure returns, per residue of p, the kind of the
+// HELIX or SHEET record covering it ("" for none)
+//
+// A record covers its start and end residues and every residue of its
+// chain between them in p.Residues order, so insertion codes are honoured.
+// Records whose start or end residue is not in p are skipped. Returns nil
+// if p has no records.
+func (p *Protein) AnnotatedSecondaryStructure() []SSRecordKind {
+	if p == nil || len(p.SSRecords) == 0 {
+		return nil
+	}
+
+	type key struct {
+		chain string
+		seq   int
+		iCode string
+	}
+	index := make(map[key]int, len(p.Residues))
+	for i, res := range p.Residues {
+		index[key{res.ChainID, res.SeqNum, res.ICode}] = i
+	}
+
+	kinds := make([]SSRecordKind, len(p.Residues))
+	for _, record := range p.SSRecords {
+		start, okStart := index[key{record.ChainID, record.StartSeq, record.StartICode}]
+		end, okEnd := index[key{record.ChainID, record.EndSeq, record.EndICode}]
+		if !okStart || !okEnd {
+			continue
+		}
+		for i := start; i <= end; i++ {
+			if p.Residues[i].ChainID == record.ChainID {
+				kinds[i] = record.Kind
+			}
+		}
+	}
+	return kinds
+}
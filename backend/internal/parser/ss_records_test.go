@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestHelixSheetRecords parses a chain with a HELIX record over residues
+// 2-5 and a SHEET strand over 7-8, and checks exactly those residues are
+// annotated
+func TestHelixSheetRecords(t *testing.T) {
+	var pdb strings.Builder
+	fmt.Fprintf(&pdb, "HELIX  %3d %3s %3s %1s %4d%1s %3s %1s %4d%1s%2d\n", 1, "1", "GLU", "A", 2, "", "LYS", "A", 5, "", 1)
+	fmt.Fprintf(&pdb, "SHEET  %3d %3s%2d %3s %1s%4d%1s %3s %1s%4d%1s%2d\n", 1, "S1", 1, "VAL", "A", 7, "", "ILE", "A", 8, "", 0)
+	serial := 1
+	for i, resName := range []string{"GLY", "GLU", "ALA", "LEU", "LYS", "GLY", "VAL", "ILE"} {
+		for j, name := range []string{"N", "CA", "C", "O"} {
+			fmt.Fprintf(&pdb, "ATOM  %5d  %-3s %3s A%4d    %8.3f%8.3f%8.3f%6.2f%6.2f          %2s\n",
+				serial, name, resName, i+1, float64(i)*3.8+float64(j)*0.5, 0.0, 0.0, 1.0, 10.0, name[:1])
+			serial++
+		}
+	}
+	pdb.WriteString("END\n")
+
+	path := t.TempDir() + "/ss.pdb"
+	if err := os.WriteFile(path, []byte(pdb.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write PDB: %v", err)
+	}
+	protein, err := ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+
+	if len(protein.SSRecords) != 2 {
+		t.Fatalf("Expected 2 SS records, got %d: %+v", len(protein.SSRecords), protein.SSRecords)
+	}
+	helix := protein.SSRecords[0]
+	if helix.Kind != SSRecordHelix || helix.ChainID != "A" || helix.StartSeq != 2 || helix.EndSeq != 5 || helix.HelixClass != 1 {
+		t.Errorf("HELIX record parsed as %+v", helix)
+	}
+
+	want := []SSRecordKind{"", SSRecordHelix, SSRecordHelix, SSRecordHelix, SSRecordHelix, "", SSRecordSheet, SSRecordSheet}
+	got := protein.AnnotatedSecondaryStructure()
+	if len(got) != len(want) {
+		t.Fatalf("Annotation has %d residues, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Residue %d (%s %d): annotated %q, want %q", i, protein.Residues[i].Name, protein.Residues[i].SeqNum, got[i], want[i])
+		}
+	}
+
+	if clone := protein.Copy(); len(clone.SSRecords) != 2 {
+		t.Errorf("Copy kept %d SS records, want 2", len(clone.SSRecords))
+	}
+	protein.SSRecords = nil
+	if protein.AnnotatedSecondaryStructure() != nil {
+		t.Error("Protein without records has an annotation")
+	}
+}
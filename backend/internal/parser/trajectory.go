@@ -0,0 +1,119 @@
+// Package parser - Sampling trajectories
+//
+// Monte Carlo and simulated annealing keep only their best structure. A
+// Trajectory records a snapshot every Stride accepted steps, up to MaxFrames,
+// so a run can be replayed in PyMOL/VMD/Chimera as a multi-MODEL PDB or an
+// XYZ animation.
+//
+// BIOCHEMIST: Frames show how the chain moved, not just where it ended
+// ETHICIST: Memory is bounded - frames past MaxFrames are counted, not kept
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TrajectoryFrame is one recorded snapshot
+type TrajectoryFrame struct {
+	Step    int      // Sampling step at which the snapshot was taken
+	Energy  float64  // Energy of the snapshot (in Trajectory.EnergyUnit)
+	Protein *Protein // Deep copy of the structure
+}
+
+// Trajectory records snapshots of an accepted-move sequence
+type Trajectory struct {
+	Stride    int // Record every Stride-th accepted step
+	MaxFrames int // Keep at most MaxFrames frames (<= 0: unbounded)
+
+	EnergyUnit string // Unit of the frame energies ("": kcal/mol)
+
+	Frames  []TrajectoryFrame
+	Dropped int // Frames not kept because MaxFrames was reached
+}
+
+// NewTrajectory returns a trajectory recording every stride accepted steps,
+// or nil (recording off) when stride <= 0
+//
+// Record and Len are safe on a nil trajectory.
+func NewTrajectory(stride, maxFrames int) *Trajectory {
+	if stride <= 0 {
+		return nil
+	}
+	return &Trajectory{Stride: stride, MaxFrames: maxFrames}
+}
+
+// Record snapshots protein if accepted (the running count of accepted
+// steps) is a multiple of the stride
+func (t *Trajectory) Record(accepted, step int, energy float64, protein *Protein) {
+	if t == nil || accepted <= 0 || accepted%t.Stride != 0 {
+		return
+	}
+	if t.MaxFrames > 0 && len(t.Frames) >= t.MaxFrames {
+		t.Dropped++
+		return
+	}
+	t.Frames = append(t.Frames, TrajectoryFrame{Step: step, Energy: energy, Protein: protein.Copy()})
+}
+
+// Len returns the number of recorded frames
+func (t *Trajectory) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.Frames)
+}
+
+// WritePDB writes the frames as a multi-MODEL PDB file, with each frame's
+// step and energy as a REMARK
+func (t *Trajectory) WritePDB(filename string) error {
+	if t.Len() == 0 {
+		return fmt.Errorf("trajectory has no frames")
+	}
+
+	unit := "KCAL/MOL"
+	if t.EnergyUnit != "" {
+		unit = strings.ToUpper(t.EnergyUnit)
+	}
+
+	models := make([]*Protein, len(t.Frames))
+	remarks := []string{fmt.Sprintf("TRAJECTORY: %d FRAMES, STRIDE %d ACCEPTED STEPS", len(t.Frames), t.Stride)}
+	for i, frame := range t.Frames {
+		models[i] = frame.Protein
+		remarks = append(remarks, fmt.Sprintf("MODEL %d: STEP %d, ENERGY %.3f %s", i+1, frame.Step, frame.Energy, unit))
+	}
+	return WritePDBModels(models, filename, remarks)
+}
+
+// WriteXYZ writes the frames as a multi-frame XYZ file: per frame the atom
+// count, a comment with step and energy, then "element x y z" lines
+func (t *Trajectory) WriteXYZ(filename string) error {
+	if t.Len() == 0 {
+		return fmt.Errorf("trajectory has no frames")
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create XYZ file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, frame := range t.Frames {
+		fmt.Fprintf(w, "%d\n", len(frame.Protein.Atoms))
+		fmt.Fprintf(w, "step=%d energy=%.3f\n", frame.Step, frame.Energy)
+		for _, atom := range frame.Protein.Atoms {
+			element := atom.Element
+			if element == "" && len(atom.Name) > 0 {
+				element = atom.Name[:1]
+			}
+			fmt.Fprintf(w, "%-2s %12.5f %12.5f %12.5f\n", element, atom.X, atom.Y, atom.Z)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write XYZ file: %w", err)
+	}
+	return nil
+}
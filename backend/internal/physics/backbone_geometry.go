@@ -0,0 +1,142 @@
+// Package physics - Backbone geometry validation
+//
+// ValidateCoordinates rejects a structure whose peptide bond is outside
+// 1-2 Å, which catches a broken chain but says nothing about a bond at
+// 1.6 Å or a squeezed N-CA-C angle. ValidateBackboneGeometry measures every
+// backbone bond and angle against its ff14SB equilibrium value and reports
+// each one out of tolerance, with the residue and by how much.
+//
+// BIOCHEMIST: Backbone bond lengths vary by ~0.02 Å and angles by ~2-3°
+// in high-resolution structures; the tolerances here are wide enough to
+// pass any sensible model and flag only genuine distortion
+//
+// CITATION:
+// Engh, R. A., & Huber, R. (1991). "Accurate bond and angle parameters for
+// X-ray protein structure refinement." Acta Cryst. A47: 392-400.
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Backbone geometry tolerances
+const (
+	PeptideBondMin = 1.20 // C(i)-N(i+1), Å
+	PeptideBondMax = 1.50
+
+	BondLengthTolerance = 0.10 // N-CA and CA-C, Å either side of ideal
+	BondAngleTolerance  = 10.0 // N-CA-C, CA-C-N and C-N-CA, degrees either side of ideal
+)
+
+// GeometryViolation is one backbone bond or angle out of tolerance
+type GeometryViolation struct {
+	ResidueIndex int    // 0-based; the residue of the bond's first atom or the angle's central atom
+	Residue      string // Name, chain and number, e.g. "ALA A12"
+	Measure      string // "C-N", "N-CA", "CA-C", "N-CA-C", "CA-C-N" or "C-N-CA"
+	IsAngle      bool   // Value, Ideal, Min and Max are degrees if true, else Å
+
+	Value    float64 // Measured (NaN for non-finite coordinates)
+	Ideal    float64
+	Min, Max float64 // Allowed range
+}
+
+// Deviation is Value - Ideal
+func (v GeometryViolation) Deviation() float64 {
+	return v.Value - v.Ideal
+}
+
+// String describes the violation, e.g.
+// "residue 3 (LEU A4) C-N 1.83 Å, ideal 1.33 (allowed 1.20-1.50)"
+func (v GeometryViolation) String() string {
+	unit, format := "Å", "%.2f"
+	if v.IsAngle {
+		unit, format = "°", "%.1f"
+	}
+	return fmt.Sprintf("residue %d (%s) %s "+format+" %s, ideal "+format+" (allowed "+format+"-"+format+")",
+		v.ResidueIndex, v.Residue, v.Measure, v.Value, unit, v.Ideal, v.Min, v.Max)
+}
+
+// ValidateBackboneGeometry measures the N-CA and CA-C bonds and N-CA-C
+// angle of every residue, and the C-N peptide bond and CA-C-N and C-N-CA
+// angles between consecutive residues of a chain, returning those out of
+// tolerance in the order met along the chain (nil if none)
+//
+// Bonds and angles with a missing atom are skipped. A structure fresh from
+// geometry.BuildProteinFromAngles has none; a chain break shows as a
+// stretched C-N bond.
+func ValidateBackboneGeometry(protein *parser.Protein) []GeometryViolation {
+	if protein == nil {
+		return nil
+	}
+
+	var violations []GeometryViolation
+	bond := func(index int, res *parser.Residue, measure string, a, b *parser.Atom, lo, hi float64) {
+		if a == nil || b == nil {
+			return
+		}
+		value := atomVector(a).Sub(atomVector(b)).Magnitude()
+		if math.IsNaN(value) || value < lo || value > hi {
+			violations = append(violations, GeometryViolation{
+				ResidueIndex: index, Residue: residueLabel(res), Measure: measure,
+				Value: value, Ideal: backboneBondParams[measure].R0, Min: lo, Max: hi,
+			})
+		}
+	}
+	angle := func(index int, res *parser.Residue, measure string, a, b, c *parser.Atom) {
+		if a == nil || b == nil || c == nil {
+			return
+		}
+		ideal := backboneAngleParams[measure].Theta0 * 180 / math.Pi
+		value := bondAngleDegrees(a, b, c)
+		lo, hi := ideal-BondAngleTolerance, ideal+BondAngleTolerance
+		if math.IsNaN(value) || value < lo || value > hi {
+			violations = append(violations, GeometryViolation{
+				ResidueIndex: index, Residue: residueLabel(res), Measure: measure, IsAngle: true,
+				Value: value, Ideal: ideal, Min: lo, Max: hi,
+			})
+		}
+	}
+	bondRange := func(measure string) (float64, float64) {
+		ideal := backboneBondParams[measure].R0
+		return ideal - BondLengthTolerance, ideal + BondLengthTolerance
+	}
+
+	for i, res := range protein.Residues {
+		if res == nil {
+			continue
+		}
+		lo, hi := bondRange("N-CA")
+		bond(i, res, "N-CA", res.N, res.CA, lo, hi)
+		lo, hi = bondRange("CA-C")
+		bond(i, res, "CA-C", res.CA, res.C, lo, hi)
+		angle(i, res, "N-CA-C", res.N, res.CA, res.C)
+
+		if i+1 >= len(protein.Residues) {
+			continue
+		}
+		next := protein.Residues[i+1]
+		if next == nil || next.ChainID != res.ChainID {
+			continue
+		}
+		bond(i, res, "C-N", res.C, next.N, PeptideBondMin, PeptideBondMax)
+		angle(i, res, "CA-C-N", res.CA, res.C, next.N)
+		angle(i+1, next, "C-N-CA", res.C, next.N, next.CA)
+	}
+	return violations
+}
+
+// bondAngleDegrees is the angle a-b-c at b in degrees
+func bondAngleDegrees(a, b, c *parser.Atom) float64 {
+	v1 := atomVector(a).Sub(atomVector(b))
+	v2 := atomVector(c).Sub(atomVector(b))
+	cos := v1.Dot(v2) / (v1.Magnitude() * v2.Magnitude())
+	return math.Acos(math.Max(-1, math.Min(1, cos))) * 180 / math.Pi
+}
+
+// residueLabel is a residue's name, chain and number, e.g. "ALA A12"
+func residueLabel(res *parser.Residue) string {
+	return fmt.Sprintf("%s %s%d%s", res.Name, res.ChainID, res.SeqNum, res.ICode)
+}
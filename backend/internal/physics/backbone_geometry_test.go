@@ -0,0 +1,55 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+)
+
+// TestValidateBackboneGeometry checks a built helix has no violations, then
+// stretches the peptide bond between residues 3 and 4 along its own axis
+// (so no angle changes) and expects exactly that bond reported
+func TestValidateBackboneGeometry(t *testing.T) {
+	angles := make([]geometry.RamachandranAngles, 8)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -57 * math.Pi / 180, Psi: -47 * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles("AKLVEGAK", angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	if violations := ValidateBackboneGeometry(protein); len(violations) != 0 {
+		t.Fatalf("Built helix has %d violations, first: %s", len(violations), violations[0])
+	}
+
+	c, n := protein.Residues[3].C, protein.Residues[4].N
+	bond := atomVector(n).Sub(atomVector(c))
+	shift := bond.Mul(0.5 / bond.Magnitude()) // 0.5 Å longer
+	for _, atom := range protein.Atoms {
+		if atom.ResSeq >= protein.Residues[4].SeqNum {
+			atom.X, atom.Y, atom.Z = atom.X+shift.X, atom.Y+shift.Y, atom.Z+shift.Z
+		}
+	}
+	want := bond.Magnitude() + 0.5
+
+	violations := ValidateBackboneGeometry(protein)
+	for _, v := range violations {
+		t.Logf("Violation: %s", v)
+	}
+	if len(violations) != 1 {
+		t.Fatalf("%d violations, want exactly the stretched C-N bond", len(violations))
+	}
+	v := violations[0]
+	if v.Measure != "C-N" || v.ResidueIndex != 3 || v.IsAngle || math.Abs(v.Value-want) > 1e-9 {
+		t.Errorf("Got %s (%.4f Å), want residue 3 C-N at %.4f Å", v, v.Value, want)
+	}
+	if math.Abs(v.Deviation()-(want-v.Ideal)) > 1e-12 || v.Max != PeptideBondMax {
+		t.Errorf("Deviation %.3f Å, allowed up to %.2f", v.Deviation(), v.Max)
+	}
+
+	_, report := ScoreStructureQuality(protein)
+	if len(report.GeometryViolations) != 1 || report.GeometryViolations[0] != v {
+		t.Errorf("ScoreStructureQuality reported %v", report.GeometryViolations)
+	}
+}
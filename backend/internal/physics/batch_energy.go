@@ -0,0 +1,124 @@
+// Package physics - Parallel ensemble energy evaluation
+//
+// Scoring an ensemble calls CalculateTotalEnergyWithConfig once per
+// structure, one after another, and spends most of it in two all-pairs
+// non-bonded loops that each compute every distance. CalculateTotalEnergyBatch
+// spreads the structures over a worker pool and, per structure, computes
+// the distances once into a neighbor list of the pairs within the longer
+// cutoff; the van der Waals and Coulomb sums both walk that list. Each
+// worker reuses its list's storage from one structure to the next.
+//
+// PHYSICIST: Pairs beyond the cutoff contribute exactly 0 in the serial
+// loops, so dropping them changes nothing
+// MATHEMATICIAN: The list is kept in the serial loops' (i, j) order, so
+// every floating-point sum is performed in the same order and the results
+// are bit-for-bit those of CalculateTotalEnergyWithConfig
+//
+// A cell grid would find the pairs in O(n), but restoring the serial order
+// afterwards costs more than it saves at the sizes folded here.
+package physics
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// CalculateTotalEnergyBatch evaluates CalculateTotalEnergyWithConfig for
+// every protein on runtime.NumCPU() workers
+//
+// Results are in input order and identical to serial evaluation; a nil
+// protein gets zero components. The proteins are only read.
+func CalculateTotalEnergyBatch(proteins []*parser.Protein, config EnergyConfig) []EnergyComponents {
+	return calculateTotalEnergyBatch(proteins, config, runtime.NumCPU())
+}
+
+// calculateTotalEnergyBatch is CalculateTotalEnergyBatch on a given number
+// of workers
+func calculateTotalEnergyBatch(proteins []*parser.Protein, config EnergyConfig, workers int) []EnergyComponents {
+	results := make([]EnergyComponents, len(proteins))
+	if workers > len(proteins) {
+		workers = len(proteins)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var pairs []nonBondedPair // Reused across this worker's structures
+			for i := range jobs {
+				if proteins[i] == nil {
+					continue
+				}
+				// Each index is written by exactly one worker
+				results[i], pairs = neighborListEnergy(proteins[i], config, pairs[:0])
+			}
+		}()
+	}
+	for i := range proteins {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// nonBondedPair is an atom pair (i < j) of the neighbor list with its distance
+type nonBondedPair struct {
+	i, j int32
+	r    float64
+}
+
+// neighborListEnergy computes the total energy with the non-bonded sums
+// taken over a neighbor list, built into pairs (whose backing array is
+// returned for reuse)
+func neighborListEnergy(protein *parser.Protein, config EnergyConfig, pairs []nonBondedPair) (EnergyComponents, []nonBondedPair) {
+	pairs = buildNeighborList(protein, math.Max(config.VdWCutoff, config.ElecCutoff), pairs)
+
+	atoms := protein.Atoms
+	lj := ljParameters(protein)
+	charges := partialCharges(protein)
+	vdw, elec := 0.0, 0.0
+	for _, p := range pairs {
+		a, b := atoms[p.i], atoms[p.j]
+		// !(r > cutoff) mirrors the pair energies' own test, NaN included
+		if !(p.r > config.VdWCutoff) {
+			vdw += lennardJonesPairEnergy(a, b, lj[p.i], lj[p.j], config.VdWSwitchStart, config.VdWCutoff)
+		}
+		if !(p.r > config.ElecCutoff) && charges[p.i] != 0 && charges[p.j] != 0 {
+			elec += electrostaticPairEnergy(a, b, charges[p.i], charges[p.j], config.ElecSwitchStart, config.ElecCutoff)
+		}
+	}
+
+	return totalEnergyWithNonBonded(protein, config, vdw, elec), pairs
+}
+
+// buildNeighborList appends to pairs every atom pair more than one residue
+// apart that the pair energies do not cut off at cutoff, in the serial
+// loops' (i, j) order
+func buildNeighborList(protein *parser.Protein, cutoff float64, pairs []nonBondedPair) []nonBondedPair {
+	atoms := protein.Atoms
+	for i, a := range atoms {
+		for j := i + 1; j < len(atoms); j++ {
+			b := atoms[j]
+			// Same residue or adjacent residues: bonded/1-4, as in the serial loops
+			if math.Abs(float64(a.ResSeq-b.ResSeq)) <= 1 {
+				continue
+			}
+			// Same expression as the pair energies, so the cutoff test agrees
+			dx, dy, dz := b.X-a.X, b.Y-a.Y, b.Z-a.Z
+			if r := math.Sqrt(dx*dx + dy*dy + dz*dz); !(r > cutoff) {
+				pairs = append(pairs, nonBondedPair{i: int32(i), j: int32(j), r: r})
+			}
+		}
+	}
+	return pairs
+}
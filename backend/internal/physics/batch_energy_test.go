@@ -0,0 +1,91 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// batchEnsemble builds count random-(φ, ψ) structures of a 20-residue
+// Trp-cage-like sequence
+func batchEnsemble(tb testing.TB, count int) []*parser.Protein {
+	tb.Helper()
+	const sequence = "NLYIQWLKDGGPSSGRPPPS"
+	rng := rand.New(rand.NewSource(7))
+	ensemble := make([]*parser.Protein, count)
+	for k := range ensemble {
+		angles := make([]geometry.RamachandranAngles, len(sequence))
+		for i := range angles {
+			angles[i] = geometry.RamachandranAngles{Phi: (rng.Float64()*2 - 1) * math.Pi, Psi: (rng.Float64()*2 - 1) * math.Pi}
+		}
+		protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+		if err != nil {
+			tb.Fatalf("Build failed: %v", err)
+		}
+		ensemble[k] = protein
+	}
+	return ensemble
+}
+
+// TestCalculateTotalEnergyBatch checks batch energies equal serial ones
+// exactly, in order, for any number of workers
+func TestCalculateTotalEnergyBatch(t *testing.T) {
+	ensemble := batchEnsemble(t, 20)
+	ensemble = append(ensemble, nil)
+
+	configs := map[string]EnergyConfig{
+		"default":     DefaultEnergyConfig(),
+		"hard cutoff": {VdWCutoff: 8, ElecCutoff: 12},
+		"all terms":   {VdWCutoff: 10, ElecCutoff: 12, VdWSwitchStart: 8, ElecSwitchStart: 10, UseCMAP: true, UseHBonds: true, UseImpropers: true},
+	}
+	for name, config := range configs {
+		serial := make([]EnergyComponents, len(ensemble))
+		for i, protein := range ensemble {
+			if protein != nil {
+				serial[i] = CalculateTotalEnergyWithConfig(protein, config)
+			}
+		}
+
+		for _, workers := range []int{1, 3, runtime.NumCPU()} {
+			batch := calculateTotalEnergyBatch(ensemble, config, workers)
+			if len(batch) != len(ensemble) {
+				t.Fatalf("%s, %d workers: %d results for %d structures", name, workers, len(batch), len(ensemble))
+			}
+			for i := range batch {
+				if batch[i] != serial[i] {
+					t.Errorf("%s, %d workers: structure %d batch %+v != serial %+v", name, workers, i, batch[i], serial[i])
+				}
+			}
+		}
+		t.Logf("%s: structure 0 E=%.3f vdW=%.3f elec=%.3f", name, serial[0].Total, serial[0].VanDerWaals, serial[0].Electrostatic)
+	}
+
+	if got := CalculateTotalEnergyBatch(ensemble[:3], DefaultEnergyConfig()); got[2] != CalculateTotalEnergyWithConfig(ensemble[2], DefaultEnergyConfig()) {
+		t.Errorf("CalculateTotalEnergyBatch disagrees with serial evaluation")
+	}
+}
+
+func BenchmarkCalculateTotalEnergyBatch(b *testing.B) {
+	ensemble := batchEnsemble(b, 64)
+	config := DefaultEnergyConfig()
+
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, protein := range ensemble {
+				CalculateTotalEnergyWithConfig(protein, config)
+			}
+		}
+	})
+	for workers := 1; workers <= runtime.NumCPU(); workers *= 2 {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				calculateTotalEnergyBatch(ensemble, config, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestBondedForces checks each bonded term's analytical forces against the
+// central finite-difference gradient of its own energy, and that the terms
+// compose with the rest in CalculateForces
+func TestBondedForces(t *testing.T) {
+	protein := buildAlanineDipeptide(-70, 140)
+
+	// Off ideal geometry so bonds and angles carry force
+	rng := rand.New(rand.NewSource(11))
+	for _, atom := range protein.Atoms {
+		atom.X += 0.05 * rng.NormFloat64()
+		atom.Y += 0.05 * rng.NormFloat64()
+		atom.Z += 0.05 * rng.NormFloat64()
+	}
+
+	terms := []struct {
+		name   string
+		energy func(*parser.Protein) float64
+		forces func(*parser.Protein) map[int]Vector3
+	}{
+		{"bond", calculateBondEnergyTotal, BondStretchForces},
+		{"angle", calculateAngleEnergyTotal, AngleBendForces},
+		{"dihedral", TorsionEnergy, ProperDihedralForces},
+	}
+
+	for _, term := range terms {
+		forces := term.forces(protein)
+		maxError, maxForce := 0.0, 0.0
+		var net Vector3
+		for _, atom := range protein.Atoms {
+			analytical := forces[atom.Serial]
+			net = net.Add(analytical)
+			for k, coord := range []*float64{&atom.X, &atom.Y, &atom.Z} {
+				orig := *coord
+				*coord = orig + forceCheckStep
+				ePlus := term.energy(protein)
+				*coord = orig - forceCheckStep
+				eMinus := term.energy(protein)
+				*coord = orig
+
+				numerical := -(ePlus - eMinus) / (2 * forceCheckStep)
+				component := []float64{analytical.X, analytical.Y, analytical.Z}[k]
+				maxError = math.Max(maxError, math.Abs(component-numerical))
+				maxForce = math.Max(maxForce, math.Abs(numerical))
+			}
+		}
+
+		t.Logf("%-8s E=%8.3f max |F|=%7.3f max error=%.2e net |F|=%.1e",
+			term.name, term.energy(protein), maxForce, maxError, net.Magnitude())
+		if maxForce == 0 {
+			t.Errorf("%s: no force to check", term.name)
+		}
+		if maxError > 1e-4 {
+			t.Errorf("%s: max force error %.2e exceeds 1e-4 kcal/(mol·Å)", term.name, maxError)
+		}
+		if net.Magnitude() > 1e-8 {
+			t.Errorf("%s: forces sum to %.2e, want 0", term.name, net.Magnitude())
+		}
+	}
+
+	// Bonded + non-bonded = CalculateForces (optional terms off)
+	config := DefaultEnergyConfig()
+	total := CalculateForcesWithConfig(protein, config)
+	bonded := CalculateBondedForces(protein)
+	nonBonded := zeroForces(protein)
+	addNonBondedForces(protein, nonBonded, config)
+	for _, atom := range protein.Atoms {
+		sum := bonded[atom.Serial].Add(nonBonded[atom.Serial])
+		if d := sum.Sub(total[atom.Serial]).Magnitude(); d > 1e-12 {
+			t.Errorf("Atom %d %s: bonded + non-bonded differs from CalculateForces by %.2e", atom.Serial, atom.Name, d)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package physics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// uniformChain builds sequence with every residue at (phi, psi) degrees
+func uniformChain(tb testing.TB, sequence string, phi, psi float64) *parser.Protein {
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: phi * math.Pi / 180, Psi: psi * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		tb.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	return protein
+}
+
+// TestCAEnergyCompactBelowExtended checks a compact helix scores below the
+// same sequence as an extended strand, and that EnergyConfig.Model selects
+// the CA model
+func TestCAEnergyCompactBelowExtended(t *testing.T) {
+	sequence := "LKELLKKLAELLKKLAELLK"
+	compact := CalculateCAEnergy(uniformChain(t, sequence, -57, -47), DefaultCAModelConfig())
+	extended := CalculateCAEnergy(uniformChain(t, sequence, -120, 130), DefaultCAModelConfig())
+	t.Logf("Helix: bond %.3f, angle %.3f, contact %.2f, repulsion %.3f = %.2f kcal/mol",
+		compact.PseudoBond, compact.PseudoAngle, compact.Contact, compact.Repulsion, compact.Total)
+	t.Logf("Extended: %.2f kcal/mol", extended.Total)
+
+	if compact.Total >= extended.Total {
+		t.Errorf("Compact helix %.2f kcal/mol not below extended chain %.2f", compact.Total, extended.Total)
+	}
+	// Built CA-CA distances are 3.80 Å to within ~0.01 Å
+	if compact.PseudoBond > 0.1 || compact.PseudoAngle > 1e-6 || compact.Repulsion > 1e-6 {
+		t.Errorf("Ideal helix pays chain terms: bond %.2g, angle %.2g, repulsion %.2g",
+			compact.PseudoBond, compact.PseudoAngle, compact.Repulsion)
+	}
+
+	config := DefaultEnergyConfig()
+	config.Model = EnergyModelCA
+	if total := CalculateTotalEnergyWithConfig(uniformChain(t, sequence, -57, -47), config).Total; math.Abs(total-compact.Total) > 1e-9 {
+		t.Errorf("EnergyModelCA total %.4f, CalculateCAEnergy %.4f", total, compact.Total)
+	}
+}
+
+// TestCAModelSpeedup times the CA model against the all-atom energy on a
+// 150-residue chain; the CA model must be at least 10× faster
+func TestCAModelSpeedup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	ca := testing.Benchmark(BenchmarkCAEnergy150)
+	allAtom := testing.Benchmark(BenchmarkAllAtomEnergy150)
+	speedup := float64(allAtom.NsPerOp()) / float64(ca.NsPerOp())
+	t.Logf("150 residues: CA %v/op, all-atom %v/op: %.0f× faster",
+		ca.NsPerOp(), allAtom.NsPerOp(), speedup)
+	if speedup < 10 {
+		t.Errorf("CA model only %.1f× faster than all-atom", speedup)
+	}
+}
+
+// benchmarkChain150 is a 150-residue helix
+func benchmarkChain150(b *testing.B) *parser.Protein {
+	return uniformChain(b, strings.Repeat("MVLSEGEWQLVLHVWAKVEA", 8)[:150], -57, -47)
+}
+
+func BenchmarkCAEnergy150(b *testing.B) {
+	protein := benchmarkChain150(b)
+	config := DefaultEnergyConfig()
+	config.Model = EnergyModelCA
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculateTotalEnergyWithConfig(protein, config)
+	}
+}
+
+func BenchmarkAllAtomEnergy150(b *testing.B) {
+	protein := benchmarkChain150(b)
+	config := DefaultEnergyConfig()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculateTotalEnergyWithConfig(protein, config)
+	}
+}
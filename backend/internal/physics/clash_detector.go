@@ -15,6 +15,10 @@ type ClashReport struct {
 	Energy          float64
 	IsValid         bool
 	ValidationError string
+
+	// Backbone bonds and angles out of tolerance (ScoreStructureQuality
+	// only; see ValidateBackboneGeometry)
+	GeometryViolations []GeometryViolation
 }
 
 // DetectClashes checks for severe atomic overlaps
@@ -154,8 +158,9 @@ func ValidateCoordinates(protein *parser.Protein) ClashReport {
 
 // ScoreStructureQuality combines validation + clash detection
 func ScoreStructureQuality(protein *parser.Protein) (float64, ClashReport) {
-	// Validate coordinates first
+	// Validate coordinates first; the geometry check names the bonds at fault
 	report := ValidateCoordinates(protein)
+	report.GeometryViolations = ValidateBackboneGeometry(protein)
 	if !report.IsValid {
 		return -999999.9, report
 	}
@@ -250,3 +250,68 @@ func TestScoreStructureQuality_Invalid(t *testing.T) {
 		t.Error("Should mark structure as invalid")
 	}
 }
+
+// TestCalculateClashscore uses a hand-built structure with exactly two
+// serious clashes, plus a near miss, bonded pairs and a hydrogen bond that
+// must not be counted
+func TestCalculateClashscore(t *testing.T) {
+	atom := func(name, element string, resSeq int, x, y, z float64) *parser.Atom {
+		return &parser.Atom{Name: name, Element: element, ResName: "ALA", ChainID: "A", ResSeq: resSeq, X: x, Y: y, Z: z}
+	}
+
+	// Residue 1: N-CA bonded pair (1.46 Å, excluded as covalent)
+	n1 := atom("N", "N", 1, 0, 0, 0)
+	ca1 := atom("CA", "C", 1, 1.46, 0, 0)
+
+	// Clash 1: C(10) vs O(20) at 2.5 Å → overlap 1.70 + 1.52 - 2.5 = 0.72 Å
+	c10 := atom("C", "C", 10, 20, 0, 0)
+	o20 := atom("O", "O", 20, 22.5, 0, 0)
+
+	// Clash 2: N(30) vs C(40) at 2.8 Å → overlap 1.55 + 1.70 - 2.8 = 0.45 Å
+	n30 := atom("N", "N", 30, 40, 0, 0)
+	c40 := atom("C", "C", 40, 42.8, 0, 0)
+
+	// Near miss: C(50) vs C(60) at 3.1 Å → overlap 0.30 Å (below 0.4)
+	c50 := atom("CA", "C", 50, 60, 0, 0)
+	c60 := atom("CA", "C", 60, 63.1, 0, 0)
+
+	// Hydrogen bond: N-H(70) ··· O(80) at 1.9 Å → favorable, not a clash
+	n70 := atom("N", "N", 70, 80, 0, 0)
+	h70 := atom("H", "H", 70, 81.01, 0, 0)
+	o80 := atom("O", "O", 80, 82.91, 0, 0)
+
+	protein := &parser.Protein{
+		Atoms: []*parser.Atom{n1, ca1, c10, o20, n30, c40, c50, c60, n70, h70, o80},
+	}
+
+	score, clashes := CalculateClashscore(protein)
+
+	if len(clashes) != 2 {
+		for _, c := range clashes {
+			t.Logf("  clash %s%d-%s%d: d=%.2f overlap=%.2f", c.Atom1.Name, c.Atom1.ResSeq, c.Atom2.Name, c.Atom2.ResSeq, c.Distance, c.Overlap)
+		}
+		t.Fatalf("Expected exactly 2 clashes, got %d", len(clashes))
+	}
+
+	expectedOverlaps := []float64{0.72, 0.45}
+	for i, c := range clashes {
+		if math.Abs(c.Overlap-expectedOverlaps[i]) > 0.001 {
+			t.Errorf("Clash %d overlap: got %.3f, want %.3f", i, c.Overlap, expectedOverlaps[i])
+		}
+	}
+
+	expectedScore := 1000.0 * 2 / float64(len(protein.Atoms))
+	if math.Abs(score-expectedScore) > 1e-9 {
+		t.Errorf("Clashscore: got %.2f, want %.2f", score, expectedScore)
+	}
+
+	t.Logf("Clashscore: %.1f (%d clashes / %d atoms)", score, len(clashes), len(protein.Atoms))
+}
+
+// TestCalculateClashscore_Empty degrades gracefully on empty input
+func TestCalculateClashscore_Empty(t *testing.T) {
+	score, clashes := CalculateClashscore(&parser.Protein{})
+	if score != 0 || len(clashes) != 0 {
+		t.Errorf("Empty protein: score=%.2f clashes=%d", score, len(clashes))
+	}
+}
@@ -0,0 +1,246 @@
+// Package physics - MolProbity-style clashscore
+//
+// WAVE 11 follow-up: DetectClashes counts severe overlaps with a loose
+// 0.6 × (r1 + r2) threshold, which is useful as a sanity check but is not
+// comparable to published validation tools. Clashscore is the MolProbity
+// standard: serious overlaps (≥ 0.4 Å) per 1000 atoms.
+//
+// BIOCHEMIST: Covalently linked atoms (up to 1-4) and hydrogen-bonded
+// polar H···acceptor pairs are not clashes
+// PHYSICIST: Overlap = (r1 + r2) - d with Bondi van der Waals radii
+// MATHEMATICIAN: Bond graph from residue topology + covalent radii
+// ETHICIST: Works on backbone-only models; more atoms just means more pairs
+//
+// CITATION:
+// Word, J. M., et al. (1999). "Visualizing and quantifying molecular goodness-of-fit:
+// Small-probe contact dots with explicit hydrogen atoms." J. Mol. Biol. 285(4): 1711-1733.
+//
+// Chen, V. B., et al. (2010). "MolProbity: all-atom structure validation for
+// macromolecular crystallography." Acta Cryst. D66: 12-21.
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// ClashOverlapThreshold is the MolProbity serious-clash overlap (Å)
+const ClashOverlapThreshold = 0.4
+
+// Clash is a pair of non-bonded atoms overlapping by at least 0.4 Å
+type Clash struct {
+	Atom1    *parser.Atom
+	Atom2    *parser.Atom
+	Distance float64 // Interatomic distance (Å)
+	Overlap  float64 // (r1 + r2) - distance (Å)
+}
+
+// clashVdWRadii are Bondi (1964) van der Waals radii (Å)
+var clashVdWRadii = map[string]float64{
+	"H": 1.20,
+	"C": 1.70,
+	"N": 1.55,
+	"O": 1.52,
+	"S": 1.80,
+}
+
+// covalentRadii (Å) for bond perception within residues
+//
+// Cordero, B., et al. (2008). "Covalent radii revisited." Dalton Trans. 2832-2838.
+var covalentRadii = map[string]float64{
+	"H": 0.31,
+	"C": 0.76,
+	"N": 0.71,
+	"O": 0.66,
+	"S": 1.05,
+}
+
+// CalculateClashscore returns serious clashes per 1000 atoms and the clashing pairs
+//
+// ALGORITHM:
+//  1. Perceive bonds: intra-residue pairs within covalent distance,
+//     peptide C(i)-N(i+1), and S-S disulfides
+//  2. Exclude pairs separated by ≤ 3 bonds (1-2, 1-3, 1-4)
+//  3. Exclude polar H···N/O pairs (hydrogen bonds, not clashes)
+//  4. Every remaining pair with overlap ≥ 0.4 Å is a clash
+//
+// Score = 1000 × clashes / atoms. MolProbity reference: < 10 is good for
+// crystal structures; raw predictions are often 20-100.
+func CalculateClashscore(protein *parser.Protein) (float64, []Clash) {
+	if protein == nil || len(protein.Atoms) == 0 {
+		return 0.0, nil
+	}
+
+	atoms := protein.Atoms
+	index := make(map[*parser.Atom]int, len(atoms))
+	for i, atom := range atoms {
+		index[atom] = i
+	}
+
+	bonds := perceiveBonds(protein, index)
+	polarH := make([]bool, len(atoms))
+	for i, atom := range atoms {
+		if clashElement(atom) != "H" {
+			continue
+		}
+		for _, j := range bonds[i] {
+			if e := clashElement(atoms[j]); e == "N" || e == "O" {
+				polarH[i] = true
+			}
+		}
+	}
+
+	clashes := make([]Clash, 0)
+	for i := 0; i < len(atoms); i++ {
+		excluded := bondedWithin(bonds, i, 3)
+
+		for j := i + 1; j < len(atoms); j++ {
+			if excluded[j] {
+				continue
+			}
+
+			a1, a2 := atoms[i], atoms[j]
+			e1, e2 := clashElement(a1), clashElement(a2)
+
+			// Hydrogen bonds are favorable contacts
+			if (polarH[i] && (e2 == "N" || e2 == "O")) || (polarH[j] && (e1 == "N" || e1 == "O")) {
+				continue
+			}
+
+			r1, r2 := clashRadius(e1), clashRadius(e2)
+			dx := a1.X - a2.X
+			dy := a1.Y - a2.Y
+			dz := a1.Z - a2.Z
+			distSq := dx*dx + dy*dy + dz*dz
+
+			// Quick reject before sqrt
+			limit := r1 + r2 - ClashOverlapThreshold
+			if distSq > limit*limit {
+				continue
+			}
+
+			dist := math.Sqrt(distSq)
+			overlap := r1 + r2 - dist
+			if overlap >= ClashOverlapThreshold {
+				clashes = append(clashes, Clash{Atom1: a1, Atom2: a2, Distance: dist, Overlap: overlap})
+			}
+		}
+	}
+
+	score := 1000.0 * float64(len(clashes)) / float64(len(atoms))
+	return score, clashes
+}
+
+// perceiveBonds builds an adjacency list over protein.Atoms
+func perceiveBonds(protein *parser.Protein, index map[*parser.Atom]int) [][]int {
+	atoms := protein.Atoms
+	bonds := make([][]int, len(atoms))
+	addBond := func(i, j int) {
+		bonds[i] = append(bonds[i], j)
+		bonds[j] = append(bonds[j], i)
+	}
+
+	// Group atoms by residue
+	byResidue := make(map[string][]int)
+	for i, atom := range atoms {
+		key := fmt.Sprintf("%s:%d:%s", atom.ChainID, atom.ResSeq, atom.ICode)
+		byResidue[key] = append(byResidue[key], i)
+	}
+
+	// Intra-residue bonds from covalent radii
+	for _, members := range byResidue {
+		for a := 0; a < len(members); a++ {
+			for b := a + 1; b < len(members); b++ {
+				if isCovalentlyBonded(atoms[members[a]], atoms[members[b]]) {
+					addBond(members[a], members[b])
+				}
+			}
+		}
+	}
+
+	// Peptide bonds C(i)-N(i+1) along the residue list
+	for i := 1; i < len(protein.Residues); i++ {
+		prev, curr := protein.Residues[i-1], protein.Residues[i]
+		if prev == nil || curr == nil || prev.C == nil || curr.N == nil || prev.ChainID != curr.ChainID {
+			continue
+		}
+		ci, okC := index[prev.C]
+		ni, okN := index[curr.N]
+		if okC && okN && isCovalentlyBonded(prev.C, curr.N) {
+			addBond(ci, ni)
+		}
+	}
+
+	// Disulfides (inter-residue S-S)
+	for i := 0; i < len(atoms); i++ {
+		if clashElement(atoms[i]) != "S" {
+			continue
+		}
+		for j := i + 1; j < len(atoms); j++ {
+			if clashElement(atoms[j]) == "S" && atoms[j].ResSeq != atoms[i].ResSeq && isCovalentlyBonded(atoms[i], atoms[j]) {
+				addBond(i, j)
+			}
+		}
+	}
+
+	return bonds
+}
+
+// bondedWithin returns atoms reachable from start in at most depth bonds
+func bondedWithin(bonds [][]int, start, depth int) map[int]bool {
+	seen := map[int]bool{start: true}
+	frontier := []int{start}
+
+	for d := 0; d < depth && len(frontier) > 0; d++ {
+		next := make([]int, 0)
+		for _, i := range frontier {
+			for _, j := range bonds[i] {
+				if !seen[j] {
+					seen[j] = true
+					next = append(next, j)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return seen
+}
+
+// isCovalentlyBonded uses d ≤ rc1 + rc2 + 0.45 Å
+func isCovalentlyBonded(a1, a2 *parser.Atom) bool {
+	rc1, ok1 := covalentRadii[clashElement(a1)]
+	rc2, ok2 := covalentRadii[clashElement(a2)]
+	if !ok1 {
+		rc1 = 0.76
+	}
+	if !ok2 {
+		rc2 = 0.76
+	}
+
+	dx := a1.X - a2.X
+	dy := a1.Y - a2.Y
+	dz := a1.Z - a2.Z
+	return math.Sqrt(dx*dx+dy*dy+dz*dz) <= rc1+rc2+0.45
+}
+
+// clashElement returns the element, inferring it from the atom name if unset
+func clashElement(atom *parser.Atom) string {
+	if atom.Element != "" {
+		return atom.Element
+	}
+	if len(atom.Name) > 0 {
+		return atom.Name[:1]
+	}
+	return "C"
+}
+
+// clashRadius returns the van der Waals radius, defaulting to carbon
+func clashRadius(element string) float64 {
+	if r, ok := clashVdWRadii[element]; ok {
+		return r
+	}
+	return 1.70
+}
@@ -0,0 +1,277 @@
+// Package physics - CMAP backbone φ/ψ correction
+//
+// One-dimensional Fourier torsions cannot express the coupling between φ
+// and ψ, so the alanine dipeptide minima sit off the experimental basins.
+// CMAP adds a tabulated 2D correction E(φ, ψ) per residue, interpolated
+// from a 24×24 grid.
+//
+// The grids are an approximation, not the published CHARMM or ff14SB/ff19SB
+// correction maps: each is a synthetic wrapped-Gaussian-mixture PMF over the
+// populated Ramachandran regions, capped at 4 kcal/mol. Do not cite them as
+// a force field's CMAP.
+//
+// BIOCHEMIST: Four residue classes, as in validation.RamachandranScore:
+// general, glycine, proline and pre-proline
+// PHYSICIST: Grids are Ramachandran potentials of mean force -RT ln(P/P_max),
+// capped at 4 kcal/mol (see cmap_gen.go)
+// MATHEMATICIAN: Periodic bicubic (Catmull-Rom) interpolation is C¹, so
+// analytic ∂E/∂φ and ∂E/∂ψ are continuous and chain through the dihedral
+// gradient for forces
+// ETHICIST: The grids are derived from smoothed structure statistics, not
+// fitted to quantum data like CHARMM's; the CMAP term is off by default
+//
+// CITATION:
+// MacKerell, A. D., Feig, M., Brooks, C. L. (2004). "Extending the treatment of backbone
+// energetics in protein force fields." J. Comput. Chem. 25(11): 1400-1415.
+package physics
+
+//go:generate go run cmap_gen.go
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// CMAP grid layout (must match cmap_gen.go)
+const (
+	cmapGridSize = 24
+	cmapGridStep = 2.0 * math.Pi / cmapGridSize // radians (15°)
+)
+
+// cmapClass selects the correction grid for a residue
+type cmapClass int
+
+const (
+	cmapGeneral cmapClass = iota
+	cmapGlycine
+	cmapProline
+	cmapPreProline
+)
+
+// cmapGrid holds energies at φ = -π + i·step (rows), ψ = -π + j·step (columns)
+type cmapGrid [cmapGridSize][cmapGridSize]float64
+
+//go:embed cmap_table.txt
+var cmapTableData string
+
+// cmapGrids are parsed once from the embedded table
+var cmapGrids = mustParseCMAPTable(cmapTableData)
+
+// CMAPEnergy computes the CMAP correction summed over residues with a
+// defined (φ, ψ)
+//
+// φ_i = C(i-1)-N-CA-C, ψ_i = N-CA-C-N(i+1); terminal residues have no
+// correction. Returns kcal/mol. The grids are the approximate PMFs of
+// cmap_gen.go, not published CMAP data.
+func CMAPEnergy(protein *parser.Protein) float64 {
+	if protein == nil {
+		return 0.0
+	}
+
+	total := 0.0
+	for _, c := range enumerateCMAPTerms(protein) {
+		e, _, _ := cmapGrids[c.class].interpolate(torsionAngle(c.phi), torsionAngle(c.psi))
+		total += e
+	}
+	return total
+}
+
+// addCMAPForces adds -∇E_CMAP to the force map
+func addCMAPForces(protein *parser.Protein, forces map[int]Vector3) {
+	for _, c := range enumerateCMAPTerms(protein) {
+		_, dPhi, dPsi := cmapGrids[c.class].interpolate(torsionAngle(c.phi), torsionAngle(c.psi))
+
+		for _, d := range []struct {
+			atoms [4]*parser.Atom
+			dE    float64
+		}{{c.phi, dPhi}, {c.psi, dPsi}} {
+			if d.dE == 0 {
+				continue
+			}
+			grad := dihedralGradient(d.atoms)
+			for k, atom := range d.atoms {
+				forces[atom.Serial] = forces[atom.Serial].Add(grad[k].Mul(-d.dE))
+			}
+		}
+	}
+}
+
+// cmapTerm is one residue's (φ, ψ) atom quartets and grid class
+type cmapTerm struct {
+	phi, psi [4]*parser.Atom
+	class    cmapClass
+}
+
+// enumerateCMAPTerms lists residues with complete φ and ψ quartets
+func enumerateCMAPTerms(protein *parser.Protein) []cmapTerm {
+	residues := protein.Residues
+	terms := make([]cmapTerm, 0, len(residues))
+
+	for i := 1; i < len(residues)-1; i++ {
+		prev, res, next := residues[i-1], residues[i], residues[i+1]
+		if prev.C == nil || res.N == nil || res.CA == nil || res.C == nil || next.N == nil {
+			continue
+		}
+
+		terms = append(terms, cmapTerm{
+			phi:   [4]*parser.Atom{prev.C, res.N, res.CA, res.C},
+			psi:   [4]*parser.Atom{res.N, res.CA, res.C, next.N},
+			class: residueCMAPClass(residues, i),
+		})
+	}
+
+	return terms
+}
+
+// residueCMAPClass classifies residue i (Gly/Pro checked before pre-Pro)
+func residueCMAPClass(residues []*parser.Residue, i int) cmapClass {
+	switch residues[i].Name {
+	case "GLY", "G":
+		return cmapGlycine
+	case "PRO", "P":
+		return cmapProline
+	}
+	if i+1 < len(residues) {
+		if next := residues[i+1].Name; next == "PRO" || next == "P" {
+			return cmapPreProline
+		}
+	}
+	return cmapGeneral
+}
+
+// interpolate returns E(φ, ψ) and its partial derivatives (per radian)
+//
+// MATHEMATICIAN: Separable Catmull-Rom spline over the 4×4 neighbourhood,
+// with indices wrapped for periodicity
+func (g *cmapGrid) interpolate(phi, psi float64) (float64, float64, float64) {
+	i0, tPhi := cmapGridCoord(phi)
+	j0, tPsi := cmapGridCoord(psi)
+
+	wPhi, dwPhi := catmullRomWeights(tPhi)
+	wPsi, dwPsi := catmullRomWeights(tPsi)
+
+	energy, dPhi, dPsi := 0.0, 0.0, 0.0
+	for a := 0; a < 4; a++ {
+		row := (i0 + a - 1 + cmapGridSize) % cmapGridSize
+		for b := 0; b < 4; b++ {
+			v := g[row][(j0+b-1+cmapGridSize)%cmapGridSize]
+			energy += wPhi[a] * wPsi[b] * v
+			dPhi += dwPhi[a] * wPsi[b] * v
+			dPsi += wPhi[a] * dwPsi[b] * v
+		}
+	}
+
+	return energy, dPhi / cmapGridStep, dPsi / cmapGridStep
+}
+
+// cmapGridCoord maps an angle (radians) to its lower grid index and fraction
+func cmapGridCoord(angle float64) (int, float64) {
+	x := math.Mod(angle+math.Pi, 2.0*math.Pi)
+	if x < 0 {
+		x += 2.0 * math.Pi
+	}
+	x /= cmapGridStep
+
+	idx := int(math.Floor(x))
+	t := x - float64(idx)
+	return idx % cmapGridSize, t
+}
+
+// catmullRomWeights returns the four spline weights at t ∈ [0, 1) and
+// their derivatives with respect to t
+func catmullRomWeights(t float64) ([4]float64, [4]float64) {
+	t2 := t * t
+	t3 := t2 * t
+
+	w := [4]float64{
+		0.5 * (-t3 + 2*t2 - t),
+		0.5 * (3*t3 - 5*t2 + 2),
+		0.5 * (-3*t3 + 4*t2 + t),
+		0.5 * (t3 - t2),
+	}
+	dw := [4]float64{
+		0.5 * (-3*t2 + 4*t - 1),
+		0.5 * (9*t2 - 10*t),
+		0.5 * (-9*t2 + 8*t + 1),
+		0.5 * (3*t2 - 2*t),
+	}
+	return w, dw
+}
+
+// mustParseCMAPTable parses the embedded grids; malformed data is a build error
+func mustParseCMAPTable(data string) map[cmapClass]*cmapGrid {
+	grids, err := parseCMAPTable(data)
+	if err != nil {
+		panic(fmt.Sprintf("physics: invalid embedded CMAP table: %v", err))
+	}
+	return grids
+}
+
+// parseCMAPTable reads "class <name>" blocks of 24 rows × 24 values
+func parseCMAPTable(data string) (map[cmapClass]*cmapGrid, error) {
+	classNames := map[string]cmapClass{
+		"general":    cmapGeneral,
+		"glycine":    cmapGlycine,
+		"proline":    cmapProline,
+		"preproline": cmapPreProline,
+	}
+
+	grids := make(map[cmapClass]*cmapGrid, len(classNames))
+	var current *cmapGrid
+	row := 0
+
+	for lineNum, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(line, "class "); ok {
+			if current != nil && row != cmapGridSize {
+				return nil, fmt.Errorf("line %d: previous class has %d rows, want %d", lineNum+1, row, cmapGridSize)
+			}
+			class, known := classNames[strings.TrimSpace(name)]
+			if !known {
+				return nil, fmt.Errorf("line %d: unknown class %q", lineNum+1, name)
+			}
+			current = &cmapGrid{}
+			grids[class] = current
+			row = 0
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: grid data before class header", lineNum+1)
+		}
+		if row >= cmapGridSize {
+			return nil, fmt.Errorf("line %d: more than %d rows", lineNum+1, cmapGridSize)
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != cmapGridSize {
+			return nil, fmt.Errorf("line %d: %d values, want %d", lineNum+1, len(fields), cmapGridSize)
+		}
+		for j, field := range fields {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			current[row][j] = v
+		}
+		row++
+	}
+
+	if current != nil && row != cmapGridSize {
+		return nil, fmt.Errorf("last class has %d rows, want %d", row, cmapGridSize)
+	}
+	if len(grids) != len(classNames) {
+		return nil, fmt.Errorf("found %d classes, want %d", len(grids), len(classNames))
+	}
+
+	return grids, nil
+}
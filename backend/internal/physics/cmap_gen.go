@@ -0,0 +1,119 @@
+//go:build ignore
+
+// cmap_gen writes cmap_table.txt, the embedded CMAP correction grids
+//
+// The grids are an approximation made here, not the published CHARMM,
+// ff14SB or ff19SB CMAP data: a synthetic PMF from hand-placed Gaussian peaks.
+//
+// Each grid is the Ramachandran potential of mean force
+//
+//	E(φ, ψ) = -RT ln(P(φ, ψ) / P_max), capped at cmapCap
+//
+// on a 24×24 grid (15° spacing, φ rows and ψ columns from -180°), where P
+// is a wrapped-Gaussian mixture over the populated regions of the Top8000
+// statistics (same peaks as validation.RamachandranScore).
+//
+// Usage: go generate ./internal/physics
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	gridSize = 24
+	gridStep = 15.0
+	rt       = 0.593 // kcal/mol at 298 K
+	cmapCap  = 4.0   // kcal/mol
+)
+
+type peak struct {
+	phi, psi, sigPhi, sigPsi, weight float64
+}
+
+var classes = []struct {
+	name  string
+	peaks []peak
+}{
+	{"general", []peak{
+		{-63, -43, 12, 12, 0.50},  // α-helix
+		{-120, 130, 25, 22, 0.25}, // β-sheet
+		{-67, 145, 13, 15, 0.14},  // PPII
+		{-90, 0, 18, 18, 0.07},    // Bridge
+		{57, 42, 10, 12, 0.04},    // Left-handed helix
+	}},
+	{"glycine", []peak{
+		{-65, -40, 15, 15, 0.20},
+		{65, 40, 15, 15, 0.20},
+		{-80, 170, 20, 20, 0.15},
+		{80, -170, 20, 20, 0.15},
+		{180, 180, 25, 25, 0.20},
+		{90, 0, 18, 20, 0.10},
+	}},
+	{"proline", []peak{
+		{-65, -35, 10, 15, 0.40},
+		{-65, 145, 10, 15, 0.55},
+		{-85, 70, 10, 12, 0.05},
+	}},
+	{"preproline", []peak{
+		{-120, 140, 25, 18, 0.45},
+		{-70, 145, 15, 15, 0.35},
+		{-65, -40, 12, 12, 0.15},
+		{-140, 80, 15, 15, 0.05},
+	}},
+}
+
+func wrap(d float64) float64 {
+	d = math.Mod(d+180.0, 360.0)
+	if d < 0 {
+		d += 360.0
+	}
+	return d - 180.0
+}
+
+func density(phi, psi float64, peaks []peak) float64 {
+	p := 0.0
+	for _, pk := range peaks {
+		dPhi, dPsi := wrap(phi-pk.phi), wrap(psi-pk.psi)
+		p += pk.weight * math.Exp(-0.5*(dPhi*dPhi/(pk.sigPhi*pk.sigPhi)+dPsi*dPsi/(pk.sigPsi*pk.sigPsi))) /
+			(pk.sigPhi * pk.sigPsi)
+	}
+	return p
+}
+
+func main() {
+	var out strings.Builder
+	out.WriteString("# CMAP correction grids (kcal/mol), generated by cmap_gen.go - DO NOT EDIT\n")
+	out.WriteString("# APPROXIMATION: synthetic Gaussian-mixture Ramachandran PMF capped at 4 kcal/mol,\n")
+	out.WriteString("# NOT published CHARMM/ff14SB/ff19SB CMAP grid data - do not cite as such\n")
+	out.WriteString("# 24x24 per class; rows phi = -180 + 15*i, columns psi = -180 + 15*j (degrees)\n")
+
+	for _, class := range classes {
+		grid := [gridSize][gridSize]float64{}
+		pMax := 0.0
+		for i := 0; i < gridSize; i++ {
+			for j := 0; j < gridSize; j++ {
+				grid[i][j] = density(-180+gridStep*float64(i), -180+gridStep*float64(j), class.peaks)
+				pMax = math.Max(pMax, grid[i][j])
+			}
+		}
+
+		fmt.Fprintf(&out, "class %s\n", class.name)
+		for i := 0; i < gridSize; i++ {
+			row := make([]string, gridSize)
+			for j := 0; j < gridSize; j++ {
+				e := math.Min(-rt*math.Log(math.Max(grid[i][j]/pMax, 1e-300)), cmapCap)
+				row[j] = fmt.Sprintf("%.3f", e)
+			}
+			out.WriteString(strings.Join(row, " ") + "\n")
+		}
+	}
+
+	if err := os.WriteFile("cmap_table.txt", []byte(out.String()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write cmap_table.txt: %v\n", err)
+		os.Exit(1)
+	}
+}
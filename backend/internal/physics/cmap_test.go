@@ -0,0 +1,114 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCMAPShiftsAlanineDipeptideMinimum checks CMAP moves the α minimum
+// toward the experimental helix basin (-63°, -43°)
+func TestCMAPShiftsAlanineDipeptideMinimum(t *testing.T) {
+	const step = 5
+	target := [2]float64{-63, -43}
+
+	// Search the α basin on both surfaces
+	minimum := func(useCMAP bool) ([2]float64, float64) {
+		best, bestE := [2]float64{}, math.Inf(1)
+		for phi := -140; phi <= -30; phi += step {
+			for psi := -90; psi <= 10; psi += step {
+				protein := buildAlanineDipeptide(float64(phi), float64(psi))
+				e := TorsionEnergy(protein)
+				if useCMAP {
+					e += CMAPEnergy(protein)
+				}
+				if e < bestE {
+					best, bestE = [2]float64{float64(phi), float64(psi)}, e
+				}
+			}
+		}
+		return best, bestE
+	}
+
+	distance := func(p [2]float64) float64 {
+		return math.Hypot(p[0]-target[0], p[1]-target[1])
+	}
+
+	without, eWithout := minimum(false)
+	with, eWith := minimum(true)
+
+	t.Logf("Torsions only: α minimum at (%.0f°, %.0f°), %.2f kcal/mol, %.1f° from helix", without[0], without[1], eWithout, distance(without))
+	t.Logf("With CMAP:     α minimum at (%.0f°, %.0f°), %.2f kcal/mol, %.1f° from helix", with[0], with[1], eWith, distance(with))
+
+	if distance(with) >= distance(without) {
+		t.Errorf("CMAP did not move the α minimum toward (-63°, -43°)")
+	}
+	if distance(with) > 20 {
+		t.Errorf("CMAP α minimum %.1f° from helix basin, want ≤ 20°", distance(with))
+	}
+}
+
+// TestCMAPGridInterpolation checks the spline reproduces grid nodes and
+// that its derivatives match finite differences
+func TestCMAPGridInterpolation(t *testing.T) {
+	deg := math.Pi / 180.0
+	grid := cmapGrids[cmapGeneral]
+
+	for _, node := range [][2]int{{0, 0}, {8, 9}, {23, 23}, {15, 20}} {
+		phi := -math.Pi + float64(node[0])*cmapGridStep
+		psi := -math.Pi + float64(node[1])*cmapGridStep
+		e, _, _ := grid.interpolate(phi, psi)
+		if math.Abs(e-grid[node[0]][node[1]]) > 1e-9 {
+			t.Errorf("Node %v: interpolated %.6f, grid %.6f", node, e, grid[node[0]][node[1]])
+		}
+	}
+
+	// Periodicity: +180° and -180° are the same point
+	ePlus, _, _ := grid.interpolate(math.Pi-1e-12, 10*deg)
+	eMinus, _, _ := grid.interpolate(-math.Pi, 10*deg)
+	if math.Abs(ePlus-eMinus) > 1e-6 {
+		t.Errorf("Grid not periodic in φ: %.6f vs %.6f", ePlus, eMinus)
+	}
+
+	const h = 1e-6
+	for _, p := range [][2]float64{{-63, -43}, {-120, 130}, {57, 42}, {172, -177}} {
+		phi, psi := p[0]*deg, p[1]*deg
+		_, dPhi, dPsi := grid.interpolate(phi, psi)
+
+		e1, _, _ := grid.interpolate(phi+h, psi)
+		e2, _, _ := grid.interpolate(phi-h, psi)
+		e3, _, _ := grid.interpolate(phi, psi+h)
+		e4, _, _ := grid.interpolate(phi, psi-h)
+
+		if math.Abs(dPhi-(e1-e2)/(2*h)) > 1e-4 || math.Abs(dPsi-(e3-e4)/(2*h)) > 1e-4 {
+			t.Errorf("(%.0f°, %.0f°): analytic (%.4f, %.4f), numerical (%.4f, %.4f)",
+				p[0], p[1], dPhi, dPsi, (e1-e2)/(2*h), (e3-e4)/(2*h))
+		}
+	}
+}
+
+// TestCMAPForces checks CMAP forces are -∇E through VerifyForcesWithConfig
+func TestCMAPForces(t *testing.T) {
+	protein := buildAlanineDipeptide(-75, -30)
+
+	// Break symmetry so every gradient component is exercised
+	for i, atom := range protein.Atoms {
+		atom.X += 0.05 * math.Sin(float64(i))
+		atom.Y += 0.05 * math.Cos(float64(i))
+	}
+
+	config := DefaultEnergyConfig()
+	config.UseCMAP = true
+
+	if e := CalculateTotalEnergyWithConfig(protein, config); e.CMAP == 0 {
+		t.Fatalf("CMAP energy is zero with UseCMAP enabled")
+	}
+	if e := CalculateTotalEnergy(protein, config.VdWCutoff, config.ElecCutoff); e.CMAP != 0 {
+		t.Errorf("CMAP energy %.4f without UseCMAP", e.CMAP)
+	}
+
+	maxError := VerifyForcesWithConfig(protein, config)
+	t.Logf("Max force error with CMAP: %.2e kcal/(mol·Å)", maxError)
+	if maxError > 1e-3 {
+		t.Errorf("CMAP forces disagree with finite differences: %.2e", maxError)
+	}
+}
@@ -0,0 +1,97 @@
+// Package physics - Contact distance restraints
+//
+// Predicted residue contacts are only useful during folding if they pull
+// on the structure. ContactRestraint turns one predicted contact into a
+// harmonic CA-CA distance restraint that is part of the energy function
+// (EnergyConfig.ContactRestraints), so minimizers and samplers see its
+// gradient instead of a score added after the fact.
+//
+// BIOCHEMIST: Contacts are predicted at CB-CB < 8 Å; a 7 Å CA-CA target
+// is the usual backbone-only stand-in
+// PHYSICIST: E = Σ k·w·(d - d0)², F = -2k·w·(d - d0)·r̂ on the second CA
+// and the opposite on the first
+// MATHEMATICIAN: Weighting by confidence w ∈ [0, 1] makes uncertain
+// contacts proportionally softer springs
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// ContactRestraint pulls the CA atoms of two residues toward a target distance
+type ContactRestraint struct {
+	Residue1 int     // Index into protein.Residues (0-indexed)
+	Residue2 int     // Index into protein.Residues (0-indexed)
+	Target   float64 // Target CA-CA distance (Å)
+	Weight   float64 // Confidence weight, typically the prediction score in [0, 1]
+}
+
+// DefaultContactForceConstant is the restraint spring constant used when
+// EnergyConfig.ContactForceConstant is zero, in kcal/(mol·Å²)
+const DefaultContactForceConstant = 10.0
+
+// ContactRestraintEnergy returns the restraint energy Σ k·w·(d - d0)² in
+// kcal/mol and the resulting forces, keyed by atom Serial like
+// CalculateForces
+//
+// Restraints naming a residue outside the protein or without a CA are
+// skipped.
+func ContactRestraintEnergy(protein *parser.Protein, restraints []ContactRestraint, forceConstant float64) (float64, map[int]Vector3) {
+	forces := make(map[int]Vector3)
+	energy := contactRestraintTotal(protein, restraints, forceConstant, forces)
+	return energy, forces
+}
+
+// contactRestraintTotal sums the restraint energy and, if forces is not
+// nil, adds the restraint forces to it
+func contactRestraintTotal(protein *parser.Protein, restraints []ContactRestraint, forceConstant float64, forces map[int]Vector3) float64 {
+	if protein == nil {
+		return 0
+	}
+
+	total := 0.0
+	for _, restraint := range restraints {
+		ca1 := restraintCA(protein, restraint.Residue1)
+		ca2 := restraintCA(protein, restraint.Residue2)
+		if ca1 == nil || ca2 == nil || ca1 == ca2 {
+			continue
+		}
+
+		dx := ca2.X - ca1.X
+		dy := ca2.Y - ca1.Y
+		dz := ca2.Z - ca1.Z
+		d := math.Sqrt(dx*dx + dy*dy + dz*dz)
+
+		k := forceConstant * restraint.Weight
+		deviation := d - restraint.Target
+		total += k * deviation * deviation
+
+		if forces == nil || d == 0 {
+			continue
+		}
+		// Force on ca2 = -dE/dd · r̂, r̂ pointing from ca1 to ca2
+		f := Vector3{X: dx / d, Y: dy / d, Z: dz / d}.Mul(-2 * k * deviation)
+		forces[ca2.Serial] = forces[ca2.Serial].Add(f)
+		forces[ca1.Serial] = forces[ca1.Serial].Sub(f)
+	}
+
+	return total
+}
+
+// contactForceConstant returns config's restraint spring constant
+func (config EnergyConfig) contactForceConstant() float64 {
+	if config.ContactForceConstant == 0 {
+		return DefaultContactForceConstant
+	}
+	return config.ContactForceConstant
+}
+
+// restraintCA returns the CA of residue index i, or nil
+func restraintCA(protein *parser.Protein, i int) *parser.Atom {
+	if i < 0 || i >= len(protein.Residues) || protein.Residues[i] == nil {
+		return nil
+	}
+	return protein.Residues[i].CA
+}
@@ -0,0 +1,44 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// TestContactRestraintEnergy checks the confidence-weighted harmonic energy
+// against a hand calculation and the forces against finite differences of
+// the total with the restraint enabled
+func TestContactRestraintEnergy(t *testing.T) {
+	strand := buildUniformChain(t, 8, -120, 120)
+	ca1, ca8 := strand.Residues[0].CA, strand.Residues[7].CA
+	d := math.Sqrt((ca8.X-ca1.X)*(ca8.X-ca1.X) + (ca8.Y-ca1.Y)*(ca8.Y-ca1.Y) + (ca8.Z-ca1.Z)*(ca8.Z-ca1.Z))
+
+	restraints := []ContactRestraint{
+		{Residue1: 0, Residue2: 7, Target: 7.0, Weight: 0.5},
+		{Residue1: 2, Residue2: 99, Target: 7.0, Weight: 1.0}, // No such residue: skipped
+	}
+	energy, forces := ContactRestraintEnergy(strand, restraints, 10.0)
+	want := 10.0 * 0.5 * (d - 7.0) * (d - 7.0)
+	t.Logf("CA1-CA8 %.2f Å: restraint energy %.3f kcal/mol", d, energy)
+	if math.Abs(energy-want) > 1e-9 {
+		t.Errorf("Restraint energy %.6f, want %.6f", energy, want)
+	}
+	if sum := forces[ca1.Serial].Add(forces[ca8.Serial]); sum.Magnitude() > 1e-9 || len(forces) != 2 {
+		t.Errorf("Forces %v should be an equal and opposite pair on the two CAs", forces)
+	}
+
+	config := DefaultEnergyConfig()
+	without := CalculateTotalEnergyWithConfig(strand, config)
+	config.ContactRestraints = restraints
+	config.ContactForceConstant = 10.0
+	with := CalculateTotalEnergyWithConfig(strand, config)
+	if math.Abs(with.Contact-want) > 1e-9 || math.Abs((with.Total-without.Total)-with.Contact) > 1e-9 {
+		t.Errorf("Restraints added %.3f to the total (Contact %.3f), want %.3f", with.Total-without.Total, with.Contact, want)
+	}
+
+	maxError := VerifyForcesWithConfig(strand, config)
+	t.Logf("Max force error with restraints: %.2e kcal/(mol·Å)", maxError)
+	if maxError > 1e-3 {
+		t.Errorf("Restraint forces disagree with finite differences: %.2e", maxError)
+	}
+}
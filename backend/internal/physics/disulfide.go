@@ -0,0 +1,222 @@
+// Package physics - Disulfide bond detection and restraint energy
+//
+// Crambin (1CRN) carries three disulfides and trypsin (2PTN) six; without
+// them the folding pipeline has no reason to bring the cysteines together.
+//
+// BIOCHEMIST: Cys SG-SG bond is 2.04 ± 0.05 Å; bonded Cβ-Cβ distances fall
+// in 3.4-4.3 Å, which lets us detect candidate pairs before side chains exist
+// PHYSICIST: Harmonic restraint E = k(r - r₀)², same form as CalculateBondEnergy
+// MATHEMATICIAN: Each cysteine bonds at most once; pairs are assigned greedily
+// from the shortest distance up
+// ETHICIST: Cβ-based detection is a proximity heuristic, not proof of a bond;
+// Disulfide.FromSidechain records which evidence was used
+//
+// CITATION:
+// Petersen, M. T. N., et al. (1999). "Amino acid neighbours and detailed conformational
+// analysis of cysteines in proteins." Protein Eng. 12(7): 535-548.
+package physics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Disulfide geometry and restraint parameters
+const (
+	DisulfideSGCutoff = 2.5  // Å, SG-SG detection cutoff
+	DisulfideCBCutoff = 4.5  // Å, Cβ-Cβ detection cutoff (no side chains)
+	DisulfideSGLength = 2.05 // Å, target S-S bond length
+	DisulfideCBLength = 3.8  // Å, typical Cβ-Cβ across a disulfide
+
+	disulfideSGForceConstant = 166.0 // kcal/(mol·Å²), CHARMM S-S bond
+	disulfideCBForceConstant = 10.0  // kcal/(mol·Å²), loose: Cβ-Cβ spread is wide
+)
+
+// Disulfide is a bonded (or restrained) cysteine pair
+type Disulfide struct {
+	Residue1      int     // Index into protein.Residues (Residue1 < Residue2)
+	Residue2      int     // Index into protein.Residues
+	SeqNum1       int     // PDB residue number of first cysteine
+	SeqNum2       int     // PDB residue number of second cysteine
+	Distance      float64 // SG-SG or Cβ-Cβ distance at detection (Å)
+	FromSidechain bool    // true: SG-SG evidence; false: Cβ-Cβ (real or virtual)
+}
+
+// String formats the pair like an SSBOND record summary
+func (d Disulfide) String() string {
+	atom := "CB"
+	if d.FromSidechain {
+		atom = "SG"
+	}
+	return fmt.Sprintf("CYS %d - CYS %d (%s-%s %.2f Å)", d.SeqNum1, d.SeqNum2, atom, atom, d.Distance)
+}
+
+// DetectDisulfides finds cysteine pairs forming disulfide bonds
+//
+// Pairs where both cysteines have an SG atom are judged on SG-SG distance
+// (< DisulfideSGCutoff). Otherwise Cβ-Cβ distance is used (< DisulfideCBCutoff),
+// with a virtual Cβ built from N, CA, C for backbone-only models.
+func DetectDisulfides(protein *parser.Protein) []Disulfide {
+	if protein == nil {
+		return nil
+	}
+
+	cysteines := make([]int, 0)
+	for i, res := range protein.Residues {
+		if isCysteine(res.Name) {
+			cysteines = append(cysteines, i)
+		}
+	}
+
+	sidechains := residueSidechainAtoms(protein)
+
+	candidates := make([]Disulfide, 0)
+	for a := 0; a < len(cysteines); a++ {
+		for b := a + 1; b < len(cysteines); b++ {
+			i, j := cysteines[a], cysteines[b]
+			fromSG := sidechains[i]["SG"] != nil && sidechains[j]["SG"] != nil
+
+			p1, ok1 := disulfidePosition(protein, sidechains, i, fromSG)
+			p2, ok2 := disulfidePosition(protein, sidechains, j, fromSG)
+			if !ok1 || !ok2 {
+				continue
+			}
+
+			dist := p1.Sub(p2).Magnitude()
+			cutoff := DisulfideCBCutoff
+			if fromSG {
+				cutoff = DisulfideSGCutoff
+			}
+			if dist < cutoff {
+				candidates = append(candidates, Disulfide{
+					Residue1:      i,
+					Residue2:      j,
+					SeqNum1:       protein.Residues[i].SeqNum,
+					SeqNum2:       protein.Residues[j].SeqNum,
+					Distance:      dist,
+					FromSidechain: fromSG,
+				})
+			}
+		}
+	}
+
+	// Greedy assignment: shortest first, each cysteine bonds once
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].Distance < candidates[b].Distance
+	})
+
+	bonded := make(map[int]bool)
+	disulfides := make([]Disulfide, 0)
+	for _, c := range candidates {
+		if bonded[c.Residue1] || bonded[c.Residue2] {
+			continue
+		}
+		bonded[c.Residue1] = true
+		bonded[c.Residue2] = true
+		disulfides = append(disulfides, c)
+	}
+
+	// Report in sequence order
+	sort.Slice(disulfides, func(a, b int) bool {
+		return disulfides[a].Residue1 < disulfides[b].Residue1
+	})
+
+	return disulfides
+}
+
+// DisulfideEnergy computes the harmonic restraint energy for the given pairs
+//
+// Positions are re-read from the current coordinates, so pairs detected on
+// one structure can restrain later structures of the same sequence.
+func DisulfideEnergy(protein *parser.Protein, disulfides []Disulfide) float64 {
+	if protein == nil || len(disulfides) == 0 {
+		return 0.0
+	}
+
+	sidechains := residueSidechainAtoms(protein)
+
+	totalEnergy := 0.0
+	for _, d := range disulfides {
+		if d.Residue1 >= len(protein.Residues) || d.Residue2 >= len(protein.Residues) {
+			continue
+		}
+
+		p1, ok1 := disulfidePosition(protein, sidechains, d.Residue1, d.FromSidechain)
+		p2, ok2 := disulfidePosition(protein, sidechains, d.Residue2, d.FromSidechain)
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		r0, k := DisulfideCBLength, disulfideCBForceConstant
+		if d.FromSidechain {
+			r0, k = DisulfideSGLength, disulfideSGForceConstant
+		}
+
+		dr := p1.Sub(p2).Magnitude() - r0
+		totalEnergy += k * dr * dr
+	}
+
+	return totalEnergy
+}
+
+// CalculateTotalEnergyWithDisulfides is CalculateTotalEnergy plus the
+// disulfide restraint term
+func CalculateTotalEnergyWithDisulfides(protein *parser.Protein, vdwCutoff, elecCutoff float64, disulfides []Disulfide) EnergyComponents {
+	energy := CalculateTotalEnergy(protein, vdwCutoff, elecCutoff)
+
+	energy.Disulfide = DisulfideEnergy(protein, disulfides)
+	energy.Total = math.Min(energy.Total+energy.Disulfide, 10000.0) // Same cap as CalculateTotalEnergy
+
+	return energy
+}
+
+// isCysteine accepts three-letter (PDB) and one-letter (built) residue names
+func isCysteine(name string) bool {
+	return name == "CYS" || name == "C"
+}
+
+// disulfidePosition returns the SG position, or Cβ (real, else virtual)
+func disulfidePosition(protein *parser.Protein, sidechains map[int]map[string]*parser.Atom, i int, useSG bool) (Vector3, bool) {
+	if useSG {
+		if sg := sidechains[i]["SG"]; sg != nil {
+			return Vector3{X: sg.X, Y: sg.Y, Z: sg.Z}, true
+		}
+		return Vector3{}, false
+	}
+
+	if cb := sidechains[i]["CB"]; cb != nil {
+		return Vector3{X: cb.X, Y: cb.Y, Z: cb.Z}, true
+	}
+	return VirtualCB(protein.Residues[i])
+}
+
+// Ideal Cβ construction coefficients (see VirtualCB)
+const (
+	virtualCBCoeffA = -0.58273431
+	virtualCBCoeffB = 0.56802827
+	virtualCBCoeffC = -0.54067466
+)
+
+// VirtualCB places an ideal Cβ from backbone N, CA, C (false if one is missing)
+//
+// MATHEMATICIAN: b = CA - N, c = C - CA, a = b × c;
+// Cβ = -0.58273431·a + 0.56802827·b - 0.54067466·c + CA
+// (ideal L-amino acid geometry, as used by trRosetta and AlphaFold)
+func VirtualCB(res *parser.Residue) (Vector3, bool) {
+	if res.N == nil || res.CA == nil || res.C == nil {
+		return Vector3{}, false
+	}
+
+	n := Vector3{X: res.N.X, Y: res.N.Y, Z: res.N.Z}
+	ca := Vector3{X: res.CA.X, Y: res.CA.Y, Z: res.CA.Z}
+	c := Vector3{X: res.C.X, Y: res.C.Y, Z: res.C.Z}
+
+	b := ca.Sub(n)
+	cc := c.Sub(ca)
+	a := crossVec(b, cc)
+
+	return a.Mul(virtualCBCoeffA).Add(b.Mul(virtualCBCoeffB)).Add(cc.Mul(virtualCBCoeffC)).Add(ca), true
+}
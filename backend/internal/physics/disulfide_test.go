@@ -0,0 +1,136 @@
+package physics
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestDetectDisulfides1CRN checks crambin's three native disulfides are found
+func TestDetectDisulfides1CRN(t *testing.T) {
+	const path = "../../../testdata/1CRN.pdb"
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s not available (fetch with cmd/download_pdb)", path)
+	}
+
+	protein, err := parser.ParsePDB(path)
+	if err != nil {
+		t.Fatalf("Failed to parse 1CRN: %v", err)
+	}
+
+	disulfides := DetectDisulfides(protein)
+	for _, d := range disulfides {
+		t.Logf("  %s", d)
+	}
+
+	expected := [][2]int{{3, 40}, {4, 32}, {16, 26}}
+	if len(disulfides) != len(expected) {
+		t.Fatalf("Expected %d disulfides, got %d", len(expected), len(disulfides))
+	}
+	for k, want := range expected {
+		d := disulfides[k]
+		if d.SeqNum1 != want[0] || d.SeqNum2 != want[1] || !d.FromSidechain {
+			t.Errorf("Disulfide %d: got %s, want CYS %d - CYS %d (SG)", k, d, want[0], want[1])
+		}
+	}
+
+	// Native geometry is at the restraint minimum
+	if e := DisulfideEnergy(protein, disulfides); e > 1.0 {
+		t.Errorf("Native disulfide energy %.3f kcal/mol should be near zero", e)
+	}
+}
+
+// buildCysteineTestProtein places residues with CB/SG atoms at given positions
+func buildCysteineTestProtein(names []string, cb, sg []Vector3) *parser.Protein {
+	protein := &parser.Protein{Name: "cys_test"}
+	serial := 1
+	for i, name := range names {
+		res := &parser.Residue{Name: name, SeqNum: i + 1, ChainID: "A"}
+		res.CA = &parser.Atom{Serial: serial, Name: "CA", ResName: name, ChainID: "A", ResSeq: i + 1,
+			X: cb[i].X - 1.0, Y: cb[i].Y, Z: cb[i].Z}
+		serial++
+		protein.Atoms = append(protein.Atoms, res.CA)
+		protein.Atoms = append(protein.Atoms, &parser.Atom{Serial: serial, Name: "CB", ResName: name, ChainID: "A",
+			ResSeq: i + 1, X: cb[i].X, Y: cb[i].Y, Z: cb[i].Z})
+		serial++
+		if sg != nil {
+			protein.Atoms = append(protein.Atoms, &parser.Atom{Serial: serial, Name: "SG", ResName: name, ChainID: "A",
+				ResSeq: i + 1, X: sg[i].X, Y: sg[i].Y, Z: sg[i].Z})
+			serial++
+		}
+		protein.Residues = append(protein.Residues, res)
+	}
+	return protein
+}
+
+// TestDetectDisulfidesSidechain checks SG-SG detection, pairing and energy
+func TestDetectDisulfidesSidechain(t *testing.T) {
+	names := []string{"CYS", "ALA", "CYS", "CYS"}
+	cb := []Vector3{{X: 0}, {X: 10}, {X: 3.8}, {X: 30}}
+	sg := []Vector3{{X: 0.9}, {X: 10}, {X: 2.9}, {X: 30}} // Cys1-Cys3 SG-SG = 2.0 Å
+
+	protein := buildCysteineTestProtein(names, cb, sg)
+	disulfides := DetectDisulfides(protein)
+
+	if len(disulfides) != 1 {
+		t.Fatalf("Expected 1 disulfide, got %d", len(disulfides))
+	}
+	d := disulfides[0]
+	t.Logf("Detected: %s", d)
+	if d.Residue1 != 0 || d.Residue2 != 2 || !d.FromSidechain {
+		t.Errorf("Wrong pair: %+v", d)
+	}
+
+	relaxed := DisulfideEnergy(protein, disulfides)
+	for _, atom := range protein.Atoms {
+		if atom.Name == "SG" && atom.ResSeq == 3 {
+			atom.X += 1.0 // Stretch Cys3 SG away (2.0 → 3.0 Å)
+		}
+	}
+	stretched := DisulfideEnergy(protein, disulfides)
+	t.Logf("Energy: relaxed %.3f, stretched %.3f kcal/mol", relaxed, stretched)
+
+	if relaxed > 1.0 || stretched < 100.0 {
+		t.Errorf("Harmonic restraint wrong: relaxed %.3f, stretched %.3f", relaxed, stretched)
+	}
+
+	withSS := CalculateTotalEnergyWithDisulfides(protein, 10.0, 12.0, disulfides)
+	if math.Abs(withSS.Disulfide-stretched) > 1e-9 {
+		t.Errorf("EnergyComponents.Disulfide %.3f, want %.3f", withSS.Disulfide, stretched)
+	}
+}
+
+// TestDetectDisulfidesCB checks Cβ fallback, greedy pairing and the virtual Cβ
+func TestDetectDisulfidesCB(t *testing.T) {
+	// Cys1 sits 3.6 Å from Cys2 and 4.0 Å from Cys3: only the shorter pair bonds
+	names := []string{"CYS", "CYS", "CYS"}
+	cb := []Vector3{{X: 0}, {X: 3.6}, {X: -4.0}}
+
+	disulfides := DetectDisulfides(buildCysteineTestProtein(names, cb, nil))
+	if len(disulfides) != 1 || disulfides[0].Residue2 != 1 || disulfides[0].FromSidechain {
+		t.Fatalf("Expected single Cβ-based pair 0-1, got %v", disulfides)
+	}
+
+	// Virtual Cβ sits at the ideal 1.53 Å from CA
+	angles := make([]geometry.RamachandranAngles, 3)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60.0 * math.Pi / 180.0, Psi: -45.0 * math.Pi / 180.0}
+	}
+	built, err := geometry.BuildProteinFromAngles("ACA", angles)
+	if err != nil {
+		t.Fatalf("Failed to build peptide: %v", err)
+	}
+	res := built.Residues[1]
+	vcb, ok := VirtualCB(res)
+	if !ok {
+		t.Fatalf("Virtual Cβ not built")
+	}
+	d := vcb.Sub(Vector3{X: res.CA.X, Y: res.CA.Y, Z: res.CA.Z}).Magnitude()
+	t.Logf("Virtual CA-CB distance: %.3f Å", d)
+	if math.Abs(d-1.53) > 0.05 {
+		t.Errorf("Virtual CA-CB %.3f Å, want ≈1.53", d)
+	}
+}
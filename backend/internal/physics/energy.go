@@ -11,6 +11,10 @@ import (
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 )
 
+// TotalEnergyCap bounds |EnergyComponents.Total| (kcal/mol); the
+// components themselves are not capped
+const TotalEnergyCap = 10000.0
+
 // EnergyComponents holds breakdown of total energy
 type EnergyComponents struct {
 	Bond          float64 // Bond stretching energy
@@ -18,9 +22,75 @@ type EnergyComponents struct {
 	Dihedral      float64 // Ramachandran dihedral energy (backbone constraints)
 	VanDerWaals   float64 // Lennard-Jones energy
 	Electrostatic float64 // Coulomb energy
+	Disulfide     float64 // Disulfide restraint (CalculateTotalEnergyWithDisulfides only)
+	CMAP          float64 // φ/ψ grid correction (EnergyConfig.UseCMAP only)
+	HBond         float64 // Smooth backbone H-bonds (EnergyConfig.UseHBonds only)
+	Contact       float64 // Contact distance restraints (EnergyConfig.ContactRestraints only)
+	Restraint     float64 // User distance/dihedral restraints (EnergyConfig.DistanceRestraints, DihedralRestraints only)
+	Improper      float64 // Planarity and chirality impropers (EnergyConfig.UseImpropers only)
 	Total         float64 // Sum of all components
 }
 
+// Uncapped returns the sum of the components that Total caps at
+// ±TotalEnergyCap
+//
+// A severely clashing structure has Total pinned at the cap, where the
+// energy surface is flat; the uncapped sum still slopes out of the clash.
+func (c EnergyComponents) Uncapped() float64 {
+	return sumComponents(c)
+}
+
+// EnergyConfig selects cutoffs and optional terms for the energy function
+type EnergyConfig struct {
+	VdWCutoff  float64 // Van der Waals cutoff (Å)
+	ElecCutoff float64 // Electrostatic cutoff (Å)
+
+	// Switching windows: pair energies fall smoothly to zero over
+	// [SwitchStart, Cutoff] (see cutoffSwitch); 0 keeps the hard cutoff
+	VdWSwitchStart  float64 // Å
+	ElecSwitchStart float64 // Å
+
+	UseCMAP   bool // Add the CMAP φ/ψ correction (see CMAPEnergy)
+	UseHBonds bool // Add the directional backbone H-bond term (see HBondEnergy)
+
+	// Add harmonic impropers for carbonyl and peptide planarity and Cα
+	// chirality (see ImproperEnergy)
+	UseImpropers bool
+
+	// Predicted contacts to restrain (nil: none), see ContactRestraintEnergy
+	ContactRestraints    []ContactRestraint
+	ContactForceConstant float64 // kcal/(mol·Å²); 0 uses DefaultContactForceConstant
+
+	// User restraints from experimental data (nil: none), see RestraintEnergy
+	DistanceRestraints []DistanceRestraint
+	DihedralRestraints []DihedralRestraint
+
+	// Unit of reported energies and forces (zero value: kcal/mol); the
+	// terms are computed and capped in kcal/mol, then converted
+	Units EnergyUnits
+
+	// Energy function (zero value: all-atom). EnergyModelCA replaces the
+	// force-field terms with the coarse-grained CA model (see caTotalEnergy);
+	// the cutoffs and optional all-atom terms are then ignored. Forces
+	// (CalculateForcesWithConfig) are all-atom only: the dihedral-space
+	// optimizers differentiate the CA energy numerically
+	Model EnergyModel
+}
+
+// DefaultEnergyConfig returns the cutoffs used throughout the pipeline with
+// 2 Å switching windows, CMAP, H-bonds and impropers off
+func DefaultEnergyConfig() EnergyConfig {
+	return EnergyConfig{
+		VdWCutoff:       10.0,
+		ElecCutoff:      12.0,
+		VdWSwitchStart:  8.0,
+		ElecSwitchStart: 10.0,
+		UseCMAP:         false,
+		UseHBonds:       false,
+		UseImpropers:    false,
+	}
+}
+
 // CalculateTotalEnergy computes all energy terms for a protein
 //
 // PHYSICIST:
@@ -33,6 +103,25 @@ type EnergyComponents struct {
 //
 // Returns: Energy components in kcal/mol
 func CalculateTotalEnergy(protein *parser.Protein, vdwCutoff, elecCutoff float64) EnergyComponents {
+	return CalculateTotalEnergyWithConfig(protein, EnergyConfig{VdWCutoff: vdwCutoff, ElecCutoff: elecCutoff})
+}
+
+// CalculateTotalEnergyWithConfig computes all energy terms, including the
+// optional terms enabled in config
+func CalculateTotalEnergyWithConfig(protein *parser.Protein, config EnergyConfig) EnergyComponents {
+	if config.Model == EnergyModelCA {
+		return caTotalEnergy(protein, config)
+	}
+
+	// Van der Waals and electrostatic: Sum over all non-bonded pairs
+	vdw := calculateVanDerWaalsTotal(protein, config.VdWSwitchStart, config.VdWCutoff)
+	elec := calculateElectrostaticTotal(protein, config.ElecSwitchStart, config.ElecCutoff)
+	return totalEnergyWithNonBonded(protein, config, vdw, elec)
+}
+
+// totalEnergyWithNonBonded completes CalculateTotalEnergyWithConfig given
+// the van der Waals and electrostatic sums
+func totalEnergyWithNonBonded(protein *parser.Protein, config EnergyConfig, vdw, elec float64) EnergyComponents {
 	energy := EnergyComponents{}
 
 	// Bond energy: Sum over all covalent bonds
@@ -41,30 +130,52 @@ func CalculateTotalEnergy(protein *parser.Protein, vdwCutoff, elecCutoff float64
 	// Angle energy: Sum over all bond angles
 	energy.Angle = calculateAngleEnergyTotal(protein)
 
-	// Dihedral energy: Ramachandran potential (backbone φ,ψ constraints)
-	energy.Dihedral = RamachandranPotential(protein)
+	// Dihedral energy: AMBER Fourier torsions (φ, ψ, ω, χ)
+	energy.Dihedral = TorsionEnergy(protein)
+
+	energy.VanDerWaals = vdw
+	energy.Electrostatic = elec
+
+	// CMAP: tabulated φ/ψ correction
+	if config.UseCMAP {
+		energy.CMAP = CMAPEnergy(protein)
+	}
+
+	// H-bonds: smooth directional N-H···O=C term
+	if config.UseHBonds {
+		energy.HBond = HBondEnergy(protein)
+	}
+
+	// Impropers: planarity and chirality
+	if config.UseImpropers {
+		energy.Improper = ImproperEnergy(protein)
+	}
 
-	// Van der Waals: Sum over all non-bonded pairs
-	energy.VanDerWaals = calculateVanDerWaalsTotal(protein, vdwCutoff)
+	// Contacts: harmonic CA-CA restraints
+	if len(config.ContactRestraints) > 0 {
+		energy.Contact = contactRestraintTotal(protein, config.ContactRestraints, config.contactForceConstant(), nil)
+	}
 
-	// Electrostatic: Sum over all non-bonded pairs
-	energy.Electrostatic = calculateElectrostaticTotal(protein, elecCutoff)
+	// Restraints: flat-bottom distances and dihedrals
+	if config.hasRestraints() {
+		energy.Restraint = restraintTotal(protein, config, nil)
+	}
 
 	// Total
-	energy.Total = energy.Bond + energy.Angle + energy.Dihedral + energy.VanDerWaals + energy.Electrostatic
+	energy.Total = energy.Bond + energy.Angle + energy.Dihedral + energy.VanDerWaals + energy.Electrostatic + energy.CMAP + energy.HBond + energy.Contact + energy.Restraint + energy.Improper
 
 	// Cap energy to prevent overflow
 	// Realistic protein energies: -500 to +2000 kcal/mol
 	// >10,000 indicates severe steric clashes or coordinate corruption
 	// <-10,000 indicates unphysical attraction
-	if energy.Total > 10000.0 {
-		energy.Total = 10000.0
+	if energy.Total > TotalEnergyCap {
+		energy.Total = TotalEnergyCap
 	}
-	if energy.Total < -10000.0 {
-		energy.Total = -10000.0
+	if energy.Total < -TotalEnergyCap {
+		energy.Total = -TotalEnergyCap
 	}
 
-	return energy
+	return energy.InUnits(config.Units)
 }
 
 // calculateBondEnergyTotal sums bond energies for all bonds in protein
@@ -158,13 +269,14 @@ func calculateAngleEnergyTotal(protein *parser.Protein) float64 {
 //
 // PHYSICIST:
 // Only calculate for atoms separated by >3 bonds (1-4 and beyond)
-// Use cutoff distance to reduce O(n²) cost
-func calculateVanDerWaalsTotal(protein *parser.Protein, cutoff float64) float64 {
+// Use cutoff distance to reduce O(n²) cost, switched off from switchStart
+func calculateVanDerWaalsTotal(protein *parser.Protein, switchStart, cutoff float64) float64 {
 	totalEnergy := 0.0
 
 	// Simple O(n²) loop for now
 	// TODO: Use spatial hashing for O(n) performance (Wave 3 - Williams Optimizer)
 	atoms := protein.Atoms
+	lj := ljParameters(protein)
 
 	for i := 0; i < len(atoms); i++ {
 		for j := i + 1; j < len(atoms); j++ {
@@ -173,7 +285,7 @@ func calculateVanDerWaalsTotal(protein *parser.Protein, cutoff float64) float64
 				continue
 			}
 
-			energy := CalculateLennardJonesEnergy(atoms[i], atoms[j], cutoff)
+			energy := lennardJonesPairEnergy(atoms[i], atoms[j], lj[i], lj[j], switchStart, cutoff)
 			totalEnergy += energy
 		}
 	}
@@ -181,17 +293,11 @@ func calculateVanDerWaalsTotal(protein *parser.Protein, cutoff float64) float64
 	return totalEnergy
 }
 
-// calculateElectrostaticTotal sums Coulomb energies for all non-bonded pairs
-func calculateElectrostaticTotal(protein *parser.Protein, cutoff float64) float64 {
+// calculateElectrostaticTotal sums Coulomb energies for all non-bonded
+// pairs, switched off over [switchStart, cutoff]
+func calculateElectrostaticTotal(protein *parser.Protein, switchStart, cutoff float64) float64 {
 	totalEnergy := 0.0
-
-	// Simplified partial charges (backbone only, from AMBER ff14SB)
-	charges := map[string]float64{
-		"N":  -0.4157, // Backbone nitrogen
-		"CA": 0.0337,  // Alpha carbon
-		"C":  0.5973,  // Carbonyl carbon
-		"O":  -0.5679, // Carbonyl oxygen
-	}
+	charges := partialCharges(protein)
 
 	atoms := protein.Atoms
 
@@ -203,14 +309,13 @@ func calculateElectrostaticTotal(protein *parser.Protein, cutoff float64) float6
 			}
 
 			// Get charges
-			charge1, ok1 := charges[atoms[i].Name]
-			charge2, ok2 := charges[atoms[j].Name]
+			charge1, charge2 := charges[i], charges[j]
 
-			if !ok1 || !ok2 {
-				continue // Skip atoms with unknown charges
+			if charge1 == 0 || charge2 == 0 {
+				continue // Skip uncharged atoms
 			}
 
-			energy := CalculateElectrostaticEnergy(atoms[i], atoms[j], charge1, charge2, cutoff)
+			energy := electrostaticPairEnergy(atoms[i], atoms[j], charge1, charge2, switchStart, cutoff)
 			totalEnergy += energy
 		}
 	}
@@ -223,21 +328,44 @@ func calculateElectrostaticTotal(protein *parser.Protein, cutoff float64) float6
 // MATHEMATICIAN:
 // F = -∇E (force is negative gradient of energy)
 // Returns force vector for each atom
+//
+// Forces are the exact negative gradient of the uncapped CalculateTotalEnergy
+// sum (bond + angle + dihedral + VdW + electrostatic); VerifyForces checks this.
 func CalculateForces(protein *parser.Protein, vdwCutoff, elecCutoff float64) map[int]Vector3 {
-	forces := make(map[int]Vector3)
+	return CalculateForcesWithConfig(protein, EnergyConfig{VdWCutoff: vdwCutoff, ElecCutoff: elecCutoff})
+}
 
-	// Initialize all forces to zero
-	for _, atom := range protein.Atoms {
-		forces[atom.Serial] = Vector3{X: 0, Y: 0, Z: 0}
-	}
+// CalculateForcesWithConfig is CalculateForces for CalculateTotalEnergyWithConfig,
+// in config.Units per Å
+func CalculateForcesWithConfig(protein *parser.Protein, config EnergyConfig) map[int]Vector3 {
+	forces := zeroForces(protein)
 
-	// Bond forces
-	addBondForces(protein, forces)
+	// Bonded terms (see CalculateBondedForces)
+	addBondedForces(protein, forces)
+	if config.UseCMAP {
+		addCMAPForces(protein, forces)
+	}
+	if config.UseHBonds {
+		addHBondForces(protein, forces)
+	}
+	if config.UseImpropers {
+		addImproperForces(protein, forces)
+	}
+	if len(config.ContactRestraints) > 0 {
+		contactRestraintTotal(protein, config.ContactRestraints, config.contactForceConstant(), forces)
+	}
+	if config.hasRestraints() {
+		restraintTotal(protein, config, forces)
+	}
 
-	// TODO: Angle forces, VdW forces, electrostatic forces
-	// For Wave 1, we're focusing on basic bond forces
-	// Full implementation in Wave 2
+	// Non-bonded terms
+	addNonBondedForces(protein, forces, config)
 
+	if f := config.Units.FromKcal(); f != 1.0 {
+		for serial, force := range forces {
+			forces[serial] = force.Mul(f)
+		}
+	}
 	return forces
 }
 
@@ -292,3 +420,71 @@ func addBondForces(protein *parser.Protein, forces map[int]Vector3) {
 		}
 	}
 }
+
+// addAngleForces adds angle bending forces to force map
+func addAngleForces(protein *parser.Protein, forces map[int]Vector3) {
+	addAngle := func(a1, a2, a3 *parser.Atom, params AngleParameters) {
+		f1, f2, f3 := CalculateAngleForces(a1, a2, a3, params)
+		forces[a1.Serial] = forces[a1.Serial].Add(f1)
+		forces[a2.Serial] = forces[a2.Serial].Add(f2)
+		forces[a3.Serial] = forces[a3.Serial].Add(f3)
+	}
+
+	// Same angles as calculateAngleEnergyTotal
+	for _, res := range protein.Residues {
+		if !res.HasCompleteBackbone() {
+			continue
+		}
+
+		// N-CA-C angle
+		addAngle(res.N, res.CA, res.C, GetAngleParams("N", "CA", "C"))
+
+		// CA-C-O angle
+		if res.O != nil {
+			addAngle(res.CA, res.C, res.O, GetAngleParams("CA", "C", "O"))
+		}
+	}
+
+	for i := 0; i < len(protein.Residues)-1; i++ {
+		res1 := protein.Residues[i]
+		res2 := protein.Residues[i+1]
+
+		// CA-C-N angle (across peptide bond)
+		if res1.CA != nil && res1.C != nil && res2.N != nil {
+			addAngle(res1.CA, res1.C, res2.N, GetAngleParams("CA", "C", "N"))
+		}
+
+		// C-N-CA angle (across peptide bond)
+		if res1.C != nil && res2.N != nil && res2.CA != nil {
+			addAngle(res1.C, res2.N, res2.CA, GetAngleParams("C", "N", "CA"))
+		}
+	}
+}
+
+// addNonBondedForces adds Lennard-Jones and Coulomb forces to force map
+//
+// Uses the same exclusions as calculateVanDerWaalsTotal and
+// calculateElectrostaticTotal (same or adjacent residues are skipped) and
+// the same switching windows.
+func addNonBondedForces(protein *parser.Protein, forces map[int]Vector3, config EnergyConfig) {
+	atoms := protein.Atoms
+	charges := partialCharges(protein)
+	lj := ljParameters(protein)
+
+	for i := 0; i < len(atoms); i++ {
+		for j := i + 1; j < len(atoms); j++ {
+			if math.Abs(float64(atoms[i].ResSeq-atoms[j].ResSeq)) <= 1 {
+				continue
+			}
+
+			force := lennardJonesPairForce(atoms[i], atoms[j], lj[i], lj[j], config.VdWSwitchStart, config.VdWCutoff)
+
+			if charges[i] != 0 && charges[j] != 0 {
+				force = force.Add(electrostaticPairForce(atoms[i], atoms[j], charges[i], charges[j], config.ElecSwitchStart, config.ElecCutoff))
+			}
+
+			forces[atoms[i].Serial] = forces[atoms[i].Serial].Add(force.Mul(-1))
+			forces[atoms[j].Serial] = forces[atoms[j].Serial].Add(force)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Package physics - Per-residue energy decomposition
+//
+// CalculateTotalEnergyWithConfig says how bad a structure is, not where.
+// DecomposeEnergyPerResidue splits every term of the same energy function
+// over the residues that take part in it, so clashes and strained geometry
+// show up as individual high-energy residues.
+//
+// PHYSICIST: A term involving atoms of k residues gives each of them 1/k
+// (a non-bonded pair or a peptide bond is split evenly between its two
+// residues); torsions, CMAP and impropers belong to the residue that owns
+// them in enumerateTorsions, enumerateCMAPTerms and enumerateImpropers
+// MATHEMATICIAN: Σ_residues E_r = uncapped E_total, term by term
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// ResidueEnergy is one residue's share of each energy term (in
+// EnergyConfig.Units, kcal/mol by default)
+type ResidueEnergy struct {
+	Index   int    // Index into protein.Residues
+	Name    string // Residue name
+	SeqNum  int    // Residue sequence number
+	ChainID string // Chain identifier
+
+	Bond          float64
+	Angle         float64
+	Dihedral      float64
+	VanDerWaals   float64
+	Electrostatic float64
+	CMAP          float64 // EnergyConfig.UseCMAP only
+	Improper      float64 // EnergyConfig.UseImpropers only
+	HBond         float64 // EnergyConfig.UseHBonds only
+	Contact       float64 // EnergyConfig.ContactRestraints only
+	Restraint     float64 // EnergyConfig.DistanceRestraints and DihedralRestraints only
+	Total         float64 // Sum of the above
+}
+
+// Bonded returns the residue's bond, angle, dihedral, CMAP and improper energy
+func (r ResidueEnergy) Bonded() float64 {
+	return r.Bond + r.Angle + r.Dihedral + r.CMAP + r.Improper
+}
+
+// NonBonded returns the residue's VdW, electrostatic and H-bond energy
+func (r ResidueEnergy) NonBonded() float64 {
+	return r.VanDerWaals + r.Electrostatic + r.HBond
+}
+
+// DecomposeEnergyPerResidue splits CalculateTotalEnergyWithConfig(protein,
+// config) over protein.Residues
+//
+// Entry i is residue i. The entries' Total values sum to the uncapped total
+// of the components (the ±10000 kcal/mol cap applies only to the global
+// Total). Non-bonded pairs with an atom outside every residue are assigned
+// wholly to the other atom's residue and dropped if neither has one.
+func DecomposeEnergyPerResidue(protein *parser.Protein, config EnergyConfig) []ResidueEnergy {
+	if protein == nil {
+		return nil
+	}
+
+	energies := make([]ResidueEnergy, len(protein.Residues))
+	for i, res := range protein.Residues {
+		energies[i].Index = i
+		if res != nil {
+			energies[i].Name = res.Name
+			energies[i].SeqNum = res.SeqNum
+			energies[i].ChainID = res.ChainID
+		}
+	}
+	residueOf := atomResidueIndex(protein)
+
+	// Bonds, mirroring calculateBondEnergyTotal
+	for i, res := range protein.Residues {
+		if !res.HasCompleteBackbone() {
+			continue
+		}
+		energies[i].Bond += CalculateBondEnergy(res.N, res.CA, GetBondParams("N", "CA"))
+		energies[i].Bond += CalculateBondEnergy(res.CA, res.C, GetBondParams("CA", "C"))
+		if res.O != nil {
+			energies[i].Bond += CalculateBondEnergy(res.C, res.O, GetBondParams("C", "O"))
+		}
+	}
+	for i := 0; i < len(protein.Residues)-1; i++ {
+		res1, res2 := protein.Residues[i], protein.Residues[i+1]
+		if res1.C != nil && res2.N != nil {
+			half := CalculateBondEnergy(res1.C, res2.N, GetBondParams("C", "N")) / 2
+			energies[i].Bond += half
+			energies[i+1].Bond += half
+		}
+	}
+
+	// Angles, mirroring calculateAngleEnergyTotal
+	for i, res := range protein.Residues {
+		if !res.HasCompleteBackbone() {
+			continue
+		}
+		energies[i].Angle += CalculateAngleEnergy(res.N, res.CA, res.C, GetAngleParams("N", "CA", "C"))
+		if res.O != nil {
+			energies[i].Angle += CalculateAngleEnergy(res.CA, res.C, res.O, GetAngleParams("CA", "C", "O"))
+		}
+	}
+	for i := 0; i < len(protein.Residues)-1; i++ {
+		res1, res2 := protein.Residues[i], protein.Residues[i+1]
+		if res1.CA != nil && res1.C != nil && res2.N != nil {
+			half := CalculateAngleEnergy(res1.CA, res1.C, res2.N, GetAngleParams("CA", "C", "N")) / 2
+			energies[i].Angle += half
+			energies[i+1].Angle += half
+		}
+		if res1.C != nil && res2.N != nil && res2.CA != nil {
+			half := CalculateAngleEnergy(res1.C, res2.N, res2.CA, GetAngleParams("C", "N", "CA")) / 2
+			energies[i].Angle += half
+			energies[i+1].Angle += half
+		}
+	}
+
+	// Torsions, to the residue they were enumerated for
+	owner := make(map[*parser.Residue]int, len(protein.Residues))
+	backboneOwner := make(map[*parser.Atom]int, 2*len(protein.Residues))
+	for i, res := range protein.Residues {
+		owner[res] = i
+		if res != nil {
+			backboneOwner[res.CA] = i
+			backboneOwner[res.O] = i
+		}
+	}
+	for _, t := range enumerateTorsions(protein) {
+		theta := torsionAngle(t.atoms)
+		e := 0.0
+		for _, term := range t.terms {
+			e += term.HalfBarrier * (1.0 + math.Cos(float64(term.Periodicity)*theta-term.Phase))
+		}
+		energies[owner[t.residue]].Dihedral += e
+	}
+
+	if config.UseCMAP {
+		for _, c := range enumerateCMAPTerms(protein) {
+			e, _, _ := cmapGrids[c.class].interpolate(torsionAngle(c.phi), torsionAngle(c.psi))
+			energies[backboneOwner[c.phi[2]]].CMAP += e // φ's CA
+		}
+	}
+
+	if config.UseImpropers {
+		for _, t := range enumerateImpropers(protein) {
+			d := t.deviation()
+			energies[t.residue].Improper += t.k * d * d
+		}
+	}
+
+	if config.UseHBonds {
+		forEachHBondPair(protein, func(donor hbondDonor, acceptor *parser.Atom) {
+			e := hbondPairEnergy(donor, acceptor)
+			if e == 0 {
+				return
+			}
+			energies[owner[donor.residue]].HBond += e / 2
+			energies[backboneOwner[acceptor]].HBond += e / 2
+		})
+	}
+
+	if len(config.ContactRestraints) > 0 {
+		k := config.contactForceConstant()
+		for _, restraint := range config.ContactRestraints {
+			e := contactRestraintTotal(protein, []ContactRestraint{restraint}, k, nil)
+			if e == 0 {
+				continue
+			}
+			energies[restraint.Residue1].Contact += e / 2
+			energies[restraint.Residue2].Contact += e / 2
+		}
+	}
+
+	// Distance restraints split like contacts; a dihedral belongs to its residue
+	for _, restraint := range config.DistanceRestraints {
+		e := distanceRestraintTotal(protein, []DistanceRestraint{restraint}, nil)
+		if e == 0 {
+			continue
+		}
+		energies[restraint.Residue1].Restraint += e / 2
+		energies[restraint.Residue2].Restraint += e / 2
+	}
+	for _, restraint := range config.DihedralRestraints {
+		if e := dihedralRestraintTotal(protein, []DihedralRestraint{restraint}, nil); e != 0 {
+			energies[restraint.Residue].Restraint += e
+		}
+	}
+
+	// Non-bonded pairs, with the exclusions of calculateVanDerWaalsTotal
+	atoms := protein.Atoms
+	charges := partialCharges(protein)
+	lj := ljParameters(protein)
+	owners := make([]int, len(atoms))
+	for i, atom := range atoms {
+		owners[i] = -1
+		if r, ok := residueOf(atom); ok {
+			owners[i] = r
+		}
+	}
+	split := func(i, j int, e float64, field func(*ResidueEnergy) *float64) {
+		ri, rj := owners[i], owners[j]
+		switch {
+		case ri >= 0 && rj >= 0:
+			*field(&energies[ri]) += e / 2
+			*field(&energies[rj]) += e / 2
+		case ri >= 0:
+			*field(&energies[ri]) += e
+		case rj >= 0:
+			*field(&energies[rj]) += e
+		}
+	}
+	vdw := func(r *ResidueEnergy) *float64 { return &r.VanDerWaals }
+	elec := func(r *ResidueEnergy) *float64 { return &r.Electrostatic }
+	for i := 0; i < len(atoms); i++ {
+		for j := i + 1; j < len(atoms); j++ {
+			if math.Abs(float64(atoms[i].ResSeq-atoms[j].ResSeq)) <= 1 {
+				continue
+			}
+			split(i, j, lennardJonesPairEnergy(atoms[i], atoms[j], lj[i], lj[j], config.VdWSwitchStart, config.VdWCutoff), vdw)
+			if charges[i] != 0 && charges[j] != 0 {
+				split(i, j, electrostaticPairEnergy(atoms[i], atoms[j], charges[i], charges[j], config.ElecSwitchStart, config.ElecCutoff), elec)
+			}
+		}
+	}
+
+	f := config.Units.FromKcal()
+	for i := range energies {
+		r := &energies[i]
+		if f != 1.0 {
+			r.Bond, r.Angle, r.Dihedral = r.Bond*f, r.Angle*f, r.Dihedral*f
+			r.VanDerWaals, r.Electrostatic = r.VanDerWaals*f, r.Electrostatic*f
+			r.CMAP, r.Improper, r.HBond, r.Contact = r.CMAP*f, r.Improper*f, r.HBond*f, r.Contact*f
+			r.Restraint *= f
+		}
+		r.Total = r.Bonded() + r.NonBonded() + r.Contact + r.Restraint
+	}
+	return energies
+}
+
+// HighEnergyResidues returns the indices of residues whose Total is positive
+// and more than threshold standard deviations above the mean over residues
+//
+// BIOCHEMIST: Clashes and strained bonds concentrate positive energy in a
+// few residues; a well-packed structure has no such outliers, so an empty
+// result is the healthy case. threshold 2-3 is typical.
+func HighEnergyResidues(energies []ResidueEnergy, threshold float64) []int {
+	if len(energies) == 0 {
+		return nil
+	}
+
+	mean := 0.0
+	for _, r := range energies {
+		mean += r.Total
+	}
+	mean /= float64(len(energies))
+
+	variance := 0.0
+	for _, r := range energies {
+		variance += (r.Total - mean) * (r.Total - mean)
+	}
+	std := math.Sqrt(variance / float64(len(energies)))
+
+	var flagged []int
+	for i, r := range energies {
+		if r.Total > 0 && r.Total > mean+threshold*std {
+			flagged = append(flagged, i)
+		}
+	}
+	return flagged
+}
+
+// atomResidueIndex returns a lookup from atom to the index of its residue in
+// protein.Residues, matched on chain, residue number and insertion code
+func atomResidueIndex(protein *parser.Protein) func(atom *parser.Atom) (int, bool) {
+	type key struct {
+		chain string
+		seq   int
+		iCode string
+	}
+	index := make(map[key]int, len(protein.Residues))
+	for i, res := range protein.Residues {
+		if res != nil {
+			index[key{res.ChainID, res.SeqNum, res.ICode}] = i
+		}
+	}
+	return func(atom *parser.Atom) (int, bool) {
+		i, ok := index[key{atom.ChainID, atom.ResSeq, atom.ICode}]
+		return i, ok
+	}
+}
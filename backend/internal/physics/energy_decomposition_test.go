@@ -0,0 +1,90 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDecomposeEnergyPerResidue checks the per-residue energies sum to the
+// global total with every optional term on, and that a clash placed on one
+// residue makes it the highest-energy residue
+func TestDecomposeEnergyPerResidue(t *testing.T) {
+	helix := buildUniformChain(t, 12, -57, -47)
+
+	config := DefaultEnergyConfig()
+	config.UseCMAP = true
+	config.UseHBonds = true
+	config.UseImpropers = true
+	config.ContactRestraints = []ContactRestraint{{Residue1: 0, Residue2: 11, Target: 7.0, Weight: 0.8}}
+
+	sumCheck := func(label string) []ResidueEnergy {
+		t.Helper()
+		global := CalculateTotalEnergyWithConfig(helix, config)
+		residues := DecomposeEnergyPerResidue(helix, config)
+		if len(residues) != len(helix.Residues) {
+			t.Fatalf("%s: %d residue energies for %d residues", label, len(residues), len(helix.Residues))
+		}
+		var sum ResidueEnergy
+		for _, r := range residues {
+			sum.Bond += r.Bond
+			sum.Angle += r.Angle
+			sum.Dihedral += r.Dihedral
+			sum.VanDerWaals += r.VanDerWaals
+			sum.Electrostatic += r.Electrostatic
+			sum.CMAP += r.CMAP
+			sum.Improper += r.Improper
+			sum.HBond += r.HBond
+			sum.Contact += r.Contact
+			sum.Total += r.Total
+		}
+		for _, term := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"Bond", sum.Bond, global.Bond},
+			{"Angle", sum.Angle, global.Angle},
+			{"Dihedral", sum.Dihedral, global.Dihedral},
+			{"VanDerWaals", sum.VanDerWaals, global.VanDerWaals},
+			{"Electrostatic", sum.Electrostatic, global.Electrostatic},
+			{"CMAP", sum.CMAP, global.CMAP},
+			{"Improper", sum.Improper, global.Improper},
+			{"HBond", sum.HBond, global.HBond},
+			{"Contact", sum.Contact, global.Contact},
+			{"Total", sum.Total, sumComponents(global)},
+		} {
+			if math.Abs(term.got-term.want) > 1e-6 {
+				t.Errorf("%s: residue %s sum %.9f, global %.9f", label, term.name, term.got, term.want)
+			}
+		}
+		t.Logf("%s: total %.3f kcal/mol over %d residues (H-bond %.3f)", label, sum.Total, len(residues), sum.HBond)
+		return residues
+	}
+	sumCheck("helix")
+
+	// Clash: residue 6's carbonyl O pushed onto residue 10's N
+	o, n := helix.Residues[6].O, helix.Residues[10].N
+	o.X, o.Y, o.Z = n.X+0.8, n.Y, n.Z
+	residues := sumCheck("clash")
+
+	worst := 0
+	for i, r := range residues {
+		if r.Total > residues[worst].Total {
+			worst = i
+		}
+	}
+	t.Logf("Residue 6: %.1f kcal/mol (bonded %.1f, non-bonded %.1f); residue 10: %.1f",
+		residues[6].Total, residues[6].Bonded(), residues[6].NonBonded(), residues[10].Total)
+	if worst != 6 {
+		t.Errorf("Highest-energy residue is %d (%.1f kcal/mol), want the clashing residue 6 (%.1f)",
+			worst, residues[worst].Total, residues[6].Total)
+	}
+
+	flagged := HighEnergyResidues(residues, 2.0)
+	found := false
+	for _, i := range flagged {
+		found = found || i == 6
+	}
+	if !found {
+		t.Errorf("HighEnergyResidues = %v, want residue 6 flagged", flagged)
+	}
+}
@@ -1,8 +1,11 @@
 package physics
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 )
 
@@ -171,3 +174,42 @@ func isNaN(x float64) bool {
 func isInf(x float64) bool {
 	return x > 1e308 || x < -1e308
 }
+
+// TestVerifyForces checks analytical forces match the finite-difference
+// gradient of the energy for every term
+func TestVerifyForces(t *testing.T) {
+	sequence := "AGPAVA"
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -70 * math.Pi / 180, Psi: 140 * math.Pi / 180}
+	}
+
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Failed to build peptide: %v", err)
+	}
+
+	// Three-letter names select the GLY/PRO Ramachandran terms
+	for _, res := range protein.Residues {
+		res.Name = map[string]string{"A": "ALA", "G": "GLY", "P": "PRO", "V": "VAL"}[res.Name]
+	}
+
+	// Perturb off ideal geometry so every term has a non-zero force
+	rng := rand.New(rand.NewSource(7))
+	for _, atom := range protein.Atoms {
+		atom.X += 0.1 * rng.NormFloat64()
+		atom.Y += 0.1 * rng.NormFloat64()
+		atom.Z += 0.1 * rng.NormFloat64()
+	}
+
+	energy := CalculateTotalEnergy(protein, 10.0, 12.0)
+	t.Logf("Energy: bond %.2f, angle %.2f, dihedral %.2f, VdW %.2f, elec %.2f",
+		energy.Bond, energy.Angle, energy.Dihedral, energy.VanDerWaals, energy.Electrostatic)
+
+	maxError := VerifyForces(protein, 10.0, 12.0)
+	t.Logf("Max force error vs finite difference: %.2e kcal/(mol·Å)", maxError)
+
+	if maxError > 1e-3 {
+		t.Errorf("Max force error %.2e exceeds 1e-3 kcal/(mol·Å)", maxError)
+	}
+}
@@ -199,6 +199,49 @@ func CalculateAngleEnergy(atom1, atom2, atom3 *parser.Atom, params AngleParamete
 	return energy
 }
 
+// CalculateAngleForces computes forces on the three atoms of an angle
+//
+// MATHEMATICIAN:
+// With unit vectors u = (r1-r2)/|r1-r2|, w = (r3-r2)/|r3-r2| and cos θ = u·w:
+// ∂θ/∂r1 = -(w - cos θ u) / (|r1-r2| sin θ)
+// ∂θ/∂r3 = -(u - cos θ w) / (|r3-r2| sin θ)
+// ∂θ/∂r2 = -(∂θ/∂r1 + ∂θ/∂r3)
+// F_i = -2k(θ - θ_0) ∂θ/∂r_i
+//
+// Returns forces on atom1, atom2 (central) and atom3
+func CalculateAngleForces(atom1, atom2, atom3 *parser.Atom, params AngleParameters) (Vector3, Vector3, Vector3) {
+	v1 := Vector3{X: atom1.X - atom2.X, Y: atom1.Y - atom2.Y, Z: atom1.Z - atom2.Z}
+	v2 := Vector3{X: atom3.X - atom2.X, Y: atom3.Y - atom2.Y, Z: atom3.Z - atom2.Z}
+
+	mag1 := v1.Magnitude()
+	mag2 := v2.Magnitude()
+	if mag1 == 0 || mag2 == 0 {
+		return Vector3{}, Vector3{}, Vector3{}
+	}
+
+	u := v1.Mul(1.0 / mag1)
+	w := v2.Mul(1.0 / mag2)
+	cosTheta := u.Dot(w)
+
+	// Gradient is singular for collinear atoms (sin θ = 0)
+	sinTheta := math.Sqrt(math.Max(0, 1.0-cosTheta*cosTheta))
+	if sinTheta < 1e-8 {
+		return Vector3{}, Vector3{}, Vector3{}
+	}
+
+	theta := math.Acos(math.Max(-1.0, math.Min(1.0, cosTheta)))
+	dEdTheta := 2.0 * params.K0 * (theta - params.Theta0)
+
+	dTheta1 := w.Sub(u.Mul(cosTheta)).Mul(-1.0 / (mag1 * sinTheta))
+	dTheta3 := u.Sub(w.Mul(cosTheta)).Mul(-1.0 / (mag2 * sinTheta))
+
+	f1 := dTheta1.Mul(-dEdTheta)
+	f3 := dTheta3.Mul(-dEdTheta)
+	f2 := f1.Add(f3).Mul(-1)
+
+	return f1, f2, f3
+}
+
 // LennardJonesParams holds van der Waals parameters
 //
 // Citation: AMBER ff14SB parameters
@@ -210,7 +253,7 @@ type LennardJonesParams struct {
 	Sigma float64
 }
 
-// Standard Lennard-Jones parameters for common atoms
+// Element-level Lennard-Jones parameters, for atoms without an ff14SB type
 var ljParams = map[string]LennardJonesParams{
 	"C": {Epsilon: 0.086, Sigma: 1.908}, // Carbon (sp3)
 	"N": {Epsilon: 0.170, Sigma: 1.824}, // Nitrogen (amide)
@@ -226,11 +269,23 @@ var ljParams = map[string]LennardJonesParams{
 // - Repulsive term (r⁻¹²): Pauli exclusion at short range
 // - Attractive term (r⁻⁶): London dispersion forces
 //
+// Parameters come from each atom's ff14SB type (DefaultForceFieldParams),
+// falling back to element defaults for atoms it does not list.
+//
 // Citation: Jones, J. E. (1924). "On the determination of molecular fields."
 // Proc. R. Soc. Lond. A 106.738: 463-477.
 //
 // Returns energy in kcal/mol
 func CalculateLennardJonesEnergy(atom1, atom2 *parser.Atom, cutoff float64) float64 {
+	params1 := ff14SBParams.Lookup(atom1.ResName, atom1.Name, atom1.Element).LJ
+	params2 := ff14SBParams.Lookup(atom2.ResName, atom2.Name, atom2.Element).LJ
+	return lennardJonesPairEnergy(atom1, atom2, params1, params2, 0, cutoff)
+}
+
+// lennardJonesPairEnergy is CalculateLennardJonesEnergy with the atoms'
+// parameters already looked up, switched off over [switchStart, cutoff]
+// (see cutoffSwitch)
+func lennardJonesPairEnergy(atom1, atom2 *parser.Atom, params1, params2 LennardJonesParams, switchStart, cutoff float64) float64 {
 	// Calculate distance
 	dx := atom2.X - atom1.X
 	dy := atom2.Y - atom1.Y
@@ -242,17 +297,7 @@ func CalculateLennardJonesEnergy(atom1, atom2 *parser.Atom, cutoff float64) floa
 		return 0
 	}
 
-	// Get LJ parameters (combine using Lorentz-Berthelot rules)
-	params1, ok1 := ljParams[atom1.Element]
-	params2, ok2 := ljParams[atom2.Element]
-
-	if !ok1 || !ok2 {
-		// Default params for unknown elements
-		params1 = LennardJonesParams{Epsilon: 0.1, Sigma: 1.8}
-		params2 = params1
-	}
-
-	// Combining rules:
+	// Combining rules (Lorentz-Berthelot):
 	// ε_ij = sqrt(ε_i × ε_j)
 	// σ_ij = (σ_i + σ_j) / 2
 	epsilon := math.Sqrt(params1.Epsilon * params2.Epsilon)
@@ -265,7 +310,51 @@ func CalculateLennardJonesEnergy(atom1, atom2 *parser.Atom, cutoff float64) floa
 
 	energy := 4.0 * epsilon * (term12 - term6)
 
-	return energy
+	sw, _ := cutoffSwitch(r, switchStart, cutoff)
+	return energy * sw
+}
+
+// CalculateLennardJonesForce computes the van der Waals force on atom2
+//
+// MATHEMATICIAN:
+// dE/dr = 4ε × [-12σ¹²/r¹³ + 6σ⁶/r⁷]
+// F_2 = -dE/dr × (r_2 - r_1)/r, F_1 = -F_2
+//
+// Returns force on atom2 (atom1 receives the opposite force)
+func CalculateLennardJonesForce(atom1, atom2 *parser.Atom, cutoff float64) Vector3 {
+	// Same parameter lookup as CalculateLennardJonesEnergy
+	params1 := ff14SBParams.Lookup(atom1.ResName, atom1.Name, atom1.Element).LJ
+	params2 := ff14SBParams.Lookup(atom2.ResName, atom2.Name, atom2.Element).LJ
+	return lennardJonesPairForce(atom1, atom2, params1, params2, 0, cutoff)
+}
+
+// lennardJonesPairForce is CalculateLennardJonesForce with the atoms'
+// parameters already looked up, for the switched lennardJonesPairEnergy
+func lennardJonesPairForce(atom1, atom2 *parser.Atom, params1, params2 LennardJonesParams, switchStart, cutoff float64) Vector3 {
+	dx := atom2.X - atom1.X
+	dy := atom2.Y - atom1.Y
+	dz := atom2.Z - atom1.Z
+	r := math.Sqrt(dx*dx + dy*dy + dz*dz)
+
+	if r > cutoff || r == 0 {
+		return Vector3{X: 0, Y: 0, Z: 0}
+	}
+
+	epsilon := math.Sqrt(params1.Epsilon * params2.Epsilon)
+	sigma := (params1.Sigma + params2.Sigma) / 2.0
+
+	sigmaOverR := sigma / r
+	term6 := math.Pow(sigmaOverR, 6)
+	term12 := term6 * term6
+
+	dEdr := 4.0 * epsilon * (-12.0*term12 + 6.0*term6) / r
+
+	// d(E·S)/dr = dE/dr·S + E·dS/dr
+	if sw, dsdr := cutoffSwitch(r, switchStart, cutoff); sw < 1 {
+		dEdr = dEdr*sw + 4.0*epsilon*(term12-term6)*dsdr
+	}
+
+	return Vector3{X: dx / r, Y: dy / r, Z: dz / r}.Mul(-dEdr)
 }
 
 // CalculateElectrostaticEnergy computes Coulomb electrostatic energy
@@ -283,6 +372,12 @@ func CalculateLennardJonesEnergy(atom1, atom2 *parser.Atom, cutoff float64) floa
 //
 // Returns energy in kcal/mol
 func CalculateElectrostaticEnergy(atom1, atom2 *parser.Atom, charge1, charge2, cutoff float64) float64 {
+	return electrostaticPairEnergy(atom1, atom2, charge1, charge2, 0, cutoff)
+}
+
+// electrostaticPairEnergy is CalculateElectrostaticEnergy switched off over
+// [switchStart, cutoff] (see cutoffSwitch)
+func electrostaticPairEnergy(atom1, atom2 *parser.Atom, charge1, charge2, switchStart, cutoff float64) float64 {
 	// Calculate distance
 	dx := atom2.X - atom1.X
 	dy := atom2.Y - atom1.Y
@@ -305,7 +400,42 @@ func CalculateElectrostaticEnergy(atom1, atom2 *parser.Atom, charge1, charge2, c
 	// Coulomb energy
 	energy := (kCoulomb * charge1 * charge2) / dielectric
 
-	return energy
+	sw, _ := cutoffSwitch(r, switchStart, cutoff)
+	return energy * sw
+}
+
+// CalculateElectrostaticForce computes the Coulomb force on atom2
+//
+// MATHEMATICIAN:
+// CalculateElectrostaticEnergy evaluates E = k q_i q_j / (4r), so
+// dE/dr = -k q_i q_j / (4r²)
+//
+// Returns force on atom2 (atom1 receives the opposite force)
+func CalculateElectrostaticForce(atom1, atom2 *parser.Atom, charge1, charge2, cutoff float64) Vector3 {
+	return electrostaticPairForce(atom1, atom2, charge1, charge2, 0, cutoff)
+}
+
+// electrostaticPairForce is CalculateElectrostaticForce for the switched
+// electrostaticPairEnergy
+func electrostaticPairForce(atom1, atom2 *parser.Atom, charge1, charge2, switchStart, cutoff float64) Vector3 {
+	dx := atom2.X - atom1.X
+	dy := atom2.Y - atom1.Y
+	dz := atom2.Z - atom1.Z
+	r := math.Sqrt(dx*dx + dy*dy + dz*dz)
+
+	if r > cutoff || r == 0 {
+		return Vector3{X: 0, Y: 0, Z: 0}
+	}
+
+	kCoulomb := 332.06
+	dEdr := -kCoulomb * charge1 * charge2 / (4.0 * r * r)
+
+	// d(E·S)/dr = dE/dr·S + E·dS/dr
+	if sw, dsdr := cutoffSwitch(r, switchStart, cutoff); sw < 1 {
+		dEdr = dEdr*sw + kCoulomb*charge1*charge2/(4.0*r)*dsdr
+	}
+
+	return Vector3{X: dx / r, Y: dy / r, Z: dz / r}.Mul(-dEdr)
 }
 
 // GetBondParams returns force field parameters for a bond
@@ -341,3 +471,16 @@ func GetAngleParams(atomType1, atomType2, atomType3 string) AngleParameters {
 	// Default
 	return backboneAngleParams["default"]
 }
+
+// GetLennardJonesParams returns van der Waals parameters for an element
+//
+// Sigma values are the AMBER R*/2 radii (half the pair minimum-energy
+// distance). This is the element-level fallback of ForceFieldParams.Lookup.
+func GetLennardJonesParams(element string) LennardJonesParams {
+	if params, ok := ljParams[element]; ok {
+		return params
+	}
+
+	// Default
+	return defaultLJParams
+}
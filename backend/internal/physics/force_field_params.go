@@ -0,0 +1,195 @@
+// Package physics - Per-atom-type force field parameters
+//
+// The nonbonded terms used to look up Lennard-Jones parameters by element,
+// so every carbon was the same carbon, and charged only N, CA, C and O with
+// one set of backbone values. ForceFieldParams assigns each (residue, atom
+// name) its AMBER ff14SB atom type, partial charge and the type's LJ
+// parameters, so a glycine CA, a carboxylate O2 and an amide H each get
+// their own.
+//
+// BIOCHEMIST: Types follow ff14SB: CX for protein CA, 2C/3C/C8 for side-chain
+// sp3 carbons, O2 for carboxylate oxygens; HIS is the HIE tautomer
+// PHYSICIST: LJ parameters keep this package's convention (Sigma = R*/2,
+// combined as in CalculateLennardJonesEnergy); charges are amino12.lib
+// MATHEMATICIAN: Lookup falls back from the residue's entry to the generic
+// backbone/terminal entry ("*"), then to the element defaults (ljParams)
+// with zero charge, so every atom gets parameters
+//
+// CITATION:
+// Maier, J. A., et al. (2015). "ff14SB: Improving the accuracy of protein
+// side chain and backbone parameters from ff99SB." J. Chem. Theory Comput.
+// 11(8): 3696-3713.
+package physics
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// genericResidue is the table entry for atoms of any residue
+const genericResidue = "*"
+
+// defaultLJParams are used for atoms with no type and an unknown element
+var defaultLJParams = LennardJonesParams{Epsilon: 0.1, Sigma: 1.8}
+
+// AtomTypeParams are the nonbonded parameters of one atom
+type AtomTypeParams struct {
+	Type   string  // AMBER atom type ("" for an element fallback)
+	Charge float64 // Partial charge (e)
+	LJ     LennardJonesParams
+}
+
+// atomAssignment is one "<atom> <type> <charge>" table line
+type atomAssignment struct {
+	Type   string
+	Charge float64
+}
+
+// ForceFieldParams maps residue + atom name to atom types, charges and LJ
+// parameters
+type ForceFieldParams struct {
+	types    map[string]LennardJonesParams
+	residues map[string]map[string]atomAssignment
+}
+
+//go:embed ff14sb_params.txt
+var ff14SBTableData string
+
+// ff14SBParams are parsed once from the embedded table
+var ff14SBParams = mustParseForceFieldParams(ff14SBTableData)
+
+// DefaultForceFieldParams returns the built-in AMBER ff14SB parameters
+func DefaultForceFieldParams() *ForceFieldParams {
+	return ff14SBParams
+}
+
+// LoadForceFieldParams reads a parameter table in the format of
+// ff14sb_params.txt: "type <name> <R*/2> <epsilon>" lines, then
+// "residue <name>" blocks of "<atom> <type> <charge>" lines
+func LoadForceFieldParams(r io.Reader) (*ForceFieldParams, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read force field parameters: %w", err)
+	}
+	return parseForceFieldParams(string(data))
+}
+
+// Lookup returns the parameters of atom atomName in residue resName
+// (three- or one-letter code); element picks the fallback LJ parameters
+// for atoms the table does not list
+func (p *ForceFieldParams) Lookup(resName, atomName, element string) AtomTypeParams {
+	for _, res := range []string{strings.ToUpper(resName), genericResidue} {
+		if assignment, ok := p.residues[res][atomName]; ok {
+			return AtomTypeParams{Type: assignment.Type, Charge: assignment.Charge, LJ: p.types[assignment.Type]}
+		}
+	}
+
+	return AtomTypeParams{LJ: GetLennardJonesParams(element)}
+}
+
+// atomTypeParams returns the parameters of each atom in protein.Atoms
+func (p *ForceFieldParams) atomTypeParams(protein *parser.Protein) []AtomTypeParams {
+	params := make([]AtomTypeParams, len(protein.Atoms))
+	for i, atom := range protein.Atoms {
+		params[i] = p.Lookup(atom.ResName, atom.Name, atom.Element)
+	}
+	return params
+}
+
+// ljParameters returns the Lennard-Jones parameters of each atom in
+// protein.Atoms from the ff14SB types
+func ljParameters(protein *parser.Protein) []LennardJonesParams {
+	lj := make([]LennardJonesParams, len(protein.Atoms))
+	for i, params := range ff14SBParams.atomTypeParams(protein) {
+		lj[i] = params.LJ
+	}
+	return lj
+}
+
+// mustParseForceFieldParams parses the embedded table; malformed data is a
+// build error
+func mustParseForceFieldParams(data string) *ForceFieldParams {
+	params, err := parseForceFieldParams(data)
+	if err != nil {
+		panic(fmt.Sprintf("physics: invalid embedded force field table: %v", err))
+	}
+	return params
+}
+
+// parseForceFieldParams reads type lines and residue blocks (every atom
+// must name a type defined above it) and aliases residues by one-letter code
+func parseForceFieldParams(data string) (*ForceFieldParams, error) {
+	params := &ForceFieldParams{
+		types:    make(map[string]LennardJonesParams),
+		residues: make(map[string]map[string]atomAssignment),
+	}
+	var current map[string]atomAssignment
+
+	for lineNum, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		switch fields[0] {
+		case "type":
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("line %d: want \"type <name> <R*/2> <epsilon>\"", lineNum+1)
+			}
+			sigma, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			epsilon, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			params.types[fields[1]] = LennardJonesParams{Epsilon: epsilon, Sigma: sigma}
+
+		case "residue":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: want \"residue <name>\"", lineNum+1)
+			}
+			name := strings.ToUpper(fields[1])
+			if params.residues[name] != nil {
+				return nil, fmt.Errorf("line %d: residue %s defined twice", lineNum+1, name)
+			}
+			current = make(map[string]atomAssignment)
+			params.residues[name] = current
+
+		default:
+			if current == nil {
+				return nil, fmt.Errorf("line %d: atom before residue header", lineNum+1)
+			}
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: want \"<atom> <type> <charge>\"", lineNum+1)
+			}
+			charge, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			if _, ok := params.types[fields[1]]; !ok {
+				return nil, fmt.Errorf("line %d: atom %s has undefined type %s", lineNum+1, fields[0], fields[1])
+			}
+			current[fields[0]] = atomAssignment{Type: fields[1], Charge: charge}
+		}
+	}
+
+	if len(params.residues) == 0 {
+		return nil, fmt.Errorf("no residues defined")
+	}
+
+	// Coordinate builders name residues by one-letter code
+	for three, one := range threeToOne {
+		if table, ok := params.residues[three]; ok && params.residues[string(one)] == nil {
+			params.residues[string(one)] = table
+		}
+	}
+	return params, nil
+}
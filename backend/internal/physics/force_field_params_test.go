@@ -0,0 +1,73 @@
+package physics
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestForceFieldParamsLookup spot-checks ff14SB types and charges and the
+// fallbacks for unknown residues and atoms
+func TestForceFieldParamsLookup(t *testing.T) {
+	ff := DefaultForceFieldParams()
+
+	cases := []struct {
+		res, atom, element string
+		wantType           string
+		wantCharge         float64
+	}{
+		{"ALA", "O", "O", "O", -0.5679},    // Backbone carbonyl O
+		{"ALA", "N", "N", "N", -0.4157},    // Backbone amide N
+		{"G", "CA", "C", "CX", -0.0252},    // Glycine CA, one-letter name
+		{"PRO", "N", "N", "N", -0.2548},    // Proline N has no H
+		{"ASP", "OD1", "O", "O2", -0.8014}, // Carboxylate side chain
+		{"LYS", "NZ", "N", "N3", -0.3854},
+		{"UNK", "C", "C", "C", 0.5973}, // Unknown residue: generic backbone
+		{"ALA", "ZN", "ZN", "", 0},     // Unknown atom: element fallback
+	}
+	for _, tc := range cases {
+		got := ff.Lookup(tc.res, tc.atom, tc.element)
+		if got.Type != tc.wantType || math.Abs(got.Charge-tc.wantCharge) > 1e-9 {
+			t.Errorf("%s %s: type %q charge %.4f, want %q %.4f", tc.res, tc.atom, got.Type, got.Charge, tc.wantType, tc.wantCharge)
+		}
+	}
+
+	if lj := ff.Lookup("ALA", "ZN", "ZN").LJ; lj != defaultLJParams {
+		t.Errorf("Unknown element LJ %+v, want default %+v", lj, defaultLJParams)
+	}
+	if cx, c := ff.Lookup("ALA", "CA", "C").LJ, ff.Lookup("ALA", "C", "C").LJ; cx.Epsilon == c.Epsilon {
+		t.Errorf("CX and C share ε = %.4f; sp3 and carbonyl carbons should differ", cx.Epsilon)
+	}
+
+	// The energy sees the same charges on a built chain
+	protein := buildUniformChain(t, 6, -60, -45)
+	charges := partialCharges(protein)
+	for i, atom := range protein.Atoms {
+		if atom.Name == "O" && math.Abs(charges[i]-(-0.5679)) > 1e-9 {
+			t.Errorf("Residue %d O charge %.4f, want -0.5679", atom.ResSeq, charges[i])
+		}
+	}
+}
+
+// TestLoadForceFieldParamsErrors checks malformed tables are rejected
+func TestLoadForceFieldParamsErrors(t *testing.T) {
+	bad := []string{
+		"residue ALA\nO X -0.5\n",           // Undefined type
+		"type O 1.6612 0.21\nO O -0.5679\n", // Atom before residue
+		"type O 1.6612\n",                   // Short type line
+		"type O 1.6612 0.21\n",              // No residues
+	}
+	for _, table := range bad {
+		if _, err := LoadForceFieldParams(strings.NewReader(table)); err == nil {
+			t.Errorf("Table %q loaded without error", table)
+		}
+	}
+
+	ff, err := LoadForceFieldParams(strings.NewReader("type O 1.6612 0.21\nresidue ALA\nO O -0.5\n"))
+	if err != nil {
+		t.Fatalf("Valid table rejected: %v", err)
+	}
+	if got := ff.Lookup("A", "O", "O"); got.Charge != -0.5 || got.LJ.Sigma != 1.6612 {
+		t.Errorf("Loaded table lookup %+v", got)
+	}
+}
@@ -0,0 +1,180 @@
+// Package physics - Smooth backbone hydrogen-bond energy
+//
+// DetectHydrogenBonds applies hard distance and angle cutoffs, so its energy
+// jumps as a bond forms or breaks and has no usable gradient. HBondEnergy
+// scores every backbone N-H···O=C pair with a term that goes to zero
+// smoothly at its cutoffs, so it can sit in the optimizer's objective
+// (EnergyConfig.UseHBonds).
+//
+// BIOCHEMIST: Donors are backbone amides (explicit H or HN; otherwise H is
+// placed on the C(i-1)-N-CA bisector), acceptors are carbonyl O; as in
+// DetectHydrogenBonds, residues adjacent in sequence do not pair
+// PHYSICIST: E = ε · exp(-(d - d0)²/w) · cos²θ · S(d), with d the N···O
+// distance, θ the N-H···O angle (cos²θ for θ > 90°, else 0) and S the
+// CHARMM switch from 1 at hbondSwitchOn to 0 at hbondCutoff
+// MATHEMATICIAN: cos²θ and S are C¹ at their ends (zero value and slope),
+// so the energy and its gradient are continuous everywhere; forces are
+// central differences of each pair's energy
+//
+// CITATION:
+// Brooks, B. R., et al. (1983). "CHARMM: A program for macromolecular energy,
+// minimization, and dynamics calculations." J. Comput. Chem. 4(2): 187-217.
+// (switching function)
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Smooth H-bond parameters
+const (
+	hbondWellDepth = -5.0 // kcal/mol, as calculateHBondEnergy
+	hbondOptimal   = 2.9  // Å, N···O
+	hbondWidth     = 0.2  // Å², Gaussian width
+	hbondSwitchOn  = 3.5  // Å, switching starts
+	hbondCutoff    = 4.0  // Å, energy is zero beyond
+	hbondVirtualNH = 1.01 // Å, N-H length for a placed H
+	hbondFDStep    = 1e-6 // Å, force finite-difference step
+)
+
+// hbondDonor is a backbone amide: N with an explicit H, or with the prevC
+// and CA that place a virtual H
+type hbondDonor struct {
+	residue *parser.Residue
+	n, h    *parser.Atom
+	prevC   *parser.Atom
+	ca      *parser.Atom
+}
+
+// hydrogen returns the donor H position
+func (d hbondDonor) hydrogen() Vector3 {
+	n := atomVector(d.n)
+	if d.h != nil {
+		return atomVector(d.h)
+	}
+	bisector := n.Sub(atomVector(d.prevC)).Normalize().Add(n.Sub(atomVector(d.ca)).Normalize())
+	return n.Add(bisector.Normalize().Mul(hbondVirtualNH))
+}
+
+// atoms returns the atoms that determine the donor's geometry
+func (d hbondDonor) atoms() []*parser.Atom {
+	if d.h != nil {
+		return []*parser.Atom{d.n, d.h}
+	}
+	return []*parser.Atom{d.n, d.prevC, d.ca}
+}
+
+// HBondEnergy returns the smooth backbone hydrogen-bond energy (kcal/mol)
+func HBondEnergy(protein *parser.Protein) float64 {
+	total := 0.0
+	forEachHBondPair(protein, func(donor hbondDonor, acceptor *parser.Atom) {
+		total += hbondPairEnergy(donor, acceptor)
+	})
+	return total
+}
+
+// addHBondForces adds -∇HBondEnergy to the force map
+func addHBondForces(protein *parser.Protein, forces map[int]Vector3) {
+	forEachHBondPair(protein, func(donor hbondDonor, acceptor *parser.Atom) {
+		if hbondPairEnergy(donor, acceptor) == 0 {
+			return // Beyond the cutoff or angle, and so is the gradient
+		}
+		for _, atom := range append(donor.atoms(), acceptor) {
+			coords := []*float64{&atom.X, &atom.Y, &atom.Z}
+			var grad [3]float64
+			for k, coord := range coords {
+				orig := *coord
+				*coord = orig + hbondFDStep
+				ePlus := hbondPairEnergy(donor, acceptor)
+				*coord = orig - hbondFDStep
+				eMinus := hbondPairEnergy(donor, acceptor)
+				*coord = orig
+				grad[k] = (ePlus - eMinus) / (2 * hbondFDStep)
+			}
+			forces[atom.Serial] = forces[atom.Serial].Add(Vector3{X: -grad[0], Y: -grad[1], Z: -grad[2]})
+		}
+	})
+}
+
+// hbondPairEnergy is the smooth energy of one donor-acceptor pair
+func hbondPairEnergy(donor hbondDonor, acceptor *parser.Atom) float64 {
+	n, o := atomVector(donor.n), atomVector(acceptor)
+	d := o.Sub(n).Magnitude()
+	if d >= hbondCutoff {
+		return 0
+	}
+
+	h := donor.hydrogen()
+	cosTheta := n.Sub(h).Normalize().Dot(o.Sub(h).Normalize())
+	if cosTheta >= 0 {
+		return 0 // θ <= 90°: O is not in front of the N-H
+	}
+
+	radial := math.Exp(-(d - hbondOptimal) * (d - hbondOptimal) / hbondWidth)
+	return hbondWellDepth * radial * cosTheta * cosTheta * hbondSwitch(d)
+}
+
+// hbondSwitch is the CHARMM switching function: 1 below hbondSwitchOn, 0
+// above hbondCutoff, with zero slope at both ends
+func hbondSwitch(d float64) float64 {
+	s, _ := cutoffSwitch(d, hbondSwitchOn, hbondCutoff)
+	return s
+}
+
+// forEachHBondPair calls fn for every backbone donor-acceptor pair not
+// adjacent in sequence
+func forEachHBondPair(protein *parser.Protein, fn func(donor hbondDonor, acceptor *parser.Atom)) {
+	hydrogens := make(map[*parser.Residue]*parser.Atom)
+	type residueKey struct {
+		chainID string
+		seq     int
+		iCode   string
+	}
+	byKey := make(map[residueKey]*parser.Residue, len(protein.Residues))
+	for _, res := range protein.Residues {
+		if res != nil {
+			byKey[residueKey{res.ChainID, res.SeqNum, res.ICode}] = res
+		}
+	}
+	for _, atom := range protein.Atoms {
+		if atom.Name == "H" || atom.Name == "HN" {
+			if res := byKey[residueKey{atom.ChainID, atom.ResSeq, atom.ICode}]; res != nil {
+				hydrogens[res] = atom
+			}
+		}
+	}
+
+	donors := make([]hbondDonor, 0, len(protein.Residues))
+	for i, res := range protein.Residues {
+		if res == nil || res.N == nil {
+			continue
+		}
+		donor := hbondDonor{residue: res, n: res.N, h: hydrogens[res]}
+		if donor.h == nil {
+			if i == 0 || res.CA == nil || protein.Residues[i-1] == nil || protein.Residues[i-1].C == nil {
+				continue // No H to place
+			}
+			donor.prevC, donor.ca = protein.Residues[i-1].C, res.CA
+		}
+		donors = append(donors, donor)
+	}
+
+	for _, donor := range donors {
+		for _, acceptor := range protein.Residues {
+			if acceptor == nil || acceptor.O == nil {
+				continue
+			}
+			if acceptor.ChainID == donor.residue.ChainID && abs(acceptor.SeqNum-donor.residue.SeqNum) <= 1 {
+				continue
+			}
+			fn(donor, acceptor.O)
+		}
+	}
+}
+
+// atomVector returns an atom's position
+func atomVector(atom *parser.Atom) Vector3 {
+	return Vector3{X: atom.X, Y: atom.Y, Z: atom.Z}
+}
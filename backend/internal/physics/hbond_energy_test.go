@@ -0,0 +1,105 @@
+package physics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// buildUniformChain builds a poly-Ala chain with every residue at (phi, psi) degrees
+func buildUniformChain(t *testing.T, n int, phi, psi float64) *parser.Protein {
+	t.Helper()
+	angles := make([]geometry.RamachandranAngles, n)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: phi * math.Pi / 180, Psi: psi * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles(strings.Repeat("A", n), angles)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return protein
+}
+
+// TestHBondEnergyHelix checks the helical i→i+4 H-bonds lower the total
+// energy with UseHBonds, and the forces are its exact negative gradient
+func TestHBondEnergyHelix(t *testing.T) {
+	helix := buildUniformChain(t, 8, -57, -47)
+	extended := buildUniformChain(t, 8, -120, 120)
+
+	config := DefaultEnergyConfig()
+	without := CalculateTotalEnergyWithConfig(helix, config)
+	config.UseHBonds = true
+	with := CalculateTotalEnergyWithConfig(helix, config)
+
+	// The O(1)···N(5) pair is the first native i→i+4 H-bond
+	pair := 0.0
+	forEachHBondPair(helix, func(donor hbondDonor, acceptor *parser.Atom) {
+		if donor.residue.SeqNum == 5 && acceptor.ResSeq == 1 {
+			pair = hbondPairEnergy(donor, acceptor)
+		}
+	})
+
+	t.Logf("Helix: H-bond %.3f kcal/mol (O1···N5 %.3f), total %.3f → %.3f; extended H-bond %.3f",
+		with.HBond, pair, without.Total, with.Total, HBondEnergy(extended))
+
+	if pair > -1.0 {
+		t.Errorf("Native i→i+4 H-bond energy %.3f kcal/mol, want < -1", pair)
+	}
+	if with.Total >= without.Total || math.Abs((with.Total-without.Total)-with.HBond) > 1e-9 {
+		t.Errorf("UseHBonds changed the total by %.3f, want the H-bond energy %.3f", with.Total-without.Total, with.HBond)
+	}
+	if e := HBondEnergy(extended); with.HBond >= e {
+		t.Errorf("Helix H-bond energy %.3f not below extended %.3f", with.HBond, e)
+	}
+
+	for i, atom := range helix.Atoms {
+		atom.X += 0.05 * math.Sin(float64(i))
+		atom.Y += 0.05 * math.Cos(float64(i))
+	}
+	maxError := VerifyForcesWithConfig(helix, config)
+	t.Logf("Max force error with H-bonds: %.2e kcal/(mol·Å)", maxError)
+	if maxError > 1e-3 {
+		t.Errorf("H-bond forces disagree with finite differences: %.2e", maxError)
+	}
+}
+
+// TestHBondEnergyContinuous moves an acceptor along a linear N-H···O line
+// through the switching region and checks energy and slope have no jumps
+func TestHBondEnergyContinuous(t *testing.T) {
+	donorRes := &parser.Residue{Name: "A", SeqNum: 5, ChainID: "A"}
+	donor := hbondDonor{
+		residue: donorRes,
+		n:       &parser.Atom{Name: "N"},
+		h:       &parser.Atom{Name: "H", X: 1.01},
+	}
+	acceptor := &parser.Atom{Name: "O"}
+	energyAt := func(d float64) float64 {
+		acceptor.X = d
+		return hbondPairEnergy(donor, acceptor)
+	}
+
+	const eps = 1e-7
+	for _, d := range []float64{hbondSwitchOn, hbondCutoff} {
+		below, above := energyAt(d-eps), energyAt(d+eps)
+		slopeBelow := (energyAt(d-eps) - energyAt(d-2*eps)) / eps
+		slopeAbove := (energyAt(d+2*eps) - energyAt(d+eps)) / eps
+		t.Logf("d = %.2f Å: E %.6f | %.6f, slope %.6f | %.6f", d, below, above, slopeBelow, slopeAbove)
+
+		if math.Abs(above-below) > 1e-5 {
+			t.Errorf("Energy jumps by %.2e at %.2f Å", above-below, d)
+		}
+		if math.Abs(slopeAbove-slopeBelow) > 1e-2 {
+			t.Errorf("Slope jumps from %.4f to %.4f at %.2f Å", slopeBelow, slopeAbove, d)
+		}
+	}
+
+	if e := energyAt(hbondOptimal); e > 0.9*hbondWellDepth {
+		t.Errorf("Linear H-bond at optimum %.3f kcal/mol, want about %.1f", e, hbondWellDepth)
+	}
+	if e := energyAt(hbondCutoff + 0.1); e != 0 {
+		t.Errorf("Energy %.3e beyond the cutoff", e)
+	}
+}
@@ -0,0 +1,63 @@
+package physics
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestDetectHydrogenBondsCHARMMNames checks that an H-bond whose acceptor
+// is a CHARMM C-terminal OT1 is found once atom names are normalized
+func TestDetectHydrogenBondsCHARMMNames(t *testing.T) {
+	// GLY 1 donates N-HN along +x to OT1 of GLY 3 (H···O 1.99 Å, 180°)
+	atoms := []struct {
+		name   string
+		resSeq int
+		x, y   float64
+	}{
+		{"N", 1, 0.0, 0.0}, {"HN", 1, 1.01, 0.0}, {"CA", 1, -0.5, 1.4},
+		{"C", 1, -2.0, 1.5}, {"O", 1, -2.6, 2.5},
+		{"N", 3, 6.4, 1.0}, {"CA", 3, 5.0, 1.2}, {"C", 3, 4.2, 0.0},
+		{"OT1", 3, 3.0, 0.0}, {"OT2", 3, 4.8, -1.1},
+	}
+	var pdb strings.Builder
+	for i, a := range atoms {
+		pdb.WriteString(fmt.Sprintf("ATOM  %5d %-4s GLY A%4d    %8.3f%8.3f%8.3f  1.00 10.00           %1s\n",
+			i+1, a.name, a.resSeq, a.x, a.y, 0.0, a.name[:1]))
+	}
+	pdb.WriteString("END\n")
+
+	path := t.TempDir() + "/charmm.pdb"
+	if err := os.WriteFile(path, []byte(pdb.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write PDB: %v", err)
+	}
+
+	raw, err := parser.ParsePDB(path)
+	if err != nil {
+		t.Fatalf("ParsePDB failed: %v", err)
+	}
+	if hbonds := DetectHydrogenBonds(raw); len(hbonds) != 0 {
+		t.Errorf("Without normalization OT1 is no acceptor, yet %d H-bonds were found", len(hbonds))
+	}
+
+	protein, err := parser.ParsePDBWithOptions(path, parser.PDBOptions{NormalizeNames: true})
+	if err != nil {
+		t.Fatalf("ParsePDBWithOptions failed: %v", err)
+	}
+	hbonds := DetectHydrogenBonds(protein)
+	if len(hbonds) != 1 {
+		t.Fatalf("Expected 1 H-bond after normalization, got %d", len(hbonds))
+	}
+
+	hb := hbonds[0]
+	t.Logf("H-bond %d → %d: %.2f Å, %.0f°", hb.DonorResidue.SeqNum, hb.AcceptorResidue.SeqNum, hb.Distance, hb.Angle)
+	if hb.DonorResidue.SeqNum != 1 || hb.AcceptorResidue.SeqNum != 3 || hb.AcceptorAtom.Name != "O" {
+		t.Errorf("Expected GLY 1 N-H → GLY 3 O, got %d → %d %s", hb.DonorResidue.SeqNum, hb.AcceptorResidue.SeqNum, hb.AcceptorAtom.Name)
+	}
+	if hb.Distance > 2.1 {
+		t.Errorf("H···O distance %.2f Å: the explicit H should have been used", hb.Distance)
+	}
+}
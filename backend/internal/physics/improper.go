@@ -0,0 +1,129 @@
+// Package physics - Improper dihedral (planarity and chirality) terms
+//
+// Proper torsions describe rotation about bonds; nothing in them stops a
+// minimizer from pushing a carbonyl O out of the peptide plane or swapping
+// Cα substituents through the tetrahedral center into a D-amino acid.
+// Impropers restrain the out-of-plane geometry of those centers directly.
+//
+// BIOCHEMIST: Three terms per residue: the carbonyl C (CA, N(i+1), C, O
+// coplanar), the peptide bond (ω = CA-C-N-CA at 0° or 180°, so cis
+// prolines stay cis) and the Cα chirality of residues with a Cβ atom
+// PHYSICIST: Harmonic E = k (ξ - ξ0)² about the ideal improper ξ0, in
+// kcal/(mol·rad²), CHARMM style; the ω term restrains to the nearer of
+// cis and trans
+// MATHEMATICIAN: ξ is an ordinary dihedral of the four atoms, so forces
+// reuse the torsion gradient (dihedralGradient)
+//
+// CITATION:
+// MacKerell, A. D., et al. (1998). "All-atom empirical potential for molecular
+// modeling and dynamics studies of proteins." J. Phys. Chem. B 102(18): 3586-3616.
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Improper force constants, kcal/(mol·rad²)
+const (
+	improperCarbonylK  = 120.0 // CHARMM22 O-CA-N-C (O=C out of plane)
+	improperOmegaK     = 25.0  // Peptide bond planarity, on top of the ω Fourier term
+	improperChiralityK = 40.0  // Cα tetrahedral center
+)
+
+// improperChiralityIdeal is N-C-CA-CB for an L residue with the ideal
+// N-CA-C angle of the coordinate builder (radians); a D residue sits at
+// the negative of it
+const improperChiralityIdeal = 122.62 * math.Pi / 180
+
+// improperTerm is one harmonic improper
+type improperTerm struct {
+	atoms    [4]*parser.Atom
+	ideal    float64 // ξ0 (radians)
+	k        float64
+	cisTrans bool // Restrain to the nearer of 0 and π instead of ideal
+	residue  int  // Owning residue index in protein.Residues
+}
+
+// deviation returns ξ - ξ0 wrapped to [-π, π]
+func (t improperTerm) deviation() float64 {
+	xi := torsionAngle(t.atoms)
+	if t.cisTrans {
+		// Distance to the nearer of 0 and π: wrap with period π
+		return math.Remainder(xi, math.Pi)
+	}
+	return math.Remainder(xi-t.ideal, 2*math.Pi)
+}
+
+// ImproperEnergy returns the harmonic improper energy (kcal/mol) for
+// carbonyl planarity, peptide-bond planarity and Cα chirality
+func ImproperEnergy(protein *parser.Protein) float64 {
+	if protein == nil {
+		return 0.0
+	}
+
+	total := 0.0
+	for _, t := range enumerateImpropers(protein) {
+		d := t.deviation()
+		total += t.k * d * d
+	}
+	return total
+}
+
+// addImproperForces adds -∇ImproperEnergy to the force map
+func addImproperForces(protein *parser.Protein, forces map[int]Vector3) {
+	for _, t := range enumerateImpropers(protein) {
+		dEdXi := 2 * t.k * t.deviation()
+		if dEdXi == 0 {
+			continue
+		}
+		grad := dihedralGradient(t.atoms)
+		for k, atom := range t.atoms {
+			forces[atom.Serial] = forces[atom.Serial].Add(grad[k].Mul(-dEdXi))
+		}
+	}
+}
+
+// enumerateImpropers lists the improper terms whose atoms are all present
+func enumerateImpropers(protein *parser.Protein) []improperTerm {
+	residues := protein.Residues
+	terms := make([]improperTerm, 0, 3*len(residues))
+	sidechains := residueSidechainAtoms(protein)
+
+	for i, res := range residues {
+		if res == nil {
+			continue
+		}
+
+		if i+1 < len(residues) && residues[i+1] != nil && residues[i+1].ChainID == res.ChainID {
+			next := residues[i+1]
+			// Carbonyl: CA and O on opposite sides of the N(i+1)-C line
+			if res.CA != nil && next.N != nil && res.C != nil && res.O != nil {
+				terms = append(terms, improperTerm{
+					atoms: [4]*parser.Atom{res.CA, next.N, res.C, res.O},
+					ideal: math.Pi, k: improperCarbonylK, residue: i,
+				})
+			}
+			// Peptide bond: ω planar, cis or trans
+			if res.CA != nil && res.C != nil && next.N != nil && next.CA != nil {
+				terms = append(terms, improperTerm{
+					atoms: [4]*parser.Atom{res.CA, res.C, next.N, next.CA},
+					k:     improperOmegaK, cisTrans: true, residue: i,
+				})
+			}
+		}
+
+		// Cα chirality, only with a real Cβ (Gly has none; a virtual Cβ
+		// is L by construction)
+		if cb := sidechains[i]["CB"]; cb != nil && !isGlycine(res.Name) &&
+			res.N != nil && res.C != nil && res.CA != nil {
+			terms = append(terms, improperTerm{
+				atoms: [4]*parser.Atom{res.N, res.C, res.CA, cb},
+				ideal: improperChiralityIdeal, k: improperChiralityK, residue: i,
+			})
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,81 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestImproperEnergy checks ideal geometry costs nothing, a pyramidalized
+// carbonyl and a D-flipped Cβ cost energy that vanishes when restored, and
+// the forces are the exact negative gradient
+func TestImproperEnergy(t *testing.T) {
+	chain := buildUniformChain(t, 6, -65, -40)
+
+	// Real Cβ atoms at the ideal L position
+	serial := len(chain.Atoms) + 1
+	for _, res := range chain.Residues {
+		pos, _ := VirtualCB(res)
+		chain.Atoms = append(chain.Atoms, &parser.Atom{
+			Serial: serial, Name: "CB", ResName: res.Name, ChainID: res.ChainID,
+			ResSeq: res.SeqNum, X: pos.X, Y: pos.Y, Z: pos.Z, Element: "C",
+		})
+		serial++
+	}
+
+	ideal := ImproperEnergy(chain)
+	t.Logf("Ideal chain: improper energy %.2e kcal/mol", ideal)
+	if ideal > 1e-6 {
+		t.Errorf("Ideal planar, L geometry has improper energy %.3e, want 0", ideal)
+	}
+
+	// Pyramidalize residue 2's carbonyl: O 0.3 Å out of the CA-C-N plane
+	res, next := chain.Residues[2], chain.Residues[3]
+	o := res.O
+	c := atomVector(res.C)
+	normal := crossVec(atomVector(res.CA).Sub(c), atomVector(next.N).Sub(c)).Normalize()
+	planar := atomVector(o)
+	o.X, o.Y, o.Z = planar.X+0.3*normal.X, planar.Y+0.3*normal.Y, planar.Z+0.3*normal.Z
+
+	config := DefaultEnergyConfig()
+	without := CalculateTotalEnergyWithConfig(chain, config)
+	config.UseImpropers = true
+	with := CalculateTotalEnergyWithConfig(chain, config)
+	t.Logf("Pyramidal carbonyl: improper %.3f kcal/mol", with.Improper)
+	if with.Improper <= 1.0 {
+		t.Errorf("Pyramidal carbonyl improper energy %.3f, want clearly positive", with.Improper)
+	}
+	if math.Abs((with.Total-without.Total)-with.Improper) > 1e-9 {
+		t.Errorf("UseImpropers added %.6f to the total, Improper is %.6f", with.Total-without.Total, with.Improper)
+	}
+
+	maxError := VerifyForcesWithConfig(chain, config)
+	t.Logf("Max force error with impropers: %.2e kcal/(mol·Å)", maxError)
+	if maxError > 1e-3 {
+		t.Errorf("Improper forces disagree with finite differences: %.2e", maxError)
+	}
+
+	o.X, o.Y, o.Z = planar.X, planar.Y, planar.Z
+	if e := ImproperEnergy(chain); e > 1e-6 {
+		t.Errorf("Planarized carbonyl still has improper energy %.3e", e)
+	}
+
+	// D flip: reflect residue 3's Cβ through its N-CA-C plane
+	var cb *parser.Atom
+	for _, atom := range chain.Atoms {
+		if atom.Name == "CB" && atom.ResSeq == next.SeqNum {
+			cb = atom
+		}
+	}
+	ca := atomVector(next.CA)
+	plane := crossVec(atomVector(next.N).Sub(ca), atomVector(next.C).Sub(ca)).Normalize()
+	original := atomVector(cb)
+	reflected := original.Sub(plane.Mul(2 * original.Sub(ca).Dot(plane)))
+	cb.X, cb.Y, cb.Z = reflected.X, reflected.Y, reflected.Z
+	flipped := ImproperEnergy(chain)
+	t.Logf("D-flipped Cβ: improper %.1f kcal/mol", flipped)
+	if flipped < 10 {
+		t.Errorf("D-flipped Cβ improper energy %.3f, want a large penalty", flipped)
+	}
+}
@@ -0,0 +1,285 @@
+// Package physics - Incremental energy for Monte Carlo
+//
+// A Monte Carlo step changes one residue's φ or ψ, yet CalculateTotalEnergy
+// re-sums all O(n²) non-bonded pairs. IncrementalEnergy keeps a running total
+// and, for a trial structure, re-evaluates only the pairs whose distance can
+// have changed, reading their old energies from a per-pair cache. Bonded,
+// torsion, CMAP, improper and restraint terms are O(n) and are recomputed in full;
+// they are not the bottleneck.
+//
+// PHYSICIST: A backbone dihedral move rotates everything downstream of the
+// bond as one rigid body, so pairs within the moved set keep their distance -
+// only moved × fixed pairs contribute to ΔE
+// MATHEMATICIAN: ΔE_nb = Σ_{i∈M, j∉M} [e_ij(trial) - e_ij(current)], plus
+// Σ_{i<j∈M} when the moved set is not rigid; cost O(|M|·n) instead of O(n²)
+// ETHICIST: Floating-point deltas accumulate rounding error - Resync reports
+// the drift against a full recomputation so callers can bound it
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// maxPairCacheAtoms bounds the per-pair energy cache (two triangular
+// float64 tables: ~32 MB at 2000 atoms); larger structures re-evaluate the
+// old pair energies instead
+const maxPairCacheAtoms = 2000
+
+// maxIncrementalPairEnergy is the largest pair energy (kcal/mol) added to or
+// removed from the running totals; a severe clash beyond it would leave its
+// round-off in every later total, so such moves are recalculated in full
+const maxIncrementalPairEnergy = 1e6
+
+// IncrementalEnergy tracks the energy of a structure across trial moves
+//
+// The tracked structure is held by reference and must not be modified in
+// place: propose each move as a new protein with the same atoms in the same
+// order, and Accept it to make it current.
+type IncrementalEnergy struct {
+	config     EnergyConfig // Units forced to kcal/mol; see units
+	units      EnergyUnits  // Unit of returned energies
+	protein    *parser.Protein
+	components EnergyComponents     // Uncapped running components
+	charges    []float64            // Partial charges of protein.Atoms
+	lj         []LennardJonesParams // LJ parameters of protein.Atoms
+
+	// Per-pair energies of protein (upper triangle, see pairIndex); nil
+	// above maxPairCacheAtoms
+	pairVdW  []float64
+	pairElec []float64
+
+	// Changes computed by the last Propose, applied by Accept
+	pendingProtein *parser.Protein
+	pendingPairs   []pairUpdate
+	pendingFull    bool
+}
+
+// pairUpdate is a new cached energy for one atom pair
+type pairUpdate struct {
+	index     int
+	vdw, elec float64
+}
+
+// NewIncrementalEnergy starts tracking protein with a full energy calculation
+func NewIncrementalEnergy(protein *parser.Protein, config EnergyConfig) *IncrementalEnergy {
+	e := &IncrementalEnergy{config: config, units: config.Units}
+	e.config.Units = KcalPerMol
+	e.reset(protein)
+	return e
+}
+
+// Energy returns the current components, with Total capped exactly as
+// CalculateTotalEnergyWithConfig caps it
+func (e *IncrementalEnergy) Energy() EnergyComponents {
+	return capped(e.components).InUnits(e.units)
+}
+
+// Protein returns the tracked structure
+func (e *IncrementalEnergy) Protein() *parser.Protein {
+	return e.protein
+}
+
+// Propose returns the energy of trial without changing the tracked state
+//
+// Atoms whose coordinates differ from the tracked structure form the moved
+// set. rigid asserts that the moved atoms moved together as one rigid body
+// (true for a backbone dihedral rebuild from an already rebuilt structure),
+// so pairs inside the moved set are skipped. Falls back to a full
+// calculation when the atom lists do not correspond or a non-rigid move
+// changed more than half the atoms.
+func (e *IncrementalEnergy) Propose(trial *parser.Protein, rigid bool) EnergyComponents {
+	return capped(e.propose(trial, rigid)).InUnits(e.units)
+}
+
+// Accept makes trial the tracked structure; components must be the value
+// Propose returned for it
+//
+// Accepting a structure other than the last one proposed falls back to a
+// full calculation.
+func (e *IncrementalEnergy) Accept(trial *parser.Protein, components EnergyComponents) {
+	if trial != e.pendingProtein || e.pendingFull {
+		e.reset(trial)
+		return
+	}
+
+	for _, u := range e.pendingPairs {
+		e.pairVdW[u.index] = u.vdw
+		e.pairElec[u.index] = u.elec
+	}
+	e.protein = trial
+	if f := e.units.FromKcal(); f != 1.0 {
+		components = components.scaled(1.0 / f)
+	}
+	components.Total = sumComponents(components)
+	e.components = components
+	e.clearPending()
+}
+
+// Resync replaces the running total with a full calculation and returns
+// the drift |E_incremental - E_full| of the uncapped total (in the
+// config's Units)
+func (e *IncrementalEnergy) Resync() float64 {
+	previous := e.components.Total
+	e.reset(e.protein)
+	return math.Abs(previous-e.components.Total) * e.units.FromKcal()
+}
+
+// reset tracks protein from a full calculation
+func (e *IncrementalEnergy) reset(protein *parser.Protein) {
+	e.protein = protein
+	e.components = uncappedEnergy(protein, e.config)
+	e.charges = partialCharges(protein)
+	e.lj = ljParameters(protein)
+	e.pairVdW, e.pairElec = nil, nil
+	e.clearPending()
+
+	n := len(protein.Atoms)
+	if n > maxPairCacheAtoms {
+		return
+	}
+	e.pairVdW = make([]float64, n*(n-1)/2)
+	e.pairElec = make([]float64, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			k := pairIndex(n, i, j)
+			e.pairVdW[k], e.pairElec[k] = nonbondedPairEnergy(protein.Atoms[i], protein.Atoms[j], e.charges[i], e.charges[j], e.lj[i], e.lj[j], e.config)
+		}
+	}
+}
+
+// clearPending forgets the last proposal
+func (e *IncrementalEnergy) clearPending() {
+	e.pendingProtein = nil
+	e.pendingPairs = e.pendingPairs[:0]
+	e.pendingFull = false
+}
+
+// propose computes the uncapped components of trial
+func (e *IncrementalEnergy) propose(trial *parser.Protein, rigid bool) EnergyComponents {
+	e.clearPending()
+	e.pendingProtein = trial
+
+	current := e.protein
+	if trial == nil || current == nil || len(trial.Atoms) != len(current.Atoms) {
+		e.pendingFull = true
+		return uncappedEnergy(trial, e.config)
+	}
+
+	var moved []int
+	for i, a := range trial.Atoms {
+		b := current.Atoms[i]
+		if a.Name != b.Name || a.ResSeq != b.ResSeq {
+			e.pendingFull = true
+			return uncappedEnergy(trial, e.config)
+		}
+		if a.X != b.X || a.Y != b.Y || a.Z != b.Z {
+			moved = append(moved, i)
+		}
+	}
+
+	n := len(trial.Atoms)
+	if !rigid && 2*len(moved) > n {
+		e.pendingFull = true
+		return uncappedEnergy(trial, e.config)
+	}
+
+	components := e.components
+	components.Bond = calculateBondEnergyTotal(trial)
+	components.Angle = calculateAngleEnergyTotal(trial)
+	components.Dihedral = TorsionEnergy(trial)
+	if e.config.UseCMAP {
+		components.CMAP = CMAPEnergy(trial)
+	}
+	if e.config.UseHBonds {
+		components.HBond = HBondEnergy(trial)
+	}
+	if e.config.UseImpropers {
+		components.Improper = ImproperEnergy(trial)
+	}
+	if len(e.config.ContactRestraints) > 0 {
+		components.Contact = contactRestraintTotal(trial, e.config.ContactRestraints, e.config.contactForceConstant(), nil)
+	}
+	if e.config.hasRestraints() {
+		components.Restraint = restraintTotal(trial, e.config, nil)
+	}
+
+	inMoved := make([]bool, n)
+	for _, i := range moved {
+		inMoved[i] = true
+	}
+
+	for _, i := range moved {
+		for j := 0; j < n; j++ {
+			if j == i || (inMoved[j] && (rigid || j < i)) {
+				continue
+			}
+
+			vdwNew, elecNew := nonbondedPairEnergy(trial.Atoms[i], trial.Atoms[j], e.charges[i], e.charges[j], e.lj[i], e.lj[j], e.config)
+			var vdwOld, elecOld float64
+			if e.pairVdW != nil {
+				k := pairIndex(n, i, j)
+				vdwOld, elecOld = e.pairVdW[k], e.pairElec[k]
+				e.pendingPairs = append(e.pendingPairs, pairUpdate{index: k, vdw: vdwNew, elec: elecNew})
+			} else {
+				vdwOld, elecOld = nonbondedPairEnergy(current.Atoms[i], current.Atoms[j], e.charges[i], e.charges[j], e.lj[i], e.lj[j], e.config)
+			}
+
+			if math.Abs(vdwNew) > maxIncrementalPairEnergy || math.Abs(vdwOld) > maxIncrementalPairEnergy {
+				e.clearPending()
+				e.pendingProtein = trial
+				e.pendingFull = true
+				return uncappedEnergy(trial, e.config)
+			}
+
+			components.VanDerWaals += vdwNew - vdwOld
+			components.Electrostatic += elecNew - elecOld
+		}
+	}
+
+	components.Total = sumComponents(components)
+	return components
+}
+
+// pairIndex maps atom pair (i, j), i ≠ j, to its upper-triangle index
+func pairIndex(n, i, j int) int {
+	if i > j {
+		i, j = j, i
+	}
+	return i*n - i*(i+1)/2 + j - i - 1
+}
+
+// nonbondedPairEnergy returns the VdW and electrostatic energy of one pair
+// with partial charges qa, qb and LJ parameters lja, ljb, with the
+// exclusions of calculateVanDerWaalsTotal and calculateElectrostaticTotal
+func nonbondedPairEnergy(a, b *parser.Atom, qa, qb float64, lja, ljb LennardJonesParams, config EnergyConfig) (vdw, elec float64) {
+	if math.Abs(float64(a.ResSeq-b.ResSeq)) <= 1 {
+		return 0, 0
+	}
+
+	vdw = lennardJonesPairEnergy(a, b, lja, ljb, config.VdWSwitchStart, config.VdWCutoff)
+
+	if qa != 0 && qb != 0 {
+		elec = electrostaticPairEnergy(a, b, qa, qb, config.ElecSwitchStart, config.ElecCutoff)
+	}
+	return vdw, elec
+}
+
+// uncappedEnergy is CalculateTotalEnergyWithConfig without the ±10000 cap
+func uncappedEnergy(protein *parser.Protein, config EnergyConfig) EnergyComponents {
+	components := CalculateTotalEnergyWithConfig(protein, config)
+	components.Total = sumComponents(components)
+	return components
+}
+
+// sumComponents adds the terms CalculateTotalEnergyWithConfig sums
+func sumComponents(c EnergyComponents) float64 {
+	return c.Bond + c.Angle + c.Dihedral + c.VanDerWaals + c.Electrostatic + c.CMAP + c.HBond + c.Contact + c.Restraint + c.Improper
+}
+
+// capped applies the ±10000 kcal/mol cap of CalculateTotalEnergyWithConfig
+func capped(c EnergyComponents) EnergyComponents {
+	c.Total = math.Max(-TotalEnergyCap, math.Min(TotalEnergyCap, c.Total))
+	return c
+}
@@ -0,0 +1,160 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// dihedralMover proposes Monte Carlo style backbone moves: one residue's
+// (φ, ψ) perturbed, then the chain rebuilt
+type dihedralMover struct {
+	t        testing.TB
+	rng      *rand.Rand
+	sequence string
+	angles   []geometry.RamachandranAngles
+	trial    []geometry.RamachandranAngles
+}
+
+func newDihedralMover(t testing.TB, numResidues int) *dihedralMover {
+	m := &dihedralMover{
+		t:        t,
+		rng:      rand.New(rand.NewSource(7)),
+		sequence: strings.Repeat("A", numResidues),
+		angles:   make([]geometry.RamachandranAngles, numResidues),
+	}
+	for i := range m.angles {
+		m.angles[i] = geometry.RamachandranAngles{Phi: -60 * math.Pi / 180, Psi: -45 * math.Pi / 180}
+	}
+	return m
+}
+
+// build returns the structure for angles
+func (m *dihedralMover) build(angles []geometry.RamachandranAngles) *parser.Protein {
+	m.t.Helper()
+	protein, err := geometry.BuildProteinFromAngles(m.sequence, angles)
+	if err != nil {
+		m.t.Fatalf("build failed: %v", err)
+	}
+	return protein
+}
+
+// propose returns the current structure with one residue moved
+func (m *dihedralMover) propose() *parser.Protein {
+	m.trial = append(m.trial[:0], m.angles...)
+	k := m.rng.Intn(len(m.trial))
+	m.trial[k].Phi += m.rng.NormFloat64() * 0.3
+	m.trial[k].Psi += m.rng.NormFloat64() * 0.3
+	return m.build(m.trial)
+}
+
+// accept makes the last proposal current
+func (m *dihedralMover) accept() {
+	m.angles, m.trial = m.trial, m.angles
+}
+
+func TestIncrementalEnergyDihedralMoves(t *testing.T) {
+	const steps = 500
+	mover := newDihedralMover(t, 30)
+	config := DefaultEnergyConfig()
+	rng := rand.New(rand.NewSource(11))
+
+	inc := NewIncrementalEnergy(mover.build(mover.angles), config)
+	maxDrift := 0.0
+	for step := 0; step < steps; step++ {
+		trial := mover.propose()
+		proposed := inc.Propose(trial, true)
+		full := CalculateTotalEnergyWithConfig(trial, config)
+		if math.Abs(proposed.Total-full.Total) > 1e-4 {
+			t.Fatalf("step %d: proposed %.8f, full %.8f", step, proposed.Total, full.Total)
+		}
+
+		// Accept most moves; a rejected move leaves the state untouched
+		if rng.Float64() < 0.8 {
+			inc.Accept(trial, proposed)
+			mover.accept()
+		}
+
+		if (step+1)%50 == 0 {
+			drift := inc.Resync()
+			maxDrift = math.Max(maxDrift, drift)
+			if drift > 1e-4 {
+				t.Errorf("step %d: drift %.2e kcal/mol exceeds 1e-4", step, drift)
+			}
+		}
+	}
+
+	t.Logf("Max drift over %d moves: %.2e kcal/mol", steps, maxDrift)
+}
+
+func TestIncrementalEnergyNonRigidMove(t *testing.T) {
+	mover := newDihedralMover(t, 20)
+	config := DefaultEnergyConfig()
+	config.UseCMAP = true
+
+	current := mover.build(mover.angles)
+	inc := NewIncrementalEnergy(current, config)
+
+	// Move two atoms independently: pairs between them change too
+	trial := current.Copy()
+	trial.Atoms[10].X += 0.7
+	trial.Atoms[50].Y -= 0.4
+
+	proposed := inc.Propose(trial, false)
+	full := CalculateTotalEnergyWithConfig(trial, config)
+	if math.Abs(proposed.Total-full.Total) > 1e-6 {
+		t.Errorf("proposed %.8f, full %.8f", proposed.Total, full.Total)
+	}
+	if math.Abs(proposed.VanDerWaals-full.VanDerWaals) > 1e-6 ||
+		math.Abs(proposed.Electrostatic-full.Electrostatic) > 1e-6 {
+		t.Errorf("non-bonded terms differ: VdW %.6f/%.6f, elec %.6f/%.6f",
+			proposed.VanDerWaals, full.VanDerWaals, proposed.Electrostatic, full.Electrostatic)
+	}
+
+	// Proposing does not change the tracked energy
+	if got, want := inc.Energy().Total, CalculateTotalEnergyWithConfig(current, config).Total; math.Abs(got-want) > 1e-9 {
+		t.Errorf("tracked energy changed by Propose: %.8f, want %.8f", got, want)
+	}
+}
+
+// benchmarkTrajectory returns 1001 structures of a 40-residue chain, each
+// one accepted dihedral move after the previous
+func benchmarkTrajectory(b *testing.B) []*parser.Protein {
+	mover := newDihedralMover(b, 40)
+	trajectory := []*parser.Protein{mover.build(mover.angles)}
+	for step := 0; step < 1000; step++ {
+		trajectory = append(trajectory, mover.propose())
+		mover.accept()
+	}
+	return trajectory
+}
+
+func BenchmarkIncrementalEnergy1000Steps(b *testing.B) {
+	trajectory := benchmarkTrajectory(b)
+	config := DefaultEnergyConfig()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		inc := NewIncrementalEnergy(trajectory[0], config)
+		for _, trial := range trajectory[1:] {
+			inc.Accept(trial, inc.Propose(trial, true))
+		}
+	}
+}
+
+func BenchmarkFullEnergy1000Steps(b *testing.B) {
+	trajectory := benchmarkTrajectory(b)
+	config := DefaultEnergyConfig()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		CalculateTotalEnergyWithConfig(trajectory[0], config)
+		for _, trial := range trajectory[1:] {
+			CalculateTotalEnergyWithConfig(trial, config)
+		}
+	}
+}
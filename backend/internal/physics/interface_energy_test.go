@@ -0,0 +1,60 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// twoLayerComplex returns two chains, A and B, each a 3×3 square layer of
+// uncharged carbons (no force-field atom name: element parameters, no
+// charge) 4 Å apart, with layer B gap Å above layer A
+func twoLayerComplex(gap float64) *parser.Protein {
+	protein := &parser.Protein{}
+	for c, chain := range []string{"A", "B"} {
+		for k := 0; k < 9; k++ {
+			protein.Atoms = append(protein.Atoms, &parser.Atom{
+				Serial: len(protein.Atoms) + 1, Name: "CX", Element: "C", ResName: "UNK",
+				ChainID: chain, ResSeq: 1,
+				X: 4 * float64(k%3), Y: 4 * float64(k/3), Z: gap * float64(c),
+			})
+		}
+	}
+	return protein
+}
+
+// TestInterfaceEnergy compares two chains far apart, which do not
+// interact, with the same chains in van der Waals contact
+func TestInterfaceEnergy(t *testing.T) {
+	apart, err := CalculateInterfaceEnergy(twoLayerComplex(100), "A", "B")
+	if err != nil {
+		t.Fatalf("CalculateInterfaceEnergy failed: %v", err)
+	}
+	if apart.Total != 0 || apart.AtomContacts != 0 || math.Abs(apart.BuriedSurfaceArea) > 1e-6 {
+		t.Errorf("Chains 100 Å apart: E = %.4f kcal/mol, %d contacts, BSA %.2f Ų, want all 0",
+			apart.Total, apart.AtomContacts, apart.BuriedSurfaceArea)
+	}
+
+	// Same residue number in both chains: no exclusion applies between them
+	contact, err := CalculateInterfaceEnergy(twoLayerComplex(4), "A", "B")
+	if err != nil {
+		t.Fatalf("CalculateInterfaceEnergy failed: %v", err)
+	}
+	t.Logf("In contact: VdW %.3f + elec %.3f = %.3f kcal/mol, %d contacts, BSA %.0f Ų",
+		contact.VanDerWaals, contact.Electrostatic, contact.Total, contact.AtomContacts, contact.BuriedSurfaceArea)
+
+	if contact.Total >= 0 {
+		t.Errorf("Interface energy in contact is %.3f kcal/mol, want negative", contact.Total)
+	}
+	if contact.AtomContacts != 9 {
+		t.Errorf("%d atom contacts, want the 9 stacked pairs", contact.AtomContacts)
+	}
+	if contact.BuriedSurfaceArea <= 0 {
+		t.Errorf("Contact buried %.1f Ų", contact.BuriedSurfaceArea)
+	}
+
+	if _, err := CalculateInterfaceEnergy(twoLayerComplex(4), "A", "C"); err == nil {
+		t.Error("Accepted a chain with no atoms")
+	}
+}
@@ -35,6 +35,9 @@ import (
 // - protein: Protein structure with atomic coordinates
 //
 // Returns: Total Ramachandran energy in kcal/mol
+//
+// Not part of CalculateTotalEnergy (see TorsionEnergy); kept as a
+// knowledge-based Ramachandran score.
 func RamachandranPotential(protein *parser.Protein) float64 {
 	totalEnergy := 0.0
 
@@ -64,6 +67,51 @@ func RamachandranPotential(protein *parser.Protein) float64 {
 	return totalEnergy
 }
 
+// dihedralGradient returns ∂θ/∂r for the four atoms of dihedral θ(a, b, c, d)
+//
+// θ follows the IUPAC sign convention, as measured by torsionAngle.
+//
+// Citation: Blondel, A., & Karplus, M. (1996). "New formulation for derivatives of
+// torsion angles and improper torsion angles in molecular mechanics."
+// J. Comput. Chem. 17(9): 1132-1141.
+func dihedralGradient(atoms [4]*parser.Atom) [4]Vector3 {
+	pos := func(a *parser.Atom) Vector3 { return Vector3{X: a.X, Y: a.Y, Z: a.Z} }
+	p1, p2, p3, p4 := pos(atoms[0]), pos(atoms[1]), pos(atoms[2]), pos(atoms[3])
+
+	f := p1.Sub(p2)
+	g := p2.Sub(p3)
+	h := p4.Sub(p3)
+
+	a := crossVec(f, g)
+	b := crossVec(h, g)
+	aa := a.Dot(a)
+	bb := b.Dot(b)
+	gLen := g.Magnitude()
+	if aa < 1e-12 || bb < 1e-12 || gLen < 1e-12 {
+		return [4]Vector3{} // Collinear: dihedral undefined
+	}
+
+	d1 := a.Mul(-gLen / aa)
+	d4 := b.Mul(gLen / bb)
+
+	fg := f.Dot(g) / (aa * gLen)
+	hg := h.Dot(g) / (bb * gLen)
+
+	d2 := a.Mul(gLen/aa + fg).Sub(b.Mul(hg))
+	d3 := b.Mul(hg - gLen/bb).Sub(a.Mul(fg))
+
+	return [4]Vector3{d1, d2, d3, d4}
+}
+
+// crossVec computes the cross product a × b
+func crossVec(a, b Vector3) Vector3 {
+	return Vector3{
+		X: a.Y*b.Z - a.Z*b.Y,
+		Y: a.Z*b.X - a.X*b.Z,
+		Z: a.X*b.Y - a.Y*b.X,
+	}
+}
+
 // ramachandranEnergy returns energy penalty for given (φ, ψ) angles
 //
 // BIOCHEMIST:
@@ -123,31 +171,41 @@ func ramachandranEnergy(phi, psi float64, residueName string) float64 {
 // Citation: Lovell, S. C., et al. (2003). "Structure validation by Cα geometry:
 // φ,ψ and Cβ deviation." Proteins 50.3: 437-450.
 func generalRamachandran(phi, psi float64) float64 {
+	// Take minimum energy (most favorable region)
+	// MATHEMATICIAN: min() ensures we don't double-penalize angles between regions
+	minE, _ := nearestWell(phi, psi, generalWells)
+
+	// Scale to kcal/mol (max penalty ~15 kcal/mol for severely forbidden regions)
+	// PHYSICIST: Empirically calibrated to match MD simulation force fields
+	return minE * generalRamachandranScale
+}
+
+// ramachandranWell is one allowed region: center (φ₀, ψ₀) and widths (σ_φ, σ_ψ) in degrees
+type ramachandranWell struct {
+	Phi0, Psi0     float64
+	SigPhi, SigPsi float64
+}
+
+// generalRamachandranScale: max penalty for standard amino acids (kcal/mol)
+const generalRamachandranScale = 15.0
+
+// generalWells are the allowed regions for standard amino acids
+var generalWells = []ramachandranWell{
 	// α-helix region: φ = -60°, ψ = -45°
 	// Most favorable region for helical secondary structure
-	alphaE := gaussianPotential(phi, psi, -60, -45, 30, 30)
+	{Phi0: -60, Psi0: -45, SigPhi: 30, SigPsi: 30},
 
 	// β-sheet region: φ = -120°, ψ = +120°
 	// Favored by extended backbone conformations
-	betaE := gaussianPotential(phi, psi, -120, 120, 40, 50)
+	{Phi0: -120, Psi0: 120, SigPhi: 40, SigPsi: 50},
 
 	// Left-handed helix: φ = +60°, ψ = +45°
 	// Rare but allowed (mainly in short peptides)
-	leftE := gaussianPotential(phi, psi, 60, 45, 25, 25)
+	{Phi0: 60, Psi0: 45, SigPhi: 25, SigPsi: 25},
 
 	// PPII helix region: φ = -75°, ψ = +145°
 	// Common in loops and unstructured regions
-	ppiiE := gaussianPotential(phi, psi, -75, 145, 30, 30)
-
-	// Take minimum energy (most favorable region)
-	// MATHEMATICIAN: min() ensures we don't double-penalize angles between regions
-	minE := math.Min(alphaE, betaE)
-	minE = math.Min(minE, leftE)
-	minE = math.Min(minE, ppiiE)
-
-	// Scale to kcal/mol (max penalty ~15 kcal/mol for severely forbidden regions)
-	// PHYSICIST: Empirically calibrated to match MD simulation force fields
-	return minE * 15.0
+	{Phi0: -75, Psi0: 145, SigPhi: 30, SigPsi: 30},
 }
 
 // glycineRamachandran calculates energy for glycine (more permissive)
@@ -163,26 +221,29 @@ func generalRamachandran(phi, psi float64) float64 {
 func glycineRamachandran(phi, psi float64) float64 {
 	// Glycine allows much broader regions due to lack of steric clashes
 	// Use larger standard deviations (50-70° vs 20-40° for general amino acids)
+	minE, _ := nearestWell(phi, psi, glycineWells)
 
+	// Lower penalty for glycine (5 vs 15 kcal/mol for general amino acids)
+	// PHYSICIST: Reflects reduced steric strain due to lack of Cβ
+	return minE * glycineRamachandranScale
+}
+
+// glycineRamachandranScale: max penalty for glycine (kcal/mol)
+const glycineRamachandranScale = 5.0
+
+// glycineWells are the broader allowed regions for glycine
+var glycineWells = []ramachandranWell{
 	// α-helix region (still favorable)
-	alphaE := gaussianPotential(phi, psi, -60, -45, 50, 50)
+	{Phi0: -60, Psi0: -45, SigPhi: 50, SigPsi: 50},
 
 	// β-sheet region (slightly broader)
-	betaE := gaussianPotential(phi, psi, -120, 120, 60, 70)
+	{Phi0: -120, Psi0: 120, SigPhi: 60, SigPsi: 70},
 
 	// Left-handed helix (much more favorable for glycine)
-	leftE := gaussianPotential(phi, psi, 60, 45, 50, 50)
+	{Phi0: 60, Psi0: 45, SigPhi: 50, SigPsi: 50},
 
 	// PPII region
-	ppiiE := gaussianPotential(phi, psi, -75, 145, 50, 50)
-
-	minE := math.Min(alphaE, betaE)
-	minE = math.Min(minE, leftE)
-	minE = math.Min(minE, ppiiE)
-
-	// Lower penalty for glycine (5 vs 15 kcal/mol for general amino acids)
-	// PHYSICIST: Reflects reduced steric strain due to lack of Cβ
-	return minE * 5.0
+	{Phi0: -75, Psi0: 145, SigPhi: 50, SigPsi: 50},
 }
 
 // prolineRamachandran calculates energy for proline (more restrictive)
@@ -199,19 +260,36 @@ func glycineRamachandran(phi, psi float64) float64 {
 func prolineRamachandran(phi, psi float64) float64 {
 	// Proline φ constrained to ~-60° by pyrrolidine ring
 	// ψ can vary more (typically around -30° for helix-like, +150° for PPII)
+	minE, _ := nearestWell(phi, psi, prolineWells)
+
+	// Higher penalty for proline (20 vs 15 kcal/mol for general amino acids)
+	// PHYSICIST: Ring constraint makes violations more energetically costly
+	return minE * prolineRamachandranScale
+}
+
+// prolineRamachandranScale: max penalty for proline (kcal/mol)
+const prolineRamachandranScale = 20.0
 
+// prolineWells are the allowed regions for proline
+var prolineWells = []ramachandranWell{
 	// Helix-like region: φ = -60°, ψ = -30°
-	helixE := gaussianPotential(phi, psi, -60, -30, 20, 40)
+	{Phi0: -60, Psi0: -30, SigPhi: 20, SigPsi: 40},
 
 	// PPII region: φ = -60°, ψ = +145°
 	// Most common conformation for proline
-	ppiiE := gaussianPotential(phi, psi, -60, 145, 20, 30)
-
-	minE := math.Min(helixE, ppiiE)
+	{Phi0: -60, Psi0: 145, SigPhi: 20, SigPsi: 30},
+}
 
-	// Higher penalty for proline (20 vs 15 kcal/mol for general amino acids)
-	// PHYSICIST: Ring constraint makes violations more energetically costly
-	return minE * 20.0
+// nearestWell returns the minimum 1 - G over wells and the index of that well
+func nearestWell(phi, psi float64, wells []ramachandranWell) (float64, int) {
+	minE, best := math.Inf(1), -1
+	for i, w := range wells {
+		e := gaussianPotential(phi, psi, w.Phi0, w.Psi0, w.SigPhi, w.SigPsi)
+		if e < minE {
+			minE, best = e, i
+		}
+	}
+	return minE, best
 }
 
 // gaussianPotential calculates 2D Gaussian energy function with periodic boundary handling
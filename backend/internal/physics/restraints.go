@@ -0,0 +1,202 @@
+// Package physics - User distance and dihedral restraints
+//
+// Experimental data often pins down part of a structure before it is
+// folded: NOE distances, crosslinks, a known disulfide geometry, dihedrals
+// from J-couplings or chemical shifts. DistanceRestraint and
+// DihedralRestraint bring such data into the energy function
+// (EnergyConfig.DistanceRestraints, EnergyConfig.DihedralRestraints), so
+// minimizers follow their gradient like any other term.
+//
+// BIOCHEMIST: NOE-derived restraints are bounds, not exact distances, so
+// both terms are flat-bottomed: anything within Target ± Tolerance costs
+// nothing
+// PHYSICIST: E = w·x², x the violation beyond the tolerance; F = -2w·x·∂x/∂r
+// MATHEMATICIAN: Dihedral deviations are wrapped to [-π, π] before the
+// tolerance applies, so -179° and +179° are 2° apart
+//
+// CITATION:
+// Nilges, M., Clore, G. M., & Gronenborn, A. M. (1988). "Determination of
+// three-dimensional structures of proteins from interproton distance data
+// by hybrid distance geometry-dynamical simulated annealing calculations."
+// FEBS Lett. 229(2): 317-324.
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// DistanceRestraint holds two atoms within Tolerance of a target distance
+type DistanceRestraint struct {
+	Residue1  int     // Index into protein.Residues (0-indexed)
+	Residue2  int     // Index into protein.Residues (0-indexed)
+	Atom1     string  // Atom name in Residue1 ("" means CA)
+	Atom2     string  // Atom name in Residue2 ("" means CA)
+	Target    float64 // Target distance (Å)
+	Tolerance float64 // Half-width of the zero-energy window (Å)
+	Weight    float64 // Force constant, kcal/(mol·Å²)
+}
+
+// DihedralRestraint holds a backbone φ or ψ within Tolerance of a target
+type DihedralRestraint struct {
+	Residue   int     // Index into protein.Residues (0-indexed)
+	Angle     string  // "phi" or "psi"
+	Target    float64 // Target angle (radians)
+	Tolerance float64 // Half-width of the zero-energy window (radians)
+	Weight    float64 // Force constant, kcal/(mol·rad²)
+}
+
+// RestraintEnergy returns the energy of the distance and dihedral
+// restraints in kcal/mol and the resulting forces, keyed by atom Serial
+// like CalculateForces
+//
+// Restraints naming a residue outside the protein or an atom it does not
+// have (including φ of the first and ψ of the last residue) are skipped.
+func RestraintEnergy(protein *parser.Protein, distances []DistanceRestraint, dihedrals []DihedralRestraint) (float64, map[int]Vector3) {
+	forces := make(map[int]Vector3)
+	energy := distanceRestraintTotal(protein, distances, forces) + dihedralRestraintTotal(protein, dihedrals, forces)
+	return energy, forces
+}
+
+// hasRestraints reports whether config carries distance or dihedral restraints
+func (config EnergyConfig) hasRestraints() bool {
+	return len(config.DistanceRestraints) > 0 || len(config.DihedralRestraints) > 0
+}
+
+// restraintTotal sums config's distance and dihedral restraint energy and,
+// if forces is not nil, adds their forces to it
+func restraintTotal(protein *parser.Protein, config EnergyConfig, forces map[int]Vector3) float64 {
+	return distanceRestraintTotal(protein, config.DistanceRestraints, forces) +
+		dihedralRestraintTotal(protein, config.DihedralRestraints, forces)
+}
+
+// distanceRestraintTotal sums the flat-bottom distance restraint energy and,
+// if forces is not nil, adds the restraint forces to it
+func distanceRestraintTotal(protein *parser.Protein, restraints []DistanceRestraint, forces map[int]Vector3) float64 {
+	if protein == nil {
+		return 0
+	}
+
+	total := 0.0
+	for _, restraint := range restraints {
+		a1 := restraintAtom(protein, restraint.Residue1, restraint.Atom1)
+		a2 := restraintAtom(protein, restraint.Residue2, restraint.Atom2)
+		if a1 == nil || a2 == nil || a1 == a2 {
+			continue
+		}
+
+		dx := a2.X - a1.X
+		dy := a2.Y - a1.Y
+		dz := a2.Z - a1.Z
+		d := math.Sqrt(dx*dx + dy*dy + dz*dz)
+
+		violation := flatBottomViolation(d-restraint.Target, restraint.Tolerance)
+		if violation == 0 {
+			continue
+		}
+		total += restraint.Weight * violation * violation
+
+		if forces == nil || d == 0 {
+			continue
+		}
+		// Force on a2 = -dE/dd · r̂, r̂ pointing from a1 to a2
+		f := Vector3{X: dx / d, Y: dy / d, Z: dz / d}.Mul(-2 * restraint.Weight * violation)
+		forces[a2.Serial] = forces[a2.Serial].Add(f)
+		forces[a1.Serial] = forces[a1.Serial].Sub(f)
+	}
+
+	return total
+}
+
+// dihedralRestraintTotal sums the flat-bottom φ/ψ restraint energy and, if
+// forces is not nil, adds the restraint forces to it
+func dihedralRestraintTotal(protein *parser.Protein, restraints []DihedralRestraint, forces map[int]Vector3) float64 {
+	if protein == nil {
+		return 0
+	}
+
+	total := 0.0
+	for _, restraint := range restraints {
+		atoms, ok := restraintDihedralAtoms(protein, restraint.Residue, restraint.Angle)
+		if !ok {
+			continue
+		}
+
+		delta := math.Remainder(torsionAngle(atoms)-restraint.Target, 2*math.Pi)
+		violation := flatBottomViolation(delta, restraint.Tolerance)
+		if violation == 0 {
+			continue
+		}
+		total += restraint.Weight * violation * violation
+
+		if forces == nil {
+			continue
+		}
+		grad := dihedralGradient(atoms)
+		dEdTheta := 2 * restraint.Weight * violation
+		for k, atom := range atoms {
+			forces[atom.Serial] = forces[atom.Serial].Sub(grad[k].Mul(dEdTheta))
+		}
+	}
+
+	return total
+}
+
+// flatBottomViolation returns how far deviation lies outside ±tolerance,
+// signed like deviation, or 0 inside the window
+func flatBottomViolation(deviation, tolerance float64) float64 {
+	tolerance = math.Abs(tolerance)
+	switch {
+	case deviation > tolerance:
+		return deviation - tolerance
+	case deviation < -tolerance:
+		return deviation + tolerance
+	}
+	return 0
+}
+
+// restraintAtom returns the atom named name ("" for CA) of residue index
+// i, or nil
+func restraintAtom(protein *parser.Protein, i int, name string) *parser.Atom {
+	if i < 0 || i >= len(protein.Residues) || protein.Residues[i] == nil {
+		return nil
+	}
+	res := protein.Residues[i]
+	switch name {
+	case "", "CA":
+		return res.CA
+	case "N":
+		return res.N
+	case "C":
+		return res.C
+	case "O":
+		return res.O
+	}
+	for _, atom := range protein.Atoms {
+		if atom.Name == name && atom.ResSeq == res.SeqNum && atom.ChainID == res.ChainID && atom.ICode == res.ICode {
+			return atom
+		}
+	}
+	return nil
+}
+
+// restraintDihedralAtoms returns the four backbone atoms of φ (C(i-1), N,
+// CA, C) or ψ (N, CA, C, N(i+1)) of residue index i
+func restraintDihedralAtoms(protein *parser.Protein, i int, angle string) ([4]*parser.Atom, bool) {
+	var atoms [4]*parser.Atom
+	switch angle {
+	case "phi":
+		atoms = [4]*parser.Atom{restraintAtom(protein, i-1, "C"), restraintAtom(protein, i, "N"), restraintAtom(protein, i, "CA"), restraintAtom(protein, i, "C")}
+	case "psi":
+		atoms = [4]*parser.Atom{restraintAtom(protein, i, "N"), restraintAtom(protein, i, "CA"), restraintAtom(protein, i, "C"), restraintAtom(protein, i+1, "N")}
+	default:
+		return atoms, false
+	}
+	for _, atom := range atoms {
+		if atom == nil {
+			return atoms, false
+		}
+	}
+	return atoms, true
+}
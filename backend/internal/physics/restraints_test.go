@@ -0,0 +1,91 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// TestRestraintEnergy checks the flat bottom and the restraint forces
+// against finite differences of the restraint energy
+func TestRestraintEnergy(t *testing.T) {
+	protein := buildAlanineDipeptide(-70, 140)
+	deg := math.Pi / 180
+
+	// Satisfied restraints cost nothing
+	ca0, ca2 := restraintAtom(protein, 0, "CA"), restraintAtom(protein, 2, "CA")
+	dist := math.Sqrt(math.Pow(ca2.X-ca0.X, 2) + math.Pow(ca2.Y-ca0.Y, 2) + math.Pow(ca2.Z-ca0.Z, 2))
+	satisfied := []DistanceRestraint{{Residue1: 0, Residue2: 2, Target: dist + 0.3, Tolerance: 0.5, Weight: 10}}
+	phiSatisfied := []DihedralRestraint{{Residue: 1, Angle: "phi", Target: -75 * deg, Tolerance: 10 * deg, Weight: 100}}
+	if e, _ := RestraintEnergy(protein, satisfied, phiSatisfied); e != 0 {
+		t.Errorf("Satisfied restraints: energy %.4f, want 0", e)
+	}
+
+	// Unknown atoms, missing neighbours and unknown angles are skipped
+	skipped := []DistanceRestraint{{Residue1: 0, Residue2: 7, Target: 1, Weight: 10}, {Residue1: 0, Residue2: 2, Atom2: "ZZ", Target: 1, Weight: 10}}
+	skippedDihedral := []DihedralRestraint{{Residue: 0, Angle: "phi", Weight: 100}, {Residue: 1, Angle: "omega", Weight: 100}}
+	if e, _ := RestraintEnergy(protein, skipped, skippedDihedral); e != 0 {
+		t.Errorf("Unresolvable restraints: energy %.4f, want 0", e)
+	}
+
+	distances := []DistanceRestraint{
+		{Residue1: 0, Residue2: 2, Target: 3.0, Tolerance: 0.2, Weight: 10},
+		{Residue1: 0, Residue2: 2, Atom1: "O", Atom2: "N", Target: 9.0, Tolerance: 0.5, Weight: 5},
+		{Residue1: 1, Residue2: 2, Atom1: "CB", Target: 2.0, Weight: 8},
+	}
+	dihedrals := []DihedralRestraint{
+		{Residue: 1, Angle: "phi", Target: -120 * deg, Tolerance: 5 * deg, Weight: 100},
+		{Residue: 1, Angle: "psi", Target: -170 * deg, Tolerance: 5 * deg, Weight: 50}, // Across ±180°
+	}
+
+	energy, forces := RestraintEnergy(protein, distances, dihedrals)
+	if energy <= 0 {
+		t.Fatalf("Violated restraints: energy %.4f, want > 0", energy)
+	}
+
+	config := DefaultEnergyConfig()
+	config.DistanceRestraints, config.DihedralRestraints = distances, dihedrals
+	if c := CalculateTotalEnergyWithConfig(protein, config); math.Abs(c.Restraint-energy) > 1e-9 {
+		t.Errorf("EnergyComponents.Restraint = %.6f, want %.6f", c.Restraint, energy)
+	}
+
+	restraintE := func() float64 {
+		e, _ := RestraintEnergy(protein, distances, dihedrals)
+		return e
+	}
+	maxError, maxForce := 0.0, 0.0
+	var net Vector3
+	for _, atom := range protein.Atoms {
+		analytical := forces[atom.Serial]
+		net = net.Add(analytical)
+		for k, coord := range []*float64{&atom.X, &atom.Y, &atom.Z} {
+			orig := *coord
+			*coord = orig + forceCheckStep
+			ePlus := restraintE()
+			*coord = orig - forceCheckStep
+			eMinus := restraintE()
+			*coord = orig
+
+			numerical := -(ePlus - eMinus) / (2 * forceCheckStep)
+			component := []float64{analytical.X, analytical.Y, analytical.Z}[k]
+			maxError = math.Max(maxError, math.Abs(component-numerical))
+			maxForce = math.Max(maxForce, math.Abs(numerical))
+		}
+	}
+
+	t.Logf("Restraint E=%.3f kcal/mol, max |F|=%.3f, max error=%.2e, net |F|=%.1e", energy, maxForce, maxError, net.Magnitude())
+	if maxError > 1e-4 {
+		t.Errorf("Max restraint force error %.2e exceeds 1e-4 kcal/(mol·Å)", maxError)
+	}
+	if net.Magnitude() > 1e-8 {
+		t.Errorf("Restraint forces sum to %.2e, want 0", net.Magnitude())
+	}
+
+	// The decomposition assigns all of it to residues
+	sum := 0.0
+	for _, r := range DecomposeEnergyPerResidue(protein, config) {
+		sum += r.Restraint
+	}
+	if math.Abs(sum-energy) > 1e-9 {
+		t.Errorf("Per-residue restraint energy sums to %.6f, want %.6f", sum, energy)
+	}
+}
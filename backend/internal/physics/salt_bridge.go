@@ -0,0 +1,273 @@
+// Package physics - Salt bridge and cation-π detection
+//
+// H-bonds are not the only side-chain interactions holding a fold
+// together. The contact predictor already rewards charge pairs and
+// aromatic contacts; these detectors say whether a structure actually
+// makes them.
+//
+// BIOCHEMIST: Salt bridge = Lys NZ or Arg NE/NH1/NH2 within 4.0 Å of Asp
+// OD1/OD2 or Glu OE1/OE2. Cation-π = Lys NZ or Arg CZ within 6.0 Å of the
+// centroid of a Phe, Tyr or Trp (six-membered) ring, sitting over the ring
+// face rather than beside its edge.
+// PHYSICIST: The cation-π angle is between the centroid→cation vector and
+// the ring plane: 90° is straight over the ring, 0° is in the plane
+// ETHICIST: Backbone-only models have no side chains; detection then falls
+// back to Cβ-Cβ proximity (real or virtual Cβ), a hypothesis rather than an
+// observed interaction, recorded in FromSidechain like Disulfide
+//
+// CITATION:
+// Barlow, D. J., & Thornton, J. M. (1983). "Ion-pairs in proteins."
+// J. Mol. Biol. 168(4): 867-885.
+// Gallivan, J. P., & Dougherty, D. A. (1999). "Cation-π interactions in
+// structural biology." Proc. Natl. Acad. Sci. USA 96(17): 9459-9464.
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Salt bridge and cation-π detection criteria
+const (
+	SaltBridgeCutoff     = 4.0  // Å, cationic N to carboxylate O
+	SaltBridgeCBCutoff   = 7.0  // Å, Cβ-Cβ (no side chains)
+	CationPiCutoff       = 6.0  // Å, cation to ring centroid
+	CationPiCBCutoff     = 7.0  // Å, Cβ-Cβ (no side chains)
+	CationPiMinAngle     = 30.0 // Degrees above the ring plane
+	minSaltBridgeSpacing = 2    // Residues i, i+1 are never counted
+)
+
+// Side-chain atoms of the charged and aromatic groups
+var (
+	cationicAtoms = map[byte][]string{'K': {"NZ"}, 'R': {"NE", "NH1", "NH2"}}
+	anionicAtoms  = map[byte][]string{'D': {"OD1", "OD2"}, 'E': {"OE1", "OE2"}}
+	cationCenter  = map[byte]string{'K': "NZ", 'R': "CZ"}
+	aromaticRings = map[byte][]string{
+		'F': {"CG", "CD1", "CD2", "CE1", "CE2", "CZ"},
+		'Y': {"CG", "CD1", "CD2", "CE1", "CE2", "CZ"},
+		'W': {"CD2", "CE2", "CE3", "CZ2", "CZ3", "CH2"},
+	}
+)
+
+// SaltBridge is a Lys/Arg - Asp/Glu ion pair
+type SaltBridge struct {
+	Cation        int     // Index into protein.Residues of the Lys/Arg
+	Anion         int     // Index into protein.Residues of the Asp/Glu
+	SeqNum1       int     // PDB residue number of the cation
+	SeqNum2       int     // PDB residue number of the anion
+	Distance      float64 // Closest N-O distance, or Cβ-Cβ (Å)
+	FromSidechain bool    // true: N-O evidence; false: Cβ-Cβ proxy
+}
+
+// String formats the pair with its evidence
+func (s SaltBridge) String() string {
+	atoms := "CB-CB"
+	if s.FromSidechain {
+		atoms = "N-O"
+	}
+	return fmt.Sprintf("%d - %d (%s %.2f Å)", s.SeqNum1, s.SeqNum2, atoms, s.Distance)
+}
+
+// CationPi is a Lys/Arg cation over a Phe/Tyr/Trp ring
+type CationPi struct {
+	Cation        int     // Index into protein.Residues of the Lys/Arg
+	Aromatic      int     // Index into protein.Residues of the Phe/Tyr/Trp
+	SeqNum1       int     // PDB residue number of the cation
+	SeqNum2       int     // PDB residue number of the aromatic
+	Distance      float64 // Cation to ring centroid, or Cβ-Cβ (Å)
+	Angle         float64 // Centroid→cation angle to the ring plane (degrees); NaN for Cβ proxies
+	FromSidechain bool    // true: ring geometry; false: Cβ-Cβ proxy
+}
+
+// String formats the pair with its geometry
+func (c CationPi) String() string {
+	if !c.FromSidechain {
+		return fmt.Sprintf("%d - %d (CB-CB %.2f Å)", c.SeqNum1, c.SeqNum2, c.Distance)
+	}
+	return fmt.Sprintf("%d - %d (centroid %.2f Å, %.0f° to ring plane)", c.SeqNum1, c.SeqNum2, c.Distance, c.Angle)
+}
+
+// DetectSaltBridges finds Lys/Arg - Asp/Glu ion pairs
+//
+// Pairs where both residues have their charged atoms are judged on the
+// closest N-O distance (< SaltBridgeCutoff). Otherwise Cβ-Cβ distance is
+// used (< SaltBridgeCBCutoff), with a virtual Cβ for backbone-only models.
+// Sequence neighbours are skipped. Results are in cation order.
+func DetectSaltBridges(protein *parser.Protein) []SaltBridge {
+	if protein == nil {
+		return nil
+	}
+
+	sidechains := residueSidechainAtoms(protein)
+	bridges := make([]SaltBridge, 0)
+
+	for i, res := range protein.Residues {
+		cationNames, ok := cationicAtoms[residueCode(res.Name)]
+		if !ok {
+			continue
+		}
+		for j, other := range protein.Residues {
+			anionNames, ok := anionicAtoms[residueCode(other.Name)]
+			if !ok || !nonAdjacent(protein, i, j) {
+				continue
+			}
+
+			cations := presentAtoms(sidechains[i], cationNames)
+			anions := presentAtoms(sidechains[j], anionNames)
+			bridge := SaltBridge{
+				Cation: i, Anion: j,
+				SeqNum1: res.SeqNum, SeqNum2: other.SeqNum,
+			}
+
+			if len(cations) > 0 && len(anions) > 0 {
+				bridge.FromSidechain = true
+				bridge.Distance = math.Inf(1)
+				for _, n := range cations {
+					for _, o := range anions {
+						bridge.Distance = math.Min(bridge.Distance, atomVector(n).Sub(atomVector(o)).Magnitude())
+					}
+				}
+				if bridge.Distance < SaltBridgeCutoff {
+					bridges = append(bridges, bridge)
+				}
+				continue
+			}
+
+			if d, ok := cbDistance(protein, sidechains, i, j); ok && d < SaltBridgeCBCutoff {
+				bridge.Distance = d
+				bridges = append(bridges, bridge)
+			}
+		}
+	}
+	return bridges
+}
+
+// DetectCationPi finds Lys/Arg cations over Phe/Tyr/Trp rings
+//
+// With side chains, the cation (Lys NZ, Arg CZ) must lie within
+// CationPiCutoff of the ring centroid and at least CationPiMinAngle above
+// the ring plane (Trp uses its six-membered ring). Otherwise Cβ-Cβ distance
+// is used (< CationPiCBCutoff) and Angle is NaN. Sequence neighbours are
+// skipped. Results are in cation order.
+func DetectCationPi(protein *parser.Protein) []CationPi {
+	if protein == nil {
+		return nil
+	}
+
+	sidechains := residueSidechainAtoms(protein)
+	pairs := make([]CationPi, 0)
+
+	for i, res := range protein.Residues {
+		centerName, ok := cationCenter[residueCode(res.Name)]
+		if !ok {
+			continue
+		}
+		for j, other := range protein.Residues {
+			ringNames, ok := aromaticRings[residueCode(other.Name)]
+			if !ok || !nonAdjacent(protein, i, j) {
+				continue
+			}
+
+			pair := CationPi{
+				Cation: i, Aromatic: j,
+				SeqNum1: res.SeqNum, SeqNum2: other.SeqNum,
+			}
+
+			center := sidechains[i][centerName]
+			ring := presentAtoms(sidechains[j], ringNames)
+			if center != nil && len(ring) == len(ringNames) {
+				centroid, normal := ringGeometry(ring)
+				offset := atomVector(center).Sub(centroid)
+				pair.FromSidechain = true
+				pair.Distance = offset.Magnitude()
+				if pair.Distance == 0 {
+					continue
+				}
+				sinAngle := math.Abs(offset.Dot(normal)) / pair.Distance
+				pair.Angle = math.Asin(math.Min(sinAngle, 1)) * 180.0 / math.Pi
+				if pair.Distance < CationPiCutoff && pair.Angle >= CationPiMinAngle {
+					pairs = append(pairs, pair)
+				}
+				continue
+			}
+
+			if d, ok := cbDistance(protein, sidechains, i, j); ok && d < CationPiCBCutoff {
+				pair.Distance = d
+				pair.Angle = math.NaN()
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	return pairs
+}
+
+// residueCode returns the one-letter code of a three-letter (PDB) or
+// one-letter (built) residue name, 0 if unknown
+func residueCode(name string) byte {
+	if len(name) == 1 {
+		return name[0]
+	}
+	return threeToOne[name]
+}
+
+// nonAdjacent reports whether residues i and j are at least
+// minSaltBridgeSpacing apart in sequence or on different chains
+func nonAdjacent(protein *parser.Protein, i, j int) bool {
+	a, b := protein.Residues[i], protein.Residues[j]
+	if a.ChainID != b.ChainID {
+		return true
+	}
+	d := i - j
+	if d < 0 {
+		d = -d
+	}
+	return d >= minSaltBridgeSpacing
+}
+
+// presentAtoms returns the atoms of names present in a residue's side chain
+func presentAtoms(atoms map[string]*parser.Atom, names []string) []*parser.Atom {
+	present := make([]*parser.Atom, 0, len(names))
+	for _, name := range names {
+		if atom := atoms[name]; atom != nil {
+			present = append(present, atom)
+		}
+	}
+	return present
+}
+
+// cbDistance returns the Cβ-Cβ distance of residues i and j (real Cβ, else
+// virtual)
+func cbDistance(protein *parser.Protein, sidechains map[int]map[string]*parser.Atom, i, j int) (float64, bool) {
+	p1, ok1 := disulfidePosition(protein, sidechains, i, false)
+	p2, ok2 := disulfidePosition(protein, sidechains, j, false)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	return p1.Sub(p2).Magnitude(), true
+}
+
+// ringGeometry returns the centroid and unit normal of a planar ring
+//
+// MATHEMATICIAN: The normal is the sum of cross products of consecutive
+// centroid→atom vectors (Newell's method), robust to slight non-planarity
+func ringGeometry(ring []*parser.Atom) (centroid, normal Vector3) {
+	for _, atom := range ring {
+		centroid = centroid.Add(atomVector(atom))
+	}
+	centroid = centroid.Mul(1.0 / float64(len(ring)))
+
+	// aromaticRings lists atoms in name order; walk them around the ring
+	// (Phe CG-CD1-CE1-CZ-CE2-CD2, Trp CD2-CE2-CZ2-CH2-CZ3-CE3)
+	order := []int{0, 1, 3, 5, 4, 2}
+	for k := range order {
+		a := atomVector(ring[order[k]]).Sub(centroid)
+		b := atomVector(ring[order[(k+1)%len(order)]]).Sub(centroid)
+		normal = normal.Add(crossVec(a, b))
+	}
+	if m := normal.Magnitude(); m > 0 {
+		normal = normal.Mul(1.0 / m)
+	}
+	return centroid, normal
+}
@@ -0,0 +1,110 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestDetectSaltBridges designs a Lys2-Glu6 salt bridge on a helix and
+// checks it is found within SaltBridgeCutoff, lost when pulled apart, and
+// proposed from Cβ proxies once the side chains are removed
+func TestDetectSaltBridges(t *testing.T) {
+	helix := buildUniformChain(t, 10, -57, -47)
+	lys, glu := helix.Residues[1], helix.Residues[5]
+	lys.Name, glu.Name = "LYS", "GLU"
+
+	cbK, _ := VirtualCB(lys)
+	cbE, _ := VirtualCB(glu)
+	mid := cbK.Add(cbE).Mul(0.5)
+	u := cbE.Sub(cbK).Mul(1.0 / cbE.Sub(cbK).Magnitude())
+	perp := crossVec(u, Vector3{X: 0, Y: 0, Z: 1})
+	perp = perp.Mul(1.0 / perp.Magnitude())
+
+	serial := len(helix.Atoms) + 1
+	add := func(res *parser.Residue, name string, p Vector3) *parser.Atom {
+		atom := &parser.Atom{Serial: serial, Name: name, ResName: res.Name, ChainID: res.ChainID,
+			ResSeq: res.SeqNum, X: p.X, Y: p.Y, Z: p.Z, Element: name[:1]}
+		serial++
+		helix.Atoms = append(helix.Atoms, atom)
+		return atom
+	}
+	add(lys, "CB", cbK)
+	add(glu, "CB", cbE)
+	add(lys, "NZ", mid.Add(u.Mul(-1.4)))
+	oe1 := add(glu, "OE1", mid.Add(u.Mul(1.4)))
+	oe2 := add(glu, "OE2", mid.Add(u.Mul(1.4)).Add(perp.Mul(2.2)))
+
+	bridges := DetectSaltBridges(helix)
+	if len(bridges) != 1 {
+		t.Fatalf("Found %d salt bridges, want 1: %v", len(bridges), bridges)
+	}
+	b := bridges[0]
+	t.Logf("Salt bridge: %s", b)
+	if b.Cation != 1 || b.Anion != 5 || !b.FromSidechain {
+		t.Errorf("Got %+v, want Lys index 1 - Glu index 5 from side chains", b)
+	}
+	if math.Abs(b.Distance-2.8) > 1e-9 || b.Distance >= SaltBridgeCutoff {
+		t.Errorf("NZ-OE1 distance %.3f Å, want 2.8 Å (< %.1f)", b.Distance, SaltBridgeCutoff)
+	}
+
+	// Pull the carboxylate 3 Å further away: N-O 5.8 Å, no bridge
+	for _, o := range []*parser.Atom{oe1, oe2} {
+		o.X += 3 * u.X
+		o.Y += 3 * u.Y
+		o.Z += 3 * u.Z
+	}
+	if bridges := DetectSaltBridges(helix); len(bridges) != 0 {
+		t.Errorf("Separated pair still detected: %v", bridges)
+	}
+
+	// Backbone only: i, i+4 Cβ atoms on a helix are ~6 Å apart
+	backbone := buildUniformChain(t, 10, -57, -47)
+	backbone.Residues[1].Name, backbone.Residues[5].Name = "K", "E"
+	bridges = DetectSaltBridges(backbone)
+	if len(bridges) != 1 || bridges[0].FromSidechain || bridges[0].Distance >= SaltBridgeCBCutoff {
+		t.Errorf("Backbone-only detection %v, want one Cβ proxy pair", bridges)
+	}
+}
+
+// TestDetectCationPi places a Lys NZ 3.5 Å over a Phe ring, then in the
+// ring plane, and checks only the stacked geometry counts
+func TestDetectCationPi(t *testing.T) {
+	helix := buildUniformChain(t, 10, -57, -47)
+	lys, phe := helix.Residues[1], helix.Residues[5]
+	lys.Name, phe.Name = "LYS", "PHE"
+
+	serial := len(helix.Atoms) + 1
+	add := func(res *parser.Residue, name string, p Vector3) *parser.Atom {
+		atom := &parser.Atom{Serial: serial, Name: name, ResName: res.Name, ChainID: res.ChainID,
+			ResSeq: res.SeqNum, X: p.X, Y: p.Y, Z: p.Z, Element: name[:1]}
+		serial++
+		helix.Atoms = append(helix.Atoms, atom)
+		return atom
+	}
+
+	// Hexagon of radius 1.39 Å in the z = 20 plane, in ring order
+	center := Vector3{X: 0, Y: 0, Z: 20}
+	for k, name := range []string{"CG", "CD1", "CE1", "CZ", "CE2", "CD2"} {
+		a := float64(k) * math.Pi / 3
+		add(phe, name, center.Add(Vector3{X: 1.39 * math.Cos(a), Y: 1.39 * math.Sin(a)}))
+	}
+	nz := add(lys, "NZ", center.Add(Vector3{Z: 3.5}))
+
+	pairs := DetectCationPi(helix)
+	if len(pairs) != 1 {
+		t.Fatalf("Found %d cation-π pairs, want 1: %v", len(pairs), pairs)
+	}
+	p := pairs[0]
+	t.Logf("Cation-π: %s", p)
+	if p.Cation != 1 || p.Aromatic != 5 || math.Abs(p.Distance-3.5) > 1e-9 || math.Abs(p.Angle-90) > 1e-6 {
+		t.Errorf("Got %+v, want Lys 1 over Phe 5 at 3.5 Å and 90°", p)
+	}
+
+	// Same distance, beside the ring edge
+	nz.X, nz.Z = 3.5, 20
+	if pairs := DetectCationPi(helix); len(pairs) != 0 {
+		t.Errorf("In-plane cation detected: %v", pairs)
+	}
+}
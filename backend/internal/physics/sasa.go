@@ -0,0 +1,158 @@
+// Package physics - Solvent-accessible surface area (Shrake-Rupley)
+//
+// The solvation terms and burial statistics score how much of each residue
+// water can touch. This is the standard numerical answer: place a set of
+// evenly spread points on every atom's sphere of radius r_vdW + r_probe and
+// count those not inside any neighbouring atom's sphere.
+//
+// PHYSICIST: SASA_i = 4π(r_i + r_probe)² × (exposed points / total points)
+// BIOCHEMIST: Heavy atoms only, Bondi radii by element (hydrogens are
+// folded into the united-atom radii and get zero area); probe 1.4 Å = water
+// MATHEMATICIAN: Points on a Fibonacci (golden-angle) spiral are near
+// uniform for any count; the area estimate converges as 1/√points
+//
+// CITATION:
+// Shrake, A., & Rupley, J. A. (1973). "Environment and exposure to solvent
+// of protein atoms. Lysozyme and insulin." J. Mol. Biol. 79(2): 351-371.
+// Bondi, A. (1964). "van der Waals volumes and radii." J. Phys. Chem.
+// 68(3): 441-451.
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// SASA parameters
+const (
+	DefaultProbeRadius = 1.4 // Å, water
+	SASAPointsPerAtom  = 960 // Sphere points per atom (Shrake & Rupley used 92)
+)
+
+// sasaSpherePoints are SASAPointsPerAtom unit vectors on a Fibonacci spiral
+var sasaSpherePoints = fibonacciSpherePoints(SASAPointsPerAtom)
+
+// CalculateSASA returns the solvent-accessible surface area of every atom
+// (Ų), indexed like protein.Atoms, by the Shrake-Rupley method
+//
+// Hydrogens have zero area and do not occlude. Neighbours are found with a
+// SpatialHash, so the cost is O(atoms × points × local neighbours).
+func CalculateSASA(protein *parser.Protein, probeRadius float64) []float64 {
+	if protein == nil {
+		return nil
+	}
+	sasa := make([]float64, len(protein.Atoms))
+
+	// Expanded radii; 0 marks atoms left out (hydrogens)
+	radii := make([]float64, len(protein.Atoms))
+	index := make(map[*parser.Atom]int, len(protein.Atoms))
+	maxRadius := 0.0
+	for i, atom := range protein.Atoms {
+		element := clashElement(atom)
+		if element == "H" {
+			continue
+		}
+		radii[i] = clashRadius(element) + probeRadius
+		maxRadius = math.Max(maxRadius, radii[i])
+		index[atom] = i
+	}
+	if maxRadius == 0 {
+		return sasa
+	}
+
+	grid := NewSpatialHash(2 * maxRadius)
+	for i, atom := range protein.Atoms {
+		if radii[i] > 0 {
+			grid.Insert(atom)
+		}
+	}
+
+	for i, atom := range protein.Atoms {
+		r := radii[i]
+		if r == 0 {
+			continue
+		}
+		center := atomVector(atom)
+
+		// Neighbours whose expanded spheres overlap this one
+		type occluder struct {
+			center Vector3
+			r2     float64
+		}
+		neighbours := make([]occluder, 0, 32)
+		for _, other := range grid.GetNeighbors(atom) {
+			j, ok := index[other]
+			if !ok || j == i {
+				continue
+			}
+			c := atomVector(other)
+			if c.Sub(center).Magnitude() < r+radii[j] {
+				neighbours = append(neighbours, occluder{center: c, r2: radii[j] * radii[j]})
+			}
+		}
+
+		exposed := 0
+		last := 0 // Neighbour that buried the previous point: adjacent points usually share it
+		for _, u := range sasaSpherePoints {
+			point := center.Add(u.Mul(r))
+			buried := false
+			for k := range neighbours {
+				m := (last + k) % len(neighbours)
+				d := neighbours[m].center.Sub(point)
+				if d.Dot(d) < neighbours[m].r2 {
+					buried, last = true, m
+					break
+				}
+			}
+			if !buried {
+				exposed++
+			}
+		}
+		sasa[i] = 4 * math.Pi * r * r * float64(exposed) / float64(len(sasaSpherePoints))
+	}
+	return sasa
+}
+
+// ResidueSASA sums per-atom SASA (from CalculateSASA) over each residue
+//
+// Residues with no heavy atoms are left out.
+func ResidueSASA(protein *parser.Protein, atomSASA []float64) map[*parser.Residue]float64 {
+	perResidue := make(map[*parser.Residue]float64)
+	if protein == nil {
+		return perResidue
+	}
+	residueOf := atomResidueIndex(protein)
+	for i, atom := range protein.Atoms {
+		if i >= len(atomSASA) || clashElement(atom) == "H" {
+			continue
+		}
+		if r, ok := residueOf(atom); ok {
+			perResidue[protein.Residues[r]] += atomSASA[i]
+		}
+	}
+	return perResidue
+}
+
+// TotalSASA sums per-atom SASA (Ų)
+func TotalSASA(atomSASA []float64) float64 {
+	total := 0.0
+	for _, a := range atomSASA {
+		total += a
+	}
+	return total
+}
+
+// fibonacciSpherePoints returns n near-uniform unit vectors on a golden-angle
+// spiral (z evenly spaced in (-1, 1), azimuth advancing by the golden angle)
+func fibonacciSpherePoints(n int) []Vector3 {
+	goldenAngle := math.Pi * (3 - math.Sqrt(5))
+	points := make([]Vector3, n)
+	for k := range points {
+		z := 1 - (2*float64(k)+1)/float64(n)
+		rho := math.Sqrt(1 - z*z)
+		theta := goldenAngle * float64(k)
+		points[k] = Vector3{X: rho * math.Cos(theta), Y: rho * math.Sin(theta), Z: z}
+	}
+	return points
+}
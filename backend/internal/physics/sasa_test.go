@@ -0,0 +1,102 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// sasaTestProtein wraps bare atoms at the given positions in a protein
+func sasaTestProtein(element string, positions []Vector3) *parser.Protein {
+	protein := &parser.Protein{}
+	for i, p := range positions {
+		protein.Atoms = append(protein.Atoms, &parser.Atom{
+			Serial: i + 1, Name: element, Element: element, ResName: "UNK", ResSeq: 1,
+			X: p.X, Y: p.Y, Z: p.Z,
+		})
+	}
+	return protein
+}
+
+// TestSASAIsolatedAndOverlapping checks an isolated carbon against
+// 4π(r + probe)² and a pair of overlapping carbons against the exact area
+// of a sphere minus the cap its neighbour covers
+func TestSASAIsolatedAndOverlapping(t *testing.T) {
+	R := 1.70 + DefaultProbeRadius
+	sphere := 4 * math.Pi * R * R
+
+	single := CalculateSASA(sasaTestProtein("C", []Vector3{{}}), DefaultProbeRadius)
+	t.Logf("Isolated C: %.3f Ų (4π(r+probe)² = %.3f)", single[0], sphere)
+	if math.Abs(single[0]-sphere) > 1e-9 {
+		t.Errorf("Isolated atom SASA %.4f Ų, want %.4f", single[0], sphere)
+	}
+
+	// Equal spheres at distance d: each loses a cap of height R - d/2
+	d := 3.0
+	pair := CalculateSASA(sasaTestProtein("C", []Vector3{{}, {X: d}}), DefaultProbeRadius)
+	exact := sphere - 2*math.Pi*R*(R-d/2)
+	tolerance := sphere / math.Sqrt(SASAPointsPerAtom)
+	t.Logf("Pair at %.1f Å: %.3f and %.3f Ų (exact %.3f, tolerance %.3f)", d, pair[0], pair[1], exact, tolerance)
+	for i, a := range pair {
+		if math.Abs(a-exact) > tolerance {
+			t.Errorf("Atom %d SASA %.3f Ų, want %.3f ± %.3f", i, a, exact, tolerance)
+		}
+	}
+}
+
+// TestSASABuriedAtom checks the centre of a 3×3×3 carbon cluster is fully
+// buried, the corners are not, and hydrogens neither have nor block area
+func TestSASABuriedAtom(t *testing.T) {
+	positions := make([]Vector3, 0, 27)
+	for x := -1; x <= 1; x++ {
+		for y := -1; y <= 1; y++ {
+			for z := -1; z <= 1; z++ {
+				positions = append(positions, Vector3{X: 2 * float64(x), Y: 2 * float64(y), Z: 2 * float64(z)})
+			}
+		}
+	}
+	cluster := sasaTestProtein("C", positions)
+	sasa := CalculateSASA(cluster, DefaultProbeRadius)
+
+	centre, corner := sasa[13], sasa[0]
+	t.Logf("Cluster: centre %.3f Ų, corner %.3f Ų, total %.1f Ų", centre, corner, TotalSASA(sasa))
+	if centre > 1e-9 {
+		t.Errorf("Buried centre atom has SASA %.3f Ų, want 0", centre)
+	}
+	if corner < 1 {
+		t.Errorf("Corner atom SASA %.3f Ų, want exposed", corner)
+	}
+
+	// A hydrogen at the centre of an isolated carbon pair changes nothing
+	withH := sasaTestProtein("C", []Vector3{{}, {X: 3}})
+	withH.Atoms = append(withH.Atoms, &parser.Atom{Serial: 3, Name: "H", Element: "H", X: 1.5})
+	plain := CalculateSASA(sasaTestProtein("C", []Vector3{{}, {X: 3}}), DefaultProbeRadius)
+	got := CalculateSASA(withH, DefaultProbeRadius)
+	if got[0] != plain[0] || got[1] != plain[1] || got[2] != 0 {
+		t.Errorf("Hydrogen changed SASA: %v, want %v and 0", got, plain)
+	}
+}
+
+// TestResidueSASA checks per-residue SASA on a helix sums to the total and
+// that middle residues are less exposed than the termini
+func TestResidueSASA(t *testing.T) {
+	helix := buildUniformChain(t, 10, -57, -47)
+	atoms := CalculateSASA(helix, DefaultProbeRadius)
+	perResidue := ResidueSASA(helix, atoms)
+
+	sum := 0.0
+	for _, a := range perResidue {
+		sum += a
+	}
+	total := TotalSASA(atoms)
+	if len(perResidue) != len(helix.Residues) || math.Abs(sum-total) > 1e-6 {
+		t.Errorf("%d residues summing to %.3f Ų, want %d summing to %.3f", len(perResidue), sum, len(helix.Residues), total)
+	}
+
+	first, middle := perResidue[helix.Residues[0]], perResidue[helix.Residues[5]]
+	t.Logf("Helix: total %.1f Ų, residue 1 %.1f Ų, residue 6 %.1f Ų", total, first, middle)
+	if middle >= first {
+		t.Errorf("Middle residue SASA %.1f Ų not below N-terminal %.1f Ų", middle, first)
+	}
+}
@@ -1,8 +1,6 @@
 package physics
 
 import (
-	"math"
-
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 )
 
@@ -41,93 +39,16 @@ var hydrophobicityScale = map[byte]float64{
 	'Y': -1.3, // Tyrosine
 }
 
-// CalculateSASA calculates Solvent-Accessible Surface Area for each residue
-// Uses simplified Lee-Richards algorithm
-func CalculateSASA(protein *parser.Protein) map[*parser.Residue]float64 {
-	sasa := make(map[*parser.Residue]float64)
-
-	// Probe radius (water molecule, ~1.4 Å)
-	probeRadius := 1.4
-
-	// Atomic radii (from Bondi, 1964)
-	atomicRadii := map[string]float64{
-		"N":  1.55,
-		"CA": 1.70,
-		"C":  1.70,
-		"O":  1.52,
-	}
-
-	// For each residue, calculate SASA of its CA atom (simplified)
-	// Real SASA would consider all atoms, but CA is good approximation
-	for _, residue := range protein.Residues {
-		if residue.CA == nil {
-			continue
-		}
-
-		// Extended radius = atomic radius + probe radius
-		caRadius := atomicRadii["CA"] + probeRadius
-
-		// Generate probe points on sphere around CA
-		numProbePoints := 100 // More points = more accurate, but slower
-		exposedPoints := 0
-
-		for i := 0; i < numProbePoints; i++ {
-			// Fibonacci sphere point generation
-			phi := math.Pi * (3.0 - math.Sqrt(5.0)) // Golden angle in radians
-			y := 1.0 - (float64(i)/float64(numProbePoints-1))*2.0
-			radius := math.Sqrt(1.0 - y*y)
-			theta := phi * float64(i)
-
-			x := math.Cos(theta) * radius
-			z := math.Sin(theta) * radius
-
-			// Scale to CA radius
-			probeX := residue.CA.X + x*caRadius
-			probeY := residue.CA.Y + y*caRadius
-			probeZ := residue.CA.Z + z*caRadius
-
-			// Check if this probe point is buried by other atoms
-			isExposed := true
-			for _, otherResidue := range protein.Residues {
-				if otherResidue.SeqNum == residue.SeqNum {
-					continue // Skip self
-				}
-
-				// Check distance to other CA atoms
-				if otherResidue.CA != nil {
-					dx := probeX - otherResidue.CA.X
-					dy := probeY - otherResidue.CA.Y
-					dz := probeZ - otherResidue.CA.Z
-					dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
-
-					otherRadius := atomicRadii["CA"] + probeRadius
-					if dist < otherRadius {
-						isExposed = false
-						break
-					}
-				}
-			}
-
-			if isExposed {
-				exposedPoints++
-			}
-		}
-
-		// SASA is proportional to fraction of exposed points
-		// Multiply by sphere surface area
-		sphereArea := 4.0 * math.Pi * caRadius * caRadius
-		residueSASA := sphereArea * float64(exposedPoints) / float64(numProbePoints)
-
-		sasa[residue] = residueSASA
-	}
-
-	return sasa
+// residueSASA returns the per-residue SASA the solvation terms score:
+// Shrake-Rupley (CalculateSASA) with a water probe, summed by ResidueSASA
+func residueSASA(protein *parser.Protein) map[*parser.Residue]float64 {
+	return ResidueSASA(protein, CalculateSASA(protein, DefaultProbeRadius))
 }
 
 // CalculateSolvationEnergy calculates implicit solvation energy
 // Uses SASA-based model (similar to EEF1)
 func CalculateSolvationEnergy(protein *parser.Protein) float64 {
-	sasa := CalculateSASA(protein)
+	sasa := residueSASA(protein)
 
 	totalEnergy := 0.0
 
@@ -176,7 +97,7 @@ type BurialStatistics struct {
 }
 
 func GetBurialStatistics(protein *parser.Protein) BurialStatistics {
-	sasa := CalculateSASA(protein)
+	sasa := residueSASA(protein)
 
 	stats := BurialStatistics{}
 
@@ -233,7 +154,7 @@ func GetBurialStatistics(protein *parser.Protein) BurialStatistics {
 // CalculateHydrophobicEffect calculates hydrophobic collapse energy
 // Rewards buried hydrophobic residues, penalizes exposed ones
 func CalculateHydrophobicEffect(protein *parser.Protein) float64 {
-	sasa := CalculateSASA(protein)
+	sasa := residueSASA(protein)
 
 	totalEnergy := 0.0
 
@@ -267,7 +188,7 @@ func CalculateHydrophobicEffect(protein *parser.Protein) float64 {
 // CalculateEntropyPenalty calculates entropy loss upon folding
 // Simplified: proportional to number of buried residues
 func CalculateEntropyPenalty(protein *parser.Protein) float64 {
-	sasa := CalculateSASA(protein)
+	sasa := residueSASA(protein)
 
 	numBuried := 0
 	for _, residueSASA := range sasa {
@@ -108,12 +108,14 @@ func CalculateNonBondedEnergySpatial(protein *parser.Protein, vdwCutoff, elecCut
 		spatialHash.Insert(atom)
 	}
 
-	// Simplified charges
-	charges := map[string]float64{
-		"N":  -0.4157,
-		"CA": 0.0337,
-		"C":  0.5973,
-		"O":  -0.5679,
+	// Partial charges (ff14SB, or terminal where capped) and LJ parameters
+	charges := make(map[*parser.Atom]float64, len(protein.Atoms))
+	for i, q := range partialCharges(protein) {
+		charges[protein.Atoms[i]] = q
+	}
+	lj := make(map[*parser.Atom]LennardJonesParams, len(protein.Atoms))
+	for i, params := range ljParameters(protein) {
+		lj[protein.Atoms[i]] = params
 	}
 
 	// Calculate pairwise energies (only neighbors)
@@ -151,14 +153,13 @@ func CalculateNonBondedEnergySpatial(protein *parser.Protein, vdwCutoff, elecCut
 
 			// Van der Waals
 			if r <= vdwCutoff {
-				vdw += CalculateLennardJonesEnergy(atom1, atom2, vdwCutoff)
+				vdw += lennardJonesPairEnergy(atom1, atom2, lj[atom1], lj[atom2], 0, vdwCutoff)
 			}
 
 			// Electrostatic
 			if r <= elecCutoff {
-				charge1, ok1 := charges[atom1.Name]
-				charge2, ok2 := charges[atom2.Name]
-				if ok1 && ok2 {
+				charge1, charge2 := charges[atom1], charges[atom2]
+				if charge1 != 0 && charge2 != 0 {
 					elec += CalculateElectrostaticEnergy(atom1, atom2, charge1, charge2, elecCutoff)
 				}
 			}
@@ -0,0 +1,45 @@
+// Package physics - Smooth nonbonded cutoffs
+//
+// A hard cutoff drops a pair's energy from its value at the cutoff to zero
+// in one step: moves that carry a pair across it change the energy by a
+// finite amount for an infinitesimal displacement, and the force has a
+// delta spike there. Minimizers see spurious uphill steps and MC sees
+// spurious acceptances or rejections. Multiplying each pair energy by a
+// switching function that falls from 1 to 0 over [switchStart, cutoff]
+// removes both.
+//
+// PHYSICIST: CHARMM switch S(r) on r²: 1 below r_on, 0 above r_off,
+// S = (r_off² - r²)² (r_off² + 2r² - 3r_on²) / (r_off² - r_on²)³ between
+// MATHEMATICIAN: S and dS/dr vanish at both ends of the window (dS/dr = 0
+// at r_on, S = dS/dr = 0 at r_off), so E·S is C¹ everywhere
+//
+// CITATION:
+// Brooks, B. R., et al. (1983). "CHARMM: A program for macromolecular energy,
+// minimization, and dynamics calculations." J. Comput. Chem. 4(2): 187-217.
+package physics
+
+// cutoffSwitch returns the switching factor S(r) and dS/dr for the window
+// [on, off]
+//
+// With on <= 0 or on >= off there is no window: S is a hard cutoff (1 up to
+// and including off, 0 beyond) with zero slope.
+func cutoffSwitch(r, on, off float64) (s, dsdr float64) {
+	if on <= 0 || on >= off {
+		if r > off {
+			return 0, 0
+		}
+		return 1, 0
+	}
+	if r <= on {
+		return 1, 0
+	}
+	if r >= off {
+		return 0, 0
+	}
+
+	on2, off2, r2 := on*on, off*off, r*r
+	denom := (off2 - on2) * (off2 - on2) * (off2 - on2)
+	s = (off2 - r2) * (off2 - r2) * (off2 + 2*r2 - 3*on2) / denom
+	dsdr = 12 * r * (off2 - r2) * (on2 - r2) / denom
+	return s, dsdr
+}
@@ -0,0 +1,81 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestSwitchedPairEnergyIsSmooth scans a pair distance across the switching
+// window and the cutoff for both VdW and Coulomb, checking the energy is
+// continuous, reaches zero at the cutoff, and its slope (the analytic force,
+// checked against a finite difference) is continuous too
+func TestSwitchedPairEnergyIsSmooth(t *testing.T) {
+	const switchStart, cutoff = 8.0, 10.0
+	lj := LennardJonesParams{Sigma: 3.4, Epsilon: 0.15}
+
+	terms := []struct {
+		name   string
+		energy func(a, b *parser.Atom) float64
+		force  func(a, b *parser.Atom) Vector3
+	}{
+		{
+			"VdW",
+			func(a, b *parser.Atom) float64 { return lennardJonesPairEnergy(a, b, lj, lj, switchStart, cutoff) },
+			func(a, b *parser.Atom) Vector3 { return lennardJonesPairForce(a, b, lj, lj, switchStart, cutoff) },
+		},
+		{
+			"Coulomb",
+			func(a, b *parser.Atom) float64 {
+				return electrostaticPairEnergy(a, b, -0.5679, 0.5973, switchStart, cutoff)
+			},
+			func(a, b *parser.Atom) Vector3 {
+				return electrostaticPairForce(a, b, -0.5679, 0.5973, switchStart, cutoff)
+			},
+		},
+	}
+
+	origin := &parser.Atom{}
+	at := func(r float64) *parser.Atom { return &parser.Atom{X: r} }
+	const step, h = 0.01, 1e-5
+
+	for _, term := range terms {
+		// Slope is -F_x for atom2 on the +x axis
+		slope := func(r float64) float64 { return -term.force(origin, at(r)).X }
+
+		unswitched := term.energy(origin, at(switchStart))
+		maxJump, maxKink, maxForceErr := 0.0, 0.0, 0.0
+		for r := 7.5; r < 10.5; r += step {
+			maxJump = math.Max(maxJump, math.Abs(term.energy(origin, at(r+step))-term.energy(origin, at(r))))
+			maxKink = math.Max(maxKink, math.Abs(slope(r+step)-slope(r)))
+
+			fd := (term.energy(origin, at(r+h)) - term.energy(origin, at(r-h))) / (2 * h)
+			// Skip the window edges, where the second derivative jumps and
+			// the central difference picks up an O(h) error
+			if math.Abs(r-cutoff) > 2*h && math.Abs(r-switchStart) > 2*h {
+				maxForceErr = math.Max(maxForceErr, math.Abs(fd-slope(r)))
+			}
+		}
+		t.Logf("%s: E(r_on) = %.5f, max step in E = %.2e, in dE/dr = %.2e, force error %.2e",
+			term.name, unswitched, maxJump, maxKink, maxForceErr)
+
+		// A step of 0.01 Å may change E and dE/dr by a smooth amount, but
+		// nowhere by a jump comparable to the unswitched value
+		if limit := 0.05 * math.Abs(unswitched); maxJump > limit {
+			t.Errorf("%s energy jumps by %.3e over %.2f Å (limit %.3e)", term.name, maxJump, step, limit)
+		}
+		if limit := 0.05 * math.Abs(unswitched); maxKink > limit {
+			t.Errorf("%s slope jumps by %.3e over %.2f Å (limit %.3e)", term.name, maxKink, step, limit)
+		}
+		if maxForceErr > 1e-6*math.Max(1, math.Abs(unswitched)) {
+			t.Errorf("%s analytic force differs from finite difference by %.3e", term.name, maxForceErr)
+		}
+
+		for _, r := range []float64{cutoff - 1e-6, cutoff, cutoff + 1e-6} {
+			if e, s := term.energy(origin, at(r)), slope(r); math.Abs(e) > 1e-9 || math.Abs(s) > 1e-4 {
+				t.Errorf("%s at r = %.6f: E = %.3e, dE/dr = %.3e, want both 0", term.name, r, e, s)
+			}
+		}
+	}
+}
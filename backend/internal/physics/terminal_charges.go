@@ -0,0 +1,113 @@
+// Package physics - Per-atom partial charges with charged termini
+//
+// The ff14SB residue charges (ForceFieldParams) describe residues inside a
+// chain, so a chain has no charged ends. When the termini are capped (OXT on the
+// C-terminus, H1/H2/H3 on the N-terminus, see geometry.AddTerminalCaps) the
+// terminal residues take AMBER's terminal-residue charges instead: a
+// carboxylate carrying about -1 e and an ammonium carrying about +1 e.
+//
+// BIOCHEMIST: At pH 7 the C-terminus is COO- and the N-terminus NH3+; a
+// neutral NH2 terminus (H1, H2 only) is given charges that sum to the
+// uncapped N, adding no net charge
+// PHYSICIST: Terminal residues are recognized from their cap atoms, so
+// uncapped structures keep exactly the residue charges
+//
+// CITATION:
+// Maier, J. A., et al. (2015). "ff14SB: Improving the accuracy of protein
+// side chain and backbone parameters from ff99SB." J. Chem. Theory Comput.
+// 11(8): 3696-3713. (NALA / CALA terminal charges)
+package physics
+
+import (
+	"fmt"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// cTerminalCharges replace the residue charges in a residue that has OXT
+var cTerminalCharges = map[string]float64{
+	"CA":  -0.1747,
+	"C":   0.7731,
+	"O":   -0.8055,
+	"OXT": -0.8055,
+}
+
+// nTerminalCharges replace the residue charges in a residue that has H3 (NH3+)
+var nTerminalCharges = map[string]float64{
+	"N":  0.1414,
+	"CA": 0.0962,
+	"H1": 0.1997,
+	"H2": 0.1997,
+	"H3": 0.1997,
+}
+
+// nTerminalNeutralCharges replace the residue charges in a residue that has
+// H1 but no H3 (NH2); N + H1 + H2 equals the backbone N charge
+var nTerminalNeutralCharges = map[string]float64{
+	"N":  -0.9757,
+	"H1": 0.2800,
+	"H2": 0.2800,
+}
+
+// partialCharges returns the charge (e) of each atom in protein.Atoms: the
+// ff14SB residue charges, with terminal charges in capped terminal residues
+// and 0 for atoms the force field does not list
+func partialCharges(protein *parser.Protein) []float64 {
+	residueKey := func(atom *parser.Atom) string {
+		return fmt.Sprintf("%s:%d:%s", atom.ChainID, atom.ResSeq, atom.ICode)
+	}
+
+	// Recognize capped termini from their cap atoms; a residue may carry
+	// several tables (a one-residue chain), applied in this order
+	capped := make(map[string]map[string]bool)
+	for _, atom := range protein.Atoms {
+		switch atom.Name {
+		case "OXT", "H1", "H3":
+			key := residueKey(atom)
+			if capped[key] == nil {
+				capped[key] = make(map[string]bool)
+			}
+			capped[key][atom.Name] = true
+		}
+	}
+	terminal := make(map[string][]map[string]float64, len(capped))
+	for key, caps := range capped {
+		if caps["OXT"] {
+			terminal[key] = append(terminal[key], cTerminalCharges)
+		}
+		if caps["H3"] {
+			terminal[key] = append(terminal[key], nTerminalCharges)
+		} else if caps["H1"] {
+			terminal[key] = append(terminal[key], nTerminalNeutralCharges)
+		}
+	}
+
+	charges := make([]float64, len(protein.Atoms))
+	for i, params := range ff14SBParams.atomTypeParams(protein) {
+		charges[i] = params.Charge
+		if len(terminal) == 0 {
+			continue // Uncapped: residue charges only
+		}
+		atom := protein.Atoms[i]
+		for _, table := range terminal[residueKey(atom)] {
+			if q, ok := table[atom.Name]; ok {
+				charges[i] = q
+				break
+			}
+		}
+	}
+	return charges
+}
+
+// NetCharge returns the sum of the partial charges of protein (e), as the
+// electrostatic energy sees them
+func NetCharge(protein *parser.Protein) float64 {
+	if protein == nil {
+		return 0
+	}
+	total := 0.0
+	for _, q := range partialCharges(protein) {
+		total += q
+	}
+	return total
+}
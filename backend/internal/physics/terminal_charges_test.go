@@ -0,0 +1,116 @@
+package physics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// buildCappedPeptide builds a 12-residue helix with capped termini
+func buildCappedPeptide(t *testing.T, protonated bool) (capped, bare *parser.Protein) {
+	t.Helper()
+	sequence := strings.Repeat("A", 12)
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60 * math.Pi / 180, Psi: -45 * math.Pi / 180}
+	}
+
+	bare, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	config := geometry.DefaultGeometryConfig()
+	config.CapTermini = true
+	config.ProtonatedNTerminus = protonated
+	capped, err = geometry.BuildProteinFromAnglesWithConfig(sequence, angles, config)
+	if err != nil {
+		t.Fatalf("Capped build failed: %v", err)
+	}
+	return capped, bare
+}
+
+// TestTerminalCapsCharges checks OXT is built and the carboxylate carries a
+// negative charge into the electrostatic term
+func TestTerminalCapsCharges(t *testing.T) {
+	capped, bare := buildCappedPeptide(t, false)
+
+	last := capped.Residues[len(capped.Residues)-1]
+	charges := partialCharges(capped)
+	var oxt *parser.Atom
+	carboxylate := 0.0
+	for i, atom := range capped.Atoms {
+		if atom.ResSeq != last.SeqNum {
+			continue
+		}
+		switch atom.Name {
+		case "OXT":
+			oxt = atom
+			carboxylate += charges[i]
+		case "C", "O":
+			carboxylate += charges[i]
+		}
+	}
+	if oxt == nil {
+		t.Fatal("No OXT on the C-terminal residue")
+	}
+
+	dist := func(a, b *parser.Atom) float64 {
+		return math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+	}
+	if d := dist(oxt, last.C); math.Abs(d-geometry.BondC_OXT) > 1e-6 {
+		t.Errorf("C-OXT distance %.3f Å, want %.3f", d, geometry.BondC_OXT)
+	}
+	if d := dist(oxt, last.O); d < 2.0 {
+		t.Errorf("OXT only %.2f Å from O", d)
+	}
+
+	netChange := NetCharge(capped) - NetCharge(bare)
+	t.Logf("Carboxylate C+O+OXT charge %.3f e, net charge change %.3f e", carboxylate, netChange)
+	if carboxylate > -0.8 {
+		t.Errorf("Carboxylate charge %.3f e, want about -1", carboxylate)
+	}
+	if netChange > -0.8 || netChange < -1.2 {
+		t.Errorf("Capping (neutral NH2) changed the net charge by %.3f e, want about -1", netChange)
+	}
+
+	cappedElec := CalculateTotalEnergy(capped, 10.0, 12.0).Electrostatic
+	bareElec := CalculateTotalEnergy(bare, 10.0, 12.0).Electrostatic
+	t.Logf("Electrostatic: bare %.3f, capped %.3f kcal/mol", bareElec, cappedElec)
+	if cappedElec == bareElec {
+		t.Error("Terminal charges did not change the electrostatic energy")
+	}
+}
+
+// TestTerminalCapsProtonated checks NH3+ gets three hydrogens and a positive
+// terminal charge, and incremental energies agree with full ones
+func TestTerminalCapsProtonated(t *testing.T) {
+	capped, bare := buildCappedPeptide(t, true)
+
+	hydrogens := 0
+	for _, atom := range capped.Atoms {
+		if atom.ResSeq == 1 && (atom.Name == "H1" || atom.Name == "H2" || atom.Name == "H3") {
+			hydrogens++
+		}
+	}
+	if hydrogens != 3 {
+		t.Errorf("NH3+ terminus has %d hydrogens, want 3", hydrogens)
+	}
+
+	// NH3+ and COO- cancel to within the backbone charge model's slack
+	if change := NetCharge(capped) - NetCharge(bare); math.Abs(change) > 0.3 {
+		t.Errorf("Zwitterion changed the net charge by %.3f e, want about 0", change)
+	}
+
+	config := DefaultEnergyConfig()
+	inc := NewIncrementalEnergy(capped, config)
+	trial := capped.Copy()
+	trial.Atoms[len(trial.Atoms)-1].X += 0.5 // Move a cap atom
+	proposed := inc.Propose(trial, false)
+	full := CalculateTotalEnergyWithConfig(trial, config)
+	if math.Abs(proposed.Electrostatic-full.Electrostatic) > 1e-9 {
+		t.Errorf("Incremental electrostatics %.6f, full %.6f", proposed.Electrostatic, full.Electrostatic)
+	}
+}
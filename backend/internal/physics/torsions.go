@@ -0,0 +1,347 @@
+// Package physics - AMBER-form Fourier torsion energy
+//
+// Each torsion contributes a short Fourier series
+//
+//	E(θ) = Σ_n V_n/2 × (1 + cos(nθ - γ_n))
+//
+// with parameters looked up by the atom-type quartet of its four atoms, as
+// in AMBER parm files. This replaces the Gaussian-well Ramachandran
+// potential as the Dihedral energy component.
+//
+// BIOCHEMIST: Backbone φ/ψ, their Cβ partners φ'/ψ', the peptide ω and the
+// side-chain χ1/χ2 torsions of whichever side-chain atoms are present.
+// Non-glycine residues without a Cβ atom get a virtual Cβ, so backbone-only
+// models still see L-amino acid chirality (αR favored over αL).
+// PHYSICIST: ff14SB backbone torsions assume scaled 1-4 non-bonded terms,
+// which this force field does not have (residues i, i±1 are excluded), so
+// the φ/φ'/ψ/ψ' terms are refit in AMBER form to put the alanine dipeptide
+// minima in the α and β basins. ω and χ use the parm99 generic terms.
+// MATHEMATICIAN: Every term is even in θ; chirality enters only through the
+// Cβ position. ∂E/∂θ = -Σ V_n/2 × n × sin(nθ - γ_n) is chained through the
+// analytical dihedral gradient (and the virtual Cβ construction) for forces.
+// ETHICIST: The refit backbone terms are not ff14SB numbers; do not compare
+// energies with AMBER output
+//
+// CITATION:
+// Cornell, W. D., et al. (1995). "A second generation force field for the simulation of
+// proteins, nucleic acids, and organic molecules." J. Am. Chem. Soc. 117(19): 5179-5197.
+//
+// Maier, J. A., et al. (2015). "ff14SB: Improving the accuracy of protein side chain and
+// backbone parameters from ff99SB." J. Chem. Theory Comput. 11(8): 3696-3713.
+package physics
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TorsionTerm is one Fourier term V_n/2 × (1 + cos(nθ - γ))
+type TorsionTerm struct {
+	HalfBarrier float64 // V_n/2 in kcal/mol (AMBER PK divided by IDIVF)
+	Periodicity int     // n
+	Phase       float64 // γ in radians
+}
+
+// torsionParameters maps "T1-T2-T3-T4" AMBER atom types to Fourier terms
+//
+// "X" is a wildcard for the outer atoms. Lookup tries the exact quartet,
+// its reverse, then the X-T2-T3-X wildcard.
+var torsionParameters = map[string][]TorsionTerm{
+	// φ: C(i-1)-N-CA-C, penalizes φ ≈ 0 (C···C clash)
+	"C-N-CT-C": {{HalfBarrier: 1.00, Periodicity: 1, Phase: 0}},
+
+	// φ': C(i-1)-N-CA-CB, φ' ≈ φ - 120°: penalizes φ > 0 for L residues
+	"C-N-CT-CT": {{HalfBarrier: 2.00, Periodicity: 1, Phase: 0}},
+
+	// ψ and ψ' (CB-CA-C-N(i+1), ψ' ≈ ψ + 120°) combine into minima at
+	// ψ ≈ -45° (α) and ψ ≈ 135° (β/PPII)
+	"N-CT-C-N":  {{HalfBarrier: 0.46, Periodicity: 2, Phase: 0}},
+	"CT-CT-C-N": {{HalfBarrier: 1.10, Periodicity: 2, Phase: 0}},
+
+	// ω: peptide bond, 10.0/4 per path (parm99 X-C-N-X)
+	"X-C-N-X": {{HalfBarrier: 2.50, Periodicity: 2, Phase: math.Pi}},
+
+	// Rotations about N-CA and CA-C beyond φ/ψ carry no generic barrier
+	"X-CT-N-X": {{HalfBarrier: 0.00, Periodicity: 2, Phase: 0}},
+	"X-C-CT-X": {{HalfBarrier: 0.00, Periodicity: 2, Phase: 0}},
+
+	// χ: sp3-sp3 carbon, 1.4/9 per path (parm99 X-CT-CT-X)
+	"X-CT-CT-X": {{HalfBarrier: 0.156, Periodicity: 3, Phase: 0}},
+
+	// χ2 of aromatics and carboxyl/amide groups: no barrier (parm99 X-CA-CT-X, X-C-CT-X)
+	"X-CA-CT-X": {{HalfBarrier: 0.00, Periodicity: 2, Phase: 0}},
+}
+
+// torsion is one dihedral with its resolved Fourier terms
+type torsion struct {
+	atoms [4]*parser.Atom
+	terms []TorsionTerm
+
+	// virtualCB is the index in atoms of a virtual Cβ (-1 if none) built
+	// from the backbone of residue
+	virtualCB int
+	residue   *parser.Residue
+}
+
+// TorsionEnergy computes the AMBER Fourier torsion energy (kcal/mol)
+//
+// Backbone torsions need only N, CA, C, O; φ'/ψ' and χ torsions are added
+// for residues whose CB, γ and δ atoms are present in protein.Atoms.
+func TorsionEnergy(protein *parser.Protein) float64 {
+	totalEnergy := 0.0
+	for _, t := range enumerateTorsions(protein) {
+		theta := torsionAngle(t.atoms)
+		for _, term := range t.terms {
+			totalEnergy += term.HalfBarrier * (1.0 + math.Cos(float64(term.Periodicity)*theta-term.Phase))
+		}
+	}
+	return totalEnergy
+}
+
+// addTorsionForces adds F = -∂E/∂θ × ∂θ/∂r for every torsion
+func addTorsionForces(protein *parser.Protein, forces map[int]Vector3) {
+	for _, t := range enumerateTorsions(protein) {
+		theta := torsionAngle(t.atoms)
+
+		dEdTheta := 0.0
+		for _, term := range t.terms {
+			n := float64(term.Periodicity)
+			dEdTheta -= term.HalfBarrier * n * math.Sin(n*theta-term.Phase)
+		}
+		if dEdTheta == 0 {
+			continue
+		}
+
+		grad := dihedralGradient(t.atoms)
+		for k, atom := range t.atoms {
+			force := grad[k].Mul(-dEdTheta)
+			if k == t.virtualCB {
+				addVirtualCBForce(t.residue, force, forces)
+				continue
+			}
+			forces[atom.Serial] = forces[atom.Serial].Add(force)
+		}
+	}
+}
+
+// lookupTorsion resolves Fourier terms for an atom-type quartet
+func lookupTorsion(t1, t2, t3, t4 string) []TorsionTerm {
+	for _, key := range []string{
+		t1 + "-" + t2 + "-" + t3 + "-" + t4,
+		t4 + "-" + t3 + "-" + t2 + "-" + t1,
+		"X-" + t2 + "-" + t3 + "-X",
+		"X-" + t3 + "-" + t2 + "-X",
+	} {
+		if terms, ok := torsionParameters[key]; ok {
+			return terms
+		}
+	}
+	return nil
+}
+
+// sidechainGamma and sidechainDelta name the atoms defining χ1 and χ2
+var sidechainGamma = []string{"CG", "CG1", "OG", "OG1", "SG"}
+var sidechainDelta = []string{"CD", "CD1", "OD1", "ND1", "SD"}
+
+// enumerateTorsions lists every parameterized torsion in the protein
+func enumerateTorsions(protein *parser.Protein) []torsion {
+	torsions := make([]torsion, 0, 6*len(protein.Residues))
+	residues := protein.Residues
+	sidechains := residueSidechainAtoms(protein)
+
+	add := func(res *parser.Residue, virtual *parser.Atom, atoms ...*parser.Atom) {
+		virtualIdx := -1
+		for k, a := range atoms {
+			if a == nil {
+				return
+			}
+			if a == virtual {
+				virtualIdx = k
+			}
+		}
+		types := [4]string{}
+		for k, a := range atoms {
+			types[k] = amberAtomType(res.Name, a.Name)
+		}
+		if terms := lookupTorsion(types[0], types[1], types[2], types[3]); len(terms) > 0 {
+			torsions = append(torsions, torsion{
+				atoms:     [4]*parser.Atom{atoms[0], atoms[1], atoms[2], atoms[3]},
+				terms:     terms,
+				virtualCB: virtualIdx,
+				residue:   res,
+			})
+		}
+	}
+
+	for i, res := range residues {
+		cb := sidechains[i]["CB"]
+		var virtual *parser.Atom
+		if cb == nil && !isGlycine(res.Name) {
+			if pos, ok := VirtualCB(res); ok {
+				virtual = &parser.Atom{Name: "CB", ResName: res.Name, X: pos.X, Y: pos.Y, Z: pos.Z}
+				cb = virtual
+			}
+		}
+
+		if i > 0 {
+			prev := residues[i-1]
+			add(res, virtual, prev.C, res.N, res.CA, res.C)   // φ
+			add(res, virtual, prev.C, res.N, res.CA, cb)      // φ'
+			add(res, virtual, prev.CA, prev.C, res.N, res.CA) // ω
+			add(res, virtual, prev.O, prev.C, res.N, res.CA)  // ω (carbonyl path)
+		}
+
+		if i < len(residues)-1 {
+			next := residues[i+1]
+			add(res, virtual, res.N, res.CA, res.C, next.N) // ψ
+			add(res, virtual, cb, res.CA, res.C, next.N)    // ψ'
+		}
+
+		// χ1 and χ2 from whichever side-chain atoms exist
+		gamma := firstAtom(sidechains[i], sidechainGamma)
+		add(res, virtual, res.N, res.CA, cb, gamma)
+		add(res, virtual, res.CA, cb, gamma, firstAtom(sidechains[i], sidechainDelta))
+	}
+
+	return torsions
+}
+
+// addVirtualCBForce distributes a force on a virtual Cβ onto N, CA and C
+//
+// MATHEMATICIAN: Cβ = k1·(b × c) + k2·b + k3·c + CA with b = CA - N,
+// c = C - CA (see VirtualCB). For force f on Cβ:
+// f_b = k2·f + k1·(c × f), f_c = k3·f + k1·(f × b)
+// F_N = -f_b, F_CA = f + f_b - f_c, F_C = f_c
+func addVirtualCBForce(res *parser.Residue, f Vector3, forces map[int]Vector3) {
+	n := Vector3{X: res.N.X, Y: res.N.Y, Z: res.N.Z}
+	ca := Vector3{X: res.CA.X, Y: res.CA.Y, Z: res.CA.Z}
+	c := Vector3{X: res.C.X, Y: res.C.Y, Z: res.C.Z}
+
+	b := ca.Sub(n)
+	cc := c.Sub(ca)
+
+	fb := f.Mul(virtualCBCoeffB).Add(crossVec(cc, f).Mul(virtualCBCoeffA))
+	fc := f.Mul(virtualCBCoeffC).Add(crossVec(f, b).Mul(virtualCBCoeffA))
+
+	forces[res.N.Serial] = forces[res.N.Serial].Sub(fb)
+	forces[res.CA.Serial] = forces[res.CA.Serial].Add(f).Add(fb).Sub(fc)
+	forces[res.C.Serial] = forces[res.C.Serial].Add(fc)
+}
+
+// isGlycine accepts three-letter (PDB) and one-letter (built) residue names
+func isGlycine(name string) bool {
+	return name == "GLY" || name == "G"
+}
+
+// residueSidechainAtoms maps residue index → side-chain atoms by name
+func residueSidechainAtoms(protein *parser.Protein) map[int]map[string]*parser.Atom {
+	type key struct {
+		chain string
+		seq   int
+	}
+	index := make(map[key]int, len(protein.Residues))
+	for i, res := range protein.Residues {
+		index[key{res.ChainID, res.SeqNum}] = i
+	}
+
+	atoms := make(map[int]map[string]*parser.Atom)
+	for _, atom := range protein.Atoms {
+		if isBackboneName(atom.Name) {
+			continue
+		}
+		i, ok := index[key{atom.ChainID, atom.ResSeq}]
+		if !ok {
+			continue
+		}
+		if atoms[i] == nil {
+			atoms[i] = make(map[string]*parser.Atom)
+		}
+		if atoms[i][atom.Name] == nil { // Keep first alternate location
+			atoms[i][atom.Name] = atom
+		}
+	}
+
+	return atoms
+}
+
+// firstAtom returns the first present atom among names
+func firstAtom(atoms map[string]*parser.Atom, names []string) *parser.Atom {
+	for _, name := range names {
+		if a := atoms[name]; a != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// isBackboneName reports backbone heavy atoms and backbone hydrogens
+func isBackboneName(name string) bool {
+	switch name {
+	case "N", "CA", "C", "O", "OXT", "H", "HN", "HA":
+		return true
+	}
+	return false
+}
+
+// amberAtomType assigns the AMBER type used for torsion lookup
+//
+// BIOCHEMIST: Backbone N/CA/C/O are N/CT/C/O; side-chain types follow the
+// parm99 residue libraries for the atoms that define χ1 and χ2
+func amberAtomType(resName, atomName string) string {
+	switch atomName {
+	case "N":
+		return "N"
+	case "CA", "CB":
+		return "CT"
+	case "C":
+		return "C"
+	case "O", "OXT":
+		return "O"
+	case "SG":
+		if resName == "CYS" || resName == "C" {
+			return "SH"
+		}
+		return "S"
+	case "SD":
+		return "S"
+	case "OG", "OG1":
+		return "OH"
+	case "ND1":
+		return "NA"
+	case "OD1":
+		return "O"
+	}
+
+	// γ/δ carbons: aromatic rings are CA (sp2), carboxyl/amide C are C
+	switch resName {
+	case "PHE", "F", "TYR", "Y", "TRP", "W", "HIS", "H":
+		if atomName == "CG" || atomName == "CD1" {
+			return "CA"
+		}
+	case "ASP", "D", "ASN", "N":
+		if atomName == "CG" {
+			return "C"
+		}
+	case "GLU", "E", "GLN", "Q":
+		if atomName == "CD" {
+			return "C"
+		}
+	}
+
+	return "CT"
+}
+
+// torsionAngle measures θ(a, b, c, d) in radians, IUPAC sign convention
+// (right-handed α-helix φ ≈ -57°), matching dihedralGradient
+func torsionAngle(atoms [4]*parser.Atom) float64 {
+	pos := func(a *parser.Atom) Vector3 { return Vector3{X: a.X, Y: a.Y, Z: a.Z} }
+	b1 := pos(atoms[1]).Sub(pos(atoms[0]))
+	b2 := pos(atoms[2]).Sub(pos(atoms[1]))
+	b3 := pos(atoms[3]).Sub(pos(atoms[2]))
+
+	n1 := crossVec(b1, b2)
+	n2 := crossVec(b2, b3)
+
+	return math.Atan2(b2.Magnitude()*b1.Dot(n2), n1.Dot(n2))
+}
@@ -0,0 +1,150 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// buildAlanineDipeptide builds Ala-Ala-Ala with Cβ on every residue; the
+// outer residues stand in for the ACE/NME caps and the middle residue's
+// (φ, ψ) are set to the given angles
+func buildAlanineDipeptide(phiDeg, psiDeg float64) *parser.Protein {
+	deg := math.Pi / 180.0
+	phis := []float64{-180, phiDeg, -180}
+	psis := []float64{180, psiDeg, 180}
+
+	n0 := geometry.Vector3{X: 0, Y: 0, Z: 0}
+	ca0 := geometry.Vector3{X: geometry.BondN_CA, Y: 0, Z: 0}
+	a := (180.0 - geometry.AngleN_CA_C) * deg
+	c0 := ca0.Add(geometry.Vector3{X: math.Cos(a), Y: math.Sin(a), Z: 0}.Scale(geometry.BondCA_C))
+
+	positions := [][3]geometry.Vector3{{n0, ca0, c0}}
+	for i := 1; i < 3; i++ {
+		prev := positions[i-1]
+		nPos := placeDipeptideAtom(prev[0], prev[1], prev[2], geometry.BondC_N, geometry.AngleCA_C_N*deg, psis[i-1]*deg)
+		caPos := placeDipeptideAtom(prev[1], prev[2], nPos, geometry.BondN_CA, geometry.AngleC_N_CA*deg, math.Pi)
+		cPos := placeDipeptideAtom(prev[2], nPos, caPos, geometry.BondCA_C, geometry.AngleN_CA_C*deg, phis[i]*deg)
+		positions = append(positions, [3]geometry.Vector3{nPos, caPos, cPos})
+	}
+
+	protein := &parser.Protein{Name: "alanine_dipeptide"}
+	serial := 1
+	for i, p := range positions {
+		newAtom := func(name string, pos geometry.Vector3) *parser.Atom {
+			atom := &parser.Atom{Serial: serial, Name: name, ResName: "ALA", ChainID: "A",
+				ResSeq: i + 1, X: pos.X, Y: pos.Y, Z: pos.Z, Element: name[:1]}
+			serial++
+			protein.Atoms = append(protein.Atoms, atom)
+			return atom
+		}
+
+		oPos := placeDipeptideAtom(p[0], p[1], p[2], geometry.BondC_O, geometry.AngleCA_C_O*deg, psis[i]*deg+math.Pi)
+		res := &parser.Residue{Name: "ALA", SeqNum: i + 1, ChainID: "A",
+			N: newAtom("N", p[0]), CA: newAtom("CA", p[1]), C: newAtom("C", p[2]), O: newAtom("O", oPos)}
+		cb, _ := VirtualCB(res)
+		newAtom("CB", geometry.Vector3{X: cb.X, Y: cb.Y, Z: cb.Z})
+		protein.Residues = append(protein.Residues, res)
+	}
+
+	return protein
+}
+
+// placeDipeptideAtom places d from a, b, c, bond length, angle and torsion (NeRF)
+func placeDipeptideAtom(a, b, c geometry.Vector3, bond, angle, torsion float64) geometry.Vector3 {
+	bc := c.Sub(b).Normalize()
+	nrm := b.Sub(a).Cross(bc).Normalize()
+	m := nrm.Cross(bc)
+
+	return c.Add(bc.Scale(-bond * math.Cos(angle))).
+		Add(m.Scale(bond * math.Sin(angle) * math.Cos(torsion))).
+		Add(nrm.Scale(bond * math.Sin(angle) * math.Sin(torsion)))
+}
+
+// TestAlanineDipeptideTorsionSurface checks the torsion φ/ψ surface has
+// local minima in the α and β basins and disfavors αL for L-alanine
+func TestAlanineDipeptideTorsionSurface(t *testing.T) {
+	const step = 10
+	wrap := func(x int) int { return ((x+180)%360+360)%360 - 180 }
+
+	surface := make(map[[2]int]float64)
+	for phi := -180; phi < 180; phi += step {
+		for psi := -180; psi < 180; psi += step {
+			surface[[2]int{phi, psi}] = TorsionEnergy(buildAlanineDipeptide(float64(phi), float64(psi)))
+		}
+	}
+
+	isLocalMin := func(k [2]int) bool {
+		for dPhi := -step; dPhi <= step; dPhi += step {
+			for dPsi := -step; dPsi <= step; dPsi += step {
+				if (dPhi != 0 || dPsi != 0) && surface[[2]int{wrap(k[0] + dPhi), wrap(k[1] + dPsi)}] < surface[k] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	foundAlpha, foundBeta := false, false
+	for k := range surface {
+		if !isLocalMin(k) {
+			continue
+		}
+		t.Logf("Local minimum at (φ, ψ) = (%d°, %d°): %.2f kcal/mol", k[0], k[1], surface[k])
+		if k[0] >= -110 && k[0] <= -50 && k[1] >= -70 && k[1] <= -20 {
+			foundAlpha = true
+		}
+		if k[0] >= -160 && k[0] <= -60 && k[1] >= 100 && k[1] <= 170 {
+			foundBeta = true
+		}
+	}
+
+	if !foundAlpha {
+		t.Errorf("No local minimum in the α basin")
+	}
+	if !foundBeta {
+		t.Errorf("No local minimum in the β basin")
+	}
+
+	alphaR := surface[[2]int{-60, -40}]
+	alphaL := surface[[2]int{60, 40}]
+	t.Logf("αR %.2f vs αL %.2f kcal/mol", alphaR, alphaL)
+	if alphaL-alphaR < 2.0 {
+		t.Errorf("αL (%.2f) should be well above αR (%.2f) for L-alanine", alphaL, alphaR)
+	}
+}
+
+// TestTorsionVirtualCB checks a backbone-only model scores like one with Cβ
+func TestTorsionVirtualCB(t *testing.T) {
+	withCB := buildAlanineDipeptide(-60, -40)
+
+	backboneOnly := buildAlanineDipeptide(-60, -40)
+	atoms := backboneOnly.Atoms[:0]
+	for _, atom := range backboneOnly.Atoms {
+		if atom.Name != "CB" {
+			atoms = append(atoms, atom)
+		}
+	}
+	backboneOnly.Atoms = atoms
+
+	e1, e2 := TorsionEnergy(withCB), TorsionEnergy(backboneOnly)
+	t.Logf("Torsion energy: explicit Cβ %.4f, virtual Cβ %.4f", e1, e2)
+	if math.Abs(e1-e2) > 1e-9 {
+		t.Errorf("Virtual Cβ energy %.6f differs from explicit %.6f", e2, e1)
+	}
+
+	// Glycine has no Cβ: φ' and ψ' must vanish, making the surface achiral
+	left, right := buildAlanineDipeptide(60, 40), buildAlanineDipeptide(-60, -40)
+	for _, p := range []*parser.Protein{left, right} {
+		p.Atoms = p.Atoms[:0]
+		for _, res := range p.Residues {
+			res.Name = "GLY"
+			p.Atoms = append(p.Atoms, res.N, res.CA, res.C, res.O)
+		}
+	}
+	if math.Abs(TorsionEnergy(left)-TorsionEnergy(right)) > 1e-9 {
+		t.Errorf("Glycine torsion energy should be symmetric under (φ, ψ) → (-φ, -ψ)")
+	}
+}
@@ -0,0 +1,89 @@
+// Package physics - Physical constants and energy units
+//
+// The force field is parameterized in kcal/mol (AMBER/CHARMM convention),
+// while GROMACS, OpenMM and many analysis tools report kJ/mol. Energies are
+// always computed in kcal/mol; EnergyUnits converts what is reported, and
+// Boltzmann gives k_B in the same unit so Boltzmann factors exp(-E/k_BT)
+// do not depend on the choice.
+//
+// PHYSICIST: 1 kcal = 4.184 kJ exactly (thermochemical calorie);
+// k_B per mole (the gas constant R) = 0.001987 kcal/(mol·K) =
+// 0.008314 kJ/(mol·K)
+//
+// CITATION:
+// Tiesinga, E., et al. (2021). "CODATA recommended values of the fundamental
+// physical constants: 2018." Rev. Mod. Phys. 93(2): 025010.
+package physics
+
+// Physical constants
+const (
+	// BoltzmannKcal is k_B in kcal/(mol·K), the value used by every
+	// Metropolis criterion in the samplers and optimizers
+	BoltzmannKcal = 0.001987
+
+	// KcalToKJ converts kcal/mol to kJ/mol
+	KcalToKJ = 4.184
+
+	// BoltzmannKJ is k_B in kJ/(mol·K)
+	BoltzmannKJ = BoltzmannKcal * KcalToKJ
+)
+
+// EnergyUnits selects the unit energies are reported in
+type EnergyUnits int
+
+const (
+	KcalPerMol EnergyUnits = iota // kcal/mol (default)
+	KJPerMol                      // kJ/mol
+)
+
+// String returns the unit symbol
+func (u EnergyUnits) String() string {
+	if u == KJPerMol {
+		return "kJ/mol"
+	}
+	return "kcal/mol"
+}
+
+// FromKcal returns the factor converting kcal/mol into u
+func (u EnergyUnits) FromKcal() float64 {
+	if u == KJPerMol {
+		return KcalToKJ
+	}
+	return 1.0
+}
+
+// Boltzmann returns k_B in u per kelvin
+func (u EnergyUnits) Boltzmann() float64 {
+	return BoltzmannKcal * u.FromKcal()
+}
+
+// KT returns the thermal energy k_B·T in u at temperature (Kelvin)
+func (u EnergyUnits) KT(temperature float64) float64 {
+	return u.Boltzmann() * temperature
+}
+
+// InUnits converts energy components computed in kcal/mol into u
+func (c EnergyComponents) InUnits(u EnergyUnits) EnergyComponents {
+	if f := u.FromKcal(); f != 1.0 {
+		return c.scaled(f)
+	}
+	return c
+}
+
+// scaled multiplies every component by f
+func (c EnergyComponents) scaled(f float64) EnergyComponents {
+	return EnergyComponents{
+		Bond:          c.Bond * f,
+		Angle:         c.Angle * f,
+		Dihedral:      c.Dihedral * f,
+		VanDerWaals:   c.VanDerWaals * f,
+		Electrostatic: c.Electrostatic * f,
+		Disulfide:     c.Disulfide * f,
+		CMAP:          c.CMAP * f,
+		HBond:         c.HBond * f,
+		Contact:       c.Contact * f,
+		Restraint:     c.Restraint * f,
+		Improper:      c.Improper * f,
+		Total:         c.Total * f,
+	}
+}
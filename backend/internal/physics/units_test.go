@@ -0,0 +1,62 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEnergyUnits checks that kJ/mol energies, forces and per-residue
+// decompositions are the kcal/mol values times 4.184, and that k_B·T is
+// unit-consistent so Boltzmann factors do not change
+func TestEnergyUnits(t *testing.T) {
+	if kt := KJPerMol.KT(300) / KcalPerMol.KT(300); math.Abs(kt-KcalToKJ) > 1e-12 {
+		t.Errorf("kT ratio %.6f, want %.3f", kt, KcalToKJ)
+	}
+	t.Logf("kT(300 K) = %.4f %s = %.4f %s", KcalPerMol.KT(300), KcalPerMol, KJPerMol.KT(300), KJPerMol)
+
+	protein := buildUniformChain(t, 8, -57, -47)
+	kcal := DefaultEnergyConfig()
+	kj := kcal
+	kj.Units = KJPerMol
+
+	eKcal := CalculateTotalEnergyWithConfig(protein, kcal)
+	eKJ := CalculateTotalEnergyWithConfig(protein, kj)
+	t.Logf("Total: %.4f kcal/mol, %.4f kJ/mol", eKcal.Total, eKJ.Total)
+	if eKJ != eKcal.InUnits(KJPerMol) || eKJ.Total != eKcal.Total*KcalToKJ {
+		t.Errorf("kJ/mol components %+v, want %+v", eKJ, eKcal.InUnits(KJPerMol))
+	}
+
+	fKcal := CalculateForcesWithConfig(protein, kcal)
+	fKJ := CalculateForcesWithConfig(protein, kj)
+	for serial, f := range fKcal {
+		if fKJ[serial] != f.Mul(KcalToKJ) {
+			t.Fatalf("Atom %d force %v kJ/(mol·Å), want %v", serial, fKJ[serial], f.Mul(KcalToKJ))
+		}
+	}
+
+	rKcal := DecomposeEnergyPerResidue(protein, kcal)
+	rKJ := DecomposeEnergyPerResidue(protein, kj)
+	for i := range rKcal {
+		if math.Abs(rKJ[i].Total-rKcal[i].Total*KcalToKJ) > 1e-9*math.Max(1, math.Abs(rKJ[i].Total)) {
+			t.Errorf("Residue %d: %.6f kJ/mol, want %.6f", i, rKJ[i].Total, rKcal[i].Total*KcalToKJ)
+		}
+	}
+
+	// The incremental tracker reports in the same unit and keeps its running
+	// total in kcal/mol, so a proposed and accepted move agrees with a full
+	// calculation
+	trial := buildUniformChain(t, 8, -60, -45)
+	inc := NewIncrementalEnergy(protein, kj)
+	if inc.Energy() != eKJ {
+		t.Errorf("Incremental %+v, want %+v", inc.Energy(), eKJ)
+	}
+	proposed := inc.Propose(trial, false)
+	inc.Accept(trial, proposed)
+	full := CalculateTotalEnergyWithConfig(trial, kj).Total
+	if math.Abs(inc.Energy().Total-full) > 1e-6*math.Max(1, math.Abs(full)) {
+		t.Errorf("After accept %.6f kJ/mol, full calculation %.6f", inc.Energy().Total, full)
+	}
+	if drift := inc.Resync(); drift > 1e-6*math.Max(1, math.Abs(full)) {
+		t.Errorf("Resync drift %.3g kJ/mol", drift)
+	}
+}
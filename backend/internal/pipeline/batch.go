@@ -0,0 +1,230 @@
+// Package pipeline - Batch folding
+//
+// Benchmarks fold tens of proteins; FoldBatch runs RunUnifiedPipelineV2 for
+// each named sequence on a bounded worker pool. Entries are independent: an
+// error (or panic) in one is recorded against its name and the rest of the
+// batch continues.
+//
+// PHYSICIST: Each run still parallelizes its own Phase C with
+// config.MaxWorkers; total goroutines ≈ maxWorkers × config.MaxWorkers
+// ETHICIST: Failures are reported per sequence, never silently dropped
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchReport holds per-sequence results and aggregate statistics
+type BatchReport struct {
+	Results map[string]*UnifiedPipelineV2Result // Successful runs by name
+	Errors  map[string]error                    // Failed runs by name
+
+	NumSequences int
+	NumSucceeded int
+	NumFailed    int
+	SuccessRate  float64 // NumSucceeded / NumSequences
+
+	WallTimeSeconds    float64 // Elapsed time for the whole batch
+	TotalFoldSeconds   float64 // Sum of per-sequence pipeline times
+	MeanFoldSeconds    float64 // Mean per successful sequence
+	MeanFinalEnergy    float64 // Mean over successful sequences (kcal/mol)
+	MeanSamplesPerFold float64
+}
+
+// Err returns nil if every sequence folded, otherwise one error naming each
+// failure (sorted by name)
+func (r *BatchReport) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.Errors))
+	for name := range r.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = fmt.Errorf("%s: %w", name, r.Errors[name])
+	}
+	return fmt.Errorf("%d of %d sequences failed: %w", r.NumFailed, r.NumSequences, errors.Join(errs...))
+}
+
+// FoldBatch folds every sequence concurrently on up to maxWorkers goroutines
+//
+// Returns the successful results by name; the error is non-nil if any
+// sequence failed (see BatchReport.Err). Use FoldBatchWithReport for timing
+// and per-entry errors.
+func FoldBatch(sequences map[string]string, config UnifiedPipelineV2Config, maxWorkers int) (map[string]*UnifiedPipelineV2Result, error) {
+	report := FoldBatchWithReport(sequences, config, maxWorkers)
+	return report.Results, report.Err()
+}
+
+// FoldBatchWithReport is FoldBatch returning the full BatchReport
+//
+// config is applied to every sequence with Sequence replaced. Output paths
+// are made per-entry: CheckpointDir gets a sub-directory per name and
+// OutputPDBPath/OutputEnsemblePath get "_<name>" before the extension, with
+// unsafe characters replaced and the batch index appended to names that
+// would otherwise share a file (see batchFileTags).
+// maxWorkers <= 0 uses runtime.NumCPU().
+func FoldBatchWithReport(sequences map[string]string, config UnifiedPipelineV2Config, maxWorkers int) *BatchReport {
+	startTime := time.Now()
+
+	report := &BatchReport{
+		Results:      make(map[string]*UnifiedPipelineV2Result, len(sequences)),
+		Errors:       make(map[string]error),
+		NumSequences: len(sequences),
+	}
+	if len(sequences) == 0 {
+		return report
+	}
+
+	// Sorted names: deterministic dispatch order
+	names := make([]string, 0, len(sequences))
+	for name := range sequences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tags := batchFileTags(names)
+
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+	if maxWorkers > len(names) {
+		maxWorkers = len(names)
+	}
+
+	var mu sync.Mutex
+	jobs := make(chan string)
+
+	var wg sync.WaitGroup
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				result, err := foldBatchEntry(sequences[name], tags[name], config)
+
+				mu.Lock()
+				if err != nil {
+					report.Errors[name] = err
+				} else {
+					report.Results[name] = result
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, name := range names {
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+
+	report.NumSucceeded = len(report.Results)
+	report.NumFailed = len(report.Errors)
+	report.SuccessRate = float64(report.NumSucceeded) / float64(report.NumSequences)
+	report.WallTimeSeconds = time.Since(startTime).Seconds()
+
+	for _, result := range report.Results {
+		report.TotalFoldSeconds += result.TotalTimeSeconds
+		report.MeanFinalEnergy += result.FinalEnergy
+		report.MeanSamplesPerFold += float64(result.TotalSamplesGenerated)
+	}
+	if report.NumSucceeded > 0 {
+		n := float64(report.NumSucceeded)
+		report.MeanFoldSeconds = report.TotalFoldSeconds / n
+		report.MeanFinalEnergy /= n
+		report.MeanSamplesPerFold /= n
+	}
+
+	return report
+}
+
+// foldBatchEntry runs the pipeline for one entry, writing its outputs under
+// tag and converting panics to errors
+func foldBatchEntry(sequence, tag string, config UnifiedPipelineV2Config) (result *UnifiedPipelineV2Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("pipeline panicked: %v", r)
+		}
+	}()
+
+	if sequence == "" {
+		return nil, fmt.Errorf("empty sequence")
+	}
+
+	config.Sequence = sequence
+	if config.CheckpointDir != "" {
+		config.CheckpointDir = filepath.Join(config.CheckpointDir, tag)
+	}
+	config.OutputPDBPath = batchOutputPath(config.OutputPDBPath, tag)
+	config.OutputEnsemblePath = batchOutputPath(config.OutputEnsemblePath, tag)
+
+	return RunUnifiedPipelineV2(config, nil)
+}
+
+// batchUnsafeChars matches characters replaced in per-entry file names
+var batchUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// batchFileTag turns an entry name into a file-name-safe tag
+func batchFileTag(name string) string {
+	tag := batchUnsafeChars.ReplaceAllString(name, "_")
+	if tag == "" || strings.Trim(tag, ".") == "" {
+		tag = "_"
+	}
+	return tag
+}
+
+// batchFileTags assigns each of the sorted names a distinct file tag
+//
+// Names whose sanitized tags collide ("a/b" and "a:b" both give "a_b";
+// compared case-insensitively for case-insensitive file systems) get
+// "_<index>" appended, index being the name's position in names, so no
+// entry overwrites another's output.
+func batchFileTags(names []string) map[string]string {
+	count := make(map[string]int, len(names))
+	for _, name := range names {
+		count[strings.ToLower(batchFileTag(name))]++
+	}
+
+	tags := make(map[string]string, len(names))
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		if tag := batchFileTag(name); count[strings.ToLower(tag)] == 1 {
+			tags[name] = tag
+			used[strings.ToLower(tag)] = true
+		}
+	}
+	for i, name := range names {
+		if _, ok := tags[name]; ok {
+			continue
+		}
+		tag := fmt.Sprintf("%s_%d", batchFileTag(name), i)
+		for used[strings.ToLower(tag)] {
+			tag = fmt.Sprintf("%s_%d", tag, i)
+		}
+		tags[name] = tag
+		used[strings.ToLower(tag)] = true
+	}
+	return tags
+}
+
+// batchOutputPath inserts "_<tag>" before the extension ("" stays "")
+func batchOutputPath(path, tag string) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_" + tag + ext
+}
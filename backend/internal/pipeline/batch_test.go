@@ -0,0 +1,105 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFoldBatch folds three sequences concurrently and checks every result
+// comes back under its own name
+func TestFoldBatch(t *testing.T) {
+	sequences := map[string]string{
+		"helix":  "AEAAAKEAAAKA",
+		"strand": "VTVTVTV",
+		"mixed":  "GACDEFGH",
+	}
+
+	dir := t.TempDir()
+	config := DefaultUnifiedPipelineV2Config("")
+	config.MaxWorkers = 2
+	config.OutputPDBPath = filepath.Join(dir, "best.pdb")
+
+	report := FoldBatchWithReport(sequences, config, 3)
+	if err := report.Err(); err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+
+	t.Logf("Batch: %d/%d folded, wall %.2f s, summed %.2f s, mean E = %.2f kcal/mol",
+		report.NumSucceeded, report.NumSequences, report.WallTimeSeconds, report.TotalFoldSeconds, report.MeanFinalEnergy)
+
+	if report.NumSucceeded != 3 || report.SuccessRate != 1 {
+		t.Errorf("Expected 3 successes, got %d (rate %.2f)", report.NumSucceeded, report.SuccessRate)
+	}
+
+	for name, sequence := range sequences {
+		result := report.Results[name]
+		if result == nil || result.FinalStructure == nil {
+			t.Errorf("%s: missing result", name)
+			continue
+		}
+
+		var got strings.Builder
+		for _, res := range result.FinalStructure.Residues {
+			got.WriteString(res.Name)
+		}
+		if got.String() != sequence {
+			t.Errorf("%s: folded %q, want %q", name, got.String(), sequence)
+		}
+		if len(result.SecondaryStructure) != len(sequence) {
+			t.Errorf("%s: %d SS predictions for %d residues", name, len(result.SecondaryStructure), len(sequence))
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "best_"+name+".pdb")); err != nil {
+			t.Errorf("%s: per-entry PDB not written: %v", name, err)
+		}
+	}
+}
+
+// TestFoldBatchRecordsFailures checks one bad entry does not abort the batch
+func TestFoldBatchRecordsFailures(t *testing.T) {
+	sequences := map[string]string{
+		"good":  "ACDEFG",
+		"empty": "",
+	}
+
+	config := DefaultUnifiedPipelineV2Config("")
+	results, err := FoldBatch(sequences, config, 2)
+	if err == nil {
+		t.Fatal("Expected an error for the empty sequence")
+	}
+	if !strings.Contains(err.Error(), "empty") {
+		t.Errorf("Error does not name the failed entry: %v", err)
+	}
+	if results["good"] == nil {
+		t.Error("Good entry missing from results")
+	}
+	if _, ok := results["empty"]; ok {
+		t.Error("Failed entry should not have a result")
+	}
+	t.Logf("Batch error: %v", err)
+}
+
+// TestBatchFileTagsDistinct checks names that sanitize to the same tag get
+// distinct ones while safe, unique names keep theirs
+func TestBatchFileTagsDistinct(t *testing.T) {
+	names := []string{"a/b", "a:b", "a_b_1", "A_B", "helix"}
+	tags := batchFileTags(names)
+	t.Logf("Tags: %v", tags)
+
+	seen := make(map[string]string)
+	for _, name := range names {
+		tag := tags[name]
+		if tag == "" {
+			t.Fatalf("%q has no tag", name)
+		}
+		if other, ok := seen[strings.ToLower(tag)]; ok {
+			t.Errorf("%q and %q share tag %q", name, other, tag)
+		}
+		seen[strings.ToLower(tag)] = name
+	}
+	if tags["helix"] != "helix" || tags["a_b_1"] != "a_b_1" {
+		t.Errorf("Unique names renamed: helix → %q, a_b_1 → %q", tags["helix"], tags["a_b_1"])
+	}
+}
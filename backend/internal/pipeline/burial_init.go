@@ -0,0 +1,160 @@
+// Package pipeline - Burial-biased initialization
+//
+// An SS-only start is a set of helices and strands joined by fully extended
+// coils - every residue is solvent exposed. With a burial profile (see
+// prediction.PredictBurialProfile) the coil residues are re-chosen from a
+// few Ramachandran basins so that predicted-buried residues gather around
+// a common centre: a nascent core for sampling to start from.
+//
+// BIOCHEMIST: Only coil (φ, ψ) change - predicted helices and strands keep
+// their geometry; loops are what bring core elements together
+// MATHEMATICIAN: Greedy coordinate descent on the burial-weighted radius of
+// gyration, with a CA-CA clash penalty so the core does not collapse
+// through itself
+// ETHICIST: Deterministic - same sequence, same starting structure
+package pipeline
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// Burial compaction parameters
+const (
+	burialCompactionPasses = 2    // Coordinate descent sweeps over the coil residues
+	burialClashDistance    = 4.0  // CA-CA distance (Å) below which non-local residues clash
+	burialClashWeight      = 10.0 // Penalty per Å² of clash overlap
+	burialClashMinSep      = 3    // Minimum sequence separation for a clash
+)
+
+// burialLoopBasins are the coil (φ, ψ) choices, in degrees: β, PPII, αR and
+// the bridge region
+var burialLoopBasins = [][2]float64{
+	{-120, 120},
+	{-65, 145},
+	{-60, -45},
+	{-90, 0},
+}
+
+// initializeWithBurial builds the SS-based initial structure, compacting
+// the coil residues around the predicted-buried residues when burial is
+// non-nil (one probability per residue)
+func initializeWithBurial(sequence string, ssPred []prediction.SecondaryStructurePrediction, burial []float64) *parser.Protein {
+	angles := ssInitialAngles(sequence, ssPred)
+	if len(burial) == len(sequence) {
+		angles = compactByBurial(sequence, angles, ssPred, burial)
+	}
+
+	// Build 3D structure from angles
+	// WAVE 11.1: Use quaternion-based coordinate builder (NOVEL!)
+	structure, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		// Fallback to simple builder if quaternion method fails
+		return initializeFallback(sequence)
+	}
+
+	return structure
+}
+
+// compactByBurial re-chooses coil (φ, ψ) from burialLoopBasins to minimize
+// burialCompactness, returning the improved angles
+func compactByBurial(sequence string, angles []geometry.RamachandranAngles,
+	ssPred []prediction.SecondaryStructurePrediction, burial []float64) []geometry.RamachandranAngles {
+
+	var coil []int
+	for i := range angles {
+		if i >= len(ssPred) || (ssPred[i].PredictedType != prediction.AlphaHelix &&
+			ssPred[i].PredictedType != prediction.BetaSheet) {
+			coil = append(coil, i)
+		}
+	}
+	if len(coil) == 0 {
+		return angles
+	}
+
+	best := make([]geometry.RamachandranAngles, len(angles))
+	copy(best, angles)
+
+	evaluate := func(trial []geometry.RamachandranAngles) float64 {
+		protein, err := geometry.BuildProteinFromAngles(sequence, trial)
+		if err != nil {
+			return math.Inf(1)
+		}
+		return burialCompactness(protein, burial)
+	}
+	bestScore := evaluate(best)
+
+	trial := make([]geometry.RamachandranAngles, len(angles))
+	for pass := 0; pass < burialCompactionPasses; pass++ {
+		improved := false
+		for _, i := range coil {
+			for _, basin := range burialLoopBasins {
+				copy(trial, best)
+				trial[i] = geometry.RamachandranAngles{
+					Phi: basin[0] * math.Pi / 180.0,
+					Psi: basin[1] * math.Pi / 180.0,
+				}
+				if score := evaluate(trial); score < bestScore {
+					bestScore = score
+					best[i] = trial[i]
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	return best
+}
+
+// burialCompactness returns the burial-weighted radius of gyration² of the
+// CA atoms (Å²) plus the clash penalty
+//
+// R² = Σ p_i |r_i - c|² / Σ p_i with c = Σ p_i r_i / Σ p_i, so buried
+// residues are drawn together while exposed ones are free.
+func burialCompactness(protein *parser.Protein, burial []float64) float64 {
+	var cas []*parser.Atom
+	var weights []float64
+	for i, res := range protein.Residues {
+		if i < len(burial) && res != nil && res.CA != nil {
+			cas = append(cas, res.CA)
+			weights = append(weights, burial[i])
+		}
+	}
+
+	var sumW, cx, cy, cz float64
+	for k, ca := range cas {
+		sumW += weights[k]
+		cx += weights[k] * ca.X
+		cy += weights[k] * ca.Y
+		cz += weights[k] * ca.Z
+	}
+	if sumW == 0 {
+		return 0
+	}
+	cx, cy, cz = cx/sumW, cy/sumW, cz/sumW
+
+	spread := 0.0
+	for k, ca := range cas {
+		dx, dy, dz := ca.X-cx, ca.Y-cy, ca.Z-cz
+		spread += weights[k] * (dx*dx + dy*dy + dz*dz)
+	}
+
+	clash := 0.0
+	for a := 0; a < len(cas); a++ {
+		for b := a + burialClashMinSep; b < len(cas); b++ {
+			dx, dy, dz := cas[a].X-cas[b].X, cas[a].Y-cas[b].Y, cas[a].Z-cas[b].Z
+			if d := math.Sqrt(dx*dx + dy*dy + dz*dz); d < burialClashDistance {
+				overlap := burialClashDistance - d
+				clash += burialClashWeight * overlap * overlap
+			}
+		}
+	}
+
+	return spread/sumW + clash
+}
@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// TestInitializeWithBurial checks two hydrophobic strands joined by a
+// charged loop start closer together with the burial bias than without
+func TestInitializeWithBurial(t *testing.T) {
+	strand := "VIVLVIV"
+	loop := "DGKEDGKRD"
+	sequence := strand + loop + strand
+
+	ssPred := make([]prediction.SecondaryStructurePrediction, len(sequence))
+	for i := range ssPred {
+		ssPred[i] = prediction.SecondaryStructurePrediction{Position: i, PredictedType: prediction.Coil}
+		if i < len(strand) || i >= len(strand)+len(loop) {
+			ssPred[i].PredictedType = prediction.BetaSheet
+		}
+	}
+
+	burial := prediction.PredictBurialProfile(sequence)
+	plain := initializeWithBurial(sequence, ssPred, nil)
+	biased := initializeWithBurial(sequence, ssPred, burial)
+
+	if len(biased.Residues) != len(sequence) {
+		t.Fatalf("Residue count %d, want %d", len(biased.Residues), len(sequence))
+	}
+
+	plainScore := burialCompactness(plain, burial)
+	biasedScore := burialCompactness(biased, burial)
+	t.Logf("Burial-weighted compactness: plain %.1f Å², biased %.1f Å²", plainScore, biasedScore)
+
+	if biasedScore >= 0.5*plainScore {
+		t.Errorf("Burial bias should at least halve compactness: %.1f vs %.1f", biasedScore, plainScore)
+	}
+
+	// Strand geometry is untouched: only coil angles may change
+	plainAngles := ssInitialAngles(sequence, ssPred)
+	biasedAngles := compactByBurial(sequence, ssInitialAngles(sequence, ssPred), ssPred, burial)
+	for i := range plainAngles {
+		if ssPred[i].PredictedType == prediction.BetaSheet && biasedAngles[i] != plainAngles[i] {
+			t.Errorf("Strand residue %d angles changed", i)
+		}
+	}
+}
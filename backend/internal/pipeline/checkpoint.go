@@ -0,0 +1,265 @@
+// Package pipeline - Checkpoint and resume
+//
+// A long benchmark run that crashes in Phase C should not have to sample
+// again. With UnifiedPipelineV2Config.CheckpointDir set, the pipeline writes:
+//
+//	checkpoint.json    phase, config, predictions (and scores after Phase C)
+//	ensemble.pdb       sampled ensemble (multi-MODEL)
+//	optimized.pdb      optimized ensemble (after Phase C)
+//	experimental.pdb   reference structure, if one was given
+//
+// checkpoint.json is written last and replaced atomically, so it always
+// names a phase whose structure files are complete.
+//
+// PHYSICIST: PDB stores 3 decimals, so after each checkpoint the pipeline
+// continues from the structures read back from disk - a fresh run and a
+// resumed run then optimize identical coordinates
+// ETHICIST: Resumed results are the same results, not approximations
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// Checkpoint phases
+const (
+	CheckpointPhaseSampled   = "sampled"
+	CheckpointPhaseOptimized = "optimized"
+)
+
+// Checkpoint file names inside CheckpointDir
+const (
+	checkpointFile      = "checkpoint.json"
+	checkpointEnsemble  = "ensemble.pdb"
+	checkpointOptimized = "optimized.pdb"
+	checkpointReference = "experimental.pdb"
+	checkpointVersion   = 1
+)
+
+// checkpointWritten is called after each checkpoint is committed (test hook)
+var checkpointWritten = func(dir, phase string) {}
+
+// pipelineCheckpoint is the JSON part of a checkpoint
+type pipelineCheckpoint struct {
+	Version int
+	Phase   string
+
+	Config             UnifiedPipelineV2Config
+	SecondaryStructure []prediction.SecondaryStructurePrediction
+	ContactMap         []prediction.ContactPrediction
+	BurialProfile      []float64
+	HasExperimental    bool
+
+	TotalSamplesGenerated int
+
+	// Set once Phase C has completed
+	BestIndex          int
+	BestEnergy         float64
+	SuccessRate        float64
+	Scores             []checkpointScore
+	OptimizationResult *optimization.OptimizationResult
+}
+
+// checkpointScore records one ensemble member's Phase C outcome
+type checkpointScore struct {
+	Energy     float64
+	SkipReason string
+}
+
+// ResumeUnifiedPipelineV2 continues a pipeline run from its checkpoint
+//
+// A "sampled" checkpoint re-runs Phase C on the saved ensemble, then Phase D;
+// an "optimized" checkpoint runs Phase D only. The saved configuration is
+// used, with CheckpointDir set to checkpointDir.
+func ResumeUnifiedPipelineV2(checkpointDir string) (*UnifiedPipelineV2Result, error) {
+	return ResumeUnifiedPipelineV2Ctx(context.Background(), checkpointDir)
+}
+
+// ResumeUnifiedPipelineV2Ctx is ResumeUnifiedPipelineV2 with cancellation
+// (see RunUnifiedPipelineV2Ctx)
+func ResumeUnifiedPipelineV2Ctx(ctx context.Context, checkpointDir string) (*UnifiedPipelineV2Result, error) {
+	startTime := time.Now()
+
+	cp, err := readCheckpoint(checkpointDir)
+	if err != nil {
+		return nil, err
+	}
+
+	config := cp.Config
+	config.CheckpointDir = checkpointDir
+
+	var experimental *parser.Protein
+	if cp.HasExperimental {
+		experimental, err = parser.ParsePDB(filepath.Join(checkpointDir, checkpointReference))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint reference: %w", err)
+		}
+	}
+
+	run := &pipelineRun{
+		config:       config,
+		experimental: experimental,
+		result: &UnifiedPipelineV2Result{
+			SecondaryStructure:    cp.SecondaryStructure,
+			ContactMap:            cp.ContactMap,
+			TotalSamplesGenerated: cp.TotalSamplesGenerated,
+		},
+		ssPred:    cp.SecondaryStructure,
+		contacts:  cp.ContactMap,
+		startTime: startTime,
+	}
+
+	if config.Verbose {
+		fmt.Printf("=== FoldVedic.ai Unified Pipeline v2.0 (resumed: %s) ===\n\n", cp.Phase)
+	}
+
+	switch cp.Phase {
+	case CheckpointPhaseSampled:
+		ensemble, err := parser.ParsePDBModels(filepath.Join(checkpointDir, checkpointEnsemble))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint ensemble: %w", err)
+		}
+		return run.optimizeAndSelect(ctx, ensemble)
+
+	case CheckpointPhaseOptimized:
+		ensemble, err := parser.ParsePDBModels(filepath.Join(checkpointDir, checkpointOptimized))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint ensemble: %w", err)
+		}
+		if cp.BestIndex < 0 || cp.BestIndex >= len(ensemble) {
+			return nil, fmt.Errorf("checkpoint best index %d outside ensemble of %d", cp.BestIndex, len(ensemble))
+		}
+		run.result.SuccessRate = cp.SuccessRate
+		var usable []bool
+		if len(cp.Scores) == len(ensemble) {
+			usable = make([]bool, len(ensemble))
+			for i, score := range cp.Scores {
+				usable[i] = score.SkipReason == ""
+			}
+		}
+		consensusIndex, err := findConsensus(ensemble, usable, run.config.ConsensusRMSDCutoff)
+		if err != nil {
+			consensusIndex = -1
+		}
+		return run.selectAndValidate(ensemble, ensemble[cp.BestIndex], cp.BestEnergy, cp.OptimizationResult, consensusIndex, nil)
+
+	default:
+		return nil, fmt.Errorf("unknown checkpoint phase %q", cp.Phase)
+	}
+}
+
+// writeSamplingCheckpoint saves the ensemble and predictions, returning the
+// ensemble as read back from disk
+func (run *pipelineRun) writeSamplingCheckpoint(ensemble []*parser.Protein) ([]*parser.Protein, error) {
+	dir := run.config.CheckpointDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	path := filepath.Join(dir, checkpointEnsemble)
+	remarks := []string{fmt.Sprintf("FOLDVEDIC CHECKPOINT: %d SAMPLED MODELS", len(ensemble))}
+	if err := parser.WritePDBModels(ensemble, path, remarks); err != nil {
+		return nil, err
+	}
+	if run.experimental != nil {
+		if err := parser.WritePDB(run.experimental, filepath.Join(dir, checkpointReference), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	cp := run.checkpoint(CheckpointPhaseSampled)
+	if err := writeCheckpoint(dir, cp); err != nil {
+		return nil, err
+	}
+	checkpointWritten(dir, CheckpointPhaseSampled)
+
+	return parser.ParsePDBModels(path)
+}
+
+// writeOptimizationCheckpoint saves the optimized ensemble and scores,
+// returning the ensemble as read back from disk
+func (run *pipelineRun) writeOptimizationCheckpoint(ensemble []*parser.Protein, candidates []ensembleCandidate,
+	bestIndex int, bestEnergy float64, bestOptResult *optimization.OptimizationResult) ([]*parser.Protein, error) {
+
+	dir := run.config.CheckpointDir
+	path := filepath.Join(dir, checkpointOptimized)
+	remarks := []string{fmt.Sprintf("FOLDVEDIC CHECKPOINT: %d OPTIMIZED MODELS, BEST MODEL %d", len(ensemble), bestIndex+1)}
+	if err := parser.WritePDBModels(ensemble, path, remarks); err != nil {
+		return nil, err
+	}
+
+	cp := run.checkpoint(CheckpointPhaseOptimized)
+	cp.BestIndex = bestIndex
+	cp.BestEnergy = bestEnergy
+	cp.SuccessRate = run.result.SuccessRate
+	cp.OptimizationResult = bestOptResult
+	cp.Scores = make([]checkpointScore, len(candidates))
+	for i, cand := range candidates {
+		cp.Scores[i] = checkpointScore{Energy: cand.energy, SkipReason: cand.skipReason}
+	}
+
+	if err := writeCheckpoint(dir, cp); err != nil {
+		return nil, err
+	}
+	checkpointWritten(dir, CheckpointPhaseOptimized)
+
+	return parser.ParsePDBModels(path)
+}
+
+// checkpoint fills the fields shared by every phase
+func (run *pipelineRun) checkpoint(phase string) *pipelineCheckpoint {
+	return &pipelineCheckpoint{
+		Version:               checkpointVersion,
+		Phase:                 phase,
+		Config:                run.config,
+		SecondaryStructure:    run.ssPred,
+		ContactMap:            run.contacts,
+		BurialProfile:         run.result.BurialProfile,
+		HasExperimental:       run.experimental != nil,
+		TotalSamplesGenerated: run.result.TotalSamplesGenerated,
+		BestIndex:             -1,
+	}
+}
+
+// writeCheckpoint replaces checkpoint.json atomically (write, then rename)
+func writeCheckpoint(dir string, cp *pipelineCheckpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := filepath.Join(dir, checkpointFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, checkpointFile)); err != nil {
+		return fmt.Errorf("failed to commit checkpoint: %w", err)
+	}
+	return nil
+}
+
+// readCheckpoint loads and checks checkpoint.json
+func readCheckpoint(dir string) (*pipelineCheckpoint, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp pipelineCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d (want %d)", cp.Version, checkpointVersion)
+	}
+	return &cp, nil
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// sameStructure reports the first atom whose coordinates differ, or -1
+func sameStructure(a, b *parser.Protein) int {
+	if len(a.Atoms) != len(b.Atoms) {
+		return 0
+	}
+	for i, atom := range a.Atoms {
+		other := b.Atoms[i]
+		if atom.X != other.X || atom.Y != other.Y || atom.Z != other.Z {
+			return i
+		}
+	}
+	return -1
+}
+
+// TestResumeAfterSampling interrupts a run right after the sampling
+// checkpoint, resumes it, and checks the result matches an uninterrupted run
+func TestResumeAfterSampling(t *testing.T) {
+	sequence := "ACDEFGHIK"
+
+	// Uninterrupted reference run
+	full := DefaultUnifiedPipelineV2Config(sequence)
+	full.CheckpointDir = filepath.Join(t.TempDir(), "full")
+	want, err := RunUnifiedPipelineV2(full, nil)
+	if err != nil {
+		t.Fatalf("Uninterrupted pipeline failed: %v", err)
+	}
+
+	// Interrupted run: cancel as soon as the sampling checkpoint is committed
+	interrupted := DefaultUnifiedPipelineV2Config(sequence)
+	interrupted.CheckpointDir = filepath.Join(t.TempDir(), "interrupted")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	original := checkpointWritten
+	defer func() { checkpointWritten = original }()
+	checkpointWritten = func(dir, phase string) {
+		if phase == CheckpointPhaseSampled {
+			cancel()
+		}
+	}
+
+	partial, err := RunUnifiedPipelineV2Ctx(ctx, interrupted, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if partial != nil && partial.FinalStructure != nil {
+		t.Fatal("Interrupted run should not select a structure")
+	}
+	checkpointWritten = original
+
+	cp, err := readCheckpoint(interrupted.CheckpointDir)
+	if err != nil {
+		t.Fatalf("Failed to read checkpoint: %v", err)
+	}
+	if cp.Phase != CheckpointPhaseSampled {
+		t.Fatalf("Checkpoint phase %q, want %q", cp.Phase, CheckpointPhaseSampled)
+	}
+
+	got, err := ResumeUnifiedPipelineV2(interrupted.CheckpointDir)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	t.Logf("Uninterrupted: E = %.4f, %d samples", want.FinalEnergy, want.TotalSamplesGenerated)
+	t.Logf("Resumed:       E = %.4f, %d samples", got.FinalEnergy, got.TotalSamplesGenerated)
+
+	if got.FinalEnergy != want.FinalEnergy {
+		t.Errorf("Final energy differs: resumed %.6f vs uninterrupted %.6f", got.FinalEnergy, want.FinalEnergy)
+	}
+	if got.TotalSamplesGenerated != want.TotalSamplesGenerated || got.SuccessRate != want.SuccessRate {
+		t.Errorf("Statistics differ: %d/%.3f vs %d/%.3f", got.TotalSamplesGenerated, got.SuccessRate,
+			want.TotalSamplesGenerated, want.SuccessRate)
+	}
+	if i := sameStructure(got.FinalStructure, want.FinalStructure); i >= 0 {
+		t.Errorf("Final structures differ at atom %d", i)
+	}
+
+	// The completed run left an optimization checkpoint: resuming it only
+	// re-runs selection and must give the same answer again
+	again, err := ResumeUnifiedPipelineV2(full.CheckpointDir)
+	if err != nil {
+		t.Fatalf("Resume from optimization checkpoint failed: %v", err)
+	}
+	if again.FinalEnergy != want.FinalEnergy || sameStructure(again.FinalStructure, want.FinalStructure) >= 0 {
+		t.Errorf("Resume from optimization checkpoint gave a different structure (E = %.6f)", again.FinalEnergy)
+	}
+}
+
+// TestResumeMissingCheckpoint checks resuming an empty directory fails
+func TestResumeMissingCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ResumeUnifiedPipelineV2(dir); err == nil {
+		t.Error("Expected an error resuming a directory without checkpoint.json")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, checkpointFile), []byte(`{"Version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResumeUnifiedPipelineV2(dir); err == nil {
+		t.Error("Expected an error for an unsupported checkpoint version")
+	}
+}
@@ -0,0 +1,110 @@
+// Package pipeline - Declarative run configuration
+//
+// A folding run is otherwise configured in Go code. LoadConfig reads the
+// same UnifiedPipelineV2Config from a JSON file, so the CLI and server can
+// take a config file instead:
+//
+//	{
+//	  "Sequence": "NLYIQWLKDGGPSSGRPPPS",
+//	  "SSMethod": "gor",
+//	  "NumSamplesPerMethod": 10,
+//	  "UseMonteCarlo": false,
+//	  "OptimizationConfig": {"BaseSteps": 2000}
+//	}
+//
+// Keys are the Go field names, as in checkpoint.json. Omitted fields, at
+// any depth, keep DefaultUnifiedPipelineV2Config's values; unknown keys are
+// errors, so a misspelt option cannot be silently ignored, and so is any
+// data after the one JSON object.
+//
+// YAML is not supported: the module has no third-party dependencies.
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// validSSMethods are the secondary structure methods a config may name
+var validSSMethods = map[prediction.PredictionMethod]bool{
+	prediction.MethodChouFasman: true,
+	prediction.MethodGOR:        true,
+	prediction.MethodVedic:      true,
+	prediction.MethodConsensus:  true,
+}
+
+// validStrategies are the optimization strategies a config may name
+var validStrategies = map[optimization.OptimizationStrategy]bool{
+	optimization.StrategyLBFGS:              true,
+	optimization.StrategySimulatedAnnealing: true,
+	optimization.StrategyHybrid:             true,
+	optimization.StrategySteepestDescent:    true,
+	optimization.StrategyBasinHopping:       true,
+	optimization.StrategyFIRE:               true,
+}
+
+// LoadConfig reads a pipeline configuration from a JSON file, filling
+// omitted fields from DefaultUnifiedPipelineV2Config
+func LoadConfig(path string) (UnifiedPipelineV2Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return UnifiedPipelineV2Config{}, fmt.Errorf("config %s: YAML is not supported, use JSON", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return UnifiedPipelineV2Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	config := DefaultUnifiedPipelineV2Config("")
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return UnifiedPipelineV2Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	// One object only: trailing data is as suspect as an unknown field
+	if err := decoder.Decode(&json.RawMessage{}); err != io.EOF {
+		return UnifiedPipelineV2Config{}, fmt.Errorf("config %s: unexpected data after the JSON object", path)
+	}
+
+	if err := ValidateConfig(config); err != nil {
+		return UnifiedPipelineV2Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ValidateConfig checks the enum, count and weight fields and the
+// restraints of a configuration
+func ValidateConfig(config UnifiedPipelineV2Config) error {
+	if !validSSMethods[config.SSMethod] {
+		return fmt.Errorf("unknown SSMethod %q", config.SSMethod)
+	}
+	if !validStrategies[config.OptimizationStrategy] {
+		return fmt.Errorf("unknown OptimizationStrategy %q", config.OptimizationStrategy)
+	}
+	if !validStrategies[config.OptimizationConfig.Strategy] {
+		return fmt.Errorf("unknown OptimizationConfig.Strategy %q", config.OptimizationConfig.Strategy)
+	}
+	if !validSelectionModes[config.Selection] {
+		return fmt.Errorf("unknown Selection %q", config.Selection)
+	}
+	if config.NumSamplesPerMethod < 0 {
+		return fmt.Errorf("NumSamplesPerMethod %d is negative", config.NumSamplesPerMethod)
+	}
+	w := config.ScoreWeights
+	if w.Energy < 0 || w.Vedic < 0 || w.Contacts < 0 || w.Ramachandran < 0 || w.Clash < 0 {
+		return fmt.Errorf("ScoreWeights %+v has a negative weight", w)
+	}
+	if err := validateRestraints(config.Restraints, len(config.Sequence)); err != nil {
+		return err
+	}
+	return nil
+}
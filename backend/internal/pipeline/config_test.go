@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// writeConfigFile writes text to name in a fresh temporary directory
+func writeConfigFile(t *testing.T, name, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// TestLoadConfig loads a config overriding a few fields, one nested, and
+// checks the result equals the defaults with exactly those changes; bad
+// enums, unknown keys, trailing data and YAML are rejected
+func TestLoadConfig(t *testing.T) {
+	path := writeConfigFile(t, "run.json", `{
+		"Sequence": "NLYIQWLKDGGPSSGRPPPS",
+		"SSMethod": "gor",
+		"NumSamplesPerMethod": 12,
+		"UseMonteCarlo": false,
+		"OptimizationStrategy": "lbfgs",
+		"OptimizationConfig": {"BaseSteps": 250}
+	}`)
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	want := DefaultUnifiedPipelineV2Config("NLYIQWLKDGGPSSGRPPPS")
+	want.SSMethod = prediction.MethodGOR
+	want.NumSamplesPerMethod = 12
+	want.UseMonteCarlo = false
+	want.OptimizationStrategy = optimization.StrategyLBFGS
+	want.OptimizationConfig.BaseSteps = 250
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("Loaded config\n%+v\nwant\n%+v", config, want)
+	}
+	t.Logf("Loaded: %d samples/method, Monte Carlo %v, SS %s, strategy %s, base steps %d",
+		config.NumSamplesPerMethod, config.UseMonteCarlo, config.SSMethod,
+		config.OptimizationStrategy, config.OptimizationConfig.BaseSteps)
+
+	for _, bad := range []struct{ name, text, mention string }{
+		{"ss.json", `{"SSMethod": "psipred"}`, "SSMethod"},
+		{"strategy.json", `{"OptimizationStrategy": "newton"}`, "OptimizationStrategy"},
+		{"typo.json", `{"NumSamplesPerMethd": 3}`, "NumSamplesPerMethd"},
+		{"negative.json", `{"NumSamplesPerMethod": -1}`, "NumSamplesPerMethod"},
+		{"run.yaml", "NumSamplesPerMethod: 3\n", "YAML"},
+		{"trailing.json", `{"NumSamplesPerMethod": 3} {"UseMonteCarlo": true}`, "after the JSON object"},
+		{"garbage.json", "{\"NumSamplesPerMethod\": 3}\n}", "after the JSON object"},
+	} {
+		_, err := LoadConfig(writeConfigFile(t, bad.name, bad.text))
+		if err == nil || !strings.Contains(err.Error(), bad.mention) {
+			t.Errorf("%s: error %v, want one mentioning %s", bad.name, err, bad.mention)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package pipeline - Consensus selection
+//
+// Phase D picks the optimized structure with the best composite score, a
+// single noisy draw from the ensemble. The alternative is the consensus:
+// the centroid of the most populated RMSD cluster of the optimized
+// ensemble (sampling.ConsensusStructure), the state sampling and
+// optimization converged to most often. Both are reported; Selection says
+// which becomes FinalStructure.
+//
+// BIOCHEMIST: Populated clusters are free-energy basins; one deep but
+// lonely minimum is more often a force-field artifact
+// ETHICIST: Skipped (failed) candidates never vote
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/sampling"
+)
+
+// SelectionMode chooses the final structure in Phase D
+type SelectionMode string
+
+const (
+	// SelectionBestScore picks the highest scoring.CompositeScore (default;
+	// "" means the same)
+	SelectionBestScore SelectionMode = "best_score"
+
+	// SelectionConsensus picks the centroid of the largest cluster
+	SelectionConsensus SelectionMode = "consensus"
+)
+
+// validSelectionModes are the selection modes a config may name
+var validSelectionModes = map[SelectionMode]bool{
+	"":                 true,
+	SelectionBestScore: true,
+	SelectionConsensus: true,
+}
+
+// defaultConsensusRMSDCutoff is the cluster radius (Å) used when
+// ConsensusRMSDCutoff is not positive
+const defaultConsensusRMSDCutoff = 2.0
+
+// findConsensus returns the index into ensemble of the consensus structure
+// (sampling.ConsensusStructure at cutoff Å) of its usable members
+//
+// usable nil means every non-nil member.
+func findConsensus(ensemble []*parser.Protein, usable []bool, cutoff float64) (int, error) {
+	if cutoff <= 0 {
+		cutoff = defaultConsensusRMSDCutoff
+	}
+
+	var members []*parser.Protein
+	var indices []int
+	for i, structure := range ensemble {
+		if structure != nil && (usable == nil || usable[i]) {
+			members = append(members, structure)
+			indices = append(indices, i)
+		}
+	}
+	if len(members) == 0 {
+		return -1, fmt.Errorf("no optimized structures to cluster")
+	}
+
+	centroid, err := sampling.ConsensusStructure(members, cutoff)
+	if err != nil {
+		return -1, err
+	}
+	for k, structure := range members {
+		if structure == centroid {
+			return indices[k], nil
+		}
+	}
+	return -1, fmt.Errorf("consensus centroid is not an ensemble member")
+}
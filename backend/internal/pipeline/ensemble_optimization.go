@@ -0,0 +1,214 @@
+// Package pipeline - Parallel ensemble optimization
+//
+// Phase C relaxes every sampled structure independently, so the work is
+// embarrassingly parallel. A bounded worker pool (one worker per CPU by
+// default) optimizes clones concurrently; results land in a slice indexed by
+// ensemble position, so selection afterwards is identical to the sequential
+// loop regardless of completion order.
+//
+// PHYSICIST: Each relaxation is deterministic given its input structure
+// MATHEMATICIAN: Results indexed by position → order-independent reduction
+// ETHICIST: Same answer on a laptop and a 64-core server
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/scoring"
+)
+
+// ensembleCandidate is the outcome of optimizing one ensemble member
+type ensembleCandidate struct {
+	structure  *parser.Protein // Optimized clone (nil if never processed)
+	optResult  *optimization.OptimizationResult
+	energy     float64 // Final energy incl. Vedic, contact, restraint and clash terms
+	score      scoring.CompositeResult
+	skipReason string // Non-empty if rejected
+}
+
+// scoreWeights returns the selection weights, without the Vedic and contact
+// terms when those features are off
+func scoreWeights(config UnifiedPipelineV2Config) scoring.ScoreWeights {
+	weights := config.ScoreWeights
+	if !config.UseVedicBiasing {
+		weights.Vedic = 0
+	}
+	if !config.UseContactMap {
+		weights.Contacts = 0
+	}
+	return weights
+}
+
+// optimizeEnsemble relaxes every structure on up to config.MaxWorkers goroutines
+//
+// Returns one candidate per ensemble member (same order). On cancellation,
+// members not yet started have a nil structure, members cut short are
+// skipped, and ctx.Err() is returned.
+func optimizeEnsemble(ctx context.Context, ensemble []*parser.Protein, contacts []prediction.ContactPrediction,
+	ssPred []prediction.SecondaryStructurePrediction, config UnifiedPipelineV2Config) ([]ensembleCandidate, error) {
+
+	workers := config.MaxWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(ensemble) {
+		workers = len(ensemble)
+	}
+
+	candidates := make([]ensembleCandidate, len(ensemble))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Each index is written by exactly one worker
+				candidates[i] = optimizeCandidate(ctx, ensemble[i], contacts, ssPred, config)
+			}
+		}()
+	}
+
+	var cancelErr error
+	for i := range ensemble {
+		if err := ctx.Err(); err != nil {
+			cancelErr = err
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Cancelled after the last member started: some were cut short
+	if cancelErr == nil {
+		cancelErr = ctx.Err()
+	}
+
+	return candidates, cancelErr
+}
+
+// optimizeCandidate validates, relaxes and scores a clone of one structure
+//
+// Every minimizer checks ctx each iteration; a candidate cut short by
+// cancellation is skipped.
+func optimizeCandidate(ctx context.Context, original *parser.Protein, contacts []prediction.ContactPrediction,
+	ssPred []prediction.SecondaryStructurePrediction, config UnifiedPipelineV2Config) ensembleCandidate {
+
+	// GentleRelax mutates in place: never touch the shared ensemble member
+	structure := original.Copy()
+	cand := ensembleCandidate{structure: structure}
+
+	// WAVE 11.2.1: VALIDATE COORDINATES BEFORE OPTIMIZATION
+	// Agent 4.5.2: Energy Stability Surgeon - Prevent Phase 2 corruption
+	_, validationReport := physics.ScoreStructureQuality(structure)
+
+	if !validationReport.IsValid {
+		// Skip structures with corrupted coordinates (NaN, Inf, broken backbone)
+		cand.skipReason = validationReport.ValidationError
+		return cand
+	}
+
+	if validationReport.HasClashes && validationReport.ClashCount > 5 {
+		// Skip structures with severe steric clashes (>5 clashes)
+		cand.skipReason = fmt.Sprintf("%d severe clashes (worst: %.2f Å)",
+			validationReport.ClashCount, validationReport.WorstClashDist)
+		return cand
+	}
+
+	// Coarse-grained global fold first: all-atom refinement only polishes it
+	if config.CAPrefoldIterations > 0 {
+		prefoldConfig := optimization.DefaultQuaternionLBFGSConfig()
+		prefoldConfig.EnergyModel = physics.EnergyModelCA
+		prefoldConfig.MaxIterations = config.CAPrefoldIterations
+		if config.UseContactMap {
+			prefoldConfig.ContactRestraints = prediction.ContactRestraints(contacts)
+		}
+		if _, err := optimization.MinimizeQuaternionLBFGSCtx(ctx, structure, prefoldConfig); err != nil {
+			cand.skipReason = fmt.Sprintf("CA prefold failed: %v", err)
+			return cand
+		}
+	}
+
+	// Restrain φ/ψ toward the predicted helices and strands before relaxing
+	if config.UseConstraintRefinement && len(ssPred) > 0 {
+		constraintConfig := config.ConstraintConfig
+		constraintConfig.SecondaryStructure = ssPred
+		if err := optimization.ConstraintGuidedRefinementCtx(ctx, structure, constraintConfig, 50); err != nil {
+			cand.skipReason = fmt.Sprintf("constraint refinement failed: %v", err)
+			return cand
+		}
+	}
+
+	// WAVE 11.2: Use gentle relaxation instead of aggressive L-BFGS
+	// Wright Brothers lesson: Simple > Complex!
+	relaxConfig := optimization.DefaultGentleRelaxationConfig()
+	relaxConfig.MaxSteps = 50
+
+	relaxResult, err := optimization.GentleRelaxCtx(ctx, structure, relaxConfig)
+	if err != nil {
+		cand.skipReason = fmt.Sprintf("relaxation failed: %v", err)
+		return cand
+	}
+
+	// Pull toward the user restraints with their gradient in the objective
+	if len(config.Restraints) > 0 {
+		if err := minimizeRestrained(ctx, structure, config.Restraints); err != nil {
+			cand.skipReason = fmt.Sprintf("restrained minimization failed: %v", err)
+			return cand
+		}
+	}
+
+	// WAVE 11.2.2: VALIDATE AGAIN AFTER OPTIMIZATION
+	// Ensure optimization didn't introduce instabilities
+	_, validationAfter := physics.ScoreStructureQuality(structure)
+	if !validationAfter.IsValid || (validationAfter.HasClashes && validationAfter.ClashCount > 5) {
+		cand.skipReason = "became unstable after optimization"
+		return cand
+	}
+
+	// Create opt result for compatibility
+	cand.optResult = &optimization.OptimizationResult{
+		Strategy:      optimization.StrategyHybrid,
+		InitialEnergy: relaxResult.InitialEnergy,
+		FinalEnergy:   relaxResult.FinalEnergy,
+		EnergyChange:  relaxResult.EnergyChange,
+		Iterations:    relaxResult.Steps,
+		Converged:     relaxResult.Converged,
+	}
+
+	// Apply Vedic biasing if enabled
+	finalEnergy := cand.optResult.FinalEnergy
+	if config.UseVedicBiasing {
+		angles := geometry.CalculateRamachandran(structure)
+		vedicEnergy := prediction.CalculateVedicEnergy(structure, angles, config.VedicBias)
+		finalEnergy += config.VedicBias.VedicWeight * vedicEnergy * 1000.0 // Scale to kcal/mol
+	}
+
+	// Apply contact restraints if enabled
+	if config.UseContactMap && len(contacts) > 0 {
+		contactEnergy := prediction.ApplyContactRestraints(structure, contacts, 10.0)
+		finalEnergy += contactEnergy
+	}
+
+	// Restraint violations left after minimization
+	if len(config.Restraints) > 0 {
+		finalEnergy += restraintEnergy(structure, config.Restraints)
+	}
+
+	// Quality penalty for structures with minor clashes
+	clashPenalty := float64(validationAfter.ClashCount) * 100.0 // 100 kcal/mol per clash
+	cand.energy = finalEnergy + clashPenalty
+	cand.score = scoring.CompositeScore(structure, scoreWeights(config), contacts)
+
+	return cand
+}
@@ -0,0 +1,170 @@
+// Package pipeline - Folding with user restraints
+//
+// Sometimes part of the answer is known before folding: NOE distances,
+// crosslinks, a measured φ from J-couplings. FoldWithRestraints runs the
+// pipeline with such data as Restraints: after relaxation every ensemble
+// member is minimized by quaternion L-BFGS with the restraints in its
+// energy and gradient, so the selected model honours them.
+//
+// BIOCHEMIST: Restraints are bounds (Target ± Tolerance), as NMR distances
+// are reported; inside the window they cost nothing
+// PHYSICIST: Flat-bottom harmonic terms, see physics.RestraintEnergy
+// ETHICIST: A restraint on a residue the sequence does not have is an
+// error, not silently dropped
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/optimization"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// RestraintKind selects what a Restraint holds
+type RestraintKind string
+
+const (
+	RestraintDistance RestraintKind = "distance" // Atom-atom distance
+	RestraintDihedral RestraintKind = "dihedral" // Backbone φ or ψ
+)
+
+// Default restraint force constants, used when Restraint.Weight is zero
+const (
+	DefaultDistanceRestraintWeight = 10.0  // kcal/(mol·Å²)
+	DefaultDihedralRestraintWeight = 100.0 // kcal/(mol·rad²); 10° beyond tolerance ≈ 3 kcal/mol
+)
+
+// restraintIterations is the L-BFGS budget of each restrained minimization
+const restraintIterations = 100
+
+// Restraint is a user-supplied distance or dihedral restraint
+//
+// Residues are positions in the sequence (0-indexed). A distance restraint
+// uses Residue1, Residue2, Atom1 and Atom2 ("" means CA) with Target and
+// Tolerance in Å; a dihedral restraint uses Residue and Angle ("phi" or
+// "psi") with Target and Tolerance in degrees.
+type Restraint struct {
+	Kind RestraintKind
+
+	// Distance restraints
+	Residue1 int
+	Residue2 int
+	Atom1    string
+	Atom2    string
+
+	// Dihedral restraints
+	Residue int
+	Angle   string
+
+	Target    float64 // Å or degrees
+	Tolerance float64 // Half-width of the zero-energy window (Å or degrees)
+	Weight    float64 // Force constant; 0 uses the kind's default
+}
+
+// FoldWithRestraints folds sequence with config, adding restraints to the
+// energy and gradient of every candidate's final minimization
+//
+// config.Sequence and config.Restraints are replaced by sequence and
+// restraints. The result's RestraintEnergy is what the selected model
+// still pays for violations.
+func FoldWithRestraints(sequence string, restraints []Restraint, config UnifiedPipelineV2Config) (*UnifiedPipelineV2Result, error) {
+	return FoldWithRestraintsCtx(context.Background(), sequence, restraints, config)
+}
+
+// FoldWithRestraintsCtx is FoldWithRestraints with cancellation (see
+// RunUnifiedPipelineV2Ctx)
+func FoldWithRestraintsCtx(ctx context.Context, sequence string, restraints []Restraint, config UnifiedPipelineV2Config) (*UnifiedPipelineV2Result, error) {
+	if err := validateRestraints(restraints, len(sequence)); err != nil {
+		return nil, err
+	}
+	config.Sequence = sequence
+	config.Restraints = restraints
+	return RunUnifiedPipelineV2Ctx(ctx, config, nil)
+}
+
+// validateRestraints checks kinds, angles, tolerances and weights, and
+// residues against a sequence of n residues (n = 0 skips the upper bound,
+// for configs loaded before the sequence is known)
+func validateRestraints(restraints []Restraint, n int) error {
+	inRange := func(i int) bool { return i >= 0 && (n == 0 || i < n) }
+	for k, r := range restraints {
+		switch r.Kind {
+		case RestraintDistance:
+			if !inRange(r.Residue1) || !inRange(r.Residue2) {
+				return fmt.Errorf("restraint %d: residues %d-%d outside the %d-residue sequence", k, r.Residue1, r.Residue2, n)
+			}
+			if r.Residue1 == r.Residue2 && r.Atom1 == r.Atom2 {
+				return fmt.Errorf("restraint %d: both ends are the same atom", k)
+			}
+			if r.Target < 0 {
+				return fmt.Errorf("restraint %d: target distance %.2f Å is negative", k, r.Target)
+			}
+		case RestraintDihedral:
+			if !inRange(r.Residue) {
+				return fmt.Errorf("restraint %d: residue %d outside the %d-residue sequence", k, r.Residue, n)
+			}
+			if r.Angle != "phi" && r.Angle != "psi" {
+				return fmt.Errorf("restraint %d: unknown angle %q (want phi or psi)", k, r.Angle)
+			}
+		default:
+			return fmt.Errorf("restraint %d: unknown kind %q", k, r.Kind)
+		}
+		if r.Tolerance < 0 || r.Weight < 0 || math.IsNaN(r.Target) {
+			return fmt.Errorf("restraint %d: negative tolerance or weight, or NaN target", k)
+		}
+	}
+	return nil
+}
+
+// physicsRestraints converts restraints to the physics terms, in radians
+// and with default weights filled in
+func physicsRestraints(restraints []Restraint) ([]physics.DistanceRestraint, []physics.DihedralRestraint) {
+	var distances []physics.DistanceRestraint
+	var dihedrals []physics.DihedralRestraint
+	for _, r := range restraints {
+		switch r.Kind {
+		case RestraintDistance:
+			weight := r.Weight
+			if weight == 0 {
+				weight = DefaultDistanceRestraintWeight
+			}
+			distances = append(distances, physics.DistanceRestraint{
+				Residue1: r.Residue1, Residue2: r.Residue2,
+				Atom1: r.Atom1, Atom2: r.Atom2,
+				Target: r.Target, Tolerance: r.Tolerance, Weight: weight,
+			})
+		case RestraintDihedral:
+			weight := r.Weight
+			if weight == 0 {
+				weight = DefaultDihedralRestraintWeight
+			}
+			dihedrals = append(dihedrals, physics.DihedralRestraint{
+				Residue: r.Residue, Angle: r.Angle,
+				Target:    r.Target * math.Pi / 180,
+				Tolerance: r.Tolerance * math.Pi / 180,
+				Weight:    weight,
+			})
+		}
+	}
+	return distances, dihedrals
+}
+
+// minimizeRestrained minimizes structure in place by quaternion L-BFGS
+// with restraints in the objective
+func minimizeRestrained(ctx context.Context, structure *parser.Protein, restraints []Restraint) error {
+	lbfgsConfig := optimization.DefaultQuaternionLBFGSConfig()
+	lbfgsConfig.MaxIterations = restraintIterations
+	lbfgsConfig.DistanceRestraints, lbfgsConfig.DihedralRestraints = physicsRestraints(restraints)
+	_, err := optimization.MinimizeQuaternionLBFGSCtx(ctx, structure, lbfgsConfig)
+	return err
+}
+
+// restraintEnergy returns the restraint energy of structure in kcal/mol
+func restraintEnergy(structure *parser.Protein, restraints []Restraint) float64 {
+	distances, dihedrals := physicsRestraints(restraints)
+	energy, _ := physics.RestraintEnergy(structure, distances, dihedrals)
+	return energy
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// TestFoldWithRestraints checks that a tight CA-CA restraint between the
+// termini is met by the selected model
+func TestFoldWithRestraints(t *testing.T) {
+	sequence := "GAKAEAKAG"
+	config := UnifiedPipelineV2Config{
+		UseSSprediction:     true,
+		SSMethod:            prediction.MethodChouFasman,
+		UseQuaternionSlerp:  true,
+		NumSamplesPerMethod: 3,
+		ScoreWeights:        DefaultUnifiedPipelineV2Config(sequence).ScoreWeights,
+	}
+
+	last := len(sequence) - 1
+	restraints := []Restraint{
+		{Kind: RestraintDistance, Residue1: 0, Residue2: last, Target: 8.0, Tolerance: 0.5, Weight: 100},
+	}
+
+	unrestrained, err := RunUnifiedPipelineV2(withSequence(config, sequence), nil)
+	if err != nil {
+		t.Fatalf("Unrestrained fold failed: %v", err)
+	}
+	result, err := FoldWithRestraints(sequence, restraints, config)
+	if err != nil {
+		t.Fatalf("FoldWithRestraints failed: %v", err)
+	}
+
+	termini := func(s *UnifiedPipelineV2Result) float64 {
+		a, b := s.FinalStructure.Residues[0].CA, s.FinalStructure.Residues[last].CA
+		return math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+	}
+	d := termini(result)
+	t.Logf("Termini CA-CA: %.2f Å unrestrained, %.2f Å restrained (target 8.0 ± 0.5), violation energy %.3f kcal/mol",
+		termini(unrestrained), d, result.RestraintEnergy)
+
+	if math.Abs(d-8.0) > 0.5 {
+		t.Errorf("Termini CA-CA %.2f Å, want 8.0 ± 0.5 Å", d)
+	}
+
+	// Restraints on residues the sequence does not have are rejected
+	bad := []Restraint{{Kind: RestraintDistance, Residue1: 0, Residue2: len(sequence), Target: 8}}
+	if _, err := FoldWithRestraints(sequence, bad, config); err == nil {
+		t.Error("Out-of-range restraint accepted")
+	}
+	bad = []Restraint{{Kind: RestraintDihedral, Residue: 2, Angle: "omega"}}
+	if _, err := FoldWithRestraints(sequence, bad, config); err == nil {
+		t.Error("Unknown dihedral accepted")
+	}
+}
+
+// withSequence returns config for sequence
+func withSequence(config UnifiedPipelineV2Config, sequence string) UnifiedPipelineV2Config {
+	config.Sequence = sequence
+	return config
+}
@@ -32,7 +32,10 @@
 package pipeline
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"runtime"
 	"time"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
@@ -41,9 +44,15 @@ import (
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/sampling"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/scoring"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
 
+// highEnergyResidueSigma is how many standard deviations above the mean
+// residue energy a residue must be to be reported as high-energy
+const highEnergyResidueSigma = 3.0
+
 // UnifiedPipelineV2Config holds all configuration parameters
 type UnifiedPipelineV2Config struct {
 	// Input
@@ -57,6 +66,10 @@ type UnifiedPipelineV2Config struct {
 	UseContactMap bool
 	ContactConfig prediction.ContactMapConfig
 
+	// Burial profile: compact coil residues around predicted-buried
+	// residues in the initial structure (see prediction.PredictBurialProfile)
+	UseBurialBias bool
+
 	// Sampling strategy (multiple can be enabled)
 	UseQuaternionSlerp bool
 	UseMonteCarlo      bool
@@ -68,12 +81,51 @@ type UnifiedPipelineV2Config struct {
 	OptimizationStrategy optimization.OptimizationStrategy
 	OptimizationConfig   optimization.AdaptiveOptimizationConfig
 
+	// Constraint refinement: restrain each candidate's φ/ψ toward the
+	// predicted secondary structure before relaxation (needs UseSSprediction)
+	UseConstraintRefinement bool
+	ConstraintConfig        optimization.ConstraintConfig
+
+	// Coarse-grained prefold: > 0 minimizes each candidate for this many
+	// quaternion L-BFGS iterations in the CA-only model (physics.EnergyModelCA),
+	// pulled toward the predicted contacts with UseContactMap, before the
+	// all-atom steps. A fast global fold for large proteins; 0 skips it
+	CAPrefoldIterations int
+
+	// User distance/dihedral restraints (nil: none): each candidate is
+	// minimized with them in its energy after relaxation (see FoldWithRestraints)
+	Restraints []Restraint
+
 	// Vedic biasing
 	UseVedicBiasing bool
 	VedicBias       prediction.VedicStructuralBias
 
+	// Final selection: the optimized structure with the highest
+	// scoring.CompositeScore wins. The Vedic and contact weights only
+	// count with UseVedicBiasing and UseContactMap.
+	ScoreWeights scoring.ScoreWeights
+
+	// Selection mode: SelectionConsensus instead makes the centroid of the
+	// largest cluster of optimized structures, at ConsensusRMSDCutoff Å CA-RMSD
+	// (<= 0: 2 Å), the final structure; both are reported either way
+	Selection           SelectionMode
+	ConsensusRMSDCutoff float64
+
+	// Parallelism: workers for ensemble optimization (<= 0 uses runtime.NumCPU())
+	MaxWorkers int
+
 	// Output
 	Verbose bool
+
+	// OutputPDBPath writes the selected structure as PDB when set
+	OutputPDBPath string
+
+	// OutputEnsemblePath writes the sampled ensemble as multi-MODEL PDB when set
+	OutputEnsemblePath string
+
+	// CheckpointDir, when set, receives a checkpoint after sampling and after
+	// optimization; ResumeUnifiedPipelineV2 continues from it
+	CheckpointDir string
 }
 
 // DefaultUnifiedPipelineV2Config returns recommended Phase 2 parameters
@@ -84,6 +136,7 @@ func DefaultUnifiedPipelineV2Config(sequence string) UnifiedPipelineV2Config {
 		SSMethod:            prediction.MethodChouFasman,
 		UseContactMap:       true,
 		ContactConfig:       prediction.DefaultContactMapConfig(),
+		UseBurialBias:       true,
 		UseQuaternionSlerp:  true,
 		UseMonteCarlo:       true,
 		UseFragmentAssembly: true,
@@ -91,8 +144,14 @@ func DefaultUnifiedPipelineV2Config(sequence string) UnifiedPipelineV2Config {
 		NumSamplesPerMethod: 5, // 5 samples × 4 methods = 20 total
 		OptimizationStrategy: optimization.StrategyHybrid,
 		OptimizationConfig:   optimization.DefaultAdaptiveOptimizationConfig(),
+		UseConstraintRefinement: true,
+		ConstraintConfig:        optimization.DefaultConstraintConfig(),
 		UseVedicBiasing:      true,
 		VedicBias:            prediction.DefaultVedicStructuralBias(),
+		ScoreWeights:         scoring.DefaultScoreWeights(),
+		Selection:            SelectionBestScore,
+		ConsensusRMSDCutoff:  defaultConsensusRMSDCutoff,
+		MaxWorkers:           runtime.NumCPU(),
 		Verbose:              false,
 	}
 }
@@ -102,16 +161,44 @@ type UnifiedPipelineV2Result struct {
 	// Predictions
 	SecondaryStructure []prediction.SecondaryStructurePrediction
 	ContactMap         []prediction.ContactPrediction
+	BurialProfile      []float64 // Buried probability per residue (UseBurialBias only)
 	VedicReport        prediction.VedicHarmonicReport
+	SequenceProperties prediction.SequenceReport // Molecular weight, pI, charge and composition
 
 	// Final structure
 	FinalStructure *parser.Protein
 	FinalAngles    []geometry.RamachandranAngles
+	Disulfides     []physics.Disulfide // Cysteine pairs bonded in the final structure
+
+	// Centroid of the largest cluster of optimized structures (see
+	// sampling.ConsensusStructure); FinalStructure itself under
+	// SelectionConsensus, nil if clustering failed
+	ConsensusStructure *parser.Protein
+
+	// Compactness of the final structure (see validation.CompactnessScore)
+	RadiusOfGyration         float64 // CA radius of gyration (Å)
+	ExpectedRadiusOfGyration float64 // Folded globular protein of this length (Å)
+	CompactnessScore         float64 // [0, 1]; near 0 means the model never collapsed
 
 	// Energetics
 	FinalEnergy      float64
+	RestraintEnergy  float64 // Violation energy of config.Restraints in FinalStructure (kcal/mol)
 	FinalVedicScore  float64
-	CombinedScore    float64
+	CombinedScore    float64 // Score.Total
+
+	// Composite score of the final structure, with its term breakdown
+	Score scoring.CompositeResult
+
+	// Per-residue split of the final structure's force-field energy and
+	// the residues standing out from it (clashes, strain), as indices into
+	// FinalStructure.Residues; see physics.DecomposeEnergyPerResidue
+	ResidueEnergies    []physics.ResidueEnergy
+	HighEnergyResidues []int
+
+	// pLDDT-style 0-100 confidence per residue of FinalStructure from the
+	// optimized ensemble's CA spread (see sampling.ResidueConfidence); also
+	// written to the output PDB's B-factor column
+	ResidueConfidence []float64
 
 	// Optimization statistics
 	OptimizationResult *optimization.OptimizationResult
@@ -121,6 +208,12 @@ type UnifiedPipelineV2Result struct {
 
 	// Pipeline statistics
 	TotalSamplesGenerated int
+
+	// Conformational coverage of the sampled ensemble (see
+	// sampling.EnsembleDiversity): mean pairwise CA-RMSD (Å) and the
+	// number of distinct conformations at 2 Å
+	EnsembleMeanRMSD      float64
+	EnsembleEffectiveSize int
 	TotalTimeSeconds      float64
 	SuccessRate           float64
 
@@ -150,8 +243,10 @@ type UnifiedPipelineV2Result struct {
 //      - Hybrid (SA → L-BFGS)
 //
 // Phase D: Selection & Validation
-//   6. Score structures (energy + Vedic + contact satisfaction)
-//   7. Select best structure
+//   6. Score structures (scoring.CompositeScore: energy, Vedic, contacts,
+//      Ramachandran, clashscore)
+//   7. Select best structure (best composite score, or the consensus:
+//      centroid of the largest RMSD cluster)
 //   8. Validate against experimental (if available)
 //
 // EXPECTED PERFORMANCE:
@@ -159,13 +254,31 @@ type UnifiedPipelineV2Result struct {
 // - RMSD: <5 Å target (vs 63.16 Å Phase 1)
 // - Success rate: >90% (no crashes)
 func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.Protein) (*UnifiedPipelineV2Result, error) {
+	return RunUnifiedPipelineV2Ctx(context.Background(), config, experimental)
+}
+
+// RunUnifiedPipelineV2Ctx is RunUnifiedPipelineV2 with cancellation
+//
+// ctx is checked every step of the Monte Carlo, fragment assembly and
+// minimization loops, between sampling methods and before each ensemble
+// member is optimized. If cancelled during optimization, members cut short
+// are skipped, the best completed structure is selected and scored as usual
+// (no output files are written) and returned together with ctx.Err(). If cancelled earlier, the partial result holds
+// only the predictions.
+func RunUnifiedPipelineV2Ctx(ctx context.Context, config UnifiedPipelineV2Config, experimental *parser.Protein) (*UnifiedPipelineV2Result, error) {
 	startTime := time.Now()
 
 	result := &UnifiedPipelineV2Result{}
+	if properties, err := prediction.SequenceProperties(config.Sequence); err == nil {
+		result.SequenceProperties = properties
+	}
 
 	if config.Verbose {
 		fmt.Printf("=== FoldVedic.ai Unified Pipeline v2.0 ===\n")
 		fmt.Printf("Sequence: %s (%d residues)\n", config.Sequence, len(config.Sequence))
+		if result.SequenceProperties.Length > 0 {
+			fmt.Printf("Properties: %s\n", result.SequenceProperties)
+		}
 		fmt.Printf("\n")
 	}
 
@@ -212,6 +325,23 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		}
 	}
 
+	// Step 3: Burial profile from hydropathy and hydrophobic moment
+	var burial []float64
+	if config.UseBurialBias {
+		burial = prediction.PredictBurialProfile(config.Sequence)
+		result.BurialProfile = burial
+
+		if config.Verbose {
+			buried := 0
+			for _, p := range burial {
+				if p > 0.5 {
+					buried++
+				}
+			}
+			fmt.Printf("  Burial Profile: %d of %d residues predicted buried\n", buried, len(burial))
+		}
+	}
+
 	if config.Verbose {
 		fmt.Printf("\n")
 	}
@@ -223,8 +353,13 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 
 	ensemble := make([]*parser.Protein, 0)
 
-	// Initialize base structure from secondary structure prediction
-	baseStructure := initializeFromSSPrediction(config.Sequence, ssPred)
+	// Initialize base structure from secondary structure prediction,
+	// compacted around the predicted core
+	baseStructure := initializeWithBurial(config.Sequence, ssPred, burial)
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
 
 	// Method 1: Quaternion slerp sampling
 	if config.UseQuaternionSlerp {
@@ -240,13 +375,17 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
 	// Method 2: Monte Carlo sampling
 	if config.UseMonteCarlo {
 		mcConfig := sampling.DefaultMonteCarloConfig()
 		mcConfig.NumSteps = 500 // Quick MC runs
 		mcConfig.VedicWeight = 0.3
 
-		mcEnsemble, err := sampling.GenerateMonteCarloEnsemble(baseStructure, mcConfig, config.NumSamplesPerMethod)
+		mcEnsemble, err := sampling.GenerateMonteCarloEnsembleCtx(ctx, baseStructure, mcConfig, config.NumSamplesPerMethod)
 		if err == nil {
 			ensemble = append(ensemble, mcEnsemble...)
 			if config.Verbose {
@@ -255,12 +394,16 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
 	// Method 3: Fragment assembly
 	if config.UseFragmentAssembly {
 		fragmentLib := sampling.NewFragmentLibrary()
 		fragConfig := sampling.DefaultFragmentAssemblyConfig()
 
-		fragEnsemble, err := sampling.GenerateFragmentEnsemble(config.Sequence, fragmentLib, fragConfig, config.NumSamplesPerMethod)
+		fragEnsemble, err := sampling.GenerateFragmentEnsembleCtx(ctx, config.Sequence, fragmentLib, fragConfig, config.NumSamplesPerMethod)
 		if err == nil {
 			ensemble = append(ensemble, fragEnsemble...)
 			if config.Verbose {
@@ -269,6 +412,10 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
 	// Method 4: Basin explorer
 	if config.UseBasinExplorer {
 		basinConfig := sampling.DefaultBasinExplorerConfig()
@@ -294,125 +441,201 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		fmt.Printf("\n")
 	}
 
+	run := &pipelineRun{
+		config:       config,
+		experimental: experimental,
+		result:       result,
+		ssPred:       ssPred,
+		contacts:     contacts,
+		startTime:    startTime,
+	}
+
+	// Persist the ensemble; later phases read it back so an uninterrupted run
+	// optimizes exactly what ResumeUnifiedPipelineV2 would
+	if config.CheckpointDir != "" {
+		var err error
+		ensemble, err = run.writeSamplingCheckpoint(ensemble)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write sampling checkpoint: %w", err)
+		}
+		if config.Verbose {
+			fmt.Printf("  Checkpoint: %s (sampling)\n\n", config.CheckpointDir)
+		}
+	}
+
+	return run.optimizeAndSelect(ctx, ensemble)
+}
+
+// pipelineRun carries state from sampling into optimization and selection,
+// for both fresh and resumed runs
+type pipelineRun struct {
+	config       UnifiedPipelineV2Config
+	experimental *parser.Protein
+	result       *UnifiedPipelineV2Result
+	ssPred       []prediction.SecondaryStructurePrediction
+	contacts     []prediction.ContactPrediction
+	startTime    time.Time
+}
+
+// optimizeAndSelect runs Phase C on the ensemble, then Phase D
+func (run *pipelineRun) optimizeAndSelect(ctx context.Context, ensemble []*parser.Protein) (*UnifiedPipelineV2Result, error) {
+	config := run.config
+	result := run.result
+
 	// PHASE C: ENERGY OPTIMIZATION
 	if config.Verbose {
 		fmt.Printf("Phase C: Energy Optimization\n")
 	}
 
+	// Optimize independent structures on a bounded worker pool; each worker
+	// relaxes its own clone, so ensemble members are never shared
+	candidates, cancelErr := optimizeEnsemble(ctx, ensemble, run.contacts, run.ssPred, config)
+
 	bestEnergy := 1e10
+	bestScore := math.Inf(-1)
+	bestIndex := -1
 	var bestStructure *parser.Protein
 	var bestOptResult *optimization.OptimizationResult
 
 	successful := 0
 
-	for i, structure := range ensemble {
-		// WAVE 11.2.1: VALIDATE COORDINATES BEFORE OPTIMIZATION
-		// Agent 4.5.2: Energy Stability Surgeon - Prevent Phase 2 corruption
-		_, validationReport := physics.ScoreStructureQuality(structure)
-
-		if !validationReport.IsValid {
-			// Skip structures with corrupted coordinates (NaN, Inf, broken backbone)
-			if config.Verbose && i < 3 {
-				fmt.Printf("  ⚠ Skipping structure %d: %s\n", i+1, validationReport.ValidationError)
-			}
-			continue
-		}
-
-		if validationReport.HasClashes && validationReport.ClashCount > 5 {
-			// Skip structures with severe steric clashes (>5 clashes)
-			if config.Verbose && i < 3 {
-				fmt.Printf("  ⚠ Skipping structure %d: %d severe clashes (worst: %.2f Å)\n",
-					i+1, validationReport.ClashCount, validationReport.WorstClashDist)
-			}
-			continue
-		}
-
-		// WAVE 11.2: Use gentle relaxation instead of aggressive L-BFGS
-		// Wright Brothers lesson: Simple > Complex!
-		relaxConfig := optimization.DefaultGentleRelaxationConfig()
-		relaxConfig.MaxSteps = 50
-
-		relaxResult, err := optimization.GentleRelax(structure, relaxConfig)
-		if err != nil {
-			// Skip failed relaxations
-			continue
+	// Collect in ensemble order: ties keep the lowest index (deterministic)
+	for i, cand := range candidates {
+		if cand.structure == nil {
+			continue // Not reached before cancellation
 		}
+		ensemble[i] = cand.structure
 
-		// WAVE 11.2.2: VALIDATE AGAIN AFTER OPTIMIZATION
-		// Ensure optimization didn't introduce instabilities
-		_, validationAfter := physics.ScoreStructureQuality(structure)
-		if !validationAfter.IsValid || (validationAfter.HasClashes && validationAfter.ClashCount > 5) {
+		if cand.skipReason != "" {
 			if config.Verbose && i < 3 {
-				fmt.Printf("  ⚠ Structure %d became unstable after optimization\n", i+1)
+				fmt.Printf("  ⚠ Skipping structure %d: %s\n", i+1, cand.skipReason)
 			}
 			continue
 		}
 
 		successful++
 
-		// Create opt result for compatibility
-		optResult := &optimization.OptimizationResult{
-			Strategy:      optimization.StrategyHybrid,
-			InitialEnergy: relaxResult.InitialEnergy,
-			FinalEnergy:   relaxResult.FinalEnergy,
-			EnergyChange:  relaxResult.EnergyChange,
-			Iterations:    relaxResult.Steps,
-			Converged:     relaxResult.Converged,
+		if cand.score.Total > bestScore {
+			bestScore = cand.score.Total
+			bestEnergy = cand.energy
+			bestIndex = i
+			bestStructure = cand.structure
+			bestOptResult = cand.optResult
 		}
+	}
 
-		// Apply Vedic biasing if enabled
-		finalEnergy := optResult.FinalEnergy
-		if config.UseVedicBiasing {
-			angles := geometry.CalculateRamachandran(structure)
-			vedicEnergy := prediction.CalculateVedicEnergy(structure, angles, config.VedicBias)
-			finalEnergy += config.VedicBias.VedicWeight * vedicEnergy * 1000.0 // Scale to kcal/mol
-		}
-
-		// Apply contact restraints if enabled
-		if config.UseContactMap && len(contacts) > 0 {
-			contactEnergy := prediction.ApplyContactRestraints(structure, contacts, 10.0)
-			finalEnergy += contactEnergy
-		}
-
-		// Track best (with quality penalty for structures with minor clashes)
-		clashPenalty := float64(validationAfter.ClashCount) * 100.0 // 100 kcal/mol per clash
-		finalEnergyWithPenalty := finalEnergy + clashPenalty
-
-		if finalEnergyWithPenalty < bestEnergy {
-			bestEnergy = finalEnergyWithPenalty
-			bestStructure = structure
-			bestOptResult = optResult
-		}
+	if config.Verbose && cancelErr != nil {
+		fmt.Printf("  Cancelled: %v\n", cancelErr)
+	}
 
-		if config.Verbose && i%5 == 0 {
-			fmt.Printf("  Optimized %d/%d structures (best energy: %.2f kcal/mol)\r",
-				i+1, len(ensemble), bestEnergy)
-		}
+	usable := make([]bool, len(candidates))
+	for i, cand := range candidates {
+		usable[i] = cand.structure != nil && cand.skipReason == ""
+	}
+	consensusIndex, err := findConsensus(ensemble, usable, config.ConsensusRMSDCutoff)
+	if err == nil && config.Selection == SelectionConsensus {
+		cand := candidates[consensusIndex]
+		bestScore = cand.score.Total
+		bestEnergy = cand.energy
+		bestIndex = consensusIndex
+		bestStructure = cand.structure
+		bestOptResult = cand.optResult
 	}
 
 	if config.Verbose {
 		fmt.Printf("\n")
 		fmt.Printf("  Optimization complete: %d/%d successful (%.1f%%)\n",
 			successful, len(ensemble), 100.0*float64(successful)/float64(len(ensemble)))
-		fmt.Printf("  Best energy: %.2f kcal/mol\n", bestEnergy)
+		fmt.Printf("  Best composite score: %.4f (energy %.2f kcal/mol)\n", bestScore, bestEnergy)
 		fmt.Printf("\n")
 	}
 
 	result.SuccessRate = float64(successful) / float64(len(ensemble))
 
 	if bestStructure == nil {
+		if cancelErr != nil {
+			return result, cancelErr
+		}
 		return nil, fmt.Errorf("all optimizations failed")
 	}
 
+	if config.CheckpointDir != "" && cancelErr == nil {
+		var err error
+		ensemble, err = run.writeOptimizationCheckpoint(ensemble, candidates, bestIndex, bestEnergy, bestOptResult)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write optimization checkpoint: %w", err)
+		}
+		bestStructure = ensemble[bestIndex]
+	}
+
+	return run.selectAndValidate(ensemble, bestStructure, bestEnergy, bestOptResult, consensusIndex, cancelErr)
+}
+
+// selectAndValidate runs Phase D: scores, validates and writes the best
+// structure; consensusIndex is the ensemble index of the consensus (-1: none)
+func (run *pipelineRun) selectAndValidate(ensemble []*parser.Protein, bestStructure *parser.Protein,
+	bestEnergy float64, bestOptResult *optimization.OptimizationResult, consensusIndex int, cancelErr error) (*UnifiedPipelineV2Result, error) {
+
+	config := run.config
+	result := run.result
+	experimental := run.experimental
+	ssPred := run.ssPred
+
 	// PHASE D: SELECTION & VALIDATION
 	if config.Verbose {
 		fmt.Printf("Phase D: Final Structure Selection\n")
 	}
 
 	result.FinalStructure = bestStructure
+	if consensusIndex >= 0 {
+		result.ConsensusStructure = ensemble[consensusIndex]
+		if config.Verbose {
+			fmt.Printf("  Selection: %s (consensus is model %d)\n", config.Selection, consensusIndex+1)
+		}
+	}
 	result.FinalAngles = geometry.CalculateRamachandran(bestStructure)
+	result.EnsembleMeanRMSD, result.EnsembleEffectiveSize = sampling.EnsembleDiversity(ensemble)
+	result.ResidueConfidence = sampling.ResidueConfidence(bestStructure, ensemble)
+	parser.SetResidueTempFactors(bestStructure, result.ResidueConfidence)
 	result.FinalEnergy = bestEnergy
 	result.OptimizationResult = bestOptResult
+	result.Disulfides = physics.DetectDisulfides(bestStructure)
+	result.ResidueEnergies = physics.DecomposeEnergyPerResidue(bestStructure, physics.DefaultEnergyConfig())
+	result.HighEnergyResidues = physics.HighEnergyResidues(result.ResidueEnergies, highEnergyResidueSigma)
+	if len(config.Restraints) > 0 {
+		result.RestraintEnergy = restraintEnergy(bestStructure, config.Restraints)
+		if config.Verbose {
+			fmt.Printf("  Restraints: %d, violation energy %.2f kcal/mol\n", len(config.Restraints), result.RestraintEnergy)
+		}
+	}
+
+	result.RadiusOfGyration = validation.RadiusOfGyration(bestStructure)
+	result.ExpectedRadiusOfGyration = validation.ExpectedRadiusOfGyration(len(bestStructure.Residues))
+	result.CompactnessScore = validation.CompactnessScore(bestStructure)
+
+	if config.Verbose {
+		fmt.Printf("  Ensemble: %d structures, %d distinct at 2 Å (mean pairwise RMSD %.2f Å)\n",
+			len(ensemble), result.EnsembleEffectiveSize, result.EnsembleMeanRMSD)
+		fmt.Printf("  Mean residue confidence: %.1f / 100\n", stats.Mean(result.ResidueConfidence))
+		fmt.Printf("  Radius of gyration: %.2f Å (folded ≈ %.2f Å, compactness %.3f)\n",
+			result.RadiusOfGyration, result.ExpectedRadiusOfGyration, result.CompactnessScore)
+	}
+
+	if config.Verbose && len(result.HighEnergyResidues) > 0 {
+		fmt.Printf("  High-energy residues: %d\n", len(result.HighEnergyResidues))
+		for _, i := range result.HighEnergyResidues {
+			r := result.ResidueEnergies[i]
+			fmt.Printf("    %s %d: %.1f kcal/mol (bonded %.1f, non-bonded %.1f)\n",
+				r.Name, r.SeqNum, r.Total, r.Bonded(), r.NonBonded())
+		}
+	}
+
+	if config.Verbose && len(result.Disulfides) > 0 {
+		fmt.Printf("  Disulfides: %d\n", len(result.Disulfides))
+		for _, d := range result.Disulfides {
+			fmt.Printf("    %s\n", d)
+		}
+	}
 
 	// Calculate Vedic score
 	result.FinalVedicScore = prediction.ScoreProteinVedicHarmonics(
@@ -429,9 +652,14 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		config.VedicBias,
 	)
 
-	// Combined score
-	result.CombinedScore = (1.0 - config.VedicBias.VedicWeight) * result.FinalEnergy +
-		config.VedicBias.VedicWeight * (1.0 - result.FinalVedicScore) * 1000.0
+	// Composite score the structure was selected by
+	result.Score = scoring.CompositeScore(bestStructure, scoreWeights(config), run.contacts)
+	result.CombinedScore = result.Score.Total
+	if config.Verbose {
+		w := result.Score.Weighted
+		fmt.Printf("  Composite score: %.4f (energy %.3f, Vedic %.3f, contacts %.3f, Ramachandran %.3f, clash %.3f)\n",
+			result.Score.Total, w.Energy, w.Vedic, w.Contacts, w.Ramachandran, w.Clash)
+	}
 
 	// Validate against experimental if provided
 	if experimental != nil {
@@ -442,6 +670,7 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 			fmt.Printf("  RMSD: %.2f Å\n", comp.RMSD)
 			fmt.Printf("  TM-score: %.3f\n", comp.TMScore)
 			fmt.Printf("  GDT_TS: %.3f\n", comp.GDT_TS)
+			fmt.Printf("  GDT_HA: %.1f\n", comp.GDT_HA)
 		}
 
 		// Quality score: Harmonic mean of metrics
@@ -459,7 +688,27 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		result.QualityScore = 2.0 / (1.0/energyScore + 1.0/vedicScore)
 	}
 
-	result.TotalTimeSeconds = time.Since(startTime).Seconds()
+	// Write outputs (skipped for cancelled runs)
+	if config.OutputPDBPath != "" && cancelErr == nil {
+		if err := parser.WritePDB(bestStructure, config.OutputPDBPath, pipelineRemarks(result)); err != nil {
+			return nil, fmt.Errorf("failed to write output PDB: %w", err)
+		}
+		if config.Verbose {
+			fmt.Printf("  Wrote structure: %s\n", config.OutputPDBPath)
+		}
+	}
+
+	if config.OutputEnsemblePath != "" && cancelErr == nil {
+		remarks := []string{fmt.Sprintf("FOLDVEDIC ENSEMBLE: %d MODELS", len(ensemble))}
+		if err := parser.WritePDBModels(ensemble, config.OutputEnsemblePath, remarks); err != nil {
+			return nil, fmt.Errorf("failed to write ensemble PDB: %w", err)
+		}
+		if config.Verbose {
+			fmt.Printf("  Wrote ensemble: %s (%d models)\n", config.OutputEnsemblePath, len(ensemble))
+		}
+	}
+
+	result.TotalTimeSeconds = time.Since(run.startTime).Seconds()
 
 	if config.Verbose {
 		fmt.Printf("  Vedic Score: %.3f\n", result.FinalVedicScore)
@@ -468,67 +717,63 @@ func RunUnifiedPipelineV2(config UnifiedPipelineV2Config, experimental *parser.P
 		fmt.Printf("=== Pipeline Complete (%.2f seconds) ===\n", result.TotalTimeSeconds)
 	}
 
-	return result, nil
+	return result, cancelErr
 }
 
-// initializeFromSSPrediction creates initial structure from SS prediction
-//
-// BIOCHEMIST:
-// Use predicted helix/sheet regions to set initial (φ, ψ) angles
-// - Helix: φ=-60°, ψ=-45°
-// - Sheet: φ=-120°, ψ=+120°
-// - Coil: Extended φ=-120°, ψ=+120°
-func initializeFromSSPrediction(sequence string, ssPred []prediction.SecondaryStructurePrediction) *parser.Protein {
-	// Create template protein
-	protein := &parser.Protein{
-		Name:     "initialized",
-		Residues: make([]*parser.Residue, len(sequence)),
+// pipelineRemarks summarizes the result as PDB REMARK text
+func pipelineRemarks(result *UnifiedPipelineV2Result) []string {
+	remarks := []string{
+		"FOLDVEDIC UNIFIED PIPELINE V2 PREDICTION",
+		fmt.Sprintf("FINAL ENERGY: %.3f KCAL/MOL", result.FinalEnergy),
+		fmt.Sprintf("VEDIC SCORE: %.4f", result.FinalVedicScore),
+		fmt.Sprintf("RADIUS OF GYRATION: %.3f ANGSTROM (EXPECTED %.3f)",
+			result.RadiusOfGyration, result.ExpectedRadiusOfGyration),
 	}
 
-	for i := range sequence {
-		protein.Residues[i] = &parser.Residue{
-			Name:    string(sequence[i]),
-			SeqNum:  i + 1,
-			ChainID: "A",
-		}
+	if len(result.ResidueConfidence) > 0 {
+		remarks = append(remarks, fmt.Sprintf("MEAN RESIDUE CONFIDENCE: %.1f (B-FACTOR COLUMN, 0-100)",
+			stats.Mean(result.ResidueConfidence)))
 	}
 
-	// Build structure from SS-based angles
-	angles := make([]geometry.RamachandranAngles, len(sequence))
-
-	for i := range sequence {
-		var phi, psi float64
-
-		if i < len(ssPred) {
-			switch ssPred[i].PredictedType {
-			case prediction.AlphaHelix:
-				phi = -60.0 * 3.14159 / 180.0
-				psi = -45.0 * 3.14159 / 180.0
-			case prediction.BetaSheet:
-				phi = -120.0 * 3.14159 / 180.0
-				psi = +120.0 * 3.14159 / 180.0
-			default: // Coil
-				phi = -120.0 * 3.14159 / 180.0
-				psi = +120.0 * 3.14159 / 180.0
-			}
-		} else {
-			// Fallback: extended
-			phi = -120.0 * 3.14159 / 180.0
-			psi = +120.0 * 3.14159 / 180.0
-		}
+	if result.Validation != nil {
+		remarks = append(remarks,
+			fmt.Sprintf("RMSD TO EXPERIMENTAL: %.3f ANGSTROM", result.Validation.RMSD),
+			fmt.Sprintf("TM-SCORE: %.4f", result.Validation.TMScore),
+		)
+	}
 
-		angles[i] = geometry.RamachandranAngles{Phi: phi, Psi: psi}
+	for _, d := range result.Disulfides {
+		remarks = append(remarks, fmt.Sprintf("DISULFIDE: CYS %d - CYS %d", d.SeqNum1, d.SeqNum2))
 	}
 
-	// Build 3D structure from angles
-	// WAVE 11.1: Use quaternion-based coordinate builder (NOVEL!)
-	structure, err := geometry.BuildProteinFromAngles(sequence, angles)
-	if err != nil {
-		// Fallback to simple builder if quaternion method fails
-		return initializeFallback(sequence)
+	for _, i := range result.HighEnergyResidues {
+		r := result.ResidueEnergies[i]
+		remarks = append(remarks, fmt.Sprintf("HIGH ENERGY RESIDUE: %s %d %.1f KCAL/MOL", r.Name, r.SeqNum, r.Total))
 	}
 
-	return structure
+	return remarks
+}
+
+// initializeFromSSPrediction creates initial structure from SS prediction
+//
+// BIOCHEMIST:
+// Use predicted helix/sheet regions to set initial (φ, ψ) angles
+// (geometry.SecondaryStructureAngles)
+// - Helix: φ=-60°, ψ=-45°
+// - Sheet: φ=-120°, ψ=+120°
+// - Turn: φ=-90°, ψ=0°
+// - Coil: PPII φ=-75°, ψ=+150°
+func initializeFromSSPrediction(sequence string, ssPred []prediction.SecondaryStructurePrediction) *parser.Protein {
+	return initializeWithBurial(sequence, ssPred, nil)
+}
+
+// ssInitialAngles returns the (φ, ψ) of each residue's predicted SS type
+func ssInitialAngles(sequence string, ssPred []prediction.SecondaryStructurePrediction) []geometry.RamachandranAngles {
+	ss := make([]prediction.SecondaryStructureType, len(ssPred))
+	for i, p := range ssPred {
+		ss[i] = p.PredictedType
+	}
+	return geometry.SecondaryStructureAnglesFor(len(sequence), ss)
 }
 
 // initializeFallback creates extended chain if coordinate builder fails
@@ -585,7 +830,38 @@ func initializeFallback(sequence string) *parser.Protein {
 // CONVENIENCE FUNCTION:
 // Folds protein using all default Phase 2 enhancements
 func QuickFold(sequence string, verbose bool) (*UnifiedPipelineV2Result, error) {
+	return QuickFoldCtx(context.Background(), sequence, verbose)
+}
+
+// QuickFoldCtx is QuickFold with cancellation (see RunUnifiedPipelineV2Ctx)
+func QuickFoldCtx(ctx context.Context, sequence string, verbose bool) (*UnifiedPipelineV2Result, error) {
 	config := DefaultUnifiedPipelineV2Config(sequence)
 	config.Verbose = verbose
-	return RunUnifiedPipelineV2(config, nil)
+	return RunUnifiedPipelineV2Ctx(ctx, config, nil)
+}
+
+// QuickFoldFASTA folds every record of a FASTA file with QuickFold defaults
+//
+// Results are returned in file order. Parsing is strict (20 standard amino
+// acids only) since the pipeline has no parameters for unknown residues.
+func QuickFoldFASTA(path string, verbose bool) ([]*UnifiedPipelineV2Result, error) {
+	records, err := parser.ParseFASTA(path)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*UnifiedPipelineV2Result, 0, len(records))
+	for _, record := range records {
+		if verbose {
+			fmt.Printf(">%s\n", record.Header)
+		}
+
+		result, err := QuickFold(record.Sequence, verbose)
+		if err != nil {
+			return results, fmt.Errorf("folding %q failed: %w", record.Header, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
 }
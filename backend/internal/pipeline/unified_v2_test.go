@@ -1,8 +1,17 @@
 package pipeline
 
 import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
 )
 
@@ -84,6 +93,183 @@ func TestQuickFold(t *testing.T) {
 	t.Logf("  Time: %.2f seconds", result.TotalTimeSeconds)
 }
 
+// TestQuickFoldWritesPDB runs the QuickFold defaults with output paths and
+// checks the written files parse back
+func TestQuickFoldWritesPDB(t *testing.T) {
+	sequence := "ACDEFG"
+	dir := t.TempDir()
+
+	config := DefaultUnifiedPipelineV2Config(sequence)
+	config.OutputPDBPath = filepath.Join(dir, "best.pdb")
+	config.OutputEnsemblePath = filepath.Join(dir, "ensemble.pdb")
+
+	result, err := RunUnifiedPipelineV2(config, nil)
+	if err != nil {
+		t.Fatalf("Pipeline failed: %v", err)
+	}
+
+	parsed, err := parser.ParsePDB(config.OutputPDBPath)
+	if err != nil {
+		t.Fatalf("Failed to parse written PDB: %v", err)
+	}
+	if len(parsed.Residues) != len(sequence) {
+		t.Errorf("Residue count mismatch: %d vs %d", len(parsed.Residues), len(sequence))
+	}
+	if parsed.Sequence() != sequence {
+		t.Errorf("Sequence mismatch: %s vs %s", parsed.Sequence(), sequence)
+	}
+	if len(result.ResidueConfidence) != len(sequence) {
+		t.Fatalf("Got %d residue confidences, want %d", len(result.ResidueConfidence), len(sequence))
+	}
+	for i, res := range parsed.Residues {
+		if want := result.ResidueConfidence[i]; math.Abs(res.CA.TempFacto-want) > 0.005 {
+			t.Errorf("Residue %d B-factor %.2f, want confidence %.2f", i+1, res.CA.TempFacto, want)
+		}
+	}
+
+	data, err := os.ReadFile(config.OutputPDBPath)
+	if err != nil {
+		t.Fatalf("Failed to read written PDB: %v", err)
+	}
+	if !strings.Contains(string(data), "REMARK   1 FINAL ENERGY") {
+		t.Error("Written PDB missing energy remark")
+	}
+
+	ensemble, err := os.ReadFile(config.OutputEnsemblePath)
+	if err != nil {
+		t.Fatalf("Failed to read ensemble PDB: %v", err)
+	}
+	models := strings.Count(string(ensemble), "ENDMDL")
+	if models != result.TotalSamplesGenerated {
+		t.Errorf("Ensemble has %d models, expected %d", models, result.TotalSamplesGenerated)
+	}
+
+	t.Logf("Wrote %d residues and %d ensemble models", len(parsed.Residues), models)
+}
+
+// TestRunUnifiedPipelineV2CtxCancelled checks a cancelled context stops the
+// pipeline before sampling and returns ctx.Err()
+func TestRunUnifiedPipelineV2CtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := RunUnifiedPipelineV2Ctx(ctx, DefaultUnifiedPipelineV2Config("ACDEFG"), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if result == nil || result.TotalSamplesGenerated != 0 || result.FinalStructure != nil {
+		t.Errorf("Expected partial result with predictions only, got %+v", result)
+	}
+}
+
+// TestOptimizeCandidateCancelledMidway cancels one candidate's optimization
+// partway through and checks it stops promptly and is skipped
+func TestOptimizeCandidateCancelledMidway(t *testing.T) {
+	sequence := strings.Repeat("AEELLKKAEELLKK", 4)
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60 * math.Pi / 180, Psi: -45 * math.Pi / 180}
+	}
+	structure, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	config := DefaultUnifiedPipelineV2Config(sequence)
+	config.CAPrefoldIterations = 2000
+
+	start := time.Now()
+	optimizeCandidate(context.Background(), structure, nil, nil, config)
+	fullTime := time.Since(start)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fullTime/10)
+	defer cancel()
+	start = time.Now()
+	cut := optimizeCandidate(ctx, structure, nil, nil, config)
+	cutTime := time.Since(start)
+	t.Logf("Full candidate %v; cancelled after %v returned in %v (%s)", fullTime, fullTime/10, cutTime, cut.skipReason)
+
+	if !strings.Contains(cut.skipReason, context.DeadlineExceeded.Error()) {
+		t.Errorf("Cancelled candidate not skipped for the deadline: %q", cut.skipReason)
+	}
+	if cutTime > fullTime/2 {
+		t.Errorf("Cancelled candidate took %v, full run %v", cutTime, fullTime)
+	}
+}
+
+// TestParallelOptimizationMatchesSequential checks the worker pool selects
+// the same best structure as a single worker
+func TestParallelOptimizationMatchesSequential(t *testing.T) {
+	sequence := "ACDEFGHIK"
+
+	sequential := DefaultUnifiedPipelineV2Config(sequence)
+	sequential.MaxWorkers = 1
+	seqResult, err := RunUnifiedPipelineV2(sequential, nil)
+	if err != nil {
+		t.Fatalf("Sequential pipeline failed: %v", err)
+	}
+
+	parallel := DefaultUnifiedPipelineV2Config(sequence)
+	parallel.MaxWorkers = 4
+	parResult, err := RunUnifiedPipelineV2(parallel, nil)
+	if err != nil {
+		t.Fatalf("Parallel pipeline failed: %v", err)
+	}
+
+	t.Logf("Sequential: E = %.4f, success %.2f", seqResult.FinalEnergy, seqResult.SuccessRate)
+	t.Logf("Parallel:   E = %.4f, success %.2f", parResult.FinalEnergy, parResult.SuccessRate)
+
+	if seqResult.FinalEnergy != parResult.FinalEnergy {
+		t.Errorf("Best energy differs: sequential %.6f vs parallel %.6f", seqResult.FinalEnergy, parResult.FinalEnergy)
+	}
+	if seqResult.SuccessRate != parResult.SuccessRate {
+		t.Errorf("Success rate differs: %.3f vs %.3f", seqResult.SuccessRate, parResult.SuccessRate)
+	}
+	for i, atom := range seqResult.FinalStructure.Atoms {
+		other := parResult.FinalStructure.Atoms[i]
+		if atom.X != other.X || atom.Y != other.Y || atom.Z != other.Z {
+			t.Fatalf("Best structures differ at atom %d", i)
+		}
+	}
+}
+
+// TestConsensusSelection checks Phase D reports the largest cluster's
+// centroid, and that SelectionConsensus makes it the final structure
+func TestConsensusSelection(t *testing.T) {
+	sequence := "ACDEFGHIK"
+
+	config := DefaultUnifiedPipelineV2Config(sequence)
+	config.MaxWorkers = 2
+	bestResult, err := RunUnifiedPipelineV2(config, nil)
+	if err != nil {
+		t.Fatalf("Best-score pipeline failed: %v", err)
+	}
+	if bestResult.ConsensusStructure == nil {
+		t.Fatal("No consensus structure reported")
+	}
+
+	config.Selection = SelectionConsensus
+	consensusResult, err := RunUnifiedPipelineV2(config, nil)
+	if err != nil {
+		t.Fatalf("Consensus pipeline failed: %v", err)
+	}
+	t.Logf("Best score: E = %.2f; consensus: E = %.2f", bestResult.FinalEnergy, consensusResult.FinalEnergy)
+
+	if consensusResult.FinalStructure != consensusResult.ConsensusStructure {
+		t.Error("SelectionConsensus did not make the consensus the final structure")
+	}
+	for i, atom := range consensusResult.FinalStructure.Atoms {
+		other := bestResult.ConsensusStructure.Atoms[i]
+		if atom.X != other.X || atom.Y != other.Y || atom.Z != other.Z {
+			t.Fatalf("Consensus differs between selection modes at atom %d", i)
+		}
+	}
+
+	config.Selection = "medoid"
+	if err := ValidateConfig(config); err == nil {
+		t.Error("ValidateConfig accepted an unknown Selection")
+	}
+}
+
 // TestRunUnifiedPipelineV2WithCustomConfig tests custom configuration
 func TestRunUnifiedPipelineV2WithCustomConfig(t *testing.T) {
 	sequence := "GACDEF"
@@ -127,9 +313,19 @@ func TestRunUnifiedPipelineV2WithCustomConfig(t *testing.T) {
 		t.Error("Contact map should be empty (disabled)")
 	}
 
+	// Compactness is reported for the final structure
+	if result.RadiusOfGyration <= 0 || result.ExpectedRadiusOfGyration <= 0 {
+		t.Errorf("Radius of gyration not reported: %.2f (expected %.2f)",
+			result.RadiusOfGyration, result.ExpectedRadiusOfGyration)
+	}
+	if result.CompactnessScore < 0 || result.CompactnessScore > 1 {
+		t.Errorf("CompactnessScore %.3f outside [0, 1]", result.CompactnessScore)
+	}
+
 	t.Logf("Custom config test passed")
 	t.Logf("  Energy: %.2f kcal/mol", result.FinalEnergy)
 	t.Logf("  Vedic: %.3f", result.FinalVedicScore)
+	t.Logf("  Rg: %.2f Å (expected %.2f Å)", result.RadiusOfGyration, result.ExpectedRadiusOfGyration)
 }
 
 // TestInitializeFromSSPrediction tests structure initialization
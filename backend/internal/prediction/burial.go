@@ -0,0 +1,129 @@
+// Package prediction - Burial profile from hydrophobicity
+//
+// Secondary structure says how the chain is locally shaped, not which side
+// of it faces the core. PredictBurialProfile estimates, per residue, the
+// probability of being buried from two sequence signals: the mean Kyte-
+// Doolittle hydropathy of a sliding window (a hydrophobic stretch is a core
+// strand or a transmembrane segment) and the hydrophobic moment of the
+// window (an amphipathic helix or strand lies on the surface with one face
+// buried, so there the residue's own hydropathy decides its side).
+//
+// BIOCHEMIST: Hydrophobic residues pack into the core (or the bilayer);
+// charged loops stay solvated
+// MATHEMATICIAN: μH = |Σ h_j e^{ijδ}| / w at δ = 100° (α-helix) and 160°
+// (β-strand); μH / mean|h| ∈ [0, 1] measures amphipathicity
+// ETHICIST: A sequence-only estimate - a prior for initialization, not a
+// solvent accessibility prediction
+//
+// CITATION:
+// Kyte, J., & Doolittle, R. F. (1982). "A simple method for displaying the
+// hydropathic character of a protein." J. Mol. Biol. 157(1): 105-132.
+//
+// Eisenberg, D., Weiss, R. M., & Terwilliger, T. C. (1982). "The helical
+// hydrophobic moment: a measure of the amphiphilicity of a helix."
+// Nature 299: 371-374.
+package prediction
+
+import (
+	"math"
+	"strings"
+)
+
+// kyteDoolittle is the Kyte-Doolittle hydropathy scale (-4.5 to 4.5)
+var kyteDoolittle = map[byte]float64{
+	'I': 4.5, 'V': 4.2, 'L': 3.8, 'F': 2.8, 'C': 2.5,
+	'M': 1.9, 'A': 1.8, 'G': -0.4, 'T': -0.7, 'S': -0.8,
+	'W': -0.9, 'Y': -1.3, 'P': -1.6, 'H': -3.2, 'E': -3.5,
+	'Q': -3.5, 'D': -3.5, 'N': -3.5, 'K': -3.9, 'R': -4.5,
+}
+
+// Burial profile parameters
+const (
+	burialHydropathyWindow = 9   // Residues averaged for the local hydropathy
+	burialMomentWindow     = 11  // Residues in the hydrophobic moment (~3 helical turns)
+	burialWindowWeight     = 0.6 // Share of the window mean in the burial score
+	burialOffset           = 0.1 // Score of a half-buried residue is -burialOffset
+	burialSteepness        = 4.0 // Logistic steepness
+	kyteDoolittleMax       = 4.5 // Normalizes hydropathy to [-1, 1]
+	helixMomentAngle       = 100 // Degrees per residue, α-helix
+	strandMomentAngle      = 160 // Degrees per residue, β-strand
+)
+
+// PredictBurialProfile returns the probability that each residue is buried,
+// in [0, 1]
+//
+// ALGORITHM (hydropathy h normalized to [-1, 1]):
+//  1. H̄_i = mean h over a window of 9 centred on i (clipped at the termini)
+//  2. A_i = max(μH at 100°, μH at 160°) / mean|h| over a window of 11
+//  3. s_i = 0.6·H̄_i + 0.4·h_i·(0.5 + 0.5·A_i)
+//  4. p_i = 1 / (1 + exp(-4·(s_i + 0.1)))
+//
+// Unknown residue letters count as neutral (h = 0). Lowercase is accepted.
+func PredictBurialProfile(sequence string) []float64 {
+	sequence = strings.ToUpper(sequence)
+	n := len(sequence)
+	if n == 0 {
+		return nil
+	}
+
+	h := make([]float64, n)
+	for i := 0; i < n; i++ {
+		h[i] = kyteDoolittle[sequence[i]] / kyteDoolittleMax
+	}
+
+	profile := make([]float64, n)
+	for i := range profile {
+		mean := windowMean(h, i, burialHydropathyWindow)
+		amphipathicity := math.Max(
+			amphipathicMoment(h, i, burialMomentWindow, helixMomentAngle),
+			amphipathicMoment(h, i, burialMomentWindow, strandMomentAngle),
+		)
+
+		score := burialWindowWeight*mean + (1-burialWindowWeight)*h[i]*(0.5+0.5*amphipathicity)
+		profile[i] = 1.0 / (1.0 + math.Exp(-burialSteepness*(score+burialOffset)))
+	}
+
+	return profile
+}
+
+// windowBounds returns [lo, hi) for a window of width w centred on i
+func windowBounds(n, i, w int) (lo, hi int) {
+	lo = i - w/2
+	hi = lo + w
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > n {
+		hi = n
+	}
+	return lo, hi
+}
+
+// windowMean returns the mean of values over a window of width w centred on i
+func windowMean(values []float64, i, w int) float64 {
+	lo, hi := windowBounds(len(values), i, w)
+	sum := 0.0
+	for j := lo; j < hi; j++ {
+		sum += values[j]
+	}
+	return sum / float64(hi-lo)
+}
+
+// amphipathicMoment returns the hydrophobic moment of the window centred on
+// i at angleDeg per residue, divided by the window's mean |h| (0 for an
+// all-neutral window)
+func amphipathicMoment(h []float64, i, w int, angleDeg float64) float64 {
+	lo, hi := windowBounds(len(h), i, w)
+	delta := angleDeg * math.Pi / 180.0
+
+	var sumCos, sumSin, sumAbs float64
+	for j := lo; j < hi; j++ {
+		sumCos += h[j] * math.Cos(float64(j)*delta)
+		sumSin += h[j] * math.Sin(float64(j)*delta)
+		sumAbs += math.Abs(h[j])
+	}
+	if sumAbs == 0 {
+		return 0
+	}
+	return math.Hypot(sumCos, sumSin) / sumAbs
+}
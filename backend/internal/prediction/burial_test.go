@@ -0,0 +1,78 @@
+package prediction
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPredictBurialProfile checks a transmembrane-like hydrophobic stretch
+// scores as buried and a charged loop as exposed
+func TestPredictBurialProfile(t *testing.T) {
+	loop := "DEKRKDEGKRED"
+	core := "LIVALLIVFAVLIL"
+	sequence := loop + core + loop
+
+	profile := PredictBurialProfile(sequence)
+	if len(profile) != len(sequence) {
+		t.Fatalf("Profile length %d, want %d", len(profile), len(sequence))
+	}
+
+	mean := func(lo, hi int) float64 {
+		sum := 0.0
+		for _, p := range profile[lo:hi] {
+			if p < 0 || p > 1 {
+				t.Fatalf("Burial probability %.3f outside [0, 1]", p)
+			}
+			sum += p
+		}
+		return sum / float64(hi-lo)
+	}
+
+	// Skip the 3 residues at each boundary, where the windows straddle both
+	coreStart, coreEnd := len(loop), len(loop)+len(core)
+	coreBurial := mean(coreStart+3, coreEnd-3)
+	loopBurial := mean(0, len(loop)-3)
+	t.Logf("Mean burial: core %.3f, charged loop %.3f", coreBurial, loopBurial)
+
+	if coreBurial < 0.8 {
+		t.Errorf("Hydrophobic stretch burial %.3f, want ≥ 0.8", coreBurial)
+	}
+	if loopBurial > 0.2 {
+		t.Errorf("Charged loop burial %.3f, want ≤ 0.2", loopBurial)
+	}
+
+	// Case-insensitive
+	lower := PredictBurialProfile(strings.ToLower(sequence))
+	for i := range profile {
+		if lower[i] != profile[i] {
+			t.Fatalf("Lowercase profile differs at %d: %.4f vs %.4f", i, lower[i], profile[i])
+		}
+	}
+}
+
+// TestBurialProfileAmphipathicHelix checks the hydrophobic face of an
+// amphipathic helix scores above its polar face
+func TestBurialProfileAmphipathicHelix(t *testing.T) {
+	// Heptad-like pattern: hydrophobic at a/d/e positions of an α-helical wheel
+	sequence := "LKKLLKELKKLLKELKKLLKE"
+	profile := PredictBurialProfile(sequence)
+
+	var hydrophobic, polar float64
+	var nh, np int
+	for i := 3; i < len(sequence)-3; i++ {
+		if sequence[i] == 'L' {
+			hydrophobic += profile[i]
+			nh++
+		} else {
+			polar += profile[i]
+			np++
+		}
+	}
+	hydrophobic /= float64(nh)
+	polar /= float64(np)
+	t.Logf("Amphipathic helix: hydrophobic face %.3f, polar face %.3f", hydrophobic, polar)
+
+	if hydrophobic <= polar+0.2 {
+		t.Errorf("Hydrophobic face %.3f should exceed polar face %.3f by > 0.2", hydrophobic, polar)
+	}
+}
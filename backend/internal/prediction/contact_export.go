@@ -0,0 +1,243 @@
+// Package prediction - Contact map export
+//
+// Contact predictions were only ever counted (ValidateContactMap,
+// GetContactRangeStatistics). These writers put them in a form that can be
+// read, plotted (numpy.loadtxt on the matrix, imshow) and compared with
+// the native map pair by pair.
+//
+// FORMAT: plain text. Lines starting with '#' are headers; a "# MATRIX"
+// header is followed by L rows of L values, a "# LIST" header by one
+// contact per line. Residue numbers in lists are 1-based, as in the CASP
+// RR format; ContactPrediction indices are 0-based.
+//
+// BIOCHEMIST: Predicted-vs-native overlays show which secondary structure
+// elements a predictor gets right (TP bands) and which it invents (FP)
+// MATHEMATICIAN: Contact maps are symmetric; the matrix writes both
+// triangles so it loads as an ordinary square array
+package prediction
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Contact comparison classes
+const (
+	ContactTruePositive  = "TP" // Predicted and native
+	ContactFalsePositive = "FP" // Predicted only
+	ContactFalseNegative = "FN" // Native only
+)
+
+// contactPair is a contact with Residue1 < Residue2
+type contactPair struct {
+	i, j  int
+	score float64
+}
+
+// WriteContactMap writes contacts for a sequence of length L as a dense
+// L×L score matrix followed by a ranked contact list
+//
+// Matrix entry (i, j) is the score of contact i-j, 0 if not predicted, and
+// equals entry (j, i). The list gives rank, residue numbers i < j, score,
+// range class (see ClassifyContact) and sequence separation, best score
+// first. Duplicate pairs keep their highest score. Returns an error if a
+// contact lies outside [0, L).
+func WriteContactMap(contacts []ContactPrediction, L int, w io.Writer) error {
+	pairs, err := uniqueContactPairs(contacts, L)
+	if err != nil {
+		return err
+	}
+
+	matrix := make([][]float64, L)
+	for i := range matrix {
+		matrix[i] = make([]float64, L)
+	}
+	for _, p := range pairs {
+		matrix[p.i][p.j] = p.score
+		matrix[p.j][p.i] = p.score
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# CONTACT MAP: L=%d, %d contacts\n", L, len(pairs))
+	fmt.Fprintf(bw, "# MATRIX: %d x %d scores, symmetric, 0 = no contact\n", L, L)
+	for _, row := range matrix {
+		for j, score := range row {
+			if j > 0 {
+				bw.WriteByte(' ')
+			}
+			fmt.Fprintf(bw, "%.3f", score)
+		}
+		bw.WriteByte('\n')
+	}
+
+	fmt.Fprintln(bw, "# LIST: rank i j score range separation")
+	for rank, p := range pairs {
+		fmt.Fprintf(bw, "%d %d %d %.3f %s %d\n", rank+1, p.i+1, p.j+1, p.score, contactRangeOf(p), p.j-p.i)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write contact map: %w", err)
+	}
+	return nil
+}
+
+// WriteContactComparison overlays predicted contacts on native ones for a
+// sequence of length L
+//
+// The header carries TP/FP/FN counts with precision and recall. The matrix
+// is L×L symbols, symmetric: 'T' true positive, 'P' false positive, 'N'
+// false negative, '.' neither. The list has one line per pair: class (TP,
+// FP or FN), residue numbers i < j, predicted score (0 for FN) and range
+// class; predicted pairs come first, best score first, then missed native
+// pairs in residue order. Get native contacts with NativeContacts.
+func WriteContactComparison(predicted, native []ContactPrediction, L int, w io.Writer) error {
+	predPairs, err := uniqueContactPairs(predicted, L)
+	if err != nil {
+		return fmt.Errorf("predicted contacts: %w", err)
+	}
+	nativePairs, err := uniqueContactPairs(native, L)
+	if err != nil {
+		return fmt.Errorf("native contacts: %w", err)
+	}
+
+	isNative := make(map[[2]int]bool, len(nativePairs))
+	for _, p := range nativePairs {
+		isNative[[2]int{p.i, p.j}] = true
+	}
+	isPredicted := make(map[[2]int]bool, len(predPairs))
+	truePositives := 0
+	for _, p := range predPairs {
+		isPredicted[[2]int{p.i, p.j}] = true
+		if isNative[[2]int{p.i, p.j}] {
+			truePositives++
+		}
+	}
+	falsePositives := len(predPairs) - truePositives
+	falseNegatives := len(nativePairs) - truePositives
+
+	precision, recall := 0.0, 0.0
+	if len(predPairs) > 0 {
+		precision = float64(truePositives) / float64(len(predPairs))
+	}
+	if len(nativePairs) > 0 {
+		recall = float64(truePositives) / float64(len(nativePairs))
+	}
+
+	matrix := make([][]byte, L)
+	for i := range matrix {
+		matrix[i] = make([]byte, L)
+		for j := range matrix[i] {
+			matrix[i][j] = '.'
+		}
+	}
+	mark := func(p contactPair, symbol byte) {
+		matrix[p.i][p.j] = symbol
+		matrix[p.j][p.i] = symbol
+	}
+	for _, p := range nativePairs {
+		mark(p, 'N')
+	}
+	for _, p := range predPairs {
+		if isNative[[2]int{p.i, p.j}] {
+			mark(p, 'T')
+		} else {
+			mark(p, 'P')
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# CONTACT COMPARISON: L=%d, %d predicted, %d native\n", L, len(predPairs), len(nativePairs))
+	fmt.Fprintf(bw, "# TP %d FP %d FN %d, precision %.3f, recall %.3f\n",
+		truePositives, falsePositives, falseNegatives, precision, recall)
+	fmt.Fprintf(bw, "# MATRIX: %d x %d, symmetric, T = TP, P = FP, N = FN, . = none\n", L, L)
+	for _, row := range matrix {
+		for j, symbol := range row {
+			if j > 0 {
+				bw.WriteByte(' ')
+			}
+			bw.WriteByte(symbol)
+		}
+		bw.WriteByte('\n')
+	}
+
+	fmt.Fprintln(bw, "# LIST: class i j score range")
+	for _, p := range predPairs {
+		class := ContactFalsePositive
+		if isNative[[2]int{p.i, p.j}] {
+			class = ContactTruePositive
+		}
+		fmt.Fprintf(bw, "%s %d %d %.3f %s\n", class, p.i+1, p.j+1, p.score, contactRangeOf(p))
+	}
+	sort.Slice(nativePairs, func(a, b int) bool {
+		if nativePairs[a].i != nativePairs[b].i {
+			return nativePairs[a].i < nativePairs[b].i
+		}
+		return nativePairs[a].j < nativePairs[b].j
+	})
+	for _, p := range nativePairs {
+		if !isPredicted[[2]int{p.i, p.j}] {
+			fmt.Fprintf(bw, "%s %d %d %.3f %s\n", ContactFalseNegative, p.i+1, p.j+1, 0.0, contactRangeOf(p))
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write contact comparison: %w", err)
+	}
+	return nil
+}
+
+// NativeContacts returns the contacts of an experimental structure: Cα
+// pairs closer than config.ContactThreshold and at least
+// config.MinSequenceSeparation apart, the set ValidateContactMap scores
+// against
+func NativeContacts(protein *parser.Protein, config ContactMapConfig) []ContactPrediction {
+	if protein == nil {
+		return nil
+	}
+	return extractNativeContacts(protein, config.ContactThreshold, config.MinSequenceSeparation)
+}
+
+// uniqueContactPairs orders each contact as i < j, merges duplicates
+// (highest score) and sorts by score, best first, then by residue
+func uniqueContactPairs(contacts []ContactPrediction, L int) ([]contactPair, error) {
+	best := make(map[[2]int]float64, len(contacts))
+	for _, c := range contacts {
+		i, j := c.Residue1, c.Residue2
+		if i < 0 || j < 0 || i >= L || j >= L {
+			return nil, fmt.Errorf("contact %d-%d outside sequence of length %d", i, j, L)
+		}
+		if i == j {
+			continue
+		}
+		if i > j {
+			i, j = j, i
+		}
+		if score, ok := best[[2]int{i, j}]; !ok || c.Score > score {
+			best[[2]int{i, j}] = c.Score
+		}
+	}
+
+	pairs := make([]contactPair, 0, len(best))
+	for key, score := range best {
+		pairs = append(pairs, contactPair{i: key[0], j: key[1], score: score})
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a].score != pairs[b].score {
+			return pairs[a].score > pairs[b].score
+		}
+		if pairs[a].i != pairs[b].i {
+			return pairs[a].i < pairs[b].i
+		}
+		return pairs[a].j < pairs[b].j
+	})
+	return pairs, nil
+}
+
+// contactRangeOf classifies a pair by its sequence separation
+func contactRangeOf(p contactPair) ContactRange {
+	return ClassifyContact(ContactPrediction{Distance: p.j - p.i})
+}
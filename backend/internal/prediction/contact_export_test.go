@@ -0,0 +1,150 @@
+package prediction
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// readContactExport splits an export into its matrix rows and list lines
+func readContactExport(t *testing.T, text string) (matrix [][]string, list [][]string) {
+	t.Helper()
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# MATRIX"):
+			section = "matrix"
+		case strings.HasPrefix(line, "# LIST"):
+			section = "list"
+		case strings.HasPrefix(line, "#"):
+		case section == "matrix":
+			matrix = append(matrix, strings.Fields(line))
+		case section == "list":
+			list = append(list, strings.Fields(line))
+		}
+	}
+	return matrix, list
+}
+
+// TestWriteContactMap serializes a small contact set, parses it back and
+// checks the matrix is square, symmetric and holds every score, and that
+// the list is ranked with range classes
+func TestWriteContactMap(t *testing.T) {
+	const L = 30
+	contacts := []ContactPrediction{
+		{Residue1: 2, Residue2: 9, Score: 0.4},
+		{Residue1: 20, Residue2: 5, Score: 0.9}, // Given as j, i
+		{Residue1: 3, Residue2: 29, Score: 0.75},
+		{Residue1: 2, Residue2: 9, Score: 0.6}, // Duplicate: higher score kept
+	}
+
+	var buf bytes.Buffer
+	if err := WriteContactMap(contacts, L, &buf); err != nil {
+		t.Fatalf("WriteContactMap failed: %v", err)
+	}
+	matrix, list := readContactExport(t, buf.String())
+
+	if len(matrix) != L {
+		t.Fatalf("Matrix has %d rows, want %d", len(matrix), L)
+	}
+	scores := make([][]float64, L)
+	for i, row := range matrix {
+		if len(row) != L {
+			t.Fatalf("Row %d has %d columns, want %d", i, len(row), L)
+		}
+		scores[i] = make([]float64, L)
+		for j, field := range row {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				t.Fatalf("Entry (%d, %d) %q: %v", i, j, field, err)
+			}
+			scores[i][j] = v
+		}
+	}
+	nonzero := 0
+	for i := 0; i < L; i++ {
+		for j := 0; j < L; j++ {
+			if scores[i][j] != scores[j][i] {
+				t.Errorf("Matrix not symmetric at (%d, %d): %.3f vs %.3f", i, j, scores[i][j], scores[j][i])
+			}
+			if scores[i][j] != 0 {
+				nonzero++
+			}
+		}
+	}
+	want := map[[2]int]float64{{5, 20}: 0.9, {3, 29}: 0.75, {2, 9}: 0.6}
+	for pair, score := range want {
+		if scores[pair[0]][pair[1]] != score {
+			t.Errorf("Entry %v = %.3f, want %.3f", pair, scores[pair[0]][pair[1]], score)
+		}
+	}
+	if nonzero != 2*len(want) {
+		t.Errorf("%d nonzero entries, want %d", nonzero, 2*len(want))
+	}
+
+	// rank i j score range separation, 1-based residues, best first
+	wantList := [][]string{
+		{"1", "6", "21", "0.900", "medium", "15"},
+		{"2", "4", "30", "0.750", "long", "26"},
+		{"3", "3", "10", "0.600", "short", "7"},
+	}
+	if len(list) != len(wantList) {
+		t.Fatalf("List has %d contacts, want %d:\n%s", len(list), len(wantList), buf.String())
+	}
+	for k, fields := range wantList {
+		if strings.Join(list[k], " ") != strings.Join(fields, " ") {
+			t.Errorf("List line %d = %v, want %v", k+1, list[k], fields)
+		}
+	}
+
+	if err := WriteContactMap([]ContactPrediction{{Residue1: 0, Residue2: L}}, L, &buf); err == nil {
+		t.Errorf("Contact outside the sequence was accepted")
+	}
+}
+
+// TestWriteContactComparison overlays predictions on a native set with one
+// pair of each class
+func TestWriteContactComparison(t *testing.T) {
+	const L = 12
+	predicted := []ContactPrediction{
+		{Residue1: 0, Residue2: 7, Score: 0.8},  // TP
+		{Residue1: 1, Residue2: 10, Score: 0.5}, // FP
+	}
+	native := []ContactPrediction{
+		{Residue1: 0, Residue2: 7, Score: 1},
+		{Residue1: 3, Residue2: 11, Score: 1}, // FN
+	}
+
+	var buf bytes.Buffer
+	if err := WriteContactComparison(predicted, native, L, &buf); err != nil {
+		t.Fatalf("WriteContactComparison failed: %v", err)
+	}
+	t.Logf("\n%s", buf.String())
+	if !strings.Contains(buf.String(), "# TP 1 FP 1 FN 1, precision 0.500, recall 0.500") {
+		t.Errorf("Missing TP/FP/FN summary")
+	}
+
+	matrix, list := readContactExport(t, buf.String())
+	for _, cell := range []struct {
+		i, j   int
+		symbol string
+	}{{0, 7, "T"}, {1, 10, "P"}, {3, 11, "N"}, {2, 5, "."}} {
+		if matrix[cell.i][cell.j] != cell.symbol || matrix[cell.j][cell.i] != cell.symbol {
+			t.Errorf("Cells (%d, %d) = %s/%s, want %s", cell.i, cell.j, matrix[cell.i][cell.j], matrix[cell.j][cell.i], cell.symbol)
+		}
+	}
+
+	wantList := []string{"TP 1 8 0.800 short", "FP 2 11 0.500 short", "FN 4 12 0.000 short"}
+	if len(list) != len(wantList) {
+		t.Fatalf("List has %d lines, want %d", len(list), len(wantList))
+	}
+	for k, line := range wantList {
+		if strings.Join(list[k], " ") != line {
+			t.Errorf("List line %d = %q, want %q", k+1, strings.Join(list[k], " "), line)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"sort"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
 )
 
 // ContactPrediction represents a predicted residue-residue contact
@@ -43,7 +44,7 @@ type ContactPrediction struct {
 // ContactMapConfig holds contact prediction parameters
 type ContactMapConfig struct {
 	// Prediction method
-	Method string // "MI", "DCA", "Vedic", "Consensus"
+	Method string // "MI", "DCA", "Vedic", "Consensus" (MSA input: "MI" or "DCA")
 
 	// Minimum sequence separation
 	// Typical: 6 (short-range), 12 (medium-range), 24 (long-range)
@@ -59,6 +60,11 @@ type ContactMapConfig struct {
 
 	// Use Vedic harmonic scoring
 	UseVedicScoring bool
+
+	// MSA-based prediction (PredictContactMapFromMSA)
+	IdentityThreshold     float64 // Sequences at least this identical share one unit of weight
+	MaxGapFraction        float64 // Columns with more gaps than this are not scored
+	MinEffectiveSequences float64 // Minimum Meff (weighted depth) for coevolution analysis
 }
 
 // DefaultContactMapConfig returns recommended parameters
@@ -69,6 +75,9 @@ func DefaultContactMapConfig() ContactMapConfig {
 		MaxContacts:           100,
 		ContactThreshold:      8.0,
 		UseVedicScoring:       true,
+		IdentityThreshold:     0.8,
+		MaxGapFraction:        0.5,
+		MinEffectiveSequences: 20.0,
 	}
 }
 
@@ -175,9 +184,7 @@ func predictContactsMI(sequence string, config ContactMapConfig) ([]ContactPredi
 	}
 
 	// Sort by score (descending)
-	sort.Slice(contacts, func(i, j int) bool {
-		return contacts[i].Score > contacts[j].Score
-	})
+	sortContactsByScore(contacts)
 
 	// Limit to MaxContacts
 	if len(contacts) > config.MaxContacts {
@@ -257,9 +264,7 @@ func predictContactsVedic(sequence string, config ContactMapConfig) ([]ContactPr
 	}
 
 	// Sort and limit
-	sort.Slice(contacts, func(i, j int) bool {
-		return contacts[i].Score > contacts[j].Score
-	})
+	sortContactsByScore(contacts)
 
 	if len(contacts) > config.MaxContacts {
 		contacts = contacts[:config.MaxContacts]
@@ -278,11 +283,13 @@ func predictContactsConsensus(sequence string, config ContactMapConfig) ([]Conta
 	contactMap := make(map[string]*ContactPrediction)
 
 	for _, contact := range miContacts {
+		contact := contact // Own copy: the map keeps its address
 		key := fmt.Sprintf("%d-%d", contact.Residue1, contact.Residue2)
 		contactMap[key] = &contact
 	}
 
 	for _, contact := range vedicContacts {
+		contact := contact
 		key := fmt.Sprintf("%d-%d", contact.Residue1, contact.Residue2)
 		if existing, ok := contactMap[key]; ok {
 			// Average scores
@@ -294,16 +301,14 @@ func predictContactsConsensus(sequence string, config ContactMapConfig) ([]Conta
 		}
 	}
 
-	// Convert back to slice
+	// Convert back to slice (map order is random; the sort fixes it)
 	consensus := make([]ContactPrediction, 0, len(contactMap))
 	for _, contact := range contactMap {
 		consensus = append(consensus, *contact)
 	}
 
 	// Sort and limit
-	sort.Slice(consensus, func(i, j int) bool {
-		return consensus[i].Score > consensus[j].Score
-	})
+	sortContactsByScore(consensus)
 
 	if len(consensus) > config.MaxContacts {
 		consensus = consensus[:config.MaxContacts]
@@ -312,6 +317,21 @@ func predictContactsConsensus(sequence string, config ContactMapConfig) ([]Conta
 	return consensus, nil
 }
 
+// sortContactsByScore sorts contacts best first, breaking score ties by
+// residue pair so that truncating to MaxContacts keeps the same contacts on
+// every run
+func sortContactsByScore(contacts []ContactPrediction) {
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].Score != contacts[j].Score {
+			return contacts[i].Score > contacts[j].Score
+		}
+		if contacts[i].Residue1 != contacts[j].Residue1 {
+			return contacts[i].Residue1 < contacts[j].Residue1
+		}
+		return contacts[i].Residue2 < contacts[j].Residue2
+	})
+}
+
 // enhanceContactsWithVedic boosts scores for Fibonacci-separated contacts
 func enhanceContactsWithVedic(contacts []ContactPrediction, sequence string) []ContactPrediction {
 	fibonacci := generateFibonacci(len(sequence))
@@ -466,6 +486,29 @@ func calculateDistance(a1, a2 *parser.Atom) float64 {
 	return math.Sqrt(dx*dx + dy*dy + dz*dz)
 }
 
+// contactTargetDistance is the CA-CA distance a predicted contact is
+// restrained to (Å)
+const contactTargetDistance = 7.0
+
+// ContactRestraints converts predicted contacts into physics restraints with
+// the 7 Å CA-CA target, weighted by prediction confidence
+//
+// Pass the result as EnergyConfig.ContactRestraints or
+// QuaternionLBFGSConfig.ContactRestraints so the contacts pull residues
+// together during minimization.
+func ContactRestraints(contacts []ContactPrediction) []physics.ContactRestraint {
+	restraints := make([]physics.ContactRestraint, 0, len(contacts))
+	for _, contact := range contacts {
+		restraints = append(restraints, physics.ContactRestraint{
+			Residue1: contact.Residue1,
+			Residue2: contact.Residue2,
+			Target:   contactTargetDistance,
+			Weight:   contact.Score,
+		})
+	}
+	return restraints
+}
+
 // ApplyContactRestraints adds contact distance restraints to energy function
 //
 // USAGE IN FOLDING:
@@ -477,40 +520,12 @@ func calculateDistance(a1, a2 *parser.Atom) float64 {
 //   d_ij = current distance between residues i and j
 //   d_target = target contact distance (typically 6-8 Å)
 //   k = restraint force constant (kcal/(mol·Å²))
+//
+// This only scores a finished structure; to fold toward the contacts, put
+// ContactRestraints(contacts) into the minimizer's energy function.
 func ApplyContactRestraints(protein *parser.Protein, contacts []ContactPrediction, forceConstant float64) float64 {
-	const targetDistance = 7.0 // Å
-
-	totalEnergy := 0.0
-
-	// Build Cα lookup
-	caMap := make(map[int]*parser.Atom)
-	for i, res := range protein.Residues {
-		if res.CA != nil {
-			caMap[i] = res.CA
-		}
-	}
-
-	for _, contact := range contacts {
-		ca1, ok1 := caMap[contact.Residue1]
-		ca2, ok2 := caMap[contact.Residue2]
-
-		if !ok1 || !ok2 {
-			continue
-		}
-
-		currentDist := calculateDistance(ca1, ca2)
-
-		// Harmonic restraint energy
-		deviation := currentDist - targetDistance
-		energy := forceConstant * deviation * deviation
-
-		// Weight by prediction confidence
-		energy *= contact.Score
-
-		totalEnergy += energy
-	}
-
-	return totalEnergy
+	energy, _ := physics.ContactRestraintEnergy(protein, ContactRestraints(contacts), forceConstant)
+	return energy
 }
 
 // GetContactMapCoverage calculates what fraction of residues have contacts
@@ -0,0 +1,45 @@
+package prediction
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestConsensusContactsDeterministic checks consensus prediction, merged
+// through a map, returns the same ranked, distinct contacts on every run,
+// and that equal scores are ordered by residue pair
+func TestConsensusContactsDeterministic(t *testing.T) {
+	sequence := strings.Repeat("VKLIEAFLKG", 4) // Repeats: many tied scores
+	config := DefaultContactMapConfig()
+	config.Method = "Consensus"
+	config.MaxContacts = 20
+
+	first, err := PredictContactMap(sequence, config)
+	if err != nil {
+		t.Fatalf("PredictContactMap failed: %v", err)
+	}
+	for run := 1; run < 10; run++ {
+		again, err := PredictContactMap(sequence, config)
+		if err != nil {
+			t.Fatalf("PredictContactMap failed: %v", err)
+		}
+		if !reflect.DeepEqual(first, again) {
+			t.Fatalf("Run %d returned different contacts", run)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	for _, c := range first {
+		if seen[[2]int{c.Residue1, c.Residue2}] {
+			t.Fatalf("Contact %d-%d listed twice", c.Residue1, c.Residue2)
+		}
+		seen[[2]int{c.Residue1, c.Residue2}] = true
+	}
+	for k := 1; k < len(first); k++ {
+		a, b := first[k-1], first[k]
+		if a.Score == b.Score && (a.Residue1 > b.Residue1 || (a.Residue1 == b.Residue1 && a.Residue2 > b.Residue2)) {
+			t.Errorf("Tied contacts %d-%d and %d-%d out of residue order", a.Residue1, a.Residue2, b.Residue1, b.Residue2)
+		}
+	}
+}
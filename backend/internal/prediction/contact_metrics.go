@@ -0,0 +1,142 @@
+// Package prediction - Top-L contact precision by range
+//
+// ValidateContactMap scores the whole predicted set at once, so its numbers
+// depend on how many contacts were predicted and are dominated by easy
+// short-range pairs. The literature (CASP RR assessment) instead ranks the
+// predictions by score and reports precision of the top L, L/2 and L/5
+// separately for short-, medium- and long-range pairs, long-range top-L/5
+// being the headline number. ContactMetricsAtCutoffs reports the same, so
+// our predictors can be compared with published ones.
+//
+// BIOCHEMIST: Long-range contacts (|i-j| ≥ 24) pin the fold together;
+// short-range ones mostly restate secondary structure
+// MATHEMATICIAN: Precision at k = correct / k among the k best-scored
+// predictions of a range; recall = correct / native contacts of the range
+//
+// CITATION:
+// Monastyrskyy, B., et al. (2014). "Evaluation of residue-residue contact
+// prediction in CASP10." Proteins 82(S2): 138-153.
+package prediction
+
+import (
+	"fmt"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// MinRangedContactSeparation is the smallest sequence separation assessed
+// by ContactMetricsAtCutoffs; closer pairs belong to no range
+const MinRangedContactSeparation = 6
+
+// ContactPrecisionAtK is the quality of the top K predictions of one range
+type ContactPrecisionAtK struct {
+	K         int // Cutoff: L, L/2 or L/5 (at least 1)
+	Evaluated int // Predictions considered: min(K, predictions in range)
+	Correct   int // Of those, native contacts
+
+	Precision float64 // Correct / Evaluated (0 if none evaluated)
+	Recall    float64 // Correct / native contacts in range (0 if none)
+	F1        float64
+}
+
+// ContactRangeMetrics holds the top-L, L/2 and L/5 metrics of one range
+type ContactRangeMetrics struct {
+	Native int // Native contacts in this range
+
+	TopL  ContactPrecisionAtK
+	TopL2 ContactPrecisionAtK
+	TopL5 ContactPrecisionAtK
+}
+
+// ContactMetrics is the CASP-style assessment of a contact prediction
+type ContactMetrics struct {
+	L int // Sequence length
+
+	Short  ContactRangeMetrics // 6 ≤ |i-j| ≤ 11
+	Medium ContactRangeMetrics // 12 ≤ |i-j| ≤ 23
+	Long   ContactRangeMetrics // |i-j| ≥ 24
+}
+
+// ContactMetricsAtCutoffs ranks predicted by score and reports, for each of
+// the short, medium and long ranges, precision, recall and F1 of the top L,
+// L/2 and L/5 predictions of that range against the native contacts of
+// protein (Cα pairs closer than config.ContactThreshold)
+//
+// L is len(protein.Residues). Contacts may be given in either residue
+// order; duplicates keep their best score, ties rank by residue index, and
+// pairs closer than MinRangedContactSeparation are ignored.
+// config.MinSequenceSeparation and MaxContacts are not used: the ranges
+// and cutoffs are fixed by the assessment.
+func ContactMetricsAtCutoffs(predicted []ContactPrediction, protein *parser.Protein, config ContactMapConfig) (ContactMetrics, error) {
+	if protein == nil || len(protein.Residues) == 0 {
+		return ContactMetrics{}, fmt.Errorf("protein has no residues")
+	}
+	L := len(protein.Residues)
+
+	pairs, err := uniqueContactPairs(predicted, L)
+	if err != nil {
+		return ContactMetrics{}, err
+	}
+
+	native := make(map[[2]int]bool)
+	nativeCount := make(map[ContactRange]int)
+	for _, contact := range extractNativeContacts(protein, config.ContactThreshold, 1) {
+		p := contactPair{i: contact.Residue1, j: contact.Residue2}
+		if p.j-p.i < MinRangedContactSeparation {
+			continue
+		}
+		native[[2]int{p.i, p.j}] = true
+		nativeCount[contactRangeOf(p)]++
+	}
+
+	// Correctness of each range's predictions, best first
+	ranked := make(map[ContactRange][]bool)
+	for _, p := range pairs {
+		if p.j-p.i < MinRangedContactSeparation {
+			continue
+		}
+		class := contactRangeOf(p)
+		ranked[class] = append(ranked[class], native[[2]int{p.i, p.j}])
+	}
+
+	rangeMetrics := func(class ContactRange) ContactRangeMetrics {
+		return ContactRangeMetrics{
+			Native: nativeCount[class],
+			TopL:   precisionAtK(ranked[class], L, nativeCount[class]),
+			TopL2:  precisionAtK(ranked[class], L/2, nativeCount[class]),
+			TopL5:  precisionAtK(ranked[class], L/5, nativeCount[class]),
+		}
+	}
+	return ContactMetrics{
+		L:      L,
+		Short:  rangeMetrics(ShortRange),
+		Medium: rangeMetrics(MediumRange),
+		Long:   rangeMetrics(LongRange),
+	}, nil
+}
+
+// precisionAtK scores the first k of correct (the ranked predictions of a
+// range, true where native) against native contacts in the range
+func precisionAtK(correct []bool, k, native int) ContactPrecisionAtK {
+	result := ContactPrecisionAtK{K: max(k, 1)}
+	result.Evaluated = result.K
+	if len(correct) < result.Evaluated {
+		result.Evaluated = len(correct)
+	}
+	for _, ok := range correct[:result.Evaluated] {
+		if ok {
+			result.Correct++
+		}
+	}
+
+	if result.Evaluated > 0 {
+		result.Precision = float64(result.Correct) / float64(result.Evaluated)
+	}
+	if native > 0 {
+		result.Recall = float64(result.Correct) / float64(native)
+	}
+	if result.Precision+result.Recall > 0 {
+		result.F1 = 2 * result.Precision * result.Recall / (result.Precision + result.Recall)
+	}
+	return result
+}
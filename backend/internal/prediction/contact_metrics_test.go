@@ -0,0 +1,101 @@
+package prediction
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// contactTestProtein places L Cα atoms 20 Å apart along x, then moves the
+// second residue of each pair 5 Å from the first, so the native contacts
+// (8 Å) are exactly pairs
+func contactTestProtein(L int, pairs [][2]int) *parser.Protein {
+	protein := &parser.Protein{}
+	for i := 0; i < L; i++ {
+		protein.Residues = append(protein.Residues, &parser.Residue{
+			Name: "ALA", ChainID: "A", SeqNum: i + 1,
+			CA: &parser.Atom{Name: "CA", X: 20 * float64(i)},
+		})
+	}
+	for _, p := range pairs {
+		a, b := protein.Residues[p[0]].CA, protein.Residues[p[1]].CA
+		b.X, b.Y, b.Z = a.X+5, a.Y, a.Z
+	}
+	return protein
+}
+
+// TestContactMetricsAtCutoffs checks the top-L, L/2 and L/5 precision of a
+// hand-ranked prediction against a known native map
+func TestContactMetricsAtCutoffs(t *testing.T) {
+	const L = 60 // L/5 = 12
+
+	// Natives: 10 long-range (i, i+30), 2 medium, 2 short
+	var natives [][2]int
+	for i := 0; i < 10; i++ {
+		natives = append(natives, [2]int{i, i + 30})
+	}
+	natives = append(natives, [2]int{10, 25}, [2]int{11, 26}, [2]int{40, 48}, [2]int{41, 49})
+	protein := contactTestProtein(L, natives)
+
+	// 15 long-range predictions, every third one wrong: the top 12 hold 8
+	// correct, all 15 hold the 10 natives
+	var predicted []ContactPrediction
+	nextNative := 0
+	for rank := 0; rank < 15; rank++ {
+		c := ContactPrediction{Score: 0.9 - 0.01*float64(rank)}
+		if rank%3 == 2 {
+			c.Residue1, c.Residue2 = rank, rank+40 // Not native
+		} else {
+			c.Residue1, c.Residue2 = natives[nextNative][1], natives[nextNative][0] // Reversed order
+			nextNative++
+		}
+		predicted = append(predicted, c)
+	}
+	predicted = append(predicted,
+		ContactPrediction{Residue1: 0, Residue2: 30, Score: 0.2},   // Duplicate: best score kept
+		ContactPrediction{Residue1: 12, Residue2: 30, Score: 0.6},  // Medium, wrong
+		ContactPrediction{Residue1: 10, Residue2: 25, Score: 0.5},  // Medium, native
+		ContactPrediction{Residue1: 40, Residue2: 48, Score: 0.95}, // Short, native
+		ContactPrediction{Residue1: 50, Residue2: 53, Score: 0.99}, // Separation 3: ignored
+	)
+
+	metrics, err := ContactMetricsAtCutoffs(predicted, protein, DefaultContactMapConfig())
+	if err != nil {
+		t.Fatalf("ContactMetricsAtCutoffs failed: %v", err)
+	}
+	t.Logf("Long: L %.3f, L/2 %.3f, L/5 %.3f (%d/%d); medium L/5 %.3f; short L/5 %.3f",
+		metrics.Long.TopL.Precision, metrics.Long.TopL2.Precision, metrics.Long.TopL5.Precision,
+		metrics.Long.TopL5.Correct, metrics.Long.TopL5.Evaluated,
+		metrics.Medium.TopL5.Precision, metrics.Short.TopL5.Precision)
+
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-12 }
+	long5 := metrics.Long.TopL5
+	if long5.K != 12 || long5.Evaluated != 12 || long5.Correct != 8 {
+		t.Errorf("Long top-L/5: K %d, evaluated %d, correct %d; want 12, 12, 8", long5.K, long5.Evaluated, long5.Correct)
+	}
+	if !near(long5.Precision, 8.0/12) || !near(long5.Recall, 0.8) {
+		t.Errorf("Long top-L/5 precision %.4f recall %.4f, want %.4f and 0.8", long5.Precision, long5.Recall, 8.0/12)
+	}
+	if want := 2 * (8.0 / 12) * 0.8 / (8.0/12 + 0.8); !near(long5.F1, want) {
+		t.Errorf("Long top-L/5 F1 %.4f, want %.4f", long5.F1, want)
+	}
+	if l := metrics.Long.TopL; l.K != L || l.Evaluated != 15 || l.Correct != 10 || !near(l.Recall, 1) {
+		t.Errorf("Long top-L: K %d, evaluated %d, correct %d, recall %.3f; want %d, 15, 10, 1", l.K, l.Evaluated, l.Correct, l.Recall, L)
+	}
+	if metrics.Long.Native != 10 || metrics.Medium.Native != 2 || metrics.Short.Native != 2 {
+		t.Errorf("Native counts long %d, medium %d, short %d; want 10, 2, 2",
+			metrics.Long.Native, metrics.Medium.Native, metrics.Short.Native)
+	}
+	if m := metrics.Medium.TopL5; m.Evaluated != 2 || m.Correct != 1 || !near(m.Precision, 0.5) {
+		t.Errorf("Medium top-L/5: evaluated %d, correct %d, precision %.3f; want 2, 1, 0.5", m.Evaluated, m.Correct, m.Precision)
+	}
+	if s := metrics.Short.TopL5; s.Evaluated != 1 || !near(s.Precision, 1) {
+		t.Errorf("Short top-L/5: evaluated %d, precision %.3f; want 1, 1", s.Evaluated, s.Precision)
+	}
+
+	// Out-of-range residues are an error
+	if _, err := ContactMetricsAtCutoffs([]ContactPrediction{{Residue1: 0, Residue2: L}}, protein, DefaultContactMapConfig()); err == nil {
+		t.Error("Expected error for a contact outside the sequence")
+	}
+}
@@ -0,0 +1,283 @@
+// Package prediction - GOR secondary structure prediction
+//
+// GOR assigns each residue the state carrying the most information given
+// the residues in a ±8 window around it. Unlike Chou-Fasman, a residue's
+// contribution depends on where it sits relative to the predicted one (an
+// N-cap Ser before a helix is not the same signal as one after it), and the
+// pair terms capture how the central residue modulates its neighbours.
+//
+// BIOCHEMIST: Parameters are counted from the embedded set of chains with
+// known 3-state structure (gor_training.txt) when the package loads
+// PHYSICIST: Directional information: self terms I(S; R_{j+m}) for each
+// offset m in [-8, 8], pair terms I(S; R_{j+m} | R_j) for m ≠ 0
+// MATHEMATICIAN: I(S; R) = log P(S | R) / P(S), estimated with
+// pseudocounts that shrink sparse cells toward zero information; the
+// predicted state maximises log P(S) + Σ self + Σ pair
+//
+// CITATION:
+// Garnier, J., Osguthorpe, D. J., & Robson, B. (1978). "Analysis of the
+// accuracy and implications of simple methods for predicting the secondary
+// structure of globular proteins." J. Mol. Biol. 120(1): 97-120.
+//
+// Gibrat, J. F., Garnier, J., & Robson, B. (1987). "Further developments
+// of protein secondary structure prediction using information theory."
+// J. Mol. Biol. 198(3): 425-443.
+package prediction
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// GOR window and shrinkage
+const (
+	gorHalfWindow = 8
+	gorWindow     = 2*gorHalfWindow + 1
+
+	// Pseudocounts added to each (offset, residue) and (offset, residue
+	// pair) cell, distributed as the lower-order estimate; with few
+	// observations a cell carries almost no information
+	gorSelfPseudocount = 20.0
+	gorPairPseudocount = 20.0
+)
+
+// gorAminoAcids indexes the 20 standard residues; others carry no information
+const gorAminoAcids = "ACDEFGHIKLMNPQRSTVWY"
+
+// gorStates are the three GOR states, in parameter order
+var gorStates = [3]SecondaryStructureType{AlphaHelix, BetaSheet, Coil}
+
+//go:embed gor_training.txt
+var gorTrainingData string
+
+// gorDefaultParams are trained once from the embedded set
+var gorDefaultParams = mustTrainGOR(gorTrainingData)
+
+// gorChain is one training chain
+type gorChain struct {
+	name     string
+	sequence string
+	states   string // H, E or C per residue
+}
+
+// gorParams holds information values in nats
+type gorParams struct {
+	logPrior [3]float64
+	self     [gorWindow][20][3]float64     // I(S; R_{j+m}), index m + gorHalfWindow
+	pair     [gorWindow][20][20][3]float64 // I(S; R_{j+m} | R_j) beyond the self terms
+}
+
+// mustTrainGOR parses and trains on embedded data, panicking if it is invalid
+func mustTrainGOR(data string) *gorParams {
+	chains, err := parseGORTraining(data)
+	if err != nil {
+		panic(fmt.Sprintf("prediction: invalid embedded GOR training set: %v", err))
+	}
+	return trainGOR(chains)
+}
+
+// parseGORTraining reads "<name> <sequence> <states>" lines
+func parseGORTraining(data string) ([]gorChain, error) {
+	var chains []gorChain
+	for lineNum, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: want name, sequence and states, got %d fields", lineNum+1, len(fields))
+		}
+		chain := gorChain{name: fields[0], sequence: strings.ToUpper(fields[1]), states: fields[2]}
+		if len(chain.sequence) != len(chain.states) {
+			return nil, fmt.Errorf("line %d: %s has %d residues but %d states", lineNum+1, chain.name, len(chain.sequence), len(chain.states))
+		}
+		for _, s := range chain.states {
+			if gorStateIndex(byte(s)) < 0 {
+				return nil, fmt.Errorf("line %d: %s has unknown state %q", lineNum+1, chain.name, s)
+			}
+		}
+		chains = append(chains, chain)
+	}
+
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("no training chains")
+	}
+	return chains, nil
+}
+
+// trainGOR counts states around every training residue and converts the
+// counts to information values
+func trainGOR(chains []gorChain) *gorParams {
+	var nState [3]float64
+	var nSelf [gorWindow][20][3]float64
+	var nPair [gorWindow][20][20][3]float64
+
+	for _, chain := range chains {
+		n := len(chain.sequence)
+		for j := 0; j < n; j++ {
+			s := gorStateIndex(chain.states[j])
+			nState[s]++
+			rj := gorResidueIndex(chain.sequence[j])
+			for m := -gorHalfWindow; m <= gorHalfWindow; m++ {
+				if j+m < 0 || j+m >= n {
+					continue
+				}
+				rm := gorResidueIndex(chain.sequence[j+m])
+				if rm < 0 {
+					continue
+				}
+				nSelf[m+gorHalfWindow][rm][s]++
+				if m != 0 && rj >= 0 {
+					nPair[m+gorHalfWindow][rj][rm][s]++
+				}
+			}
+		}
+	}
+
+	total := nState[0] + nState[1] + nState[2]
+	var prior [3]float64
+	params := &gorParams{}
+	for s := range prior {
+		prior[s] = (nState[s] + 1) / (total + 3)
+		params.logPrior[s] = math.Log(prior[s])
+	}
+
+	// Self information: P(S | R at m), shrunk toward P(S)
+	for m := range nSelf {
+		for r := range nSelf[m] {
+			params.self[m][r] = gorInformation(nSelf[m][r], prior, gorSelfPseudocount)
+		}
+	}
+
+	// Pair information: P(S | R_j, R_{j+m}), shrunk toward the estimate
+	// from the two self terms alone, so it only adds what they miss
+	for m := range nPair {
+		if m == gorHalfWindow {
+			continue
+		}
+		for rj := range nPair[m] {
+			for rm := range nPair[m][rj] {
+				var expected [3]float64
+				sum := 0.0
+				for s := range expected {
+					expected[s] = prior[s] * math.Exp(params.self[gorHalfWindow][rj][s]+params.self[m][rm][s])
+					sum += expected[s]
+				}
+				for s := range expected {
+					expected[s] /= sum
+				}
+				params.pair[m][rj][rm] = gorInformation(nPair[m][rj][rm], expected, gorPairPseudocount)
+			}
+		}
+	}
+
+	return params
+}
+
+// gorInformation returns log P(S | counts) / P0(S), with P(S | counts)
+// estimated from counts plus pseudocount observations distributed as P0
+func gorInformation(counts, p0 [3]float64, pseudocount float64) [3]float64 {
+	n := counts[0] + counts[1] + counts[2]
+	var info [3]float64
+	for s := range info {
+		p := (counts[s] + pseudocount*p0[s]) / (n + pseudocount)
+		info[s] = math.Log(p / p0[s])
+	}
+	return info
+}
+
+// predict assigns each residue of sequence its most informative state
+//
+// HelixScore, SheetScore and CoilScore hold the state probabilities
+// (softmax of the information sums) and Confidence the chosen one's.
+// Helix and strand runs shorter than config's minimum lengths become coil.
+func (p *gorParams) predict(sequence string, config PredictionConfig) []SecondaryStructurePrediction {
+	n := len(sequence)
+	predictions := make([]SecondaryStructurePrediction, n)
+
+	for j := 0; j < n; j++ {
+		score := p.logPrior
+		rj := gorResidueIndex(sequence[j])
+		for m := -gorHalfWindow; m <= gorHalfWindow; m++ {
+			if j+m < 0 || j+m >= n {
+				continue
+			}
+			rm := gorResidueIndex(sequence[j+m])
+			if rm < 0 {
+				continue
+			}
+			for s := range score {
+				score[s] += p.self[m+gorHalfWindow][rm][s]
+				if m != 0 && rj >= 0 {
+					score[s] += p.pair[m+gorHalfWindow][rj][rm][s]
+				}
+			}
+		}
+
+		// Softmax over the three states
+		best := 0
+		for s := range score {
+			if score[s] > score[best] {
+				best = s
+			}
+		}
+		var prob [3]float64
+		sum := 0.0
+		for s := range score {
+			prob[s] = math.Exp(score[s] - score[best])
+			sum += prob[s]
+		}
+		for s := range prob {
+			prob[s] /= sum
+		}
+
+		predictions[j] = SecondaryStructurePrediction{
+			Position:      j,
+			Residue:       string(sequence[j]),
+			PredictedType: gorStates[best],
+			Confidence:    prob[best],
+			HelixScore:    prob[0],
+			SheetScore:    prob[1],
+			CoilScore:     prob[2],
+		}
+	}
+
+	dropShortRuns(predictions, AlphaHelix, config.MinHelixLength)
+	dropShortRuns(predictions, BetaSheet, config.MinSheetLength)
+	return predictions
+}
+
+// dropShortRuns reassigns runs of ssType shorter than minLength to coil
+func dropShortRuns(predictions []SecondaryStructurePrediction, ssType SecondaryStructureType, minLength int) {
+	for start := 0; start < len(predictions); {
+		if predictions[start].PredictedType != ssType {
+			start++
+			continue
+		}
+		end := start
+		for end < len(predictions) && predictions[end].PredictedType == ssType {
+			end++
+		}
+		if end-start < minLength {
+			for i := start; i < end; i++ {
+				predictions[i].PredictedType = Coil
+				predictions[i].Confidence = predictions[i].CoilScore
+			}
+		}
+		start = end
+	}
+}
+
+// gorResidueIndex returns aa's index in gorAminoAcids, or -1
+func gorResidueIndex(aa byte) int {
+	return strings.IndexByte(gorAminoAcids, aa)
+}
+
+// gorStateIndex returns the parameter index of state letter s, or -1
+func gorStateIndex(s byte) int {
+	return strings.IndexByte("HEC", s)
+}
@@ -0,0 +1,94 @@
+package prediction
+
+import (
+	"testing"
+)
+
+// threeState maps a prediction to H/E/C, folding turns into coil
+func threeState(predictions []SecondaryStructurePrediction) string {
+	states := []byte(GetSecondaryStructureString(predictions))
+	for i, s := range states {
+		if s == 'T' {
+			states[i] = 'C'
+		}
+	}
+	return string(states)
+}
+
+// q3 is the fraction of matching states
+func q3(predicted, actual string) float64 {
+	correct := 0
+	for i := range actual {
+		if predicted[i] == actual[i] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(actual))
+}
+
+// TestGORDiffersFromChouFasman checks GOR is not a re-weighting of the
+// Chou-Fasman propensities on a designed helix-loop-strand sequence
+func TestGORDiffersFromChouFasman(t *testing.T) {
+	sequence := "SPEELLKKAEELLKRGNPDGTKVTVTVEVNGKRYEVEV"
+	config := DefaultPredictionConfig()
+	config.UseVedicEnhancement = false
+
+	config.Method = MethodGOR
+	gor, err := PredictSecondaryStructure(sequence, config)
+	if err != nil {
+		t.Fatalf("GOR failed: %v", err)
+	}
+	config.Method = MethodChouFasman
+	cf, err := PredictSecondaryStructure(sequence, config)
+	if err != nil {
+		t.Fatalf("Chou-Fasman failed: %v", err)
+	}
+
+	gorStates, cfStates := threeState(gor), threeState(cf)
+	t.Logf("Sequence:    %s", sequence)
+	t.Logf("GOR:         %s", gorStates)
+	t.Logf("Chou-Fasman: %s", cfStates)
+
+	if gorStates == cfStates {
+		t.Errorf("GOR and Chou-Fasman agree at every residue")
+	}
+	for _, p := range gor {
+		if sum := p.HelixScore + p.SheetScore + p.CoilScore; sum < 0.999 || sum > 1.001 {
+			t.Fatalf("Residue %d state probabilities sum to %.4f", p.Position, sum)
+		}
+	}
+}
+
+// TestGORJackknifeQ3 predicts each training chain with parameters trained
+// on the others and compares Q3 with Chou-Fasman on the same chains
+func TestGORJackknifeQ3(t *testing.T) {
+	chains, err := parseGORTraining(gorTrainingData)
+	if err != nil {
+		t.Fatalf("Embedded training set: %v", err)
+	}
+
+	config := DefaultPredictionConfig()
+	config.UseVedicEnhancement = false
+
+	var gorCorrect, cfCorrect, total float64
+	for i, chain := range chains {
+		others := append(append([]gorChain{}, chains[:i]...), chains[i+1:]...)
+		gor := threeState(trainGOR(others).predict(chain.sequence, config))
+		cf, err := predictChouFasman(chain.sequence, config)
+		if err != nil {
+			t.Fatalf("Chou-Fasman failed on %s: %v", chain.name, err)
+		}
+
+		n := float64(len(chain.sequence))
+		gorCorrect += q3(gor, chain.states) * n
+		cfCorrect += q3(threeState(cf), chain.states) * n
+		total += n
+		t.Logf("%-7s GOR Q3 %.2f, Chou-Fasman Q3 %.2f", chain.name, q3(gor, chain.states), q3(threeState(cf), chain.states))
+	}
+
+	gorQ3, cfQ3 := gorCorrect/total, cfCorrect/total
+	t.Logf("Jackknife over %d residues: GOR Q3 %.3f, Chou-Fasman Q3 %.3f", int(total), gorQ3, cfQ3)
+	if gorQ3 < cfQ3-0.05 {
+		t.Errorf("GOR Q3 %.3f well below Chou-Fasman %.3f", gorQ3, cfQ3)
+	}
+}
@@ -0,0 +1,610 @@
+// Package prediction - MSA-based contact prediction
+//
+// The single-sequence "MI" and "DCA" methods in contact_map.go are
+// heuristics: one sequence carries no coevolution signal. Given a multiple
+// sequence alignment, residue pairs in contact show correlated substitutions
+// across homologs, which is what PredictContactMapFromMSA measures.
+//
+// BIOCHEMIST: A destabilising mutation at one contact position is tolerated
+// when its partner compensates, so contacting columns covary
+// PHYSICIST: Pseudolikelihood DCA fits a Potts model P(σ) ∝ exp(Σh + ΣJ);
+// the couplings J_ij separate direct contacts from transitive correlations
+// MATHEMATICIAN: Average product correction removes the per-column
+// background (entropy, phylogeny) shared by all pairs of a column
+// ETHICIST: Shallow alignments give noise that looks like signal; below the
+// minimum effective depth we refuse rather than return meaningless contacts
+//
+// CITATION:
+// Dunn, S. D., Wahl, L. M., Gloor, G. B. (2008). "Mutual information without the influence
+// of phylogeny or entropy dramatically improves residue contact prediction."
+// Bioinformatics 24(3): 333-340.
+//
+// Ekeberg, M., et al. (2013). "Improved contact prediction in proteins: Using pseudolikelihoods
+// to infer Potts models." Phys. Rev. E 87(1): 012707.
+package prediction
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// msaStates is the Potts alphabet: 20 amino acids plus gap (state 20)
+const (
+	msaAlphabet = "ACDEFGHIKLMNPQRSTVWY"
+	msaStates   = len(msaAlphabet) + 1
+	msaGap      = msaStates - 1
+)
+
+// Pseudolikelihood DCA parameters
+const (
+	plmIterations   = 200
+	plmLearningRate = 0.1
+	plmLambdaH      = 0.01
+	plmLambdaJ      = 0.01
+	plmAdamBeta1    = 0.9
+	plmAdamBeta2    = 0.999
+	plmAdamEpsilon  = 1e-8
+)
+
+// minScoredPositions is the fewest non-gappy columns worth scoring
+const minScoredPositions = 2
+
+// alignment is a parsed MSA restricted to the query's match columns
+type alignment struct {
+	names []string
+	seqs  [][]int // State indices, len = number of columns
+}
+
+// PredictContactMapFromMSA predicts contacts from coevolution in an alignment
+//
+// ALGORITHM:
+//  1. Read the A3M or Stockholm alignment; columns are the first (query)
+//     sequence's residues, so contact indices are 0-indexed query positions
+//  2. Weight each sequence by 1/(number of sequences ≥ IdentityThreshold
+//     identical to it); Meff = Σ weights
+//  3. Score column pairs: Method "DCA" uses pseudolikelihood Potts couplings
+//     (Frobenius norm), anything else uses mutual information
+//  4. Apply APC, skip columns gappier than MaxGapFraction, filter by
+//     MinSequenceSeparation, rank, and keep the top MaxContacts
+//
+// Scores are normalised to [0, 1] by the top pair. Vedic enhancement is not
+// applied: coevolution scores are evidence, not priors. Zero-valued MSA
+// fields in config fall back to DefaultContactMapConfig.
+//
+// Returns an error if Meff < MinEffectiveSequences; use PredictContactMap on
+// the query sequence instead.
+func PredictContactMapFromMSA(msaPath string, config ContactMapConfig) ([]ContactPrediction, error) {
+	aln, err := readAlignment(msaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := DefaultContactMapConfig()
+	if config.IdentityThreshold <= 0 {
+		config.IdentityThreshold = defaults.IdentityThreshold
+	}
+	if config.MaxGapFraction <= 0 {
+		config.MaxGapFraction = defaults.MaxGapFraction
+	}
+	if config.MinEffectiveSequences <= 0 {
+		config.MinEffectiveSequences = defaults.MinEffectiveSequences
+	}
+
+	weights, meff := sequenceWeights(aln.seqs, config.IdentityThreshold)
+	if meff < config.MinEffectiveSequences {
+		return nil, fmt.Errorf("alignment too shallow for coevolution analysis: Meff %.1f from %d sequences, need %.1f; use PredictContactMap for single-sequence prediction",
+			meff, len(aln.seqs), config.MinEffectiveSequences)
+	}
+
+	// Columns with too many gaps carry little signal and inflate MI
+	L := len(aln.seqs[0])
+	scored := make([]bool, L)
+	numScored := 0
+	for i := 0; i < L; i++ {
+		gapWeight := 0.0
+		for n, seq := range aln.seqs {
+			if seq[i] == msaGap {
+				gapWeight += weights[n]
+			}
+		}
+		if gapWeight/meff <= config.MaxGapFraction {
+			scored[i] = true
+			numScored++
+		}
+	}
+	if numScored < minScoredPositions {
+		return nil, fmt.Errorf("only %d of %d columns have gap fraction ≤ %.2f", numScored, L, config.MaxGapFraction)
+	}
+
+	var raw [][]float64
+	method := "MI"
+	if config.Method == "DCA" {
+		method = "DCA"
+		raw = plmDCAScores(aln.seqs, weights, meff)
+	} else {
+		raw = mutualInformation(aln.seqs, weights, meff)
+	}
+	scores := averageProductCorrection(raw, scored)
+
+	contacts := make([]ContactPrediction, 0)
+	maxScore := 0.0
+	for i := 0; i < L; i++ {
+		for j := i + config.MinSequenceSeparation; j < L; j++ {
+			if j <= i || !scored[i] || !scored[j] || scores[i][j] <= 0 {
+				continue
+			}
+			contacts = append(contacts, ContactPrediction{
+				Residue1: i,
+				Residue2: j,
+				Distance: j - i,
+				Score:    scores[i][j],
+				Method:   method,
+			})
+			maxScore = math.Max(maxScore, scores[i][j])
+		}
+	}
+
+	for i := range contacts {
+		contacts[i].Score /= maxScore
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].Score > contacts[j].Score
+	})
+
+	if config.MaxContacts > 0 && len(contacts) > config.MaxContacts {
+		contacts = contacts[:config.MaxContacts]
+	}
+
+	return contacts, nil
+}
+
+// readAlignment reads an A3M (or aligned FASTA) or Stockholm file
+//
+// Stockholm is detected by a ".sto"/".stk"/".stockholm" extension or a
+// "# STOCKHOLM" header. Columns where the query has a gap are dropped.
+func readAlignment(path string) (*alignment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open alignment: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alignment: %w", err)
+	}
+
+	stockholm := false
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sto", ".stk", ".stockholm":
+		stockholm = true
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		stockholm = stockholm || strings.HasPrefix(line, "# STOCKHOLM")
+		break
+	}
+
+	var names, rows []string
+	if stockholm {
+		names, rows = parseStockholm(lines)
+	} else {
+		names, rows = parseA3M(lines)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no sequences in alignment %s", path)
+	}
+	for k, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("sequence %q has %d aligned columns, query has %d", names[k], len(row), len(rows[0]))
+		}
+	}
+
+	// Keep the query's match columns only
+	query := rows[0]
+	aln := &alignment{names: names, seqs: make([][]int, len(rows))}
+	for k, row := range rows {
+		seq := make([]int, 0, len(query))
+		for c := 0; c < len(query); c++ {
+			if query[c] == '-' {
+				continue
+			}
+			seq = append(seq, msaStateIndex(row[c]))
+		}
+		aln.seqs[k] = seq
+	}
+	if len(aln.seqs[0]) == 0 {
+		return nil, fmt.Errorf("query sequence in %s is empty", path)
+	}
+
+	return aln, nil
+}
+
+// parseA3M returns aligned rows with insertions (lowercase, '.') removed
+func parseA3M(lines []string) ([]string, []string) {
+	var names []string
+	var rows []string
+	var current strings.Builder
+	inRecord := false
+
+	flush := func() {
+		if inRecord {
+			rows = append(rows, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, ">") {
+			flush()
+			names = append(names, strings.TrimSpace(line[1:]))
+			inRecord = true
+			continue
+		}
+		if !inRecord {
+			continue
+		}
+		for _, ch := range line {
+			switch {
+			case ch >= 'A' && ch <= 'Z', ch == '-':
+				current.WriteRune(ch)
+			}
+		}
+	}
+	flush()
+
+	return names, rows
+}
+
+// parseStockholm returns aligned rows, joining interleaved blocks by name
+func parseStockholm(lines []string) ([]string, []string) {
+	var names []string
+	seqs := make(map[string]*strings.Builder)
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "//" {
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		b, ok := seqs[fields[0]]
+		if !ok {
+			b = &strings.Builder{}
+			seqs[fields[0]] = b
+			names = append(names, fields[0])
+		}
+		for _, ch := range strings.ToUpper(fields[1]) {
+			if ch == '.' {
+				ch = '-'
+			}
+			b.WriteRune(ch)
+		}
+	}
+
+	rows := make([]string, len(names))
+	for k, name := range names {
+		rows[k] = seqs[name].String()
+	}
+	return names, rows
+}
+
+// msaStateIndex maps a residue letter to its Potts state; unknowns are gaps
+func msaStateIndex(ch byte) int {
+	if idx := strings.IndexByte(msaAlphabet, ch); idx >= 0 {
+		return idx
+	}
+	return msaGap
+}
+
+// sequenceWeights down-weights redundant sequences
+//
+// w_n = 1 / |{m : identity(n, m) ≥ threshold}| (including n itself)
+func sequenceWeights(seqs [][]int, threshold float64) ([]float64, float64) {
+	N := len(seqs)
+	L := len(seqs[0])
+	neighbours := make([]int, N)
+
+	for n := 0; n < N; n++ {
+		neighbours[n]++
+		for m := n + 1; m < N; m++ {
+			same := 0
+			for c := 0; c < L; c++ {
+				if seqs[n][c] == seqs[m][c] {
+					same++
+				}
+			}
+			if float64(same) >= threshold*float64(L) {
+				neighbours[n]++
+				neighbours[m]++
+			}
+		}
+	}
+
+	weights := make([]float64, N)
+	meff := 0.0
+	for n := range weights {
+		weights[n] = 1.0 / float64(neighbours[n])
+		meff += weights[n]
+	}
+	return weights, meff
+}
+
+// mutualInformation returns the weighted MI matrix (nats)
+//
+// MI_ij = Σ_ab f_ij(a,b) ln[f_ij(a,b) / (f_i(a) f_j(b))]
+func mutualInformation(seqs [][]int, weights []float64, meff float64) [][]float64 {
+	L := len(seqs[0])
+
+	single := make([][msaStates]float64, L)
+	for n, seq := range seqs {
+		for i, a := range seq {
+			single[i][a] += weights[n] / meff
+		}
+	}
+
+	mi := make([][]float64, L)
+	for i := range mi {
+		mi[i] = make([]float64, L)
+	}
+
+	var pair [msaStates][msaStates]float64
+	for i := 0; i < L; i++ {
+		for j := i + 1; j < L; j++ {
+			pair = [msaStates][msaStates]float64{}
+			for n, seq := range seqs {
+				pair[seq[i]][seq[j]] += weights[n] / meff
+			}
+
+			value := 0.0
+			for a := 0; a < msaStates; a++ {
+				for b := 0; b < msaStates; b++ {
+					if pair[a][b] > 0 {
+						value += pair[a][b] * math.Log(pair[a][b]/(single[i][a]*single[j][b]))
+					}
+				}
+			}
+			mi[i][j] = value
+			mi[j][i] = value
+		}
+	}
+
+	return mi
+}
+
+// averageProductCorrection returns S_ij - S̄_i S̄_j / S̄ over scored columns
+func averageProductCorrection(raw [][]float64, scored []bool) [][]float64 {
+	L := len(raw)
+	rowMean := make([]float64, L)
+	total, pairs := 0.0, 0
+
+	for i := 0; i < L; i++ {
+		if !scored[i] {
+			continue
+		}
+		count := 0
+		for j := 0; j < L; j++ {
+			if j == i || !scored[j] {
+				continue
+			}
+			rowMean[i] += raw[i][j]
+			count++
+			total += raw[i][j]
+			pairs++
+		}
+		if count > 0 {
+			rowMean[i] /= float64(count)
+		}
+	}
+
+	corrected := make([][]float64, L)
+	for i := range corrected {
+		corrected[i] = make([]float64, L)
+	}
+	if pairs == 0 || total == 0 {
+		return corrected
+	}
+	mean := total / float64(pairs)
+
+	for i := 0; i < L; i++ {
+		for j := 0; j < L; j++ {
+			if i != j && scored[i] && scored[j] {
+				corrected[i][j] = raw[i][j] - rowMean[i]*rowMean[j]/mean
+			}
+		}
+	}
+	return corrected
+}
+
+// plmDCAScores returns symmetrised coupling norms from asymmetric
+// pseudolikelihood maximisation
+//
+// PHYSICIST: Each site r is a softmax regression of σ_r on all other sites,
+// fitted independently (Adam, L2-regularised). ‖J_ij‖ is the Frobenius norm
+// of the zero-sum-gauged coupling block over amino acid states (gap excluded),
+// averaged over the (i→j) and (j→i) fits.
+func plmDCAScores(seqs [][]int, weights []float64, meff float64) [][]float64 {
+	L := len(seqs[0])
+	couplings := make([][][]float64, L) // couplings[r][j][a*q+b] = J_rj(a, b)
+
+	sites := make(chan int)
+	var wg sync.WaitGroup
+	workers := runtime.NumCPU()
+	if workers > L {
+		workers = L
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range sites {
+				// Each site is written by exactly one worker
+				couplings[r] = fitPseudolikelihoodSite(seqs, weights, meff, r)
+			}
+		}()
+	}
+	for r := 0; r < L; r++ {
+		sites <- r
+	}
+	close(sites)
+	wg.Wait()
+
+	scores := make([][]float64, L)
+	for i := range scores {
+		scores[i] = make([]float64, L)
+	}
+	for i := 0; i < L; i++ {
+		for j := i + 1; j < L; j++ {
+			norm := 0.5 * (couplingNorm(couplings[i][j], false) + couplingNorm(couplings[j][i], true))
+			scores[i][j] = norm
+			scores[j][i] = norm
+		}
+	}
+	return scores
+}
+
+// fitPseudolikelihoodSite fits h_r and J_r· for site r
+//
+// Minimises -(1/Meff) Σ_n w_n ln P(σ_r^n | σ_\r^n) + λh‖h‖² + λJ‖J‖², with
+// P(a | σ) = softmax_a(h_r(a) + Σ_{j≠r} J_rj(a, σ_j)).
+func fitPseudolikelihoodSite(seqs [][]int, weights []float64, meff float64, r int) [][]float64 {
+	L := len(seqs[0])
+	q := msaStates
+
+	// Parameter layout: h (q), then J_rj (q×q) for each j
+	numParams := q + L*q*q
+	params := make([]float64, numParams)
+	grad := make([]float64, numParams)
+	m := make([]float64, numParams)
+	v := make([]float64, numParams)
+	field := make([]float64, q)
+
+	jOffset := func(j, a, b int) int { return q + (j*q+a)*q + b }
+
+	for iter := 1; iter <= plmIterations; iter++ {
+		for k := range grad {
+			grad[k] = 0
+		}
+
+		for n, seq := range seqs {
+			// Local field on site r
+			copy(field, params[:q])
+			for j, b := range seq {
+				if j == r {
+					continue
+				}
+				for a := 0; a < q; a++ {
+					field[a] += params[jOffset(j, a, b)]
+				}
+			}
+
+			// Softmax (shifted for stability)
+			maxField := field[0]
+			for _, f := range field[1:] {
+				maxField = math.Max(maxField, f)
+			}
+			z := 0.0
+			for a := range field {
+				field[a] = math.Exp(field[a] - maxField)
+				z += field[a]
+			}
+
+			w := weights[n] / meff
+			for a := 0; a < q; a++ {
+				g := field[a] / z
+				if a == seq[r] {
+					g -= 1
+				}
+				g *= w
+				grad[a] += g
+				for j, b := range seq {
+					if j != r {
+						grad[jOffset(j, a, b)] += g
+					}
+				}
+			}
+		}
+
+		// L2 regularisation and Adam step
+		correction1 := 1 - math.Pow(plmAdamBeta1, float64(iter))
+		correction2 := 1 - math.Pow(plmAdamBeta2, float64(iter))
+		for k := range params {
+			if k < q {
+				grad[k] += 2 * plmLambdaH * params[k]
+			} else {
+				grad[k] += 2 * plmLambdaJ * params[k]
+			}
+			m[k] = plmAdamBeta1*m[k] + (1-plmAdamBeta1)*grad[k]
+			v[k] = plmAdamBeta2*v[k] + (1-plmAdamBeta2)*grad[k]*grad[k]
+			params[k] -= plmLearningRate * (m[k] / correction1) / (math.Sqrt(v[k]/correction2) + plmAdamEpsilon)
+		}
+	}
+
+	couplings := make([][]float64, L)
+	for j := 0; j < L; j++ {
+		if j == r {
+			continue
+		}
+		start := jOffset(j, 0, 0)
+		couplings[j] = append([]float64(nil), params[start:start+q*q]...)
+	}
+	return couplings
+}
+
+// couplingNorm returns the Frobenius norm of a zero-sum-gauged q×q block
+// over amino acid states; transpose reads block[b*q+a] as J(a, b)
+func couplingNorm(block []float64, transpose bool) float64 {
+	q := msaStates
+	at := func(a, b int) float64 {
+		if transpose {
+			return block[b*q+a]
+		}
+		return block[a*q+b]
+	}
+
+	rowMean := make([]float64, q)
+	colMean := make([]float64, q)
+	total := 0.0
+	for a := 0; a < q; a++ {
+		for b := 0; b < q; b++ {
+			rowMean[a] += at(a, b) / float64(q)
+			colMean[b] += at(a, b) / float64(q)
+			total += at(a, b)
+		}
+	}
+	total /= float64(q * q)
+
+	sum := 0.0
+	for a := 0; a < msaGap; a++ {
+		for b := 0; b < msaGap; b++ {
+			gauged := at(a, b) - rowMean[a] - colMean[b] + total
+			sum += gauged * gauged
+		}
+	}
+	return math.Sqrt(sum)
+}
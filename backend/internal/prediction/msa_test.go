@@ -0,0 +1,134 @@
+package prediction
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCoevolvingA3M writes an A3M with one planted covarying column pair
+//
+// Column partner is a fixed permutation of column planted in every sequence,
+// so the pair is perfectly coupled; all other columns are independent.
+func writeCoevolvingA3M(t *testing.T, numSeqs, length, planted, partner int) string {
+	t.Helper()
+	rng := rand.New(rand.NewSource(7))
+	permutation := rng.Perm(len(msaAlphabet))
+
+	var b strings.Builder
+	for n := 0; n < numSeqs; n++ {
+		seq := make([]byte, length)
+		for i := range seq {
+			seq[i] = msaAlphabet[rng.Intn(len(msaAlphabet))]
+		}
+		seq[partner] = msaAlphabet[permutation[strings.IndexByte(msaAlphabet, seq[planted])]]
+
+		row := string(seq)
+		if n > 0 && n%5 == 0 {
+			// Exercise A3M insertions (dropped) and gaps (kept)
+			row = row[:3] + "kl" + row[3:]
+			row = row[:1] + "-" + row[2:]
+		}
+		fmt.Fprintf(&b, ">seq%d\n%s\n", n, row)
+	}
+
+	path := filepath.Join(t.TempDir(), "planted.a3m")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("Failed to write MSA: %v", err)
+	}
+	return path
+}
+
+// TestPredictContactMapFromMSA checks the planted pair ranks at the top for
+// both MI-APC and pseudolikelihood DCA
+func TestPredictContactMapFromMSA(t *testing.T) {
+	const planted, partner = 4, 19
+	path := writeCoevolvingA3M(t, 300, 24, planted, partner)
+
+	for _, method := range []string{"MI", "DCA"} {
+		config := DefaultContactMapConfig()
+		config.Method = method
+		config.MaxContacts = 10
+
+		contacts, err := PredictContactMapFromMSA(path, config)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if len(contacts) == 0 {
+			t.Fatalf("%s: no contacts predicted", method)
+		}
+
+		rank := -1
+		for k, c := range contacts {
+			if c.Residue1 == planted && c.Residue2 == partner {
+				rank = k
+				break
+			}
+		}
+
+		t.Logf("%s: planted pair rank %d, top (%d, %d) score %.2f, runner-up score %.2f",
+			method, rank+1, contacts[0].Residue1, contacts[0].Residue2, contacts[0].Score, contacts[1].Score)
+
+		if rank < 0 || rank > 2 {
+			t.Errorf("%s: planted pair (%d, %d) rank %d, want top 3", method, planted, partner, rank+1)
+		}
+		for _, c := range contacts {
+			if c.Distance < config.MinSequenceSeparation || c.Score < 0 || c.Score > 1 {
+				t.Errorf("%s: invalid contact %+v", method, c)
+			}
+		}
+	}
+}
+
+// TestPredictContactMapFromMSAShallow checks a shallow alignment is rejected
+// with a pointer to the single-sequence fallback
+func TestPredictContactMapFromMSAShallow(t *testing.T) {
+	path := writeCoevolvingA3M(t, 8, 24, 4, 19)
+
+	_, err := PredictContactMapFromMSA(path, DefaultContactMapConfig())
+	if err == nil {
+		t.Fatal("Expected an error for an 8-sequence alignment")
+	}
+	if !strings.Contains(err.Error(), "PredictContactMap") {
+		t.Errorf("Error does not suggest the single-sequence fallback: %v", err)
+	}
+	t.Logf("Shallow MSA: %v", err)
+}
+
+// TestReadAlignmentStockholm checks interleaved blocks and query-gap columns
+func TestReadAlignmentStockholm(t *testing.T) {
+	data := `# STOCKHOLM 1.0
+#=GF ID test
+query   AC-DE
+homolog AcLD.
+
+query   FG
+homolog F-
+//
+`
+	path := filepath.Join(t.TempDir(), "test.sto")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write MSA: %v", err)
+	}
+
+	aln, err := readAlignment(path)
+	if err != nil {
+		t.Fatalf("readAlignment failed: %v", err)
+	}
+	if len(aln.seqs) != 2 || len(aln.seqs[0]) != 6 {
+		t.Fatalf("Got %d sequences of %d columns, want 2 of 6", len(aln.seqs), len(aln.seqs[0]))
+	}
+
+	want := []int{
+		msaStateIndex('A'), msaStateIndex('C'), msaStateIndex('D'),
+		msaGap, msaStateIndex('F'), msaGap,
+	}
+	for c, state := range aln.seqs[1] {
+		if state != want[c] {
+			t.Errorf("Homolog column %d: state %d, want %d", c, state, want[c])
+		}
+	}
+}
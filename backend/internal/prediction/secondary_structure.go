@@ -24,33 +24,23 @@ package prediction
 import (
 	"fmt"
 	"strings"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
 )
 
-// SecondaryStructureType represents predicted secondary structure
-type SecondaryStructureType int
+// SecondaryStructureType represents predicted secondary structure; it is
+// the geometry type, so predictions feed geometry.BuildFromSecondaryStructure
+// directly
+type SecondaryStructureType = geometry.SecondaryStructureType
 
 const (
-	Coil SecondaryStructureType = iota
-	AlphaHelix
-	BetaSheet
-	Turn
+	Coil       = geometry.Coil
+	AlphaHelix = geometry.AlphaHelix
+	BetaSheet  = geometry.BetaSheet
+	Turn       = geometry.Turn
 )
 
-func (s SecondaryStructureType) String() string {
-	switch s {
-	case AlphaHelix:
-		return "H" // Helix
-	case BetaSheet:
-		return "E" // Extended (sheet)
-	case Turn:
-		return "T" // Turn
-	case Coil:
-		return "C" // Coil/loop
-	default:
-		return "C"
-	}
-}
-
 // SecondaryStructurePrediction holds prediction results for one residue
 type SecondaryStructurePrediction struct {
 	Position       int
@@ -87,6 +77,15 @@ type PredictionConfig struct {
 
 	// Use Vedic enhancement (golden ratio patterns)
 	UseVedicEnhancement bool
+
+	// Post-process the prediction with SmoothSecondaryStructure
+	UseSmoothing bool
+
+	// Longest coil/turn gap filled between two helices or two strands
+	MaxCoilGap int
+
+	// Longest run of the other SS type merged between two helices or strands
+	MaxMergeGap int
 }
 
 // DefaultPredictionConfig returns recommended parameters
@@ -97,6 +96,9 @@ func DefaultPredictionConfig() PredictionConfig {
 		MinHelixLength:      4, // Minimum 4 residues for helix
 		MinSheetLength:      3, // Minimum 3 residues for sheet
 		UseVedicEnhancement: true,
+		UseSmoothing:        true,
+		MaxCoilGap:          1, // Single-residue breaks inside helices/strands
+		MaxMergeGap:         1,
 	}
 }
 
@@ -152,18 +154,28 @@ func PredictSecondaryStructure(sequence string, config PredictionConfig) ([]Seco
 	// Convert to uppercase
 	sequence = strings.ToUpper(sequence)
 
+	var predictions []SecondaryStructurePrediction
+	var err error
 	switch config.Method {
 	case MethodChouFasman:
-		return predictChouFasman(sequence, config)
+		predictions, err = predictChouFasman(sequence, config)
 	case MethodGOR:
-		return predictGOR(sequence, config)
+		predictions, err = predictGOR(sequence, config)
 	case MethodVedic:
-		return predictVedicEnhanced(sequence, config)
+		predictions, err = predictVedicEnhanced(sequence, config)
 	case MethodConsensus:
-		return predictConsensus(sequence, config)
+		predictions, err = predictConsensus(sequence, config)
 	default:
-		return predictChouFasman(sequence, config)
+		predictions, err = predictChouFasman(sequence, config)
+	}
+	if err != nil {
+		return nil, err
 	}
+
+	if config.UseSmoothing {
+		predictions = SmoothSecondaryStructure(predictions, config)
+	}
+	return predictions, nil
 }
 
 // predictChouFasman implements Chou-Fasman algorithm
@@ -378,55 +390,11 @@ func calculateConfidence(primaryScore, competingScore float64) float64 {
 // MATHEMATICIAN:
 // Information theory approach using conditional probabilities
 // P(S|R) = probability of state S given residue R in window
+//
+// Uses directional self and pair information over a ±8 window (see
+// gor.go); config.WindowSize does not apply.
 func predictGOR(sequence string, config PredictionConfig) ([]SecondaryStructurePrediction, error) {
-	// Simplified GOR implementation
-	// Full GOR requires information parameters from database
-	// For v0.2, use simplified propensity-based approach
-
-	n := len(sequence)
-	predictions := make([]SecondaryStructurePrediction, n)
-
-	for i := 0; i < n; i++ {
-		aa := string(sequence[i])
-
-		// Average propensities in window
-		helixScore := 0.0
-		sheetScore := 0.0
-		count := 0.0
-
-		halfWindow := config.WindowSize / 2
-		for j := maxInt(0, i-halfWindow); j <= minInt(n-1, i+halfWindow); j++ {
-			windowAA := string(sequence[j])
-			helixScore += getPropensity(windowAA, chouFasmanHelixPropensity)
-			sheetScore += getPropensity(windowAA, chouFasmanSheetPropensity)
-			count++
-		}
-
-		helixScore /= count
-		sheetScore /= count
-
-		predictions[i] = SecondaryStructurePrediction{
-			Position:   i,
-			Residue:    aa,
-			HelixScore: helixScore,
-			SheetScore: sheetScore,
-			CoilScore:  1.0,
-		}
-
-		// Assign type based on scores
-		if helixScore > 1.05 && helixScore > sheetScore {
-			predictions[i].PredictedType = AlphaHelix
-			predictions[i].Confidence = (helixScore - 1.0) / 0.5
-		} else if sheetScore > 1.05 && sheetScore > helixScore {
-			predictions[i].PredictedType = BetaSheet
-			predictions[i].Confidence = (sheetScore - 1.0) / 0.5
-		} else {
-			predictions[i].PredictedType = Coil
-			predictions[i].Confidence = 0.5
-		}
-	}
-
-	return predictions, nil
+	return gorDefaultParams.predict(sequence, config), nil
 }
 
 // predictVedicEnhanced uses Vedic patterns for enhanced prediction
@@ -554,6 +522,44 @@ func CalculateQ3Accuracy(predicted, actual []SecondaryStructurePrediction) float
 	return float64(correct) / float64(len(predicted))
 }
 
+// EvaluateAgainstPDBSS returns the Q3 accuracy of pred against the HELIX
+// and SHEET records of protein (see parser.Protein.AnnotatedSecondaryStructure)
+//
+// BIOCHEMIST: Residues in a HELIX record are helix, in a SHEET record
+// strand, and all others coil; a predicted Turn counts as coil, as in the
+// three-state reduction. pred must have one entry per residue of protein.
+// A protein without HELIX or SHEET records has no annotation to score
+// against and is an error.
+func EvaluateAgainstPDBSS(pred []SecondaryStructurePrediction, protein *parser.Protein) (float64, error) {
+	annotated := protein.AnnotatedSecondaryStructure()
+	if annotated == nil {
+		return 0, fmt.Errorf("protein has no HELIX or SHEET records")
+	}
+	if len(pred) != len(annotated) {
+		return 0, fmt.Errorf("%d predictions for %d residues", len(pred), len(annotated))
+	}
+
+	actual := make([]SecondaryStructurePrediction, len(annotated))
+	for i, kind := range annotated {
+		actual[i] = SecondaryStructurePrediction{Position: i, Residue: protein.Residues[i].Name, PredictedType: Coil}
+		switch kind {
+		case parser.SSRecordHelix:
+			actual[i].PredictedType = AlphaHelix
+		case parser.SSRecordSheet:
+			actual[i].PredictedType = BetaSheet
+		}
+	}
+
+	threeState := make([]SecondaryStructurePrediction, len(pred))
+	copy(threeState, pred)
+	for i := range threeState {
+		if threeState[i].PredictedType == Turn {
+			threeState[i].PredictedType = Coil
+		}
+	}
+	return CalculateQ3Accuracy(threeState, actual), nil
+}
+
 // Helper functions
 func minInt(a, b int) int {
 	if a < b {
@@ -0,0 +1,40 @@
+package prediction
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestEvaluateAgainstPDBSS scores a prediction against a HELIX record over
+// residues 2-5 of a six-residue chain
+func TestEvaluateAgainstPDBSS(t *testing.T) {
+	protein := &parser.Protein{}
+	for i, name := range []string{"GLY", "GLU", "ALA", "LEU", "LYS", "GLY"} {
+		protein.Residues = append(protein.Residues, &parser.Residue{Name: name, SeqNum: i + 1, ChainID: "A"})
+	}
+
+	// HHHH in 2-5; residue 1 is a turn (coil), residue 6 a wrong strand
+	pred := make([]SecondaryStructurePrediction, 6)
+	for i, ss := range []SecondaryStructureType{Turn, AlphaHelix, AlphaHelix, AlphaHelix, AlphaHelix, BetaSheet} {
+		pred[i] = SecondaryStructurePrediction{Position: i, PredictedType: ss}
+	}
+
+	if _, err := EvaluateAgainstPDBSS(pred, protein); err == nil {
+		t.Error("Scored a protein without HELIX or SHEET records")
+	}
+
+	protein.SSRecords = []parser.SSRecord{{Kind: parser.SSRecordHelix, ChainID: "A", StartSeq: 2, EndSeq: 5}}
+	q3, err := EvaluateAgainstPDBSS(pred, protein)
+	if err != nil {
+		t.Fatalf("EvaluateAgainstPDBSS failed: %v", err)
+	}
+	if math.Abs(q3-5.0/6.0) > 1e-12 {
+		t.Errorf("Q3 = %.3f, want 5/6", q3)
+	}
+
+	if _, err := EvaluateAgainstPDBSS(pred[:5], protein); err == nil {
+		t.Error("Accepted 5 predictions for 6 residues")
+	}
+}
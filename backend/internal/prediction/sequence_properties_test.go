@@ -0,0 +1,57 @@
+package prediction
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSequencePropertiesUbiquitin checks human ubiquitin against ExPASy
+// ProtParam: MW 8564.84 Da, theoretical pI 6.56
+func TestSequencePropertiesUbiquitin(t *testing.T) {
+	const ubiquitin = "MQIFVKTLTGKTITLEVEPSDTIENVKAKIQDKEGIPPDQQRLIFAGKQLEDGRTLSDYNIQKESTLHLVLRLRGG"
+
+	report, err := SequenceProperties(ubiquitin)
+	if err != nil {
+		t.Fatalf("SequenceProperties failed: %v", err)
+	}
+	t.Logf("Ubiquitin: %s", report)
+
+	if report.Length != 76 {
+		t.Errorf("Length %d, want 76", report.Length)
+	}
+	if math.Abs(report.MolecularWeight-8564.84) > 0.05 {
+		t.Errorf("MW %.2f Da, want 8564.84", report.MolecularWeight)
+	}
+	if math.Abs(report.IsoelectricPoint-6.56) > 0.01 {
+		t.Errorf("pI %.3f, want 6.56", report.IsoelectricPoint)
+	}
+	if q := NetCharge(ubiquitin, report.IsoelectricPoint); math.Abs(q) > 1e-3 {
+		t.Errorf("Net charge %.4f at the pI, want 0", q)
+	}
+	// 11 D/E against 11 K/R, His neutral and the C-terminal Gly acidic
+	if report.NetChargePH7 >= 0 || report.NetChargePH7 < -1 {
+		t.Errorf("Net charge at pH 7 %.3f, want slightly negative", report.NetChargePH7)
+	}
+
+	// 7 lysines in 76 residues; percentages sum to 100
+	if k := report.Composition['K']; math.Abs(k-700.0/76) > 1e-9 {
+		t.Errorf("Lys %.3f%%, want %.3f%%", k, 700.0/76)
+	}
+	if _, ok := report.Composition['W']; ok {
+		t.Error("Composition lists absent Trp")
+	}
+	total := 0.0
+	for _, percent := range report.Composition {
+		total += percent
+	}
+	if math.Abs(total-100) > 1e-9 {
+		t.Errorf("Composition sums to %.6f%%", total)
+	}
+
+	if _, err := SequenceProperties("ACDZ"); err == nil {
+		t.Error("Expected error for invalid residue")
+	}
+	if _, err := SequenceProperties(""); err == nil {
+		t.Error("Expected error for empty sequence")
+	}
+}
@@ -0,0 +1,93 @@
+// Package prediction - Secondary-structure segment smoothing
+//
+// Per-residue predictors leave implausible fragments: a one-residue helix
+// between coils, a helix broken by a single coil residue, a strand
+// interrupted by one residue called helix. MinHelixLength and
+// MinSheetLength only govern Chou-Fasman's nucleation regions, so such
+// fragments reach the builder from every method. SmoothSecondaryStructure
+// cleans a prediction up into contiguous segments.
+//
+// BIOCHEMIST: A helix needs ~4 residues (one turn, its first i→i+4
+// H-bond) and a strand ~3 to pair; a helix rarely breaks for a single
+// residue without a Pro or Gly kink
+//
+// ALGORITHM:
+//  1. Fill coil/turn gaps of at most MaxCoilGap residues between two
+//     segments of the same type (helix or strand) with that type
+//  2. Merge two segments of the same type separated by at most MaxMergeGap
+//     residues of the other type
+//  3. Turn helices shorter than MinHelixLength and strands shorter than
+//     MinSheetLength into coil
+package prediction
+
+// SmoothSecondaryStructure returns a copy of predictions post-processed as
+// described above with config's MaxCoilGap, MaxMergeGap, MinHelixLength
+// and MinSheetLength (a zero or negative value disables that step)
+//
+// Residues that change type get the mean confidence of the two flanking
+// residues when filled or merged, and 0.5 (as Chou-Fasman coil) when
+// turned into coil. Turns are loops here and are kept unless filled.
+func SmoothSecondaryStructure(predictions []SecondaryStructurePrediction, config PredictionConfig) []SecondaryStructurePrediction {
+	smoothed := make([]SecondaryStructurePrediction, len(predictions))
+	copy(smoothed, predictions)
+
+	isLoop := func(ss SecondaryStructureType) bool { return ss == Coil || ss == Turn }
+
+	// Steps 1 and 2: bridge short gaps between segments of the same type
+	bridge := func(maxGap int, gapOK func(SecondaryStructureType) bool) {
+		if maxGap <= 0 {
+			return
+		}
+		for _, gap := range ssSegments(smoothed) {
+			if gap.end-gap.start > maxGap || gap.start == 0 || gap.end == len(smoothed) {
+				continue
+			}
+			before, after := smoothed[gap.start-1], smoothed[gap.end]
+			flank := before.PredictedType
+			if isLoop(flank) || after.PredictedType != flank || !gapOK(smoothed[gap.start].PredictedType) || smoothed[gap.start].PredictedType == flank {
+				continue
+			}
+			for i := gap.start; i < gap.end; i++ {
+				smoothed[i].PredictedType = flank
+				smoothed[i].Confidence = (before.Confidence + after.Confidence) / 2
+			}
+		}
+	}
+	bridge(config.MaxCoilGap, isLoop)
+	bridge(config.MaxMergeGap, func(ss SecondaryStructureType) bool { return !isLoop(ss) })
+
+	// Step 3: drop segments too short to form
+	for _, seg := range ssSegments(smoothed) {
+		minLength := 0
+		switch smoothed[seg.start].PredictedType {
+		case AlphaHelix:
+			minLength = config.MinHelixLength
+		case BetaSheet:
+			minLength = config.MinSheetLength
+		}
+		if seg.end-seg.start >= minLength {
+			continue
+		}
+		for i := seg.start; i < seg.end; i++ {
+			smoothed[i].PredictedType = Coil
+			smoothed[i].Confidence = 0.5
+		}
+	}
+
+	return smoothed
+}
+
+// ssSegments splits predictions into maximal runs [start, end) of one
+// predicted type
+func ssSegments(predictions []SecondaryStructurePrediction) []region {
+	var segments []region
+	for start := 0; start < len(predictions); {
+		end := start + 1
+		for end < len(predictions) && predictions[end].PredictedType == predictions[start].PredictedType {
+			end++
+		}
+		segments = append(segments, region{start: start, end: end})
+		start = end
+	}
+	return segments
+}
@@ -0,0 +1,95 @@
+package prediction
+
+import (
+	"testing"
+)
+
+// ssFromString builds predictions of the given H/E/T/C states, all with
+// confidence 0.8
+func ssFromString(states string) []SecondaryStructurePrediction {
+	types := map[byte]SecondaryStructureType{'H': AlphaHelix, 'E': BetaSheet, 'T': Turn, 'C': Coil}
+	predictions := make([]SecondaryStructurePrediction, len(states))
+	for i := range states {
+		predictions[i] = SecondaryStructurePrediction{
+			Position:      i,
+			PredictedType: types[states[i]],
+			Confidence:    0.8,
+		}
+	}
+	return predictions
+}
+
+// checkSegmentLengths fails if a helix or strand in predictions is shorter
+// than config allows
+func checkSegmentLengths(t *testing.T, predictions []SecondaryStructurePrediction, config PredictionConfig) {
+	t.Helper()
+	for _, seg := range ssSegments(predictions) {
+		length := seg.end - seg.start
+		switch predictions[seg.start].PredictedType {
+		case AlphaHelix:
+			if length < config.MinHelixLength {
+				t.Errorf("Helix %d-%d has %d residues, minimum %d", seg.start, seg.end-1, length, config.MinHelixLength)
+			}
+		case BetaSheet:
+			if length < config.MinSheetLength {
+				t.Errorf("Strand %d-%d has %d residues, minimum %d", seg.start, seg.end-1, length, config.MinSheetLength)
+			}
+		}
+	}
+}
+
+// TestSmoothSecondaryStructure smooths a jagged prediction: a coil break
+// and a one-residue strand inside a helix, a coil break inside a strand,
+// and a lone helix residue and a two-residue strand between coils
+func TestSmoothSecondaryStructure(t *testing.T) {
+	const (
+		jagged = "HHCHHHEHHHHHCCEECEEECCHCCEECC"
+		want   = "HHHHHHHHHHHHCCEEEEEECCCCCCCCC"
+	)
+	config := DefaultPredictionConfig()
+	input := ssFromString(jagged)
+
+	smoothed := SmoothSecondaryStructure(input, config)
+	got := GetSecondaryStructureString(smoothed)
+	t.Logf("Jagged:   %s", jagged)
+	t.Logf("Smoothed: %s", got)
+
+	if got != want {
+		t.Errorf("Smoothed to %s, want %s", got, want)
+	}
+	checkSegmentLengths(t, smoothed, config)
+	if GetSecondaryStructureString(input) != jagged {
+		t.Errorf("SmoothSecondaryStructure modified its input")
+	}
+	if smoothed[2].Confidence != 0.8 || smoothed[22].Confidence != 0.5 {
+		t.Errorf("Confidences %.2f (filled) and %.2f (removed), want 0.80 and 0.50",
+			smoothed[2].Confidence, smoothed[22].Confidence)
+	}
+
+	// Zero thresholds leave the prediction as it is
+	if got := GetSecondaryStructureString(SmoothSecondaryStructure(input, PredictionConfig{})); got != jagged {
+		t.Errorf("Smoothing with zero thresholds gave %s, want %s", got, jagged)
+	}
+
+	// Wider gaps are only bridged when configured
+	config.MaxCoilGap = 2
+	if got := GetSecondaryStructureString(SmoothSecondaryStructure(ssFromString("HHHHCCHHHH"), config)); got != "HHHHHHHHHH" {
+		t.Errorf("Two-residue gap with MaxCoilGap 2 gave %s, want HHHHHHHHHH", got)
+	}
+}
+
+// TestPredictSecondaryStructureSmoothed checks every method's default
+// output respects the minimum segment lengths
+func TestPredictSecondaryStructureSmoothed(t *testing.T) {
+	sequence := "SPEELLKKAEELLKRGNPDGTKVTVTVEVNGKRYEVEV"
+	config := DefaultPredictionConfig()
+	for _, method := range []PredictionMethod{MethodChouFasman, MethodGOR, MethodVedic, MethodConsensus} {
+		config.Method = method
+		predictions, err := PredictSecondaryStructure(sequence, config)
+		if err != nil {
+			t.Fatalf("%s failed: %v", method, err)
+		}
+		t.Logf("%-15s %s", method, GetSecondaryStructureString(predictions))
+		checkSegmentLengths(t, predictions, config)
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
 )
 
 // Constants
@@ -266,21 +267,13 @@ func calculateDigitalRootEnergy(angles []geometry.RamachandranAngles) float64 {
 			continue
 		}
 
-		// Convert angles to degrees and get digital roots
-		phiDeg := int(math.Abs(angle.Phi * 180.0 / math.Pi))
-		psiDeg := int(math.Abs(angle.Psi * 180.0 / math.Pi))
-
-		phiRoot := digitalRoot(phiDeg)
-		psiRoot := digitalRoot(psiDeg)
+		// Digital roots of the angles in whole degrees
+		phiRoot := vedic.DigitalRootFloat(angle.Phi)
+		psiRoot := vedic.DigitalRootFloat(angle.Psi)
 
 		// Vedic consistency: Certain digital root pairs are "harmonious"
-		// Based on Vedic square (multiplication table mod 9)
 		// Harmonious pairs: (1,1), (2,2), (3,6), (4,4), (5,5), (6,3), (7,7), (8,8), (9,9)
-		isHarmonious := (phiRoot == psiRoot) ||
-			(phiRoot == 3 && psiRoot == 6) ||
-			(phiRoot == 6 && psiRoot == 3)
-
-		if !isHarmonious {
+		if !vedic.HarmoniousRoots(phiRoot, psiRoot) {
 			energy += 1.0
 		}
 
@@ -294,16 +287,6 @@ func calculateDigitalRootEnergy(angles []geometry.RamachandranAngles) float64 {
 	return energy / float64(validCount)
 }
 
-// digitalRoot computes Vedic digital root
-func digitalRoot(n int) int {
-	if n == 0 {
-		return 0
-	}
-
-	// Vedic formula: digital root = 1 + ((n-1) mod 9)
-	return 1 + ((n - 1) % 9)
-}
-
 // BiasAnglesTowardVedicHarmonics adjusts Ramachandran angles toward φ-ratio harmonics
 //
 // USE CASE:
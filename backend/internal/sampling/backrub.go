@@ -0,0 +1,115 @@
+// Package sampling - Backrub local backbone moves
+//
+// A dihedral move at residue i swings the whole chain after i; in a compact
+// structure almost any such move clashes. Backrub is the local alternative
+// seen in high-resolution crystal structures: the segment between Cα(i-1)
+// and Cα(i+1) - the two flanking peptide planes and residue i with its side
+// chain - rotates as a rigid body about the Cα(i-1)-Cα(i+1) axis. Nothing
+// outside the segment moves.
+//
+// BIOCHEMIST: Matches the alternate conformations of real proteins: the
+// central Cα shifts by up to ~1 Å while its neighbours stay put
+// PHYSICIST: A rigid rotation keeps every bond length; only the N-Cα-C
+// angles at the two pivots change
+// MATHEMATICIAN: Rotation about an axis through Cα(i-1): p' = a + R(p - a)
+//
+// CITATION:
+// Davis, I. W., et al. (2006). "The backrub motion: how protein backbone
+// shrugs when a sidechain dances." Structure 14(2): 265-274.
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// maxBackrubAngle bounds the backrub step of a Monte Carlo move (radians);
+// beyond ~40° the pivot N-Cα-C angles distort badly
+const maxBackrubAngle = 40.0 * math.Pi / 180.0
+
+// BackrubMove rotates the segment between the Cα atoms flanking residue
+// center by angle (radians) about the axis through them, in place
+//
+// The rotated atoms are C and O of center-1, every atom of center, and N of
+// center+1 with its hydrogens. Bond lengths are unchanged. center must have
+// a neighbour on each side with N, CA and C; a center+1 whose N carries a
+// ring (proline) is rejected, since rotating N would stretch N-CD.
+func BackrubMove(protein *parser.Protein, center int, angle float64) error {
+	if protein == nil {
+		return fmt.Errorf("protein is nil")
+	}
+	if center < 1 || center+1 >= len(protein.Residues) {
+		return fmt.Errorf("backrub center %d needs a residue on each side (have %d residues)", center, len(protein.Residues))
+	}
+	prev, mid, next := protein.Residues[center-1], protein.Residues[center], protein.Residues[center+1]
+	for _, res := range []*parser.Residue{prev, mid, next} {
+		if res == nil || res.N == nil || res.CA == nil || res.C == nil {
+			return fmt.Errorf("backrub around residue %d: missing backbone atoms", center)
+		}
+	}
+
+	pivot := backrubVector(prev.CA)
+	axis := backrubVector(next.CA).Sub(pivot)
+	if axis.Magnitude() < 1e-6 {
+		return fmt.Errorf("backrub around residue %d: flanking CA atoms coincide", center)
+	}
+
+	moving := []*parser.Atom{prev.C, next.N}
+	if prev.O != nil {
+		moving = append(moving, prev.O)
+	}
+	nextN := backrubVector(next.N)
+	for _, atom := range protein.Atoms {
+		switch {
+		case residueHasAtom(mid, atom):
+			moving = append(moving, atom)
+		case residueHasAtom(next, atom) && atom != next.N && atom != next.CA:
+			d := backrubVector(atom).Sub(nextN).Magnitude()
+			if atom.Element == "H" && d < 1.3 {
+				moving = append(moving, atom) // Amide H
+			} else if atom.Element != "H" && d < 1.7 {
+				return fmt.Errorf("backrub around residue %d: N of residue %d is in a ring", center, center+1)
+			}
+		}
+	}
+
+	q := geometry.QuaternionFromAxisAngle(axis, angle)
+	for _, atom := range moving {
+		p := backrubVector(atom).Sub(pivot).RotateByQuaternion(q).Add(pivot)
+		atom.X, atom.Y, atom.Z = p.X, p.Y, p.Z
+	}
+	return nil
+}
+
+// proposeBackrub returns a copy of current with a backrub about a random
+// interior residue by N(0, stepSize), clamped to ±maxBackrubAngle; ok is
+// false if no backrub applies
+func proposeBackrub(current *parser.Protein, stepSize float64, rng *rand.Rand) (*parser.Protein, bool) {
+	n := len(current.Residues)
+	if n < 3 {
+		return nil, false
+	}
+	center := 1 + rng.Intn(n-2)
+	angle := math.Max(-maxBackrubAngle, math.Min(maxBackrubAngle, rng.NormFloat64()*stepSize))
+
+	proposed := cloneProteinDeep(current)
+	if err := BackrubMove(proposed, center, angle); err != nil {
+		return nil, false
+	}
+	return proposed, true
+}
+
+// residueHasAtom reports whether atom belongs to res (same chain, number and
+// insertion code)
+func residueHasAtom(res *parser.Residue, atom *parser.Atom) bool {
+	return atom.ResSeq == res.SeqNum && atom.ChainID == res.ChainID && atom.ICode == res.ICode
+}
+
+// backrubVector converts an atom position to a geometry vector
+func backrubVector(atom *parser.Atom) geometry.Vector3 {
+	return geometry.Vector3{X: atom.X, Y: atom.Y, Z: atom.Z}
+}
@@ -0,0 +1,104 @@
+package sampling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// covalentPairs returns the atom index pairs of protein closer than 1.75 Å:
+// every bond of an ideal backbone with hydrogens, and no non-bonded pair
+func covalentPairs(protein *parser.Protein) [][2]int {
+	var pairs [][2]int
+	for i, a := range protein.Atoms {
+		for j := i + 1; j < len(protein.Atoms); j++ {
+			if atomDistance(a, protein.Atoms[j]) < 1.75 {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	return pairs
+}
+
+func atomDistance(a, b *parser.Atom) float64 {
+	return math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+}
+
+// TestBackrubMove rotates the fifth residue of a helix by 20° and checks its
+// Cα moves while the flanking Cα-Cα distance, every bond length and every
+// atom outside residues 4-6 stay put
+func TestBackrubMove(t *testing.T) {
+	deg := math.Pi / 180.0
+	angles := make([]geometry.RamachandranAngles, 8)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60 * deg, Psi: -45 * deg}
+	}
+	protein, err := geometry.BuildProteinFromAngles("AKLVEGLA", angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	original := cloneProteinDeep(protein)
+	bonds := covalentPairs(original)
+
+	const center = 4
+	if err := BackrubMove(protein, center, 20*deg); err != nil {
+		t.Fatalf("BackrubMove failed: %v", err)
+	}
+
+	shift := atomDistance(protein.Residues[center].CA, original.Residues[center].CA)
+	before := atomDistance(original.Residues[center-1].CA, original.Residues[center+1].CA)
+	after := atomDistance(protein.Residues[center-1].CA, protein.Residues[center+1].CA)
+	t.Logf("Central CA shift %.3f Å; flanking CA-CA %.6f → %.6f Å; %d bonds", shift, before, after, len(bonds))
+	if shift < 0.3 {
+		t.Errorf("Central CA moved only %.3f Å", shift)
+	}
+	if math.Abs(after-before) > 1e-9 {
+		t.Errorf("Flanking CA-CA changed %.9f → %.9f Å", before, after)
+	}
+
+	worst := 0.0
+	for _, b := range bonds {
+		d0 := atomDistance(original.Atoms[b[0]], original.Atoms[b[1]])
+		d1 := atomDistance(protein.Atoms[b[0]], protein.Atoms[b[1]])
+		worst = math.Max(worst, math.Abs(d1-d0))
+	}
+	if worst > 1e-9 {
+		t.Errorf("Bond lengths changed by up to %.2e Å", worst)
+	}
+
+	for i, atom := range protein.Atoms {
+		if atom.ResSeq < center || atom.ResSeq > center+2 { // ResSeq is 1-based
+			if atomDistance(atom, original.Atoms[i]) > 0 {
+				t.Errorf("Atom %s of residue %d outside the segment moved", atom.Name, atom.ResSeq)
+			}
+		}
+	}
+
+	if err := BackrubMove(protein, 0, 10*deg); err == nil {
+		t.Error("Backrub about the first residue accepted")
+	}
+}
+
+// TestBackrubMonteCarlo runs Monte Carlo with backrub moves and checks they
+// are accepted and leave backbone bond lengths ideal
+func TestBackrubMonteCarlo(t *testing.T) {
+	config := DefaultMonteCarloConfig()
+	config.MoveType = "backrub"
+	config.NumSteps = 300
+
+	result, err := MonteCarloVedic(buildIdealHelix(10), config)
+	if err != nil {
+		t.Fatalf("MonteCarloVedic failed: %v", err)
+	}
+	deviation := maxBackboneBondDeviation(result.FinalStructure)
+	t.Logf("Backrub MC: %d accepted, %d rejected, E %.1f → %.1f kcal/mol, bond deviation %.2e Å",
+		result.NumAccepted, result.NumRejected, result.InitialEnergy, result.FinalEnergy, deviation)
+	if result.NumAccepted == 0 {
+		t.Error("No backrub moves accepted")
+	}
+	if deviation > 1e-6 {
+		t.Errorf("Backrub MC distorted bonds by %.2e Å", deviation)
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
 )
 
 // RamachandranBasin represents an allowed region in Ramachandran space
@@ -70,6 +71,15 @@ type BasinExplorerConfig struct {
 	// Proline special handling (restricted φ angle)
 	ProlineHandling bool
 
+	// Draw each residue's (φ, ψ) from the binned Ramachandran probability
+	// map of its type (general, glycine, proline, pre-proline) instead of a
+	// basin center ± Gaussian jitter
+	UseProbabilisticSampling bool
+
+	// Basins MixedBasinSampling chooses from, weighted by residue
+	// preference (nil = DefaultBasinSet())
+	Basins BasinSet
+
 	// Random seed
 	Seed int64
 }
@@ -83,6 +93,8 @@ func DefaultBasinExplorerConfig() BasinExplorerConfig {
 		GlycineHandling: true,
 		ProlineHandling: true,
 		Seed:            42,
+
+		UseProbabilisticSampling: false, // Basin centers ± jitter
 	}
 }
 
@@ -199,12 +211,19 @@ func GetStandardRamachandranBasins() []RamachandranBasin {
 // BIOCHEMIST:
 // This ensures all generated structures have biophysically allowed angles
 // No Ramachandran outliers (unlike random sampling)
+//
+// With UseProbabilisticSampling, generates the same number of structures
+// (SamplesPerBasin per basin) by probabilisticSampling instead.
 func ExploreRamachandranBasins(sequence string, config BasinExplorerConfig) ([]*parser.Protein, error) {
 	if len(sequence) == 0 {
 		return nil, fmt.Errorf("empty sequence")
 	}
+	if config.UseProbabilisticSampling {
+		numStructures := config.SamplesPerBasin * len(GetStandardRamachandranBasins())
+		return probabilisticSampling(sequence, config, numStructures)
+	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
 	basins := GetStandardRamachandranBasins()
 	ensemble := make([]*parser.Protein, 0)
@@ -236,7 +255,8 @@ func ExploreRamachandranBasins(sequence string, config BasinExplorerConfig) ([]*
 
 			for resIdx := range sequence {
 				// Sample (φ, ψ) from this basin
-				phi, psi := sampleFromBasin(basin, config)
+				phi, psi := sampleFromBasin(basin, config, rng)
+				phi = prolinePhi(sequence, resIdx, phi, config, rng)
 
 				angles[resIdx] = geometry.RamachandranAngles{
 					Phi: phi * math.Pi / 180.0, // Convert to radians
@@ -245,8 +265,7 @@ func ExploreRamachandranBasins(sequence string, config BasinExplorerConfig) ([]*
 			}
 
 			// Build structure
-			template := createSequenceTemplate(sequence)
-			protein, err := buildStructureFromAngles(template, angles)
+			protein, err := buildBasinStructure(sequence, angles)
 			if err != nil {
 				// Skip failed structures
 				continue
@@ -271,58 +290,85 @@ func ExploreRamachandranBasins(sequence string, config BasinExplorerConfig) ([]*
 //
 // ALGORITHM:
 // 1. For each structure:
-//    a. For each residue, randomly select basin
+//    a. For each residue, select a basin of config.Basins weighted by
+//       population and the residue's preference (see BasinSet.Choose)
 //    b. Sample (φ, ψ) from selected basin
 // 2. Build structure
+//
+// With UseProbabilisticSampling, residues are drawn by probabilisticSampling.
 func MixedBasinSampling(sequence string, config BasinExplorerConfig, numStructures int) ([]*parser.Protein, error) {
 	if len(sequence) == 0 {
 		return nil, fmt.Errorf("empty sequence")
 	}
+	if config.UseProbabilisticSampling {
+		return probabilisticSampling(sequence, config, numStructures)
+	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
-	basins := GetStandardRamachandranBasins()
+	basins := config.Basins
+	if len(basins) == 0 {
+		basins = DefaultBasinSet()
+	}
 	ensemble := make([]*parser.Protein, 0, numStructures)
 
-	// Prepare basin selection weights
-	weights := make([]float64, len(basins))
-	totalWeight := 0.0
+	// Generate structures
+	for structIdx := 0; structIdx < numStructures; structIdx++ {
+		angles := make([]geometry.RamachandranAngles, len(sequence))
 
-	for i, basin := range basins {
-		if config.UseVedicBiasing {
-			weights[i] = basin.VedicScore * basin.Population
-		} else {
-			weights[i] = basin.Population
+		for resIdx := range sequence {
+			// Select basin for this residue, weighted by its preferences
+			// (Gly/Pro without special handling are weighted as Ala)
+			name, _ := ramaSamplingNames(sequence, resIdx, config)
+			basin := basins.Choose(name[0], config.UseVedicBiasing, rng)
+
+			// Sample from selected basin
+			phi, psi := basin.sample(rng)
+			phi = prolinePhi(sequence, resIdx, phi, config, rng)
+
+			angles[resIdx] = geometry.RamachandranAngles{
+				Phi: phi * math.Pi / 180.0,
+				Psi: psi * math.Pi / 180.0,
+			}
 		}
-		totalWeight += weights[i]
+
+		// Build structure
+		protein, err := buildBasinStructure(sequence, angles)
+		if err != nil {
+			continue
+		}
+
+		ensemble = append(ensemble, protein)
 	}
 
-	// Normalize weights
-	for i := range weights {
-		weights[i] /= totalWeight
+	if len(ensemble) == 0 {
+		return nil, fmt.Errorf("failed to generate any structures")
 	}
 
-	// Generate structures
+	return ensemble, nil
+}
+
+// probabilisticSampling builds structures whose every (φ, ψ) is drawn from
+// the residue type's Ramachandran probability map
+//
+// BIOCHEMIST:
+// Samples the whole statistically weighted allowed region, not just basin
+// centers: glycine reaches the left-handed (φ > 0) region, proline stays
+// near φ = -65°, and the residue before a proline avoids the α basin.
+// GlycineHandling / ProlineHandling off fall back to the general map.
+//
+// Uses its own RNG seeded with config.Seed, so ensembles are reproducible.
+func probabilisticSampling(sequence string, config BasinExplorerConfig, numStructures int) ([]*parser.Protein, error) {
+	rng := rand.New(rand.NewSource(config.Seed))
+	ensemble := make([]*parser.Protein, 0, numStructures)
+
 	for structIdx := 0; structIdx < numStructures; structIdx++ {
 		angles := make([]geometry.RamachandranAngles, len(sequence))
 
 		for resIdx := range sequence {
-			// Select basin for this residue (weighted random)
-			basin := selectBasinWeighted(basins, weights)
-
-			// Handle residue-specific constraints
-			resName := string(sequence[resIdx])
-			if config.GlycineHandling && resName == "G" {
-				// Glycine: prefer left-handed helix or bridge
-				basin = basins[2] // left_handed_helix
-			}
-			if config.ProlineHandling && resName == "P" {
-				// Proline: restricted to specific regions
-				basin = basins[3] // extended_ppii
-			}
-
-			// Sample from selected basin
-			phi, psi := sampleFromBasin(basin, config)
+			name, next := ramaSamplingNames(sequence, resIdx, config)
+			phi, psi := validation.SampleRamachandran(name, next, rng)
+			phi = prolinePhi(sequence, resIdx, phi, config, rng)
 
 			angles[resIdx] = geometry.RamachandranAngles{
 				Phi: phi * math.Pi / 180.0,
@@ -330,9 +376,7 @@ func MixedBasinSampling(sequence string, config BasinExplorerConfig, numStructur
 			}
 		}
 
-		// Build structure
-		template := createSequenceTemplate(sequence)
-		protein, err := buildStructureFromAngles(template, angles)
+		protein, err := buildBasinStructure(sequence, angles)
 		if err != nil {
 			continue
 		}
@@ -347,15 +391,37 @@ func MixedBasinSampling(sequence string, config BasinExplorerConfig, numStructur
 	return ensemble, nil
 }
 
+// ramaSamplingNames returns the residue and next-residue codes that select
+// the probability map, masking Gly/Pro when their special handling is off
+func ramaSamplingNames(sequence string, i int, config BasinExplorerConfig) (name, next string) {
+	name = string(sequence[i])
+	if i+1 < len(sequence) {
+		next = string(sequence[i+1])
+	}
+
+	if !config.GlycineHandling && name == "G" {
+		name = "A"
+	}
+	if !config.ProlineHandling {
+		if name == "P" {
+			name = "A"
+		}
+		if next == "P" {
+			next = ""
+		}
+	}
+	return name, next
+}
+
 // sampleFromBasin samples (φ, ψ) from Gaussian around basin center
 //
 // MATHEMATICIAN:
 // Gaussian sampling: N(μ, σ²)
 // μ = basin center, σ = basin standard deviation
-func sampleFromBasin(basin RamachandranBasin, config BasinExplorerConfig) (phi, psi float64) {
+func sampleFromBasin(basin RamachandranBasin, config BasinExplorerConfig, rng *rand.Rand) (phi, psi float64) {
 	// Gaussian sampling
-	phi = basin.PhiCenter + rand.NormFloat64()*basin.PhiSigma
-	psi = basin.PsiCenter + rand.NormFloat64()*basin.PsiSigma
+	phi = basin.PhiCenter + rng.NormFloat64()*basin.PhiSigma
+	psi = basin.PsiCenter + rng.NormFloat64()*basin.PsiSigma
 
 	// Wrap to [-180, +180]
 	phi = wrapAngle(phi)
@@ -364,6 +430,30 @@ func sampleFromBasin(basin RamachandranBasin, config BasinExplorerConfig) (phi,
 	return phi, psi
 }
 
+// prolinePhi returns φ (degrees) for residue i of sequence: unchanged unless
+// the residue is a proline under ProlineHandling and φ lies outside the
+// ring-compatible window, in which case φ is redrawn from
+// N(ProlinePhiCenter, ProlinePhiSigma) truncated to the window
+//
+// BIOCHEMIST: Basins are shared by all residues, so a β-basin draw would
+// otherwise put a proline at φ = -120°, which the ring cannot reach
+func prolinePhi(sequence string, i int, phi float64, config BasinExplorerConfig, rng *rand.Rand) float64 {
+	if !config.ProlineHandling || sequence[i] != 'P' {
+		return phi
+	}
+	if phi >= geometry.ProlinePhiMin && phi <= geometry.ProlinePhiMax {
+		return phi
+	}
+	// Window is ±~2σ wide: a few redraws almost always suffice
+	for attempt := 0; attempt < 100; attempt++ {
+		phi = geometry.ProlinePhiCenter + rng.NormFloat64()*geometry.ProlinePhiSigma
+		if phi >= geometry.ProlinePhiMin && phi <= geometry.ProlinePhiMax {
+			return phi
+		}
+	}
+	return geometry.ProlinePhiCenter
+}
+
 // wrapAngle wraps angle to [-180, +180] degrees
 func wrapAngle(angle float64) float64 {
 	for angle > 180.0 {
@@ -376,8 +466,8 @@ func wrapAngle(angle float64) float64 {
 }
 
 // selectBasinWeighted selects basin using weighted random sampling
-func selectBasinWeighted(basins []RamachandranBasin, weights []float64) RamachandranBasin {
-	r := rand.Float64()
+func selectBasinWeighted(basins []RamachandranBasin, weights []float64, rng *rand.Rand) RamachandranBasin {
+	r := rng.Float64()
 	cumulative := 0.0
 
 	for i, weight := range weights {
@@ -404,44 +494,16 @@ func sortBasinsByVedic(basins []RamachandranBasin) {
 	}
 }
 
-// createSequenceTemplate creates template protein from sequence
-func createSequenceTemplate(sequence string) *parser.Protein {
-	protein := &parser.Protein{
-		Name:     "basin_sampled",
-		Residues: make([]*parser.Residue, len(sequence)),
-	}
-
-	for i, aa := range sequence {
-		// Map single-letter to three-letter code (simplified)
-		threeLetter := singleToThreeLetter(string(aa))
-
-		protein.Residues[i] = &parser.Residue{
-			Name:    threeLetter,
-			SeqNum:  i + 1,
-			ChainID: "A",
-		}
+// buildBasinStructure builds a sampled structure with the NeRF builder,
+// which reproduces the sampled angles exactly (proline φ already lies in
+// its ring window)
+func buildBasinStructure(sequence string, angles []geometry.RamachandranAngles) (*parser.Protein, error) {
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		return nil, err
 	}
-
-	return protein
-}
-
-// singleToThreeLetter converts single-letter amino acid code to three-letter
-//
-// BIOCHEMIST: Standard amino acid codes
-func singleToThreeLetter(single string) string {
-	codeMap := map[string]string{
-		"A": "ALA", "R": "ARG", "N": "ASN", "D": "ASP",
-		"C": "CYS", "Q": "GLN", "E": "GLU", "G": "GLY",
-		"H": "HIS", "I": "ILE", "L": "LEU", "K": "LYS",
-		"M": "MET", "F": "PHE", "P": "PRO", "S": "SER",
-		"T": "THR", "W": "TRP", "Y": "TYR", "V": "VAL",
-	}
-
-	if code, ok := codeMap[single]; ok {
-		return code
-	}
-
-	return "ALA" // Default fallback
+	protein.Name = "basin_sampled"
+	return protein, nil
 }
 
 // ConstrainedBasinSampling generates structures with basin constraints
@@ -459,7 +521,7 @@ func ConstrainedBasinSampling(sequence string, constraints map[int]string, confi
 		return nil, fmt.Errorf("empty sequence")
 	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
 	basins := GetStandardRamachandranBasins()
 	basinMap := make(map[string]RamachandranBasin)
@@ -494,11 +556,12 @@ func ConstrainedBasinSampling(sequence string, constraints map[int]string, confi
 				for i := range weights {
 					weights[i] /= totalWeight
 				}
-				basin = selectBasinWeighted(basins, weights)
+				basin = selectBasinWeighted(basins, weights, rng)
 			}
 
 			// Sample from basin
-			phi, psi := sampleFromBasin(basin, config)
+			phi, psi := sampleFromBasin(basin, config, rng)
+			phi = prolinePhi(sequence, resIdx, phi, config, rng)
 
 			angles[resIdx] = geometry.RamachandranAngles{
 				Phi: phi * math.Pi / 180.0,
@@ -507,8 +570,7 @@ func ConstrainedBasinSampling(sequence string, constraints map[int]string, confi
 		}
 
 		// Build structure
-		template := createSequenceTemplate(sequence)
-		protein, err := buildStructureFromAngles(template, angles)
+		protein, err := buildBasinStructure(sequence, angles)
 		if err != nil {
 			continue
 		}
@@ -0,0 +1,211 @@
+package sampling
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestProbabilisticBasinSampling checks residue-type maps: glycine populates
+// the left-handed (φ > 0) region, other residues largely avoid it
+func TestProbabilisticBasinSampling(t *testing.T) {
+	sequence := "AGLGKGVGSGEGTGAG"
+
+	config := DefaultBasinExplorerConfig()
+	config.UseProbabilisticSampling = true
+	config.SamplesPerBasin = 10
+
+	ensemble, err := ExploreRamachandranBasins(sequence, config)
+	if err != nil {
+		t.Fatalf("ExploreRamachandranBasins failed: %v", err)
+	}
+	if want := config.SamplesPerBasin * len(GetStandardRamachandranBasins()); len(ensemble) != want {
+		t.Errorf("Got %d structures, want %d", len(ensemble), want)
+	}
+
+	glyLeft, glyTotal := 0, 0
+	otherLeft, otherTotal := 0, 0
+	for _, protein := range ensemble {
+		angles := geometry.CalculateRamachandran(protein)
+		for i, angle := range angles {
+			if math.IsNaN(angle.Phi) {
+				continue
+			}
+			left := angle.Phi > 0
+			if sequence[i] == 'G' {
+				glyTotal++
+				if left {
+					glyLeft++
+				}
+			} else {
+				otherTotal++
+				if left {
+					otherLeft++
+				}
+			}
+		}
+	}
+
+	glyFraction := float64(glyLeft) / float64(glyTotal)
+	otherFraction := float64(otherLeft) / float64(otherTotal)
+	t.Logf("φ > 0: glycine %.1f%% (%d/%d), other %.1f%% (%d/%d)",
+		100*glyFraction, glyLeft, glyTotal, 100*otherFraction, otherLeft, otherTotal)
+
+	if glyFraction < 0.3 {
+		t.Errorf("Glycine φ > 0 fraction %.2f, want ≥ 0.3", glyFraction)
+	}
+	if otherFraction > 0.1 {
+		t.Errorf("Non-glycine φ > 0 fraction %.2f, want ≤ 0.1", otherFraction)
+	}
+
+	// Reproducible for a fixed seed
+	again, err := ExploreRamachandranBasins(sequence, config)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if a, b := ensemble[3].Residues[5].CA, again[3].Residues[5].CA; a.X != b.X || a.Y != b.Y || a.Z != b.Z {
+		t.Error("Same seed gave different structures")
+	}
+}
+
+// TestSamplersConcurrentDeterminism runs seeded samplers concurrently and
+// checks each reproduces its serial output: no shared random source
+func TestSamplersConcurrentDeterminism(t *testing.T) {
+	const sequence = "MKVLAGEGTSLPQW"
+	const runs = 4
+
+	basinConfig := DefaultBasinExplorerConfig()
+	mixed := func(seed int64) []*parser.Protein {
+		config := basinConfig
+		config.Seed = seed
+		ensemble, err := MixedBasinSampling(sequence, config, 6)
+		if err != nil {
+			t.Errorf("MixedBasinSampling failed: %v", err)
+		}
+		return ensemble
+	}
+
+	initial := createTestProtein(8)
+	quatConfig := DefaultQuaternionSearchConfig()
+	quatConfig.UseFibonacciSphere = false
+	quat := func(seed int64) []*parser.Protein {
+		config := quatConfig
+		config.Seed = seed
+		ensemble, err := QuaternionGuidedSearch(initial, config)
+		if err != nil {
+			t.Errorf("QuaternionGuidedSearch failed: %v", err)
+		}
+		return ensemble
+	}
+
+	samplers := map[string]func(int64) []*parser.Protein{"MixedBasinSampling": mixed, "QuaternionGuidedSearch": quat}
+	for name, sample := range samplers {
+		serial := make([][]*parser.Protein, runs)
+		for i := range serial {
+			serial[i] = sample(int64(100 + i))
+		}
+
+		// Every sampler and seed at once; each goroutine owns one slot
+		concurrent := make(map[string][][]*parser.Protein)
+		for other := range samplers {
+			concurrent[other] = make([][]*parser.Protein, runs)
+		}
+		var wg sync.WaitGroup
+		for other, otherSample := range samplers {
+			for i := 0; i < runs; i++ {
+				wg.Add(1)
+				go func(results [][]*parser.Protein, sample func(int64) []*parser.Protein, i int) {
+					defer wg.Done()
+					results[i] = sample(int64(100 + i))
+				}(concurrent[other], otherSample, i)
+			}
+		}
+		wg.Wait()
+
+		for i := range serial {
+			if !sameCoordinates(serial[i], concurrent[name][i]) {
+				t.Errorf("%s seed %d: concurrent run differs from serial run", name, 100+i)
+			}
+		}
+		if sameCoordinates(serial[0], serial[1]) {
+			t.Errorf("%s: different seeds gave identical ensembles", name)
+		}
+	}
+}
+
+// sameCoordinates reports whether two ensembles have bitwise identical atoms
+func sameCoordinates(a, b []*parser.Protein) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i].Atoms) != len(b[i].Atoms) {
+			return false
+		}
+		for j, atom := range a[i].Atoms {
+			other := b[i].Atoms[j]
+			if atom.X != other.X || atom.Y != other.Y || atom.Z != other.Z {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TestProlinePhiSampling samples a proline-rich tail with every basin
+// sampler and checks proline φ stays in the ring window while the
+// neighbouring non-prolines range outside it
+func TestProlinePhiSampling(t *testing.T) {
+	const sequence = "GGPSSGRPPPS" // Trp-cage tail
+	const slack = 0.5              // Degrees, for round-trip through coordinates
+
+	config := DefaultBasinExplorerConfig()
+	config.SamplesPerBasin = 5
+	probabilistic := config
+	probabilistic.UseProbabilisticSampling = true
+
+	samplers := map[string]func() ([]*parser.Protein, error){
+		"basins":        func() ([]*parser.Protein, error) { return ExploreRamachandranBasins(sequence, config) },
+		"mixed":         func() ([]*parser.Protein, error) { return MixedBasinSampling(sequence, config, 40) },
+		"constrained":   func() ([]*parser.Protein, error) { return ConstrainedBasinSampling(sequence, nil, config, 40) },
+		"probabilistic": func() ([]*parser.Protein, error) { return MixedBasinSampling(sequence, probabilistic, 40) },
+	}
+	for name, sample := range samplers {
+		ensemble, err := sample()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		proMin, proMax := math.Inf(1), math.Inf(-1)
+		otherOutside, otherTotal := 0, 0
+		for _, protein := range ensemble {
+			for i, angle := range geometry.CalculateRamachandran(protein) {
+				if !angle.HasPhi() {
+					continue
+				}
+				phi := angle.ToDegressPhi()
+				if sequence[i] == 'P' {
+					proMin, proMax = math.Min(proMin, phi), math.Max(proMax, phi)
+					continue
+				}
+				otherTotal++
+				if phi < geometry.ProlinePhiMin || phi > geometry.ProlinePhiMax {
+					otherOutside++
+				}
+			}
+		}
+		t.Logf("%s: proline φ in [%.1f°, %.1f°]; non-proline outside window %d/%d",
+			name, proMin, proMax, otherOutside, otherTotal)
+
+		if proMin < geometry.ProlinePhiMin-slack || proMax > geometry.ProlinePhiMax+slack {
+			t.Errorf("%s: proline φ spans [%.1f°, %.1f°], outside [%.0f°, %.0f°]",
+				name, proMin, proMax, geometry.ProlinePhiMin, geometry.ProlinePhiMax)
+		}
+		if otherOutside == 0 {
+			t.Errorf("%s: non-proline φ confined to the proline window", name)
+		}
+	}
+}
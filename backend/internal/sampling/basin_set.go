@@ -0,0 +1,133 @@
+// Package sampling - Ramachandran basin definitions with residue preferences
+//
+// MixedBasinSampling used to draw every residue's basin from the same
+// population weights and then hard-code two exceptions: every glycine went
+// to the left-handed helix and every proline to PPII. A BasinSet keeps the
+// basins as data instead, each with per-residue preference multipliers, so
+// a glycine is merely much more likely to be left-handed than an alanine
+// and new residue biases need no code.
+//
+// BIOCHEMIST: Glycine, lacking a Cβ, populates φ > 0 (αL and type II turn
+// positions) far more than any other residue, with Asn and Asp next; the
+// proline ring fixes φ near -65°, making PPII its most common state and
+// ruling out φ > 0
+//
+// CITATION:
+// Hovmöller, S., Zhou, T., & Ohlson, T. (2002). "Conformations of amino acids
+// in proteins." Acta Cryst. D58: 768-776.
+package sampling
+
+import "math/rand"
+
+// Basin is a Gaussian region of Ramachandran space that a residue's (φ, ψ)
+// can be drawn from
+type Basin struct {
+	Name string
+
+	// Center and standard deviation (degrees)
+	PhiCenter float64
+	PsiCenter float64
+	PhiSpread float64
+	PsiSpread float64
+
+	// Relative population over all residues (0-1)
+	Population float64
+
+	// Vedic harmonic score, multiplies Population under UseVedicBiasing
+	VedicScore float64
+
+	// Multiplier of the selection weight per one-letter residue code;
+	// residues not listed use 1, and 0 excludes the basin for that residue
+	ResiduePreference map[byte]float64
+}
+
+// BasinSet is the set of basins a residue's basin is chosen from
+type BasinSet []Basin
+
+// basinResiduePreferences are the residue preference multipliers of
+// DefaultBasinSet, keyed by basin name
+var basinResiduePreferences = map[string]map[byte]float64{
+	"alpha_helix":       {'G': 0.5, 'P': 0.7},
+	"beta_sheet":        {'G': 0.5, 'P': 0.3},
+	"left_handed_helix": {'G': 8.0, 'N': 3.0, 'D': 3.0, 'P': 0},
+	"extended_ppii":     {'P': 4.0},
+	"bridge":            {'P': 0.5},
+	"turn_type_II":      {'G': 4.0, 'N': 2.0, 'D': 2.0, 'P': 0},
+}
+
+// DefaultBasinSet returns the standard Ramachandran basins (see
+// GetStandardRamachandranBasins) with residue preferences: glycine favours
+// the left-handed helix (~39% of Vedic-biased draws vs ~6% for alanine),
+// Asn and Asp less so, and proline favours PPII (~53% vs ~12%) and never
+// takes φ > 0
+func DefaultBasinSet() BasinSet {
+	standard := GetStandardRamachandranBasins()
+	set := make(BasinSet, len(standard))
+	for i, basin := range standard {
+		preference := make(map[byte]float64)
+		for code, multiplier := range basinResiduePreferences[basin.Name] {
+			preference[code] = multiplier
+		}
+		set[i] = Basin{
+			Name:              basin.Name,
+			PhiCenter:         basin.PhiCenter,
+			PsiCenter:         basin.PsiCenter,
+			PhiSpread:         basin.PhiSigma,
+			PsiSpread:         basin.PsiSigma,
+			Population:        basin.Population,
+			VedicScore:        basin.VedicScore,
+			ResiduePreference: preference,
+		}
+	}
+	return set
+}
+
+// Weight is the unnormalized selection weight of basin b for residue code
+func (b Basin) Weight(code byte, useVedic bool) float64 {
+	weight := b.Population
+	if useVedic {
+		weight *= b.VedicScore
+	}
+	if multiplier, ok := b.ResiduePreference[code]; ok {
+		weight *= multiplier
+	}
+	return weight
+}
+
+// Choose draws a basin for residue code with probability proportional to
+// its Weight; returns the first basin if all weights are zero
+func (s BasinSet) Choose(code byte, useVedic bool, rng *rand.Rand) Basin {
+	total := 0.0
+	for _, basin := range s {
+		total += basin.Weight(code, useVedic)
+	}
+	if total <= 0 {
+		return s[0]
+	}
+
+	r := rng.Float64() * total
+	cumulative := 0.0
+	for _, basin := range s {
+		weight := basin.Weight(code, useVedic)
+		cumulative += weight
+		if weight > 0 && r < cumulative {
+			return basin
+		}
+	}
+
+	// Rounding: return the last basin with weight
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].Weight(code, useVedic) > 0 {
+			return s[i]
+		}
+	}
+	return s[0]
+}
+
+// sample draws (φ, ψ) in degrees from the basin's Gaussian, wrapped to
+// [-180, +180]
+func (b Basin) sample(rng *rand.Rand) (phi, psi float64) {
+	phi = wrapAngle(b.PhiCenter + rng.NormFloat64()*b.PhiSpread)
+	psi = wrapAngle(b.PsiCenter + rng.NormFloat64()*b.PsiSpread)
+	return phi, psi
+}
@@ -0,0 +1,92 @@
+package sampling
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+)
+
+// TestBasinSetResiduePreference checks that DefaultBasinSet sends glycine
+// to the left-handed helix, and proline to PPII, more often than alanine
+func TestBasinSetResiduePreference(t *testing.T) {
+	const draws = 20000
+	set := DefaultBasinSet()
+	rng := rand.New(rand.NewSource(7))
+
+	rate := func(code byte, basin string) float64 {
+		hits := 0
+		for i := 0; i < draws; i++ {
+			if set.Choose(code, true, rng).Name == basin {
+				hits++
+			}
+		}
+		return float64(hits) / draws
+	}
+
+	glyLeft, alaLeft := rate('G', "left_handed_helix"), rate('A', "left_handed_helix")
+	proPPII, alaPPII := rate('P', "extended_ppii"), rate('A', "extended_ppii")
+	proLeft := rate('P', "left_handed_helix")
+	t.Logf("left_handed_helix: Gly %.1f%%, Ala %.1f%%, Pro %.1f%%; extended_ppii: Pro %.1f%%, Ala %.1f%%",
+		100*glyLeft, 100*alaLeft, 100*proLeft, 100*proPPII, 100*alaPPII)
+
+	if glyLeft < 3*alaLeft {
+		t.Errorf("Gly left-handed rate %.3f, want ≥ 3× Ala's %.3f", glyLeft, alaLeft)
+	}
+	if proPPII < 2*alaPPII {
+		t.Errorf("Pro PPII rate %.3f, want ≥ 2× Ala's %.3f", proPPII, alaPPII)
+	}
+	if proLeft != 0 {
+		t.Errorf("Pro left-handed rate %.3f, want 0", proLeft)
+	}
+
+	// The sampled structures show the same bias
+	const sequence = "AGAGAGAGAGAG"
+	config := DefaultBasinExplorerConfig()
+	ensemble, err := MixedBasinSampling(sequence, config, 200)
+	if err != nil {
+		t.Fatalf("MixedBasinSampling failed: %v", err)
+	}
+	left := map[byte]int{}
+	total := map[byte]int{}
+	for _, protein := range ensemble {
+		for i, angle := range geometry.CalculateRamachandran(protein) {
+			if math.IsNaN(angle.Phi) {
+				continue
+			}
+			total[sequence[i]]++
+			if angle.Phi > 0 {
+				left[sequence[i]]++
+			}
+		}
+	}
+	glyFraction := float64(left['G']) / float64(total['G'])
+	alaFraction := float64(left['A']) / float64(total['A'])
+	t.Logf("Sampled φ > 0: Gly %.1f%%, Ala %.1f%%", 100*glyFraction, 100*alaFraction)
+	if glyFraction <= 2*alaFraction {
+		t.Errorf("Sampled Gly φ > 0 fraction %.3f, want > 2× Ala's %.3f", glyFraction, alaFraction)
+	}
+
+	// Without glycine handling, Gly is weighted as Ala
+	config.GlycineHandling = false
+	plain, err := MixedBasinSampling(sequence, config, 200)
+	if err != nil {
+		t.Fatalf("MixedBasinSampling without GlycineHandling failed: %v", err)
+	}
+	glyLeftPlain, glyTotalPlain := 0, 0
+	for _, protein := range plain {
+		for i, angle := range geometry.CalculateRamachandran(protein) {
+			if sequence[i] != 'G' || math.IsNaN(angle.Phi) {
+				continue
+			}
+			glyTotalPlain++
+			if angle.Phi > 0 {
+				glyLeftPlain++
+			}
+		}
+	}
+	if plainFraction := float64(glyLeftPlain) / float64(glyTotalPlain); plainFraction >= glyFraction {
+		t.Errorf("Gly φ > 0 fraction %.3f without GlycineHandling, want below %.3f", plainFraction, glyFraction)
+	}
+}
@@ -0,0 +1,67 @@
+// Package sampling - Boltzmann-weighted ensemble averages
+//
+// The best structure is one point of the ensemble; thermodynamic quantities
+// (mean Rg, mean number of contacts, fraction helical) are averages over it.
+// BoltzmannAverage weights each sampled structure by its Boltzmann factor.
+//
+// PHYSICIST: ⟨O⟩ = Σ O_i·exp(-E_i/kT) / Σ exp(-E_i/kT)
+// MATHEMATICIAN: Factors are computed as exp(-(E_i - E_min)/kT), so the
+// largest is exactly 1 - no overflow or all-zero underflow at low T
+// ETHICIST: Reweighting is only as good as the sampling - structures the
+// sampler never visited contribute nothing
+package sampling
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+)
+
+// BoltzmannAverage returns the Boltzmann-weighted average of observable
+// over structures with the given energies (kcal/mol) at temperature (K)
+//
+// Structures with a NaN energy or a nil protein are ignored. At
+// temperature <= 0 the average is the observable of the lowest-energy
+// structure(s). Returns NaN when the slices differ in length or no
+// structure remains.
+func BoltzmannAverage(structures []*parser.Protein, energies []float64, temperature float64,
+	observable func(*parser.Protein) float64) float64 {
+
+	if len(structures) != len(energies) {
+		return math.NaN()
+	}
+
+	minEnergy := math.Inf(1)
+	for i, e := range energies {
+		if structures[i] != nil && !math.IsNaN(e) && e < minEnergy {
+			minEnergy = e
+		}
+	}
+	if math.IsInf(minEnergy, 1) {
+		return math.NaN()
+	}
+
+	const kB = physics.BoltzmannKcal // kcal/(mol·K)
+	var sumWeighted, sumWeights float64
+	for i, e := range energies {
+		if structures[i] == nil || math.IsNaN(e) {
+			continue
+		}
+
+		var weight float64
+		if temperature > 0 {
+			weight = math.Exp(-(e - minEnergy) / (kB * temperature))
+		} else if e == minEnergy {
+			weight = 1
+		}
+		if weight == 0 {
+			continue
+		}
+
+		sumWeighted += weight * observable(structures[i])
+		sumWeights += weight
+	}
+
+	return sumWeighted / sumWeights
+}
@@ -0,0 +1,49 @@
+package sampling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestBoltzmannAverageTwoState checks the average moves toward the
+// low-energy state as temperature drops
+func TestBoltzmannAverageTwoState(t *testing.T) {
+	// State A: observable 0, E = 0; state B: observable 1, E = 1 kcal/mol
+	stateA := newDoubleWellParticle()
+	stateA.Atoms[0].X = 0.0
+	stateB := newDoubleWellParticle()
+	stateB.Atoms[0].X = 1.0
+
+	structures := []*parser.Protein{stateA, stateB}
+	energies := []float64{0.0, 1.0}
+	observable := func(p *parser.Protein) float64 { return p.Atoms[0].X }
+
+	previous := math.Inf(1)
+	for _, T := range []float64{10000, 1000, 300, 100, 10} {
+		avg := BoltzmannAverage(structures, energies, T, observable)
+
+		// Exact two-state result: p_B = 1 / (1 + exp(ΔE/kT))
+		want := 1.0 / (1.0 + math.Exp(1.0/(0.001987*T)))
+		t.Logf("T = %5.0f K: ⟨x⟩ = %.6f (exact %.6f)", T, avg, want)
+
+		if math.Abs(avg-want) > 1e-9 {
+			t.Errorf("T = %.0f K: average %.9f, want %.9f", T, avg, want)
+		}
+		if avg >= previous {
+			t.Errorf("T = %.0f K: average %.6f did not decrease from %.6f", T, avg, previous)
+		}
+		previous = avg
+	}
+
+	if avg := BoltzmannAverage(structures, energies, 0, observable); avg != 0 {
+		t.Errorf("T = 0: average %.6f, want the ground state 0", avg)
+	}
+
+	// Large absolute energies must not overflow
+	shifted := []float64{-50000.0, -49999.0}
+	if avg := BoltzmannAverage(structures, shifted, 300, observable); math.IsNaN(avg) || math.IsInf(avg, 0) {
+		t.Errorf("shifted energies gave %v", avg)
+	}
+}
@@ -0,0 +1,156 @@
+// Package sampling - RMSD-based ensemble clustering
+//
+// Sampling produces hundreds of structures, but the lowest-energy one is a
+// noisy pick. Clustering on superposed CA-RMSD groups them into distinct
+// conformational states; the centroid of the most populated cluster is a
+// robust consensus prediction (the state sampling visited most often).
+//
+// BIOCHEMIST: Populated clusters correspond to free-energy basins
+// MATHEMATICIAN: Greedy neighbor-count clustering (GROMOS algorithm)
+// ETHICIST: Reports every cluster, not just the winner
+//
+// CITATION:
+// Daura, X., et al. (1999). "Peptide folding: When simulation meets experiment."
+// Angew. Chem. Int. Ed. 38(1-2): 236-240.
+package sampling
+
+import (
+	"fmt"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
+)
+
+// Cluster is a group of structurally similar ensemble members
+type Cluster struct {
+	// Representative structure (member with most neighbors)
+	Centroid      *parser.Protein
+	CentroidIndex int
+
+	// Indices into the input ensemble (centroid first)
+	Members []int
+	Size    int
+
+	// Mean pairwise CA-RMSD between members (Å), 0 for singletons
+	MeanRMSD float64
+}
+
+// ClusterEnsemble clusters structures on pairwise superposed CA-RMSD
+//
+// ALGORITHM (GROMOS):
+// 1. Compute all pairwise RMSDs
+// 2. Structure with the most neighbors within rmsdCutoff becomes a centroid
+// 3. It and its neighbors form a cluster and are removed
+// 4. Repeat until every structure is assigned
+//
+// Clusters are returned largest first; ties keep the earlier centroid index.
+func ClusterEnsemble(structures []*parser.Protein, rmsdCutoff float64) ([]Cluster, error) {
+	if len(structures) == 0 {
+		return nil, fmt.Errorf("empty ensemble")
+	}
+	if rmsdCutoff <= 0 {
+		return nil, fmt.Errorf("RMSD cutoff must be positive, got %.2f", rmsdCutoff)
+	}
+
+	n := len(structures)
+	rmsd := make([][]float64, n)
+	for i := range rmsd {
+		rmsd[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d, err := validation.CalculateSuperposedRMSD(structures[i], structures[j])
+			if err != nil {
+				return nil, fmt.Errorf("structures %d and %d: %w", i, j, err)
+			}
+			rmsd[i][j] = d
+			rmsd[j][i] = d
+		}
+	}
+
+	clusters := make([]Cluster, 0)
+	for _, members := range gromosClusters(rmsd, rmsdCutoff) {
+		clusters = append(clusters, Cluster{
+			Centroid:      structures[members[0]],
+			CentroidIndex: members[0],
+			Members:       members,
+			Size:          len(members),
+			MeanRMSD:      meanPairwiseRMSD(rmsd, members),
+		})
+	}
+
+	return clusters, nil
+}
+
+// gromosClusters groups the indices of an RMSD matrix into GROMOS clusters,
+// centroid first in each, in the order ClusterEnsemble returns them
+func gromosClusters(rmsd [][]float64, rmsdCutoff float64) [][]int {
+	n := len(rmsd)
+	assigned := make([]bool, n)
+	remaining := n
+	var clusters [][]int
+
+	for remaining > 0 {
+		// Find unassigned structure with most unassigned neighbors
+		centroid, bestCount := -1, -1
+		for i := 0; i < n; i++ {
+			if assigned[i] {
+				continue
+			}
+			count := 0
+			for j := 0; j < n; j++ {
+				if j != i && !assigned[j] && rmsd[i][j] <= rmsdCutoff {
+					count++
+				}
+			}
+			if count > bestCount {
+				centroid, bestCount = i, count
+			}
+		}
+
+		members := []int{centroid}
+		for j := 0; j < n; j++ {
+			if j != centroid && !assigned[j] && rmsd[centroid][j] <= rmsdCutoff {
+				members = append(members, j)
+			}
+		}
+		for _, m := range members {
+			assigned[m] = true
+		}
+		remaining -= len(members)
+
+		clusters = append(clusters, members)
+	}
+
+	return clusters
+}
+
+// ConsensusStructure returns the centroid of the largest cluster
+//
+// BIOCHEMIST:
+// The most frequently sampled state is a better prediction than the single
+// lowest-energy structure, which is often an artifact of force field error.
+func ConsensusStructure(structures []*parser.Protein, rmsdCutoff float64) (*parser.Protein, error) {
+	clusters, err := ClusterEnsemble(structures, rmsdCutoff)
+	if err != nil {
+		return nil, err
+	}
+	return clusters[0].Centroid, nil
+}
+
+// meanPairwiseRMSD averages RMSD over all member pairs
+func meanPairwiseRMSD(rmsd [][]float64, members []int) float64 {
+	if len(members) < 2 {
+		return 0.0
+	}
+
+	sum := 0.0
+	pairs := 0
+	for a := 0; a < len(members); a++ {
+		for b := a + 1; b < len(members); b++ {
+			sum += rmsd[members[a]][members[b]]
+			pairs++
+		}
+	}
+	return sum / float64(pairs)
+}
@@ -0,0 +1,124 @@
+package sampling
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
+)
+
+// TestClusterEnsembleThreeConformers builds noisy, randomly oriented copies of
+// three distinct conformers and checks three clusters emerge
+func TestClusterEnsembleThreeConformers(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+
+	conformers := []*parser.Protein{
+		buildIdealBackbone(12, -60, -45),  // α-helix
+		buildIdealBackbone(12, -120, 130), // β-strand
+		buildIdealBackbone(12, 60, 45),    // left-handed helix
+	}
+
+	// Conformers must be well separated for the test to be meaningful
+	for i := 0; i < len(conformers); i++ {
+		for j := i + 1; j < len(conformers); j++ {
+			d, err := validation.CalculateSuperposedRMSD(conformers[i], conformers[j])
+			if err != nil {
+				t.Fatalf("RMSD failed: %v", err)
+			}
+			t.Logf("Conformer %d vs %d: %.2f Å", i, j, d)
+			if d < 3.0 {
+				t.Fatalf("Conformers %d and %d too similar (%.2f Å)", i, j, d)
+			}
+		}
+	}
+
+	const copies = 4
+	ensemble := make([]*parser.Protein, 0)
+	labels := make([]int, 0)
+	for c, base := range conformers {
+		for k := 0; k < copies; k++ {
+			member := cloneProteinDeep(base)
+			perturbCoordinates(member, 0.15, rng)
+			randomRigidMotion(member, rng)
+			ensemble = append(ensemble, member)
+			labels = append(labels, c)
+		}
+	}
+
+	clusters, err := ClusterEnsemble(ensemble, 1.5)
+	if err != nil {
+		t.Fatalf("ClusterEnsemble failed: %v", err)
+	}
+
+	if len(clusters) != 3 {
+		t.Fatalf("Expected 3 clusters, got %d", len(clusters))
+	}
+
+	for i, cl := range clusters {
+		if cl.Size != copies {
+			t.Errorf("Cluster %d: size %d, want %d", i, cl.Size, copies)
+		}
+		label := labels[cl.CentroidIndex]
+		for _, m := range cl.Members {
+			if labels[m] != label {
+				t.Errorf("Cluster %d mixes conformers %d and %d", i, label, labels[m])
+			}
+		}
+		if cl.MeanRMSD <= 0 || cl.MeanRMSD > 1.0 {
+			t.Errorf("Cluster %d: unexpected mean RMSD %.2f Å", i, cl.MeanRMSD)
+		}
+		t.Logf("Cluster %d: conformer %d, size %d, mean RMSD %.2f Å", i, label, cl.Size, cl.MeanRMSD)
+	}
+}
+
+// TestClusterEnsembleEdgeCases covers single-structure, identical and
+// invalid inputs
+func TestClusterEnsembleEdgeCases(t *testing.T) {
+	helix := buildIdealHelix(8)
+
+	clusters, err := ClusterEnsemble([]*parser.Protein{helix}, 1.0)
+	if err != nil || len(clusters) != 1 || clusters[0].Size != 1 || clusters[0].MeanRMSD != 0 {
+		t.Errorf("Single structure: got %+v, err=%v", clusters, err)
+	}
+
+	identical := []*parser.Protein{helix, cloneProteinDeep(helix), cloneProteinDeep(helix)}
+	clusters, err = ClusterEnsemble(identical, 0.5)
+	if err != nil || len(clusters) != 1 || clusters[0].Size != 3 {
+		t.Fatalf("Identical structures: got %d clusters, err=%v", len(clusters), err)
+	}
+	if clusters[0].MeanRMSD > 1e-6 {
+		t.Errorf("Identical structures: mean RMSD %.6f, want 0", clusters[0].MeanRMSD)
+	}
+
+	consensus, err := ConsensusStructure(identical, 0.5)
+	if err != nil || consensus != helix {
+		t.Errorf("ConsensusStructure should return largest-cluster centroid, err=%v", err)
+	}
+
+	if _, err := ClusterEnsemble(nil, 1.0); err == nil {
+		t.Error("Expected error for empty ensemble")
+	}
+	if _, err := ClusterEnsemble(identical, 0); err == nil {
+		t.Error("Expected error for non-positive cutoff")
+	}
+	if _, err := ClusterEnsemble([]*parser.Protein{helix, buildIdealHelix(6)}, 1.0); err == nil {
+		t.Error("Expected error for length mismatch")
+	}
+}
+
+// randomRigidMotion applies a random rotation and translation to all atoms
+func randomRigidMotion(protein *parser.Protein, rng *rand.Rand) {
+	ax, ay, az := rng.Float64()*2*math.Pi, rng.Float64()*2*math.Pi, rng.Float64()*2*math.Pi
+	tx, ty, tz := rng.NormFloat64()*10, rng.NormFloat64()*10, rng.NormFloat64()*10
+
+	for _, atom := range protein.Atoms {
+		x, y, z := atom.X, atom.Y, atom.Z
+		// Rotate about X, then Y, then Z
+		y, z = y*math.Cos(ax)-z*math.Sin(ax), y*math.Sin(ax)+z*math.Cos(ax)
+		x, z = x*math.Cos(ay)+z*math.Sin(ay), -x*math.Sin(ay)+z*math.Cos(ay)
+		x, y = x*math.Cos(az)-y*math.Sin(az), x*math.Sin(az)+y*math.Cos(az)
+		atom.X, atom.Y, atom.Z = x+tx, y+ty, z+tz
+	}
+}
@@ -0,0 +1,96 @@
+package sampling
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
+)
+
+// TestResidueConfidence builds an ensemble whose first ten residues barely
+// move and whose last six scatter by Å, each member in its own orientation,
+// and checks the rigid region is the confident one
+func TestResidueConfidence(t *testing.T) {
+	const n, rigid = 16, 10
+	reference := buildIdealBackbone(n, -57, -47)
+	rng := rand.New(rand.NewSource(7))
+
+	ensemble := make([]*parser.Protein, 20)
+	for m := range ensemble {
+		member := cloneProteinDeep(reference)
+		for i, res := range member.Residues {
+			sigma := 0.1
+			if i >= rigid {
+				sigma = 2.5
+			}
+			dx, dy, dz := sigma*rng.NormFloat64(), sigma*rng.NormFloat64(), sigma*rng.NormFloat64()
+			for _, atom := range []*parser.Atom{res.N, res.CA, res.C, res.O} {
+				atom.X += dx
+				atom.Y += dy
+				atom.Z += dz
+			}
+		}
+		// Arbitrary rigid-body placement: superposition must undo it
+		angle := float64(m) * 0.7
+		rotation := [3][3]float64{
+			{math.Cos(angle), -math.Sin(angle), 0},
+			{math.Sin(angle), math.Cos(angle), 0},
+			{0, 0, 1},
+		}
+		validation.ApplyTransform(member, rotation, [3]float64{5 * float64(m), -3, 12})
+		ensemble[m] = member
+	}
+
+	confidence := ResidueConfidence(reference, ensemble)
+	if len(confidence) != n {
+		t.Fatalf("Got %d confidences, want %d", len(confidence), n)
+	}
+	rigidMean, variableMean := 0.0, 0.0
+	for i, c := range confidence {
+		if c < 0 || c > 100 {
+			t.Errorf("Residue %d confidence %.1f outside [0, 100]", i, c)
+		}
+		if i < rigid {
+			rigidMean += c / rigid
+		} else {
+			variableMean += c / (n - rigid)
+		}
+	}
+	t.Logf("Mean confidence: rigid %.1f, variable %.1f", rigidMean, variableMean)
+
+	if rigidMean < 70 {
+		t.Errorf("Rigid region confidence %.1f, expected above 70", rigidMean)
+	}
+	if variableMean > 40 {
+		t.Errorf("Variable region confidence %.1f, expected below 40", variableMean)
+	}
+	for i := 0; i < rigid; i++ {
+		for j := rigid; j < n; j++ {
+			if confidence[i] <= confidence[j] {
+				t.Fatalf("Rigid residue %d (%.1f) not above variable residue %d (%.1f)", i, confidence[i], j, confidence[j])
+			}
+		}
+	}
+
+	// An ensemble of copies is fully confident; no ensemble, no confidence
+	for i, c := range ResidueConfidence(reference, []*parser.Protein{cloneProteinDeep(reference)}) {
+		if math.Abs(c-100) > 1e-6 {
+			t.Errorf("Residue %d confidence %.3f against itself, want 100", i, c)
+		}
+	}
+	for i, c := range ResidueConfidence(reference, nil) {
+		if c != 0 {
+			t.Errorf("Residue %d confidence %.1f with no ensemble, want 0", i, c)
+		}
+	}
+
+	// Written into the B-factor column of every atom of the residue
+	parser.SetResidueTempFactors(reference, confidence)
+	for _, atom := range reference.Atoms {
+		if want := confidence[atom.ResSeq-1]; atom.TempFacto != want {
+			t.Fatalf("Atom %s %d B-factor %.2f, want %.2f", atom.Name, atom.ResSeq, atom.TempFacto, want)
+		}
+	}
+}
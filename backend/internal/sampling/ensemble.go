@@ -118,8 +118,9 @@ func RankAndSelectDiverse(ensemble []*EnsembleStructure, nativeProtein *parser.P
 		_, _ = validation.CalculateRMSD(es.Protein, nativeProtein)
 	}
 
-	// Step 2: Sort by energy (lower is better)
-	sort.Slice(ensemble, func(i, j int) bool {
+	// Step 2: Sort by energy (lower is better); stable, so equal energies
+	// keep their sampling order
+	sort.SliceStable(ensemble, func(i, j int) bool {
 		return ensemble[i].Energy < ensemble[j].Energy
 	})
 
@@ -0,0 +1,130 @@
+// Package sampling - Ensemble diversity and effective size
+//
+// "100+ structures" means little if most of them are the same fold a few
+// tenths of an Ångström apart. EnsembleDiversity measures what an ensemble
+// actually covers: the mean pairwise CA-RMSD and the number of distinct
+// conformations (GROMOS clusters at 2 Å, see ClusterEnsemble).
+// GenerateDiverseEnsemble keeps sampling until that number reaches a target.
+//
+// BIOCHEMIST: 2 Å CA-RMSD separates different folds or topologies of a
+// small protein, not thermal fluctuations of one fold
+// MATHEMATICIAN: Effective size = number of clusters; it can only grow as
+// structures are added
+package sampling
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
+)
+
+// DiversityRMSDCutoff is the CA-RMSD (Å) within which two structures count
+// as the same conformation for the effective ensemble size
+const DiversityRMSDCutoff = 2.0
+
+// EnsembleDiversity returns the mean pairwise superposed CA-RMSD (Å) of
+// structures and their effective size, the number of GROMOS clusters at
+// DiversityRMSDCutoff
+//
+// Pairs that cannot be superposed (different CA counts) count as distinct
+// and are left out of the mean. An empty ensemble has size 0, a single
+// structure size 1; both have mean RMSD 0.
+func EnsembleDiversity(structures []*parser.Protein) (meanPairwiseRMSD float64, effectiveSize int) {
+	tracker := &diversityTracker{}
+	for _, s := range structures {
+		tracker.add(s)
+	}
+	return tracker.meanRMSD(), tracker.effectiveSize()
+}
+
+// DiverseEnsembleConfig controls GenerateDiverseEnsemble
+type DiverseEnsembleConfig struct {
+	TargetEffectiveSize int // Stop once this many distinct conformations exist
+	MaxAttempts         int // Upper bound on calls to the generator
+}
+
+// DefaultDiverseEnsembleConfig asks for 20 distinct conformations within
+// 50 generator calls
+func DefaultDiverseEnsembleConfig() DiverseEnsembleConfig {
+	return DiverseEnsembleConfig{
+		TargetEffectiveSize: 20,
+		MaxAttempts:         50,
+	}
+}
+
+// GenerateDiverseEnsemble calls generate (with attempt = 0, 1, ...) and
+// collects its structures until their effective size reaches
+// config.TargetEffectiveSize or config.MaxAttempts calls have been made
+//
+// generate may return any number of structures per call, e.g. one sampler
+// run with a new seed. Returns every structure generated and the final
+// effective size; falling short of the target is not an error, but a
+// generator error is returned together with the structures collected so far.
+func GenerateDiverseEnsemble(generate func(attempt int) ([]*parser.Protein, error), config DiverseEnsembleConfig) ([]*parser.Protein, int, error) {
+	if generate == nil {
+		return nil, 0, fmt.Errorf("nil generator")
+	}
+	if config.TargetEffectiveSize <= 0 {
+		return nil, 0, fmt.Errorf("target effective size must be positive, got %d", config.TargetEffectiveSize)
+	}
+
+	tracker := &diversityTracker{}
+	for attempt := 0; attempt < config.MaxAttempts; attempt++ {
+		batch, err := generate(attempt)
+		if err != nil {
+			return tracker.structures, tracker.effectiveSize(), fmt.Errorf("attempt %d: %w", attempt, err)
+		}
+		for _, s := range batch {
+			if s != nil {
+				tracker.add(s)
+			}
+		}
+		if tracker.effectiveSize() >= config.TargetEffectiveSize {
+			break
+		}
+	}
+
+	return tracker.structures, tracker.effectiveSize(), nil
+}
+
+// diversityTracker keeps the pairwise RMSD matrix of a growing ensemble,
+// so adding a structure costs one row of superpositions
+type diversityTracker struct {
+	structures []*parser.Protein
+	rmsd       [][]float64 // Pairs that cannot be superposed hold +Inf
+	sum        float64     // Sum of finite pairwise RMSDs
+	pairs      int         // Number of finite pairs
+}
+
+// add appends s and its RMSDs to every earlier structure
+func (d *diversityTracker) add(s *parser.Protein) {
+	row := make([]float64, len(d.structures)+1)
+	for i, other := range d.structures {
+		r, err := validation.CalculateSuperposedRMSD(other, s)
+		if err != nil {
+			r = math.Inf(1)
+		} else {
+			d.sum += r
+			d.pairs++
+		}
+		row[i] = r
+		d.rmsd[i] = append(d.rmsd[i], r)
+	}
+	d.rmsd = append(d.rmsd, row)
+	d.structures = append(d.structures, s)
+}
+
+// meanRMSD returns the mean finite pairwise RMSD
+func (d *diversityTracker) meanRMSD() float64 {
+	if d.pairs == 0 {
+		return 0
+	}
+	return d.sum / float64(d.pairs)
+}
+
+// effectiveSize returns the number of clusters at DiversityRMSDCutoff
+func (d *diversityTracker) effectiveSize() int {
+	return len(gromosClusters(d.rmsd, DiversityRMSDCutoff))
+}
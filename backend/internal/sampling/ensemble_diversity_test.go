@@ -0,0 +1,58 @@
+package sampling
+
+import (
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestEnsembleDiversity checks duplicates count once and that
+// GenerateDiverseEnsemble stops at its target or its attempt budget
+func TestEnsembleDiversity(t *testing.T) {
+	helix := buildIdealBackbone(12, -60, -45)
+	duplicates := []*parser.Protein{helix, helix.Copy(), helix.Copy(), helix.Copy(), helix.Copy()}
+
+	mean, size := EnsembleDiversity(duplicates)
+	t.Logf("5 copies: mean RMSD %.2e Å, effective size %d", mean, size)
+	if size != 1 {
+		t.Errorf("Effective size of identical structures = %d, want 1", size)
+	}
+	if mean > 1e-6 {
+		t.Errorf("Mean pairwise RMSD of identical structures = %.2e Å, want 0", mean)
+	}
+
+	conformers := []*parser.Protein{
+		helix,
+		buildIdealBackbone(12, -120, 130), // β-strand
+		buildIdealBackbone(12, 60, 45),    // Left-handed helix
+	}
+	mean, size = EnsembleDiversity(append(conformers, duplicates...))
+	t.Logf("3 conformers + 5 copies: mean RMSD %.2f Å, effective size %d", mean, size)
+	if size != 3 || mean <= DiversityRMSDCutoff {
+		t.Errorf("Effective size %d (mean %.2f Å), want 3 distinct conformers", size, mean)
+	}
+
+	// A redundant generator exhausts the budget; a varied one stops early
+	calls := 0
+	redundant := func(attempt int) ([]*parser.Protein, error) {
+		calls++
+		return []*parser.Protein{helix.Copy()}, nil
+	}
+	config := DiverseEnsembleConfig{TargetEffectiveSize: 3, MaxAttempts: 10}
+	ensemble, size, err := GenerateDiverseEnsemble(redundant, config)
+	if err != nil || calls != 10 || size != 1 || len(ensemble) != 10 {
+		t.Errorf("Redundant generator: %d calls, %d structures, size %d, err %v; want 10, 10, 1, nil",
+			calls, len(ensemble), size, err)
+	}
+
+	calls = 0
+	varied := func(attempt int) ([]*parser.Protein, error) {
+		calls++
+		return []*parser.Protein{conformers[attempt%len(conformers)].Copy()}, nil
+	}
+	ensemble, size, err = GenerateDiverseEnsemble(varied, config)
+	if err != nil || calls != 3 || size != 3 || len(ensemble) != 3 {
+		t.Errorf("Varied generator: %d calls, %d structures, size %d, err %v; want 3, 3, 3, nil",
+			calls, len(ensemble), size, err)
+	}
+}
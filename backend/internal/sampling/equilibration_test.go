@@ -0,0 +1,115 @@
+package sampling
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+)
+
+// ar1Series returns n samples of a unit-variance AR(1) process with
+// ρ(t) = e^(-t/tau), preceded by burnIn samples relaxing linearly from
+// offset to 0
+func ar1Series(n, burnIn int, tau, offset float64, rng *rand.Rand) []float64 {
+	phi := math.Exp(-1 / tau)
+	noise := math.Sqrt(1 - phi*phi)
+	series := make([]float64, burnIn+n)
+	x := rng.NormFloat64()
+	for i := range series {
+		x = phi*x + noise*rng.NormFloat64()
+		series[i] = x
+		if i < burnIn {
+			series[i] += offset * (1 - float64(i)/float64(burnIn))
+		}
+	}
+	return series
+}
+
+// TestIntegratedAutocorrelationTime recovers the injected correlation
+// length of AR(1) series: τ_int = coth(1/2τ) ≈ 2τ
+func TestIntegratedAutocorrelationTime(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for _, tau := range []float64{1, 5, 20} {
+		series := ar1Series(100000, 0, tau, 0, rng)
+		want := 1 / math.Tanh(1/(2*tau))
+		got := IntegratedAutocorrelationTime(series)
+		t.Logf("τ = %.0f: τ_int %.2f, expected %.2f", tau, got, want)
+		if math.Abs(got-want) > 0.15*want {
+			t.Errorf("τ = %.0f: τ_int %.2f, want %.2f ± 15%%", tau, got, want)
+		}
+	}
+
+	// White noise and constants are uncorrelated
+	white := make([]float64, 10000)
+	for i := range white {
+		white[i] = rng.NormFloat64()
+	}
+	if got := IntegratedAutocorrelationTime(white); math.Abs(got-1) > 0.15 {
+		t.Errorf("White noise τ_int %.2f, want 1", got)
+	}
+	if got := IntegratedAutocorrelationTime(make([]float64, 100)); got != 1 {
+		t.Errorf("Constant series τ_int %.2f, want 1", got)
+	}
+}
+
+// TestDetectEquilibration checks MSER cuts a linear transient and the
+// effective sample count follows from τ_int
+func TestDetectEquilibration(t *testing.T) {
+	const burnIn, n, tau = 2000, 100000, 10.0
+	series := ar1Series(n, burnIn, tau, 20, rand.New(rand.NewSource(3)))
+
+	stats := DetectEquilibration(series)
+	want := 1 / math.Tanh(1/(2*tau))
+	t.Logf("Equilibrated at %d (transient ends at %d), τ_int %.2f (expected %.2f), N_eff %.0f",
+		stats.Step, burnIn, stats.AutocorrelationTime, want, stats.EffectiveSamples)
+
+	if stats.Step < burnIn/2 || stats.Step > 3*burnIn/2 {
+		t.Errorf("Equilibration step %d, want near %d", stats.Step, burnIn)
+	}
+	if math.Abs(stats.AutocorrelationTime-want) > 0.15*want {
+		t.Errorf("τ_int %.2f after burn-in, want %.2f ± 15%%", stats.AutocorrelationTime, want)
+	}
+	if wantEff := float64(len(series)-stats.Step) / stats.AutocorrelationTime; math.Abs(stats.EffectiveSamples-wantEff) > 1e-9 {
+		t.Errorf("N_eff %.2f, want %.2f", stats.EffectiveSamples, wantEff)
+	}
+
+	// A stationary series needs no burn-in
+	if stationary := DetectEquilibration(ar1Series(n, 0, tau, 0, rand.New(rand.NewSource(5)))); stationary.Step > burnIn/4 {
+		t.Errorf("Stationary series equilibrated at %d, want near 0", stationary.Step)
+	}
+}
+
+// TestMonteCarloEquilibration checks MonteCarloVedic reports equilibration
+// statistics within the run
+func TestMonteCarloEquilibration(t *testing.T) {
+	angles := make([]geometry.RamachandranAngles, 8)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60 * math.Pi / 180, Psi: -45 * math.Pi / 180}
+	}
+	protein, err := geometry.BuildProteinFromAngles("AAAAAAAA", angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 300
+	config.MoveType = "dihedral"
+	result, err := MonteCarloVedic(protein, config)
+	if err != nil {
+		t.Fatalf("MonteCarloVedic failed: %v", err)
+	}
+	steps := result.NumAccepted + result.NumRejected
+	t.Logf("%d steps: equilibrated at %d, τ_int %.1f, N_eff %.1f",
+		steps, result.EquilibrationStep, result.AutocorrelationTime, result.EffectiveSamples)
+
+	if result.EquilibrationStep < 0 || result.EquilibrationStep > steps/2 {
+		t.Errorf("EquilibrationStep %d outside [0, %d]", result.EquilibrationStep, steps/2)
+	}
+	if result.AutocorrelationTime < 1 {
+		t.Errorf("AutocorrelationTime %.2f, want ≥ 1", result.AutocorrelationTime)
+	}
+	if result.EffectiveSamples <= 0 || result.EffectiveSamples > float64(steps-result.EquilibrationStep) {
+		t.Errorf("EffectiveSamples %.1f outside (0, %d]", result.EffectiveSamples, steps-result.EquilibrationStep)
+	}
+}
@@ -21,9 +21,13 @@
 package sampling
 
 import (
+	"context"
 	"fmt"
 	"math"
-	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
@@ -64,11 +68,15 @@ type FragmentAssemblyConfig struct {
 	UseThreeMers bool
 	UseNineMers  bool
 
-	// Number of fragment insertion attempts per position
+	// Candidate fragments scored per position: the top NumInsertions of the
+	// Vedic-ranked library (0 = the whole library). Scoring every fragment
+	// costs O(positions × library size), which is large for libraries
+	// loaded from a PDB directory; a cap trades sequence matching for speed.
 	NumInsertions int
 
 	// Vedic bias weight [0, 1]
 	// 0 = ignore Vedic score, 1 = only use Vedic score
+	// The remaining (1 - VedicWeight) goes to BLOSUM62 sequence similarity
 	VedicWeight float64
 
 	// Random seed for reproducibility
@@ -80,7 +88,7 @@ func DefaultFragmentAssemblyConfig() FragmentAssemblyConfig {
 	return FragmentAssemblyConfig{
 		UseThreeMers:  true,
 		UseNineMers:   true,
-		NumInsertions: 5,    // Try 5 fragments per position
+		NumInsertions: 0,    // Score the whole library
 		VedicWeight:   0.3,  // 30% Vedic influence
 		Seed:          42,
 	}
@@ -95,8 +103,8 @@ func DefaultFragmentAssemblyConfig() FragmentAssemblyConfig {
 // - Left-handed helix: φ = +60°, ψ = +45° (rare, mainly Gly)
 // - Turn/loop: Various conformations
 //
-// For production, this would be populated from PDB database (top8000 structures)
-// or Robetta fragment server. For v0.2, using ideal fragments is sufficient.
+// For native fragments extracted from PDB structures, use LoadFragmentLibrary.
+// This idealized library remains the fallback when no structures are available.
 func NewFragmentLibrary() *FragmentLibrary {
 	lib := &FragmentLibrary{
 		ThreeMers: make([]Fragment, 0),
@@ -115,6 +123,119 @@ func NewFragmentLibrary() *FragmentLibrary {
 	return lib
 }
 
+// LoadFragmentLibrary builds a fragment library from experimental structures
+//
+// ALGORITHM:
+// 1. Parse every .pdb file in pdbDir
+// 2. Compute (φ, ψ) for each residue
+// 3. Slide a window of each requested length along every chain
+// 4. Keep windows where all angles are defined (no termini, no chain breaks)
+//
+// BIOCHEMIST:
+// Native fragments capture real local structure (capped helices, β-bulges,
+// turn motifs) that ideal φ/ψ values cannot. Each fragment keeps its source
+// sequence so insertion can prefer fragments from similar sequences.
+//
+// Supported sizes: 3 and 9. If a size yields no fragments (empty directory,
+// structures too short), the idealized fragments are used as fallback.
+func LoadFragmentLibrary(pdbDir string, sizes []int) (*FragmentLibrary, error) {
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("no fragment sizes requested")
+	}
+	for _, size := range sizes {
+		if size != 3 && size != 9 {
+			return nil, fmt.Errorf("unsupported fragment size %d (supported: 3, 9)", size)
+		}
+	}
+
+	entries, err := os.ReadDir(pdbDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fragment directory: %w", err)
+	}
+
+	// Sort for reproducible fragment ordering
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".pdb") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	lib := &FragmentLibrary{
+		ThreeMers: make([]Fragment, 0),
+		NineMers:  make([]Fragment, 0),
+	}
+
+	for _, name := range names {
+		protein, err := parser.ParsePDB(filepath.Join(pdbDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		angles := geometry.CalculateRamachandran(protein)
+		sequence := protein.Sequence()
+		source := strings.TrimSuffix(name, filepath.Ext(name))
+
+		for _, size := range sizes {
+			frags := extractFragments(protein, angles, sequence, size, source)
+			if size == 3 {
+				lib.ThreeMers = append(lib.ThreeMers, frags...)
+			} else {
+				lib.NineMers = append(lib.NineMers, frags...)
+			}
+		}
+	}
+
+	// Fall back to idealized fragments where no native ones were found
+	if len(lib.ThreeMers) == 0 || len(lib.NineMers) == 0 {
+		ideal := NewFragmentLibrary()
+		if len(lib.ThreeMers) == 0 {
+			lib.ThreeMers = ideal.ThreeMers
+		}
+		if len(lib.NineMers) == 0 {
+			lib.NineMers = ideal.NineMers
+		}
+	}
+
+	lib.rankByVedicScore()
+
+	return lib, nil
+}
+
+// extractFragments slides a window of the given length along the structure
+// and returns every window with fully defined (φ, ψ) within a single chain
+func extractFragments(protein *parser.Protein, angles []geometry.RamachandranAngles, sequence string, length int, source string) []Fragment {
+	frags := make([]Fragment, 0)
+
+	for start := 0; start+length <= len(angles); start++ {
+		valid := true
+		chain := protein.Residues[start].ChainID
+		for i := start; i < start+length; i++ {
+			if math.IsNaN(angles[i].Phi) || math.IsNaN(angles[i].Psi) || protein.Residues[i].ChainID != chain {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		window := make([]geometry.RamachandranAngles, length)
+		copy(window, angles[start:start+length])
+
+		frags = append(frags, Fragment{
+			Length:   length,
+			Angles:   window,
+			Source:   fmt.Sprintf("PDB_%s_%d", source, protein.Residues[start].SeqNum),
+			Sequence: sequence[start : start+length],
+		})
+	}
+
+	return frags
+}
+
 // addIdealAlphaHelix adds perfect alpha helix fragments
 //
 // BIOCHEMIST:
@@ -368,15 +489,9 @@ func calculateFragmentVedicScore(angles []geometry.RamachandranAngles) float64 {
 
 // sortFragmentsByVedic sorts fragments by Vedic score (descending)
 func sortFragmentsByVedic(fragments []Fragment) {
-	// Simple bubble sort (fragment count is small)
-	n := len(fragments)
-	for i := 0; i < n-1; i++ {
-		for j := 0; j < n-i-1; j++ {
-			if fragments[j].VedicScore < fragments[j+1].VedicScore {
-				fragments[j], fragments[j+1] = fragments[j+1], fragments[j]
-			}
-		}
-	}
+	sort.SliceStable(fragments, func(i, j int) bool {
+		return fragments[i].VedicScore > fragments[j].VedicScore
+	})
 }
 
 // FragmentAssembly builds protein structure via fragment insertion
@@ -400,8 +515,6 @@ func FragmentAssembly(sequence string, library *FragmentLibrary, config Fragment
 		return nil, fmt.Errorf("fragment library is nil")
 	}
 
-	rand.Seed(config.Seed)
-
 	// Start with extended chain
 	angles := make([]geometry.RamachandranAngles, len(sequence))
 	for i := range angles {
@@ -415,14 +528,14 @@ func FragmentAssembly(sequence string, library *FragmentLibrary, config Fragment
 	// Insert 9-mers first (larger context)
 	if config.UseNineMers && len(sequence) >= 9 {
 		for pos := 0; pos <= len(sequence)-9; pos++ {
-			insertBestFragment(angles, pos, library.VedicRankedNine, config)
+			insertBestFragment(angles, pos, sequence, library.VedicRankedNine, config)
 		}
 	}
 
 	// Insert 3-mers (refine local structure)
 	if config.UseThreeMers && len(sequence) >= 3 {
 		for pos := 0; pos <= len(sequence)-3; pos++ {
-			insertBestFragment(angles, pos, library.VedicRankedThree, config)
+			insertBestFragment(angles, pos, sequence, library.VedicRankedThree, config)
 		}
 	}
 
@@ -450,31 +563,35 @@ func FragmentAssembly(sequence string, library *FragmentLibrary, config Fragment
 }
 
 // insertBestFragment inserts best-scoring fragment at position
-func insertBestFragment(angles []geometry.RamachandranAngles, pos int, fragments []Fragment, config FragmentAssemblyConfig) {
+//
+// BIOCHEMIST:
+// Fragments are ranked by how well their source sequence matches the target
+// window (BLOSUM62), blended with the Vedic harmonic score. Native-derived
+// fragments carry the local (φ, ψ) preferences of similar sequences.
+//
+// Score = (1 - VedicWeight) × sequence similarity + VedicWeight × Vedic score
+//
+// fragments is Vedic-ranked; only its first config.NumInsertions are
+// scored when that is positive.
+func insertBestFragment(angles []geometry.RamachandranAngles, pos int, sequence string, fragments []Fragment, config FragmentAssemblyConfig) {
+	if config.NumInsertions > 0 && len(fragments) > config.NumInsertions {
+		fragments = fragments[:config.NumInsertions]
+	}
 	if len(fragments) == 0 {
 		return
 	}
 
-	// Try top N fragments
-	numTries := min(config.NumInsertions, len(fragments))
-
 	bestScore := math.Inf(-1)
 	var bestAngles []geometry.RamachandranAngles
 
-	for i := 0; i < numTries; i++ {
-		frag := fragments[i]
-
+	for _, frag := range fragments {
 		// Check if fragment fits
-		if pos+frag.Length > len(angles) {
+		if pos+frag.Length > len(angles) || pos+frag.Length > len(sequence) {
 			continue
 		}
 
-		// Calculate score for inserting this fragment
-		// Score = Vedic score of fragment
-		score := frag.VedicScore * config.VedicWeight
-
-		// Add local scoring (could be sequence compatibility, clash checking, etc.)
-		// For v0.2, just use Vedic score
+		similarity := fragmentSequenceSimilarity(sequence[pos:pos+frag.Length], frag.Sequence)
+		score := (1.0-config.VedicWeight)*similarity + config.VedicWeight*frag.VedicScore
 
 		if score > bestScore {
 			bestScore = score
@@ -492,6 +609,73 @@ func insertBestFragment(angles []geometry.RamachandranAngles, pos int, fragments
 	}
 }
 
+// fragmentSequenceSimilarity scores a target window against a fragment's
+// source sequence using BLOSUM62, normalized to [0, 1]
+//
+// MATHEMATICIAN:
+// Mean BLOSUM62 score over the window mapped linearly from [-4, 11] to [0, 1].
+// Fragments without sequence context score neutral (0.5).
+func fragmentSequenceSimilarity(target, fragment string) float64 {
+	if len(fragment) == 0 || len(target) == 0 {
+		return 0.5
+	}
+
+	n := len(target)
+	if len(fragment) < n {
+		n = len(fragment)
+	}
+
+	total := 0.0
+	for i := 0; i < n; i++ {
+		total += float64(blosum62Score(target[i], fragment[i]))
+	}
+	mean := total / float64(n)
+
+	return (mean + 4.0) / 15.0
+}
+
+// blosum62Order is the residue ordering of the blosum62 matrix
+const blosum62Order = "ARNDCQEGHILKMFPSTWYV"
+
+// blosum62 substitution matrix
+//
+// CITATION:
+// Henikoff, S., & Henikoff, J. G. (1992). "Amino acid substitution matrices from protein blocks."
+// PNAS 89(22): 10915-10919.
+var blosum62 = [20][20]int{
+	{4, -1, -2, -2, 0, -1, -1, 0, -2, -1, -1, -1, -1, -2, -1, 1, 0, -3, -2, 0},
+	{-1, 5, 0, -2, -3, 1, 0, -2, 0, -3, -2, 2, -1, -3, -2, -1, -1, -3, -2, -3},
+	{-2, 0, 6, 1, -3, 0, 0, 0, 1, -3, -3, 0, -2, -3, -2, 1, 0, -4, -2, -3},
+	{-2, -2, 1, 6, -3, 0, 2, -1, -1, -3, -4, -1, -3, -3, -1, 0, -1, -4, -3, -3},
+	{0, -3, -3, -3, 9, -3, -4, -3, -3, -1, -1, -3, -1, -2, -3, -1, -1, -2, -2, -1},
+	{-1, 1, 0, 0, -3, 5, 2, -2, 0, -3, -2, 1, 0, -3, -1, 0, -1, -2, -1, -2},
+	{-1, 0, 0, 2, -4, 2, 5, -2, 0, -3, -3, 1, -2, -3, -1, 0, -1, -3, -2, -2},
+	{0, -2, 0, -1, -3, -2, -2, 6, -2, -4, -4, -2, -3, -3, -2, 0, -2, -2, -3, -3},
+	{-2, 0, 1, -1, -3, 0, 0, -2, 8, -3, -3, -1, -2, -1, -2, -1, -2, -2, 2, -3},
+	{-1, -3, -3, -3, -1, -3, -3, -4, -3, 4, 2, -3, 1, 0, -3, -2, -1, -3, -1, 3},
+	{-1, -2, -3, -4, -1, -2, -3, -4, -3, 2, 4, -2, 2, 0, -3, -2, -1, -2, -1, 1},
+	{-1, 2, 0, -1, -3, 1, 1, -2, -1, -3, -2, 5, -1, -3, -1, 0, -1, -3, -2, -2},
+	{-1, -1, -2, -3, -1, 0, -2, -3, -2, 1, 2, -1, 5, 0, -2, -1, -1, -1, -1, 1},
+	{-2, -3, -3, -3, -2, -3, -3, -3, -1, 0, 0, -3, 0, 6, -4, -2, -2, 1, 3, -1},
+	{-1, -2, -2, -1, -3, -1, -1, -2, -2, -3, -3, -1, -2, -4, 7, -1, -1, -4, -3, -2},
+	{1, -1, 1, 0, -1, 0, 0, 0, -1, -2, -2, 0, -1, -2, -1, 4, 1, -3, -2, -2},
+	{0, -1, 0, -1, -1, -1, -1, -2, -2, -1, -1, -1, -1, -2, -1, 1, 5, -2, -2, 0},
+	{-3, -3, -4, -4, -2, -2, -3, -2, -2, -3, -2, -3, -1, 1, -4, -3, -2, 11, 2, -3},
+	{-2, -2, -2, -3, -2, -1, -2, -3, 2, -1, -1, -2, -1, 3, -3, -2, -2, 2, 7, -1},
+	{0, -3, -3, -3, -1, -2, -2, -3, -3, 3, 1, -2, 1, -1, -2, -2, 0, -3, -1, 4},
+}
+
+// blosum62Score returns the BLOSUM62 substitution score for two one-letter codes
+// Unknown residues (X, B, Z, ...) score -1
+func blosum62Score(a, b byte) int {
+	i := strings.IndexByte(blosum62Order, a)
+	j := strings.IndexByte(blosum62Order, b)
+	if i < 0 || j < 0 {
+		return -1
+	}
+	return blosum62[i][j]
+}
+
 // min returns minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -506,10 +690,21 @@ func min(a, b int) int {
 // Multiple fragment assemblies with different random selections
 // Explores combinatorial space of fragment combinations
 func GenerateFragmentEnsemble(sequence string, library *FragmentLibrary, config FragmentAssemblyConfig, numStructures int) ([]*parser.Protein, error) {
+	return GenerateFragmentEnsembleCtx(context.Background(), sequence, library, config, numStructures)
+}
+
+// GenerateFragmentEnsembleCtx is GenerateFragmentEnsemble with cancellation
+//
+// ctx is checked before every assembly. On cancellation the structures
+// assembled so far are returned together with ctx.Err().
+func GenerateFragmentEnsembleCtx(ctx context.Context, sequence string, library *FragmentLibrary, config FragmentAssemblyConfig, numStructures int) ([]*parser.Protein, error) {
 	ensemble := make([]*parser.Protein, 0, numStructures)
 	baseSeed := config.Seed
 
 	for i := 0; i < numStructures; i++ {
+		if err := ctx.Err(); err != nil {
+			return ensemble, err
+		}
 		config.Seed = baseSeed + int64(i)
 
 		protein, err := FragmentAssembly(sequence, library, config)
@@ -0,0 +1,146 @@
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestLoadFragmentLibrary builds a library from PDB files and checks that
+// fragment counts scale with the total number of residues
+func TestLoadFragmentLibrary(t *testing.T) {
+	dir := t.TempDir()
+
+	lengths := []int{12, 20}
+	sequences := []string{"ACDEFGHIKLMN", "PQRSTVWYACDEFGHIKLMN"}
+	for i, seq := range sequences {
+		writeFragmentTestPDB(t, filepath.Join(dir, fmt.Sprintf("frag%d.pdb", i)), seq)
+	}
+
+	lib, err := LoadFragmentLibrary(dir, []int{3, 9})
+	if err != nil {
+		t.Fatalf("LoadFragmentLibrary failed: %v", err)
+	}
+
+	// Terminal residues have undefined φ or ψ, so each chain of n residues
+	// yields n - k - 1 windows of length k
+	expected3, expected9 := 0, 0
+	for _, n := range lengths {
+		expected3 += n - 3 - 1
+		expected9 += n - 9 - 1
+	}
+
+	if len(lib.ThreeMers) != expected3 {
+		t.Errorf("3-mers: got %d, want %d", len(lib.ThreeMers), expected3)
+	}
+	if len(lib.NineMers) != expected9 {
+		t.Errorf("9-mers: got %d, want %d", len(lib.NineMers), expected9)
+	}
+
+	for _, frag := range lib.NineMers {
+		if len(frag.Sequence) != 9 || len(frag.Angles) != 9 {
+			t.Fatalf("Malformed 9-mer %s: seq=%q, angles=%d", frag.Source, frag.Sequence, len(frag.Angles))
+		}
+		if strings.ContainsRune(frag.Sequence, 'X') {
+			t.Errorf("Fragment %s lost sequence context: %q", frag.Source, frag.Sequence)
+		}
+	}
+
+	t.Logf("Loaded %d 3-mers and %d 9-mers from %d residues",
+		len(lib.ThreeMers), len(lib.NineMers), lengths[0]+lengths[1])
+}
+
+// TestLoadFragmentLibraryFallback verifies idealized fragments are used when
+// the directory has no structures
+func TestLoadFragmentLibraryFallback(t *testing.T) {
+	lib, err := LoadFragmentLibrary(t.TempDir(), []int{3, 9})
+	if err != nil {
+		t.Fatalf("LoadFragmentLibrary failed: %v", err)
+	}
+
+	ideal := NewFragmentLibrary()
+	if len(lib.ThreeMers) != len(ideal.ThreeMers) || len(lib.NineMers) != len(ideal.NineMers) {
+		t.Errorf("Expected idealized fallback (%d/%d), got %d/%d",
+			len(ideal.ThreeMers), len(ideal.NineMers), len(lib.ThreeMers), len(lib.NineMers))
+	}
+
+	if _, err := LoadFragmentLibrary(t.TempDir(), []int{5}); err == nil {
+		t.Error("Expected error for unsupported fragment size")
+	}
+}
+
+// TestInsertBestFragmentSequenceMatch checks BLOSUM62 selection prefers the
+// fragment whose source sequence matches the target window
+func TestInsertBestFragmentSequenceMatch(t *testing.T) {
+	helix := geometry.RamachandranAngles{Phi: -60.0 * math.Pi / 180.0, Psi: -45.0 * math.Pi / 180.0}
+	sheet := geometry.RamachandranAngles{Phi: -120.0 * math.Pi / 180.0, Psi: 120.0 * math.Pi / 180.0}
+
+	fragments := []Fragment{
+		{Length: 3, Angles: []geometry.RamachandranAngles{helix, helix, helix}, Sequence: "EAK"},
+		{Length: 3, Angles: []geometry.RamachandranAngles{sheet, sheet, sheet}, Sequence: "VIV"},
+	}
+
+	config := DefaultFragmentAssemblyConfig()
+	config.VedicWeight = 0.0
+
+	angles := make([]geometry.RamachandranAngles, 3)
+	insertBestFragment(angles, 0, "VIV", fragments, config)
+
+	if angles[1] != sheet {
+		t.Errorf("Expected sheet fragment for VIV, got φ=%.1f° ψ=%.1f°",
+			angles[1].ToDegressPhi(), angles[1].ToDegressPsi())
+	}
+
+	insertBestFragment(angles, 0, "EAK", fragments, config)
+	if angles[1] != helix {
+		t.Errorf("Expected helix fragment for EAK, got φ=%.1f° ψ=%.1f°",
+			angles[1].ToDegressPhi(), angles[1].ToDegressPsi())
+	}
+
+	// Capped to the first (top Vedic-ranked) fragment, VIV gets the helix
+	config.NumInsertions = 1
+	insertBestFragment(angles, 0, "VIV", fragments, config)
+	if angles[1] != helix {
+		t.Errorf("Expected only the first fragment to be scored with NumInsertions 1, got φ=%.1f° ψ=%.1f°",
+			angles[1].ToDegressPhi(), angles[1].ToDegressPsi())
+	}
+}
+
+// writeFragmentTestPDB writes a helical backbone for sequence as a PDB file
+func writeFragmentTestPDB(t *testing.T, path, sequence string) {
+	t.Helper()
+
+	angles := make([]geometry.RamachandranAngles, len(sequence))
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: -60.0 * math.Pi / 180.0, Psi: -45.0 * math.Pi / 180.0}
+	}
+
+	protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+
+	var sb strings.Builder
+	serial := 1
+	for i, res := range protein.Residues {
+		for _, atom := range []*parser.Atom{res.N, res.CA, res.C, res.O} {
+			if atom == nil {
+				continue
+			}
+			fmt.Fprintf(&sb, "ATOM  %5d  %-3s %3s A%4d    %8.3f%8.3f%8.3f  1.00  0.00          %2s\n",
+				serial, atom.Name, parser.OneToThree(sequence[i]), i+1, atom.X, atom.Y, atom.Z, atom.Name[:1])
+			serial++
+		}
+	}
+	sb.WriteString("END\n")
+
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		t.Fatalf("Failed to write test PDB: %v", err)
+	}
+}
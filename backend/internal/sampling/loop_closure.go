@@ -0,0 +1,255 @@
+// Package sampling - Loop Closure via Cyclic Coordinate Descent
+//
+// Fragment insertion and basin sampling change (φ, ψ) locally, which can leave
+// a gap where a loop should reconnect to the rest of the chain. CCD closes the
+// gap by adjusting one dihedral at a time so the loop end lands on its anchor.
+//
+// BIOCHEMIST: Only loop dihedrals move, and each step must keep (φ, ψ) in an
+// allowed Ramachandran region
+// PHYSICIST: Rigid-body rotations about N-Cα (φ) and Cα-C (ψ) bonds preserve
+// bond lengths and angles exactly
+// MATHEMATICIAN: Each CCD step has a closed-form optimal rotation angle
+// ETHICIST: Reports failure honestly when the gap cannot be closed
+//
+// CITATION:
+// Canutescu, A. A., & Dunbrack, R. L. (2003). "Cyclic coordinate descent: A robotics algorithm
+// for protein loop closure." Protein Science 12(5): 963-972.
+package sampling
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+const (
+	// ccdGapTolerance is the closure criterion (Å)
+	ccdGapTolerance = 0.1
+
+	// ccdMaxIterations bounds the number of full sweeps over the loop
+	ccdMaxIterations = 500
+
+	// ccdPeptideBond is the C-N peptide bond length (Å)
+	ccdPeptideBond = 1.329
+)
+
+// CloseLoop closes the loop spanning residues startRes..endRes (0-based,
+// inclusive) with cyclic coordinate descent
+//
+// The moving anchor is the peptide N that follows C(endRes), placed in the
+// plane of Cα-C-O at the ideal Cα-C-N angle. CCD rotates φ and ψ of each
+// loop residue in turn so the anchor reaches targetN (normally the N of
+// residue endRes+1, which is held fixed along with everything outside the loop).
+//
+// ALGORITHM:
+// For each sweep, for each rotatable bond in the loop:
+//  1. Project anchor and target onto the plane perpendicular to the bond
+//  2. Rotate by θ = atan2(û·(r × f), r·f), the angle that minimizes the gap
+//  3. Reject (or halve) the step if it leaves the allowed Ramachandran region
+//
+// Converges when the gap is below 0.1 Å.
+func CloseLoop(protein *parser.Protein, startRes, endRes int, targetN [3]float64) error {
+	if protein == nil {
+		return fmt.Errorf("protein is nil")
+	}
+	if startRes < 0 || endRes >= len(protein.Residues) || startRes > endRes {
+		return fmt.Errorf("invalid loop range [%d, %d] for %d residues", startRes, endRes, len(protein.Residues))
+	}
+	for i := startRes; i <= endRes; i++ {
+		res := protein.Residues[i]
+		if res == nil || res.N == nil || res.CA == nil || res.C == nil || res.O == nil {
+			return fmt.Errorf("residue %d has incomplete backbone", i)
+		}
+	}
+
+	target := geometry.Vector3{X: targetN[0], Y: targetN[1], Z: targetN[2]}
+	anchor := virtualNextN(protein.Residues[endRes])
+	loopAtoms := collectLoopAtoms(protein, startRes, endRes)
+
+	gap := anchor.Sub(target).Magnitude()
+	for iter := 0; iter < ccdMaxIterations && gap >= ccdGapTolerance; iter++ {
+		for i := startRes; i <= endRes; i++ {
+			res := protein.Residues[i]
+
+			// φ: rotate about N → Cα
+			ccdRotate(protein, loopAtoms, i, endRes, atomVec(res.N), atomVec(res.CA), true, &anchor, target)
+
+			// ψ: rotate about Cα → C
+			ccdRotate(protein, loopAtoms, i, endRes, atomVec(res.CA), atomVec(res.C), false, &anchor, target)
+		}
+
+		gap = anchor.Sub(target).Magnitude()
+	}
+
+	if gap >= ccdGapTolerance {
+		return fmt.Errorf("loop closure did not converge: gap %.3f Å after %d iterations", gap, ccdMaxIterations)
+	}
+
+	return nil
+}
+
+// ccdRotate applies one CCD step about the bond axisStart → axisEnd of residue i
+func ccdRotate(protein *parser.Protein, loopAtoms [][]*parser.Atom, i, endRes int, axisStart, axisEnd geometry.Vector3, isPhi bool, anchor *geometry.Vector3, target geometry.Vector3) {
+	axis := axisEnd.Sub(axisStart).Normalize()
+
+	r := anchor.Sub(axisEnd)
+	f := target.Sub(axisEnd)
+	r = r.Sub(axis.Scale(r.Dot(axis)))
+	f = f.Sub(axis.Scale(f.Dot(axis)))
+	if r.Magnitude() < 1e-6 || f.Magnitude() < 1e-6 {
+		return
+	}
+
+	theta := math.Atan2(axis.Dot(r.Cross(f)), r.Dot(f))
+	moving := movingAtoms(loopAtoms, i, isPhi)
+
+	// Try the optimal step, then smaller ones, keeping (φ, ψ) allowed
+	for attempt := 0; attempt < 3; attempt++ {
+		rotateAtoms(moving, axisEnd, axis, theta)
+		newAnchor := rotatePoint(*anchor, axisEnd, axis, theta)
+
+		if loopResidueAllowed(protein, i, endRes, newAnchor) {
+			*anchor = newAnchor
+			return
+		}
+
+		rotateAtoms(moving, axisEnd, axis, -theta)
+		theta /= 2.0
+	}
+}
+
+// collectLoopAtoms gathers all atoms (backbone and hydrogens) of each residue
+// up to endRes, indexed by residue position; residues before startRes stay empty
+func collectLoopAtoms(protein *parser.Protein, startRes, endRes int) [][]*parser.Atom {
+	loopAtoms := make([][]*parser.Atom, endRes+1)
+	seen := make(map[*parser.Atom]bool)
+	index := make(map[string]int)
+
+	for i := startRes; i <= endRes; i++ {
+		res := protein.Residues[i]
+		index[fmt.Sprintf("%s:%d", res.ChainID, res.SeqNum)] = i
+		for _, atom := range []*parser.Atom{res.N, res.CA, res.C, res.O} {
+			loopAtoms[i] = append(loopAtoms[i], atom)
+			seen[atom] = true
+		}
+	}
+
+	for _, atom := range protein.Atoms {
+		if atom == nil || seen[atom] {
+			continue
+		}
+		if i, ok := index[fmt.Sprintf("%s:%d", atom.ChainID, atom.ResSeq)]; ok {
+			loopAtoms[i] = append(loopAtoms[i], atom)
+			seen[atom] = true
+		}
+	}
+
+	return loopAtoms
+}
+
+// movingAtoms returns the atoms displaced by rotating φ or ψ of residue i
+//
+// φ (N-Cα) moves everything in residue i except N, H and Cα.
+// ψ (Cα-C) moves only the carbonyl O (and OXT) of residue i.
+// Both move every atom of the downstream loop residues.
+func movingAtoms(loopAtoms [][]*parser.Atom, i int, isPhi bool) []*parser.Atom {
+	moving := make([]*parser.Atom, 0)
+
+	for _, atom := range loopAtoms[i] {
+		if isPhi {
+			if atom.Name != "N" && atom.Name != "H" && atom.Name != "HN" && atom.Name != "CA" {
+				moving = append(moving, atom)
+			}
+		} else if atom.Name == "O" || atom.Name == "OXT" {
+			moving = append(moving, atom)
+		}
+	}
+
+	for j := i + 1; j < len(loopAtoms); j++ {
+		moving = append(moving, loopAtoms[j]...)
+	}
+
+	return moving
+}
+
+// loopResidueAllowed checks residue i (the only one whose φ/ψ a rotation at
+// residue i changes) stays in an allowed Ramachandran region
+func loopResidueAllowed(protein *parser.Protein, i, endRes int, anchor geometry.Vector3) bool {
+	res := protein.Residues[i]
+
+	var nextN geometry.Vector3
+	if i == endRes {
+		nextN = anchor
+	} else {
+		nextN = atomVec(protein.Residues[i+1].N)
+	}
+
+	psi := loopDihedral(atomVec(res.N), atomVec(res.CA), atomVec(res.C), nextN)
+
+	// φ is undefined for the N-terminal residue; only ψ constrains it
+	phi := -90.0 * math.Pi / 180.0
+	if i > 0 && protein.Residues[i-1] != nil && protein.Residues[i-1].C != nil {
+		phi = loopDihedral(atomVec(protein.Residues[i-1].C), atomVec(res.N), atomVec(res.CA), atomVec(res.C))
+	}
+
+	// Glycine tolerates positive φ
+	if res.Name == "GLY" || res.Name == "G" {
+		return true
+	}
+
+	return geometry.RamachandranAngles{Phi: phi, Psi: psi}.IsInAllowedRegion()
+}
+
+// virtualNextN places the N following residue res in the Cα-C-O plane,
+// at the ideal Cα-C-N angle on the side opposite the carbonyl O
+func virtualNextN(res *parser.Residue) geometry.Vector3 {
+	c := atomVec(res.C)
+	u := atomVec(res.CA).Sub(c).Normalize()
+	o := atomVec(res.O).Sub(c)
+	w := o.Sub(u.Scale(o.Dot(u))).Normalize()
+
+	theta := geometry.AngleCA_C_N * math.Pi / 180.0
+	dir := u.Scale(math.Cos(theta)).Sub(w.Scale(math.Sin(theta)))
+	return c.Add(dir.Scale(ccdPeptideBond))
+}
+
+// rotateAtoms rotates atoms by theta about the axis through origin
+func rotateAtoms(atoms []*parser.Atom, origin, axis geometry.Vector3, theta float64) {
+	for _, atom := range atoms {
+		p := rotatePoint(atomVec(atom), origin, axis, theta)
+		atom.X, atom.Y, atom.Z = p.X, p.Y, p.Z
+	}
+}
+
+// rotatePoint rotates p by theta about the unit axis through origin (Rodrigues)
+func rotatePoint(p, origin, axis geometry.Vector3, theta float64) geometry.Vector3 {
+	v := p.Sub(origin)
+	cosT := math.Cos(theta)
+	sinT := math.Sin(theta)
+
+	rotated := v.Scale(cosT).
+		Add(axis.Cross(v).Scale(sinT)).
+		Add(axis.Scale(axis.Dot(v) * (1 - cosT)))
+
+	return origin.Add(rotated)
+}
+
+// loopDihedral computes the signed dihedral p1-p2-p3-p4 (radians)
+func loopDihedral(p1, p2, p3, p4 geometry.Vector3) float64 {
+	b1 := p2.Sub(p1)
+	b2 := p3.Sub(p2)
+	b3 := p4.Sub(p3)
+
+	n1 := b1.Cross(b2)
+	n2 := b2.Cross(b3)
+	m1 := n1.Cross(b2.Normalize())
+
+	return math.Atan2(m1.Dot(n2), n1.Dot(n2))
+}
+
+// atomVec converts an atom position to a vector
+func atomVec(atom *parser.Atom) geometry.Vector3 {
+	return geometry.Vector3{X: atom.X, Y: atom.Y, Z: atom.Z}
+}
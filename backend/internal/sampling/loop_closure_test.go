@@ -0,0 +1,149 @@
+package sampling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestCloseLoop opens a 3-residue loop and checks CCD restores continuity
+func TestCloseLoop(t *testing.T) {
+	protein := buildIdealHelix(10)
+
+	const startRes, endRes = 3, 5
+	next := protein.Residues[endRes+1]
+	target := [3]float64{next.N.X, next.N.Y, next.N.Z}
+
+	if gap := distance3(virtualNextN(protein.Residues[endRes]), atomVec(next.N)); gap > 0.05 {
+		t.Fatalf("Closed helix should have no gap, got %.3f Å", gap)
+	}
+
+	// Open the loop: rotate φ of the first loop residue, moving only loop atoms
+	loopAtoms := collectLoopAtoms(protein, startRes, endRes)
+	res := protein.Residues[startRes]
+	axis := atomVec(res.CA).Sub(atomVec(res.N)).Normalize()
+	rotateAtoms(movingAtoms(loopAtoms, startRes, true), atomVec(res.CA), axis, -35.0*math.Pi/180.0)
+
+	openGap := distance3(atomVec(protein.Residues[endRes].C), atomVec(next.N))
+	if math.Abs(openGap-ccdPeptideBond) < 0.5 {
+		t.Fatalf("Loop was not opened: C-N distance %.3f Å", openGap)
+	}
+
+	if err := CloseLoop(protein, startRes, endRes, target); err != nil {
+		t.Fatalf("CloseLoop failed: %v", err)
+	}
+
+	closedGap := distance3(atomVec(protein.Residues[endRes].C), atomVec(next.N))
+	if math.Abs(closedGap-ccdPeptideBond) > 0.15 {
+		t.Errorf("Peptide bond not restored: C-N distance %.3f Å (want %.3f Å)", closedGap, ccdPeptideBond)
+	}
+
+	// Fixed anchor must not move
+	if next.N.X != target[0] || next.N.Y != target[1] || next.N.Z != target[2] {
+		t.Error("Residue after the loop was moved")
+	}
+
+	// Loop bond lengths are preserved by rigid rotations
+	for i := startRes; i <= endRes; i++ {
+		r := protein.Residues[i]
+		if d := distance3(atomVec(r.N), atomVec(r.CA)); math.Abs(d-geometry.BondN_CA) > 0.05 {
+			t.Errorf("Residue %d N-CA bond distorted: %.3f Å", i, d)
+		}
+	}
+
+	for i := startRes; i <= endRes; i++ {
+		if !loopResidueAllowed(protein, i, endRes, atomVec(protein.Residues[endRes+1].N)) {
+			t.Errorf("Residue %d left the allowed Ramachandran region", i)
+		}
+	}
+
+	t.Logf("C-N gap: opened %.3f Å → closed %.3f Å", openGap, closedGap)
+}
+
+// TestCloseLoopInvalidRange checks argument validation
+func TestCloseLoopInvalidRange(t *testing.T) {
+	protein := createTestProtein(5)
+	if err := CloseLoop(protein, 3, 1, [3]float64{}); err == nil {
+		t.Error("Expected error for reversed loop range")
+	}
+	if err := CloseLoop(protein, 0, 10, [3]float64{}); err == nil {
+		t.Error("Expected error for out-of-range loop")
+	}
+}
+
+func distance3(a, b geometry.Vector3) float64 {
+	return a.Sub(b).Magnitude()
+}
+
+// buildIdealHelix builds an α-helical poly-Ala backbone with exact internal
+// coordinates (φ = -60°, ψ = -45°, ω = 180°)
+func buildIdealHelix(n int) *parser.Protein {
+	return buildIdealBackbone(n, -60.0, -45.0)
+}
+
+// buildIdealBackbone builds a poly-Ala backbone with uniform (φ, ψ) in degrees
+// and exact internal coordinates (ω = 180°)
+func buildIdealBackbone(n int, phiDeg, psiDeg float64) *parser.Protein {
+	deg := math.Pi / 180.0
+	phi, psi, omega := phiDeg*deg, psiDeg*deg, 180.0*deg
+
+	protein := &parser.Protein{Name: "ideal_helix"}
+
+	// Seed the first three atoms
+	n0 := geometry.Vector3{X: 0, Y: 0, Z: 0}
+	ca0 := geometry.Vector3{X: geometry.BondN_CA, Y: 0, Z: 0}
+	a := (180.0 - geometry.AngleN_CA_C) * deg
+	c0 := ca0.Add(geometry.Vector3{X: math.Cos(a), Y: math.Sin(a), Z: 0}.Scale(geometry.BondCA_C))
+
+	positions := [][3]geometry.Vector3{{n0, ca0, c0}}
+	for i := 1; i < n; i++ {
+		prev := positions[i-1]
+		nPos := placeTestAtom(prev[0], prev[1], prev[2], geometry.BondC_N, geometry.AngleCA_C_N*deg, psi)
+		caPos := placeTestAtom(prev[1], prev[2], nPos, geometry.BondN_CA, geometry.AngleC_N_CA*deg, omega)
+		cPos := placeTestAtom(prev[2], nPos, caPos, geometry.BondCA_C, geometry.AngleN_CA_C*deg, phi)
+		positions = append(positions, [3]geometry.Vector3{nPos, caPos, cPos})
+	}
+
+	serial := 1
+	newAtom := func(name string, resSeq int, p geometry.Vector3) *parser.Atom {
+		atom := &parser.Atom{Serial: serial, Name: name, ResName: "ALA", ChainID: "A",
+			ResSeq: resSeq, X: p.X, Y: p.Y, Z: p.Z, Element: name[:1]}
+		serial++
+		protein.Atoms = append(protein.Atoms, atom)
+		return atom
+	}
+
+	for i, p := range positions {
+		// Carbonyl O lies in the peptide plane, trans to the next N
+		oPos := placeTestAtom(p[0], p[1], p[2], geometry.BondC_O, geometry.AngleCA_C_O*deg, psi+math.Pi)
+		protein.Residues = append(protein.Residues, &parser.Residue{
+			Name:    "ALA",
+			SeqNum:  i + 1,
+			ChainID: "A",
+			N:       newAtom("N", i+1, p[0]),
+			CA:      newAtom("CA", i+1, p[1]),
+			C:       newAtom("C", i+1, p[2]),
+			O:       newAtom("O", i+1, oPos),
+		})
+	}
+
+	return protein
+}
+
+// placeTestAtom places d given a, b, c, the c-d bond length, b-c-d angle and
+// a-b-c-d torsion (NeRF)
+func placeTestAtom(a, b, c geometry.Vector3, bond, angle, torsion float64) geometry.Vector3 {
+	bc := c.Sub(b).Normalize()
+	nrm := b.Sub(a).Cross(bc).Normalize()
+	m := nrm.Cross(bc)
+
+	d2 := geometry.Vector3{
+		X: -bond * math.Cos(angle),
+		Y: bond * math.Sin(angle) * math.Cos(torsion),
+		Z: bond * math.Sin(angle) * math.Sin(torsion),
+	}
+
+	return c.Add(bc.Scale(d2.X)).Add(m.Scale(d2.Y)).Add(nrm.Scale(d2.Z))
+}
@@ -18,6 +18,7 @@
 package sampling
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -43,37 +44,102 @@ type MonteCarloConfig struct {
 	// Cooling schedule: exponential, linear, geometric, vedic_phi
 	CoolingSchedule string
 
+	// Move set: cartesian, dihedral, mixed, backrub
+	// cartesian perturbs every atom (distorts bonds), dihedral perturbs one
+	// residue's (φ, ψ) by rotating about the bond, mixed picks either with equal odds,
+	// backrub rotates one residue about its flanking Cα axis (see BackrubMove)
+	MoveType string
+
 	// Step size for coordinate perturbations (Angstroms)
 	StepSize float64
 
+	// Gaussian σ for dihedral moves (radians)
+	DihedralStepSize float64
+
+	// Gaussian σ for backrub rotations (radians)
+	BackrubStepSize float64
+
 	// Vedic bias weight [0, 1]
 	// 0 = pure energy, 1 = pure Vedic score, 0.3 = 30% Vedic influence
 	VedicWeight float64
 
+	// Share of the Vedic term taken from per-residue digital root and
+	// golden-angle harmony [0, 1] (see harmonicBias)
+	// 0 = CalculateVedicScore total only
+	HarmonicBias float64
+
+	// Log-odds acceptance bonus for structures whose backbone digital root
+	// (see backboneDigitalRoot) is one of ResonantRoots, clamped to [0, 1]
+	// 0 = plain Metropolis. MonteCarloVedic only
+	DigitalRootWeight float64
+
+	// Digital roots that earn the DigitalRootWeight bonus
+	ResonantRoots []int
+
 	// Energy calculation cutoffs
 	VdWCutoff  float64 // Van der Waals cutoff (Å)
 	ElecCutoff float64 // Electrostatic cutoff (Å)
 
+	// Switching windows: nonbonded energies fall smoothly to zero over
+	// [SwitchStart, Cutoff] (0: hard cutoff, see physics.EnergyConfig)
+	VdWSwitchStart  float64
+	ElecSwitchStart float64
+
+	// Unit of the energies in MonteCarloResult (zero value: kcal/mol)
+	// Sampling itself runs in kcal/mol with k_B in kcal/(mol·K), so the
+	// accepted moves at a given temperature do not depend on it
+	EnergyUnits physics.EnergyUnits
+
 	// Random seed for reproducibility
 	Seed int64
 
 	// Acceptance tracking
 	TrackAcceptance bool
+
+	// Steps between neighbor swap attempts (replica exchange only)
+	SwapInterval int
+
+	// Re-sum only the non-bonded pairs a move changed (see
+	// physics.IncrementalEnergy) instead of the full O(n²) energy
+	IncrementalEnergy bool
+
+	// Steps between full recomputations that reset incremental drift
+	// (0 = never)
+	EnergyResyncInterval int
+
+	// Trajectory recording: snapshot every TrajectoryStride accepted steps
+	// (0 = off), keeping at most TrajectoryMaxFrames (<= 0: unbounded).
+	// MonteCarloVedic and AdaptiveMonteCarloVedic only
+	TrajectoryStride    int
+	TrajectoryMaxFrames int
 }
 
 // DefaultMonteCarloConfig returns recommended MC parameters
 func DefaultMonteCarloConfig() MonteCarloConfig {
 	return MonteCarloConfig{
-		NumSteps:           1000,            // 1000 MC steps
-		TemperatureInitial: 500.0,           // 500 K (high exploration)
-		TemperatureFinal:   10.0,            // 10 K (low refinement)
-		CoolingSchedule:    "vedic_phi",     // Golden ratio cooling
-		StepSize:           0.5,             // 0.5 Å perturbations
-		VedicWeight:        0.3,             // 30% Vedic influence
-		VdWCutoff:          10.0,            // 10 Å
-		ElecCutoff:         12.0,            // 12 Å
-		Seed:               42,              // Reproducible
-		TrackAcceptance:    true,            // Track acceptance rate
+		NumSteps:             1000,        // 1000 MC steps
+		TemperatureInitial:   500.0,       // 500 K (high exploration)
+		TemperatureFinal:     10.0,        // 10 K (low refinement)
+		CoolingSchedule:      "vedic_phi", // Golden ratio cooling
+		MoveType:             "dihedral",  // Keep bond geometry valid
+		StepSize:             0.5,         // 0.5 Å perturbations
+		DihedralStepSize:     0.2618,      // 15° φ/ψ perturbations
+		BackrubStepSize:      0.1745,      // 10° backrub rotations
+		VedicWeight:          0.3,         // 30% Vedic influence
+		HarmonicBias:         0.5,         // Half the Vedic term per-residue
+		DigitalRootWeight:    0.0,         // Digital-root bias off
+		ResonantRoots:        []int{3, 6, 9},
+		VdWCutoff:            10.0,        // 10 Å
+		ElecCutoff:           12.0,        // 12 Å
+		VdWSwitchStart:       8.0,         // Smooth cutoffs: no energy
+		ElecSwitchStart:      10.0,        // jumps as pairs cross them
+		EnergyUnits:          physics.KcalPerMol,
+		Seed:                 42,          // Reproducible
+		TrackAcceptance:      true,        // Track acceptance rate
+		SwapInterval:         10,          // REMC swap every 10 steps
+		IncrementalEnergy:    true,        // O(n) energy per dihedral move
+		EnergyResyncInterval: 100,         // Full recompute every 100 steps
+		TrajectoryMaxFrames:  1000,        // Bound recorded frames
 	}
 }
 
@@ -98,6 +164,35 @@ type MonteCarloResult struct {
 	// Convergence
 	Converged      bool
 	ConvergenceStep int
+
+	// Equilibration of the per-step energy series (see DetectEquilibration;
+	// MonteCarloVedic and AdaptiveMonteCarloVedic only): steps before
+	// EquilibrationStep are burn-in, and the rest hold EffectiveSamples
+	// independent samples, AutocorrelationTime steps apart
+	EquilibrationStep   int
+	AutocorrelationTime float64
+	EffectiveSamples    float64
+
+	// Per-replica statistics (replica exchange only, ordered by temperature)
+	Replicas []ReplicaStats
+
+	// Largest |incremental - full| energy found at a resync
+	MaxEnergyDrift float64
+
+	// Unit of every energy above and of the trajectory frames
+	EnergyUnits physics.EnergyUnits
+
+	// Recorded frames (nil unless TrajectoryStride > 0)
+	Trajectory *parser.Trajectory
+
+	// Fraction of steps that ended in a structure with a resonant backbone
+	// digital root (MonteCarloVedic only)
+	ResonantFraction float64
+
+	// Adapted dihedral step σ (radians) at the end of the run and the
+	// acceptance rate of each adaptation window (AdaptiveMonteCarloVedic only)
+	FinalStepSize    float64
+	WindowAcceptance []float64
 }
 
 // MonteCarloVedic performs Monte Carlo sampling with Vedic harmonic biasing
@@ -125,16 +220,36 @@ type MonteCarloResult struct {
 // MATHEMATICIAN:
 // Detailed balance ensures convergence to equilibrium distribution
 // Ergodicity requires all states reachable (ensured by perturbations)
+//
+// DIGITAL ROOT BIAS:
+// With DigitalRootWeight w > 0 the acceptance probability is multiplied by
+// e^w for a move into a resonant structure and e^-w for a move out of one
+// (see digitalRootBias). Detailed balance then holds exactly for the
+// tilted distribution P(state) ∝ exp(-S/kT) × e^(w·[resonant]): resonant
+// structures gain at most a factor e (a free-energy shift of at most kT),
+// and with w = 0 the run is identical to plain Metropolis.
 func MonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCarloResult, error) {
+	return MonteCarloVedicCtx(context.Background(), initial, config)
+}
+
+// MonteCarloVedicCtx is MonteCarloVedic with cancellation
+//
+// ctx is checked at the top of every step. On cancellation the result holds
+// the best structure so far and is returned together with ctx.Err().
+func MonteCarloVedicCtx(ctx context.Context, initial *parser.Protein, config MonteCarloConfig) (*MonteCarloResult, error) {
 	if initial == nil {
 		return nil, fmt.Errorf("initial structure is nil")
 	}
+	if err := validateMoveType(config.MoveType); err != nil {
+		return nil, err
+	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
 	result := &MonteCarloResult{
 		BestEnergy:     math.Inf(1),
 		BestVedicScore: 0.0,
+		Trajectory:     parser.NewTrajectory(config.TrajectoryStride, config.TrajectoryMaxFrames),
 	}
 
 	// Clone initial structure
@@ -142,38 +257,55 @@ func MonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCa
 	best := cloneProteinDeep(initial)
 
 	// Calculate initial scores
-	currentEnergy := calculateTotalEnergy(current, config.VdWCutoff, config.ElecCutoff)
+	energy := newEnergyTracker(current, config)
+	currentEnergy := energy.total()
 	currentAngles := geometry.CalculateRamachandran(current)
 	currentVedic := vedic.CalculateVedicScore(current, currentAngles)
+	var currentDihedrals []geometry.RamachandranAngles
 
 	result.InitialEnergy = currentEnergy
 	result.InitialVedicScore = currentVedic.TotalScore
 
 	// Combined score: Energy - Vedic bonus
 	// Lower is better (minimize energy, maximize Vedic)
-	currentScore := combinedScore(currentEnergy, currentVedic.TotalScore, config.VedicWeight)
+	currentScore := combinedScore(currentEnergy, vedicTerm(currentVedic, currentAngles, config), config.VedicWeight)
 	bestScore := currentScore
+	currentResonant := isResonantRoot(backboneDigitalRoot(currentAngles), config.ResonantRoots)
+	resonantSteps := 0
 
 	result.BestEnergy = currentEnergy
 	result.BestVedicScore = currentVedic.TotalScore
 
+	// Energy after every step, for DetectEquilibration
+	energies := make([]float64, 0, config.NumSteps)
+
 	// Monte Carlo loop
+	var cancelErr error
 	for step := 0; step < config.NumSteps; step++ {
+		if err := ctx.Err(); err != nil {
+			cancelErr = err
+			break
+		}
+
 		// Calculate temperature for this step
 		T := getTemperature(step, config)
 
-		// Propose move: perturb coordinates
-		proposed := cloneProteinDeep(current)
-		perturbCoordinates(proposed, config.StepSize)
+		// Propose move (Cartesian or dihedral)
+		proposed, proposedDihedrals := proposeMove(current, currentDihedrals, config, rng)
 
 		// Calculate proposed scores
-		proposedEnergy := calculateTotalEnergy(proposed, config.VdWCutoff, config.ElecCutoff)
+		proposedEnergy := energy.propose(proposed, singleTorsionMove(currentDihedrals, proposedDihedrals))
 		proposedAngles := geometry.CalculateRamachandran(proposed)
 		proposedVedic := vedic.CalculateVedicScore(proposed, proposedAngles)
-		proposedScore := combinedScore(proposedEnergy, proposedVedic.TotalScore, config.VedicWeight)
+		proposedScore := combinedScore(proposedEnergy, vedicTerm(proposedVedic, proposedAngles, config), config.VedicWeight)
+		proposedResonant := isResonantRoot(backboneDigitalRoot(proposedAngles), config.ResonantRoots)
 
 		// Metropolis acceptance criterion
+		// Boltzmann constant k in kcal/(mol·K)
+		kB := physics.BoltzmannKcal
 		deltaScore := proposedScore - currentScore
+		// Digital-root bonus enters as a shift of ΔS by -kT·logBias
+		deltaScore -= kB * T * digitalRootBias(currentResonant, proposedResonant, config.DigitalRootWeight)
 		accepted := false
 
 		if deltaScore < 0 {
@@ -181,11 +313,9 @@ func MonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCa
 			accepted = true
 		} else {
 			// Worse score: accept with probability exp(-ΔS/kT)
-			// Boltzmann constant k = 0.001987 kcal/(mol·K)
-			kB := 0.001987
 			acceptProb := math.Exp(-deltaScore / (kB * T))
 
-			if rand.Float64() < acceptProb {
+			if rng.Float64() < acceptProb {
 				accepted = true
 			}
 		}
@@ -193,10 +323,13 @@ func MonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCa
 		// Update current state
 		if accepted {
 			current = proposed
-			currentEnergy = proposedEnergy
+			currentDihedrals = proposedDihedrals
+			currentEnergy = energy.accept(proposed)
 			currentVedic = proposedVedic
 			currentScore = proposedScore
+			currentResonant = proposedResonant
 			result.NumAccepted++
+			result.Trajectory.Record(result.NumAccepted, step, currentEnergy, current)
 
 			// Track best
 			if currentScore < bestScore {
@@ -209,6 +342,17 @@ func MonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCa
 		} else {
 			result.NumRejected++
 		}
+		if currentResonant {
+			resonantSteps++
+		}
+
+		// Bound the drift of the running energy total
+		if resynced, ok := energy.resync(step, result); ok {
+			currentEnergy = resynced
+			currentAngles := geometry.CalculateRamachandran(current)
+			currentScore = combinedScore(currentEnergy, vedicTerm(currentVedic, currentAngles, config), config.VedicWeight)
+		}
+		energies = append(energies, currentEnergy)
 
 		// Check convergence: if no improvement for 200 steps, stop
 		if step-result.ConvergenceStep > 200 {
@@ -221,14 +365,48 @@ func MonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCa
 	totalSteps := result.NumAccepted + result.NumRejected
 	if totalSteps > 0 {
 		result.AcceptanceRate = float64(result.NumAccepted) / float64(totalSteps)
+		result.ResonantFraction = float64(resonantSteps) / float64(totalSteps)
 	}
 
 	// Final statistics
+	result.setEquilibration(energies)
 	result.FinalStructure = best
 	result.FinalEnergy = result.BestEnergy
 	result.FinalVedicScore = result.BestVedicScore
+	result.convertUnits(config.EnergyUnits)
 
-	return result, nil
+	return result, cancelErr
+}
+
+// setEquilibration fills the equilibration statistics from the per-step
+// energies of a finished run
+func (result *MonteCarloResult) setEquilibration(energies []float64) {
+	stats := DetectEquilibration(energies)
+	result.EquilibrationStep = stats.Step
+	result.AutocorrelationTime = stats.AutocorrelationTime
+	result.EffectiveSamples = stats.EffectiveSamples
+}
+
+// convertUnits converts the kcal/mol energies of a finished run into units
+func (result *MonteCarloResult) convertUnits(units physics.EnergyUnits) {
+	result.EnergyUnits = units
+	f := units.FromKcal()
+	if f == 1.0 {
+		return
+	}
+	result.InitialEnergy *= f
+	result.FinalEnergy *= f
+	result.BestEnergy *= f
+	result.MaxEnergyDrift *= f
+	for i := range result.Replicas {
+		result.Replicas[i].BestEnergy *= f
+	}
+	if result.Trajectory != nil {
+		result.Trajectory.EnergyUnit = units.String()
+		for i := range result.Trajectory.Frames {
+			result.Trajectory.Frames[i].Energy *= f
+		}
+	}
 }
 
 // getTemperature calculates temperature for MC step according to cooling schedule
@@ -308,7 +486,97 @@ func combinedScore(energy, vedicScore, vedicWeight float64) float64 {
 	return energy - vedicWeight*vedicScore*vedicScale
 }
 
-// perturbCoordinates randomly perturbs atom positions
+// vedicTerm returns the Vedic score used in combinedScore: the structure's
+// CalculateVedicScore total blended with harmonicBias by config.HarmonicBias
+func vedicTerm(score vedic.VedicScore, angles []geometry.RamachandranAngles, config MonteCarloConfig) float64 {
+	b := math.Max(0, math.Min(config.HarmonicBias, 1))
+	if b == 0 {
+		return score.TotalScore
+	}
+	return (1-b)*score.TotalScore + b*harmonicBias(angles)
+}
+
+// harmonicBias scores (φ, ψ) pairs for digital root and golden-angle harmony
+//
+// Per residue: half from whether DR(φ) and DR(ψ) (whole degrees) are a
+// harmonious pair, half from the mean golden-angle alignment of φ and ψ.
+// Returns the mean over residues with both angles defined, in [0, 1].
+//
+// VEDIC MATHEMATICS:
+// This is the "digital root biasing" of Vedic Monte Carlo - moves that
+// bring angle pairs into harmony lower the combined score
+func harmonicBias(angles []geometry.RamachandranAngles) float64 {
+	total := 0.0
+	count := 0
+
+	for _, angle := range angles {
+		if math.IsNaN(angle.Phi) || math.IsNaN(angle.Psi) {
+			continue
+		}
+
+		rootScore := 0.0
+		if vedic.HarmoniousRoots(vedic.DigitalRootFloat(angle.Phi), vedic.DigitalRootFloat(angle.Psi)) {
+			rootScore = 1.0
+		}
+		goldenScore := 0.5 * (vedic.GoldenAlignment(angle.Phi) + vedic.GoldenAlignment(angle.Psi))
+
+		total += 0.5*rootScore + 0.5*goldenScore
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// backboneDigitalRoot returns the digital root of Σ|φ| over residues with
+// φ defined, each rounded to whole degrees
+//
+// VEDIC MATHEMATICS:
+// A single quantized descriptor of the whole backbone; a dihedral move
+// changes it unless the moved φ rounds to the same degree (mod 9)
+func backboneDigitalRoot(angles []geometry.RamachandranAngles) int {
+	sum := 0
+	for _, angle := range angles {
+		if math.IsNaN(angle.Phi) || math.IsInf(angle.Phi, 0) {
+			continue
+		}
+		sum += int(math.Round(math.Abs(angle.Phi * 180.0 / math.Pi)))
+	}
+	return vedic.DigitalRoot(sum)
+}
+
+// isResonantRoot reports whether root is one of roots
+func isResonantRoot(root int, roots []int) bool {
+	for _, r := range roots {
+		if root == r {
+			return true
+		}
+	}
+	return false
+}
+
+// digitalRootBias returns the log acceptance bonus of a move: +w into a
+// resonant structure, -w out of one, 0 otherwise, with w clamped to [0, 1]
+//
+// MATHEMATICIAN:
+// The bonus is antisymmetric in (from, to), so it is the log ratio of the
+// tilted weights e^(w·[resonant]) and detailed balance is kept exactly for
+// the tilted distribution
+func digitalRootBias(fromResonant, toResonant bool, weight float64) float64 {
+	w := math.Max(0, math.Min(weight, 1))
+	switch {
+	case w == 0 || fromResonant == toResonant:
+		return 0
+	case toResonant:
+		return w
+	default:
+		return -w
+	}
+}
+
+// perturbCoordinates randomly perturbs atom positions using rng
 //
 // PHYSICIST:
 // Gaussian perturbations maintain detailed balance
@@ -317,24 +585,295 @@ func combinedScore(energy, vedicScore, vedicWeight float64) float64 {
 // BIOCHEMIST:
 // Perturb all atoms to explore conformational space
 // Step size controls exploration vs exploitation
-func perturbCoordinates(protein *parser.Protein, stepSize float64) {
+func perturbCoordinates(protein *parser.Protein, stepSize float64, rng *rand.Rand) {
 	for _, atom := range protein.Atoms {
 		// Gaussian perturbation in each dimension
-		atom.X += rand.NormFloat64() * stepSize
-		atom.Y += rand.NormFloat64() * stepSize
-		atom.Z += rand.NormFloat64() * stepSize
+		atom.X += rng.NormFloat64() * stepSize
+		atom.Y += rng.NormFloat64() * stepSize
+		atom.Z += rng.NormFloat64() * stepSize
+	}
+}
+
+// validateMoveType checks MonteCarloConfig.MoveType ("" means cartesian)
+func validateMoveType(moveType string) error {
+	switch moveType {
+	case "", "cartesian", "dihedral", "mixed", "backrub":
+		return nil
+	default:
+		return fmt.Errorf("unknown move type %q (expected cartesian, dihedral, mixed, or backrub)", moveType)
 	}
 }
 
+// proposeMove generates a trial structure according to config.MoveType
+//
+// dihedrals is the (φ, ψ) state of current, or nil if it must be derived from
+// coordinates (initial structure, or after a Cartesian move). The returned
+// dihedral state is nil for Cartesian and backrub moves.
+//
+// BIOCHEMIST:
+// Dihedral moves are the natural degrees of freedom of a protein backbone.
+// Bond lengths and angles stay as they are because everything beyond the
+// rotated bond turns as one rigid body.
+// Backrub moves keep bond lengths and move only three residues.
+func proposeMove(current *parser.Protein, dihedrals []geometry.RamachandranAngles, config MonteCarloConfig, rng *rand.Rand) (*parser.Protein, []geometry.RamachandranAngles) {
+	if config.MoveType == "backrub" {
+		if proposed, ok := proposeBackrub(current, config.BackrubStepSize, rng); ok {
+			return proposed, nil
+		}
+		// No backrub at this residue (e.g. before a proline): null move,
+		// never a bond-distorting Cartesian one
+		return cloneProteinDeep(current), nil
+	}
+
+	useDihedral := config.MoveType == "dihedral" ||
+		(config.MoveType == "mixed" && rng.Float64() < 0.5)
+
+	if useDihedral && len(current.Residues) > 0 {
+		if dihedrals == nil {
+			dihedrals = dihedralState(current)
+		}
+
+		proposedDihedrals := perturbDihedrals(dihedrals, config.DihedralStepSize, rng)
+		proposed, applied, err := rotateDihedrals(current, dihedrals, proposedDihedrals)
+		if err == nil {
+			return proposed, applied
+		}
+		// Fall through to a Cartesian move if the angle cannot be rotated
+	}
+
+	proposed := cloneProteinDeep(current)
+	perturbCoordinates(proposed, config.StepSize, rng)
+	return proposed, nil
+}
+
+// dihedralState extracts (φ, ψ) from coordinates, with undefined terminal
+// angles set to the extended conformation used by the builder
+func dihedralState(protein *parser.Protein) []geometry.RamachandranAngles {
+	angles := geometry.CalculateRamachandran(protein)
+	for i := range angles {
+		if math.IsNaN(angles[i].Phi) {
+			angles[i].Phi = -120.0 * math.Pi / 180.0
+		}
+		if math.IsNaN(angles[i].Psi) {
+			angles[i].Psi = 120.0 * math.Pi / 180.0
+		}
+	}
+	return angles
+}
+
+// perturbDihedrals returns a copy of angles with one random residue's φ, ψ,
+// or both perturbed by N(0, stepSize), wrapped to [-π, π]
+func perturbDihedrals(angles []geometry.RamachandranAngles, stepSize float64, rng *rand.Rand) []geometry.RamachandranAngles {
+	perturbed := make([]geometry.RamachandranAngles, len(angles))
+	copy(perturbed, angles)
+
+	k := rng.Intn(len(perturbed))
+	switch rng.Intn(3) {
+	case 0:
+		perturbed[k].Phi = wrapRadians(perturbed[k].Phi + rng.NormFloat64()*stepSize)
+	case 1:
+		perturbed[k].Psi = wrapRadians(perturbed[k].Psi + rng.NormFloat64()*stepSize)
+	default:
+		perturbed[k].Phi = wrapRadians(perturbed[k].Phi + rng.NormFloat64()*stepSize)
+		perturbed[k].Psi = wrapRadians(perturbed[k].Psi + rng.NormFloat64()*stepSize)
+	}
+
+	return perturbed
+}
+
+// rotateDihedrals returns a copy of current with each (φ, ψ) changed from
+// from to to applied as a rotation about its bond, and the angles actually
+// applied
+//
+// Everything beyond the bond turns with it (side chains, hydrogens, OXT),
+// so an all-atom structure stays all-atom and bond lengths and angles are
+// kept as they are. Undefined terminal angles (φ of the first residue, ψ of
+// the last) and proline φ, which the pyrrolidine ring fixes, are not
+// rotated and keep their value in from. Returns an error if a residue lacks
+// N, CA, C or O, or an interior angle to change is undefined in current's
+// coordinates (a chain break, or collinear atoms).
+func rotateDihedrals(current *parser.Protein, from, to []geometry.RamachandranAngles) (*parser.Protein, []geometry.RamachandranAngles, error) {
+	n := len(current.Residues)
+	for i, res := range current.Residues {
+		if res == nil || !res.HasCompleteBackbone() || res.O == nil {
+			return nil, nil, fmt.Errorf("residue %d has incomplete backbone", i)
+		}
+	}
+
+	proposed := cloneProteinDeep(current)
+	applied := make([]geometry.RamachandranAngles, len(to))
+	copy(applied, to)
+
+	var residueAtoms [][]*parser.Atom
+	rotate := func(i int, isPhi bool, axisStart, axisEnd *parser.Atom, theta float64) {
+		if residueAtoms == nil {
+			residueAtoms = collectLoopAtoms(proposed, 0, n-1)
+		}
+		axis := atomVec(axisEnd).Sub(atomVec(axisStart)).Normalize()
+		rotateAtoms(movingAtoms(residueAtoms, i, isPhi), atomVec(axisEnd), axis, theta)
+	}
+
+	var defined []geometry.RamachandranAngles
+	for i := 0; i < len(applied) && i < n; i++ {
+		res := proposed.Residues[i]
+		dPhi := wrapRadians(to[i].Phi - from[i].Phi)
+		dPsi := wrapRadians(to[i].Psi - from[i].Psi)
+		if dPhi == 0 && dPsi == 0 {
+			continue
+		}
+		if defined == nil {
+			defined = geometry.CalculateRamachandran(current)
+		}
+
+		if dPhi != 0 {
+			switch {
+			case i == 0 || isProlineName(res.Name):
+				applied[i].Phi = from[i].Phi
+			case !defined[i].HasPhi():
+				return nil, nil, fmt.Errorf("residue %d: φ undefined in coordinates", i)
+			default:
+				rotate(i, true, res.N, res.CA, dPhi)
+			}
+		}
+		if dPsi != 0 {
+			switch {
+			case i == n-1:
+				applied[i].Psi = from[i].Psi
+			case !defined[i].HasPsi():
+				return nil, nil, fmt.Errorf("residue %d: ψ undefined in coordinates", i)
+			default:
+				rotate(i, false, res.CA, res.C, dPsi)
+			}
+		}
+	}
+
+	return proposed, applied, nil
+}
+
+// singleTorsionMove reports whether after differs from before in exactly
+// one φ or ψ, i.e. rotateDihedrals moved one rigid body
+//
+// Turning both φ and ψ of a residue is not rigid: atoms between the two
+// bonds (Cβ, Hα) follow only the φ rotation.
+func singleTorsionMove(before, after []geometry.RamachandranAngles) bool {
+	if before == nil || after == nil || len(before) != len(after) {
+		return false
+	}
+	changed := 0
+	for i := range before {
+		if before[i].Phi != after[i].Phi {
+			changed++
+		}
+		if before[i].Psi != after[i].Psi {
+			changed++
+		}
+	}
+	return changed == 1
+}
+
+// isProlineName reports whether a residue name (one- or three-letter) is
+// proline or a modified proline such as HYP
+func isProlineName(name string) bool {
+	return name == "P" || parser.ThreeToOne(name) == 'P'
+}
+
+// wrapRadians wraps an angle to [-π, π]
+func wrapRadians(angle float64) float64 {
+	for angle > math.Pi {
+		angle -= 2 * math.Pi
+	}
+	for angle < -math.Pi {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
+
 // calculateTotalEnergy computes total AMBER force field energy
 //
-// This is a wrapper for physics.CalculateTotalEnergy
+// This is a wrapper for physics.CalculateTotalEnergyWithConfig, using the
+// chain's nonbonded switching window
 // Returns just the total energy value (not components)
-func calculateTotalEnergy(protein *parser.Protein, vdwCutoff, elecCutoff float64) float64 {
-	energyComponents := physics.CalculateTotalEnergy(protein, vdwCutoff, elecCutoff)
+func calculateTotalEnergy(protein *parser.Protein, config MonteCarloConfig) float64 {
+	energyComponents := physics.CalculateTotalEnergyWithConfig(protein, config.energyConfig())
 	return energyComponents.Total
 }
 
+// energyConfig returns the physics energy settings of config, in kcal/mol
+// whatever config.EnergyUnits (see convertUnits)
+func (config MonteCarloConfig) energyConfig() physics.EnergyConfig {
+	return physics.EnergyConfig{
+		VdWCutoff:       config.VdWCutoff,
+		ElecCutoff:      config.ElecCutoff,
+		VdWSwitchStart:  config.VdWSwitchStart,
+		ElecSwitchStart: config.ElecSwitchStart,
+	}
+}
+
+// energyTracker evaluates Monte Carlo energies, incrementally when
+// config.IncrementalEnergy is set
+type energyTracker struct {
+	config      MonteCarloConfig
+	incremental *physics.IncrementalEnergy
+	pending     physics.EnergyComponents
+	current     float64
+	proposed    float64
+}
+
+// newEnergyTracker starts tracking the energy of initial
+func newEnergyTracker(initial *parser.Protein, config MonteCarloConfig) *energyTracker {
+	t := &energyTracker{config: config}
+	if config.IncrementalEnergy {
+		t.incremental = physics.NewIncrementalEnergy(initial, config.energyConfig())
+		t.current = t.incremental.Energy().Total
+	} else {
+		t.current = calculateTotalEnergy(initial, config)
+	}
+	return t
+}
+
+// total returns the energy of the current structure
+func (t *energyTracker) total() float64 {
+	return t.current
+}
+
+// propose returns the energy of proposed; rigid means the atoms that moved
+// moved as one rigid body (see singleTorsionMove)
+func (t *energyTracker) propose(proposed *parser.Protein, rigid bool) float64 {
+	if t.incremental == nil {
+		t.proposed = calculateTotalEnergy(proposed, t.config)
+		return t.proposed
+	}
+	t.pending = t.incremental.Propose(proposed, rigid)
+	t.proposed = t.pending.Total
+	return t.proposed
+}
+
+// accept makes the last proposed structure current and returns its energy
+func (t *energyTracker) accept(proposed *parser.Protein) float64 {
+	if t.incremental != nil {
+		t.incremental.Accept(proposed, t.pending)
+	}
+	t.current = t.proposed
+	return t.current
+}
+
+// resync replaces the running total with a full recomputation every
+// EnergyResyncInterval steps, recording the drift in result
+//
+// Returns the recomputed energy of the current structure and true when a
+// resync happened; the caller rescores the current state with it so exact
+// ties compare as they would under full recomputation.
+func (t *energyTracker) resync(step int, result *MonteCarloResult) (float64, bool) {
+	interval := t.config.EnergyResyncInterval
+	if t.incremental == nil || interval <= 0 || (step+1)%interval != 0 {
+		return 0, false
+	}
+	drift := t.incremental.Resync()
+	result.MaxEnergyDrift = math.Max(result.MaxEnergyDrift, drift)
+	t.current = t.incremental.Energy().Total
+	return t.current, true
+}
+
 // cloneProteinDeep creates a deep copy of protein structure
 //
 // ENGINEER:
@@ -374,6 +913,7 @@ func cloneProteinDeep(protein *parser.Protein) *parser.Protein {
 		clonedRes := &parser.Residue{
 			Name:    res.Name,
 			SeqNum:  res.SeqNum,
+			ICode:   res.ICode,
 			ChainID: res.ChainID,
 		}
 		if res.N != nil {
@@ -402,6 +942,15 @@ func cloneProteinDeep(protein *parser.Protein) *parser.Protein {
 //
 // Returns ensemble of diverse low-energy structures
 func GenerateMonteCarloEnsemble(initial *parser.Protein, config MonteCarloConfig, numRuns int) ([]*parser.Protein, error) {
+	return GenerateMonteCarloEnsembleCtx(context.Background(), initial, config, numRuns)
+}
+
+// GenerateMonteCarloEnsembleCtx is GenerateMonteCarloEnsemble with
+// cancellation
+//
+// Each run checks ctx every step (see MonteCarloVedicCtx). On cancellation
+// the structures of the completed runs are returned together with ctx.Err().
+func GenerateMonteCarloEnsembleCtx(ctx context.Context, initial *parser.Protein, config MonteCarloConfig, numRuns int) ([]*parser.Protein, error) {
 	ensemble := make([]*parser.Protein, 0, numRuns)
 	baseSeed := config.Seed
 
@@ -409,7 +958,10 @@ func GenerateMonteCarloEnsemble(initial *parser.Protein, config MonteCarloConfig
 		// Different seed for each run
 		config.Seed = baseSeed + int64(run)
 
-		result, err := MonteCarloVedic(initial, config)
+		result, err := MonteCarloVedicCtx(ctx, initial, config)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ensemble, ctxErr
+		}
 		if err != nil {
 			// Skip failed runs
 			continue
@@ -425,58 +977,88 @@ func GenerateMonteCarloEnsemble(initial *parser.Protein, config MonteCarloConfig
 	return ensemble, nil
 }
 
-// AdaptiveMonteCarloVedic uses adaptive cooling schedule
+// Acceptance window AdaptiveMonteCarloVedic steers toward
+const (
+	adaptiveAcceptMin     = 0.3
+	adaptiveAcceptMax     = 0.5
+	adaptiveStepFactor    = 1.25  // Step σ growth (÷ for shrinking) per window
+	adaptiveMinStepSize   = 0.001 // Radians
+	adaptiveCheckInterval = 100   // Steps per adaptation window
+)
+
+// AdaptiveMonteCarloVedic uses adaptive cooling schedule and step size
 //
 // PHYSICIST:
-// Adjust cooling rate based on acceptance rate
-// - High acceptance (>0.7): Cool faster (exploring too much)
-// - Low acceptance (<0.3): Cool slower (stuck in local minimum)
+// Every 100 steps the window's acceptance rate is compared with the target
+// window 0.3-0.5 and both the step size and the temperature are adjusted:
+// - High acceptance (>0.5): larger steps, cool by φ⁻¹ (exploring too timidly)
+// - Low acceptance (<0.3): smaller steps, heat by 10% (stuck in local minimum)
+// Temperature stays within [TemperatureFinal, TemperatureInitial].
+//
+// Moves are always single-residue dihedral moves, whatever config.MoveType,
+// so bond geometry stays ideal; the adapted step is their σ, starting from
+// config.DihedralStepSize (bounded to [0.001, π] radians).
+//
+// MATHEMATICIAN:
+// A Gaussian φ/ψ kick of one random residue is a symmetric proposal, so
+// plain Metropolis acceptance satisfies detailed balance for the (T, σ) of
+// each window; adaptation only changes them between windows.
 //
-// Target acceptance rate: 0.4-0.6 (optimal exploration)
+// The run stops early (Converged) only after 200 steps without a new best
+// while the last window's acceptance was inside the target window, so a
+// badly tuned step cannot end it.
 func AdaptiveMonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (*MonteCarloResult, error) {
 	if initial == nil {
 		return nil, fmt.Errorf("initial structure is nil")
 	}
+	if err := validateMoveType(config.MoveType); err != nil {
+		return nil, err
+	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
 	result := &MonteCarloResult{
 		BestEnergy:     math.Inf(1),
 		BestVedicScore: 0.0,
+		Trajectory:     parser.NewTrajectory(config.TrajectoryStride, config.TrajectoryMaxFrames),
 	}
 
 	current := cloneProteinDeep(initial)
 	best := cloneProteinDeep(initial)
 
-	currentEnergy := calculateTotalEnergy(current, config.VdWCutoff, config.ElecCutoff)
+	energy := newEnergyTracker(current, config)
+	currentEnergy := energy.total()
 	currentAngles := geometry.CalculateRamachandran(current)
 	currentVedic := vedic.CalculateVedicScore(current, currentAngles)
+	var currentDihedrals []geometry.RamachandranAngles
 
 	result.InitialEnergy = currentEnergy
 	result.InitialVedicScore = currentVedic.TotalScore
 
-	currentScore := combinedScore(currentEnergy, currentVedic.TotalScore, config.VedicWeight)
+	currentScore := combinedScore(currentEnergy, vedicTerm(currentVedic, currentAngles, config), config.VedicWeight)
 	bestScore := currentScore
 
 	result.BestEnergy = currentEnergy
 	result.BestVedicScore = currentVedic.TotalScore
 
-	// Adaptive temperature control
+	// Adaptive temperature and step size control
 	T := config.TemperatureInitial
-	targetAcceptRate := 0.5
+	moveConfig := config
+	moveConfig.MoveType = "dihedral"
+	moveConfig.DihedralStepSize = math.Max(adaptiveMinStepSize, math.Min(math.Pi, config.DihedralStepSize))
 	recentAccepts := 0
 	recentTotal := 0
-	checkInterval := 100 // Adjust every 100 steps
+	tuned := false
+	energies := make([]float64, 0, config.NumSteps)
 
 	for step := 0; step < config.NumSteps; step++ {
 		// Propose and evaluate
-		proposed := cloneProteinDeep(current)
-		perturbCoordinates(proposed, config.StepSize)
+		proposed, proposedDihedrals := proposeMove(current, currentDihedrals, moveConfig, rng)
 
-		proposedEnergy := calculateTotalEnergy(proposed, config.VdWCutoff, config.ElecCutoff)
+		proposedEnergy := energy.propose(proposed, singleTorsionMove(currentDihedrals, proposedDihedrals))
 		proposedAngles := geometry.CalculateRamachandran(proposed)
 		proposedVedic := vedic.CalculateVedicScore(proposed, proposedAngles)
-		proposedScore := combinedScore(proposedEnergy, proposedVedic.TotalScore, config.VedicWeight)
+		proposedScore := combinedScore(proposedEnergy, vedicTerm(proposedVedic, proposedAngles, config), config.VedicWeight)
 
 		// Metropolis criterion
 		deltaScore := proposedScore - currentScore
@@ -485,9 +1067,9 @@ func AdaptiveMonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (
 		if deltaScore < 0 {
 			accepted = true
 		} else {
-			kB := 0.001987
+			kB := physics.BoltzmannKcal
 			acceptProb := math.Exp(-deltaScore / (kB * T))
-			if rand.Float64() < acceptProb {
+			if rng.Float64() < acceptProb {
 				accepted = true
 			}
 		}
@@ -501,10 +1083,12 @@ func AdaptiveMonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (
 		// Update
 		if accepted {
 			current = proposed
-			currentEnergy = proposedEnergy
+			currentDihedrals = proposedDihedrals
+			currentEnergy = energy.accept(proposed)
 			currentVedic = proposedVedic
 			currentScore = proposedScore
 			result.NumAccepted++
+			result.Trajectory.Record(result.NumAccepted, step, currentEnergy, current)
 
 			if currentScore < bestScore {
 				best = cloneProteinDeep(current)
@@ -517,18 +1101,23 @@ func AdaptiveMonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (
 			result.NumRejected++
 		}
 
-		// Adaptive temperature adjustment
-		if recentTotal >= checkInterval {
+		// Adaptive step size and temperature adjustment
+		if recentTotal >= adaptiveCheckInterval {
 			acceptRate := float64(recentAccepts) / float64(recentTotal)
+			result.WindowAcceptance = append(result.WindowAcceptance, acceptRate)
+			tuned = acceptRate >= adaptiveAcceptMin && acceptRate <= adaptiveAcceptMax
 
-			// Adjust temperature based on acceptance rate
-			if acceptRate > targetAcceptRate+0.1 {
-				// Too many accepts: cool faster (multiply by φ^-1)
+			sigma := moveConfig.DihedralStepSize
+			if acceptRate > adaptiveAcceptMax {
+				// Too many accepts: bigger steps, cool faster (multiply by φ^-1)
+				sigma *= adaptiveStepFactor
 				T *= vedic.PhiInverse
-			} else if acceptRate < targetAcceptRate-0.1 {
-				// Too few accepts: heat slightly
+			} else if acceptRate < adaptiveAcceptMin {
+				// Too few accepts: smaller steps, heat slightly
+				sigma /= adaptiveStepFactor
 				T *= 1.1
 			}
+			moveConfig.DihedralStepSize = math.Max(adaptiveMinStepSize, math.Min(math.Pi, sigma))
 
 			// Ensure temperature stays in bounds
 			if T < config.TemperatureFinal {
@@ -543,8 +1132,16 @@ func AdaptiveMonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (
 			recentTotal = 0
 		}
 
-		// Convergence check
-		if step-result.ConvergenceStep > 200 {
+		// Bound the drift of the running energy total
+		if resynced, ok := energy.resync(step, result); ok {
+			currentEnergy = resynced
+			currentAngles := geometry.CalculateRamachandran(current)
+			currentScore = combinedScore(currentEnergy, vedicTerm(currentVedic, currentAngles, config), config.VedicWeight)
+		}
+		energies = append(energies, currentEnergy)
+
+		// Convergence check, once the step size is tuned
+		if tuned && step-result.ConvergenceStep > 200 {
 			result.Converged = true
 			break
 		}
@@ -555,10 +1152,13 @@ func AdaptiveMonteCarloVedic(initial *parser.Protein, config MonteCarloConfig) (
 	if totalSteps > 0 {
 		result.AcceptanceRate = float64(result.NumAccepted) / float64(totalSteps)
 	}
+	result.FinalStepSize = moveConfig.DihedralStepSize
+	result.setEquilibration(energies)
 
 	result.FinalStructure = best
 	result.FinalEnergy = result.BestEnergy
 	result.FinalVedicScore = result.BestVedicScore
+	result.convertUnits(config.EnergyUnits)
 
 	return result, nil
 }
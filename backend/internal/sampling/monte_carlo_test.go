@@ -2,9 +2,13 @@ package sampling
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
 )
 
 // TestMonteCarloVedic tests basic Monte Carlo sampling
@@ -202,7 +206,7 @@ func TestPerturbCoordinates(t *testing.T) {
 	origZ := protein.Atoms[0].Z
 
 	// Perturb
-	perturbCoordinates(protein, 1.0)
+	perturbCoordinates(protein, 1.0, rand.New(rand.NewSource(1)))
 
 	// Coordinates should change
 	if protein.Atoms[0].X == origX && protein.Atoms[0].Y == origY && protein.Atoms[0].Z == origZ {
@@ -221,6 +225,151 @@ func TestPerturbCoordinates(t *testing.T) {
 	t.Logf("Perturbation: dx=%.3f, dy=%.3f, dz=%.3f", dx, dy, dz)
 }
 
+// TestDihedralMovesPreserveBonds random-walks 1000 moves of each type and
+// checks that only dihedral moves keep backbone bond lengths ideal
+func TestDihedralMovesPreserveBonds(t *testing.T) {
+	initial := buildIdealHelix(8)
+
+	maxDeviation := func(moveType string) float64 {
+		config := DefaultMonteCarloConfig()
+		config.MoveType = moveType
+		rng := rand.New(rand.NewSource(7))
+
+		current := initial
+		var dihedrals []geometry.RamachandranAngles
+		worst := 0.0
+		for step := 0; step < 1000; step++ {
+			current, dihedrals = proposeMove(current, dihedrals, config, rng)
+			if d := maxBackboneBondDeviation(current); d > worst {
+				worst = d
+			}
+		}
+		return worst
+	}
+
+	dihedralDev := maxDeviation("dihedral")
+	cartesianDev := maxDeviation("cartesian")
+	t.Logf("Max bond deviation over 1000 moves: dihedral %.4f Å, cartesian %.4f Å", dihedralDev, cartesianDev)
+
+	if dihedralDev > 0.01 {
+		t.Errorf("Dihedral moves distorted bonds by %.4f Å (limit 0.01 Å)", dihedralDev)
+	}
+	if cartesianDev <= 0.01 {
+		t.Errorf("Cartesian moves should distort bonds, got %.4f Å", cartesianDev)
+	}
+}
+
+// TestDihedralMovesKeepSideChains runs dihedral MC on an all-atom helix
+// (Cβ on every residue, a proline ring closure, OXT) and checks every atom
+// survives with its bonds, the proline φ included
+func TestDihedralMovesKeepSideChains(t *testing.T) {
+	deg := math.Pi / 180.0
+	initial := buildIdealHelix(8)
+	pro := initial.Residues[4]
+	pro.Name = "PRO"
+	addAtom := func(name string, res *parser.Residue, p geometry.Vector3) {
+		initial.Atoms = append(initial.Atoms, &parser.Atom{Serial: len(initial.Atoms) + 1, Name: name,
+			ResName: res.Name, ChainID: res.ChainID, ResSeq: res.SeqNum, X: p.X, Y: p.Y, Z: p.Z, Element: name[:1]})
+	}
+	for _, atom := range initial.Atoms {
+		if atom.ResSeq == pro.SeqNum {
+			atom.ResName = "PRO"
+		}
+	}
+	for _, res := range initial.Residues {
+		addAtom("CB", res, placeTestAtom(atomVec(res.C), atomVec(res.N), atomVec(res.CA), 1.53, 110.5*deg, -122.6*deg))
+	}
+	prev := initial.Residues[3]
+	addAtom("CD", pro, placeTestAtom(atomVec(pro.CA), atomVec(prev.C), atomVec(pro.N), 1.47, 125.0*deg, 180.0*deg))
+	last := initial.Residues[len(initial.Residues)-1]
+	addAtom("OXT", last, placeTestAtom(atomVec(last.O), atomVec(last.CA), atomVec(last.C), 1.25, 120.0*deg, 180.0*deg))
+
+	findAtom := func(p *parser.Protein, res *parser.Residue, name string) *parser.Atom {
+		for _, atom := range p.Atoms {
+			if atom.ResSeq == res.SeqNum && atom.Name == name {
+				return atom
+			}
+		}
+		return nil
+	}
+	distance := func(a, b *parser.Atom) float64 { return atomVec(a).Sub(atomVec(b)).Magnitude() }
+	initialCD := distance(findAtom(initial, pro, "CD"), pro.N)
+	initialPhi := geometry.CalculateRamachandran(initial)[4].Phi
+
+	config := DefaultMonteCarloConfig()
+	config.MoveType = "dihedral"
+	config.NumSteps = 300
+	config.Seed = 11
+	result, err := MonteCarloVedic(initial, config)
+	if err != nil {
+		t.Fatalf("MonteCarloVedic failed: %v", err)
+	}
+	final := result.FinalStructure
+	t.Logf("Accepted %d moves, %d atoms", result.NumAccepted, len(final.Atoms))
+
+	if len(final.Atoms) != len(initial.Atoms) {
+		t.Fatalf("Atom count %d after dihedral moves, want %d", len(final.Atoms), len(initial.Atoms))
+	}
+	if d := maxBackboneBondDeviation(final); d > 0.01 {
+		t.Errorf("Backbone bonds distorted by %.4f Å", d)
+	}
+	for _, res := range final.Residues {
+		cb := findAtom(final, res, "CB")
+		if cb == nil {
+			t.Fatalf("Residue %d lost its Cβ", res.SeqNum)
+		}
+		if d := distance(cb, res.CA); math.Abs(d-1.53) > 0.01 {
+			t.Errorf("Residue %d: CA-CB %.3f Å, want 1.53", res.SeqNum, d)
+		}
+	}
+	finalPro := final.Residues[4]
+	if d := distance(findAtom(final, finalPro, "CD"), finalPro.N); math.Abs(d-initialCD) > 1e-6 {
+		t.Errorf("Proline N-CD %.3f Å, want %.3f", d, initialCD)
+	}
+	if phi := geometry.CalculateRamachandran(final)[4].Phi; math.Abs(phi-initialPhi) > 1e-6 {
+		t.Errorf("Proline φ moved from %.1f° to %.1f°", initialPhi/deg, phi/deg)
+	}
+	finalLast := final.Residues[len(final.Residues)-1]
+	if d := distance(findAtom(final, finalLast, "OXT"), finalLast.C); math.Abs(d-1.25) > 0.01 {
+		t.Errorf("C-OXT %.3f Å, want 1.25", d)
+	}
+}
+
+// TestMonteCarloMoveTypeValidation rejects unknown move types
+func TestMonteCarloMoveTypeValidation(t *testing.T) {
+	config := DefaultMonteCarloConfig()
+	config.MoveType = "teleport"
+
+	if _, err := MonteCarloVedic(createTestProtein(3), config); err == nil {
+		t.Error("Expected error for unknown move type")
+	}
+}
+
+// maxBackboneBondDeviation returns the largest deviation of N-CA, CA-C and
+// C-N(i+1) bond lengths from ideal values
+func maxBackboneBondDeviation(protein *parser.Protein) float64 {
+	dist := func(a, b *parser.Atom) float64 {
+		return math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+	}
+
+	worst := 0.0
+	for i, res := range protein.Residues {
+		devs := []float64{
+			math.Abs(dist(res.N, res.CA) - geometry.BondN_CA),
+			math.Abs(dist(res.CA, res.C) - geometry.BondCA_C),
+		}
+		if i+1 < len(protein.Residues) {
+			devs = append(devs, math.Abs(dist(res.C, protein.Residues[i+1].N)-geometry.BondC_N))
+		}
+		for _, d := range devs {
+			if d > worst {
+				worst = d
+			}
+		}
+	}
+	return worst
+}
+
 // TestCloneProteinDeep verifies deep copying
 func TestCloneProteinDeep(t *testing.T) {
 	original := createTestProtein(2)
@@ -318,6 +467,42 @@ func TestAdaptiveMonteCarloVedic(t *testing.T) {
 	t.Logf("Vedic: Initial=%.3f, Final=%.3f", result.InitialVedicScore, result.FinalVedicScore)
 }
 
+// TestAdaptiveStepSize checks that a timid step grows until acceptance
+// reaches the 0.3-0.5 window on an easy surface (short helix, fixed T)
+func TestAdaptiveStepSize(t *testing.T) {
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 3000
+	config.VedicWeight = 0
+	config.TemperatureInitial, config.TemperatureFinal = 300, 300
+	config.DihedralStepSize = 0.01
+
+	result, err := AdaptiveMonteCarloVedic(buildIdealHelix(6), config)
+	if err != nil {
+		t.Fatalf("AdaptiveMonteCarloVedic failed: %v", err)
+	}
+
+	windows := result.WindowAcceptance
+	if len(windows) < 2 {
+		t.Fatalf("Only %d adaptation windows", len(windows))
+	}
+	first, last := windows[0], windows[len(windows)-1]
+	t.Logf("Step σ: %.3f → %.3f rad over %d windows", config.DihedralStepSize, result.FinalStepSize, len(windows))
+	t.Logf("Window acceptance: first=%.2f last=%.2f (target %.1f-%.1f)", first, last, adaptiveAcceptMin, adaptiveAcceptMax)
+
+	if result.FinalStepSize <= 10*config.DihedralStepSize {
+		t.Errorf("Step size %.3f did not grow from %.3f", result.FinalStepSize, config.DihedralStepSize)
+	}
+	if first <= adaptiveAcceptMax {
+		t.Errorf("First window acceptance %.2f: a 0.01 rad step should be accepted almost always", first)
+	}
+	if last < adaptiveAcceptMin-0.1 || last > adaptiveAcceptMax+0.1 {
+		t.Errorf("Last window acceptance %.2f not near the target window", last)
+	}
+	if d := maxBackboneBondDeviation(result.FinalStructure); d > 0.05 {
+		t.Errorf("Backbone bond deviation %.3f Å after adaptive dihedral moves", d)
+	}
+}
+
 // TestMetropolisCriterion verifies probabilistic acceptance
 func TestMetropolisCriterion(t *testing.T) {
 	// Simulate acceptance probability at different ΔE
@@ -396,3 +581,210 @@ func BenchmarkAdaptiveMonteCarloVedic(b *testing.B) {
 		_, _ = AdaptiveMonteCarloVedic(initial, config)
 	}
 }
+
+// TestHarmonicBias checks the digital root / golden-angle term of the Vedic
+// score rewards harmonious angle pairs and feeds combinedScore
+func TestHarmonicBias(t *testing.T) {
+	deg := math.Pi / 180
+
+	// DR(137) = DR(47) = 2 and φ sits half a degree from the golden angle
+	harmonious := []geometry.RamachandranAngles{{Phi: -137 * deg, Psi: 47 * deg}}
+	// DR(60) = 6, DR(45) = 9, both far from the golden angle
+	discordant := []geometry.RamachandranAngles{{Phi: -60 * deg, Psi: -45 * deg}}
+
+	h := harmonicBias(harmonious)
+	d := harmonicBias(discordant)
+	t.Logf("Harmonic bias: harmonious = %.3f, discordant = %.3f", h, d)
+
+	if h < 0.5 {
+		t.Errorf("Harmonious pair scored %.3f, expected at least 0.5 from its digital roots", h)
+	}
+	if h <= d {
+		t.Errorf("Harmonious pair scored %.3f, not above discordant %.3f", h, d)
+	}
+	if h < 0 || h > 1 || d < 0 || d > 1 {
+		t.Errorf("Harmonic bias out of [0, 1]: %.3f, %.3f", h, d)
+	}
+	if b := harmonicBias([]geometry.RamachandranAngles{{Phi: math.NaN(), Psi: 0}}); b != 0 {
+		t.Errorf("Undefined angles scored %.3f, expected 0", b)
+	}
+
+	// Lower combined score for the harmonious pair at equal energy
+	config := DefaultMonteCarloConfig()
+	score := vedic.VedicScore{TotalScore: 0.5}
+	sh := combinedScore(100, vedicTerm(score, harmonious, config), config.VedicWeight)
+	sd := combinedScore(100, vedicTerm(score, discordant, config), config.VedicWeight)
+	if sh >= sd {
+		t.Errorf("Harmonious combined score %.2f not below discordant %.2f", sh, sd)
+	}
+
+	config.HarmonicBias = 0
+	if v := vedicTerm(score, harmonious, config); v != score.TotalScore {
+		t.Errorf("HarmonicBias 0: Vedic term %.3f, expected total %.3f", v, score.TotalScore)
+	}
+}
+
+// TestDigitalRootBias checks the digital-root acceptance bonus: weight 0
+// leaves MonteCarloVedic plain Metropolis, weight 1 enriches structures
+// whose backbone digital root is resonant
+func TestDigitalRootBias(t *testing.T) {
+	deg := math.Pi / 180
+	// 57 + 47 + 1 = 105 → 6; ψ and undefined φ are ignored
+	angles := []geometry.RamachandranAngles{
+		{Phi: math.NaN(), Psi: 10 * deg}, {Phi: -57 * deg, Psi: -47 * deg},
+		{Phi: -47 * deg, Psi: 33 * deg}, {Phi: 1.2 * deg, Psi: math.NaN()},
+	}
+	if root := backboneDigitalRoot(angles); root != 6 {
+		t.Errorf("Backbone digital root %d, want 6", root)
+	}
+	if b := digitalRootBias(false, true, 5); b != 1 {
+		t.Errorf("Bias into resonance %.2f, want the clamped weight 1", b)
+	}
+	if b := digitalRootBias(true, false, 0.5); b != -0.5 {
+		t.Errorf("Bias out of resonance %.2f, want -0.5", b)
+	}
+	if b := digitalRootBias(true, true, 0.5); b != 0 {
+		t.Errorf("Bias within resonance %.2f, want 0", b)
+	}
+
+	initial := buildIdealHelix(10)
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 600
+	config.VedicWeight = 0
+	config.CoolingSchedule = "linear"
+	config.TemperatureInitial = 1e5 // Energy barely matters: the bias shows
+	config.TemperatureFinal = 1e5
+
+	// Weight 0 matches a run in which no state can be resonant
+	plain := config
+	plain.ResonantRoots = nil
+	for _, seed := range []int64{1, 2} {
+		config.Seed, plain.Seed = seed, seed
+		a, err := MonteCarloVedic(initial, config)
+		if err != nil {
+			t.Fatalf("MonteCarloVedic failed: %v", err)
+		}
+		b, err := MonteCarloVedic(initial, plain)
+		if err != nil {
+			t.Fatalf("MonteCarloVedic failed: %v", err)
+		}
+		if a.NumAccepted != b.NumAccepted || a.NumRejected != b.NumRejected || a.FinalEnergy != b.FinalEnergy {
+			t.Errorf("Seed %d: weight 0 accepted %d/%d (E = %.6f), plain Metropolis %d/%d (E = %.6f)",
+				seed, a.NumAccepted, a.NumRejected, a.FinalEnergy, b.NumAccepted, b.NumRejected, b.FinalEnergy)
+		}
+	}
+
+	fraction := func(weight float64) float64 {
+		config.DigitalRootWeight = weight
+		total := 0.0
+		const seeds = 8
+		for seed := int64(1); seed <= seeds; seed++ {
+			config.Seed = seed
+			result, err := MonteCarloVedic(initial, config)
+			if err != nil {
+				t.Fatalf("MonteCarloVedic failed: %v", err)
+			}
+			total += result.ResonantFraction
+		}
+		return total / seeds
+	}
+	unbiased := fraction(0)
+	biased := fraction(1)
+	// Roots 3/6/9 are a third of the states; weight 1 tilts that towards
+	// e/(e+2) ≈ 0.58
+	t.Logf("Resonant fraction: weight 0 = %.3f, weight 1 = %.3f", unbiased, biased)
+	if biased < unbiased+0.1 {
+		t.Errorf("Weight 1 resonant fraction %.3f not enriched over %.3f", biased, unbiased)
+	}
+}
+
+// TestMonteCarloIncrementalEnergy checks incremental energies track full
+// recomputation: bounded drift at every resync and the same trajectory
+func TestMonteCarloIncrementalEnergy(t *testing.T) {
+	initial := buildIdealHelix(20)
+
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 1000
+	config.EnergyResyncInterval = 50
+
+	incremental, err := MonteCarloVedic(initial, config)
+	if err != nil {
+		t.Fatalf("incremental MC failed: %v", err)
+	}
+
+	config.IncrementalEnergy = false
+	full, err := MonteCarloVedic(initial, config)
+	if err != nil {
+		t.Fatalf("full MC failed: %v", err)
+	}
+
+	t.Logf("Best energy: incremental %.6f, full %.6f (max drift %.2e kcal/mol)",
+		incremental.BestEnergy, full.BestEnergy, incremental.MaxEnergyDrift)
+
+	if incremental.MaxEnergyDrift > 1e-4 {
+		t.Errorf("Incremental energy drifted %.2e kcal/mol from full recomputation (limit 1e-4)", incremental.MaxEnergyDrift)
+	}
+	if math.Abs(incremental.BestEnergy-full.BestEnergy) > 1e-3 ||
+		incremental.NumAccepted != full.NumAccepted {
+		t.Errorf("Trajectories diverged: incremental %.6f (%d accepted), full %.6f (%d accepted)",
+			incremental.BestEnergy, incremental.NumAccepted, full.BestEnergy, full.NumAccepted)
+	}
+}
+
+// TestMonteCarloEnergyUnits folds a peptide reporting kcal/mol and kJ/mol:
+// the accepted moves must be identical at the same temperature and every
+// reported energy must differ by exactly the 4.184 conversion factor
+func TestMonteCarloEnergyUnits(t *testing.T) {
+	initial := buildIdealBackbone(12, -120, 130)
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 300
+	config.TrajectoryStride = 10
+	config.EnergyResyncInterval = 50
+
+	kj := config
+	kj.EnergyUnits = physics.KJPerMol
+
+	a, err := MonteCarloVedic(initial, config)
+	if err != nil {
+		t.Fatalf("MonteCarloVedic failed: %v", err)
+	}
+	b, err := MonteCarloVedic(initial, kj)
+	if err != nil {
+		t.Fatalf("MonteCarloVedic failed: %v", err)
+	}
+	t.Logf("kcal: %d accepted, E = %.3f %s; kJ: %d accepted, E = %.3f %s",
+		a.NumAccepted, a.FinalEnergy, a.EnergyUnits, b.NumAccepted, b.FinalEnergy, b.EnergyUnits)
+
+	if a.NumAccepted != b.NumAccepted || a.NumRejected != b.NumRejected || a.ConvergenceStep != b.ConvergenceStep {
+		t.Fatalf("Trajectories diverged: kcal %d/%d, kJ %d/%d accepted/rejected",
+			a.NumAccepted, a.NumRejected, b.NumAccepted, b.NumRejected)
+	}
+	for i, atom := range a.FinalStructure.Atoms {
+		other := b.FinalStructure.Atoms[i]
+		if atom.X != other.X || atom.Y != other.Y || atom.Z != other.Z {
+			t.Fatalf("Final structures differ at atom %d", i)
+		}
+	}
+
+	for _, pair := range [][2]float64{
+		{a.InitialEnergy, b.InitialEnergy},
+		{a.FinalEnergy, b.FinalEnergy},
+		{a.BestEnergy, b.BestEnergy},
+		{a.MaxEnergyDrift, b.MaxEnergyDrift},
+	} {
+		if pair[1] != pair[0]*physics.KcalToKJ {
+			t.Errorf("%.6f kJ/mol, want %.6f × %.3f", pair[1], pair[0], physics.KcalToKJ)
+		}
+	}
+	if a.Trajectory.Len() == 0 || a.Trajectory.Len() != b.Trajectory.Len() {
+		t.Fatalf("Recorded %d and %d frames", a.Trajectory.Len(), b.Trajectory.Len())
+	}
+	for i, frame := range a.Trajectory.Frames {
+		if b.Trajectory.Frames[i].Energy != frame.Energy*physics.KcalToKJ {
+			t.Errorf("Frame %d: %.6f kJ/mol, want %.6f", i, b.Trajectory.Frames[i].Energy, frame.Energy*physics.KcalToKJ)
+		}
+	}
+	if a.Trajectory.EnergyUnit != "" || b.Trajectory.EnergyUnit != "kJ/mol" {
+		t.Errorf("Trajectory units %q and %q, want \"\" and \"kJ/mol\"", a.Trajectory.EnergyUnit, b.Trajectory.EnergyUnit)
+	}
+}
@@ -64,7 +64,7 @@ func DefaultQuaternionSearchConfig() QuaternionSearchConfig {
 // MATHEMATICAL FOUNDATION:
 // - S³ hypersphere: Unit quaternions form 4D sphere
 // - Slerp: Great circle interpolation (geodesic path)
-// - Fibonacci sphere: Uniform point distribution on sphere
+// - Fibonacci sphere: Uniform point distribution on S³ (super-Fibonacci)
 //
 // BIOCHEMIST:
 // This explores diverse conformational basins while maintaining smooth transitions
@@ -86,10 +86,11 @@ func QuaternionGuidedSearch(initial *parser.Protein, config QuaternionSearchConf
 		return nil, fmt.Errorf("initial structure has no residues")
 	}
 
-	rand.Seed(config.Seed)
+	rng := rand.New(rand.NewSource(config.Seed))
 
-	// Step 1: Calculate current Ramachandran angles
-	currentAngles := geometry.CalculateRamachandran(initial)
+	// Step 1: Calculate current Ramachandran angles (undefined ones start
+	// extended, as the builder would place them)
+	currentAngles := dihedralState(initial)
 	if len(currentAngles) == 0 {
 		return nil, fmt.Errorf("failed to calculate Ramachandran angles")
 	}
@@ -105,7 +106,7 @@ func QuaternionGuidedSearch(initial *parser.Protein, config QuaternionSearchConf
 	if config.UseFibonacciSphere {
 		targetQuatSets = generateFibonacciTargets(currentQuats, config)
 	} else {
-		targetQuatSets = generateRandomTargets(currentQuats, config)
+		targetQuatSets = generateRandomTargets(currentQuats, config, rng)
 	}
 
 	// Step 4: Generate ensemble via slerp interpolation
@@ -150,93 +151,36 @@ func QuaternionGuidedSearch(initial *parser.Protein, config QuaternionSearchConf
 	return ensemble, nil
 }
 
-// generateFibonacciTargets creates uniformly distributed target quaternions using Fibonacci sphere
+// generateFibonacciTargets creates target quaternions from uniformly
+// distributed points on S³
 //
 // MATHEMATICIAN:
-// Fibonacci sphere algorithm distributes N points evenly on a sphere
-// - Golden angle: φ = π(3 - √5) ≈ 137.5°
-// - Each point: (cos(θ)sin(φ), sin(θ)sin(φ), cos(φ))
-// - Uniform density, no clustering at poles
-//
-// Extended to 4D (S³ hypersphere) by:
-// 1. Generate points on S² (3D sphere)
-// 2. Treat as quaternion components
-// 3. Normalize to unit quaternion
+// Takes config.NumSamples super-Fibonacci points
+// (geometry.FibonacciSphereQuaternions) and slerps each residue's current
+// quaternion towards one of them by PerturbRadius, like the random targets
+// but with even coverage. Residue r of sample k uses point (k + r) mod
+// NumSamples, so residues of one sample move in different directions and
+// each residue visits every point once over the samples.
 //
 // CITATION:
-// González, Á. (2010). "Measurement of areas on a sphere using Fibonacci and latitude–longitude lattices."
-// Mathematical Geosciences, 42(1), 49-64.
+// Alexa, M. (2022). "Super-Fibonacci spirals: Fast, low-discrepancy sampling
+// of SO(3)." Proc. IEEE/CVF CVPR, 8291-8300.
 func generateFibonacciTargets(currentQuats []geometry.Quaternion, config QuaternionSearchConfig) [][]geometry.Quaternion {
-	goldenAngle := math.Pi * (3.0 - math.Sqrt(5.0)) // ≈ 2.39996 radians ≈ 137.5°
-
-	targets := make([][]geometry.Quaternion, config.NumSamples)
+	points := geometry.FibonacciSphereQuaternions(config.NumSamples)
+	targets := make([][]geometry.Quaternion, len(points))
 
-	for sample := 0; sample < config.NumSamples; sample++ {
-		// Fibonacci sphere indices
-		i := float64(sample) + 0.5
-		n := float64(config.NumSamples)
-
-		// Spherical coordinates
-		phi := math.Acos(1.0 - 2.0*i/n)                  // Polar angle [0, π]
-		theta := goldenAngle * i                         // Azimuthal angle
-		theta = math.Mod(theta, 2.0*math.Pi)            // Wrap to [0, 2π]
-
-		// Perturb each residue's quaternion
+	for sample := range points {
 		sampleQuats := make([]geometry.Quaternion, len(currentQuats))
 		for resIdx, currentQ := range currentQuats {
-			// Generate target quaternion via Fibonacci perturbation
-			// Perturb in 4D space using spherical coordinates
-			perturbQ := fibonacciPerturbQuaternion(currentQ, phi, theta, config.PerturbRadius, resIdx, sample)
-			sampleQuats[resIdx] = perturbQ
+			point := points[(sample+resIdx)%len(points)]
+			sampleQuats[resIdx] = currentQ.Slerp(point, config.PerturbRadius)
 		}
-
 		targets[sample] = sampleQuats
 	}
 
 	return targets
 }
 
-// fibonacciPerturbQuaternion perturbs a quaternion using Fibonacci sphere direction
-//
-// MATHEMATICIAN:
-// - Map Fibonacci sphere point to 4D perturbation direction
-// - Scale by perturbRadius
-// - Add to current quaternion
-// - Normalize to stay on S³
-//
-// Per-residue variation: Use resIdx as seed for different perturbation patterns per residue
-func fibonacciPerturbQuaternion(q geometry.Quaternion, phi, theta, radius float64, resIdx, sampleIdx int) geometry.Quaternion {
-	// Add residue-specific variation to avoid uniform perturbations
-	resSeed := float64(resIdx) * 0.1
-	phi = math.Mod(phi+resSeed, math.Pi)
-	theta = math.Mod(theta+resSeed*2.0, 2.0*math.Pi)
-
-	// Convert spherical to Cartesian (3D)
-	x := math.Sin(phi) * math.Cos(theta)
-	y := math.Sin(phi) * math.Sin(theta)
-	z := math.Cos(phi)
-
-	// Add 4th dimension (W) for quaternion perturbation
-	// Use residue index modulo to create variation
-	w := math.Cos(float64(sampleIdx) * 0.1)
-
-	// Scale by perturbation radius
-	dx := x * radius
-	dy := y * radius
-	dz := z * radius
-	dw := w * radius
-
-	// Perturb and normalize
-	perturbed := geometry.Quaternion{
-		W: q.W + dw,
-		X: q.X + dx,
-		Y: q.Y + dy,
-		Z: q.Z + dz,
-	}
-
-	return perturbed.Normalize()
-}
-
 // generateRandomTargets creates randomly distributed target quaternions
 //
 // MATHEMATICIAN:
@@ -244,7 +188,7 @@ func fibonacciPerturbQuaternion(q geometry.Quaternion, phi, theta, radius float6
 // - Less uniform than Fibonacci sphere
 // - Faster to compute
 // - Useful for quick tests
-func generateRandomTargets(currentQuats []geometry.Quaternion, config QuaternionSearchConfig) [][]geometry.Quaternion {
+func generateRandomTargets(currentQuats []geometry.Quaternion, config QuaternionSearchConfig, rng *rand.Rand) [][]geometry.Quaternion {
 	targets := make([][]geometry.Quaternion, config.NumSamples)
 
 	for sample := 0; sample < config.NumSamples; sample++ {
@@ -253,10 +197,10 @@ func generateRandomTargets(currentQuats []geometry.Quaternion, config Quaternion
 		for resIdx, currentQ := range currentQuats {
 			// Random unit quaternion via rejection sampling
 			// Generate 4D Gaussian, normalize to unit sphere
-			w := rand.NormFloat64()
-			x := rand.NormFloat64()
-			y := rand.NormFloat64()
-			z := rand.NormFloat64()
+			w := rng.NormFloat64()
+			x := rng.NormFloat64()
+			y := rng.NormFloat64()
+			z := rng.NormFloat64()
 
 			randomQ := geometry.Quaternion{W: w, X: x, Y: y, Z: z}.Normalize()
 
@@ -320,6 +264,7 @@ func buildStructureFromAngles(template *parser.Protein, angles []geometry.Ramach
 		res := &parser.Residue{
 			Name:    templateRes.Name,
 			SeqNum:  templateRes.SeqNum,
+			ICode:   templateRes.ICode,
 			ChainID: templateRes.ChainID,
 		}
 
@@ -351,11 +351,16 @@ func anglesEqual(a1, a2 []geometry.RamachandranAngles, tolerance float64) bool {
 		return false
 	}
 
-	for i := range a1 {
-		if math.Abs(a1[i].Phi-a2[i].Phi) > tolerance {
-			return false
+	// Undefined (NaN) angles only equal undefined angles
+	differ := func(x, y float64) bool {
+		if math.IsNaN(x) || math.IsNaN(y) {
+			return math.IsNaN(x) != math.IsNaN(y)
 		}
-		if math.Abs(a1[i].Psi-a2[i].Psi) > tolerance {
+		return math.Abs(x-y) > tolerance
+	}
+
+	for i := range a1 {
+		if differ(a1[i].Phi, a2[i].Phi) || differ(a1[i].Psi, a2[i].Psi) {
 			return false
 		}
 	}
@@ -0,0 +1,247 @@
+// Package sampling - Replica Exchange Monte Carlo (parallel tempering)
+//
+// Single-temperature MC gets trapped in deep basins: at low T the barrier is
+// never crossed, at high T the minimum is never resolved. Replica exchange
+// runs one chain per temperature and lets configurations migrate between
+// temperatures, so hot replicas cross barriers and cold replicas refine.
+//
+// PHYSICIST: Swaps satisfy detailed balance in the extended ensemble
+// MATHEMATICIAN: Swap acceptance min(1, exp(Δβ·ΔE)) between neighbors
+// BIOCHEMIST: Hot replicas unfold/refold, cold replicas resolve basins
+// ETHICIST: Deterministic per-replica seeds make runs fully reproducible
+//
+// CITATION:
+// Swendsen, R. H., & Wang, J.-S. (1986). "Replica Monte Carlo simulation of spin-glasses."
+// Phys. Rev. Lett. 57(21): 2607-2609.
+//
+// Sugita, Y., & Okamoto, Y. (1999). "Replica-exchange molecular dynamics method for protein folding."
+// Chem. Phys. Lett. 314(1-2): 141-151.
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
+)
+
+// ReplicaStats holds statistics for one replica of a replica exchange run
+type ReplicaStats struct {
+	// Temperature of this replica (Kelvin)
+	Temperature float64
+
+	// Metropolis move statistics
+	NumAccepted    int
+	NumRejected    int
+	AcceptanceRate float64
+
+	// Swap attempts with the next-hotter replica
+	SwapAttempts       int
+	SwapAccepted       int
+	SwapAcceptanceRate float64
+
+	// Best energy visited at this temperature
+	BestEnergy float64
+}
+
+// replicaScorer returns (energy, Vedic score) for a structure
+type replicaScorer func(protein *parser.Protein) (float64, float64)
+
+// replica is the state of one temperature chain
+type replica struct {
+	T       float64
+	rng     *rand.Rand
+	current *parser.Protein
+	angles  []geometry.RamachandranAngles
+	energy  float64
+	vedic   float64
+	score   float64
+	stats   ReplicaStats
+
+	best       *parser.Protein
+	bestScore  float64
+	bestEnergy float64
+	bestVedic  float64
+}
+
+// ReplicaExchangeVedic runs replica exchange Monte Carlo with Vedic biasing
+//
+// ALGORITHM:
+//  1. Start one replica per temperature from the initial structure
+//  2. Run SwapInterval MC steps on every replica concurrently
+//  3. Attempt swaps between neighboring temperatures (i, i+1):
+//     accept with min(1, exp((β_i - β_{i+1}) × (S_i - S_{i+1})))
+//  4. Repeat until NumSteps per replica, return best structure overall
+//
+// Each replica uses its own RNG seeded with config.Seed + index, and swaps
+// use config.Seed + len(temps), so results are reproducible regardless of
+// goroutine scheduling. The cooling schedule is ignored: temperatures are fixed.
+func ReplicaExchangeVedic(initial *parser.Protein, temps []float64, config MonteCarloConfig) (*MonteCarloResult, error) {
+	return replicaExchange(initial, temps, config, func(protein *parser.Protein) (float64, float64) {
+		energy := calculateTotalEnergy(protein, config)
+		angles := geometry.CalculateRamachandran(protein)
+		return energy, vedicTerm(vedic.CalculateVedicScore(protein, angles), angles, config)
+	})
+}
+
+// replicaExchange implements ReplicaExchangeVedic for an arbitrary scorer
+func replicaExchange(initial *parser.Protein, temps []float64, config MonteCarloConfig, scorer replicaScorer) (*MonteCarloResult, error) {
+	if initial == nil {
+		return nil, fmt.Errorf("initial structure is nil")
+	}
+	if len(temps) == 0 {
+		return nil, fmt.Errorf("no replica temperatures given")
+	}
+	if err := validateMoveType(config.MoveType); err != nil {
+		return nil, err
+	}
+	for i, T := range temps {
+		if T <= 0 {
+			return nil, fmt.Errorf("replica %d has non-positive temperature %.2f", i, T)
+		}
+	}
+
+	swapInterval := config.SwapInterval
+	if swapInterval <= 0 {
+		swapInterval = 10
+	}
+
+	initialEnergy, initialVedic := scorer(initial)
+	initialScore := combinedScore(initialEnergy, initialVedic, config.VedicWeight)
+
+	replicas := make([]*replica, len(temps))
+	for i, T := range temps {
+		replicas[i] = &replica{
+			T:          T,
+			rng:        rand.New(rand.NewSource(config.Seed + int64(i))),
+			current:    cloneProteinDeep(initial),
+			energy:     initialEnergy,
+			vedic:      initialVedic,
+			score:      initialScore,
+			stats:      ReplicaStats{Temperature: T, BestEnergy: initialEnergy},
+			best:       cloneProteinDeep(initial),
+			bestScore:  initialScore,
+			bestEnergy: initialEnergy,
+			bestVedic:  initialVedic,
+		}
+	}
+	swapRng := rand.New(rand.NewSource(config.Seed + int64(len(temps))))
+
+	const kB = physics.BoltzmannKcal // kcal/(mol·K)
+
+	for done := 0; done < config.NumSteps; done += swapInterval {
+		steps := swapInterval
+		if done+steps > config.NumSteps {
+			steps = config.NumSteps - done
+		}
+
+		// Propagate all replicas concurrently
+		var wg sync.WaitGroup
+		for _, r := range replicas {
+			wg.Add(1)
+			go func(r *replica) {
+				defer wg.Done()
+				r.run(steps, config, scorer)
+			}(r)
+		}
+		wg.Wait()
+
+		// Neighbor swaps (sequential, deterministic order)
+		for i := 0; i < len(replicas)-1; i++ {
+			a, b := replicas[i], replicas[i+1]
+			a.stats.SwapAttempts++
+
+			deltaBeta := 1.0/(kB*a.T) - 1.0/(kB*b.T)
+			deltaScore := a.score - b.score
+			arg := deltaBeta * deltaScore
+
+			if arg >= 0 || swapRng.Float64() < math.Exp(arg) {
+				a.current, b.current = b.current, a.current
+				a.angles, b.angles = b.angles, a.angles
+				a.energy, b.energy = b.energy, a.energy
+				a.vedic, b.vedic = b.vedic, a.vedic
+				a.score, b.score = b.score, a.score
+				a.stats.SwapAccepted++
+			}
+		}
+	}
+
+	result := &MonteCarloResult{
+		InitialEnergy:     initialEnergy,
+		InitialVedicScore: initialVedic,
+		Replicas:          make([]ReplicaStats, len(replicas)),
+		ConvergenceStep:   config.NumSteps,
+	}
+
+	bestIdx := 0
+	for i, r := range replicas {
+		total := r.stats.NumAccepted + r.stats.NumRejected
+		if total > 0 {
+			r.stats.AcceptanceRate = float64(r.stats.NumAccepted) / float64(total)
+		}
+		if r.stats.SwapAttempts > 0 {
+			r.stats.SwapAcceptanceRate = float64(r.stats.SwapAccepted) / float64(r.stats.SwapAttempts)
+		}
+		result.Replicas[i] = r.stats
+		result.NumAccepted += r.stats.NumAccepted
+		result.NumRejected += r.stats.NumRejected
+
+		if r.bestScore < replicas[bestIdx].bestScore {
+			bestIdx = i
+		}
+	}
+
+	total := result.NumAccepted + result.NumRejected
+	if total > 0 {
+		result.AcceptanceRate = float64(result.NumAccepted) / float64(total)
+	}
+
+	best := replicas[bestIdx]
+	result.FinalStructure = best.best
+	result.FinalEnergy = best.bestEnergy
+	result.FinalVedicScore = best.bestVedic
+	result.BestEnergy = best.bestEnergy
+	result.BestVedicScore = best.bestVedic
+	result.convertUnits(config.EnergyUnits)
+
+	return result, nil
+}
+
+// run performs steps Metropolis moves at the replica's fixed temperature
+func (r *replica) run(steps int, config MonteCarloConfig, scorer replicaScorer) {
+	const kB = physics.BoltzmannKcal // kcal/(mol·K)
+
+	for step := 0; step < steps; step++ {
+		proposed, proposedAngles := proposeMove(r.current, r.angles, config, r.rng)
+
+		energy, vedicScore := scorer(proposed)
+		score := combinedScore(energy, vedicScore, config.VedicWeight)
+
+		delta := score - r.score
+		if delta < 0 || r.rng.Float64() < math.Exp(-delta/(kB*r.T)) {
+			r.current = proposed
+			r.angles = proposedAngles
+			r.energy = energy
+			r.vedic = vedicScore
+			r.score = score
+			r.stats.NumAccepted++
+
+			if energy < r.stats.BestEnergy {
+				r.stats.BestEnergy = energy
+			}
+			if score < r.bestScore {
+				r.best = cloneProteinDeep(proposed)
+				r.bestScore = score
+				r.bestEnergy = energy
+				r.bestVedic = vedicScore
+			}
+		} else {
+			r.stats.NumRejected++
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package sampling
+
+import (
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// doubleWellScorer is E(x) = 10(x² - 1)² - x on the first atom's X coordinate
+//
+// Local minimum at x ≈ -1 (E ≈ +1), global minimum at x ≈ +1 (E ≈ -1),
+// separated by a ~10 kcal/mol barrier at x = 0.
+func doubleWellScorer(protein *parser.Protein) (float64, float64) {
+	x := protein.Atoms[0].X
+	return 10.0*(x*x-1.0)*(x*x-1.0) - x, 0.0
+}
+
+func newDoubleWellParticle() *parser.Protein {
+	atom := &parser.Atom{Serial: 1, Name: "CA", ResName: "ALA", ChainID: "A", ResSeq: 1, X: -1.0, Element: "C"}
+	return &parser.Protein{
+		Name:     "double_well",
+		Residues: []*parser.Residue{{Name: "ALA", SeqNum: 1, ChainID: "A", CA: atom}},
+		Atoms:    []*parser.Atom{atom},
+	}
+}
+
+// TestReplicaExchangeDoubleWell checks REMC escapes the local well more
+// reliably than single-temperature MC at the lowest temperature
+func TestReplicaExchangeDoubleWell(t *testing.T) {
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 500
+	config.MoveType = "cartesian"
+	config.StepSize = 0.2
+	config.VedicWeight = 0.0
+
+	temps := []float64{100, 300, 1000, 3000, 10000}
+	const trials = 10
+
+	singleHits, remcHits := 0, 0
+	for trial := 0; trial < trials; trial++ {
+		config.Seed = int64(1000 + trial)
+
+		single, err := replicaExchange(newDoubleWellParticle(), temps[:1], config, doubleWellScorer)
+		if err != nil {
+			t.Fatalf("single-temperature MC failed: %v", err)
+		}
+		if single.FinalStructure.Atoms[0].X > 0 {
+			singleHits++
+		}
+
+		remc, err := replicaExchange(newDoubleWellParticle(), temps, config, doubleWellScorer)
+		if err != nil {
+			t.Fatalf("replica exchange failed: %v", err)
+		}
+		if remc.FinalStructure.Atoms[0].X > 0 {
+			remcHits++
+		}
+
+		if len(remc.Replicas) != len(temps) {
+			t.Fatalf("Expected %d replica stats, got %d", len(temps), len(remc.Replicas))
+		}
+	}
+
+	t.Logf("Global minimum found: single-T %d/%d, REMC %d/%d", singleHits, trials, remcHits, trials)
+
+	if remcHits <= singleHits {
+		t.Errorf("REMC (%d/%d) should beat single-temperature MC (%d/%d)", remcHits, trials, singleHits, trials)
+	}
+	if remcHits < trials*8/10 {
+		t.Errorf("REMC found global minimum in only %d/%d trials", remcHits, trials)
+	}
+}
+
+// TestReplicaExchangeReproducible checks identical seeds give identical results
+func TestReplicaExchangeReproducible(t *testing.T) {
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 200
+	config.MoveType = "cartesian"
+	config.StepSize = 0.2
+	config.VedicWeight = 0.0
+	temps := []float64{100, 1000, 10000}
+
+	r1, err := replicaExchange(newDoubleWellParticle(), temps, config, doubleWellScorer)
+	if err != nil {
+		t.Fatalf("replica exchange failed: %v", err)
+	}
+	r2, err := replicaExchange(newDoubleWellParticle(), temps, config, doubleWellScorer)
+	if err != nil {
+		t.Fatalf("replica exchange failed: %v", err)
+	}
+
+	if r1.FinalEnergy != r2.FinalEnergy {
+		t.Errorf("Non-reproducible: %.6f vs %.6f", r1.FinalEnergy, r2.FinalEnergy)
+	}
+	for i := range r1.Replicas {
+		if r1.Replicas[i] != r2.Replicas[i] {
+			t.Errorf("Replica %d stats differ: %+v vs %+v", i, r1.Replicas[i], r2.Replicas[i])
+		}
+		t.Logf("T=%6.0f K: accept %.2f, swap accept %.2f",
+			r1.Replicas[i].Temperature, r1.Replicas[i].AcceptanceRate, r1.Replicas[i].SwapAcceptanceRate)
+	}
+}
+
+// TestReplicaExchangeVedic runs the full-energy entry point on a small chain
+func TestReplicaExchangeVedic(t *testing.T) {
+	config := DefaultMonteCarloConfig()
+	config.NumSteps = 20
+	config.StepSize = 0.1
+
+	result, err := ReplicaExchangeVedic(createTestProtein(4), []float64{300, 600}, config)
+	if err != nil {
+		t.Fatalf("ReplicaExchangeVedic failed: %v", err)
+	}
+	if result.FinalStructure == nil || result.FinalEnergy > result.InitialEnergy {
+		t.Errorf("Best energy %.2f should not exceed initial %.2f", result.FinalEnergy, result.InitialEnergy)
+	}
+
+	if _, err := ReplicaExchangeVedic(createTestProtein(4), nil, config); err == nil {
+		t.Error("Expected error for empty temperature ladder")
+	}
+}
@@ -0,0 +1,168 @@
+// Package scoring - Weighted multi-objective structure score
+//
+// Picking a model means trading off quantities in different units: force-
+// field energy (kcal/mol, unbounded), Vedic harmony [0, 1], how many predicted
+// contacts are formed, Ramachandran quality and steric clashes. Adding them
+// raw (energy - 1000 × Vedic) lets whichever has the largest numbers decide.
+// CompositeScore first maps every term onto [0, 1], 1 best, then takes a
+// weighted sum, and reports each term so a ranking can be explained.
+//
+// BIOCHEMIST: Energy alone over-rewards collapsed, strained models; contacts,
+// Ramachandran and clashscore catch what the force field misses
+// MATHEMATICIAN: Terms are monotone maps to [0, 1] centred on typical
+// values, so a weight is the term's share of the score
+// ETHICIST: The breakdown is returned with the total - no unexplained number
+//
+// CITATION:
+// Word, J. M., et al. (1999). "Visualizing and quantifying molecular
+// goodness-of-fit: small-probe contact dots with explicit hydrogen atoms."
+// J. Mol. Biol. 285(4): 1711-1733.
+// Lovell, S. C., et al. (2003). "Structure validation by Cα geometry: φ,ψ
+// and Cβ deviation." Proteins 50(3): 437-450.
+package scoring
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/physics"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/validation"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
+)
+
+// Normalization scales
+const (
+	EnergyScale      = 5.0  // Energy per residue (kcal/mol) of the logistic energy term
+	ClashscoreScale  = 20.0 // Clashscore giving a clash term of 0.5 (a typical raw prediction)
+	ContactThreshold = 8.0  // Cα-Cα distance (Å) below which a contact is formed
+)
+
+// ScoreWeights weights the normalized terms of CompositeScore; a zero weight
+// drops the term
+type ScoreWeights struct {
+	Energy       float64 // Force-field energy per residue
+	Vedic        float64 // vedic.CalculateVedicScore total
+	Contacts     float64 // Score-weighted fraction of predicted contacts formed
+	Ramachandran float64 // Fraction of residues inside the allowed contour
+	Clash        float64 // MolProbity-style clashscore
+}
+
+// DefaultScoreWeights returns weights led by energy, with the geometric
+// checks together as heavy and Vedic a tie-breaker
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		Energy:       0.4,
+		Vedic:        0.1,
+		Contacts:     0.2,
+		Ramachandran: 0.15,
+		Clash:        0.15,
+	}
+}
+
+// ScoreTerms holds one value per term of CompositeScore
+type ScoreTerms struct {
+	Energy       float64
+	Vedic        float64
+	Contacts     float64
+	Ramachandran float64
+	Clash        float64
+}
+
+// Sum adds the terms
+func (t ScoreTerms) Sum() float64 {
+	return t.Energy + t.Vedic + t.Contacts + t.Ramachandran + t.Clash
+}
+
+// CompositeResult is a composite score and its breakdown
+type CompositeResult struct {
+	Total    float64    // Weighted sum; higher is better
+	Terms    ScoreTerms // Normalized terms in [0, 1]
+	Weighted ScoreTerms // Weight × term; sums to Total
+
+	// Raw inputs of the terms
+	Energy               float64 // Total force-field energy (kcal/mol)
+	VedicScore           float64
+	ContactSatisfaction  float64 // 0 with no contacts
+	RamachandranOutliers int
+	Clashscore           float64 // Clashes per 1000 atoms
+}
+
+// CompositeScore scores a structure by the weighted sum of its normalized
+// energy, Vedic, contact, Ramachandran and clash terms
+//
+// contacts are the predicted contacts to check (nil gives a contact term of
+// 0). Terms with zero weight are not computed.
+//
+// Normalization (each in [0, 1], 1 best):
+//   - Energy: 1 / (1 + exp(E/N / EnergyScale)), N residues
+//   - Vedic: the score itself
+//   - Contacts: Σ score over formed contacts / Σ score
+//   - Ramachandran: 1 - outliers / scored residues
+//   - Clash: 1 / (1 + clashscore / ClashscoreScale)
+func CompositeScore(protein *parser.Protein, weights ScoreWeights, contacts []prediction.ContactPrediction) CompositeResult {
+	var result CompositeResult
+	if protein == nil || len(protein.Residues) == 0 {
+		return result
+	}
+	n := float64(len(protein.Residues))
+
+	if weights.Energy != 0 {
+		result.Energy = physics.CalculateTotalEnergyWithConfig(protein, physics.DefaultEnergyConfig()).Total
+		result.Terms.Energy = 1 / (1 + math.Exp(result.Energy/n/EnergyScale))
+	}
+	if weights.Vedic != 0 {
+		result.VedicScore = vedic.CalculateVedicScore(protein, geometry.CalculateRamachandran(protein)).TotalScore
+		result.Terms.Vedic = result.VedicScore
+	}
+	if weights.Contacts != 0 {
+		result.ContactSatisfaction = ContactSatisfaction(protein, contacts)
+		result.Terms.Contacts = result.ContactSatisfaction
+	}
+	if weights.Ramachandran != 0 {
+		_, outliers := validation.RamachandranScore(protein)
+		result.RamachandranOutliers = len(outliers)
+		if scored := len(protein.Residues) - 2; scored > 0 {
+			result.Terms.Ramachandran = math.Max(0, 1-float64(len(outliers))/float64(scored))
+		}
+	}
+	if weights.Clash != 0 {
+		result.Clashscore, _ = physics.CalculateClashscore(protein)
+		result.Terms.Clash = 1 / (1 + result.Clashscore/ClashscoreScale)
+	}
+
+	result.Weighted = ScoreTerms{
+		Energy:       weights.Energy * result.Terms.Energy,
+		Vedic:        weights.Vedic * result.Terms.Vedic,
+		Contacts:     weights.Contacts * result.Terms.Contacts,
+		Ramachandran: weights.Ramachandran * result.Terms.Ramachandran,
+		Clash:        weights.Clash * result.Terms.Clash,
+	}
+	result.Total = result.Weighted.Sum()
+	return result
+}
+
+// ContactSatisfaction returns the score-weighted fraction of contacts whose
+// Cα atoms are within ContactThreshold; 0 with no usable contacts
+func ContactSatisfaction(protein *parser.Protein, contacts []prediction.ContactPrediction) float64 {
+	formed, total := 0.0, 0.0
+	for _, c := range contacts {
+		if c.Residue1 < 0 || c.Residue2 < 0 || c.Residue1 >= len(protein.Residues) || c.Residue2 >= len(protein.Residues) {
+			continue
+		}
+		a, b := protein.Residues[c.Residue1].CA, protein.Residues[c.Residue2].CA
+		if a == nil || b == nil {
+			continue
+		}
+		total += c.Score
+		dx, dy, dz := a.X-b.X, a.Y-b.Y, a.Z-b.Z
+		if math.Sqrt(dx*dx+dy*dy+dz*dz) <= ContactThreshold {
+			formed += c.Score
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return formed / total
+}
@@ -0,0 +1,109 @@
+package scoring
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/vedic"
+)
+
+// scoringTestEnsemble builds a helix, a strand and random-angle decoys
+func scoringTestEnsemble(t *testing.T, sequence string, decoys int) []*parser.Protein {
+	t.Helper()
+	deg := math.Pi / 180.0
+	rng := rand.New(rand.NewSource(3))
+
+	var ensemble []*parser.Protein
+	for k := 0; k < decoys+2; k++ {
+		angles := make([]geometry.RamachandranAngles, len(sequence))
+		for i := range angles {
+			switch k {
+			case 0:
+				angles[i] = geometry.RamachandranAngles{Phi: -57 * deg, Psi: -47 * deg}
+			case 1:
+				angles[i] = geometry.RamachandranAngles{Phi: -120 * deg, Psi: 130 * deg}
+			default:
+				angles[i] = geometry.RamachandranAngles{Phi: (rng.Float64()*360 - 180) * deg, Psi: (rng.Float64()*360 - 180) * deg}
+			}
+		}
+		protein, err := geometry.BuildProteinFromAngles(sequence, angles)
+		if err != nil {
+			t.Fatalf("BuildProteinFromAngles failed: %v", err)
+		}
+		ensemble = append(ensemble, protein)
+	}
+	return ensemble
+}
+
+// ranking returns indices sorted by descending value
+func ranking(values []float64) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] > values[order[b]] })
+	return order
+}
+
+// TestCompositeScore checks a Vedic-only weighting ranks exactly as the Vedic
+// score does, and that with every weight on the breakdown sums to the total
+// and each term lies in [0, 1]
+func TestCompositeScore(t *testing.T) {
+	const sequence = "AKLVEGLAKEAGKLW"
+	ensemble := scoringTestEnsemble(t, sequence, 6)
+
+	vedicOnly := ScoreWeights{Vedic: 1}
+	composite := make([]float64, len(ensemble))
+	pure := make([]float64, len(ensemble))
+	for i, protein := range ensemble {
+		result := CompositeScore(protein, vedicOnly, nil)
+		composite[i] = result.Total
+		pure[i] = vedic.CalculateVedicScore(protein, geometry.CalculateRamachandran(protein)).TotalScore
+		if result.Total != pure[i] {
+			t.Errorf("Structure %d: Vedic-only composite %.6f, Vedic score %.6f", i, result.Total, pure[i])
+		}
+	}
+	got, want := ranking(composite), ranking(pure)
+	t.Logf("Vedic-only ranking %v, Vedic ranking %v", got, want)
+	for k := range want {
+		if got[k] != want[k] {
+			t.Errorf("Vedic-only ranking %v differs from Vedic ranking %v", got, want)
+			break
+		}
+	}
+
+	// Helix contacts i→i+3 formed, i→i+12 not: half the weight satisfied
+	contacts := []prediction.ContactPrediction{
+		{Residue1: 2, Residue2: 5, Score: 0.5},
+		{Residue1: 1, Residue2: 13, Score: 0.5},
+	}
+	weights := DefaultScoreWeights()
+	for i, protein := range ensemble {
+		result := CompositeScore(protein, weights, contacts)
+		terms := []float64{result.Terms.Energy, result.Terms.Vedic, result.Terms.Contacts, result.Terms.Ramachandran, result.Terms.Clash}
+		for _, term := range terms {
+			if term < 0 || term > 1 || math.IsNaN(term) {
+				t.Errorf("Structure %d: term outside [0, 1]: %+v", i, result.Terms)
+				break
+			}
+		}
+		if math.Abs(result.Weighted.Sum()-result.Total) > 1e-12 {
+			t.Errorf("Structure %d: breakdown sums to %.6f, total %.6f", i, result.Weighted.Sum(), result.Total)
+		}
+		if math.Abs(result.Weighted.Clash-weights.Clash*result.Terms.Clash) > 1e-12 {
+			t.Errorf("Structure %d: weighted clash %.6f ≠ %.2f × %.6f", i, result.Weighted.Clash, weights.Clash, result.Terms.Clash)
+		}
+		if i == 0 {
+			t.Logf("Helix: total %.4f, terms %+v, E = %.1f kcal/mol, clashscore %.1f",
+				result.Total, result.Terms, result.Energy, result.Clashscore)
+			if result.ContactSatisfaction != 0.5 {
+				t.Errorf("Helix contact satisfaction %.3f, want 0.5", result.ContactSatisfaction)
+			}
+		}
+	}
+}
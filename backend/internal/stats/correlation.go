@@ -0,0 +1,72 @@
+// Package stats - Correlation coefficients
+//
+// Whether energy tracks RMSD across an ensemble, or a confidence score
+// tracks real error, is a correlation question. Pearson measures linear
+// agreement; Spearman measures whether the two orderings agree, which is
+// what matters for ranking models by energy.
+//
+// MATHEMATICIAN: Spearman's ρ is Pearson's r of the ranks; tied values
+// share the mean of the ranks they span
+//
+// CITATION:
+// Spearman, C. (1904). "The proof and measurement of association between
+// two things." Am. J. Psychol. 15(1): 72-101.
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Pearson returns the Pearson correlation coefficient of x and y in
+// [-1, 1]; 0 if they differ in length, have fewer than two values, or
+// either is constant
+func Pearson(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0
+	}
+	mx, my := Mean(x), Mean(y)
+	sxy, sxx, syy := 0.0, 0.0, 0.0
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return math.Max(-1, math.Min(1, sxy/math.Sqrt(sxx*syy)))
+}
+
+// Spearman returns the Spearman rank correlation of x and y in [-1, 1],
+// with the same degenerate cases as Pearson
+func Spearman(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0
+	}
+	return Pearson(Ranks(x), Ranks(y))
+}
+
+// Ranks returns the 1-based rank of each value, ties sharing their mean rank
+func Ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		mean := float64(start+end+1) / 2 // Mean of ranks start+1 .. end
+		for k := start; k < end; k++ {
+			ranks[order[k]] = mean
+		}
+		start = end
+	}
+	return ranks
+}
@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCorrelation checks Pearson and Spearman on linear, monotone and
+// degenerate data
+func TestCorrelation(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	if r := Pearson(x, []float64{3, 5, 7, 9, 11}); math.Abs(r-1) > 1e-12 {
+		t.Errorf("Pearson of a line %.6f, want 1", r)
+	}
+	if r := Pearson(x, []float64{5, 4, 3, 2, 1}); math.Abs(r+1) > 1e-12 {
+		t.Errorf("Pearson of a falling line %.6f, want -1", r)
+	}
+
+	// Monotone but not linear: Spearman 1, Pearson below it
+	cubic := []float64{1, 8, 27, 64, 125}
+	p, s := Pearson(x, cubic), Spearman(x, cubic)
+	t.Logf("x vs x³: Pearson %.4f, Spearman %.4f", p, s)
+	if math.Abs(s-1) > 1e-12 || p >= s {
+		t.Errorf("Pearson %.4f, Spearman %.4f; want Spearman 1 above Pearson", p, s)
+	}
+
+	ranks := Ranks([]float64{10, 20, 20, 5})
+	for i, want := range []float64{2, 3.5, 3.5, 1} {
+		if ranks[i] != want {
+			t.Errorf("Ranks %v, want tied values to share rank 3.5", ranks)
+			break
+		}
+	}
+
+	if r := Pearson(x, []float64{2, 2, 2, 2, 2}); r != 0 {
+		t.Errorf("Pearson with a constant %.3f, want 0", r)
+	}
+	if r := Spearman(x, x[:3]); r != 0 {
+		t.Errorf("Spearman of mismatched lengths %.3f, want 0", r)
+	}
+}
@@ -0,0 +1,196 @@
+// Package stats - Streaming accumulators
+//
+// Mean, StdDev and Percentile need every value in memory. RunningStats and
+// P2Quantile take the values one at a time in O(1) memory, so a long
+// benchmark can summarize as results arrive instead of holding them all.
+//
+// MATHEMATICIAN: RunningStats uses Welford's update, which never subtracts
+// two large running sums and so stays accurate where Σx² - n·mean² cancels
+// catastrophically. P2Quantile tracks five markers whose heights follow the
+// quantile by piecewise-parabolic (P²) interpolation; it is exact for up to
+// five values and approximate after.
+//
+// CITATION:
+// Welford, B. P. (1962). "Note on a method for calculating corrected sums of
+// squares and products." Technometrics 4(3): 419-420.
+// Jain, R., & Chlamtac, I. (1985). "The P² algorithm for dynamic calculation
+// of quantiles and histograms without storing observations." Commun. ACM
+// 28(10): 1076-1085.
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// RunningStats accumulates count, mean, variance, minimum and maximum of a
+// stream of values; the zero value is empty and ready to use
+type RunningStats struct {
+	n        int
+	mean     float64
+	m2       float64 // Σ (x - mean)², updated incrementally
+	min, max float64
+}
+
+// Add adds x to the stream
+func (s *RunningStats) Add(x float64) {
+	s.n++
+	if s.n == 1 {
+		s.min, s.max = x, x
+	} else {
+		s.min = math.Min(s.min, x)
+		s.max = math.Max(s.max, x)
+	}
+	delta := x - s.mean
+	s.mean += delta / float64(s.n)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Count returns the number of values added
+func (s *RunningStats) Count() int {
+	return s.n
+}
+
+// Mean returns the arithmetic mean (0 for none), as Mean
+func (s *RunningStats) Mean() float64 {
+	return s.mean
+}
+
+// Variance returns the population variance (divide by n; 0 for none), the
+// square of StdDev
+func (s *RunningStats) Variance() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return s.m2 / float64(s.n)
+}
+
+// SampleVariance returns the unbiased sample variance (divide by n - 1; 0
+// for fewer than two values)
+func (s *RunningStats) SampleVariance() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+// StdDev returns the population standard deviation, as StdDev
+func (s *RunningStats) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Min returns the smallest value added (0 for none)
+func (s *RunningStats) Min() float64 {
+	return s.min
+}
+
+// Max returns the largest value added (0 for none)
+func (s *RunningStats) Max() float64 {
+	return s.max
+}
+
+// P2Quantile estimates one percentile of a stream in constant memory with
+// the P² algorithm
+type P2Quantile struct {
+	p       float64    // Quantile as a fraction in [0, 1]
+	count   int        // Values added
+	heights [5]float64 // Marker heights q_i
+	pos     [5]float64 // Actual marker positions n_i (0-based)
+	desired [5]float64 // Desired marker positions n'_i
+	step    [5]float64 // Desired position increments dn'_i
+}
+
+// NewP2Quantile returns an estimator of the p-th percentile, p in [0, 100]
+// (clamped) as in Percentile
+func NewP2Quantile(p float64) *P2Quantile {
+	f := math.Max(0, math.Min(p, 100)) / 100
+	return &P2Quantile{
+		p:       f,
+		pos:     [5]float64{0, 1, 2, 3, 4},
+		desired: [5]float64{0, 2 * f, 4 * f, 2 + 2*f, 4},
+		step:    [5]float64{0, f / 2, f, (1 + f) / 2, 1},
+	}
+}
+
+// Add adds x to the stream
+func (q *P2Quantile) Add(x float64) {
+	if q.count < 5 {
+		q.heights[q.count] = x
+		q.count++
+		if q.count == 5 {
+			sort.Float64s(q.heights[:])
+		}
+		return
+	}
+	q.count++
+
+	// Cell k with heights[k] <= x < heights[k+1], stretching the extremes
+	var k int
+	switch {
+	case x < q.heights[0]:
+		q.heights[0] = x
+		k = 0
+	case x >= q.heights[4]:
+		q.heights[4] = x
+		k = 3
+	default:
+		for x >= q.heights[k+1] { // Stops by k = 3: x < heights[4]
+			k++
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		q.pos[i]++
+	}
+	for i := range q.desired {
+		q.desired[i] += q.step[i]
+	}
+
+	// Move the middle markers one position toward where they should be
+	for i := 1; i <= 3; i++ {
+		d := q.desired[i] - q.pos[i]
+		if (d >= 1 && q.pos[i+1]-q.pos[i] > 1) || (d <= -1 && q.pos[i-1]-q.pos[i] < -1) {
+			d = math.Copysign(1, d)
+			h := q.parabolic(i, d)
+			if !(q.heights[i-1] < h && h < q.heights[i+1]) {
+				h = q.linear(i, d)
+			}
+			q.heights[i] = h
+			q.pos[i] += d
+		}
+	}
+}
+
+// parabolic is the P² piecewise-parabolic height of marker i moved by d
+func (q *P2Quantile) parabolic(i int, d float64) float64 {
+	n, h := q.pos, q.heights
+	return h[i] + d/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+d)*(h[i+1]-h[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-d)*(h[i]-h[i-1])/(n[i]-n[i-1]))
+}
+
+// linear is the linear-interpolation fallback for marker i moved by d
+func (q *P2Quantile) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return q.heights[i] + d*(q.heights[j]-q.heights[i])/(q.pos[j]-q.pos[i])
+}
+
+// Count returns the number of values added
+func (q *P2Quantile) Count() int {
+	return q.count
+}
+
+// Value returns the current estimate of the percentile (0 for no values);
+// exact, as Percentile, for up to five values
+func (q *P2Quantile) Value() float64 {
+	if q.count == 0 {
+		return 0
+	}
+	if q.count <= 5 {
+		sorted := make([]float64, q.count)
+		copy(sorted, q.heights[:q.count])
+		sort.Float64s(sorted)
+		return sortedPercentile(sorted, q.p*100)
+	}
+	return q.heights[2]
+}
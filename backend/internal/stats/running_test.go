@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRunningStats(t *testing.T) {
+	var empty RunningStats
+	if empty.Count() != 0 || empty.Mean() != 0 || empty.Variance() != 0 || empty.Min() != 0 || empty.Max() != 0 {
+		t.Errorf("Empty RunningStats: %+v, want all zero", empty)
+	}
+
+	// Large offset: the naive Σx² - n·mean² would lose most digits here
+	rng := rand.New(rand.NewSource(7))
+	values := make([]float64, 1000)
+	var s RunningStats
+	for i := range values {
+		values[i] = 1e6 + 10*rng.NormFloat64()
+		s.Add(values[i])
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		min, max = math.Min(min, v), math.Max(max, v)
+	}
+	batchSD := StdDev(values)
+	n := float64(len(values))
+
+	t.Logf("n=%d mean=%.6f (batch %.6f) sd=%.9f (batch %.9f)", s.Count(), s.Mean(), Mean(values), s.StdDev(), batchSD)
+	if s.Count() != len(values) {
+		t.Errorf("Count = %d, want %d", s.Count(), len(values))
+	}
+	if math.Abs(s.Mean()-Mean(values)) > 1e-9 {
+		t.Errorf("Mean = %.12f, batch %.12f", s.Mean(), Mean(values))
+	}
+	if math.Abs(s.Variance()-batchSD*batchSD) > 1e-9*batchSD*batchSD {
+		t.Errorf("Variance = %.12f, batch %.12f", s.Variance(), batchSD*batchSD)
+	}
+	if want := batchSD * batchSD * n / (n - 1); math.Abs(s.SampleVariance()-want) > 1e-9*want {
+		t.Errorf("SampleVariance = %.12f, want %.12f", s.SampleVariance(), want)
+	}
+	if s.Min() != min || s.Max() != max {
+		t.Errorf("Min/Max = %.6f/%.6f, want %.6f/%.6f", s.Min(), s.Max(), min, max)
+	}
+}
+
+func TestP2Quantile(t *testing.T) {
+	// Up to five values: exact, as Percentile
+	small := []float64{4, 1, 3}
+	q := NewP2Quantile(50)
+	if q.Value() != 0 {
+		t.Errorf("Empty estimate %.3f, want 0", q.Value())
+	}
+	for _, v := range small {
+		q.Add(v)
+	}
+	if q.Value() != Median(small) {
+		t.Errorf("Median of %v: %.3f, want %.3f", small, q.Value(), Median(small))
+	}
+
+	rng := rand.New(rand.NewSource(3))
+	values := make([]float64, 1000)
+	estimators := map[float64]*P2Quantile{10: NewP2Quantile(10), 50: NewP2Quantile(50), 90: NewP2Quantile(90)}
+	for i := range values {
+		values[i] = rng.Float64()
+		for _, e := range estimators {
+			e.Add(values[i])
+		}
+	}
+
+	for p, e := range estimators {
+		exact := Percentile(values, p)
+		t.Logf("P%.0f: P² %.4f, exact %.4f", p, e.Value(), exact)
+		if e.Count() != len(values) {
+			t.Errorf("P%.0f: Count = %d, want %d", p, e.Count(), len(values))
+		}
+		// Uniform [0, 1]: the estimate is within a couple of percentiles
+		if math.Abs(e.Value()-exact) > 0.02 {
+			t.Errorf("P%.0f: P² estimate %.4f, exact %.4f", p, e.Value(), exact)
+		}
+	}
+}
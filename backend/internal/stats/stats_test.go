@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMean(t *testing.T) {
+	if m := Mean([]float64{1, 2, 3, 6}); m != 3 {
+		t.Errorf("Mean = %.3f, want 3", m)
+	}
+	if m := Mean(nil); m != 0 {
+		t.Errorf("Mean of nothing = %.3f, want 0", m)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	odd := []float64{9, 1, 5, 3, 7}
+	if m := Median(odd); m != 5 {
+		t.Errorf("Odd-length median = %.3f, want 5", m)
+	}
+	if odd[0] != 9 {
+		t.Error("Median sorted its input in place")
+	}
+	if m := Median([]float64{4, 1, 3, 2}); m != 2.5 {
+		t.Errorf("Even-length median = %.3f, want 2.5", m)
+	}
+	if m := Median([]float64{7}); m != 7 {
+		t.Errorf("Single-value median = %.3f, want 7", m)
+	}
+	if m := Median(nil); m != 0 {
+		t.Errorf("Median of nothing = %.3f, want 0", m)
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	// Population σ of the classic 2, 4, 4, 4, 5, 5, 7, 9 example
+	if s := StdDev([]float64{2, 4, 4, 4, 5, 5, 7, 9}); math.Abs(s-2) > 1e-12 {
+		t.Errorf("StdDev = %.6f, want 2", s)
+	}
+	if s := StdDev([]float64{3, 3, 3}); s != 0 {
+		t.Errorf("StdDev of constants = %.6f, want 0", s)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{15, 20, 35, 40, 50}
+	cases := []struct {
+		p, want float64
+	}{
+		{0, 15}, {25, 20}, {40, 29}, {50, 35}, {90, 46}, {100, 50},
+		{-10, 15}, {150, 50}, // Clamped
+	}
+	for _, c := range cases {
+		if got := Percentile(values, c.p); math.Abs(got-c.want) > 1e-12 {
+			t.Errorf("Percentile(%g) = %.3f, want %.3f", c.p, got, c.want)
+		}
+	}
+}
+
+func TestHarmonicMean(t *testing.T) {
+	// 3 / (1 + 1/2 + 1/4) = 12/7
+	if h := HarmonicMean([]float64{1, 2, 4}); math.Abs(h-12.0/7.0) > 1e-12 {
+		t.Errorf("HarmonicMean = %.6f, want %.6f", h, 12.0/7.0)
+	}
+	if h := HarmonicMean([]float64{1, 0, 4}); h != 0 {
+		t.Errorf("HarmonicMean with a zero = %.6f, want 0", h)
+	}
+}
+
+func TestBootstrapCI(t *testing.T) {
+	values := []float64{1.8, 2.4, 3.1, 2.2, 4.5, 2.9, 3.3, 1.5, 2.7, 3.8, 2.0, 3.5}
+	m := Mean(values)
+	lower, upper := BootstrapCI(values, 0.95)
+	t.Logf("Mean %.3f, 95%% bootstrap CI [%.3f, %.3f]", m, lower, upper)
+
+	if !(lower < m && m < upper) {
+		t.Errorf("CI [%.3f, %.3f] does not contain the mean %.3f", lower, upper, m)
+	}
+	// Normal approximation: half-width ≈ 1.96 σ/√n
+	halfWidth := 1.96 * StdDev(values) / math.Sqrt(float64(len(values)))
+	if w := (upper - lower) / 2; math.Abs(w-halfWidth) > 0.3*halfWidth {
+		t.Errorf("CI half-width %.3f, want about %.3f", w, halfWidth)
+	}
+
+	l80, u80 := BootstrapCI(values, 0.80)
+	if !(l80 > lower && u80 < upper) {
+		t.Errorf("80%% CI [%.3f, %.3f] not inside 95%% CI [%.3f, %.3f]", l80, u80, lower, upper)
+	}
+	if l, u := BootstrapCI(values, 0.95); l != lower || u != upper {
+		t.Error("BootstrapCI is not reproducible")
+	}
+	if l, u := BootstrapCI([]float64{2.5}, 0.95); l != 2.5 || u != 2.5 {
+		t.Errorf("Single-value CI [%.3f, %.3f], want [2.5, 2.5]", l, u)
+	}
+}
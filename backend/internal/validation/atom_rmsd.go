@@ -0,0 +1,137 @@
+// Package validation - Atom-selection RMSD
+//
+// CA RMSD scores the fold; once side chains are built, backbone and
+// all-heavy-atom RMSD also score the details. CalculateRMSDAtoms pairs atoms
+// by identity rather than by order, so structures with missing atoms or
+// extra residues can still be compared on what they share.
+//
+// BIOCHEMIST: Backbone = N, CA, C, O; AllHeavy = every atom except H/D
+// MATHEMATICIAN: Each selection is superposed on its own atom set (Horn,
+// see superposition.go), so the RMSDs of different selections come from
+// different transforms
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// AtomSelection chooses which atoms CalculateRMSDAtoms compares
+type AtomSelection int
+
+const (
+	CAOnly   AtomSelection = iota // Alpha carbons
+	Backbone                      // N, CA, C, O
+	AllHeavy                      // Every non-hydrogen atom
+)
+
+func (s AtomSelection) String() string {
+	switch s {
+	case CAOnly:
+		return "CA"
+	case Backbone:
+		return "backbone"
+	case AllHeavy:
+		return "all-heavy"
+	default:
+		return fmt.Sprintf("AtomSelection(%d)", int(s))
+	}
+}
+
+// atomKey identifies an atom for pairing two structures
+type atomKey struct {
+	residue residueKey
+	name    string
+}
+
+// CalculateRMSDAtoms returns the RMSD (Å) over the selected atoms after
+// optimal superposition on those same atoms
+//
+// Atoms are paired by (chain, residue number and insertion code, atom
+// name); atoms present in only one structure are left out. For alternate
+// locations the first listed is used. Returns an error for an unknown
+// selection or if fewer than three atoms pair up.
+func CalculateRMSDAtoms(predicted, experimental *parser.Protein, selection AtomSelection) (float64, error) {
+	if predicted == nil || experimental == nil {
+		return 0, fmt.Errorf("nil protein")
+	}
+	if selection < CAOnly || selection > AllHeavy {
+		return 0, fmt.Errorf("unknown atom selection %d", int(selection))
+	}
+
+	reference := selectedAtoms(experimental, selection)
+	var mobile, target [][3]float64
+	seen := make(map[atomKey]bool)
+	for _, atom := range predicted.Atoms {
+		if !atomSelected(atom, selection) {
+			continue
+		}
+		key := atomKeyOf(atom)
+		ref, ok := reference[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		mobile = append(mobile, [3]float64{atom.X, atom.Y, atom.Z})
+		target = append(target, [3]float64{ref.X, ref.Y, ref.Z})
+	}
+
+	if len(mobile) < 3 {
+		return 0, fmt.Errorf("only %d paired %s atoms, need at least 3 to superpose", len(mobile), selection)
+	}
+
+	_, _, rmsd := superposeCoords(mobile, target)
+	return rmsd, nil
+}
+
+// selectedAtoms indexes protein's selected atoms by key, keeping the first
+// of any duplicates (alternate locations)
+func selectedAtoms(protein *parser.Protein, selection AtomSelection) map[atomKey]*parser.Atom {
+	atoms := make(map[atomKey]*parser.Atom)
+	for _, atom := range protein.Atoms {
+		if !atomSelected(atom, selection) {
+			continue
+		}
+		key := atomKeyOf(atom)
+		if _, dup := atoms[key]; !dup {
+			atoms[key] = atom
+		}
+	}
+	return atoms
+}
+
+// atomSelected reports whether atom belongs to selection
+func atomSelected(atom *parser.Atom, selection AtomSelection) bool {
+	if atom == nil {
+		return false
+	}
+	switch selection {
+	case CAOnly:
+		return atom.Name == "CA"
+	case Backbone:
+		return atom.Name == "N" || atom.Name == "CA" || atom.Name == "C" || atom.Name == "O"
+	default:
+		return !isHydrogenAtom(atom)
+	}
+}
+
+// isHydrogenAtom reports whether atom is H or D, from its element or, when
+// the element column is blank, its name
+func isHydrogenAtom(atom *parser.Atom) bool {
+	element := strings.ToUpper(strings.TrimSpace(atom.Element))
+	if element != "" {
+		return element == "H" || element == "D"
+	}
+	name := strings.TrimLeft(strings.ToUpper(atom.Name), "0123456789")
+	return strings.HasPrefix(name, "H") || strings.HasPrefix(name, "D")
+}
+
+// atomKeyOf returns atom's pairing key
+func atomKeyOf(atom *parser.Atom) atomKey {
+	return atomKey{
+		residue: residueKey{chain: atom.ChainID, seqNum: atom.ResSeq, iCode: atom.ICode},
+		name:    atom.Name,
+	}
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// withSideChains adds CB, CG and an amide H to every residue of a backbone
+func withSideChains(protein *parser.Protein) *parser.Protein {
+	serial := len(protein.Atoms) + 1
+	for _, res := range protein.Residues {
+		add := func(name, element string, dx, dy, dz float64) {
+			protein.Atoms = append(protein.Atoms, &parser.Atom{
+				Serial: serial, Name: name, ResName: res.Name, ChainID: res.ChainID, ResSeq: res.SeqNum,
+				X: res.CA.X + dx, Y: res.CA.Y + dy, Z: res.CA.Z + dz, Element: element,
+			})
+			serial++
+		}
+		add("CB", "C", 1.0, 1.0, 0.5)
+		add("CG", "C", 2.0, 1.5, 1.0)
+		add("H", "H", -0.5, 0.0, -0.9)
+	}
+	return protein
+}
+
+// copyAllAtoms copies every atom through f, keeping residue pointers
+func copyAllAtoms(protein *parser.Protein, f func(atom *parser.Atom, x, y, z float64) (float64, float64, float64)) *parser.Protein {
+	clone := &parser.Protein{Name: protein.Name}
+	copies := make(map[*parser.Atom]*parser.Atom, len(protein.Atoms))
+	for _, atom := range protein.Atoms {
+		c := *atom
+		c.X, c.Y, c.Z = f(atom, atom.X, atom.Y, atom.Z)
+		copies[atom] = &c
+		clone.Atoms = append(clone.Atoms, &c)
+	}
+	for _, res := range protein.Residues {
+		r := *res
+		r.N, r.CA, r.C, r.O = copies[res.N], copies[res.CA], copies[res.C], copies[res.O]
+		clone.Residues = append(clone.Residues, &r)
+	}
+	return clone
+}
+
+// TestCalculateRMSDAtoms perturbs side chains of a rigidly moved helix and
+// checks each selection: CA matches CalculateRMSD, AllHeavy sees the side
+// chains, hydrogens and unpaired atoms are ignored
+func TestCalculateRMSDAtoms(t *testing.T) {
+	reference := withSideChains(gdtTestHelix())
+
+	rng := rand.New(rand.NewSource(3))
+	c, s := math.Cos(0.8), math.Sin(0.8)
+	predicted := copyAllAtoms(reference, func(atom *parser.Atom, x, y, z float64) (float64, float64, float64) {
+		switch atom.Name {
+		case "CG":
+			x, y, z = x+1.5*rng.NormFloat64(), y+1.5*rng.NormFloat64(), z+1.5*rng.NormFloat64()
+		case "H":
+			x += 10 // Hydrogens are never compared
+		default:
+			x, y, z = x+0.3*rng.NormFloat64(), y+0.3*rng.NormFloat64(), z+0.3*rng.NormFloat64()
+		}
+		return c*x - s*y + 8, s*x + c*y - 4, z + 2
+	})
+
+	rmsd := make(map[AtomSelection]float64)
+	for _, selection := range []AtomSelection{CAOnly, Backbone, AllHeavy} {
+		value, err := CalculateRMSDAtoms(predicted, reference, selection)
+		if err != nil {
+			t.Fatalf("%s RMSD: %v", selection, err)
+		}
+		rmsd[selection] = value
+		t.Logf("%-9s RMSD %.3f Å", selection, value)
+	}
+
+	caRMSD, _ := CalculateRMSD(predicted, reference)
+	if math.Abs(rmsd[CAOnly]-caRMSD) > 1e-9 {
+		t.Errorf("CAOnly RMSD %.6f, CalculateRMSD %.6f", rmsd[CAOnly], caRMSD)
+	}
+	if rmsd[Backbone] < 0.1 || rmsd[Backbone] > 0.6 {
+		t.Errorf("Backbone RMSD %.3f, want about the 0.3 Å noise", rmsd[Backbone])
+	}
+	if rmsd[AllHeavy] <= rmsd[Backbone] || rmsd[AllHeavy] <= rmsd[CAOnly] {
+		t.Errorf("AllHeavy RMSD %.3f not above backbone %.3f and CA %.3f", rmsd[AllHeavy], rmsd[Backbone], rmsd[CAOnly])
+	}
+
+	// Atoms missing from one side are dropped pairwise, not an error
+	trimmed := copyAllAtoms(reference, func(_ *parser.Atom, x, y, z float64) (float64, float64, float64) { return x, y, z })
+	trimmed.Atoms = trimmed.Atoms[:len(trimmed.Atoms)-5]
+	if value, err := CalculateRMSDAtoms(trimmed, reference, AllHeavy); err != nil || value > 1e-5 {
+		t.Errorf("Identical structure with missing atoms: RMSD %.3e, err %v", value, err)
+	}
+
+	if _, err := CalculateRMSDAtoms(predicted, reference, AtomSelection(7)); err == nil {
+		t.Errorf("Unknown selection accepted")
+	}
+}
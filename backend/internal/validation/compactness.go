@@ -0,0 +1,75 @@
+// Package validation - Radius of gyration and compactness
+//
+// A model can have good local geometry and still never have collapsed.
+// RadiusOfGyration measures the size of the CA trace; CompactnessScore
+// compares it to the size of a folded globular protein of the same length,
+// which needs no experimental structure.
+//
+// BIOCHEMIST: Folded globular proteins follow Rg ≈ 2.2·N^0.38 Å; molten
+// globules sit ~20-40% above it and extended chains far above
+// PHYSICIST: Rg² = (1/N) Σ |r_i - r̄|², the mean squared distance from the
+// centroid
+// MATHEMATICIAN: The score is Gaussian in ln(Rg / Rg_expected), so being
+// twice too large and half too large are penalized alike
+//
+// CITATION:
+// Skolnick, J., Kolinski, A., & Ortiz, A. R. (1997). "MONSSTER: a method for
+// folding globular proteins with a small number of distance restraints."
+// J. Mol. Biol. 265(2): 217-241.
+package validation
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// compactnessLogWidth is the σ of ln(Rg / Rg_expected) in CompactnessScore:
+// a 30% larger (or 23% smaller) Rg scores 0.5
+const compactnessLogWidth = 0.22
+
+// RadiusOfGyration returns the radius of gyration (Å) of the CA atoms
+//
+// Returns 0 for a structure without CA atoms.
+func RadiusOfGyration(protein *parser.Protein) float64 {
+	if protein == nil {
+		return 0
+	}
+	atoms := getCAlphaAtoms(protein)
+	if len(atoms) == 0 {
+		return 0
+	}
+
+	cx, cy, cz := calculateCentroid(atoms)
+	sum := 0.0
+	for _, atom := range atoms {
+		dx, dy, dz := atom.X-cx, atom.Y-cy, atom.Z-cz
+		sum += dx*dx + dy*dy + dz*dz
+	}
+	return math.Sqrt(sum / float64(len(atoms)))
+}
+
+// ExpectedRadiusOfGyration returns the empirical Rg (Å) of a folded
+// globular protein of numResidues residues: 2.2·N^0.38
+func ExpectedRadiusOfGyration(numResidues int) float64 {
+	if numResidues <= 0 {
+		return 0
+	}
+	return 2.2 * math.Pow(float64(numResidues), 0.38)
+}
+
+// CompactnessScore rates how plausibly collapsed a structure is, in [0, 1]
+//
+// 1 when Rg equals ExpectedRadiusOfGyration for the number of CA atoms,
+// falling as exp(-ln²(Rg / Rg_expected) / 2σ²) with σ = 0.22: 0.5 for a
+// molten globule (Rg ≈ 1.3× expected) and near 0 for an extended chain.
+// Returns 0 for a structure without CA atoms.
+func CompactnessScore(protein *parser.Protein) float64 {
+	rg := RadiusOfGyration(protein)
+	if rg == 0 {
+		return 0
+	}
+
+	logRatio := math.Log(rg / ExpectedRadiusOfGyration(len(getCAlphaAtoms(protein))))
+	return math.Exp(-logRatio * logRatio / (2 * compactnessLogWidth * compactnessLogWidth))
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// packedGlobule places n CA atoms uniformly through a sphere at protein
+// packing density (~135 Å³ per residue), the shape of a folded domain
+func packedGlobule(n int) *parser.Protein {
+	const volumePerResidue = 135.0
+	radius := math.Cbrt(3 * float64(n) * volumePerResidue / (4 * math.Pi))
+	goldenAngle := math.Pi * (3 - math.Sqrt(5))
+
+	protein := &parser.Protein{Name: "globule"}
+	for k := 0; k < n; k++ {
+		// Uniform in volume: r ∝ cbrt(u); directions on a Fibonacci sphere
+		r := radius * math.Cbrt((float64(k)+0.5)/float64(n))
+		z := 1 - 2*(float64(k)+0.5)/float64(n)
+		rho := math.Sqrt(1 - z*z)
+		theta := goldenAngle * float64(k)
+
+		ca := &parser.Atom{Name: "CA", ResName: "ALA", ResSeq: k + 1, ChainID: "A",
+			X: r * rho * math.Cos(theta), Y: r * rho * math.Sin(theta), Z: r * z, Element: "C"}
+		protein.Atoms = append(protein.Atoms, ca)
+		protein.Residues = append(protein.Residues, &parser.Residue{Name: "ALA", SeqNum: k + 1, ChainID: "A", CA: ca})
+	}
+	return protein
+}
+
+// TestRadiusOfGyration checks an extended chain is far larger than a folded
+// protein of its length, and a packed globule matches the expectation
+func TestRadiusOfGyration(t *testing.T) {
+	const n = 60
+
+	phi := make([]float64, n)
+	psi := make([]float64, n)
+	for i := range phi {
+		phi[i], psi[i] = -120, 120
+	}
+	extended := buildTestBackbone(strings.Repeat("A", n), phi, psi)
+	globule := packedGlobule(n)
+
+	expected := ExpectedRadiusOfGyration(n)
+	extendedRg := RadiusOfGyration(extended)
+	globuleRg := RadiusOfGyration(globule)
+	t.Logf("N = %d: expected Rg %.2f Å, extended %.2f Å (score %.3f), globule %.2f Å (score %.3f)",
+		n, expected, extendedRg, CompactnessScore(extended), globuleRg, CompactnessScore(globule))
+
+	if extendedRg < 3*expected {
+		t.Errorf("Extended chain Rg %.2f Å should exceed 3× expected (%.2f Å)", extendedRg, expected)
+	}
+	if math.Abs(globuleRg-expected)/expected > 0.15 {
+		t.Errorf("Packed globule Rg %.2f Å not within 15%% of expected %.2f Å", globuleRg, expected)
+	}
+
+	if score := CompactnessScore(extended); score > 0.01 {
+		t.Errorf("Extended chain compactness %.3f, want ≈ 0", score)
+	}
+	if score := CompactnessScore(globule); score < 0.8 {
+		t.Errorf("Packed globule compactness %.3f, want ≥ 0.8", score)
+	}
+
+	if RadiusOfGyration(&parser.Protein{}) != 0 || CompactnessScore(nil) != 0 {
+		t.Error("Empty structure should have Rg and compactness 0")
+	}
+}
@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCoreSuperpose swings out four residues of an otherwise rigidly moved
+// helix and checks the core aligns exactly while the loop is downweighted
+func TestCoreSuperpose(t *testing.T) {
+	target := gdtTestHelix()
+
+	c, s := math.Cos(1.1), math.Sin(1.1)
+	mobile := copyCAProtein(target, func(x, y, z float64) (float64, float64, float64) {
+		return c*x - s*y + 4, s*x + c*y - 9, z + 2.5
+	})
+	loop := map[int]bool{8: true, 9: true, 10: true, 11: true}
+	for i := range loop {
+		ca := mobile.Residues[i].CA
+		ca.X += 6
+		ca.Y += 7
+	}
+
+	_, _, globalRMSD, err := Superpose(mobile, target)
+	if err != nil {
+		t.Fatalf("Superpose failed: %v", err)
+	}
+
+	result, err := CoreSuperpose(mobile, target, DefaultCoreSuperpositionConfig())
+	if err != nil {
+		t.Fatalf("CoreSuperpose failed: %v", err)
+	}
+	t.Logf("Global RMSD %.3f Å; core RMSD %.2e Å over %d residues, weighted %.3f Å, %d iterations (converged %v)",
+		globalRMSD, result.CoreRMSD, len(result.Core), result.WeightedRMSD, result.Iterations, result.Converged)
+
+	if !result.Converged {
+		t.Error("Reweighting did not converge")
+	}
+	if result.CoreRMSD > 0.05 {
+		t.Errorf("Core RMSD %.3f Å, want ≈ 0", result.CoreRMSD)
+	}
+	if len(result.Core) != len(target.Residues)-len(loop) {
+		t.Errorf("Core has %d residues, want %d", len(result.Core), len(target.Residues)-len(loop))
+	}
+	for i, w := range result.Weights {
+		if loop[i] && w > 0.1 {
+			t.Errorf("Loop residue %d: weight %.3f (deviation %.2f Å), want < 0.1", i, w, result.Deviations[i])
+		}
+		if !loop[i] && w < 0.99 {
+			t.Errorf("Core residue %d: weight %.3f, want ≈ 1", i, w)
+		}
+	}
+
+	// Zero weight on the loop: an exact fit of the rest
+	weights := make([]float64, len(target.Residues))
+	for i := range weights {
+		if !loop[i] {
+			weights[i] = 1
+		}
+	}
+	if _, _, rmsd, err := WeightedSuperpose(mobile, target, weights); err != nil || rmsd > 1e-6 {
+		t.Errorf("WeightedSuperpose without the loop: RMSD %.2e Å, err %v; want 0", rmsd, err)
+	}
+
+	// Uniform weights reproduce Superpose
+	for i := range weights {
+		weights[i] = 2
+	}
+	if _, _, rmsd, err := WeightedSuperpose(mobile, target, weights); err != nil || math.Abs(rmsd-globalRMSD) > 1e-9 {
+		t.Errorf("Uniform WeightedSuperpose RMSD %.6f Å, want %.6f", rmsd, globalRMSD)
+	}
+
+	if _, _, _, err := WeightedSuperpose(mobile, target, weights[:5]); err == nil {
+		t.Error("Accepted a weight count mismatch")
+	}
+	weights[3] = -1
+	if _, _, _, err := WeightedSuperpose(mobile, target, weights); err == nil {
+		t.Error("Accepted a negative weight")
+	}
+}
@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// TestEnergyGapLowNative gives the native an energy far below any decoy and
+// checks the Z-score and gap report it as well separated
+func TestEnergyGapLowNative(t *testing.T) {
+	native := uniformBackbone(t, 12, 0, 12, -57, -47)
+	decoys, err := GenerateDihedralDecoys(native, 30, 1.0, rand.New(rand.NewSource(7)))
+	if err != nil {
+		t.Fatalf("GenerateDihedralDecoys failed: %v", err)
+	}
+
+	// Decoys score by size; the native is artificially far below them all
+	energyFn := func(p *parser.Protein) float64 {
+		if p == native {
+			return -100
+		}
+		return RadiusOfGyration(p)
+	}
+
+	result, err := EnergyGap(native, decoys, energyFn)
+	if err != nil {
+		t.Fatalf("EnergyGap failed: %v", err)
+	}
+	t.Logf("Native %.1f, decoys %.2f ± %.2f: Z = %.1f, gap %.1f to decoy %d",
+		result.NativeEnergy, result.DecoyMean, result.DecoyStdDev, result.ZScore, result.Gap, result.BestDecoy)
+
+	if result.ZScore < 10 {
+		t.Errorf("Z-score %.2f, want a large positive value", result.ZScore)
+	}
+	best := result.DecoyEnergies[result.BestDecoy]
+	if math.Abs(result.Gap-(best+100)) > 1e-9 || result.Gap <= 0 {
+		t.Errorf("Gap %.3f, want min decoy energy %.3f + 100", result.Gap, best)
+	}
+	for i, e := range result.DecoyEnergies {
+		if e < best {
+			t.Errorf("Decoy %d energy %.3f below best decoy %.3f", i, e, best)
+		}
+	}
+	if result.DecoysBelowNative != 0 {
+		t.Errorf("%d decoys below the native", result.DecoysBelowNative)
+	}
+
+	// A native scored like the decoys sits inside their distribution
+	result, err = EnergyGap(native, decoys, RadiusOfGyration)
+	if err != nil {
+		t.Fatalf("EnergyGap failed: %v", err)
+	}
+	t.Logf("Native scored by Rg: %.2f, Z = %.2f", result.NativeEnergy, result.ZScore)
+	if result.ZScore >= 10 {
+		t.Errorf("Z-score %.2f for a native scored like its decoys", result.ZScore)
+	}
+}
+
+// TestGenerateDihedralDecoys checks sigma = 0 rebuilds the native's angles
+// and a positive sigma moves them
+func TestGenerateDihedralDecoys(t *testing.T) {
+	native := uniformBackbone(t, 10, 0, 10, -57, -47)
+	rng := rand.New(rand.NewSource(1))
+
+	rebuilt, err := GenerateDihedralDecoys(native, 1, 0, rng)
+	if err != nil {
+		t.Fatalf("GenerateDihedralDecoys failed: %v", err)
+	}
+	if rmsd, err := CalculateSuperposedRMSD(rebuilt[0], native); err != nil || rmsd > 1e-6 {
+		t.Errorf("sigma = 0 decoy is %.6f Å from the native (err %v)", rmsd, err)
+	}
+
+	decoys, err := GenerateDihedralDecoys(native, 5, 0.5, rng)
+	if err != nil {
+		t.Fatalf("GenerateDihedralDecoys failed: %v", err)
+	}
+	for k, decoy := range decoys {
+		if len(decoy.Residues) != len(native.Residues) || decoy.Sequence() != native.Sequence() {
+			t.Fatalf("Decoy %d has %d residues %q, want %q", k, len(decoy.Residues), decoy.Sequence(), native.Sequence())
+		}
+		moved := 0.0
+		nativeAngles := geometry.CalculateRamachandran(native)
+		for i, a := range geometry.CalculateRamachandran(decoy) {
+			if !math.IsNaN(a.Phi) && !math.IsNaN(nativeAngles[i].Phi) {
+				moved = math.Max(moved, math.Abs(math.Remainder(a.Phi-nativeAngles[i].Phi, 2*math.Pi)))
+			}
+		}
+		if moved < 0.1 {
+			t.Errorf("Decoy %d φ within %.3f rad of the native", k, moved)
+		}
+	}
+
+	if _, err := EnergyGap(native, decoys[:1], RadiusOfGyration); err == nil {
+		t.Error("EnergyGap accepted a single decoy")
+	}
+}
@@ -0,0 +1,112 @@
+// Package validation - Energy landscape funnel analysis
+//
+// The benchmarks pick the lowest-energy model and report its RMSD, which
+// assumes low energy means native-like. A funnel analysis tests that
+// assumption directly: score every structure of an ensemble by energy and
+// by RMSD to the native, and ask whether the two agree.
+//
+// BIOCHEMIST: A good energy function has a funnel - energy falls as RMSD
+// falls, and the native-like cluster sits below every decoy
+// MATHEMATICIAN: Pearson r measures linear agreement, Spearman ρ whether
+// the rankings agree; the energy gap is min E(decoys) - min E(native-like)
+// ETHICIST: A weak correlation here means benchmark RMSDs of the
+// lowest-energy model owe more to luck than to the energy function
+//
+// CITATION:
+// Bryngelson, J. D., et al. (1995). "Funnels, pathways, and the energy
+// landscape of protein folding: a synthesis." Proteins 21(3): 167-195.
+// Simons, K. T., et al. (1999). "Improved recognition of native-like protein
+// structures using a combination of sequence-dependent and
+// sequence-independent features of proteins." Proteins 34(1): 82-95.
+package validation
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/stats"
+)
+
+// FunnelClusterWidth is how far above the lowest RMSD in the ensemble a
+// structure may be and still count as native-like (Å)
+const FunnelClusterWidth = 1.0
+
+// FunnelPoint is one structure of a funnel plot
+type FunnelPoint struct {
+	Index  int     // Index into the analysed structures
+	RMSD   float64 // Superposed CA RMSD to the native (Å)
+	Energy float64 // energyFn of the structure
+}
+
+// FunnelResult summarizes how well energy discriminates native-like
+// structures
+type FunnelResult struct {
+	Points []FunnelPoint // In input order
+
+	Pearson  float64 // Energy vs RMSD linear correlation (funnel: > 0)
+	Spearman float64 // Energy vs RMSD rank correlation (funnel: > 0)
+
+	ClusterSize      int     // Structures within FunnelClusterWidth of the lowest RMSD
+	EnergyGap        float64 // min E(rest) - min E(cluster); > 0: native-like is lowest (0 with no rest)
+	LowestEnergyRMSD float64 // RMSD of the lowest-energy structure (Å)
+}
+
+// FunnelAnalysis scores structures by energyFn and by superposed CA RMSD to
+// native, and reports their correlation and the energy gap between the
+// lowest-RMSD cluster and the rest
+//
+// Every structure must have native's CA count.
+func FunnelAnalysis(structures []*parser.Protein, native *parser.Protein, energyFn func(*parser.Protein) float64) (*FunnelResult, error) {
+	if native == nil {
+		return nil, fmt.Errorf("native structure is nil")
+	}
+	if energyFn == nil {
+		return nil, fmt.Errorf("energy function is nil")
+	}
+	if len(structures) == 0 {
+		return nil, fmt.Errorf("no structures to analyse")
+	}
+
+	result := &FunnelResult{Points: make([]FunnelPoint, len(structures))}
+	rmsds := make([]float64, len(structures))
+	energies := make([]float64, len(structures))
+	for i, structure := range structures {
+		if structure == nil {
+			return nil, fmt.Errorf("structure %d is nil", i)
+		}
+		rmsd, err := CalculateSuperposedRMSD(structure, native)
+		if err != nil {
+			return nil, fmt.Errorf("structure %d: %w", i, err)
+		}
+		rmsds[i], energies[i] = rmsd, energyFn(structure)
+		result.Points[i] = FunnelPoint{Index: i, RMSD: rmsd, Energy: energies[i]}
+	}
+
+	result.Pearson = stats.Pearson(rmsds, energies)
+	result.Spearman = stats.Spearman(rmsds, energies)
+
+	minRMSD, lowest := math.Inf(1), 0
+	for i, p := range result.Points {
+		minRMSD = math.Min(minRMSD, p.RMSD)
+		if p.Energy < result.Points[lowest].Energy {
+			lowest = i
+		}
+	}
+	result.LowestEnergyRMSD = result.Points[lowest].RMSD
+
+	clusterMin, restMin := math.Inf(1), math.Inf(1)
+	for _, p := range result.Points {
+		if p.RMSD <= minRMSD+FunnelClusterWidth {
+			result.ClusterSize++
+			clusterMin = math.Min(clusterMin, p.Energy)
+		} else {
+			restMin = math.Min(restMin, p.Energy)
+		}
+	}
+	if result.ClusterSize < len(result.Points) {
+		result.EnergyGap = restMin - clusterMin
+	}
+
+	return result, nil
+}
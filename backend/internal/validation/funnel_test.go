@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// funnelEnsemble returns copies of native with Gaussian noise of growing σ
+func funnelEnsemble(native *parser.Protein, n int, rng *rand.Rand) []*parser.Protein {
+	ensemble := make([]*parser.Protein, n)
+	for k := range ensemble {
+		sigma := 0.1 + 4.0*float64(k)/float64(n)
+		ensemble[k] = copyCAProtein(native, func(x, y, z float64) (float64, float64, float64) {
+			return x + sigma*rng.NormFloat64(), y + sigma*rng.NormFloat64(), z + sigma*rng.NormFloat64()
+		})
+	}
+	return ensemble
+}
+
+// TestFunnelAnalysis builds a synthetic funnel, energy rising with RMSD
+// plus noise, and checks strong positive correlations and a positive gap;
+// inverting the energy must flip both
+func TestFunnelAnalysis(t *testing.T) {
+	native := gdtTestHelix()
+	rng := rand.New(rand.NewSource(7))
+	ensemble := funnelEnsemble(native, 40, rng)
+
+	funnel := func(p *parser.Protein) float64 {
+		rmsd, _ := CalculateSuperposedRMSD(p, native)
+		return 20*rmsd + 2*rng.NormFloat64()
+	}
+	result, err := FunnelAnalysis(ensemble, native, funnel)
+	if err != nil {
+		t.Fatalf("FunnelAnalysis failed: %v", err)
+	}
+	t.Logf("Funnel: Pearson %.3f, Spearman %.3f, cluster %d, gap %.2f, lowest-energy RMSD %.2f Å",
+		result.Pearson, result.Spearman, result.ClusterSize, result.EnergyGap, result.LowestEnergyRMSD)
+
+	if len(result.Points) != len(ensemble) {
+		t.Fatalf("Got %d points, want %d", len(result.Points), len(ensemble))
+	}
+	if result.Pearson < 0.9 || result.Spearman < 0.9 {
+		t.Errorf("Funnel correlations %.3f / %.3f, want > 0.9", result.Pearson, result.Spearman)
+	}
+	if result.ClusterSize == 0 || result.ClusterSize == len(ensemble) || result.EnergyGap <= 0 {
+		t.Errorf("Cluster of %d with gap %.2f, want a proper subset below the rest", result.ClusterSize, result.EnergyGap)
+	}
+	minRMSD := math.Inf(1)
+	for _, p := range result.Points {
+		minRMSD = math.Min(minRMSD, p.RMSD)
+	}
+	if result.LowestEnergyRMSD > minRMSD+FunnelClusterWidth {
+		t.Errorf("Lowest-energy structure at %.2f Å, outside the native-like cluster (min %.2f Å)", result.LowestEnergyRMSD, minRMSD)
+	}
+
+	inverted, err := FunnelAnalysis(ensemble, native, func(p *parser.Protein) float64 { return -funnel(p) })
+	if err != nil {
+		t.Fatalf("FunnelAnalysis failed: %v", err)
+	}
+	t.Logf("Inverted: Pearson %.3f, Spearman %.3f, gap %.2f", inverted.Pearson, inverted.Spearman, inverted.EnergyGap)
+	if inverted.Pearson > -0.9 || inverted.Spearman > -0.9 || inverted.EnergyGap >= 0 {
+		t.Errorf("Inverted funnel: Pearson %.3f, Spearman %.3f, gap %.2f; want strongly negative",
+			inverted.Pearson, inverted.Spearman, inverted.EnergyGap)
+	}
+
+	if _, err := FunnelAnalysis([]*parser.Protein{ensemble[0], gdtTestHelix()}, native, nil); err == nil {
+		t.Errorf("Nil energy function accepted")
+	}
+}
@@ -0,0 +1,176 @@
+// Package validation - Global Distance Test
+//
+// GDT counts CA atoms that can be brought within a cutoff of the reference
+// by SOME superposition - not one global fit. A single Kabsch fit over all
+// residues is dragged by wrong loops, hiding a correct core.
+//
+// BIOCHEMIST: GDT_TS uses cutoffs {1, 2, 4, 8} Å; GDT_HA {0.5, 1, 2, 4} Å
+// grades high-accuracy models
+// MATHEMATICIAN: For each cutoff, seed superpositions on short windows,
+// then iterate: fit on the residues within the cutoff, recount, refit until
+// the set stops changing; keep the largest count (LGA-style search)
+//
+// Citation: Zemla, A. (2003). "LGA: A method for finding 3D similarities
+// in protein structures." NAR 31.13: 3370-3374.
+package validation
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// GDT cutoff sets (Å)
+var (
+	gdtTSCutoffs = []float64{1.0, 2.0, 4.0, 8.0}
+	gdtHACutoffs = []float64{0.5, 1.0, 2.0, 4.0}
+)
+
+// gdtSeedWindows are the seed segment lengths; the full chain is always tried
+var gdtSeedWindows = []int{3, 5, 7, 9}
+
+// gdtMaxRefinements bounds the fit/recount iterations per seed
+const gdtMaxRefinements = 20
+
+// CalculateGDT_HA computes the high-accuracy Global Distance Test score
+//
+// Average over cutoffs {0.5, 1, 2, 4} Å of the percentage of reference CA
+// atoms superposable within the cutoff. Returns [0, 100] (unlike
+// CalculateGDT_TS, which keeps its historical [0, 1] scale).
+//
+// Residues are paired by index; a residue missing CA in either structure
+// counts as outside every cutoff. Returns 0 if the residue counts differ.
+func CalculateGDT_HA(predicted, experimental *parser.Protein) float64 {
+	return 100.0 * gdtScore(predicted, experimental, gdtHACutoffs)
+}
+
+// gdtScore returns the mean fraction of reference residues superposable
+// within each cutoff
+func gdtScore(predicted, experimental *parser.Protein, cutoffs []float64) float64 {
+	if predicted == nil || experimental == nil || len(predicted.Residues) != len(experimental.Residues) {
+		return 0
+	}
+
+	// Pair residues with CA in both; the denominator is the reference length
+	var mobile, target [][3]float64
+	reference := 0
+	for i, res := range experimental.Residues {
+		if res == nil || res.CA == nil {
+			continue
+		}
+		reference++
+		if pred := predicted.Residues[i]; pred != nil && pred.CA != nil {
+			mobile = append(mobile, [3]float64{pred.CA.X, pred.CA.Y, pred.CA.Z})
+			target = append(target, [3]float64{res.CA.X, res.CA.Y, res.CA.Z})
+		}
+	}
+	if reference == 0 || len(mobile) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, cutoff := range cutoffs {
+		total += float64(maxSuperposableWithin(mobile, target, cutoff)) / float64(reference)
+	}
+	return total / float64(len(cutoffs))
+}
+
+// maxSuperposableWithin returns the largest number of pairs brought within
+// cutoff by any superposition found from the seed windows
+func maxSuperposableWithin(mobile, target [][3]float64, cutoff float64) int {
+	n := len(mobile)
+	best := 0
+
+	// Seeds: sliding windows (clamped to the chain length), then the full chain
+	seeds := make([][]int, 0)
+	for _, w := range gdtSeedWindows {
+		if w > n {
+			w = n
+		}
+		for start := 0; start+w <= n; start++ {
+			seed := make([]int, w)
+			for k := range seed {
+				seed[k] = start + k
+			}
+			seeds = append(seeds, seed)
+		}
+		if w == n {
+			break
+		}
+	}
+	all := make([]int, n)
+	for k := range all {
+		all[k] = k
+	}
+	seeds = append(seeds, all)
+
+	for _, seed := range seeds {
+		if count := refineSuperposition(mobile, target, seed, cutoff); count > best {
+			best = count
+			if best == n {
+				break
+			}
+		}
+	}
+	return best
+}
+
+// refineSuperposition fits on subset, then repeatedly refits on the pairs
+// within cutoff until the set is stable; returns the largest count seen
+func refineSuperposition(mobile, target [][3]float64, subset []int, cutoff float64) int {
+	best := 0
+	previous := -1
+
+	for iter := 0; iter < gdtMaxRefinements && len(subset) > 0; iter++ {
+		subMobile := make([][3]float64, len(subset))
+		subTarget := make([][3]float64, len(subset))
+		for k, idx := range subset {
+			subMobile[k], subTarget[k] = mobile[idx], target[idx]
+		}
+		rot, trans, _ := superposeCoords(subMobile, subTarget)
+
+		within := make([]int, 0, len(mobile))
+		for i := range mobile {
+			if transformedDistance(rot, trans, mobile[i], target[i]) <= cutoff {
+				within = append(within, i)
+			}
+		}
+
+		if len(within) > best {
+			best = len(within)
+		}
+		if len(within) == previous && sameIndices(within, subset) {
+			break
+		}
+		previous = len(within)
+		subset = within
+	}
+
+	return best
+}
+
+// transformedDistance returns |R·p + t - q|
+func transformedDistance(rot [3][3]float64, trans [3]float64, p, q [3]float64) float64 {
+	sum := 0.0
+	for a := 0; a < 3; a++ {
+		d := trans[a] - q[a]
+		for b := 0; b < 3; b++ {
+			d += rot[a][b] * p[b]
+		}
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// sameIndices reports whether two ascending index lists are equal
+func sameIndices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// copyCAProtein deep-copies a backbone, transforming every atom with f
+func copyCAProtein(protein *parser.Protein, f func(x, y, z float64) (float64, float64, float64)) *parser.Protein {
+	clone := &parser.Protein{Name: protein.Name}
+	for _, res := range protein.Residues {
+		copyAtom := func(a *parser.Atom) *parser.Atom {
+			if a == nil {
+				return nil
+			}
+			c := *a
+			c.X, c.Y, c.Z = f(a.X, a.Y, a.Z)
+			clone.Atoms = append(clone.Atoms, &c)
+			return &c
+		}
+		clone.Residues = append(clone.Residues, &parser.Residue{
+			Name: res.Name, SeqNum: res.SeqNum, ChainID: res.ChainID,
+			N: copyAtom(res.N), CA: copyAtom(res.CA), C: copyAtom(res.C), O: copyAtom(res.O),
+		})
+	}
+	return clone
+}
+
+// gdtTestHelix builds a 20-residue ideal α-helix
+func gdtTestHelix() *parser.Protein {
+	n := 20
+	phi := make([]float64, n)
+	psi := make([]float64, n)
+	for i := range phi {
+		phi[i], psi[i] = -57, -47
+	}
+	return buildTestBackbone("AAAAAAAAAAAAAAAAAAAA", phi, psi)
+}
+
+// TestGDTIdentical checks identical (and rigidly moved) structures score 100
+func TestGDTIdentical(t *testing.T) {
+	reference := gdtTestHelix()
+
+	// Rotate 40° about Z and translate: superposition must undo it
+	c, s := math.Cos(0.7), math.Sin(0.7)
+	moved := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) {
+		return c*x - s*y + 3, s*x + c*y - 2, z + 5
+	})
+
+	for name, predicted := range map[string]*parser.Protein{"identical": reference, "moved": moved} {
+		ha := CalculateGDT_HA(predicted, reference)
+		ts := CalculateGDT_TS(predicted, reference)
+		t.Logf("%s: GDT_HA = %.2f, GDT_TS = %.3f", name, ha, ts)
+
+		if math.Abs(ha-100) > 1e-9 {
+			t.Errorf("%s: GDT_HA = %.4f, want 100", name, ha)
+		}
+		if math.Abs(ts-1) > 1e-9 {
+			t.Errorf("%s: GDT_TS = %.4f, want 1", name, ts)
+		}
+	}
+}
+
+// TestGDTNoised checks a slightly noised structure gives an intermediate score
+// with GDT_HA ≤ GDT_TS (tighter cutoffs)
+func TestGDTNoised(t *testing.T) {
+	reference := gdtTestHelix()
+	rng := rand.New(rand.NewSource(42))
+
+	noised := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) {
+		const sigma = 0.6 // Å per coordinate
+		return x + sigma*rng.NormFloat64(), y + sigma*rng.NormFloat64(), z + sigma*rng.NormFloat64()
+	})
+
+	ha := CalculateGDT_HA(noised, reference)
+	ts := CalculateGDT_TS(noised, reference)
+	t.Logf("Noised (σ = 0.6 Å): GDT_HA = %.2f, GDT_TS = %.3f", ha, ts)
+
+	if ha <= 20 || ha >= 95 {
+		t.Errorf("GDT_HA = %.2f, want an intermediate value in (20, 95)", ha)
+	}
+	if ha > 100*ts {
+		t.Errorf("GDT_HA %.2f exceeds GDT_TS %.2f%%", ha, 100*ts)
+	}
+}
+
+// TestGDTEdgeCases checks short chains, missing CA atoms and length mismatch
+func TestGDTEdgeCases(t *testing.T) {
+	// Two residues: shorter than every seed window
+	short := buildTestBackbone("AA", []float64{-57, -57}, []float64{-47, -47})
+	if ha := CalculateGDT_HA(short, short); math.Abs(ha-100) > 1e-9 {
+		t.Errorf("2-residue self GDT_HA = %.4f, want 100", ha)
+	}
+
+	// A missing predicted CA counts as outside every cutoff
+	reference := gdtTestHelix()
+	missing := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) { return x, y, z })
+	missing.Residues[7].CA = nil
+
+	want := 100.0 * float64(len(reference.Residues)-1) / float64(len(reference.Residues))
+	if ha := CalculateGDT_HA(missing, reference); math.Abs(ha-want) > 1e-9 {
+		t.Errorf("Missing CA: GDT_HA = %.4f, want %.4f", ha, want)
+	}
+
+	if ha := CalculateGDT_HA(short, reference); ha != 0 {
+		t.Errorf("Length mismatch: GDT_HA = %.4f, want 0", ha)
+	}
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestLDDT checks lDDT is 1 for a rigidly moved copy (no superposition
+// needed) and drops, but stays high, under small coordinate noise
+func TestLDDT(t *testing.T) {
+	reference := gdtTestHelix()
+
+	moved := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) {
+		c, s := math.Cos(1.1), math.Sin(1.1)
+		return c*x - s*y + 20, s*x + c*y - 5, z + 3
+	})
+	if score := CalculateLDDT(moved, reference); math.Abs(score-1) > 1e-12 {
+		t.Errorf("Rigidly moved copy: lDDT %.4f, want 1", score)
+	}
+
+	rng := rand.New(rand.NewSource(7))
+	noised := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) {
+		return x + 0.6*rng.NormFloat64(), y + 0.6*rng.NormFloat64(), z + 0.6*rng.NormFloat64()
+	})
+	score := CalculateLDDT(noised, reference)
+	t.Logf("Noised (σ = 0.6 Å): lDDT = %.3f", score)
+	if score >= 1 || score < 0.5 {
+		t.Errorf("Noised lDDT %.3f, want in [0.5, 1)", score)
+	}
+
+	noised.Residues = noised.Residues[1:]
+	if score := CalculateLDDT(noised, reference); score != 0 {
+		t.Errorf("Residue count mismatch gave lDDT %.3f, want 0", score)
+	}
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLocalErrorSpike checks a single displaced residue shows up as a
+// localized spike in both the per-residue RMSD and the difference-distance
+// matrix
+func TestLocalErrorSpike(t *testing.T) {
+	reference := gdtTestHelix()
+	const displaced = 10
+
+	predicted := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) { return x, y, z })
+	predicted.Residues[displaced].CA.X += 4.0
+
+	deviations, err := PerResidueRMSD(predicted, reference)
+	if err != nil {
+		t.Fatalf("PerResidueRMSD failed: %v", err)
+	}
+	if len(deviations) != len(reference.Residues) {
+		t.Fatalf("Got %d deviations, want %d", len(deviations), len(reference.Residues))
+	}
+
+	t.Logf("Per-residue deviation: displaced = %.3f Å, neighbor = %.3f Å", deviations[displaced], deviations[displaced+1])
+	for i, d := range deviations {
+		if i == displaced {
+			if d < 3.0 {
+				t.Errorf("Displaced residue deviation %.3f Å, want > 3", d)
+			}
+		} else if d > 0.5 {
+			t.Errorf("Residue %d deviation %.3f Å, want < 0.5", i, d)
+		}
+	}
+
+	ddm := DifferenceDistanceMatrix(predicted, reference)
+	if len(ddm) != len(reference.Residues) {
+		t.Fatalf("Got %d matrix rows, want %d", len(ddm), len(reference.Residues))
+	}
+
+	rowMax := 0.0
+	for i := range ddm {
+		for j := range ddm[i] {
+			if ddm[i][j] != ddm[j][i] {
+				t.Fatalf("Matrix not symmetric at (%d, %d)", i, j)
+			}
+			if i == displaced || j == displaced {
+				rowMax = math.Max(rowMax, ddm[i][j])
+			} else if ddm[i][j] > 1e-9 {
+				t.Errorf("Pair (%d, %d) unaffected by the displacement has difference %.4f", i, j, ddm[i][j])
+			}
+		}
+	}
+	t.Logf("Difference-distance: max in displaced row = %.3f Å", rowMax)
+	if rowMax < 2.0 {
+		t.Errorf("Displaced row max %.3f Å, want > 2", rowMax)
+	}
+}
+
+// TestLocalErrorLengthMismatch checks structures of different length are
+// paired on their common residue numbers
+func TestLocalErrorLengthMismatch(t *testing.T) {
+	reference := gdtTestHelix()
+	predicted := copyCAProtein(reference, func(x, y, z float64) (float64, float64, float64) { return x + 1, y, z })
+	predicted.Residues = predicted.Residues[3:17]
+
+	deviations, err := PerResidueRMSD(predicted, reference)
+	if err != nil {
+		t.Fatalf("PerResidueRMSD failed: %v", err)
+	}
+	for i, d := range deviations {
+		paired := i >= 3 && i < 17
+		if paired && d > 1e-6 {
+			t.Errorf("Paired residue %d deviation %.6f Å, want 0", i, d)
+		}
+		if !paired && !math.IsNaN(d) {
+			t.Errorf("Unpaired residue %d deviation %.3f, want NaN", i, d)
+		}
+	}
+
+	ddm := DifferenceDistanceMatrix(predicted, reference)
+	if !math.IsNaN(ddm[0][5]) || ddm[5][10] > 1e-9 {
+		t.Errorf("Mismatch matrix: (0,5) = %.3f want NaN, (5,10) = %.3f want 0", ddm[0][5], ddm[5][10])
+	}
+
+	// Too few pairs to superpose
+	predicted.Residues = predicted.Residues[:2]
+	if _, err := PerResidueRMSD(predicted, reference); err == nil {
+		t.Error("Expected error with only 2 paired residues")
+	}
+}
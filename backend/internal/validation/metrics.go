@@ -15,7 +15,8 @@ import (
 // CalculateRMSD computes Root Mean Square Deviation between two structures
 //
 // BIOCHEMIST:
-// RMSD measures average distance between corresponding atoms
+// RMSD measures average distance between corresponding atoms after optimal
+// superposition (CA atoms; all backbone atoms if the CA counts differ)
 // - RMSD < 1.0 Å: Excellent match (near-identical)
 // - RMSD < 2.0 Å: Good match (same fold)
 // - RMSD < 3.5 Å: Acceptable (similar structure)
@@ -38,25 +39,8 @@ func CalculateRMSD(protein1, protein2 *parser.Protein) (float64, error) {
 		return 0, nil // Cannot compute RMSD
 	}
 
-	// Calculate centroid of each structure
-	c1x, c1y, c1z := calculateCentroid(atoms1)
-	c2x, c2y, c2z := calculateCentroid(atoms2)
-
-	// Center both structures
-	centered1 := centerAtoms(atoms1, c1x, c1y, c1z)
-	centered2 := centerAtoms(atoms2, c2x, c2y, c2z)
-
-	// Calculate RMSD (without optimal rotation for simplicity)
-	// Full Kabsch algorithm would find optimal rotation
-	sumSqDist := 0.0
-	for i := 0; i < len(centered1); i++ {
-		dx := centered1[i].X - centered2[i].X
-		dy := centered1[i].Y - centered2[i].Y
-		dz := centered1[i].Z - centered2[i].Z
-		sumSqDist += dx*dx + dy*dy + dz*dz
-	}
-
-	rmsd := math.Sqrt(sumSqDist / float64(len(centered1)))
+	// Optimal rigid-body superposition (Horn/Kabsch, see superposition.go)
+	_, _, rmsd := superposeCoords(atomCoords(atoms1), atomCoords(atoms2))
 	return rmsd, nil
 }
 
@@ -84,21 +68,16 @@ func CalculateTMScore(protein1, protein2 *parser.Protein, targetLength int) floa
 		targetLength = n
 	}
 
-	// TM-score normalization: d0 = 1.24 * ³√(L-15) - 1.8 for L > 15
-	var d0 float64
-	if targetLength > 15 {
-		d0 = 1.24*math.Pow(float64(targetLength-15), 1.0/3.0) - 1.8
-	} else {
-		d0 = 0.5
-	}
+	d0 := tmScoreD0(targetLength)
+
+	// Score after the global CA superposition
+	mobile, target := atomCoords(atoms1), atomCoords(atoms2)
+	rot, trans, _ := superposeCoords(mobile, target)
 
 	// Calculate sum of normalized distances
 	sum := 0.0
 	for i := 0; i < n; i++ {
-		dx := atoms1[i].X - atoms2[i].X
-		dy := atoms1[i].Y - atoms2[i].Y
-		dz := atoms1[i].Z - atoms2[i].Z
-		di := math.Sqrt(dx*dx + dy*dy + dz*dz)
+		di := transformedDistance(rot, trans, mobile[i], target[i])
 
 		// TM-score weight: 1 / (1 + (di/d0)²)
 		sum += 1.0 / (1.0 + (di/d0)*(di/d0))
@@ -112,42 +91,22 @@ func CalculateTMScore(protein1, protein2 *parser.Protein, targetLength int) floa
 //
 // BIOCHEMIST:
 // GDT_TS measures % of residues within distance thresholds
-// Average of: % within 1Å, 2Å, 4Å, 8Å
+// Average of: % within 1Å, 2Å, 4Å, 8Å, each after the superposition that
+// maximizes that count (see gdt.go). Returns a fraction in [0, 1].
 //
 // Citation: Zemla, A. (2003). "LGA: A method for finding 3D similarities
 // in protein structures." NAR 31.13: 3370-3374.
 func CalculateGDT_TS(protein1, protein2 *parser.Protein) float64 {
-	atoms1 := getCAlphaAtoms(protein1)
-	atoms2 := getCAlphaAtoms(protein2)
-
-	if len(atoms1) != len(atoms2) || len(atoms1) == 0 {
-		return 0
-	}
-
-	n := float64(len(atoms1))
-
-	// Count residues within distance thresholds
-	thresholds := []float64{1.0, 2.0, 4.0, 8.0}
-	scores := make([]float64, len(thresholds))
-
-	for i, threshold := range thresholds {
-		count := 0
-		for j := 0; j < len(atoms1); j++ {
-			dx := atoms1[j].X - atoms2[j].X
-			dy := atoms1[j].Y - atoms2[j].Y
-			dz := atoms1[j].Z - atoms2[j].Z
-			dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	return gdtScore(protein1, protein2, gdtTSCutoffs)
+}
 
-			if dist <= threshold {
-				count++
-			}
-		}
-		scores[i] = float64(count) / n
+// tmScoreD0 is the TM-score distance scale for a target of the given
+// length: d0 = 1.24 * ³√(L-15) - 1.8 for L > 15, 0.5 Å otherwise
+func tmScoreD0(targetLength int) float64 {
+	if targetLength > 15 {
+		return 1.24*math.Pow(float64(targetLength-15), 1.0/3.0) - 1.8
 	}
-
-	// GDT_TS = average of 4 thresholds
-	gdtTS := (scores[0] + scores[1] + scores[2] + scores[3]) / 4.0
-	return gdtTS
+	return 0.5
 }
 
 // Helper functions
@@ -196,23 +155,13 @@ func calculateCentroid(atoms []*parser.Atom) (cx, cy, cz float64) {
 	return
 }
 
-func centerAtoms(atoms []*parser.Atom, cx, cy, cz float64) []*parser.Atom {
-	centered := make([]*parser.Atom, len(atoms))
-	for i, atom := range atoms {
-		centered[i] = &parser.Atom{
-			X: atom.X - cx,
-			Y: atom.Y - cy,
-			Z: atom.Z - cz,
-		}
-	}
-	return centered
-}
-
 // StructureComparison holds all comparison metrics
 type StructureComparison struct {
 	RMSD    float64 // Root Mean Square Deviation (Å)
 	TMScore float64 // TM-score [0, 1]
 	GDT_TS  float64 // Global Distance Test Total Score [0, 1]
+	GDT_HA  float64 // Global Distance Test High Accuracy [0, 100]
+	LDDT    float64 // Local Distance Difference Test (CA) [0, 1]
 
 	NumResidues  int    // Number of residues compared
 	NumAtoms     int    // Number of atoms compared
@@ -230,6 +179,8 @@ func CompareStructures(predicted, experimental *parser.Protein) StructureCompari
 	numRes := len(predicted.Residues)
 	comparison.TMScore = CalculateTMScore(predicted, experimental, numRes)
 	comparison.GDT_TS = CalculateGDT_TS(predicted, experimental)
+	comparison.GDT_HA = CalculateGDT_HA(predicted, experimental)
+	comparison.LDDT = CalculateLDDT(predicted, experimental)
 
 	comparison.NumResidues = numRes
 	comparison.NumAtoms = len(predicted.Atoms)
@@ -0,0 +1,116 @@
+// Package validation - Model-to-model comparison
+//
+// The other metrics score a prediction against a native structure. When
+// iterating on pipeline settings there is usually no native, and the
+// question is how two of one's own models differ: by how much overall,
+// where, and whether they still share a fold. CompareModels answers all
+// three, so two configurations can be A/B tested on the same sequence.
+//
+// BIOCHEMIST: Two models with TM-score > 0.5 share a fold; a low RMSD with
+// one large local difference usually means a loop or terminus moved, not
+// a different topology
+// MATHEMATICIAN: RMSD, TM-score and the per-residue deviations all come
+// from one optimal CA superposition, so they are directly comparable
+package validation
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// SameFoldTMScore is the TM-score above which two models count as the same
+// fold (Xu & Zhang 2010: P-value ~ 10⁻⁵ for random structures)
+const SameFoldTMScore = 0.5
+
+// ModelDifferenceCutoff is the CA deviation (Å) beyond which a residue
+// counts as different between two superposed models
+const ModelDifferenceCutoff = 2.0
+
+// ModelComparison describes how two models of the same sequence differ
+type ModelComparison struct {
+	RMSD    float64 // CA RMSD after optimal superposition (Å)
+	TMScore float64 // TM-score after the same superposition, normalized by b's length
+
+	// CA deviation (Å) of each residue of b after superposition; NaN for
+	// residues with no partner in a
+	Deviations []float64
+
+	// Residues (indices into b, inclusive) of the contiguous run deviating
+	// by more than ModelDifferenceCutoff with the largest total deviation;
+	// both -1 if no residue deviates that much
+	LargestDifferenceStart int
+	LargestDifferenceEnd   int
+
+	MaxDeviation float64 // Largest per-residue deviation (Å)
+	NumPaired    int     // Number of CA pairs compared
+	SameFold     bool    // TMScore > SameFoldTMScore
+}
+
+// CompareModels superposes model a onto model b and reports their
+// structural differences
+//
+// Residues are paired as in PerResidueRMSD (by index when the lengths are
+// equal, by residue numbering otherwise), with b as the reference. Neither
+// model is modified. Returns an error if fewer than three residues pair up.
+//
+// Citation: Xu, J., & Zhang, Y. (2010). "How significant is a protein
+// structure similarity with TM-score = 0.5?" Bioinformatics 26(7): 889-895.
+func CompareModels(a, b *parser.Protein) (*ModelComparison, error) {
+	pairs, err := pairResidues(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) < 3 {
+		return nil, fmt.Errorf("only %d paired CA atoms, need at least 3 to superpose", len(pairs))
+	}
+
+	mobile := make([][3]float64, len(pairs))
+	target := make([][3]float64, len(pairs))
+	for k, p := range pairs {
+		mobile[k] = caCoord(a.Residues[p.predicted])
+		target[k] = caCoord(b.Residues[p.experimental])
+	}
+	rot, trans, rmsd := superposeCoords(mobile, target)
+
+	comparison := &ModelComparison{
+		RMSD:                   rmsd,
+		Deviations:             nanSlice(len(b.Residues)),
+		LargestDifferenceStart: -1,
+		LargestDifferenceEnd:   -1,
+		NumPaired:              len(pairs),
+	}
+
+	d0 := tmScoreD0(len(b.Residues))
+	sum := 0.0
+	for k, p := range pairs {
+		d := transformedDistance(rot, trans, mobile[k], target[k])
+		comparison.Deviations[p.experimental] = d
+		comparison.MaxDeviation = math.Max(comparison.MaxDeviation, d)
+		sum += 1.0 / (1.0 + (d/d0)*(d/d0))
+	}
+	comparison.TMScore = sum / float64(len(b.Residues))
+	comparison.SameFold = comparison.TMScore > SameFoldTMScore
+
+	// Largest contiguous run of differing residues (NaN ends a run)
+	bestTotal := 0.0
+	for start := 0; start < len(comparison.Deviations); {
+		if !(comparison.Deviations[start] > ModelDifferenceCutoff) {
+			start++
+			continue
+		}
+		end, total := start, 0.0
+		for end < len(comparison.Deviations) && comparison.Deviations[end] > ModelDifferenceCutoff {
+			total += comparison.Deviations[end]
+			end++
+		}
+		if total > bestTotal {
+			bestTotal = total
+			comparison.LargestDifferenceStart, comparison.LargestDifferenceEnd = start, end-1
+		}
+		start = end
+	}
+
+	return comparison, nil
+}
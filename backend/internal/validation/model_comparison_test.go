@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCompareModelsRotatedCopy checks a model compared to a rigidly moved
+// copy of itself differs nowhere
+func TestCompareModelsRotatedCopy(t *testing.T) {
+	model := gdtTestHelix()
+	c, s := math.Cos(2.3), math.Sin(2.3)
+	rotated := copyCAProtein(model, func(x, y, z float64) (float64, float64, float64) {
+		return x - 4, c*y - s*z + 7, s*y + c*z + 1.5
+	})
+
+	comparison, err := CompareModels(rotated, model)
+	if err != nil {
+		t.Fatalf("CompareModels failed: %v", err)
+	}
+	t.Logf("RMSD %.2e Å, TM-score %.6f, max deviation %.2e Å over %d pairs",
+		comparison.RMSD, comparison.TMScore, comparison.MaxDeviation, comparison.NumPaired)
+
+	if comparison.RMSD > 1e-6 {
+		t.Errorf("RMSD %.2e Å, want ≈ 0", comparison.RMSD)
+	}
+	if math.Abs(comparison.TMScore-1) > 1e-6 {
+		t.Errorf("TM-score %.6f, want ≈ 1", comparison.TMScore)
+	}
+	if !comparison.SameFold {
+		t.Error("Rotated copy not reported as the same fold")
+	}
+	if comparison.NumPaired != len(model.Residues) || len(comparison.Deviations) != len(model.Residues) {
+		t.Errorf("%d pairs and %d deviations, want %d", comparison.NumPaired, len(comparison.Deviations), len(model.Residues))
+	}
+	if comparison.LargestDifferenceStart != -1 || comparison.LargestDifferenceEnd != -1 {
+		t.Errorf("Largest difference %d-%d, want none", comparison.LargestDifferenceStart, comparison.LargestDifferenceEnd)
+	}
+}
+
+// TestCompareModelsMovedSegment checks a displaced segment of a 60-residue
+// helix is reported as the largest difference while the fold still matches
+func TestCompareModelsMovedSegment(t *testing.T) {
+	phi, psi := make([]float64, 60), make([]float64, 60)
+	for i := range phi {
+		phi[i], psi[i] = -57, -47
+	}
+	model := buildTestBackbone(strings.Repeat("A", 60), phi, psi)
+	moved := copyCAProtein(model, func(x, y, z float64) (float64, float64, float64) { return x, y, z })
+	for i := 30; i <= 33; i++ {
+		moved.Residues[i].CA.X += 6
+		moved.Residues[i].CA.Y += 7
+	}
+
+	comparison, err := CompareModels(moved, model)
+	if err != nil {
+		t.Fatalf("CompareModels failed: %v", err)
+	}
+	t.Logf("RMSD %.2f Å, TM-score %.3f, largest difference at residues %d-%d (max %.2f Å)",
+		comparison.RMSD, comparison.TMScore, comparison.LargestDifferenceStart,
+		comparison.LargestDifferenceEnd, comparison.MaxDeviation)
+
+	if comparison.LargestDifferenceStart != 30 || comparison.LargestDifferenceEnd != 33 {
+		t.Errorf("Largest difference at %d-%d, want 30-33", comparison.LargestDifferenceStart, comparison.LargestDifferenceEnd)
+	}
+	if !comparison.SameFold {
+		t.Errorf("TM-score %.3f: moved segment reported as a different fold", comparison.TMScore)
+	}
+	if comparison.RMSD < 1 {
+		t.Errorf("RMSD %.2f Å, want the segment to show", comparison.RMSD)
+	}
+
+	if _, err := CompareModels(nil, model); err == nil {
+		t.Error("CompareModels accepted a nil model")
+	}
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// uniformBackbone builds a poly-Ala backbone with the given (φ, ψ) in degrees
+// for residues [from, to) and extended elsewhere
+func uniformBackbone(t *testing.T, n, from, to int, phi, psi float64) *parser.Protein {
+	t.Helper()
+	deg := math.Pi / 180.0
+	angles := make([]geometry.RamachandranAngles, n)
+	for i := range angles {
+		angles[i] = geometry.RamachandranAngles{Phi: 180 * deg, Psi: 180 * deg}
+		if i >= from && i < to {
+			angles[i] = geometry.RamachandranAngles{Phi: phi * deg, Psi: psi * deg}
+		}
+	}
+	protein, err := geometry.BuildProteinFromAngles(strings.Repeat("A", n), angles)
+	if err != nil {
+		t.Fatalf("BuildProteinFromAngles failed: %v", err)
+	}
+	return protein
+}
+
+// TestNativeContactFraction checks Q = 1 for the native itself, ~0 for a
+// fully extended chain, and in between for a half-folded one
+func TestNativeContactFraction(t *testing.T) {
+	const n, cutoff, slack = 24, 8.0, 0.2
+	native := uniformBackbone(t, n, 0, n, -57, -47)
+	extended := uniformBackbone(t, n, 0, 0, 0, 0)
+	half := uniformBackbone(t, n, 0, n/2, -57, -47)
+
+	qNative := NativeContactFraction(native, native, cutoff, slack)
+	qExtended := NativeContactFraction(extended, native, cutoff, slack)
+	qHalf := NativeContactFraction(half, native, cutoff, slack)
+	t.Logf("Q: native %.3f, half helix %.3f, extended %.3f", qNative, qHalf, qExtended)
+
+	if qNative != 1 {
+		t.Errorf("Q of native against itself = %.3f, want 1", qNative)
+	}
+	if qExtended > 0.05 {
+		t.Errorf("Q of extended chain = %.3f, want ≈ 0", qExtended)
+	}
+	if qHalf < 0.3 || qHalf > 0.7 {
+		t.Errorf("Q of half-formed helix = %.3f, want ≈ 0.5", qHalf)
+	}
+
+	if q := NativeContactFraction(uniformBackbone(t, n-1, 0, n-1, -57, -47), native, cutoff, slack); q != 0 {
+		t.Errorf("Q for mismatched lengths = %.3f, want 0", q)
+	}
+}
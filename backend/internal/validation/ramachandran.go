@@ -0,0 +1,270 @@
+// Package validation - Ramachandran probability scoring
+//
+// Basin explorer and constraint refinement talk about "allowed regions", but a
+// quality metric needs probabilities. This scores each residue's (φ, ψ) on a
+// binned probability map per residue category, MolProbity style.
+//
+// BIOCHEMIST: Four categories with distinct maps - general, glycine (no Cβ),
+// proline (ring locks φ ≈ -65°) and pre-proline (residue before Pro, where
+// the Pro Cδ excludes α-helix and favors extended ψ)
+// PHYSICIST: Maps are wrapped-Gaussian mixtures placed on the populated
+// regions of high-resolution structure statistics, binned at 10°
+// MATHEMATICIAN: Favored/allowed thresholds are density contours enclosing
+// 98% / 99.95% of the probability mass, as in MolProbity
+// ETHICIST: Smoothed approximation of Top8000 statistics, not the raw data;
+// good for ranking models, not for publishing validation reports
+//
+// CITATION:
+// Lovell, S. C., et al. (2003). "Structure validation by Cα geometry: φ,ψ and Cβ deviation."
+// Proteins 50(3): 437-450.
+//
+// Williams, C. J., et al. (2018). "MolProbity: More and better reference data for improved
+// all-atom structure validation." Protein Sci. 27(1): 293-315.
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Ramachandran map binning
+const (
+	ramaBinDeg = 10.0
+	ramaBins   = 36 // 360° / 10°
+
+	// ramaFavoredMass and ramaAllowedMass define the density contours
+	ramaFavoredMass = 0.98
+	ramaAllowedMass = 0.9995
+
+	// ramaFloor avoids log(0) in empty bins
+	ramaFloor = 1e-8
+)
+
+// ramaCategory selects the probability map for a residue
+type ramaCategory int
+
+const (
+	ramaGeneral ramaCategory = iota
+	ramaGlycine
+	ramaProline
+	ramaPreProline
+)
+
+// ramaPeak is one populated region: center and width (degrees), relative weight
+type ramaPeak struct {
+	Phi, Psi       float64
+	SigPhi, SigPsi float64
+	Weight         float64
+}
+
+// ramaPeaks approximate the Top8000 density per category
+var ramaPeaks = map[ramaCategory][]ramaPeak{
+	ramaGeneral: {
+		{Phi: -63, Psi: -43, SigPhi: 12, SigPsi: 12, Weight: 0.50},  // α-helix
+		{Phi: -120, Psi: 130, SigPhi: 25, SigPsi: 22, Weight: 0.25}, // β-sheet
+		{Phi: -67, Psi: 145, SigPhi: 13, SigPsi: 15, Weight: 0.14},  // PPII
+		{Phi: -90, Psi: 0, SigPhi: 18, SigPsi: 18, Weight: 0.07},    // Bridge
+		{Phi: 57, Psi: 42, SigPhi: 10, SigPsi: 12, Weight: 0.04},    // Left-handed helix
+	},
+	ramaGlycine: {
+		{Phi: -65, Psi: -40, SigPhi: 15, SigPsi: 15, Weight: 0.20}, // α-helix
+		{Phi: 65, Psi: 40, SigPhi: 15, SigPsi: 15, Weight: 0.20},   // Left-handed helix (mirror)
+		{Phi: -80, Psi: 170, SigPhi: 20, SigPsi: 20, Weight: 0.15}, // Extended
+		{Phi: 80, Psi: -170, SigPhi: 20, SigPsi: 20, Weight: 0.15}, // Extended (mirror)
+		{Phi: 180, Psi: 180, SigPhi: 25, SigPsi: 25, Weight: 0.20}, // Fully extended
+		{Phi: 90, Psi: 0, SigPhi: 18, SigPsi: 20, Weight: 0.10},    // Left bridge
+	},
+	ramaProline: {
+		{Phi: -65, Psi: -35, SigPhi: 10, SigPsi: 15, Weight: 0.40}, // α-helix
+		{Phi: -65, Psi: 145, SigPhi: 10, SigPsi: 15, Weight: 0.55}, // PPII
+		{Phi: -85, Psi: 70, SigPhi: 10, SigPsi: 12, Weight: 0.05},  // γ-turn
+	},
+	ramaPreProline: {
+		{Phi: -120, Psi: 140, SigPhi: 25, SigPsi: 18, Weight: 0.45}, // β-sheet
+		{Phi: -70, Psi: 145, SigPhi: 15, SigPsi: 15, Weight: 0.35},  // PPII
+		{Phi: -65, Psi: -40, SigPhi: 12, SigPsi: 12, Weight: 0.15},  // α-helix
+		{Phi: -140, Psi: 80, SigPhi: 15, SigPsi: 15, Weight: 0.05},  // ζ region
+	},
+}
+
+// ramaMap is a normalized binned density with its contour thresholds
+type ramaMap struct {
+	prob          [ramaBins][ramaBins]float64
+	cdf           [ramaBins * ramaBins]float64 // Cumulative prob in (φ-major) bin order
+	favoredCutoff float64
+	allowedCutoff float64
+}
+
+// ramaMaps are built once from ramaPeaks
+var ramaMaps = buildRamaMaps()
+
+// RamachandranScore scores backbone (φ, ψ) against Ramachandran probability maps
+//
+// Returns the mean log-probability per scored residue (higher is better; the
+// uniform map gives ln(1/1296) ≈ -7.2) and the indices of residues outside
+// the allowed contour. Terminal residues and undefined angles are skipped.
+func RamachandranScore(protein *parser.Protein) (float64, []int) {
+	if protein == nil || len(protein.Residues) < 3 {
+		return 0.0, nil
+	}
+
+	angles := geometry.CalculateRamachandran(protein)
+	residues := protein.Residues
+
+	sumLogProb := 0.0
+	scored := 0
+	outliers := make([]int, 0)
+
+	for i := 1; i < len(residues)-1; i++ {
+		phi, psi := angles[i].Phi, angles[i].Psi
+		if math.IsNaN(phi) || math.IsNaN(psi) {
+			continue
+		}
+
+		m := ramaMaps[residueRamaCategory(residues, i)]
+		p := m.lookup(phi*180.0/math.Pi, psi*180.0/math.Pi)
+
+		sumLogProb += math.Log(p)
+		scored++
+
+		if p < m.allowedCutoff {
+			outliers = append(outliers, i)
+		}
+	}
+
+	if scored == 0 {
+		return 0.0, outliers
+	}
+	return sumLogProb / float64(scored), outliers
+}
+
+// SampleRamachandran draws (φ, ψ) in degrees from the probability map of a
+// residue's category
+//
+// name and next are residue names (one- or three-letter; next is "" at the
+// C-terminus), which select the general, glycine, proline or pre-proline
+// map. A bin is drawn by its probability, then a point uniformly inside it.
+func SampleRamachandran(name, next string, rng *rand.Rand) (phiDeg, psiDeg float64) {
+	m := ramaMaps[ramaCategoryOf(name, next)]
+
+	u := rng.Float64() * m.cdf[len(m.cdf)-1]
+	bin := sort.SearchFloat64s(m.cdf[:], u)
+	if bin >= len(m.cdf) {
+		bin = len(m.cdf) - 1
+	}
+
+	phiDeg = -180.0 + (float64(bin/ramaBins)+rng.Float64())*ramaBinDeg
+	psiDeg = -180.0 + (float64(bin%ramaBins)+rng.Float64())*ramaBinDeg
+	return phiDeg, psiDeg
+}
+
+// residueRamaCategory classifies residue i (Gly/Pro checked before pre-Pro)
+func residueRamaCategory(residues []*parser.Residue, i int) ramaCategory {
+	next := ""
+	if i+1 < len(residues) {
+		next = residues[i+1].Name
+	}
+	return ramaCategoryOf(residues[i].Name, next)
+}
+
+// ramaCategoryOf classifies a residue by its name and the next residue's name
+func ramaCategoryOf(name, next string) ramaCategory {
+	switch name {
+	case "GLY", "G":
+		return ramaGlycine
+	case "PRO", "P":
+		return ramaProline
+	}
+	if next == "PRO" || next == "P" {
+		return ramaPreProline
+	}
+	return ramaGeneral
+}
+
+// lookup returns the bin probability for (φ, ψ) in degrees
+func (m *ramaMap) lookup(phiDeg, psiDeg float64) float64 {
+	return m.prob[ramaBinIndex(phiDeg)][ramaBinIndex(psiDeg)]
+}
+
+// ramaBinIndex maps an angle in degrees to [0, ramaBins)
+func ramaBinIndex(deg float64) int {
+	idx := int(math.Floor((deg + 180.0) / ramaBinDeg))
+	idx %= ramaBins
+	if idx < 0 {
+		idx += ramaBins
+	}
+	return idx
+}
+
+// buildRamaMaps evaluates each mixture at bin centers and derives contours
+func buildRamaMaps() map[ramaCategory]*ramaMap {
+	maps := make(map[ramaCategory]*ramaMap, len(ramaPeaks))
+
+	for category, peaks := range ramaPeaks {
+		m := &ramaMap{}
+		total := 0.0
+
+		for a := 0; a < ramaBins; a++ {
+			phi := -180.0 + (float64(a)+0.5)*ramaBinDeg
+			for b := 0; b < ramaBins; b++ {
+				psi := -180.0 + (float64(b)+0.5)*ramaBinDeg
+
+				density := 0.0
+				for _, pk := range peaks {
+					dPhi := wrapDegrees(phi - pk.Phi)
+					dPsi := wrapDegrees(psi - pk.Psi)
+					density += pk.Weight * math.Exp(-0.5*(dPhi*dPhi/(pk.SigPhi*pk.SigPhi)+dPsi*dPsi/(pk.SigPsi*pk.SigPsi))) /
+						(pk.SigPhi * pk.SigPsi)
+				}
+				m.prob[a][b] = density
+				total += density
+			}
+		}
+
+		// Normalize and apply floor
+		values := make([]float64, 0, ramaBins*ramaBins)
+		cumulative := 0.0
+		for a := 0; a < ramaBins; a++ {
+			for b := 0; b < ramaBins; b++ {
+				m.prob[a][b] = math.Max(m.prob[a][b]/total, ramaFloor)
+				values = append(values, m.prob[a][b])
+				cumulative += m.prob[a][b]
+				m.cdf[a*ramaBins+b] = cumulative
+			}
+		}
+
+		m.favoredCutoff = densityContour(values, ramaFavoredMass)
+		m.allowedCutoff = densityContour(values, ramaAllowedMass)
+		maps[category] = m
+	}
+
+	return maps
+}
+
+// densityContour returns the bin probability above which bins hold the given mass
+func densityContour(values []float64, mass float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	cumulative := 0.0
+	for _, v := range sorted {
+		cumulative += v
+		if cumulative >= mass {
+			return v
+		}
+	}
+	return sorted[len(sorted)-1]
+}
+
+// wrapDegrees wraps an angle difference to [-180, 180)
+func wrapDegrees(d float64) float64 {
+	d = math.Mod(d+180.0, 360.0)
+	if d < 0 {
+		d += 360.0
+	}
+	return d - 180.0
+}
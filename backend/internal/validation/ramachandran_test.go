@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestRamachandranScoreHelix checks an ideal α-helix is favored with no outliers
+func TestRamachandranScoreHelix(t *testing.T) {
+	sequence := "AAAAAAAAAAAA"
+	phi := make([]float64, len(sequence))
+	psi := make([]float64, len(sequence))
+	for i := range phi {
+		phi[i], psi[i] = -57, -47
+	}
+
+	protein := buildTestBackbone(sequence, phi, psi)
+	meanLogProb, outliers := RamachandranScore(protein)
+	t.Logf("Ideal helix: mean log P = %.3f, outliers = %v", meanLogProb, outliers)
+
+	if len(outliers) != 0 {
+		t.Errorf("Ideal helix should have no outliers, got %v", outliers)
+	}
+
+	general := ramaMaps[ramaGeneral]
+	if p := general.lookup(-57, -47); p < general.favoredCutoff {
+		t.Errorf("Helix (φ, ψ) probability %.4g below favored cutoff %.4g", p, general.favoredCutoff)
+	}
+
+	uniform := math.Log(1.0 / float64(ramaBins*ramaBins))
+	if meanLogProb <= uniform {
+		t.Errorf("Helix mean log P %.3f should beat uniform %.3f", meanLogProb, uniform)
+	}
+}
+
+// TestRamachandranScoreRandom checks random (φ, ψ) produces outliers
+func TestRamachandranScoreRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	sequence := "AVLIKEDSTNQAVLIKEDSTNQAVLIKEDSTNQ"
+	phi := make([]float64, len(sequence))
+	psi := make([]float64, len(sequence))
+	for i := range phi {
+		phi[i] = rng.Float64()*360 - 180
+		psi[i] = rng.Float64()*360 - 180
+	}
+
+	meanLogProb, outliers := RamachandranScore(buildTestBackbone(sequence, phi, psi))
+	t.Logf("Random: mean log P = %.3f, %d/%d outliers", meanLogProb, len(outliers), len(sequence)-2)
+
+	if len(outliers) < (len(sequence)-2)/4 {
+		t.Errorf("Expected many outliers for random angles, got %d", len(outliers))
+	}
+
+	helixLogProb, _ := RamachandranScore(buildTestBackbone(sequence, constSlice(len(sequence), -57), constSlice(len(sequence), -47)))
+	if meanLogProb >= helixLogProb {
+		t.Errorf("Random mean log P %.3f should be below helix %.3f", meanLogProb, helixLogProb)
+	}
+}
+
+// TestRamachandranCategories checks Gly/Pro/pre-Pro classification and maps
+func TestRamachandranCategories(t *testing.T) {
+	protein := buildTestBackbone("AGAPA", constSlice(5, -65), constSlice(5, 145))
+
+	expected := []ramaCategory{ramaGeneral, ramaGlycine, ramaPreProline, ramaProline, ramaGeneral}
+	for i, want := range expected {
+		if got := residueRamaCategory(protein.Residues, i); got != want {
+			t.Errorf("Residue %d: category %d, want %d", i, got, want)
+		}
+	}
+
+	// Left-handed region: allowed for glycine, outlier for proline
+	if p := ramaMaps[ramaGlycine].lookup(80, 10); p < ramaMaps[ramaGlycine].allowedCutoff {
+		t.Errorf("Glycine (80, 10) should be allowed, p = %.3g", p)
+	}
+	if p := ramaMaps[ramaProline].lookup(60, 40); p >= ramaMaps[ramaProline].allowedCutoff {
+		t.Errorf("Proline (60, 40) should be an outlier, p = %.3g", p)
+	}
+}
+
+func constSlice(n int, v float64) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
+
+// TestSampleRamachandranProline checks proline samples stay near φ = -65°
+func TestSampleRamachandranProline(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	inRange := 0
+	const n = 2000
+	for i := 0; i < n; i++ {
+		phi, psi := SampleRamachandran("PRO", "ALA", rng)
+		if phi < -180 || phi >= 180 || psi < -180 || psi >= 180 {
+			t.Fatalf("Sample (%.1f, %.1f) outside [-180, 180)", phi, psi)
+		}
+		if phi > -100 && phi < -30 {
+			inRange++
+		}
+	}
+
+	t.Logf("Proline φ in (-100°, -30°): %d/%d", inRange, n)
+	if float64(inRange)/n < 0.95 {
+		t.Errorf("Only %d/%d proline samples near φ = -65°", inRange, n)
+	}
+}
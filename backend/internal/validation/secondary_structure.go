@@ -0,0 +1,215 @@
+// Package validation - DSSP secondary structure assignment
+//
+// We predict secondary structure from sequence (Chou-Fasman, GOR) but need the
+// observed secondary structure of a 3D model to compute Q3 against native.
+// This implements the core of DSSP: electrostatic backbone H-bond energies,
+// n-turns, α-helices and β-bridges.
+//
+// BIOCHEMIST: Helices = consecutive i→i+4 H-bonds; strands = bridge ladders
+// PHYSICIST: H-bond energy from point charges on C, O, N, H
+// MATHEMATICIAN: Pattern matching on the H-bond graph
+// ETHICIST: Deterministic, standard definition comparable across tools
+//
+// Note: physics.DetectHydrogenBonds uses distance/angle cutoffs and requires
+// explicit H atoms. DSSP defines H-bonds by its own energy criterion with
+// H placed from the preceding peptide, so that definition is used here.
+//
+// CITATION:
+// Kabsch, W., & Sander, C. (1983). "Dictionary of protein secondary structure: Pattern
+// recognition of hydrogen-bonded and geometrical features." Biopolymers 22(12): 2577-2637.
+package validation
+
+import (
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// DSSP constants
+const (
+	// dsspCoupling = q1 × q2 × f = 0.42e × 0.20e × 332 (kcal/mol·Å)
+	dsspCoupling = 0.084 * 332.0
+
+	// dsspHBondCutoff: H-bond if E < -0.5 kcal/mol
+	dsspHBondCutoff = -0.5
+
+	// dsspNHLength: N-H bond length used to place H (Å)
+	dsspNHLength = 1.0
+)
+
+// AssignSecondaryStructure assigns H/E/T/C per residue from backbone coordinates
+//
+// ALGORITHM (DSSP core):
+//  1. H-bond(i→j) from C=O of i to N-H of j if E < -0.5 kcal/mol, where
+//     E = 0.084 × 332 × (1/r_ON + 1/r_CH - 1/r_OH - 1/r_CN)
+//  2. n-turn at i if H-bond(i→i+n), n = 3, 4, 5
+//  3. α-helix (H): 4-turns at i-1 and i → residues i..i+3
+//  4. β-bridge (E): parallel or antiparallel bridge patterns, |i-j| > 2
+//  5. Turn (T): residues i+1..i+n-1 covered by any n-turn, not already H/E
+//  6. Everything else: coil (C)
+//
+// 3₁₀ (G) and π (I) helices are reported as T; isolated bridges (B) as E.
+func AssignSecondaryStructure(protein *parser.Protein) []prediction.SecondaryStructureType {
+	if protein == nil || len(protein.Residues) == 0 {
+		return nil
+	}
+
+	n := len(protein.Residues)
+	ss := make([]prediction.SecondaryStructureType, n)
+	hbond := dsspHBondMatrix(protein)
+
+	has := func(i, j int) bool {
+		return i >= 0 && j >= 0 && i < n && j < n && hbond[i][j]
+	}
+
+	// n-turns
+	turn := make([][6]bool, n)
+	for i := 0; i < n; i++ {
+		for k := 3; k <= 5; k++ {
+			turn[i][k] = has(i, i+k)
+		}
+	}
+
+	// α-helix: two consecutive 4-turns
+	for i := 1; i < n; i++ {
+		if turn[i-1][4] && turn[i][4] {
+			for k := i; k <= i+3 && k < n; k++ {
+				ss[k] = prediction.AlphaHelix
+			}
+		}
+	}
+
+	// β-bridges
+	for i := 1; i < n-1; i++ {
+		for j := 1; j < n-1; j++ {
+			if abs(i-j) <= 2 {
+				continue
+			}
+
+			parallel := (has(i-1, j) && has(j, i+1)) || (has(j-1, i) && has(i, j+1))
+			antiparallel := (has(i, j) && has(j, i)) || (has(i-1, j+1) && has(j-1, i+1))
+
+			if (parallel || antiparallel) && ss[i] != prediction.AlphaHelix {
+				ss[i] = prediction.BetaSheet
+			}
+		}
+	}
+
+	// Turns
+	for i := 0; i < n; i++ {
+		for k := 3; k <= 5; k++ {
+			if !turn[i][k] {
+				continue
+			}
+			for m := i + 1; m < i+k && m < n; m++ {
+				if ss[m] == prediction.Coil {
+					ss[m] = prediction.Turn
+				}
+			}
+		}
+	}
+
+	return ss
+}
+
+// SecondaryStructureString renders an assignment as a string (e.g. "CHHHHTTC")
+func SecondaryStructureString(ss []prediction.SecondaryStructureType) string {
+	out := make([]byte, len(ss))
+	for i, s := range ss {
+		out[i] = s.String()[0]
+	}
+	return string(out)
+}
+
+// dsspHBondMatrix returns hbond[i][j] = true if C=O(i) accepts from N-H(j)
+func dsspHBondMatrix(protein *parser.Protein) [][]bool {
+	n := len(protein.Residues)
+	hbond := make([][]bool, n)
+	for i := range hbond {
+		hbond[i] = make([]bool, n)
+	}
+
+	// Place amide H for each residue (DSSP convention)
+	hPos := make([]*[3]float64, n)
+	for j := 1; j < n; j++ {
+		res, prev := protein.Residues[j], protein.Residues[j-1]
+		if res == nil || prev == nil || res.N == nil || prev.C == nil || prev.O == nil {
+			continue
+		}
+		if res.Name == "PRO" || res.Name == "P" {
+			continue // No amide hydrogen
+		}
+
+		dx := prev.C.X - prev.O.X
+		dy := prev.C.Y - prev.O.Y
+		dz := prev.C.Z - prev.O.Z
+		norm := math.Sqrt(dx*dx + dy*dy + dz*dz)
+		if norm == 0 {
+			continue
+		}
+		hPos[j] = &[3]float64{
+			res.N.X + dsspNHLength*dx/norm,
+			res.N.Y + dsspNHLength*dy/norm,
+			res.N.Z + dsspNHLength*dz/norm,
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		acc := protein.Residues[i]
+		if acc == nil || acc.C == nil || acc.O == nil {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if abs(i-j) < 2 || hPos[j] == nil {
+				continue
+			}
+			don := protein.Residues[j]
+
+			// Quick reject on CA-CA distance
+			if acc.CA != nil && don.CA != nil && atomDistance(acc.CA, don.CA) > 9.0 {
+				continue
+			}
+
+			h := hPos[j]
+			rON := atomDistance(acc.O, don.N)
+			rCH := pointDistance(acc.C, h)
+			rOH := pointDistance(acc.O, h)
+			rCN := atomDistance(acc.C, don.N)
+			if rON == 0 || rCH == 0 || rOH == 0 || rCN == 0 {
+				continue
+			}
+
+			energy := dsspCoupling * (1/rON + 1/rCH - 1/rOH - 1/rCN)
+			if energy < dsspHBondCutoff {
+				hbond[i][j] = true
+			}
+		}
+	}
+
+	return hbond
+}
+
+// atomDistance returns the distance between two atoms (Å)
+func atomDistance(a1, a2 *parser.Atom) float64 {
+	dx := a1.X - a2.X
+	dy := a1.Y - a2.Y
+	dz := a1.Z - a2.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// pointDistance returns the distance between an atom and a point (Å)
+func pointDistance(a *parser.Atom, p *[3]float64) float64 {
+	dx := a.X - p[0]
+	dy := a.Y - p[1]
+	dz := a.Z - p[2]
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// abs returns the absolute value of an integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/geometry"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/prediction"
+)
+
+// trpCageSequence is Trp-cage TC5b (PDB 1L2Y)
+const trpCageSequence = "NLYIQWLKDGGPSSGRPPPS"
+
+// TestAssignSecondaryStructureTrpCage checks the N-terminal α-helix of a
+// Trp-cage model (1L2Y: helix at residues 2-8) is assigned H
+func TestAssignSecondaryStructureTrpCage(t *testing.T) {
+	phi := make([]float64, len(trpCageSequence))
+	psi := make([]float64, len(trpCageSequence))
+	for i := range phi {
+		if i <= 8 {
+			phi[i], psi[i] = -57, -47 // α-helix (residues 1-9)
+		} else {
+			phi[i], psi[i] = -75, 145 // Polyproline II tail
+		}
+	}
+
+	protein := buildTestBackbone(trpCageSequence, phi, psi)
+	ss := AssignSecondaryStructure(protein)
+	t.Logf("Sequence: %s", trpCageSequence)
+	t.Logf("DSSP:     %s", SecondaryStructureString(ss))
+
+	if len(ss) != len(trpCageSequence) {
+		t.Fatalf("Expected %d assignments, got %d", len(trpCageSequence), len(ss))
+	}
+
+	// Residues 2-8 (1-based) are helical in the NMR structure
+	for i := 1; i <= 7; i++ {
+		if ss[i] != prediction.AlphaHelix {
+			t.Errorf("Residue %d (%c) assigned %s, expected H", i+1, trpCageSequence[i], ss[i])
+		}
+	}
+
+	// Polyproline tail has no i→i+4 H-bonds
+	for i := 14; i < len(ss); i++ {
+		if ss[i] == prediction.AlphaHelix {
+			t.Errorf("Residue %d (%c) in PPII tail assigned H", i+1, trpCageSequence[i])
+		}
+	}
+}
+
+// TestAssignSecondaryStructureExtended checks an extended strand has no helix
+func TestAssignSecondaryStructureExtended(t *testing.T) {
+	seq := "AAAAAAAAAA"
+	phi := make([]float64, len(seq))
+	psi := make([]float64, len(seq))
+	for i := range phi {
+		phi[i], psi[i] = -120, 130
+	}
+
+	ss := AssignSecondaryStructure(buildTestBackbone(seq, phi, psi))
+	t.Logf("DSSP: %s", SecondaryStructureString(ss))
+
+	for i, s := range ss {
+		if s != prediction.Coil {
+			t.Errorf("Residue %d assigned %s in an isolated extended strand", i+1, s)
+		}
+	}
+
+	if AssignSecondaryStructure(nil) != nil {
+		t.Error("Expected nil assignment for nil protein")
+	}
+}
+
+// buildTestBackbone builds N, CA, C, O from per-residue φ/ψ (degrees) with NeRF
+func buildTestBackbone(sequence string, phiDeg, psiDeg []float64) *parser.Protein {
+	deg := math.Pi / 180.0
+	protein := &parser.Protein{Name: "test_backbone"}
+
+	n0 := geometry.Vector3{X: 0, Y: 0, Z: 0}
+	ca0 := geometry.Vector3{X: geometry.BondN_CA, Y: 0, Z: 0}
+	a := (180.0 - geometry.AngleN_CA_C) * deg
+	c0 := ca0.Add(geometry.Vector3{X: math.Cos(a), Y: math.Sin(a), Z: 0}.Scale(geometry.BondCA_C))
+
+	positions := [][3]geometry.Vector3{{n0, ca0, c0}}
+	for i := 1; i < len(sequence); i++ {
+		prev := positions[i-1]
+		nPos := placeTestAtom(prev[0], prev[1], prev[2], geometry.BondC_N, geometry.AngleCA_C_N*deg, psiDeg[i-1]*deg)
+		caPos := placeTestAtom(prev[1], prev[2], nPos, geometry.BondN_CA, geometry.AngleC_N_CA*deg, math.Pi)
+		cPos := placeTestAtom(prev[2], nPos, caPos, geometry.BondCA_C, geometry.AngleN_CA_C*deg, phiDeg[i]*deg)
+		positions = append(positions, [3]geometry.Vector3{nPos, caPos, cPos})
+	}
+
+	serial := 1
+	for i, p := range positions {
+		resName := string(sequence[i])
+		newAtom := func(name string, pos geometry.Vector3) *parser.Atom {
+			atom := &parser.Atom{Serial: serial, Name: name, ResName: resName, ChainID: "A",
+				ResSeq: i + 1, X: pos.X, Y: pos.Y, Z: pos.Z, Element: name[:1]}
+			serial++
+			protein.Atoms = append(protein.Atoms, atom)
+			return atom
+		}
+
+		oPos := placeTestAtom(p[0], p[1], p[2], geometry.BondC_O, geometry.AngleCA_C_O*deg, psiDeg[i]*deg+math.Pi)
+		protein.Residues = append(protein.Residues, &parser.Residue{
+			Name:    resName,
+			SeqNum:  i + 1,
+			ChainID: "A",
+			N:       newAtom("N", p[0]),
+			CA:      newAtom("CA", p[1]),
+			C:       newAtom("C", p[2]),
+			O:       newAtom("O", oPos),
+		})
+	}
+
+	return protein
+}
+
+// placeTestAtom places d from a, b, c, bond length, angle and torsion (NeRF)
+func placeTestAtom(a, b, c geometry.Vector3, bond, angle, torsion float64) geometry.Vector3 {
+	bc := c.Sub(b).Normalize()
+	nrm := b.Sub(a).Cross(bc).Normalize()
+	m := nrm.Cross(bc)
+
+	return c.Add(bc.Scale(-bond * math.Cos(angle))).
+		Add(m.Scale(bond * math.Sin(angle) * math.Cos(torsion))).
+		Add(nrm.Scale(bond * math.Sin(angle) * math.Sin(torsion)))
+}
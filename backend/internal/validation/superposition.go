@@ -0,0 +1,263 @@
+// Package validation - Optimal superposition
+//
+// MATHEMATICIAN: Optimal rotation via Horn's quaternion method, the
+// eigenvector of a 4×4 symmetric matrix built from the cross-covariance.
+// Equivalent to Kabsch, but never produces an improper rotation (reflection).
+//
+// Citation: Horn, B. K. P. (1987). "Closed-form solution of absolute orientation
+// using unit quaternions." J. Opt. Soc. Am. A 4(4): 629-642.
+package validation
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sarat-asymmetrica/foldvedic/backend/internal/parser"
+)
+
+// Superpose finds the rigid-body transform that best aligns mobile's CA
+// atoms onto target's: target ≈ rotation·mobile + translation
+//
+// CA atoms are paired in order, so both structures must have the same
+// number of them (at least three). rmsd is the CA RMSD after the fit. The
+// structures are not modified; use ApplyTransform to move mobile.
+func Superpose(mobile, target *parser.Protein) (rotation [3][3]float64, translation [3]float64, rmsd float64, err error) {
+	if mobile == nil || target == nil {
+		return rotation, translation, 0, fmt.Errorf("nil protein")
+	}
+	atoms1 := getCAlphaAtoms(mobile)
+	atoms2 := getCAlphaAtoms(target)
+	if len(atoms1) != len(atoms2) {
+		return rotation, translation, 0, fmt.Errorf("CA count mismatch: %d vs %d", len(atoms1), len(atoms2))
+	}
+	if len(atoms1) < 3 {
+		return rotation, translation, 0, fmt.Errorf("only %d CA atoms, need at least 3 to superpose", len(atoms1))
+	}
+
+	rotation, translation, rmsd = superposeCoords(atomCoords(atoms1), atomCoords(atoms2))
+	return rotation, translation, rmsd, nil
+}
+
+// ApplyTransform moves every atom of protein to rotation·x + translation,
+// e.g. with the transform from Superpose before writing a superposed PDB
+func ApplyTransform(protein *parser.Protein, rotation [3][3]float64, translation [3]float64) {
+	if protein == nil {
+		return
+	}
+
+	moved := make(map[*parser.Atom]bool, len(protein.Atoms))
+	move := func(atom *parser.Atom) {
+		if atom == nil || moved[atom] {
+			return
+		}
+		moved[atom] = true
+		p := [3]float64{atom.X, atom.Y, atom.Z}
+		var q [3]float64
+		for a := 0; a < 3; a++ {
+			q[a] = translation[a]
+			for b := 0; b < 3; b++ {
+				q[a] += rotation[a][b] * p[b]
+			}
+		}
+		atom.X, atom.Y, atom.Z = q[0], q[1], q[2]
+	}
+
+	for _, atom := range protein.Atoms {
+		move(atom)
+	}
+	// Residue backbone pointers normally alias Atoms; move any that do not
+	for _, res := range protein.Residues {
+		if res != nil {
+			move(res.N)
+			move(res.CA)
+			move(res.C)
+			move(res.O)
+		}
+	}
+}
+
+// CalculateSuperposedRMSD computes CA RMSD after optimal rigid-body superposition
+//
+// Two copies of the same structure in different orientations give RMSD ≈ 0.
+func CalculateSuperposedRMSD(protein1, protein2 *parser.Protein) (float64, error) {
+	atoms1 := getCAlphaAtoms(protein1)
+	atoms2 := getCAlphaAtoms(protein2)
+
+	if len(atoms1) == 0 || len(atoms2) == 0 {
+		return 0, fmt.Errorf("no CA atoms to superpose")
+	}
+	if len(atoms1) != len(atoms2) {
+		return 0, fmt.Errorf("CA count mismatch: %d vs %d", len(atoms1), len(atoms2))
+	}
+
+	_, _, rmsd := superposeCoords(atomCoords(atoms1), atomCoords(atoms2))
+	return rmsd, nil
+}
+
+// atomCoords extracts coordinates of atoms
+func atomCoords(atoms []*parser.Atom) [][3]float64 {
+	coords := make([][3]float64, len(atoms))
+	for i, atom := range atoms {
+		coords[i] = [3]float64{atom.X, atom.Y, atom.Z}
+	}
+	return coords
+}
+
+// superposeCoords finds the rotation R and translation t minimizing
+// Σ |R·mobile_i + t - target_i|², returning R, t and the resulting RMSD
+func superposeCoords(mobile, target [][3]float64) ([3][3]float64, [3]float64, float64) {
+	return weightedSuperposeCoords(mobile, target, nil)
+}
+
+// weightedSuperposeCoords is superposeCoords minimizing
+// Σ w_i |R·mobile_i + t - target_i|², returning the weighted RMSD
+// sqrt(Σ w_i d_i² / Σ w_i); nil weights are all 1
+func weightedSuperposeCoords(mobile, target [][3]float64, weights []float64) ([3][3]float64, [3]float64, float64) {
+	n := len(mobile)
+	var identity [3][3]float64
+	identity[0][0], identity[1][1], identity[2][2] = 1, 1, 1
+	if n == 0 {
+		return identity, [3]float64{}, 0
+	}
+	weight := func(i int) float64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[i]
+	}
+
+	// Weighted centroids
+	var cm, ct [3]float64
+	wSum := 0.0
+	for i := 0; i < n; i++ {
+		w := weight(i)
+		wSum += w
+		for k := 0; k < 3; k++ {
+			cm[k] += w * mobile[i][k]
+			ct[k] += w * target[i][k]
+		}
+	}
+	if wSum <= 0 {
+		return identity, [3]float64{}, 0
+	}
+	for k := 0; k < 3; k++ {
+		cm[k] /= wSum
+		ct[k] /= wSum
+	}
+
+	// Cross-covariance S_ab = Σ w m_a t_b and squared norms
+	var s [3][3]float64
+	normSum := 0.0
+	for i := 0; i < n; i++ {
+		w := weight(i)
+		var m, t [3]float64
+		for k := 0; k < 3; k++ {
+			m[k] = mobile[i][k] - cm[k]
+			t[k] = target[i][k] - ct[k]
+			normSum += w * (m[k]*m[k] + t[k]*t[k])
+		}
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				s[a][b] += w * m[a] * t[b]
+			}
+		}
+	}
+
+	sxx, sxy, sxz := s[0][0], s[0][1], s[0][2]
+	syx, syy, syz := s[1][0], s[1][1], s[1][2]
+	szx, szy, szz := s[2][0], s[2][1], s[2][2]
+
+	// Horn's symmetric 4×4 matrix
+	hm := [4][4]float64{
+		{sxx + syy + szz, syz - szy, szx - sxz, sxy - syx},
+		{syz - szy, sxx - syy - szz, sxy + syx, szx + sxz},
+		{szx - sxz, sxy + syx, -sxx + syy - szz, syz + szy},
+		{sxy - syx, szx + sxz, syz + szy, -sxx - syy + szz},
+	}
+
+	eigenvalues, eigenvectors := jacobiEigen4(hm)
+	best := 0
+	for i := 1; i < 4; i++ {
+		if eigenvalues[i] > eigenvalues[best] {
+			best = i
+		}
+	}
+
+	q0, q1, q2, q3 := eigenvectors[0][best], eigenvectors[1][best], eigenvectors[2][best], eigenvectors[3][best]
+	rot := [3][3]float64{
+		{q0*q0 + q1*q1 - q2*q2 - q3*q3, 2 * (q1*q2 - q0*q3), 2 * (q1*q3 + q0*q2)},
+		{2 * (q1*q2 + q0*q3), q0*q0 - q1*q1 + q2*q2 - q3*q3, 2 * (q2*q3 - q0*q1)},
+		{2 * (q1*q3 - q0*q2), 2 * (q2*q3 + q0*q1), q0*q0 - q1*q1 - q2*q2 + q3*q3},
+	}
+
+	// t = c_target - R·c_mobile
+	var trans [3]float64
+	for a := 0; a < 3; a++ {
+		trans[a] = ct[a]
+		for b := 0; b < 3; b++ {
+			trans[a] -= rot[a][b] * cm[b]
+		}
+	}
+
+	msd := (normSum - 2*eigenvalues[best]) / wSum
+	if msd < 0 {
+		msd = 0 // Round-off for identical structures
+	}
+
+	return rot, trans, math.Sqrt(msd)
+}
+
+// jacobiEigen4 diagonalizes a symmetric 4×4 matrix with cyclic Jacobi
+// rotations, returning eigenvalues and eigenvectors (as columns)
+func jacobiEigen4(a [4][4]float64) ([4]float64, [4][4]float64) {
+	var v [4][4]float64
+	for i := 0; i < 4; i++ {
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		off := 0.0
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				off += a[p][q] * a[p][q]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				if math.Abs(a[p][q]) < 1e-300 {
+					continue
+				}
+
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1.0 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1.0 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < 4; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < 4; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < 4; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	return [4]float64{a[0][0], a[1][1], a[2][2], a[3][3]}, v
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSuperposeRecoversTransform moves a helix by a known rotation and
+// translation, then checks Superpose returns the inverse transform and
+// ApplyTransform puts every atom back
+func TestSuperposeRecoversTransform(t *testing.T) {
+	target := gdtTestHelix()
+
+	// Rotation by 70° about the unit axis (1, 2, 2)/3, then a shift
+	axis := [3]float64{1.0 / 3, 2.0 / 3, 2.0 / 3}
+	angle := 70 * math.Pi / 180
+	c, s := math.Cos(angle), math.Sin(angle)
+	var rot [3][3]float64
+	for a := 0; a < 3; a++ {
+		for b := 0; b < 3; b++ {
+			rot[a][b] = (1 - c) * axis[a] * axis[b]
+			if a == b {
+				rot[a][b] += c
+			}
+		}
+	}
+	rot[0][1] -= s * axis[2]
+	rot[0][2] += s * axis[1]
+	rot[1][0] += s * axis[2]
+	rot[1][2] -= s * axis[0]
+	rot[2][0] -= s * axis[1]
+	rot[2][1] += s * axis[0]
+	shift := [3]float64{12.5, -7.0, 3.25}
+
+	mobile := copyCAProtein(target, func(x, y, z float64) (float64, float64, float64) {
+		p := [3]float64{x, y, z}
+		var q [3]float64
+		for a := 0; a < 3; a++ {
+			q[a] = shift[a]
+			for b := 0; b < 3; b++ {
+				q[a] += rot[a][b] * p[b]
+			}
+		}
+		return q[0], q[1], q[2]
+	})
+
+	rotation, translation, rmsd, err := Superpose(mobile, target)
+	if err != nil {
+		t.Fatalf("Superpose failed: %v", err)
+	}
+	t.Logf("RMSD after fit %.2e Å, translation (%.3f, %.3f, %.3f)", rmsd, translation[0], translation[1], translation[2])
+	if rmsd > 1e-6 {
+		t.Errorf("RMSD %.2e Å after superposing a rigid copy", rmsd)
+	}
+
+	// Inverse: R⁻¹ = Rᵀ, t⁻¹ = -Rᵀ·shift
+	for a := 0; a < 3; a++ {
+		wantT := 0.0
+		for b := 0; b < 3; b++ {
+			wantT -= rot[b][a] * shift[b]
+			if math.Abs(rotation[a][b]-rot[b][a]) > 1e-8 {
+				t.Errorf("rotation[%d][%d] = %.10f, want %.10f", a, b, rotation[a][b], rot[b][a])
+			}
+		}
+		if math.Abs(translation[a]-wantT) > 1e-6 {
+			t.Errorf("translation[%d] = %.8f, want %.8f", a, translation[a], wantT)
+		}
+	}
+
+	ApplyTransform(mobile, rotation, translation)
+	maxDev := 0.0
+	for i, res := range mobile.Residues {
+		for _, pair := range [][2]float64{
+			{res.N.X, target.Residues[i].N.X}, {res.CA.Y, target.Residues[i].CA.Y},
+			{res.C.Z, target.Residues[i].C.Z}, {res.O.X, target.Residues[i].O.X},
+		} {
+			maxDev = math.Max(maxDev, math.Abs(pair[0]-pair[1]))
+		}
+	}
+	if maxDev > 1e-6 {
+		t.Errorf("ApplyTransform left atoms up to %.2e Å from the target", maxDev)
+	}
+
+	if rmsd, err := CalculateRMSD(mobile, target); err != nil || rmsd > 1e-6 {
+		t.Errorf("CalculateRMSD after superposition %.2e (err %v)", rmsd, err)
+	}
+}
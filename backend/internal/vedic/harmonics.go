@@ -189,8 +189,8 @@ func calculateDigitalRootConsistency(angles []geometry.RamachandranAngles) float
 		psiInt := int(math.Abs(psiDeg) * 10)
 
 		// Calculate digital roots
-		drPhi := digitalRoot(phiInt)
-		drPsi := digitalRoot(psiInt)
+		drPhi := DigitalRoot(phiInt)
+		drPsi := DigitalRoot(psiInt)
 
 		// Check for Vedic consistency patterns
 		// Common patterns: DR(φ) = 6, DR(ψ) = 3 or 9
@@ -270,14 +270,3 @@ func calculateStructuralBreathingScore(protein *parser.Protein) float64 {
 	// Use max of both metrics (reward either Prana-Apana balance or compactness)
 	return math.Max(score, compactnessScore)
 }
-
-// digitalRoot computes Vedic digital root of a number
-//
-// Algorithm: Sum digits repeatedly until single digit
-// Formula: DR(n) = 1 + ((n - 1) mod 9) for n > 0
-func digitalRoot(n int) int {
-	if n == 0 {
-		return 0
-	}
-	return 1 + ((n - 1) % 9)
-}
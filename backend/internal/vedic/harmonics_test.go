@@ -132,13 +132,15 @@ func TestDigitalRoot(t *testing.T) {
 		{38, 2},  // 3 + 8 = 11 → 1 + 1 = 2
 		{123, 6}, // 1 + 2 + 3 = 6
 		{999, 9}, // 9 + 9 + 9 = 27 → 2 + 7 = 9
+		{137, 2}, // 1 + 3 + 7 = 11 → 1 + 1 = 2
+		{-137, 2},
 	}
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			result := digitalRoot(tt.input)
+			result := DigitalRoot(tt.input)
 			if result != tt.expected {
-				t.Errorf("digitalRoot(%d) = %d, expected %d", tt.input, result, tt.expected)
+				t.Errorf("DigitalRoot(%d) = %d, expected %d", tt.input, result, tt.expected)
 			}
 		})
 	}
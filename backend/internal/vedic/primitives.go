@@ -0,0 +1,71 @@
+// Package vedic - Digital root and golden-angle primitives
+//
+// CalculateVedicScore aggregates over a whole structure; these are the
+// per-number and per-angle building blocks it (and Monte Carlo biasing)
+// are made of, exposed so they can be used and tested on their own.
+//
+// MATHEMATICIAN: DR(n) = 1 + ((n - 1) mod 9) for n > 0 - the repeated digit
+// sum, equal to n mod 9 with 9 in place of 0
+// BIOCHEMIST: Dihedrals are quantized to whole degrees before taking digital
+// roots, so sub-degree noise does not flip the root
+package vedic
+
+import "math"
+
+// GoldenAngle is the phyllotaxis angle 2π/φ² (≈ 137.508°) in radians
+const GoldenAngle = 2 * math.Pi * PhiInvSquare
+
+// DigitalRoot returns the Vedic digital root of n
+//
+// Example: 137 → 1+3+7 = 11 → 1+1 = 2. DR(0) = 0; negative numbers use |n|.
+func DigitalRoot(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	if n == 0 {
+		return 0
+	}
+	return 1 + ((n - 1) % 9)
+}
+
+// DigitalRootFloat returns the digital root of an angle in radians after
+// rounding it to whole degrees
+//
+// The sign is ignored (φ = -57° has the root of 57); NaN or infinite
+// angles return 0.
+func DigitalRootFloat(x float64) int {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0
+	}
+	degrees := math.Round(math.Abs(x * 180.0 / math.Pi))
+	return DigitalRoot(int(degrees))
+}
+
+// HarmoniousRoots reports whether two digital roots form a harmonious pair
+//
+// Based on the Vedic square (multiplication table mod 9): equal roots, or
+// the 3/6 pair.
+func HarmoniousRoots(a, b int) bool {
+	return a == b || (a == 3 && b == 6) || (a == 6 && b == 3)
+}
+
+// GoldenAlignment returns how close an angle (radians) is to the nearest
+// non-zero multiple of the golden angle, in [0, 1]
+//
+// 1 at k × 137.508°, falling smoothly (raised cosine) to 0 half a golden
+// angle away. The sign is ignored; angles below half a golden angle - where
+// the nearest multiple would be zero - score 0.
+func GoldenAlignment(angleRad float64) float64 {
+	if math.IsNaN(angleRad) || math.IsInf(angleRad, 0) {
+		return 0
+	}
+
+	theta := math.Abs(angleRad)
+	k := math.Max(1, math.Round(theta/GoldenAngle))
+	offset := theta - k*GoldenAngle
+	if math.Abs(offset) >= GoldenAngle/2 {
+		return 0
+	}
+
+	return 0.5 * (1 + math.Cos(2*math.Pi*offset/GoldenAngle))
+}
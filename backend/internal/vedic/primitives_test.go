@@ -0,0 +1,71 @@
+package vedic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDigitalRootFloat(t *testing.T) {
+	deg := math.Pi / 180
+
+	tests := []struct {
+		angle    float64
+		expected int
+	}{
+		{137 * deg, 2},
+		{-57 * deg, 3},   // sign ignored: 5 + 7 = 12 → 3
+		{-47.4 * deg, 2}, // rounds to 47: 4 + 7 = 11 → 2
+		{119.6 * deg, 3}, // rounds to 120: 1 + 2 + 0 = 3
+		{0, 0},
+		{math.NaN(), 0},
+	}
+
+	for _, tt := range tests {
+		if got := DigitalRootFloat(tt.angle); got != tt.expected {
+			t.Errorf("DigitalRootFloat(%.2f°) = %d, expected %d", tt.angle/deg, got, tt.expected)
+		}
+	}
+}
+
+func TestGoldenAlignment(t *testing.T) {
+	deg := math.Pi / 180
+	t.Logf("Golden angle: %.3f°", GoldenAngle/deg)
+
+	if math.Abs(GoldenAngle/deg-137.508) > 1e-3 {
+		t.Errorf("GoldenAngle = %.4f°, expected 137.508°", GoldenAngle/deg)
+	}
+
+	peak := GoldenAlignment(GoldenAngle)
+	if math.Abs(peak-1) > 1e-12 {
+		t.Errorf("GoldenAlignment(golden angle) = %.6f, expected 1", peak)
+	}
+	if a := GoldenAlignment(-GoldenAngle); math.Abs(a-1) > 1e-12 {
+		t.Errorf("GoldenAlignment(-golden angle) = %.6f, expected 1", a)
+	}
+
+	// Falls off monotonically on both sides of the peak
+	prevLow, prevHigh := peak, peak
+	for offset := 5.0; offset <= 60; offset += 5 {
+		low := GoldenAlignment(GoldenAngle - offset*deg)
+		high := GoldenAlignment(GoldenAngle + offset*deg)
+		if low >= prevLow || high >= prevHigh {
+			t.Errorf("Alignment not decreasing at ±%.0f°: %.4f, %.4f", offset, low, high)
+		}
+		prevLow, prevHigh = low, high
+	}
+
+	// Half a golden angle away (and near zero) scores 0
+	if a := GoldenAlignment(GoldenAngle / 2); a > 1e-12 {
+		t.Errorf("GoldenAlignment(golden/2) = %.6f, expected 0", a)
+	}
+	if a := GoldenAlignment(10 * deg); a != 0 {
+		t.Errorf("GoldenAlignment(10°) = %.6f, expected 0", a)
+	}
+
+	// Range [0, 1] across the dihedral circle
+	for d := -180.0; d <= 180; d += 1 {
+		if a := GoldenAlignment(d * deg); a < 0 || a > 1 {
+			t.Errorf("GoldenAlignment(%.0f°) = %.4f out of [0, 1]", d, a)
+		}
+	}
+}